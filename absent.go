@@ -0,0 +1,25 @@
+package goa
+
+import "encoding/json"
+
+// EmitJSONZeros sets the fields of the JSON object data whose names are keys of zeros and that are
+// missing or null to the corresponding JSON encoded zero values, see the AbsentFields DSL. data is
+// returned unchanged if it is not an object or all the fields are set.
+func EmitJSONZeros(data []byte, zeros map[string]string) ([]byte, error) {
+	fields, ok := jsonObject(data)
+	if !ok {
+		return data, nil
+	}
+	changed := false
+	for name, zero := range zeros {
+		if v, ok := fields[name]; ok && string(v) != "null" {
+			continue
+		}
+		fields[name] = json.RawMessage(zero)
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(fields)
+}
@@ -0,0 +1,30 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EmitJSONZeros", func() {
+	zeros := map[string]string{"name": `""`, "count": "0", "tags": "[]"}
+
+	It("sets the missing and null fields", func() {
+		b, err := goa.EmitJSONZeros([]byte(`{"count":2,"name":null}`), zeros)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"count":2,"name":"","tags":[]}`))
+	})
+
+	It("leaves complete objects unchanged", func() {
+		data := []byte(`{"count": 2, "name": "foo", "tags": ["a"]}`)
+		b, err := goa.EmitJSONZeros(data, zeros)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(b).Should(Equal(data))
+	})
+
+	It("leaves values that are not objects unchanged", func() {
+		b, err := goa.EmitJSONZeros([]byte(`[1]`), zeros)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`[1]`))
+	})
+})
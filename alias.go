@@ -0,0 +1,80 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// The functions below implement the decoding and encoding of the legacy names of renamed
+// attributes, see the Alias and EmitAliases DSLs.
+
+// JSONAliaser is implemented by the generated structs whose attributes have legacy names.
+// JSONAliases returns the current names of the attributes indexed by legacy name. DecodeRequestStrict
+// uses it to accept the legacy names.
+type JSONAliaser interface {
+	JSONAliases() map[string]string
+}
+
+// ResolveJSONAliases renames the fields of the JSON object data whose names are keys of aliases to
+// the corresponding values. A field given with its current name prevails over the fields given
+// with a legacy name. data is returned unchanged if it is not an object or does not use any alias.
+func ResolveJSONAliases(data []byte, aliases map[string]string) ([]byte, error) {
+	fields, ok := jsonObject(data)
+	if !ok {
+		return data, nil
+	}
+	changed := false
+	for alias, name := range aliases {
+		v, ok := fields[alias]
+		if !ok {
+			continue
+		}
+		delete(fields, alias)
+		changed = true
+		if _, ok := fields[name]; !ok {
+			fields[name] = v
+		}
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(fields)
+}
+
+// EmitJSONAliases copies the fields of the JSON object data whose names are keys of aliases to
+// fields named after the corresponding values. data is returned unchanged if it is not an object.
+func EmitJSONAliases(data []byte, aliases map[string][]string) ([]byte, error) {
+	fields, ok := jsonObject(data)
+	if !ok {
+		return data, nil
+	}
+	changed := false
+	for name, legacy := range aliases {
+		v, ok := fields[name]
+		if !ok {
+			continue
+		}
+		for _, alias := range legacy {
+			fields[alias] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return data, nil
+	}
+	return json.Marshal(fields)
+}
+
+// jsonObject decodes the fields of the JSON object data, it returns false if data is not an
+// object.
+func jsonObject(data []byte) (map[string]json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
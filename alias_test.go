@@ -0,0 +1,35 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON aliases", func() {
+	It("renames the fields given with legacy names", func() {
+		data, err := goa.ResolveJSONAliases([]byte(`{"name":"foo","id":1}`), map[string]string{"name": "display_name"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(MatchJSON(`{"display_name":"foo","id":1}`))
+	})
+
+	It("gives precedence to the current names", func() {
+		data, err := goa.ResolveJSONAliases([]byte(`{"name":"foo","display_name":"bar"}`), map[string]string{"name": "display_name"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(MatchJSON(`{"display_name":"bar"}`))
+	})
+
+	It("leaves other values unchanged", func() {
+		for _, s := range []string{`null`, `[1,2]`, `{"id":1}`, `{invalid`} {
+			data, err := goa.ResolveJSONAliases([]byte(s), map[string]string{"name": "display_name"})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(data)).Should(Equal(s))
+		}
+	})
+
+	It("emits the legacy names", func() {
+		data, err := goa.EmitJSONAliases([]byte(`{"display_name":"foo","id":1}`), map[string][]string{"display_name": {"name", "nick"}})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(MatchJSON(`{"display_name":"foo","name":"foo","nick":"foo","id":1}`))
+	})
+})
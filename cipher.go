@@ -0,0 +1,64 @@
+package goa
+
+import "golang.org/x/net/context"
+
+// FieldCipher encrypts and decrypts the values of the attributes defined with the Encrypted DSL.
+// Implementations typically delegate to a key management service using the key alias given in
+// the design.
+type FieldCipher interface {
+	// Encrypt returns the ciphertext of the given attribute value.
+	Encrypt(ctx context.Context, keyAlias, plaintext string) (string, error)
+	// Decrypt returns the plaintext of the given attribute value.
+	Decrypt(ctx context.Context, keyAlias, ciphertext string) (string, error)
+}
+
+// ErrNoFieldCipher is the error produced when a request or response contains encrypted attributes
+// and no field cipher was registered with WithFieldCipher.
+var ErrNoFieldCipher = NewErrorClass("no_field_cipher", 500)
+
+// WithFieldCipher builds a context containing the given field cipher. Use it to set the service
+// context so that all requests may access the cipher:
+//
+//	service.Context = goa.WithFieldCipher(service.Context, cipher)
+//
+func WithFieldCipher(ctx context.Context, c FieldCipher) context.Context {
+	return context.WithValue(ctx, fieldCipherKey, c)
+}
+
+// ContextFieldCipher extracts the field cipher from the given context.
+func ContextFieldCipher(ctx context.Context) FieldCipher {
+	if c := ctx.Value(fieldCipherKey); c != nil {
+		return c.(FieldCipher)
+	}
+	return nil
+}
+
+// EncryptField replaces the value pointed to by v with its ciphertext using the context field
+// cipher.
+func EncryptField(ctx context.Context, keyAlias string, v *string) error {
+	c := ContextFieldCipher(ctx)
+	if c == nil {
+		return ErrNoFieldCipher("cannot encrypt attribute, no field cipher registered")
+	}
+	res, err := c.Encrypt(ctx, keyAlias, *v)
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+// DecryptField replaces the value pointed to by v with its plaintext using the context field
+// cipher.
+func DecryptField(ctx context.Context, keyAlias string, v *string) error {
+	c := ContextFieldCipher(ctx)
+	if c == nil {
+		return ErrNoFieldCipher("cannot decrypt attribute, no field cipher registered")
+	}
+	res, err := c.Decrypt(ctx, keyAlias, *v)
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
@@ -0,0 +1,59 @@
+package goa_test
+
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// upperCipher is a field cipher that upper cases the values on encrypt and lower cases them on
+// decrypt.
+type upperCipher struct{ alias string }
+
+func (c *upperCipher) Encrypt(ctx context.Context, keyAlias, plaintext string) (string, error) {
+	c.alias = keyAlias
+	return strings.ToUpper(plaintext), nil
+}
+
+func (c *upperCipher) Decrypt(ctx context.Context, keyAlias, ciphertext string) (string, error) {
+	c.alias = keyAlias
+	return strings.ToLower(ciphertext), nil
+}
+
+var _ = Describe("FieldCipher", func() {
+	var ctx context.Context
+	var cipher *upperCipher
+
+	BeforeEach(func() {
+		cipher = &upperCipher{}
+		ctx = goa.WithFieldCipher(context.Background(), cipher)
+	})
+
+	It("is stored in the context", func() {
+		Ω(goa.ContextFieldCipher(ctx)).Should(Equal(cipher))
+		Ω(goa.ContextFieldCipher(context.Background())).Should(BeNil())
+	})
+
+	It("encrypts and decrypts fields in place", func() {
+		v := "secret"
+		Ω(goa.EncryptField(ctx, "alias/pii", &v)).ShouldNot(HaveOccurred())
+		Ω(v).Should(Equal("SECRET"))
+		Ω(cipher.alias).Should(Equal("alias/pii"))
+		Ω(goa.DecryptField(ctx, "alias/pii", &v)).ShouldNot(HaveOccurred())
+		Ω(v).Should(Equal("secret"))
+	})
+
+	Context("with no cipher registered", func() {
+		It("returns an error", func() {
+			v := "secret"
+			err := goa.EncryptField(context.Background(), "alias/pii", &v)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(500))
+			Ω(v).Should(Equal("secret"))
+		})
+	})
+})
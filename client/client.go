@@ -31,6 +31,9 @@ type (
 		Host string
 		// UserAgent is the user agent set in requests made by the client.
 		UserAgent string
+		// RequestIDHeader is the name of the header used to propagate the request ID, it defaults
+		// to "X-Request-Id".
+		RequestIDHeader string
 		// Dump indicates whether to dump request response.
 		Dump bool
 	}
@@ -66,7 +69,11 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 	// TODO: setting the request ID should be done via client middleware. For now only set it if the
 	// caller provided one in the ctx.
 	if ctxreqid := ContextRequestID(ctx); ctxreqid != "" {
-		req.Header.Set("X-Request-Id", ctxreqid)
+		header := c.RequestIDHeader
+		if header == "" {
+			header = "X-Request-Id"
+		}
+		req.Header.Set(header, ctxreqid)
 	}
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EncodeQueryObject adds the fields of the struct pointed to by obj to the query string values.
+// Each field is encoded as a parameter named after name and the field json tag separated with a
+// dot, e.g. filter.status, array fields produce repeated parameters. Nil fields are omitted. The
+// generated clients call EncodeQueryObject to encode the query parameters whose type is an object.
+func EncodeQueryObject(values url.Values, name string, obj interface{}) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		key := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				key = n
+			}
+		}
+		key = name + "." + key
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < fv.Len(); j++ {
+				values.Add(key, queryValue(fv.Index(j)))
+			}
+			continue
+		}
+		values.Set(key, queryValue(fv))
+	}
+}
+
+// queryValue returns the query string representation of v.
+func queryValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.CanInterface() {
+		switch actual := v.Interface().(type) {
+		case encoding.TextMarshaler:
+			if b, err := actual.MarshalText(); err == nil {
+				return string(b)
+			}
+		case fmt.Stringer:
+			return actual.String()
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
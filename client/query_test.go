@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EncodeQueryObject", func() {
+	type filter struct {
+		Status  string     `json:"status"`
+		Min     *int       `json:"min,omitempty"`
+		Max     *int       `json:"max,omitempty"`
+		Rating  *float64   `json:"rating,omitempty"`
+		Tags    []string   `json:"tags,omitempty"`
+		Since   *time.Time `json:"since,omitempty"`
+		Ignored string     `json:"-"`
+	}
+
+	var values url.Values
+
+	BeforeEach(func() {
+		values = url.Values{}
+	})
+
+	It("encodes the fields using dotted names", func() {
+		min, rating := 3, 4.5
+		since := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+		client.EncodeQueryObject(values, "filter", &filter{
+			Status:  "active",
+			Min:     &min,
+			Rating:  &rating,
+			Tags:    []string{"red", "dry"},
+			Since:   &since,
+			Ignored: "foo",
+		})
+		Ω(values).Should(Equal(url.Values{
+			"filter.status": {"active"},
+			"filter.min":    {"3"},
+			"filter.rating": {"4.5"},
+			"filter.tags":   {"red", "dry"},
+			"filter.since":  {"2016-01-02T03:04:05Z"},
+		}))
+	})
+
+	It("ignores nil objects", func() {
+		var f *filter
+		client.EncodeQueryObject(values, "filter", f)
+		Ω(values).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,245 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// ShadowOptions configures the mirroring of requests done by the doer returned by
+	// NewShadowDoer.
+	ShadowOptions struct {
+		// BaseURL is the URL of the secondary service the requests are mirrored to. The path
+		// of the URL, if any, is prepended to the request paths.
+		BaseURL string
+		// Percent is the percentage of requests mirrored to the secondary service, between
+		// 0 and 100.
+		Percent float64
+		// Methods lists the HTTP methods of the mirrored requests, it defaults to the safe
+		// methods GET and HEAD so that mirroring does not duplicate side effects.
+		Methods []string
+		// Report is called with the differences between the primary and secondary responses,
+		// it defaults to logging the differences using the logger of the request context.
+		Report ShadowReporter
+		// Doer sends the mirrored requests, it defaults to the primary doer.
+		Doer Doer
+	}
+
+	// ShadowReporter is the function called by the shadow doer when the responses of the
+	// primary and secondary services differ or when the mirrored request fails.
+	ShadowReporter func(context.Context, *ShadowDiff)
+
+	// ShadowDiff describes the differences between the responses of the primary and the
+	// secondary services to a mirrored request.
+	ShadowDiff struct {
+		// Method is the HTTP method of the request.
+		Method string
+		// URL is the URL of the request sent to the primary service.
+		URL string
+		// PrimaryStatus is the status code of the primary service response.
+		PrimaryStatus int
+		// ShadowStatus is the status code of the secondary service response, 0 if the
+		// mirrored request failed.
+		ShadowStatus int
+		// PrimaryBody is the body of the primary service response.
+		PrimaryBody []byte
+		// ShadowBody is the body of the secondary service response.
+		ShadowBody []byte
+		// Err is the error returned when sending the mirrored request, if any.
+		Err error
+	}
+
+	// shadowDoer is the Doer returned by NewShadowDoer.
+	shadowDoer struct {
+		primary Doer
+		shadow  Doer
+		base    *url.URL
+		percent float64
+		methods map[string]bool
+		report  ShadowReporter
+	}
+
+	// detachedContext is a context that carries the values of its parent but is never
+	// canceled, it is used to send the mirrored requests once the primary request completes.
+	detachedContext struct {
+		context.Context
+	}
+)
+
+// NewShadowDoer returns a Doer that sends the requests using primary and mirrors a percentage
+// of them to the secondary service described by opts. The mirrored requests are sent
+// asynchronously once the primary response is received so that they do not add latency, their
+// responses are compared to the primary responses and the differences reported to
+// opts.Report. JSON bodies are compared structurally, other bodies byte for byte. The caller
+// always gets the primary response.
+func NewShadowDoer(primary Doer, opts *ShadowOptions) (Doer, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("missing shadow options")
+	}
+	base, err := url.Parse(opts.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shadow base URL %#v: %s", opts.BaseURL, err)
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid shadow base URL %#v: missing scheme or host", opts.BaseURL)
+	}
+	if opts.Percent < 0 || opts.Percent > 100 {
+		return nil, fmt.Errorf("invalid shadow percentage %v: must be between 0 and 100", opts.Percent)
+	}
+	methods := opts.Methods
+	if len(methods) == 0 {
+		methods = []string{"GET", "HEAD"}
+	}
+	d := &shadowDoer{
+		primary: primary,
+		shadow:  opts.Doer,
+		base:    base,
+		percent: opts.Percent,
+		methods: make(map[string]bool, len(methods)),
+		report:  opts.Report,
+	}
+	for _, m := range methods {
+		d.methods[strings.ToUpper(m)] = true
+	}
+	if d.shadow == nil {
+		d.shadow = primary
+	}
+	if d.report == nil {
+		d.report = logShadowDiff
+	}
+	return d, nil
+}
+
+// Do sends the request to the primary service and mirrors it to the secondary service if it is
+// sampled.
+func (d *shadowDoer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if !d.methods[req.Method] || d.percent == 0 || rand.Float64()*100 >= d.percent {
+		return d.primary.Do(ctx, req)
+	}
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+	sreq, err := d.shadowRequest(req, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.primary.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var respBody []byte
+	if resp.Body != nil {
+		respBody, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	}
+	diff := &ShadowDiff{
+		Method:        req.Method,
+		URL:           req.URL.String(),
+		PrimaryStatus: resp.StatusCode,
+		PrimaryBody:   respBody,
+	}
+	go d.compare(detachedContext{ctx}, sreq, diff)
+	return resp, nil
+}
+
+// shadowRequest builds the request sent to the secondary service.
+func (d *shadowDoer) shadowRequest(req *http.Request, body []byte) (*http.Request, error) {
+	u := *req.URL
+	u.Scheme = d.base.Scheme
+	u.Host = d.base.Host
+	u.User = d.base.User
+	if p := strings.TrimSuffix(d.base.Path, "/"); p != "" {
+		u.Path = p + u.Path
+		u.RawPath = ""
+	}
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	sreq, err := http.NewRequest(req.Method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Header {
+		sreq.Header[k] = append([]string(nil), v...)
+	}
+	return sreq, nil
+}
+
+// compare sends the mirrored request and reports the differences with the primary response.
+func (d *shadowDoer) compare(ctx context.Context, req *http.Request, diff *ShadowDiff) {
+	resp, err := d.shadow.Do(ctx, req)
+	if err != nil {
+		diff.Err = err
+		d.report(ctx, diff)
+		return
+	}
+	defer resp.Body.Close()
+	diff.ShadowStatus = resp.StatusCode
+	diff.ShadowBody, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		diff.Err = err
+		d.report(ctx, diff)
+		return
+	}
+	if diff.ShadowStatus != diff.PrimaryStatus || !sameBody(diff.PrimaryBody, diff.ShadowBody) {
+		d.report(ctx, diff)
+	}
+}
+
+// sameBody returns true if the response bodies are identical or hold the same JSON value.
+func sameBody(b1, b2 []byte) bool {
+	if bytes.Equal(b1, b2) {
+		return true
+	}
+	var v1, v2 interface{}
+	if json.Unmarshal(b1, &v1) != nil || json.Unmarshal(b2, &v2) != nil {
+		return false
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+// logShadowDiff is the default ShadowReporter, it logs the differences using the logger of the
+// request context.
+func logShadowDiff(ctx context.Context, diff *ShadowDiff) {
+	if diff.Err != nil {
+		goa.LogError(ctx, "shadow request failed", "method", diff.Method, "url", diff.URL, "err", diff.Err)
+		return
+	}
+	goa.LogInfo(ctx, "shadow response mismatch", "method", diff.Method, "url", diff.URL,
+		"status", diff.PrimaryStatus, "shadow_status", diff.ShadowStatus,
+		"body", string(diff.PrimaryBody), "shadow_body", string(diff.ShadowBody))
+}
+
+// Deadline returns no deadline so that the mirrored request outlives the primary request.
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+// Done returns nil as the context is never canceled.
+func (detachedContext) Done() <-chan struct{} { return nil }
+
+// Err always returns nil as the context is never canceled.
+func (detachedContext) Err() error { return nil }
@@ -0,0 +1,118 @@
+package client_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewShadowDoer", func() {
+	var primary, shadow *httptest.Server
+	var primaryBody, shadowBody string
+	var shadowPaths chan string
+	var opts *client.ShadowOptions
+	var diffs chan *client.ShadowDiff
+
+	BeforeEach(func() {
+		primaryBody = `{"id":1,"name":"foo"}`
+		shadowBody = `{"name": "foo", "id": 1}`
+		shadowPaths = make(chan string, 10)
+		diffs = make(chan *client.ShadowDiff, 10)
+		primary = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			fmt.Fprint(rw, primaryBody)
+		}))
+		shadow = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			b, _ := ioutil.ReadAll(req.Body)
+			shadowPaths <- req.Method + " " + req.URL.RequestURI() + " " + string(b)
+			fmt.Fprint(rw, shadowBody)
+		}))
+		opts = &client.ShadowOptions{
+			BaseURL: shadow.URL + "/v2",
+			Percent: 100,
+			Report:  func(_ context.Context, d *client.ShadowDiff) { diffs <- d },
+		}
+	})
+
+	AfterEach(func() {
+		primary.Close()
+		shadow.Close()
+	})
+
+	do := func(method, body string) string {
+		d, err := client.NewShadowDoer(client.HTTPClientDoer(http.DefaultClient), opts)
+		Ω(err).ShouldNot(HaveOccurred())
+		req, err := http.NewRequest(method, primary.URL+"/bottles?page=2", strings.NewReader(body))
+		Ω(err).ShouldNot(HaveOccurred())
+		resp, err := d.Do(context.Background(), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		b, err := ioutil.ReadAll(resp.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(b)
+	}
+
+	It("mirrors the requests to the secondary service", func() {
+		Ω(do("GET", "")).Should(Equal(primaryBody))
+		Eventually(shadowPaths).Should(Receive(Equal("GET /v2/bottles?page=2 ")))
+		Consistently(diffs).ShouldNot(Receive())
+	})
+
+	Context("with responses that differ", func() {
+		BeforeEach(func() {
+			shadowBody = `{"id":1,"name":"bar"}`
+		})
+
+		It("reports the differences", func() {
+			Ω(do("GET", "")).Should(Equal(primaryBody))
+			var diff *client.ShadowDiff
+			Eventually(diffs).Should(Receive(&diff))
+			Ω(diff.Method).Should(Equal("GET"))
+			Ω(diff.URL).Should(Equal(primary.URL + "/bottles?page=2"))
+			Ω(diff.PrimaryStatus).Should(Equal(200))
+			Ω(diff.ShadowStatus).Should(Equal(200))
+			Ω(string(diff.PrimaryBody)).Should(Equal(primaryBody))
+			Ω(string(diff.ShadowBody)).Should(Equal(shadowBody))
+			Ω(diff.Err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with an unsafe method", func() {
+		It("does not mirror the request by default", func() {
+			Ω(do("POST", `{"name":"foo"}`)).Should(Equal(primaryBody))
+			Consistently(shadowPaths).ShouldNot(Receive())
+		})
+
+		It("mirrors the request and its body when the method is enabled", func() {
+			opts.Methods = []string{"post"}
+			Ω(do("POST", `{"name":"foo"}`)).Should(Equal(primaryBody))
+			Eventually(shadowPaths).Should(Receive(Equal(`POST /v2/bottles?page=2 {"name":"foo"}`)))
+		})
+	})
+
+	Context("with a percentage of 0", func() {
+		BeforeEach(func() {
+			opts.Percent = 0
+		})
+
+		It("does not mirror the requests", func() {
+			Ω(do("GET", "")).Should(Equal(primaryBody))
+			Consistently(shadowPaths).ShouldNot(Receive())
+		})
+	})
+
+	Context("with invalid options", func() {
+		It("returns an error", func() {
+			_, err := client.NewShadowDoer(nil, &client.ShadowOptions{BaseURL: "/v2", Percent: 10})
+			Ω(err).Should(MatchError(ContainSubstring("missing scheme or host")))
+			_, err = client.NewShadowDoer(nil, &client.ShadowOptions{BaseURL: shadow.URL, Percent: 110})
+			Ω(err).Should(MatchError(ContainSubstring("must be between 0 and 100")))
+		})
+	})
+})
@@ -50,6 +50,14 @@ type (
 		TokenSource TokenSource
 	}
 
+	// SessionSigner adds the session cookie to the request.
+	SessionSigner struct {
+		// CookieName is the name of the session cookie.
+		CookieName string
+		// SessionID is the ID of the session started by logging in.
+		SessionID string
+	}
+
 	// Token is the interface to an OAuth2 token implementation.
 	// It can be implemented with https://godoc.org/golang.org/x/oauth2#Token.
 	Token interface {
@@ -118,6 +126,14 @@ func (s *OAuth2Signer) Sign(req *http.Request) error {
 	return signFromSource(s.TokenSource, req)
 }
 
+// Sign adds the session cookie to the request.
+func (s *SessionSigner) Sign(req *http.Request) error {
+	if s.SessionID != "" {
+		req.AddCookie(&http.Cookie{Name: s.CookieName, Value: s.SessionID})
+	}
+	return nil
+}
+
 // signFromSource generates a token using the given source and uses it to sign the request.
 func signFromSource(source TokenSource, req *http.Request) error {
 	token, err := source.Token()
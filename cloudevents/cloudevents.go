@@ -0,0 +1,96 @@
+/*
+Package cloudevents builds and publishes CloudEvents v1.0 (https://cloudevents.io) events. The code
+generated for the actions designed with EmitEvent publishes an event after each successful call to
+the publisher registered with UsePublisher. Publishers deliver the events to downstream systems
+such as message brokers or webhooks, the JSON encoding of Event follows the CloudEvents structured
+content mode.
+*/
+package cloudevents
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/uuid"
+)
+
+// SpecVersion is the version of the CloudEvents specification implemented by the package.
+const SpecVersion = "1.0"
+
+type (
+	// Event is a CloudEvents event.
+	Event struct {
+		// SpecVersion is the version of the CloudEvents specification used by the event.
+		SpecVersion string `json:"specversion"`
+		// ID identifies the event.
+		ID string `json:"id"`
+		// Source identifies the context in which the event happened.
+		Source string `json:"source"`
+		// Type describes the kind of event, e.g. "cellar.bottle.create".
+		Type string `json:"type"`
+		// Subject identifies the subject of the event in the context of the source, e.g. the
+		// request path.
+		Subject string `json:"subject,omitempty"`
+		// Time is the time the event happened.
+		Time time.Time `json:"time"`
+		// DataContentType is the content type of Data.
+		DataContentType string `json:"datacontenttype,omitempty"`
+		// Data is the event payload.
+		Data interface{} `json:"data,omitempty"`
+	}
+
+	// Publisher is the interface implemented by the event publishers.
+	Publisher interface {
+		// Publish delivers the event.
+		Publish(ctx context.Context, e *Event) error
+	}
+
+	// PublisherFunc is an adapter that allows using a function as a Publisher.
+	PublisherFunc func(ctx context.Context, e *Event) error
+
+	// publisherKey is the key used to store the publisher in the service context.
+	publisherKey struct{}
+)
+
+// New returns an event with the given type, source, subject and data. The event has a unique
+// identifier and the current time, its data content type is JSON if data is not nil.
+func New(eventType, source, subject string, data interface{}) *Event {
+	e := &Event{
+		SpecVersion: SpecVersion,
+		ID:          uuid.NewV4().String(),
+		Source:      source,
+		Type:        eventType,
+		Subject:     subject,
+		Time:        time.Now().UTC(),
+		Data:        data,
+	}
+	if data != nil {
+		e.DataContentType = "application/json"
+	}
+	return e
+}
+
+// Publish calls f(ctx, e).
+func (f PublisherFunc) Publish(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// UsePublisher registers the publisher that receives the events with the service. UsePublisher
+// must be called before the controllers are created.
+func UsePublisher(service *goa.Service, p Publisher) {
+	service.Context = context.WithValue(service.Context, publisherKey{}, p)
+}
+
+// Publish sends the event to the publisher registered in the context if any. Publishing errors
+// are logged and do not affect the response sent to the client.
+func Publish(ctx context.Context, e *Event) {
+	p, ok := ctx.Value(publisherKey{}).(Publisher)
+	if !ok {
+		return
+	}
+	if err := p.Publish(ctx, e); err != nil {
+		goa.LogError(ctx, "failed to publish event", "type", e.Type, "id", e.ID, "err", err)
+	}
+}
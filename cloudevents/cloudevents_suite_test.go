@@ -0,0 +1,13 @@
+package cloudevents_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCloudEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CloudEvents Suite")
+}
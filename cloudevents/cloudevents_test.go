@@ -0,0 +1,84 @@
+package cloudevents_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/cloudevents"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	It("builds a CloudEvents event", func() {
+		e := cloudevents.New("cellar.bottle.create", "/cellar", "/bottles/1", map[string]int{"id": 1})
+		Ω(e.SpecVersion).Should(Equal("1.0"))
+		Ω(e.ID).ShouldNot(BeEmpty())
+		Ω(e.Time).Should(BeTemporally("~", time.Now(), time.Second))
+		Ω(e.DataContentType).Should(Equal("application/json"))
+
+		b, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+		var m map[string]interface{}
+		Ω(json.Unmarshal(b, &m)).Should(Succeed())
+		Ω(m).Should(HaveKeyWithValue("specversion", "1.0"))
+		Ω(m).Should(HaveKeyWithValue("type", "cellar.bottle.create"))
+		Ω(m).Should(HaveKeyWithValue("source", "/cellar"))
+		Ω(m).Should(HaveKeyWithValue("subject", "/bottles/1"))
+		Ω(m).Should(HaveKeyWithValue("data", map[string]interface{}{"id": 1.0}))
+	})
+
+	It("omits the data content type of events with no data", func() {
+		e := cloudevents.New("cellar.bottle.delete", "/cellar", "", nil)
+		Ω(e.DataContentType).Should(BeEmpty())
+		b, err := json.Marshal(e)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).ShouldNot(ContainSubstring("data"))
+		Ω(string(b)).ShouldNot(ContainSubstring("subject"))
+	})
+})
+
+var _ = Describe("Publish", func() {
+	var service *goa.Service
+	var published []*cloudevents.Event
+	var perr error
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		published = nil
+		perr = nil
+	})
+
+	publish := func() {
+		cloudevents.Publish(service.Context, cloudevents.New("cellar.bottle.create", "/cellar", "", nil))
+	}
+
+	It("does nothing when no publisher is registered", func() {
+		Ω(publish).ShouldNot(Panic())
+	})
+
+	Context("with a publisher", func() {
+		BeforeEach(func() {
+			cloudevents.UsePublisher(service, cloudevents.PublisherFunc(func(_ context.Context, e *cloudevents.Event) error {
+				published = append(published, e)
+				return perr
+			}))
+		})
+
+		It("publishes the event", func() {
+			publish()
+			Ω(published).Should(HaveLen(1))
+			Ω(published[0].Type).Should(Equal("cellar.bottle.create"))
+		})
+
+		It("ignores publishing errors", func() {
+			perr = errors.New("broker down")
+			Ω(publish).ShouldNot(Panic())
+			Ω(published).Should(HaveLen(1))
+		})
+	})
+})
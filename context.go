@@ -19,6 +19,8 @@ const (
 	logContextKey
 	errKey
 	securityScopesKey
+	securityClaimsKey
+	fieldCipherKey
 )
 
 type (
@@ -165,3 +167,12 @@ func (r *ResponseData) Write(b []byte) (int, error) {
 	r.Length += len(b)
 	return r.ResponseWriter.Write(b)
 }
+
+// Flush sends any buffered data to the client if the underlying writer supports it. Streaming
+// handlers call Flush to push each chunk, this is required for HTTP/2 where the underlying writer
+// buffers the response.
+func (r *ResponseData) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -2,6 +2,7 @@ package goa_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 
 	"golang.org/x/net/context"
@@ -39,4 +40,17 @@ var _ = Describe("ResponseData", func() {
 			Ω(trw.Status).Should(Equal(42))
 		})
 	})
+
+	Context("Flush", func() {
+		It("flushes the underlying writer", func() {
+			rec := httptest.NewRecorder()
+			data.SwitchWriter(rec)
+			data.Flush()
+			Ω(rec.Flushed).Should(BeTrue())
+		})
+
+		It("does nothing if the underlying writer cannot flush", func() {
+			Ω(func() { data.Flush() }).ShouldNot(Panic())
+		})
+	})
 })
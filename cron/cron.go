@@ -0,0 +1,140 @@
+/*
+Package cron runs service actions on a schedule. The code generated for the actions designed with
+Schedule registers a job with a Runner for each action. The runner calls the action through the
+service mux at the times given by the job cron expression so that the same action logic serves
+the HTTP-triggered and the time-triggered calls and the action middleware runs in both cases.
+
+A job does not start while its previous run is still in progress, the skipped runs are logged and
+counted. The runner emits the following metrics for each job:
+
+	goa.cron.<job>.run      counter of the runs
+	goa.cron.<job>.error    counter of the runs that got a response with status 400 or more
+	goa.cron.<job>.skipped  counter of the runs skipped because the previous run was in progress
+	goa.cron.<job>.duration duration of the runs
+
+Overlap protection only applies within a process: services running multiple instances must
+elect the instance that runs the jobs.
+*/
+package cron
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// Runner calls the service actions on schedule.
+	Runner struct {
+		service *goa.Service
+		jobs    []*Job
+	}
+
+	// Job describes an action called on schedule.
+	Job struct {
+		// Name identifies the job in the logs and metrics, e.g. "report.purge".
+		Name string
+		// Schedule is the parsed cron expression.
+		Schedule *Schedule
+		// Verb is the HTTP method of the action route.
+		Verb string
+		// Path is the full path of the action route.
+		Path string
+		// running is 1 while the job runs.
+		running int32
+	}
+)
+
+// NewRunner returns a runner that calls the actions of the service.
+func NewRunner(service *goa.Service) *Runner {
+	return &Runner{service: service}
+}
+
+// Add registers a job that calls the action with the given route on the schedule described by
+// the cron expression spec, see Parse.
+func (r *Runner) Add(name, spec, verb, path string) error {
+	s, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	r.jobs = append(r.jobs, &Job{Name: name, Schedule: s, Verb: verb, Path: path})
+	return nil
+}
+
+// Jobs returns the registered jobs.
+func (r *Runner) Jobs() []*Job {
+	return r.jobs
+}
+
+// Run runs the jobs on schedule until ctx is done. It waits for the runs in progress to complete
+// before returning.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range r.jobs {
+		wg.Add(1)
+		go func(j *Job) {
+			defer wg.Done()
+			r.schedule(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+// RunJob runs the job once unless it is already running, it returns false if the run was skipped.
+func (r *Runner) RunJob(ctx context.Context, j *Job) bool {
+	if !atomic.CompareAndSwapInt32(&j.running, 0, 1) {
+		goa.IncrCounter([]string{"goa", "cron", j.Name, "skipped"}, 1.0)
+		r.service.LogInfo("cron", "job", j.Name, "skipped", "previous run in progress")
+		return false
+	}
+	defer atomic.StoreInt32(&j.running, 0)
+	defer goa.MeasureSince([]string{"goa", "cron", j.Name, "duration"}, time.Now())
+	goa.IncrCounter([]string{"goa", "cron", j.Name, "run"}, 1.0)
+
+	req, err := http.NewRequest(j.Verb, j.Path, nil)
+	if err != nil {
+		goa.IncrCounter([]string{"goa", "cron", j.Name, "error"}, 1.0)
+		r.service.LogError("cron", "job", j.Name, "err", err)
+		return true
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	resp := goa.Dispatch(r.service.Mux, req)
+	if resp.Status >= 400 {
+		goa.IncrCounter([]string{"goa", "cron", j.Name, "error"}, 1.0)
+		r.service.LogError("cron", "job", j.Name, "status", resp.Status, "err", string(resp.Body))
+		return true
+	}
+	r.service.LogInfo("cron", "job", j.Name, "status", resp.Status)
+	return true
+}
+
+// schedule runs the job at the times of its schedule until ctx is done. The runs do not block the
+// schedule so that the runs overlapping with a run in progress are skipped.
+func (r *Runner) schedule(ctx context.Context, j *Job) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		next := j.Schedule.Next(time.Now())
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.RunJob(ctx, j)
+		}()
+	}
+}
@@ -0,0 +1,57 @@
+package cron_test
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/cron"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		service *goa.Service
+		runner  *cron.Runner
+		calls   int32
+		block   chan struct{}
+	)
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		calls = 0
+		block = nil
+		service.Mux.Handle("DELETE", "/reports/expired", func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+			atomic.AddInt32(&calls, 1)
+			if block != nil {
+				<-block
+			}
+			rw.WriteHeader(204)
+		})
+		runner = cron.NewRunner(service)
+		Ω(runner.Add("report.purge", "*/5 * * * *", "DELETE", "/reports/expired")).Should(Succeed())
+	})
+
+	It("rejects invalid cron expressions", func() {
+		Ω(runner.Add("report.purge", "* *", "DELETE", "/reports/expired")).ShouldNot(Succeed())
+	})
+
+	It("calls the action", func() {
+		Ω(runner.RunJob(service.Context, runner.Jobs()[0])).Should(BeTrue())
+		Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(1)))
+	})
+
+	It("skips the runs that overlap with a run in progress", func() {
+		block = make(chan struct{})
+		j := runner.Jobs()[0]
+		done := make(chan bool)
+		go func() { done <- runner.RunJob(service.Context, j) }()
+		Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(1)))
+		Ω(runner.RunJob(service.Context, j)).Should(BeFalse())
+		close(block)
+		Ω(<-done).Should(BeTrue())
+		Ω(runner.RunJob(service.Context, j)).Should(BeTrue())
+	})
+})
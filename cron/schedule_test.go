@@ -0,0 +1,48 @@
+package cron_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/cron"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	// now is a Saturday
+	now := time.Date(2026, 10, 17, 16, 7, 30, 0, time.UTC)
+
+	next := func(spec string) time.Time {
+		s, err := cron.Parse(spec)
+		Ω(err).ShouldNot(HaveOccurred())
+		return s.Next(now)
+	}
+
+	It("computes the next run time", func() {
+		Ω(next("*/5 * * * *")).Should(Equal(time.Date(2026, 10, 17, 16, 10, 0, 0, time.UTC)))
+		Ω(next("0 * * * *")).Should(Equal(time.Date(2026, 10, 17, 17, 0, 0, 0, time.UTC)))
+		Ω(next("15,45 8-18/2 * * *")).Should(Equal(time.Date(2026, 10, 17, 16, 15, 0, 0, time.UTC)))
+		Ω(next("0 0 1 1 *")).Should(Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("matches the days of week", func() {
+		Ω(next("30 2 * * 1-5")).Should(Equal(time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)))
+		Ω(next("0 0 * * 7")).Should(Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("matches either the day of month or the day of week when both are set", func() {
+		Ω(next("0 12 13 * 5")).Should(Equal(time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC)))
+	})
+
+	It("accepts the macros", func() {
+		Ω(next("@weekly")).Should(Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)))
+		Ω(next("@hourly")).Should(Equal(time.Date(2026, 10, 17, 17, 0, 0, 0, time.UTC)))
+	})
+
+	It("rejects invalid expressions", func() {
+		for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-2 * * * *", "a * * * *"} {
+			_, err := cron.Parse(spec)
+			Ω(err).Should(HaveOccurred(), spec)
+		}
+	})
+})
@@ -0,0 +1,98 @@
+package goa
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressReader decompresses a request body and limits the size of the result.
+type decompressReader struct {
+	io.Reader
+	body      io.Closer
+	dec       io.Closer
+	remaining int64
+	maxSize   int64
+}
+
+// DecompressRequest replaces the body of requests compressed with gzip or deflate as indicated by
+// the Content-Encoding header with a reader that decompresses it. Reading more than maxSize
+// decompressed bytes produces an ErrRequestBodyTooLarge error. DecompressRequest returns an
+// ErrUnsupportedEncoding error if the request uses any other encoding and leaves uncompressed
+// requests untouched. The code generated for the actions of designs that use the Decompress DSL
+// calls DecompressRequest prior to decoding the request body.
+func DecompressRequest(req *http.Request, maxSize int64) error {
+	encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding")))
+	var dec io.ReadCloser
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return ErrInvalidEncoding(fmt.Sprintf("invalid gzip request body: %s", err))
+		}
+		dec = gz
+	case "deflate":
+		dec = flate.NewReader(req.Body)
+	default:
+		return ErrUnsupportedEncoding(fmt.Sprintf("unsupported request body encoding %#v", encoding), "encoding", encoding)
+	}
+	req.Body = &decompressReader{
+		Reader:    dec,
+		body:      req.Body,
+		dec:       dec,
+		remaining: maxSize,
+		maxSize:   maxSize,
+	}
+	req.Header.Del("Content-Encoding")
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	return nil
+}
+
+// Read reads decompressed bytes and fails if the maximum size is exceeded.
+func (r *decompressReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, r.tooLarge()
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF && err.Error() != "http: request body too large" {
+		err = ErrInvalidEncoding(fmt.Sprintf("invalid compressed request body: %s", err))
+	}
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n + int(r.remaining), r.tooLarge()
+	}
+	return n, err
+}
+
+// Close closes the decompressor and the original body.
+func (r *decompressReader) Close() error {
+	r.dec.Close()
+	return r.body.Close()
+}
+
+// tooLarge returns the error produced when the decompressed body exceeds the maximum size.
+func (r *decompressReader) tooLarge() error {
+	return ErrRequestBodyTooLarge(fmt.Sprintf("decompressed request body length exceeds %d bytes", r.maxSize))
+}
+
+// isDecompressionError returns true if err is one of the errors produced by DecompressRequest.
+func isDecompressionError(err error) bool {
+	e, ok := err.(*ErrorResponse)
+	if !ok {
+		return false
+	}
+	switch e.Code {
+	case "request_too_large", "unsupported_encoding", "invalid_encoding":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package goa_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecompressRequest", func() {
+	const body = `{"name":"compressed"}`
+	var req *http.Request
+	var maxSize int64
+	var decErr error
+
+	BeforeEach(func() {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(body))
+		gz.Close()
+		req, _ = http.NewRequest("POST", "/foo", &buf)
+		req.Header.Set("Content-Encoding", "gzip")
+		maxSize = 1024
+	})
+
+	JustBeforeEach(func() {
+		decErr = goa.DecompressRequest(req, maxSize)
+	})
+
+	It("decompresses gzip bodies", func() {
+		Ω(decErr).ShouldNot(HaveOccurred())
+		Ω(req.Header.Get("Content-Encoding")).Should(BeEmpty())
+		b, err := ioutil.ReadAll(req.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(body))
+	})
+
+	Context("with a deflate body", func() {
+		BeforeEach(func() {
+			var buf bytes.Buffer
+			fw, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+			fw.Write([]byte(body))
+			fw.Close()
+			req, _ = http.NewRequest("POST", "/foo", &buf)
+			req.Header.Set("Content-Encoding", "deflate")
+		})
+
+		It("decompresses the body", func() {
+			Ω(decErr).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadAll(req.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(body))
+		})
+	})
+
+	Context("with an uncompressed body", func() {
+		BeforeEach(func() {
+			req, _ = http.NewRequest("POST", "/foo", strings.NewReader(body))
+		})
+
+		It("leaves the body untouched", func() {
+			Ω(decErr).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadAll(req.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(body))
+		})
+	})
+
+	Context("with an unsupported encoding", func() {
+		BeforeEach(func() {
+			req.Header.Set("Content-Encoding", "br")
+		})
+
+		It("returns a 415 error", func() {
+			Ω(decErr).Should(HaveOccurred())
+			Ω(decErr.(goa.ServiceError).ResponseStatus()).Should(Equal(415))
+		})
+	})
+
+	Context("with an invalid gzip body", func() {
+		BeforeEach(func() {
+			req, _ = http.NewRequest("POST", "/foo", strings.NewReader(body))
+			req.Header.Set("Content-Encoding", "gzip")
+		})
+
+		It("returns a 400 error", func() {
+			Ω(decErr).Should(HaveOccurred())
+			Ω(decErr.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+		})
+	})
+
+	Context("with a decompressed body exceeding the maximum size", func() {
+		BeforeEach(func() {
+			maxSize = int64(len(body) - 1)
+		})
+
+		It("fails to read the body", func() {
+			Ω(decErr).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadAll(req.Body)
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(413))
+			Ω(b).Should(HaveLen(int(maxSize)))
+		})
+	})
+
+	Context("with a decompressed body of exactly the maximum size", func() {
+		BeforeEach(func() {
+			maxSize = int64(len(body))
+		})
+
+		It("reads the body", func() {
+			b, err := ioutil.ReadAll(req.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(body))
+		})
+	})
+
+	Context("used by an unmarshaler", func() {
+		var rw *TestResponseWriter
+
+		BeforeEach(func() {
+			maxSize = 4
+		})
+
+		JustBeforeEach(func() {
+			service := goa.New("test")
+			service.Decoder.Register(goa.NewJSONDecoder, "*/*")
+			ctrl := service.NewController("test")
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				var payload map[string]interface{}
+				return service.DecodeRequest(req, &payload)
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				rw.WriteHeader(400)
+				rw.Write([]byte(goa.ContextError(ctx).Error()))
+				return nil
+			}
+			rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+			req.ContentLength = 1
+			ctrl.MuxHandler("test", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("reports the decompressed body size error", func() {
+			Ω(string(rw.Body)).Should(MatchRegexp(`\[.*\] 413 request_too_large: decompressed request body length exceeds 4 bytes`))
+		})
+	})
+})
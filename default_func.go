@@ -0,0 +1,38 @@
+package goa
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultProvider computes the default value of an attribute. The value must be of the Go type
+// used by the generated code for the attribute, e.g. time.Time for DateTime attributes.
+type DefaultProvider func() interface{}
+
+var (
+	defaultProvidersMu sync.RWMutex
+	defaultProviders   = map[string]DefaultProvider{
+		"now": func() interface{} { return time.Now().UTC() },
+	}
+)
+
+// RegisterDefault registers the provider used to compute the default values of the attributes
+// whose design uses DefaultFunc with the given name. Registering a provider under an existing
+// name replaces the provider.
+func RegisterDefault(name string, p DefaultProvider) {
+	defaultProvidersMu.Lock()
+	defer defaultProvidersMu.Unlock()
+	defaultProviders[name] = p
+}
+
+// DefaultValue calls the provider registered under the given name and returns the computed value.
+// DefaultValue returns nil if there is no provider with that name.
+func DefaultValue(name string) interface{} {
+	defaultProvidersMu.RLock()
+	p, ok := defaultProviders[name]
+	defaultProvidersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return p()
+}
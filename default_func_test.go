@@ -0,0 +1,40 @@
+package goa_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/satori/go.uuid"
+)
+
+var _ = Describe("DefaultValue", func() {
+	It("computes UUIDs", func() {
+		v1, ok := goa.DefaultValue("uuid").(uuid.UUID)
+		Ω(ok).Should(BeTrue())
+		v2 := goa.DefaultValue("uuid").(uuid.UUID)
+		Ω(v1).ShouldNot(Equal(v2))
+	})
+
+	It("computes the current time", func() {
+		now, ok := goa.DefaultValue("now").(time.Time)
+		Ω(ok).Should(BeTrue())
+		Ω(now.Location()).Should(Equal(time.UTC))
+		Ω(now).Should(BeTemporally("~", time.Now(), time.Second))
+	})
+
+	It("returns nil for unknown providers", func() {
+		Ω(goa.DefaultValue("unknown")).Should(BeNil())
+	})
+
+	Context("with a registered provider", func() {
+		BeforeEach(func() {
+			goa.RegisterDefault("answer", func() interface{} { return 42 })
+		})
+
+		It("calls the provider", func() {
+			Ω(goa.DefaultValue("answer")).Should(Equal(42))
+		})
+	})
+})
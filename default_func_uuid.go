@@ -0,0 +1,9 @@
+// +build !js
+
+package goa
+
+import "github.com/satori/go.uuid"
+
+func init() {
+	RegisterDefault("uuid", func() interface{} { return uuid.NewV4() })
+}
@@ -0,0 +1,51 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// AbsentPolicy describes how the optional attributes of a media type that are absent from a
+// response are encoded.
+type AbsentPolicy string
+
+const (
+	// OmitAbsent leaves the absent attributes out of the response. This is the default
+	// policy.
+	OmitAbsent AbsentPolicy = "omit"
+	// EmitNull encodes the absent attributes as null.
+	EmitNull AbsentPolicy = "null"
+	// EmitZero encodes the absent attributes using the zero value of their type: "" for
+	// strings, 0 for numbers, false for booleans, [] for arrays and {} for hashes and objects.
+	EmitZero AbsentPolicy = "zero"
+)
+
+// AbsentPolicyMetadata is the metadata key set by AbsentFields on the media types whose absent
+// attributes do not use the default omit policy.
+const AbsentPolicyMetadata = "absent:policy"
+
+// AbsentPolicy returns the policy used to encode the absent optional attributes of the object
+// attribute.
+func (a *AttributeDefinition) AbsentPolicy() AbsentPolicy {
+	if p, ok := a.Metadata[AbsentPolicyMetadata]; ok && len(p) > 0 {
+		return AbsentPolicy(p[0])
+	}
+	return OmitAbsent
+}
+
+// finalizeAbsentPolicy applies the API absent policy to the media types that do not define their
+// own.
+func (a *APIDefinition) finalizeAbsentPolicy() {
+	if a.AbsentPolicy == "" || a.AbsentPolicy == OmitAbsent {
+		return
+	}
+	for _, mt := range a.MediaTypes {
+		if mt.IsError() || !mt.Type.IsObject() {
+			continue
+		}
+		if _, ok := mt.Metadata[AbsentPolicyMetadata]; ok {
+			continue
+		}
+		if mt.Metadata == nil {
+			mt.Metadata = make(dslengine.MetadataDefinition)
+		}
+		mt.Metadata[AbsentPolicyMetadata] = []string{string(a.AbsentPolicy)}
+	}
+}
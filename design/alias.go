@@ -0,0 +1,53 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+const (
+	// AliasMetadata is the metadata key set by Alias on renamed attributes, its values are
+	// the legacy names of the attribute.
+	AliasMetadata = "alias:name"
+
+	// EmitAliasMetadata is the metadata key set by EmitAliases on the renamed attributes whose
+	// values are also encoded using their legacy names.
+	EmitAliasMetadata = "alias:emit"
+)
+
+// Aliases returns the legacy names of the attribute, see the Alias DSL.
+func (a *AttributeDefinition) Aliases() []string {
+	return a.Metadata[AliasMetadata]
+}
+
+// EmitsAliases returns true if the attribute value is also encoded using its legacy names.
+func (a *AttributeDefinition) EmitsAliases() bool {
+	_, ok := a.Metadata[EmitAliasMetadata]
+	return ok && len(a.Aliases()) > 0
+}
+
+// validateAliases makes sure the legacy names of the child attributes of the object attribute do
+// not collide with the names or legacy names of other child attributes.
+func (a *AttributeDefinition) validateAliases(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	o := a.Type.ToObject()
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	owners := make(map[string]string)
+	for _, n := range names {
+		for _, alias := range o[n].Aliases() {
+			if _, ok := o[alias]; ok {
+				verr.Add(parent, "%sfield %s alias %#v is the name of another field", ctx, n, alias)
+				continue
+			}
+			if other, ok := owners[alias]; ok {
+				verr.Add(parent, "%sfield %s alias %#v is also an alias of field %s", ctx, n, alias, other)
+				continue
+			}
+			owners[alias] = n
+		}
+	}
+}
@@ -25,6 +25,7 @@ const (
 	NoContent            = "NoContent"
 	ResetContent         = "ResetContent"
 	PartialContent       = "PartialContent"
+	MultiStatus          = "MultiStatus"
 
 	MultipleChoices   = "MultipleChoices"
 	MovedPermanently  = "MovedPermanently"
@@ -33,6 +34,7 @@ const (
 	NotModified       = "NotModified"
 	UseProxy          = "UseProxy"
 	TemporaryRedirect = "TemporaryRedirect"
+	PermanentRedirect = "PermanentRedirect"
 
 	BadRequest                   = "BadRequest"
 	Unauthorized                 = "Unauthorized"
@@ -54,6 +56,11 @@ const (
 	ExpectationFailed            = "ExpectationFailed"
 	Teapot                       = "Teapot"
 	UnprocessableEntity          = "UnprocessableEntity"
+	Locked                       = "Locked"
+	PreconditionRequired         = "PreconditionRequired"
+	TooManyRequests              = "TooManyRequests"
+	RequestHeaderFieldsTooLarge  = "RequestHeaderFieldsTooLarge"
+	UnavailableForLegalReasons   = "UnavailableForLegalReasons"
 
 	InternalServerError     = "InternalServerError"
 	NotImplemented          = "NotImplemented"
@@ -61,6 +68,7 @@ const (
 	ServiceUnavailable      = "ServiceUnavailable"
 	GatewayTimeout          = "GatewayTimeout"
 	HTTPVersionNotSupported = "HTTPVersionNotSupported"
+	InsufficientStorage     = "InsufficientStorage"
 )
 
 var (
@@ -245,6 +253,10 @@ func (r MediaTypeRoot) DependsOn() []dslengine.Root {
 // IterateSets iterates over the one generated media type definition set.
 func (r MediaTypeRoot) IterateSets(iterator dslengine.SetIterator) {
 	canonicalIDs := make([]string, len(r))
+	if Design.MediaTypes == nil && len(r) > 0 {
+		// Collections of user types and primitives may be the only media types
+		Design.MediaTypes = make(map[string]*MediaTypeDefinition)
+	}
 	i := 0
 	for _, mt := range r {
 		canonicalID := CanonicalIdentifier(mt.Identifier)
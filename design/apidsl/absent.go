@@ -0,0 +1,51 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// AbsentFields sets the policy used to encode the optional attributes of a media type that are
+// absent from a response so that consumers expecting every attribute to be present, e.g. strict
+// JavaScript clients or BI pipelines, can be served. The policy is one of:
+//
+//    design.OmitAbsent: absent attributes are left out of the response (default)
+//    design.EmitNull:   absent attributes are encoded as null
+//    design.EmitZero:   absent attributes are encoded with the zero value of their type
+//
+// AbsentFields may appear in API to set the policy of all the media types or in MediaType.
+// Example:
+//
+//    var Account = MediaType("application/vnd.account+json", func() {
+//        AbsentFields(design.EmitNull)
+//        Attributes(func() {
+//            Attribute("name", String)
+//            Attribute("email", String)
+//        })
+//        View("default", func() {
+//            Attribute("name")
+//            Attribute("email")
+//        })
+//    })
+//
+// An account without email is rendered as {"email":null,"name":"..."}.
+func AbsentFields(policy design.AbsentPolicy) {
+	switch policy {
+	case design.OmitAbsent, design.EmitNull, design.EmitZero:
+	default:
+		dslengine.ReportError("invalid absent field policy %#v, must be one of %#v, %#v or %#v",
+			policy, design.OmitAbsent, design.EmitNull, design.EmitZero)
+		return
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		def.AbsentPolicy = policy
+	case *design.MediaTypeDefinition:
+		if def.Metadata == nil {
+			def.Metadata = make(dslengine.MetadataDefinition)
+		}
+		def.Metadata[design.AbsentPolicyMetadata] = []string{string(policy)}
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,92 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AbsentFields", func() {
+	var apiPolicy, mtPolicy AbsentPolicy
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiPolicy = ""
+		mtPolicy = ""
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			if apiPolicy != "" {
+				AbsentFields(apiPolicy)
+			}
+		})
+		MediaType("application/vnd.account", func() {
+			if mtPolicy != "" {
+				AbsentFields(mtPolicy)
+			}
+			Attributes(func() {
+				Attribute("name", String)
+			})
+			View("default", func() {
+				Attribute("name")
+			})
+		})
+		MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("name", String)
+			})
+			View("default", func() {
+				Attribute("name")
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("omits absent fields by default", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.MediaTypes["application/vnd.account"].AbsentPolicy()).Should(Equal(OmitAbsent))
+	})
+
+	Context("in a media type", func() {
+		BeforeEach(func() {
+			mtPolicy = EmitNull
+		})
+
+		It("sets the policy of the media type and of its projections", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			mt := Design.MediaTypes["application/vnd.account"]
+			Ω(mt.AbsentPolicy()).Should(Equal(EmitNull))
+			p, _, err := mt.Project("default")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(p.AbsentPolicy()).Should(Equal(EmitNull))
+			Ω(Design.MediaTypes["application/vnd.bottle"].AbsentPolicy()).Should(Equal(OmitAbsent))
+		})
+	})
+
+	Context("in the API", func() {
+		BeforeEach(func() {
+			apiPolicy = EmitZero
+			mtPolicy = EmitNull
+		})
+
+		It("sets the policy of the media types that do not define one", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.MediaTypes["application/vnd.account"].AbsentPolicy()).Should(Equal(EmitNull))
+			Ω(Design.MediaTypes["application/vnd.bottle"].AbsentPolicy()).Should(Equal(EmitZero))
+		})
+	})
+
+	Context("with an invalid policy", func() {
+		BeforeEach(func() {
+			mtPolicy = AbsentPolicy("empty")
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid absent field policy "empty"`))
+		})
+	})
+})
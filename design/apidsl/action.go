@@ -99,11 +99,12 @@ func Action(name string, dsl func()) {
 		action, ok := r.Actions[name]
 		if !ok {
 			action = &design.ActionDefinition{
-				Parent: r,
-				Name:   name,
+				Parent:   r,
+				Name:     name,
+				Location: dslengine.CallerLocation(),
 			}
 		}
-		if !dslengine.Execute(dsl, action) {
+		if !dslengine.Execute(checkedCache(dsl), action) {
 			return
 		}
 		r.Actions[name] = action
@@ -368,6 +369,10 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 			att = &design.AttributeDefinition{Type: actual}
 		case design.Primitive:
 			att = &design.AttributeDefinition{Type: actual}
+		case *design.ScalarDefinition:
+			att = &design.AttributeDefinition{Type: actual}
+		case *design.FlagsDefinition:
+			att = &design.AttributeDefinition{Type: actual}
 		default:
 			dslengine.ReportError("invalid Payload argument, must be a type, a media type or a DSL building a type")
 			return
@@ -391,6 +396,39 @@ func payload(isOptional bool, p interface{}, dsls ...func()) {
 	}
 }
 
+// StreamingPayload sets the type of the elements of the action streaming request payload. The
+// request body consists of a JSON array of elements or of newline delimited JSON elements
+// (NDJSON). The generated action context exposes a Stream field whose Recv method decodes and
+// validates one element at a time so that large request bodies are never loaded in memory
+// entirely. The element type must be defined with Type, StreamingPayload accepts the type or its
+// name. An action cannot define both a payload and a streaming payload. Example:
+//
+//	Action("ingest", func() {
+//		Routing(POST("/events"))
+//		StreamingPayload(Event)
+//		Response(NoContent)
+//	})
+func StreamingPayload(p interface{}) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	var ut *design.UserTypeDefinition
+	switch actual := p.(type) {
+	case *design.UserTypeDefinition:
+		ut = actual
+	case string:
+		if ut, ok = design.Design.Types[actual]; !ok {
+			dslengine.ReportError("unknown streaming payload type %s", actual)
+			return
+		}
+	default:
+		dslengine.ReportError("invalid StreamingPayload argument, must be a type defined with Type or its name")
+		return
+	}
+	a.StreamingPayload = ut
+}
+
 // newAttribute creates a new attribute definition using the media type with the given identifier
 // as base type.
 func newAttribute(baseMT string) *design.AttributeDefinition {
@@ -305,3 +305,73 @@ var _ = Describe("Payload", func() {
 	})
 
 })
+
+var _ = Describe("StreamingPayload", func() {
+	var elem interface{}
+	var payload bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		elem = nil
+		payload = false
+	})
+
+	JustBeforeEach(func() {
+		Event := Type("Event", func() {
+			Attribute("name", String)
+			Required("name")
+		})
+		if elem == nil {
+			elem = Event
+		}
+		Resource("foo", func() {
+			Action("ingest", func() {
+				Routing(POST(""))
+				StreamingPayload(elem)
+				if payload {
+					Payload(Event)
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the element type", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		sp := Design.Resources["foo"].Actions["ingest"].StreamingPayload
+		Ω(sp).ShouldNot(BeNil())
+		Ω(sp.TypeName).Should(Equal("Event"))
+	})
+
+	Context("with a type name", func() {
+		BeforeEach(func() {
+			elem = "Event"
+		})
+
+		It("sets the element type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["foo"].Actions["ingest"].StreamingPayload.TypeName).Should(Equal("Event"))
+		})
+	})
+
+	Context("with an inline type", func() {
+		BeforeEach(func() {
+			elem = ArrayOf(String)
+		})
+
+		It("fails", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a payload", func() {
+		BeforeEach(func() {
+			payload = true
+		})
+
+		It("fails", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot define both a payload and a streaming payload"))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Alias defines a legacy name of the attribute during a rename transition. The generated code
+// decodes the JSON objects that use the legacy name as if they used the attribute name, the value
+// given with the attribute name prevails if both are present. The schema snapshot comparison
+// made for AdditiveOnly APIs does not report the rename of an attribute to a name that lists the
+// old name as alias as a removal. Alias applies to the attributes of types, media types and
+// payloads and may appear multiple times. Example:
+//
+//    var User = Type("User", func() {
+//        Attribute("display_name", String, func() {
+//            Alias("name")
+//        })
+//    })
+//
+// The Go field name is derived from the attribute name ("DisplayName" above), use the
+// "struct:field:name" metadata to keep the previous field name.
+func Alias(name string) {
+	if a, ok := attributeDefinition(); ok {
+		if name == "" {
+			dslengine.ReportError("alias cannot be empty")
+			return
+		}
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		for _, alias := range a.Metadata[design.AliasMetadata] {
+			if alias == name {
+				dslengine.ReportError("alias %#v is defined twice", name)
+				return
+			}
+		}
+		a.Metadata[design.AliasMetadata] = append(a.Metadata[design.AliasMetadata], name)
+	}
+}
+
+// EmitAliases makes the generated code also encode the attribute value using the legacy names
+// defined with Alias so that clients that only know the legacy names keep working. EmitAliases
+// may appear in Attribute. Example:
+//
+//    Attribute("display_name", String, func() {
+//        Alias("name")
+//        EmitAliases()
+//    })
+func EmitAliases() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.EmitAliasMetadata] = []string{"true"}
+	}
+}
@@ -0,0 +1,57 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Alias", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("records the legacy names of the attribute", func() {
+		ut := Type("User", func() {
+			Attribute("display_name", String, func() {
+				Alias("name")
+				Alias("nick")
+				EmitAliases()
+			})
+			Attribute("email", String)
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		att := ut.Type.ToObject()["display_name"]
+		Ω(att.Aliases()).Should(Equal([]string{"name", "nick"}))
+		Ω(att.EmitsAliases()).Should(BeTrue())
+		Ω(ut.Type.ToObject()["email"].EmitsAliases()).Should(BeFalse())
+	})
+
+	It("rejects aliases that are the names of other attributes", func() {
+		Type("User", func() {
+			Attribute("display_name", String, func() {
+				Alias("email")
+			})
+			Attribute("email", String)
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring(`alias "email" is the name of another field`))
+	})
+
+	It("rejects aliases shared by two attributes", func() {
+		Type("User", func() {
+			Attribute("display_name", String, func() {
+				Alias("name")
+			})
+			Attribute("full_name", String, func() {
+				Alias("name")
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+})
@@ -95,6 +95,45 @@ func Version(ver string) {
 	}
 }
 
+// IdentifierTemplate sets the template used to build the identifiers of the media types declared
+// with a name instead of an identifier, e.g. MediaType("bottle", ...). The template may use the
+// "{org}" placeholder for the API vendor prefix, the "{name}" placeholder for the lower case media
+// type name and the "{v}" placeholder for the API version. The template must use the "{name}"
+// placeholder. Parameters given with the media type name such as "bottle; type=collection" are
+// added to the parameters of the template. Media types declared with an identifier are not
+// affected. Example:
+//
+//	API("cellar", func() {
+//		Version("1.0")
+//		VendorPrefix("goa")
+//		IdentifierTemplate("application/vnd.{org}.{name}+json; version={v}")
+//	})
+//
+// With the design above MediaType("bottle", ...) defines a media type with identifier
+// "application/vnd.goa.bottle+json; version=1.0".
+//
+// IdentifierTemplate must appear in API.
+func IdentifierTemplate(tmpl string) {
+	if api, ok := apiDefinition(); ok {
+		if !strings.Contains(tmpl, "{name}") {
+			dslengine.ReportError("invalid identifier template %#v, template must use the {name} placeholder", tmpl)
+			return
+		}
+		api.IdentifierTemplate = tmpl
+	}
+}
+
+// VendorPrefix sets the organization name used by the identifier template "{org}" placeholder.
+// Setting a vendor prefix without an identifier template causes the media types declared with a
+// name to use identifiers of the form "application/vnd.{org}.{name}+json".
+//
+// VendorPrefix must appear in API.
+func VendorPrefix(org string) {
+	if api, ok := apiDefinition(); ok {
+		api.VendorPrefix = org
+	}
+}
+
 // Description sets the definition description.
 // Description can be called inside API, Resource, Action or MediaType.
 func Description(d string) {
@@ -117,6 +156,16 @@ func Description(d string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.InterceptorDefinition:
+		def.Description = d
+	case *design.TenantDefinition:
+		def.Description = d
+	case *design.ServerDefinition:
+		def.Description = d
+	case *design.ScalarDefinition:
+		def.Description = d
+	case *design.WorkflowDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -208,11 +257,21 @@ func Expose(vals ...string) {
 	}
 }
 
-// MaxAge sets the cache expiry for preflight request responses. Used in Origin DSL.
-func MaxAge(val uint) {
-	if cors, ok := corsDefinition(); ok {
+// MaxAge sets the cache expiry for preflight request responses when used in Origin DSL. MaxAge
+// may also be given to Cache to set the number of seconds during which the action responses are
+// fresh, see Cache.
+func MaxAge(val uint) func() {
+	if cors, ok := dslengine.CurrentDefinition().(*design.CORSDefinition); ok {
 		cors.MaxAge = val
+		return func() {}
 	}
+	if !cacheContext() {
+		dslengine.IncompatibleDSL()
+		return func() {}
+	}
+	return newCacheSetting("MaxAge", func(c *design.CacheDefinition) {
+		c.MaxAge = val
+	})
 }
 
 // Credentials sets the allow credentials response header. Used in Origin DSL.
@@ -329,9 +388,12 @@ func Email(email string) {
 	}
 }
 
-// URL sets the contact or license URL.
+// URL sets the contact, license or docs URL. Within a Server definition URL sets the server URL
+// template.
 func URL(url string) {
 	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ServerDefinition:
+		def.URL = url
 	case *design.ContactDefinition:
 		def.URL = url
 	case *design.LicenseDefinition:
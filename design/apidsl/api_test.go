@@ -56,6 +56,103 @@ var _ = Describe("API", func() {
 		})
 	})
 
+	Context("with an identifier template", func() {
+		var tmpl string
+		var mt *MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "foo"
+			tmpl = "application/vnd.{org}.{name}+json; version={v}"
+			mt = MediaType("bottle", func() {
+				Attributes(func() {
+					Attribute("name")
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			dsl = func() {
+				Version("1.0")
+				VendorPrefix("goa")
+				IdentifierTemplate(tmpl)
+			}
+		})
+
+		It("builds the identifiers of the media types declared with a name", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Validate()).ShouldNot(HaveOccurred())
+			Ω(mt.Identifier).Should(Equal("application/vnd.goa.bottle+json; version=1.0"))
+			Ω(mt.TypeName).Should(Equal("Bottle"))
+			Ω(Design.MediaTypeWithIdentifier("bottle")).Should(Equal(mt))
+			Ω(Design.MediaTypeWithIdentifier(mt.Identifier)).Should(Equal(mt))
+		})
+
+		Context("with collection parameters", func() {
+			It("keeps the parameters", func() {
+				Ω(Design.ExpandIdentifier("bottle; type=collection")).Should(Equal("application/vnd.goa.bottle+json; type=collection; version=1.0"))
+			})
+		})
+
+		Context("that does not use the name placeholder", func() {
+			BeforeEach(func() {
+				tmpl = "application/vnd.{org}+json"
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("that uses a missing vendor prefix", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Version("1.0")
+					IdentifierTemplate(tmpl)
+				}
+			})
+
+			It("produces a validation error", func() {
+				Ω(Design.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a vendor prefix", func() {
+		var mt, ext *MediaTypeDefinition
+
+		BeforeEach(func() {
+			name = "foo"
+			mt = MediaType("Bottle", func() {
+				Attributes(func() {
+					Attribute("name")
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			ext = MediaType("application/vnd.other.bottle+json", func() {
+				Attributes(func() {
+					Attribute("name")
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			dsl = func() {
+				VendorPrefix("goa")
+			}
+		})
+
+		It("uses the default identifier template", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(mt.Identifier).Should(Equal("application/vnd.goa.bottle+json"))
+		})
+
+		It("does not change the media types declared with an identifier", func() {
+			Ω(ext.Identifier).Should(Equal("application/vnd.other.bottle+json"))
+		})
+	})
+
 	Context("with valid DSL", func() {
 		JustBeforeEach(func() {
 			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
@@ -0,0 +1,143 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// APIKeyMediaIdentifier is the identifier of the media type used by the resource defined with
+// ManageAPIKeys to describe API keys.
+const APIKeyMediaIdentifier = "application/vnd.goa.api-key+json"
+
+// ManageAPIKeys defines the "api_keys" resource used to administer the keys of an APIKeySecurity
+// scheme. The resource exposes the "list", "create", "show" and "revoke" actions, requests must be
+// authenticated with the scheme by a key granted the "admin" role. The scheme can be given by name
+// or using the value returned by APIKeySecurity. The optional DSL is run at the end of the
+// resource definition and may override the base path or security requirements or add actions.
+// ManageAPIKeys must appear at the top level. Example:
+//
+//    var APIKey = APIKeySecurity("api_key", func() {
+//        Header("X-Api-Key")
+//    })
+//
+//    var _ = ManageAPIKeys(APIKey, func() {
+//        BasePath("/admin/keys")
+//    })
+//
+// The generated main scaffold implements the resource actions using a apikey.KeyManager shared
+// with the scheme middleware.
+func ManageAPIKeys(scheme interface{}, dsl ...func()) *design.ResourceDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	var name string
+	switch val := scheme.(type) {
+	case string:
+		name = val
+	case *design.SecuritySchemeDefinition:
+		name = val.SchemeName
+	default:
+		dslengine.ReportError("invalid value for 'scheme' parameter, specify a string or a *SecuritySchemeDefinition")
+		return nil
+	}
+
+	media := MediaType(APIKeyMediaIdentifier, func() {
+		Description("An API key")
+		TypeName("APIKey")
+		Attributes(func() {
+			Attribute("id", design.String, "Key identifier")
+			Attribute("key", design.String, "Secret key value, only returned when the key is created")
+			Attribute("owner", design.String, "Principal the key was issued to")
+			Attribute("scopes", ArrayOf(design.String), "Security scopes granted to the key")
+			Attribute("roles", ArrayOf(design.String), "Roles granted to the key")
+			Attribute("created_at", design.DateTime, "Key creation time")
+			Attribute("last_used_at", design.DateTime, "Time the key was last used")
+			Attribute("revoked", design.Boolean, "Whether the key was revoked")
+			Required("id", "owner", "created_at", "revoked")
+		})
+		View("default", func() {
+			Attribute("id")
+			Attribute("owner")
+			Attribute("scopes")
+			Attribute("roles")
+			Attribute("created_at")
+			Attribute("last_used_at")
+			Attribute("revoked")
+		})
+		View("secret", func() {
+			Attribute("id")
+			Attribute("key")
+			Attribute("owner")
+			Attribute("scopes")
+			Attribute("roles")
+			Attribute("created_at")
+			Attribute("revoked")
+		})
+	})
+
+	return Resource("api_keys", func() {
+		var def *design.SecuritySchemeDefinition
+		for _, s := range design.Design.SecuritySchemes {
+			if s.SchemeName == name {
+				def = s
+			}
+		}
+		if def == nil {
+			dslengine.ReportError("security scheme %q not found", name)
+			return
+		}
+		if def.Kind != design.APIKeySecurityKind {
+			dslengine.ReportError("ManageAPIKeys requires an APIKeySecurity scheme, %q is not one", name)
+			return
+		}
+		Description("Administers the API keys of the " + name + " security scheme")
+		BasePath("/api_keys")
+		DefaultMedia(media)
+		Metadata(design.APIKeysMetadata, name)
+		Security(def, func() {
+			Role("admin")
+		})
+		Action("list", func() {
+			Description("List the API keys")
+			Routing(GET(""))
+			Response(design.OK, func() {
+				Media(CollectionOf(media))
+			})
+		})
+		Action("create", func() {
+			Description("Issue a new API key, the response contains the secret key value")
+			Routing(POST(""))
+			Payload(func() {
+				Attribute("owner", design.String, "Principal the key is issued to")
+				Attribute("scopes", ArrayOf(design.String), "Security scopes granted to the key")
+				Attribute("roles", ArrayOf(design.String), "Roles granted to the key")
+				Required("owner")
+			})
+			Response(design.Created, func() {
+				Media(media, "secret")
+			})
+		})
+		Action("show", func() {
+			Description("Retrieve an API key")
+			Routing(GET("/:keyID"))
+			Params(func() {
+				Param("keyID", design.String, "Key identifier")
+			})
+			Response(design.OK)
+			Response(design.NotFound)
+		})
+		Action("revoke", func() {
+			Description("Revoke an API key")
+			Routing(DELETE("/:keyID"))
+			Params(func() {
+				Param("keyID", design.String, "Key identifier")
+			})
+			Response(design.NoContent)
+			Response(design.NotFound)
+		})
+		if len(dsl) > 0 {
+			dsl[0]()
+		}
+	})
+}
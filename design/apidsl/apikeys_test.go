@@ -0,0 +1,62 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ManageAPIKeys", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with an API key scheme", func() {
+		var res *ResourceDefinition
+
+		BeforeEach(func() {
+			API("keys", func() {})
+			key := APIKeySecurity("api_key", func() {
+				Header("X-Api-Key")
+			})
+			res = ManageAPIKeys(key, func() {
+				BasePath("/admin/keys")
+			})
+			dslengine.Run()
+		})
+
+		It("defines the key management resource", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res).ShouldNot(BeNil())
+			Ω(Design.Resources).Should(HaveKey("api_keys"))
+			Ω(res.BasePath).Should(Equal("/admin/keys"))
+			Ω(res.Actions).Should(HaveLen(4))
+			Ω(res.Actions).Should(HaveKey("revoke"))
+			Ω(res.Actions["create"].Responses["Created"].ViewName).Should(Equal("secret"))
+			Ω(Design.MediaTypeWithIdentifier(APIKeyMediaIdentifier)).ShouldNot(BeNil())
+		})
+
+		It("secures the resource with the scheme", func() {
+			Ω(res.ManagedAPIKeys()).ShouldNot(BeNil())
+			Ω(res.ManagedAPIKeys().SchemeName).Should(Equal("api_key"))
+			Ω(res.Actions["list"].Security.Scheme.SchemeName).Should(Equal("api_key"))
+			Ω(res.Actions["list"].Security.Roles).Should(Equal([]string{"admin"}))
+		})
+	})
+
+	Context("with a scheme that is not an API key scheme", func() {
+		BeforeEach(func() {
+			API("keys", func() {})
+			BasicAuthSecurity("basic")
+			ManageAPIKeys("basic")
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("requires an APIKeySecurity scheme"))
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ArrayStyle sets how the values of an array parameter are serialized in the request URLs. The
+// style is one of:
+//
+//    design.StyleRepeated: each value is a separate parameter, e.g. ?id=1&id=2 (default)
+//    design.StyleComma:    the values are separated with commas, e.g. ?id=1,2
+//    design.StylePipe:     the values are separated with pipes, e.g. ?id=1|2
+//    design.StyleSpace:    the values are separated with spaces, e.g. ?id=1%202
+//
+// The generated clients serialize the values using the style. The generated services accept both
+// repeated parameters and delimited values so that ?id=1,2&id=3 is decoded as [1, 2, 3] with
+// StyleComma. ArrayStyle may only appear in the DSL of array parameters. Example:
+//
+//    Params(func() {
+//        Param("ids", ArrayOf(Integer), func() {
+//            ArrayStyle(design.StyleComma)
+//        })
+//    })
+func ArrayStyle(style design.ParamStyle) {
+	switch style {
+	case design.StyleRepeated, design.StyleComma, design.StylePipe, design.StyleSpace:
+	default:
+		dslengine.ReportError("invalid array style %#v, must be one of %#v, %#v, %#v or %#v",
+			style, design.StyleRepeated, design.StyleComma, design.StylePipe, design.StyleSpace)
+		return
+	}
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.ArrayStyleMetadata] = []string{string(style)}
+	}
+}
@@ -0,0 +1,67 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ArrayStyle", func() {
+	var paramType DataType
+	var style ParamStyle
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		paramType = ArrayOf(Integer)
+		style = StyleComma
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			Action("list", func() {
+				Routing(GET(""))
+				Params(func() {
+					Param("ids", paramType, func() {
+						ArrayStyle(style)
+					})
+					Param("names", ArrayOf(String))
+				})
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the style of the array parameter", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		params := Design.Resources["bottle"].Actions["list"].Params.Type.ToObject()
+		Ω(params["ids"].ArrayStyle()).Should(Equal(StyleComma))
+		Ω(params["ids"].ArrayStyle().Delimiter()).Should(Equal(","))
+		Ω(params["names"].ArrayStyle()).Should(Equal(StyleRepeated))
+		Ω(params["names"].ArrayStyle().Delimiter()).Should(BeEmpty())
+	})
+
+	Context("with an invalid style", func() {
+		BeforeEach(func() {
+			style = ParamStyle("tabs")
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid array style "tabs"`))
+		})
+	})
+
+	Context("on a parameter that is not an array", func() {
+		BeforeEach(func() {
+			paramType = Integer
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("array style may only be set on array attributes"))
+		})
+	})
+})
@@ -19,6 +19,9 @@ import (
 //
 // * The primitive types Boolean, Integer, Number, DateTime, UUID or String.
 //
+// * The built-in scalars Time, Date or Duration, see TimeFormat, Int64, UInt64, BigInt or Decimal,
+//   see StringEncoded, or a scalar defined via Scalar.
+//
 // * A type defined via the Type function.
 //
 // * A media type defined via the MediaType function.
@@ -110,14 +113,26 @@ func Attribute(name string, args ...interface{}) {
 			return
 		}
 
-		var baseAttr *design.AttributeDefinition
-		if parent.Reference != nil {
+		var baseAttr, refAttr *design.AttributeDefinition
+		policy := parent.DuplicatePolicy()
+		existing, redeclared := parent.Type.(design.Object)[name]
+		if redeclared && policy == design.DuplicateMerge {
+			baseAttr = design.DupAtt(existing)
+		} else if parent.Reference != nil {
 			if att, ok := parent.Reference.ToObject()[name]; ok {
+				refAttr = att
 				baseAttr = design.DupAtt(att)
 			}
 		}
 
 		dataType, description, dsl := parseAttributeArgs(baseAttr, args...)
+		if policy == design.DuplicateError {
+			if redeclared {
+				parent.AddDuplicate(name, false)
+			} else if refAttr != nil && dataType != nil && dataType.Name() != refAttr.Type.Name() {
+				parent.AddDuplicate(name, true)
+			}
+		}
 		if baseAttr != nil {
 			if description != "" {
 				baseAttr.Description = description
@@ -213,14 +228,22 @@ func parseAttributeArgs(baseAttr *design.AttributeDefinition, args ...interface{
 // Within an APIKeySecurity or JWTSecurity definition, Header
 // defines that an implementation must check the given header to get
 // the API Key.  In this case, no `args` parameter is necessary.
+//
+// Within a Tenant definition, Header defines the header that carries the tenant identifier.
 func Header(name string, args ...interface{}) {
-	if _, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.SecuritySchemeDefinition:
 		if len(args) != 0 {
 			dslengine.ReportError("do not specify args")
 			return
 		}
 		inHeader(name)
 		return
+	case *design.TenantDefinition:
+		if tenantAttribute(name, args...) {
+			def.Header = name
+		}
+		return
 	}
 
 	Attribute(name, args...)
@@ -232,7 +255,15 @@ func Member(name string, args ...interface{}) {
 }
 
 // Param is an alias of Attribute.
+//
+// Within a Tenant definition, Param defines the path parameter that carries the tenant identifier.
 func Param(name string, args ...interface{}) {
+	if def, ok := dslengine.CurrentDefinition().(*design.TenantDefinition); ok {
+		if tenantAttribute(name, args...) {
+			def.Param = name
+		}
+		return
+	}
 	Attribute(name, args...)
 }
 
@@ -292,7 +323,21 @@ func NoExample() {
 
 // Enum adds a "enum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor76.
-func Enum(val ...interface{}) {
+//
+// Enum used at the top level defines a reusable named enum type instead. The first argument is the
+// name of the type and the others its values which must all be strings or all be integers. The
+// generated code defines a Go type with one constant per value as well as the IsValid, Validate,
+// String and JSON marshaling methods. Attributes of enum types use the Go type:
+//
+//	var BottleStatus = Enum("BottleStatus", "active", "archived")
+//
+//	var Bottle = Type("Bottle", func() {
+//		Attribute("status", BottleStatus)	// Generates "Status BottleStatus", values BottleStatusActive and BottleStatusArchived
+//	})
+func Enum(val ...interface{}) *design.ScalarDefinition {
+	if dslengine.IsTopLevelDefinition() {
+		return enumType(val)
+	}
 	if a, ok := attributeDefinition(); ok {
 		ok := true
 		for i, v := range val {
@@ -324,6 +369,7 @@ func Enum(val ...interface{}) {
 			a.AddValues(val)
 		}
 	}
+	return nil
 }
 
 // SupportedValidationFormats lists the supported formats for use with the
@@ -505,6 +551,8 @@ func Required(names ...string) {
 		at = def
 	case *design.MediaTypeDefinition:
 		at = def.AttributeDefinition
+	case design.ContainerDefinition:
+		at = def.Attribute()
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -0,0 +1,53 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Audit tags the action so that the generated code emits an audit event with the given name after
+// each call. The event identifies the actor authenticated by the action security scheme, the
+// resources accessed via the request path parameters and includes a snapshot of the request
+// payload. The optional DSL lists the payload attributes masked in the snapshot with Redact, the
+// payload attributes with the "audit:redact" metadata are masked as well.
+//
+// Audit may only appear in Action. Example:
+//
+//    Action("update", func() {
+//        Routing(PATCH("/:bottleID"))
+//        Payload(BottlePayload)
+//        Audit("bottle.update", func() {
+//            Redact("owner.email")
+//        })
+//    })
+//
+func Audit(event string, dsl ...func()) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to Audit")
+		return
+	}
+	if a.Audit != nil {
+		dslengine.ReportError("audit event already defined")
+		return
+	}
+	a.Audit = &design.AuditDefinition{Event: event, Parent: a}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], a.Audit)
+	}
+}
+
+// Redact lists the paths to the payload attributes whose values are masked in the audit events.
+// Paths to nested attributes use dots to separate the attribute names, e.g. "card.number".
+// Redact may only appear in Audit.
+func Redact(attributes ...string) {
+	a, ok := dslengine.CurrentDefinition().(*design.AuditDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	a.Redact = append(a.Redact, attributes...)
+}
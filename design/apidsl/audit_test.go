@@ -0,0 +1,62 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit", func() {
+	var redact string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		redact = "card.number"
+	})
+
+	JustBeforeEach(func() {
+		payload := Type("Payment", func() {
+			Attribute("amount", Integer)
+			Attribute("password", String, func() {
+				Metadata("audit:redact")
+			})
+			Attribute("card", func() {
+				Attribute("number", String)
+			})
+		})
+		Resource("payment", func() {
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(payload)
+				Audit("payment.create", func() {
+					Redact(redact)
+				})
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with valid redacted attributes", func() {
+		It("defines the audit event", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			audit := Design.Resources["payment"].Actions["create"].Audit
+			Ω(audit).ShouldNot(BeNil())
+			Ω(audit.Event).Should(Equal("payment.create"))
+			Ω(audit.RedactedPaths()).Should(Equal([]string{"card.number", "password"}))
+		})
+	})
+
+	Context("with an unknown redacted attribute", func() {
+		BeforeEach(func() {
+			redact = "card.cvv"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`redacted attribute "card.cvv" is not a payload attribute`))
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package apidsl
+
+// AutoHead makes the generated controllers handle HEAD requests for every GET route that does
+// not have an explicit HEAD route. The HEAD handlers run the GET action and discard the response
+// body so that the response headers are identical. AutoHead may only appear in API.
+func AutoHead() {
+	if api, ok := apiDefinition(); ok {
+		api.AutoHead = true
+	}
+}
+
+// AutoOptions makes the generated controllers handle OPTIONS requests for every route path that
+// does not have an explicit OPTIONS route or a CORS policy. The OPTIONS handlers respond with an
+// Allow header listing the HTTP methods of the routes sharing the path. AutoOptions may only
+// appear in API. Example:
+//
+//    API("cellar", func() {
+//        AutoHead()
+//        AutoOptions()
+//    })
+//
+func AutoOptions() {
+	if api, ok := apiDefinition(); ok {
+		api.AutoOptions = true
+	}
+}
@@ -0,0 +1,55 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AutoHead and AutoOptions", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {
+			AutoHead()
+			AutoOptions()
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			Action("show", func() { Routing(GET("/:id")) })
+			Action("delete", func() { Routing(DELETE("/:id")) })
+			Action("peek", func() { Routing(HEAD("/peek/latest"), GET("/peek/latest")) })
+		})
+		Resource("cellar", func() {
+			BasePath("/cellars")
+			Origin("*", func() { Methods("GET") })
+			Action("list", func() { Routing(GET("")) })
+		})
+		dslengine.Run()
+	})
+
+	It("enables the generated handlers", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.AutoHead).Should(BeTrue())
+		Ω(Design.AutoOptions).Should(BeTrue())
+	})
+
+	It("computes the GET paths that get a HEAD handler", func() {
+		bottle := Design.Resources["bottle"]
+		Ω(bottle.Actions["show"].AutoHeadPaths()).Should(Equal([]string{"/bottles/:id"}))
+		Ω(bottle.Actions["peek"].AutoHeadPaths()).Should(BeEmpty())
+		Ω(bottle.Actions["delete"].AutoHeadPaths()).Should(BeEmpty())
+	})
+
+	It("computes the paths that get an OPTIONS handler", func() {
+		Ω(Design.Resources["bottle"].AutoOptionsPaths()).Should(ConsistOf("/bottles/:id", "/bottles/peek/latest"))
+		Ω(Design.Resources["cellar"].AutoOptionsPaths()).Should(BeEmpty())
+	})
+
+	It("computes the allowed methods", func() {
+		Ω(Design.AllowedMethods("/bottles/:id")).Should(Equal([]string{"DELETE", "GET", "HEAD", "OPTIONS"}))
+		Ω(Design.AllowedMethods("/bottles/peek/latest")).Should(Equal([]string{"GET", "HEAD", "OPTIONS"}))
+		Ω(Design.AllowedMethods("/unknown")).Should(BeNil())
+	})
+})
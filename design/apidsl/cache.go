@@ -0,0 +1,149 @@
+package apidsl
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// cacheSetting is a cache policy setting created by MaxAge, SWR or VaryOn.
+type cacheSetting struct {
+	// name is the name of the DSL function that created the setting.
+	name string
+	// context describes the definition the setting was created in.
+	context string
+	// location is the location of the DSL function call.
+	location dslengine.Location
+	// applied is true once Cache has applied the setting.
+	applied bool
+}
+
+// pendingCacheSettings lists the cache settings created in the action or resource DSL being
+// executed, see checkCacheSettings.
+var pendingCacheSettings []*cacheSetting
+
+// Cache defines the cache policy of the action responses. The policy sets the Cache-Control and
+// Vary headers of the successful responses and configures the optional server-side response cache
+// (see the middleware/cache package). Cache may appear in Action or in Resource in which case the
+// policy applies to all the resource actions that have a GET route. Cache accepts the policy
+// settings:
+//
+// MaxAge sets the number of seconds during which the responses are fresh.
+//
+// SWR sets the number of seconds after the responses become stale during which caches may serve
+// them while revalidating them in the background (stale-while-revalidate).
+//
+// VaryOn lists the request headers that select the cached responses.
+//
+// Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Cache(MaxAge(60), SWR(300), VaryOn("Accept", "X-Tenant"))
+//		Response(OK)
+//	})
+func Cache(settings ...func()) {
+	c := &design.CacheDefinition{}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		if def.Cache != nil {
+			dslengine.ReportError("cache policy already defined")
+			return
+		}
+		c.Parent = def
+		def.Cache = c
+	case *design.ResourceDefinition:
+		if def.Cache != nil {
+			dslengine.ReportError("cache policy already defined")
+			return
+		}
+		c.Parent = def
+		def.Cache = c
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	for _, s := range settings {
+		if !dslengine.Execute(s, c) {
+			return
+		}
+	}
+}
+
+// SWR sets the number of seconds during which caches may serve stale responses while
+// revalidating them in the background. SWR must be given to Cache.
+func SWR(val uint) func() {
+	if !cacheContext() {
+		dslengine.IncompatibleDSL()
+		return func() {}
+	}
+	return newCacheSetting("SWR", func(c *design.CacheDefinition) {
+		c.StaleWhileRevalidate = val
+	})
+}
+
+// VaryOn lists the request headers that select the cached responses. VaryOn must be given to
+// Cache.
+func VaryOn(headers ...string) func() {
+	if !cacheContext() {
+		dslengine.IncompatibleDSL()
+		return func() {}
+	}
+	return newCacheSetting("VaryOn", func(c *design.CacheDefinition) {
+		c.Vary = append(c.Vary, headers...)
+	})
+}
+
+// cacheContext returns true if the current definition may define a cache policy.
+func cacheContext() bool {
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition, *design.ResourceDefinition:
+		return true
+	}
+	return false
+}
+
+// newCacheSetting returns the cache policy setting created by the DSL function with the given name.
+// The setting must be given to Cache, checkCacheSettings reports the settings that are not.
+func newCacheSetting(name string, apply func(*design.CacheDefinition)) func() {
+	s := &cacheSetting{
+		name:     name,
+		context:  dslengine.CurrentDefinition().Context(),
+		location: dslengine.CallerLocation(),
+	}
+	pendingCacheSettings = append(pendingCacheSettings, s)
+	return func() {
+		s.applied = true
+		if c, ok := cacheDefinition(); ok {
+			apply(c)
+		}
+	}
+}
+
+// checkCacheSettings reports the cache settings created since the last check that were not given
+// to Cache, e.g. MaxAge used directly in an action, instead of silently ignoring them.
+func checkCacheSettings() {
+	for _, s := range pendingCacheSettings {
+		if s.applied {
+			continue
+		}
+		dslengine.Errors = append(dslengine.Errors, &dslengine.Error{
+			GoError: fmt.Errorf("invalid use of %s in %s, %s must be given to Cache", s.name, s.context, s.name),
+			File:    s.location.File,
+			Line:    s.location.Line,
+		})
+	}
+	pendingCacheSettings = nil
+}
+
+// checkedCache returns a DSL function that runs dsl then checks the cache settings it created.
+func checkedCache(dsl func()) func() {
+	if dsl == nil {
+		return nil
+	}
+	return func() {
+		dsl()
+		checkCacheSettings()
+	}
+}
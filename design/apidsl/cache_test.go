@@ -0,0 +1,148 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var resDSL, actionDSL func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		resDSL = nil
+		actionDSL = nil
+		verb = GET
+	})
+
+	JustBeforeEach(func() {
+		Resource("widget", func() {
+			if resDSL != nil {
+				resDSL()
+			}
+			Action("show", func() {
+				Routing(verb("/:id"))
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("in an action", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Cache(MaxAge(60), SWR(300), VaryOn("Accept", "X-Tenant"))
+			}
+		})
+
+		It("sets the cache policy", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["show"].AllCache()
+			Ω(c).ShouldNot(BeNil())
+			Ω(c.MaxAge).Should(Equal(uint(60)))
+			Ω(c.StaleWhileRevalidate).Should(Equal(uint(300)))
+			Ω(c.Vary).Should(Equal([]string{"Accept", "X-Tenant"}))
+			Ω(c.CacheControl()).Should(Equal("max-age=60, stale-while-revalidate=300"))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				Cache(MaxAge(10))
+			}
+		})
+
+		It("applies to the GET actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["show"].AllCache()
+			Ω(c).ShouldNot(BeNil())
+			Ω(c.CacheControl()).Should(Equal("max-age=10"))
+		})
+
+		Context("with a non GET action", func() {
+			BeforeEach(func() {
+				verb = DELETE
+			})
+
+			It("does not apply", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Resources["widget"].Actions["show"].AllCache()).Should(BeNil())
+			})
+		})
+	})
+
+	Context("in a non GET action", func() {
+		BeforeEach(func() {
+			verb = PUT
+			actionDSL = func() {
+				Cache(MaxAge(60))
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("only actions with a GET route may define a cache policy"))
+		})
+	})
+
+	Context("with an empty vary header", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Cache(VaryOn(""))
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a setting used outside of Cache in an action", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				MaxAge(60)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid use of MaxAge"))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("MaxAge must be given to Cache"))
+		})
+	})
+
+	Context("with a setting used outside of Cache in a resource", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				SWR(300)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("SWR must be given to Cache"))
+		})
+	})
+})
+
+var _ = Describe("MaxAge", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("reports an incompatible DSL error outside of Origin, Action and Resource", func() {
+		API("widgets", func() {
+			MaxAge(60)
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid use of MaxAge"))
+	})
+})
@@ -0,0 +1,76 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Capture makes the generated code record the requests and responses of the actions into the
+// sink registered with capture.UseSink for debugging purposes, capturing is disabled until a sink
+// is registered. The values of the attributes, headers and parameters marked with Sensitive or
+// encrypted with Encrypted are masked in the captured exchanges. The generated client tool
+// includes a "replay" command that re-issues the captured requests against another environment.
+// Capture may appear in API to apply to all the actions or in Resource to override the API
+// settings for the resource actions.
+//
+// The optional DSL may use MaxCapturedBody to limit the size of the captured response bodies
+// (defaults to 64KB). Example:
+//
+//	API("cellar", func() {
+//		Capture(func() {
+//			MaxCapturedBody(4096)
+//		})
+//	})
+func Capture(dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to Capture")
+		return
+	}
+	c := &design.CaptureDefinition{MaxBodySize: design.DefaultCaptureMaxBodySize}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Capture != nil {
+			dslengine.ReportError("capture already defined")
+			return
+		}
+		c.Parent = def
+		def.Capture = c
+	case *design.ResourceDefinition:
+		if def.Capture != nil {
+			dslengine.ReportError("capture already defined")
+			return
+		}
+		c.Parent = def
+		def.Capture = c
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], c)
+	}
+}
+
+// MaxCapturedBody sets the maximum number of response body bytes captured, larger bodies are
+// omitted from the captured exchanges, see Capture. MaxCapturedBody may only appear in Capture.
+func MaxCapturedBody(size int) {
+	if c, ok := captureDefinition(); ok {
+		c.MaxBodySize = size
+	}
+}
+
+// Sensitive marks the attribute, header or parameter as holding sensitive data whose values are
+// masked in the captured requests and responses, see Capture. Sensitive must appear in the DSL of
+// an attribute. Example:
+//
+//	Attribute("password", String, func() {
+//		Sensitive()
+//	})
+func Sensitive() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.SensitiveMetadata] = []string{}
+	}
+}
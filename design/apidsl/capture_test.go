@@ -0,0 +1,161 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capture", func() {
+	var apiDSL, resDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		Resource("res", resDSL)
+		dslengine.Run()
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			apiDSL = func() { Capture() }
+		})
+
+		It("uses the default maximum body size", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Capture).ShouldNot(BeNil())
+			Ω(Design.Capture.MaxBodySize).Should(Equal(DefaultCaptureMaxBodySize))
+			Ω(Design.Resources["res"].AllCapture()).Should(Equal(Design.Capture))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				Capture(func() {
+					MaxCapturedBody(1024)
+				})
+			}
+		})
+
+		It("applies to the resource only", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Capture).Should(BeNil())
+			Ω(Design.Resources["res"].AllCapture().MaxBodySize).Should(Equal(1024))
+		})
+	})
+
+	Context("with an invalid maximum body size", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Capture(func() {
+					MaxCapturedBody(0)
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("maximum captured body size must be greater than 0"))
+		})
+	})
+
+	Context("with sensitive attributes", func() {
+		BeforeEach(func() {
+			card := Type("card", func() {
+				Attribute("holder", String)
+				Attribute("number", String, func() {
+					Sensitive()
+				})
+			})
+			account := MediaType("application/vnd.account", func() {
+				Attributes(func() {
+					Attribute("name", String)
+					Attribute("token", String, func() {
+						Sensitive()
+					})
+				})
+				View("default", func() {
+					Attribute("name")
+					Attribute("token")
+				})
+			})
+			resDSL = func() {
+				Capture()
+				Headers(func() {
+					Header("X-Api-Key", func() {
+						Sensitive()
+					})
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Params(func() {
+						Param("otp", String, func() {
+							Sensitive()
+						})
+						Param("page", Integer)
+					})
+					Payload(func() {
+						Attribute("password", String, func() {
+							Sensitive()
+						})
+						Attribute("cards", ArrayOf(card))
+					})
+					Response(OK, account)
+				})
+			}
+		})
+
+		It("lists the sensitive values of the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			a := Design.Resources["res"].Actions["create"]
+			Ω(a.SensitivePayloadPaths()).Should(ConsistOf("password", "cards.number"))
+			Ω(a.SensitiveResponsePaths()).Should(Equal([]string{"token"}))
+			Ω(a.SensitiveHeaders()).Should(Equal([]string{"X-Api-Key"}))
+			Ω(a.SensitiveParams()).Should(Equal([]string{"otp"}))
+		})
+	})
+
+	Context("with API key security schemes", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Capture()
+				APIKeySecurity("header_key", func() {
+					Header("X-Api-Key")
+				})
+				APIKeySecurity("query_key", func() {
+					Query("api_key")
+				})
+			}
+			resDSL = func() {
+				Action("header", func() {
+					Routing(GET("/header"))
+					Security("header_key")
+					Response(OK)
+				})
+				Action("query", func() {
+					Routing(GET("/query"))
+					Security("query_key")
+					Response(OK)
+				})
+			}
+		})
+
+		It("lists the credentials as sensitive", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			header := Design.Resources["res"].Actions["header"]
+			Ω(header.SensitiveHeaders()).Should(Equal([]string{"X-Api-Key"}))
+			Ω(header.SensitiveParams()).Should(BeEmpty())
+			query := Design.Resources["res"].Actions["query"]
+			Ω(query.SensitiveHeaders()).Should(BeEmpty())
+			Ω(query.SensitiveParams()).Should(Equal([]string{"api_key"}))
+		})
+	})
+})
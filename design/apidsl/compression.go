@@ -0,0 +1,80 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Compress enables the compression of the response bodies using the encoding negotiated with the
+// client via the Accept-Encoding header. Compress may appear in API to compress the responses of
+// all the actions or in Resource to override the API settings for the resource actions.
+//
+// The optional DSL may use Encodings to list the supported encodings in order of preference
+// (defaults to "gzip" and "deflate", other encodings such as "br" require registering an encoder
+// with the compress middleware package), MinSize to set the minimum size in bytes of the
+// compressed bodies (defaults to 1024) and CompressTypes to restrict the compression to some
+// content types. Example:
+//
+//	API("cellar", func() {
+//		Compress(func() {
+//			Encodings("br", "gzip")
+//			MinSize(512)
+//			CompressTypes("application/json", "text/*")
+//		})
+//	})
+func Compress(dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to Compress")
+		return
+	}
+	c := &design.CompressionDefinition{
+		Encodings: append([]string{}, design.DefaultCompressionEncodings...),
+		MinSize:   design.DefaultCompressionMinSize,
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Compression != nil {
+			dslengine.ReportError("compression already defined")
+			return
+		}
+		c.Parent = def
+		def.Compression = c
+	case *design.ResourceDefinition:
+		if def.Compression != nil {
+			dslengine.ReportError("compression already defined")
+			return
+		}
+		c.Parent = def
+		def.Compression = c
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], c)
+	}
+}
+
+// Encodings lists the names of the encodings used to compress the response bodies in order of
+// preference, see Compress. Encodings may only appear in Compress.
+func Encodings(names ...string) {
+	if c, ok := compressionDefinition(); ok {
+		c.Encodings = names
+	}
+}
+
+// MinSize sets the minimum size in bytes of the compressed response bodies, see Compress.
+// MinSize may only appear in Compress.
+func MinSize(size int) {
+	if c, ok := compressionDefinition(); ok {
+		c.MinSize = size
+	}
+}
+
+// CompressTypes lists the media types of the compressed response bodies, see Compress. A type may
+// end with "/*" to allow all the subtypes. CompressTypes may only appear in Compress.
+func CompressTypes(types ...string) {
+	if c, ok := compressionDefinition(); ok {
+		c.ContentTypes = append(c.ContentTypes, types...)
+	}
+}
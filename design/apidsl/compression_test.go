@@ -0,0 +1,112 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compress", func() {
+	var apiDSL, resDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		Resource("res", resDSL)
+		dslengine.Run()
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			apiDSL = func() { Compress() }
+		})
+
+		It("uses the default settings", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Compression).ShouldNot(BeNil())
+			Ω(Design.Compression.Encodings).Should(Equal(DefaultCompressionEncodings))
+			Ω(Design.Compression.MinSize).Should(Equal(DefaultCompressionMinSize))
+			Ω(Design.Compression.ContentTypes).Should(BeEmpty())
+		})
+
+		It("applies to the resources", func() {
+			Ω(Design.Resources["res"].AllCompression()).Should(Equal(Design.Compression))
+		})
+	})
+
+	Context("with custom settings", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Compress(func() {
+					Encodings("br", "gzip")
+					MinSize(512)
+					CompressTypes("application/json", "text/*")
+				})
+			}
+		})
+
+		It("records them", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Compression.Encodings).Should(Equal([]string{"br", "gzip"}))
+			Ω(Design.Compression.MinSize).Should(Equal(512))
+			Ω(Design.Compression.ContentTypes).Should(Equal([]string{"application/json", "text/*"}))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			apiDSL = func() { Compress() }
+			resDSL = func() {
+				Compress(func() {
+					MinSize(0)
+				})
+			}
+		})
+
+		It("overrides the API settings", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["res"].AllCompression()
+			Ω(c).ShouldNot(Equal(Design.Compression))
+			Ω(c.MinSize).Should(Equal(0))
+		})
+	})
+
+	Context("with invalid settings", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Compress(func() {
+					Encodings("gzip", "identity", "gzip")
+					MinSize(-1)
+				})
+			}
+		})
+
+		It("reports errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("minimum size cannot be negative"))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid encoding "identity"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`encoding "gzip" is listed twice`))
+		})
+	})
+
+	Context("without encodings", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Compress(func() {
+					Encodings()
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Computed adds a computed attribute to a view. Computed attributes are not part of the media type
+// definition, their values are supplied when the view is rendered by the implementation of the
+// generated "Computer" interface registered with the service. This makes it possible to render
+// display-only fields without adding them to the core media type. Computed accepts the same
+// arguments as Attribute, computed attributes cannot be media types and are never required.
+//
+// Computed must appear in View. Example:
+//
+//	View("display", func() {
+//		Attribute("first_name")
+//		Attribute("last_name")
+//		Computed("full_name", String, "Concatenation of first and last names")
+//	})
+func Computed(name string, args ...interface{}) {
+	a, ok := attributeDefinition()
+	if !ok {
+		return
+	}
+	Attribute(name, args...)
+	att := a.Type.ToObject()[name]
+	if att == nil {
+		return
+	}
+	if _, ok := att.Type.(*design.MediaTypeDefinition); ok {
+		dslengine.ReportError("computed attribute %#v cannot be a media type", name)
+		return
+	}
+	if att.Metadata == nil {
+		att.Metadata = make(dslengine.MetadataDefinition)
+	}
+	att.Metadata[design.ComputedMetadata] = []string{}
+}
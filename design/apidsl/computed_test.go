@@ -0,0 +1,61 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Computed", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("adds computed attributes to views", func() {
+		mt := MediaType("application/vnd.user", func() {
+			Attributes(func() {
+				Attribute("first_name", String)
+				Attribute("last_name", String)
+			})
+			View("default", func() {
+				Attribute("first_name")
+				Attribute("last_name")
+				Computed("full_name", String, "Full name")
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(mt.Type.ToObject()).ShouldNot(HaveKey("full_name"))
+		view := mt.Views["default"]
+		Ω(view.ComputedAttributes()).Should(Equal([]string{"full_name"}))
+		p, _, err := mt.Project("default")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(p.Type.ToObject()).Should(HaveKey("full_name"))
+		Ω(p.Type.ToObject()["full_name"].Description).Should(Equal("Full name"))
+	})
+
+	It("rejects computed attributes that are media type attributes", func() {
+		MediaType("application/vnd.user", func() {
+			Attributes(func() {
+				Attribute("name", String)
+			})
+			View("default", func() {
+				Computed("name", String)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring(`computed attribute "name" is also a media type attribute`))
+	})
+
+	It("rejects computed attributes outside of views", func() {
+		Type("User", func() {
+			Computed("full_name", String)
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring("computed attributes can only be defined in views"))
+	})
+})
@@ -0,0 +1,79 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// MaxConcurrent limits the number of requests handled concurrently by the action. MaxConcurrent
+// may appear in Action or in Resource in which case each resource action gets its own budget of
+// n requests. Requests exceeding the budget wait for a slot to free up unless LoadShed is used,
+// the wait is bounded by MaxWait.
+// Example:
+//
+//	Action("search", func() {
+//		Routing(GET("/search"))
+//		MaxConcurrent(20)
+//		LoadShed()
+//		Response(OK)
+//	})
+func MaxConcurrent(n int) {
+	if c := concurrency(); c != nil {
+		c.Max = n
+	}
+}
+
+// LoadShed makes the requests that exceed the concurrency budget set with MaxConcurrent fail
+// right away with a 503 response instead of waiting. The optional argument is the number of
+// seconds set in the Retry-After header of the response, it defaults to 1. LoadShed may appear
+// in Action or in Resource.
+func LoadShed(retryAfter ...uint) {
+	if len(retryAfter) > 1 {
+		dslengine.ReportError("too many arguments in call to LoadShed")
+		return
+	}
+	if c := concurrency(); c != nil {
+		c.Shed = true
+		if len(retryAfter) == 1 {
+			c.RetryAfter = retryAfter[0]
+		}
+	}
+}
+
+// MaxWait sets the number of seconds the requests exceeding the concurrency budget set with
+// MaxConcurrent wait for a slot before failing with a 503 response, it defaults to 30. A value of
+// 0 makes the requests wait until a slot frees up or the client disconnects. MaxWait may appear
+// in Action or in Resource.
+func MaxWait(seconds uint) {
+	if c := concurrency(); c != nil {
+		c.MaxWait = seconds
+	}
+}
+
+// concurrency returns the concurrency budget of the current action or resource, it creates it if
+// needed.
+func concurrency() *design.ConcurrencyDefinition {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		if def.Concurrency == nil {
+			def.Concurrency = &design.ConcurrencyDefinition{
+				RetryAfter: design.DefaultRetryAfter,
+				MaxWait:    design.DefaultMaxWait,
+				Parent:     def,
+			}
+		}
+		return def.Concurrency
+	case *design.ResourceDefinition:
+		if def.Concurrency == nil {
+			def.Concurrency = &design.ConcurrencyDefinition{
+				RetryAfter: design.DefaultRetryAfter,
+				MaxWait:    design.DefaultMaxWait,
+				Parent:     def,
+			}
+		}
+		return def.Concurrency
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaxConcurrent", func() {
+	var resDSL, actionDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		resDSL = nil
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("widget", func() {
+			if resDSL != nil {
+				resDSL()
+			}
+			Action("search", func() {
+				Routing(GET("/search"))
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("in an action", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				MaxConcurrent(20)
+			}
+		})
+
+		It("sets the concurrency budget", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["search"].AllConcurrency()
+			Ω(c).ShouldNot(BeNil())
+			Ω(c.Max).Should(Equal(20))
+			Ω(c.Shed).Should(BeFalse())
+			Ω(c.MaxWait).Should(Equal(uint(DefaultMaxWait)))
+		})
+	})
+
+	Context("with a bounded wait", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				MaxConcurrent(20)
+				MaxWait(5)
+			}
+		})
+
+		It("sets the maximum wait", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["search"].AllConcurrency()
+			Ω(c.MaxWait).Should(Equal(uint(5)))
+		})
+	})
+
+	Context("with load shedding", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				LoadShed(10)
+				MaxConcurrent(20)
+			}
+		})
+
+		It("sheds the requests exceeding the budget", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["search"].AllConcurrency()
+			Ω(c.Max).Should(Equal(20))
+			Ω(c.Shed).Should(BeTrue())
+			Ω(c.RetryAfter).Should(Equal(uint(10)))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				MaxConcurrent(5)
+				LoadShed()
+			}
+		})
+
+		It("applies to the resource actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			c := Design.Resources["widget"].Actions["search"].AllConcurrency()
+			Ω(c).ShouldNot(BeNil())
+			Ω(c.Max).Should(Equal(5))
+			Ω(c.RetryAfter).Should(Equal(uint(DefaultRetryAfter)))
+		})
+	})
+
+	Context("with load shedding and no budget", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				LoadShed()
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("use MaxConcurrent to set it"))
+		})
+	})
+})
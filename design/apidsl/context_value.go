@@ -0,0 +1,109 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ContextValue declares a value that actions expect in the request context such as the
+// authenticated user, the tenant or the locale. The generated code provides typed accessors and a
+// middleware that stores the value in the request context. The generated action contexts fail to
+// initialize when an expected value is missing.
+//
+// ContextValue may appear at the top level or in API to define the value, its arguments are the
+// value name, type and optional description. ContextValue may also appear in Resource or Action
+// to declare that the actions expect the value, in which case the first argument may be the name
+// of a value defined elsewhere or the value returned by ContextValue. Values expected by a
+// resource are expected by all its actions. Examples:
+//
+//    var CurrentUser = ContextValue("user", User, "Authenticated user")
+//
+//    Action("show", func() {
+//        ContextValue(CurrentUser)
+//        ContextValue("locale", String, "Locale negotiated with the client")
+//    })
+//
+func ContextValue(name interface{}, args ...interface{}) *design.ContextValueDefinition {
+	var def *design.ContextValueDefinition
+	switch val := name.(type) {
+	case string:
+		for _, v := range design.Design.ContextValues {
+			if v.Name == val {
+				def = v
+				break
+			}
+		}
+		if def != nil && len(args) != 0 {
+			dslengine.ReportError("cannot redefine context value with name %q", val)
+			return nil
+		}
+	case *design.ContextValueDefinition:
+		if len(args) != 0 {
+			dslengine.ReportError("cannot redefine context value %q", val.Name)
+			return nil
+		}
+		def = val
+	default:
+		dslengine.ReportError("invalid value for 'name' parameter, specify a string or a *ContextValueDefinition")
+		return nil
+	}
+
+	switch parent := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *dslengine.TopLevelDefinition:
+		if def != nil {
+			dslengine.ReportError("cannot redefine context value with name %q", def.Name)
+			return nil
+		}
+		if def = newContextValue(name.(string), args); def == nil {
+			return nil
+		}
+	case *design.ResourceDefinition:
+		if def == nil {
+			if def = newContextValue(name.(string), args); def == nil {
+				return nil
+			}
+		}
+		parent.ContextValues = append(parent.ContextValues, def)
+	case *design.ActionDefinition:
+		if def == nil {
+			if def = newContextValue(name.(string), args); def == nil {
+				return nil
+			}
+		}
+		parent.ContextValues = append(parent.ContextValues, def)
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+
+	return def
+}
+
+// newContextValue defines a context value given its type and optional description and adds it to
+// the API context values.
+func newContextValue(name string, args []interface{}) *design.ContextValueDefinition {
+	if len(args) == 0 {
+		dslengine.ReportError("context value %q not found", name)
+		return nil
+	}
+	if len(args) > 2 {
+		dslengine.ReportError("too many arguments in call to ContextValue")
+		return nil
+	}
+	t, ok := args[0].(design.DataType)
+	if !ok {
+		dslengine.ReportError("invalid type for context value %q, specify a DataType", name)
+		return nil
+	}
+	def := &design.ContextValueDefinition{Name: name, Type: t}
+	if len(args) == 2 {
+		desc, ok := args[1].(string)
+		if !ok {
+			dslengine.ReportError("invalid description for context value %q, specify a string", name)
+			return nil
+		}
+		def.Description = desc
+	}
+	design.Design.ContextValues = append(design.Design.ContextValues, def)
+	return def
+}
@@ -0,0 +1,75 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ContextValue", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with values expected by a resource and an action", func() {
+		BeforeEach(func() {
+			user := Type("User", func() {
+				Attribute("id", Integer)
+			})
+			API("contextual", func() {
+				ContextValue("user", user, "Authenticated user")
+			})
+			Resource("res", func() {
+				ContextValue("user")
+				Action("show", func() {
+					Routing(GET("/"))
+					ContextValue("locale", String)
+					Response(NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("defines the values and lists the values expected by the action", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.ContextValues).Should(HaveLen(2))
+			Ω(Design.ContextValues[0].Description).Should(Equal("Authenticated user"))
+			Ω(Design.ContextValues[0].Type.(*UserTypeDefinition).TypeName).Should(Equal("User"))
+			all := Design.Resources["res"].Actions["show"].AllContextValues()
+			Ω(all).Should(HaveLen(2))
+			Ω(all[0].Name).Should(Equal("user"))
+			Ω(all[1].Name).Should(Equal("locale"))
+			Ω(all[1].Type).Should(Equal(String))
+		})
+	})
+
+	Context("with an unknown value", func() {
+		BeforeEach(func() {
+			API("contextual", nil)
+			Resource("res", func() {
+				ContextValue("unknown")
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an inline object type", func() {
+		BeforeEach(func() {
+			API("contextual", func() {
+				ContextValue("user", Object{"id": &AttributeDefinition{Type: Integer}})
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot be an inline object"))
+		})
+	})
+})
@@ -0,0 +1,173 @@
+package apidsl
+
+import (
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// CRUD adds the standard list, show, create, update and delete actions of the resource rendered
+// with the given media type. The actions use the conventional routes relative to the resource
+// base path where the item ID parameter is named after the resource, e.g. "bottleID":
+//
+//	list:   GET ""                 OK with the collection of the media type
+//	show:   GET "/:bottleID"       OK with the media type, NotFound
+//	create: POST ""                Created, BadRequest
+//	update: PATCH "/:bottleID"     NoContent, NotFound, BadRequest
+//	delete: DELETE "/:bottleID"    NoContent, NotFound
+//
+// The create and update payloads hold the media type attributes minus the ReadOnly ones, the
+// create payload requires the attributes required by the media type. The ID parameter has the
+// type of the media type "id" attribute if any, String otherwise. CRUD also sets the resource
+// default media type and the canonical action to "show" unless the resource already defines them.
+//
+// The optional DSL may use Exclude to skip some of the actions. Actions defined with Action after
+// CRUD extend the generated actions. CRUD must appear in Resource. Example:
+//
+//	Resource("bottle", func() {
+//		BasePath("/bottles")
+//		CRUD(BottleMedia, func() {
+//			Exclude("delete")
+//		})
+//	})
+func CRUD(mt *design.MediaTypeDefinition, dsl ...func()) {
+	r, ok := resourceDefinition()
+	if !ok {
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments given to CRUD")
+		return
+	}
+	if mt == nil || mt.UserTypeDefinition == nil || mt.Type == nil {
+		dslengine.ReportError("invalid CRUD media type, media type is not initialized")
+		return
+	}
+	if !mt.Type.IsObject() {
+		dslengine.ReportError("invalid CRUD media type %#v, media type must be an object", mt.Identifier)
+		return
+	}
+	crud := &design.CRUDDefinition{Resource: r, MediaType: mt}
+	if len(dsl) == 1 && !dslengine.Execute(dsl[0], crud) {
+		return
+	}
+	if design.Design.MediaTypeWithIdentifier(r.MediaType) == nil {
+		DefaultMedia(mt)
+	}
+	id := crudIDParam(r.Name)
+	idType := design.DataType(design.String)
+	if att, ok := mt.Type.ToObject()["id"]; ok && att.Type.IsPrimitive() {
+		idType = att.Type
+	}
+	if r.CanonicalActionName == "" && !crud.IsExcluded("show") {
+		r.CanonicalActionName = "show"
+	}
+	loc := dslengine.CallerLocation()
+	item := "/:" + id
+	params := func() {
+		Params(func() {
+			Param(id, idType)
+		})
+	}
+	crudAction(crud, "list", loc, func() {
+		Description("List all " + r.Name + " items")
+		Routing(GET(""))
+		Response(design.OK, CollectionOf(mt))
+	})
+	crudAction(crud, "show", loc, func() {
+		Description("Retrieve the " + r.Name + " with the given ID")
+		Routing(GET(item))
+		params()
+		Response(design.OK, mt)
+		Response(design.NotFound)
+	})
+	crudAction(crud, "create", loc, func() {
+		Description("Create a new " + r.Name)
+		Routing(POST(""))
+		Payload(mt.WritableAttributes(true))
+		Response(design.Created)
+		Response(design.BadRequest)
+	})
+	crudAction(crud, "update", loc, func() {
+		Description("Update the " + r.Name + " with the given ID")
+		Routing(PATCH(item))
+		params()
+		Payload(mt.WritableAttributes(false))
+		Response(design.NoContent)
+		Response(design.NotFound)
+		Response(design.BadRequest)
+	})
+	crudAction(crud, "delete", loc, func() {
+		Description("Delete the " + r.Name + " with the given ID")
+		Routing(DELETE(item))
+		params()
+		Response(design.NoContent)
+		Response(design.NotFound)
+	})
+}
+
+// Exclude lists the names of the actions CRUD does not generate, one of "list", "show",
+// "create", "update" or "delete". Exclude must appear in CRUD.
+func Exclude(actions ...string) {
+	crud, ok := dslengine.CurrentDefinition().(*design.CRUDDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	for _, a := range actions {
+		known := false
+		for _, n := range design.CRUDActions {
+			if a == n {
+				known = true
+				break
+			}
+		}
+		if !known {
+			dslengine.ReportError("unknown CRUD action %#v, must be one of %v", a, design.CRUDActions)
+			continue
+		}
+		crud.Excluded = append(crud.Excluded, a)
+	}
+}
+
+// ReadOnly marks the attribute as read-only: its value is set by the service so that CRUD omits
+// it from the generated payloads. ReadOnly must appear in the DSL of a media type attribute.
+// Example:
+//
+//	Attribute("created_at", DateTime, func() {
+//		ReadOnly()
+//	})
+func ReadOnly() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.ReadOnlyMetadata] = []string{}
+	}
+}
+
+// crudAction defines the CRUD action with the given name unless excluded.
+func crudAction(crud *design.CRUDDefinition, name string, loc dslengine.Location, dsl func()) {
+	if crud.IsExcluded(name) {
+		return
+	}
+	if _, ok := crud.Resource.Actions[name]; ok {
+		dslengine.ReportError("CRUD action %#v is already defined", name)
+		return
+	}
+	Action(name, dsl)
+	if a, ok := crud.Resource.Actions[name]; ok {
+		a.Location = loc
+	}
+}
+
+// crudIDParam returns the name of the item ID parameter of the resource with the given name,
+// e.g. "bottleID" for "bottle".
+func crudIDParam(resource string) string {
+	runes := []rune(camelize(resource))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes) + "ID"
+}
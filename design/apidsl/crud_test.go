@@ -0,0 +1,111 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CRUD", func() {
+	var mt *MediaTypeDefinition
+	var dsl func()
+	var res *ResourceDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+		mt = MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("id", Integer, func() {
+					ReadOnly()
+				})
+				Attribute("name", String)
+				Attribute("vintage", Integer)
+				Required("id", "name")
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+				Attribute("vintage")
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		res = Resource("bottle", func() {
+			BasePath("/bottles")
+			if dsl != nil {
+				CRUD(mt, dsl)
+			} else {
+				CRUD(mt)
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("generates the standard actions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(res.Actions).Should(HaveLen(5))
+		Ω(res.MediaType).Should(Equal(mt.Identifier))
+		Ω(res.CanonicalActionName).Should(Equal("show"))
+
+		show := res.Actions["show"]
+		Ω(show.Routes).Should(HaveLen(1))
+		Ω(show.Routes[0].Verb).Should(Equal("GET"))
+		Ω(show.Routes[0].FullPath()).Should(Equal("/bottles/:bottleID"))
+		Ω(show.Params.Type.ToObject()["bottleID"].Type).Should(Equal(Integer))
+		Ω(show.Responses).Should(HaveKey(OK))
+		Ω(show.Responses).Should(HaveKey(NotFound))
+
+		list := res.Actions["list"]
+		Ω(list.Routes[0].FullPath()).Should(Equal("/bottles"))
+		Ω(list.Responses[OK].MediaType).Should(ContainSubstring("type=collection"))
+
+		Ω(res.Actions["update"].Routes[0].Verb).Should(Equal("PATCH"))
+		Ω(res.Actions["delete"].Routes[0].Verb).Should(Equal("DELETE"))
+	})
+
+	It("derives the payloads from the media type writable attributes", func() {
+		create := res.Actions["create"].Payload
+		Ω(create).ShouldNot(BeNil())
+		Ω(create.TypeName).Should(Equal("CreateBottlePayload"))
+		Ω(create.Type.ToObject()).ShouldNot(HaveKey("id"))
+		Ω(create.Type.ToObject()).Should(HaveKey("name"))
+		Ω(create.AllRequired()).Should(Equal([]string{"name"}))
+
+		update := res.Actions["update"].Payload
+		Ω(update).ShouldNot(BeNil())
+		Ω(update.Type.ToObject()).Should(HaveLen(2))
+		Ω(update.AllRequired()).Should(BeEmpty())
+	})
+
+	Context("with excluded actions", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Exclude("delete", "update")
+			}
+		})
+
+		It("does not generate them", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(res.Actions).Should(HaveLen(3))
+			Ω(res.Actions).ShouldNot(HaveKey("delete"))
+			Ω(res.Actions).ShouldNot(HaveKey("update"))
+		})
+	})
+
+	Context("with an unknown excluded action", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Exclude("patch")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown CRUD action "patch"`))
+		})
+	})
+})
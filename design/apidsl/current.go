@@ -131,3 +131,113 @@ func responseDefinition() (*design.ResponseDefinition, bool) {
 	}
 	return r, ok
 }
+
+// interceptorDefinition returns true and current context if it is an InterceptorDefinition,
+// nil and false otherwise.
+func interceptorDefinition() (*design.InterceptorDefinition, bool) {
+	i, ok := dslengine.CurrentDefinition().(*design.InterceptorDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return i, ok
+}
+
+// tlsDefinition returns true and current context if it is a TLSDefinition,
+// nil and false otherwise.
+func tlsDefinition() (*design.TLSDefinition, bool) {
+	t, ok := dslengine.CurrentDefinition().(*design.TLSDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return t, ok
+}
+
+// scalarDefinition returns true and current context if it is a ScalarDefinition,
+// nil and false otherwise.
+func scalarDefinition() (*design.ScalarDefinition, bool) {
+	s, ok := dslengine.CurrentDefinition().(*design.ScalarDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return s, ok
+}
+
+// methodOverrideDefinition returns true and current context if it is a MethodOverrideDefinition,
+// nil and false otherwise.
+func methodOverrideDefinition() (*design.MethodOverrideDefinition, bool) {
+	m, ok := dslengine.CurrentDefinition().(*design.MethodOverrideDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return m, ok
+}
+
+// compressionDefinition returns true and current context if it is a CompressionDefinition,
+// nil and false otherwise.
+func compressionDefinition() (*design.CompressionDefinition, bool) {
+	c, ok := dslengine.CurrentDefinition().(*design.CompressionDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return c, ok
+}
+
+// decompressionDefinition returns true and current context if it is a DecompressionDefinition,
+// nil and false otherwise.
+func decompressionDefinition() (*design.DecompressionDefinition, bool) {
+	d, ok := dslengine.CurrentDefinition().(*design.DecompressionDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return d, ok
+}
+
+// limitsDefinition returns true and current context if it is a LimitsDefinition,
+// nil and false otherwise.
+func limitsDefinition() (*design.LimitsDefinition, bool) {
+	l, ok := dslengine.CurrentDefinition().(*design.LimitsDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return l, ok
+}
+
+// captureDefinition returns true and current context if it is a CaptureDefinition,
+// nil and false otherwise.
+func captureDefinition() (*design.CaptureDefinition, bool) {
+	c, ok := dslengine.CurrentDefinition().(*design.CaptureDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return c, ok
+}
+
+// cacheDefinition returns true and current context if it is a CacheDefinition,
+// nil and false otherwise.
+func cacheDefinition() (*design.CacheDefinition, bool) {
+	c, ok := dslengine.CurrentDefinition().(*design.CacheDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return c, ok
+}
+
+// downloadDefinition returns true and current context if it is a DownloadDefinition,
+// nil and false otherwise.
+func downloadDefinition() (*design.DownloadDefinition, bool) {
+	d, ok := dslengine.CurrentDefinition().(*design.DownloadDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return d, ok
+}
+
+// uploadDefinition returns true and current context if it is an UploadDefinition,
+// nil and false otherwise.
+func uploadDefinition() (*design.UploadDefinition, bool) {
+	u, ok := dslengine.CurrentDefinition().(*design.UploadDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return u, ok
+}
@@ -0,0 +1,58 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Decompress makes the generated code accept request bodies compressed with gzip or deflate as
+// indicated by the Content-Encoding header. The bodies are decompressed transparently before
+// being decoded into the action payloads. Decompress may appear in API to apply to all the
+// actions or in Resource to override the API settings for the resource actions.
+//
+// The optional DSL may use MaxDecompressedSize to limit the size of the decompressed bodies
+// (defaults to 10MB), requests whose decompressed body exceeds the limit are rejected with a 413
+// response. Example:
+//
+//	Resource("events", func() {
+//		Decompress(func() {
+//			MaxDecompressedSize(1 << 20)
+//		})
+//	})
+func Decompress(dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to Decompress")
+		return
+	}
+	d := &design.DecompressionDefinition{MaxSize: design.DefaultDecompressionMaxSize}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Decompression != nil {
+			dslengine.ReportError("decompression already defined")
+			return
+		}
+		d.Parent = def
+		def.Decompression = d
+	case *design.ResourceDefinition:
+		if def.Decompression != nil {
+			dslengine.ReportError("decompression already defined")
+			return
+		}
+		d.Parent = def
+		def.Decompression = d
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], d)
+	}
+}
+
+// MaxDecompressedSize sets the maximum size in bytes of the decompressed request bodies, see
+// Decompress. MaxDecompressedSize may only appear in Decompress.
+func MaxDecompressedSize(size int64) {
+	if d, ok := decompressionDefinition(); ok {
+		d.MaxSize = size
+	}
+}
@@ -0,0 +1,69 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decompress", func() {
+	var apiDSL, resDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		Resource("res", resDSL)
+		dslengine.Run()
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			apiDSL = func() { Decompress() }
+		})
+
+		It("uses the default maximum size", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Decompression).ShouldNot(BeNil())
+			Ω(Design.Decompression.MaxSize).Should(Equal(int64(DefaultDecompressionMaxSize)))
+			Ω(Design.Resources["res"].AllDecompression()).Should(Equal(Design.Decompression))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resDSL = func() {
+				Decompress(func() {
+					MaxDecompressedSize(1024)
+				})
+			}
+		})
+
+		It("applies to the resource only", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Decompression).Should(BeNil())
+			Ω(Design.Resources["res"].AllDecompression().MaxSize).Should(Equal(int64(1024)))
+		})
+	})
+
+	Context("with an invalid maximum size", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Decompress(func() {
+					MaxDecompressedSize(0)
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("maximum decompressed size must be greater than 0"))
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DefaultFunc sets the name of the provider that computes the default value of an attribute when
+// the value cannot be written as a literal, e.g. a generated identifier or the current time. The
+// generated code calls the provider when the attribute is missing from the decoded payload. The
+// goa package registers two providers:
+//
+//    "uuid": generates a random UUID, may only be used with UUID attributes
+//    "now":  returns the current UTC time, may only be used with DateTime attributes
+//
+// Additional providers are registered by the service with goa.RegisterDefault. DefaultFunc may
+// only appear in the DSL of primitive attributes that do not use Default. Example:
+//
+//    Attribute("id", UUID, func() {
+//        DefaultFunc("uuid")
+//    })
+//    Attribute("created_at", DateTime, func() {
+//        DefaultFunc("now")
+//    })
+func DefaultFunc(name string) {
+	if name == "" {
+		dslengine.ReportError("default value provider name cannot be empty")
+		return
+	}
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.DefaultFuncMetadata] = []string{name}
+	}
+}
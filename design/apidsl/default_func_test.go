@@ -0,0 +1,100 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DefaultFunc", func() {
+	var attType DataType
+	var provider string
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		attType = UUID
+		provider = "uuid"
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		Type("Bottle", func() {
+			Attribute("id", attType, func() {
+				DefaultFunc(provider)
+				if dsl != nil {
+					dsl()
+				}
+			})
+			Attribute("name", String)
+		})
+		dslengine.Run()
+	})
+
+	It("sets the default value provider", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		obj := Design.Types["Bottle"].Type.ToObject()
+		Ω(obj["id"].DefaultFunc()).Should(Equal("uuid"))
+		Ω(obj["name"].DefaultFunc()).Should(BeEmpty())
+	})
+
+	Context("with a custom provider", func() {
+		BeforeEach(func() {
+			attType = Integer
+			provider = "sequence"
+		})
+
+		It("does not check the type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with an empty name", func() {
+		BeforeEach(func() {
+			provider = ""
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("default value provider name cannot be empty"))
+		})
+	})
+
+	Context("with a built-in provider of another type", func() {
+		BeforeEach(func() {
+			provider = "now"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`default value provider "now" may only be used with DateTime attributes`))
+		})
+	})
+
+	Context("on an attribute that is not a primitive", func() {
+		BeforeEach(func() {
+			attType = ArrayOf(String)
+			provider = "tags"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("may only be set on attributes of primitive type"))
+		})
+	})
+
+	Context("with a default value", func() {
+		BeforeEach(func() {
+			attType = String
+			provider = "sequence"
+			dsl = func() { Default("foo") }
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot have both a default value and a default value provider"))
+		})
+	})
+})
@@ -36,3 +36,5 @@ of a list of definitions. Definitions must implement the design.Definition inter
 additionally implement the design.Source and design.Validate interfaces.
 */
 package apidsl
+
+//go:generate go run gen_funcs.go
@@ -0,0 +1,67 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Downloadable makes the action serve its binary OK response as a downloadable file. The
+// generated action context exposes a Download method that honors Range requests so that clients
+// may fetch the file in several parts, sets the Content-Disposition header and issues an opaque
+// resume token (see goa.ServeDownload). Clients resume interrupted downloads by sending the token
+// back in the Resume-Token header together with a Range header, the full file is sent instead if
+// it changed in between. Downloadable may only appear in Action and accepts the settings:
+//
+// FilenameFrom sets the name of the action parameter whose value is used as the file name in the
+// Content-Disposition header.
+//
+// Inline asks user agents to display the file rather than to save it.
+//
+// Example:
+//
+//	Action("download", func() {
+//		Routing(GET("/:name"))
+//		Params(func() {
+//			Param("name", String)
+//		})
+//		Downloadable(FilenameFrom("name"))
+//		Response(OK, "application/pdf")
+//		Response(NotFound)
+//	})
+func Downloadable(settings ...func()) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Download != nil {
+		dslengine.ReportError("download already defined")
+		return
+	}
+	d := &design.DownloadDefinition{Parent: a}
+	a.Download = d
+	for _, s := range settings {
+		if !dslengine.Execute(s, d) {
+			return
+		}
+	}
+}
+
+// FilenameFrom sets the name of the action parameter whose value is used as the file name in the
+// Content-Disposition header of the download. FilenameFrom must be given to Downloadable.
+func FilenameFrom(name string) func() {
+	return func() {
+		if d, ok := downloadDefinition(); ok {
+			d.Filename = name
+		}
+	}
+}
+
+// Inline makes the Content-Disposition header of the download ask user agents to display the
+// file rather than to save it. Inline must be given to Downloadable.
+func Inline() func() {
+	return func() {
+		if d, ok := downloadDefinition(); ok {
+			d.Inline = true
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Downloadable", func() {
+	var dsl, respDSL func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = GET
+		dsl = func() {
+			Downloadable(FilenameFrom("name"), Inline())
+		}
+		respDSL = func() {
+			Response(OK, "application/pdf")
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("report", func() {
+			Action("download", func() {
+				Routing(verb("/:name"))
+				Params(func() {
+					Param("name", String)
+					Param("version", Integer)
+				})
+				dsl()
+				respDSL()
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the download settings", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		d := Design.Resources["report"].Actions["download"].Download
+		Ω(d).ShouldNot(BeNil())
+		Ω(d.Filename).Should(Equal("name"))
+		Ω(d.Inline).Should(BeTrue())
+		Ω(d.Disposition()).Should(Equal("inline"))
+		Ω(d.Response().MediaType).Should(Equal("application/pdf"))
+	})
+
+	Context("with no setting", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Downloadable()
+			}
+		})
+
+		It("sends the file as an attachment", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			d := Design.Resources["report"].Actions["download"].Download
+			Ω(d.Filename).Should(BeEmpty())
+			Ω(d.Disposition()).Should(Equal("attachment"))
+		})
+	})
+
+	Context("with an unknown file name parameter", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Downloadable(FilenameFrom("title"))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`file name parameter "title" is not defined`))
+		})
+	})
+
+	Context("with a file name parameter that is not a string", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Downloadable(FilenameFrom("version"))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`file name parameter "version" must be a string`))
+		})
+	})
+
+	Context("with a non GET route", func() {
+		BeforeEach(func() {
+			verb = POST
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("only actions with a GET route may be downloadable"))
+		})
+	})
+
+	Context("with no OK response", func() {
+		BeforeEach(func() {
+			respDSL = func() {
+				Response(NotFound)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("downloadable actions must define an OK response"))
+		})
+	})
+
+	Context("with an OK response using a media type", func() {
+		BeforeEach(func() {
+			respDSL = func() {
+				Response(OK, ErrorMedia)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("the OK response of downloadable actions cannot use a media type or a type"))
+		})
+	})
+
+	Context("used twice", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Downloadable()
+				Downloadable()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("download already defined"))
+		})
+	})
+})
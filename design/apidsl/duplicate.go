@@ -0,0 +1,54 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DuplicateAttributes sets the policy applied to the attributes declared multiple times in the same
+// object, for example by repeated calls to Attribute. The policy is one of:
+//
+//    design.DuplicateError:    validation fails, the declarations that change the type of the
+//                              attribute defined by the reference type also fail (default)
+//    design.DuplicateLastWins: the last declaration replaces the previous ones
+//    design.DuplicateMerge:    each declaration is merged on top of the previous ones: the type
+//                              and description are overridden, validations, metadata and child
+//                              attributes are added
+//
+// DuplicateAttributes may appear in API to set the default policy, or in Type, MediaType, Payload
+// or the DSL of an object attribute to set the policy applied to the attributes declared directly
+// in the object. It must appear before the attribute declarations. Example:
+//
+//    var Account = Type("Account", func() {
+//        DuplicateAttributes(design.DuplicateMerge)
+//        Attribute("name", String)
+//        Attribute("name", func() { // adds a validation to the attribute declared above
+//            MinLength(1)
+//        })
+//    })
+func DuplicateAttributes(policy design.DuplicatePolicy) {
+	switch policy {
+	case design.DuplicateError, design.DuplicateLastWins, design.DuplicateMerge:
+	default:
+		dslengine.ReportError("invalid duplicate attribute policy %#v, must be one of %#v, %#v or %#v",
+			policy, design.DuplicateError, design.DuplicateLastWins, design.DuplicateMerge)
+		return
+	}
+	var att *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		def.DuplicatePolicy = policy
+		return
+	case *design.AttributeDefinition:
+		att = def
+	case *design.MediaTypeDefinition:
+		att = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if att.Metadata == nil {
+		att.Metadata = make(dslengine.MetadataDefinition)
+	}
+	att.Metadata[design.DuplicatePolicyMetadata] = []string{string(policy)}
+}
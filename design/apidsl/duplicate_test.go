@@ -0,0 +1,119 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DuplicateAttributes", func() {
+	var apiDSL, typeDSL func()
+	var obj Object
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		typeDSL = func() {
+			Attribute("name", String, func() {
+				MinLength(1)
+			})
+			Attribute("name", func() {
+				MaxLength(10)
+			})
+		}
+		obj = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		Type("Account", typeDSL)
+		dslengine.Run()
+		if t := Design.Types["Account"]; t != nil && t.Type != nil {
+			obj = t.Type.ToObject()
+		}
+	})
+
+	Context("with the default policy", func() {
+		It("reports the duplicate attributes", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`attribute "name" is declared multiple times, use DuplicateAttributes to change the policy`))
+		})
+	})
+
+	Context("with a reference", func() {
+		BeforeEach(func() {
+			base := Type("Abstract", func() {
+				Attribute("name", String)
+				Attribute("age", Integer)
+			})
+			typeDSL = func() {
+				Reference(base)
+				Attribute("name")
+				Attribute("age", String)
+			}
+		})
+
+		It("reports the attributes changing the referenced type", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`attribute "age" changes the type of the referenced attribute`))
+			Ω(dslengine.Errors.Error()).ShouldNot(ContainSubstring(`attribute "name"`))
+		})
+	})
+
+	Context("with the last-wins policy set in API", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				DuplicateAttributes(DuplicateLastWins)
+			}
+		})
+
+		It("keeps the last declaration", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(obj["name"].Validation.MinLength).Should(BeNil())
+			Ω(*obj["name"].Validation.MaxLength).Should(Equal(10))
+		})
+	})
+
+	Context("with the merge policy set in the type", func() {
+		BeforeEach(func() {
+			dsl := typeDSL
+			typeDSL = func() {
+				DuplicateAttributes(DuplicateMerge)
+				dsl()
+				Attribute("address", func() {
+					Attribute("city", String)
+				})
+				Attribute("address", func() {
+					Attribute("zip", String)
+					Required("zip")
+				})
+			}
+		})
+
+		It("merges the declarations", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(obj["name"].Type).Should(Equal(String))
+			Ω(*obj["name"].Validation.MinLength).Should(Equal(1))
+			Ω(*obj["name"].Validation.MaxLength).Should(Equal(10))
+			address := obj["address"].Type.ToObject()
+			Ω(address).Should(HaveKey("city"))
+			Ω(address).Should(HaveKey("zip"))
+			Ω(obj["address"].IsRequired("zip")).Should(BeTrue())
+		})
+	})
+
+	Context("with an invalid policy", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				DuplicateAttributes("first-wins")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid duplicate attribute policy "first-wins"`))
+		})
+	})
+})
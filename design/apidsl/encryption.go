@@ -0,0 +1,41 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Encrypted marks a string attribute as encrypted on the wire with the key identified by the
+// given alias, e.g. a KMS key alias. The generated code decrypts the attribute values of the
+// request payloads after the request has been logged and encrypts the attribute values of the
+// response media types before they are sent, using the goa.FieldCipher registered with the
+// service. Encrypted attributes are also redacted from the audit events. Only top level attributes
+// of payloads and media types are encrypted. Note that the payload validations apply to the
+// ciphertext as payloads are validated before being decrypted.
+//
+// Example:
+//
+//    var Customer = MediaType("application/vnd.customer+json", func() {
+//        Attributes(func() {
+//            Attribute("ssn", String, func() {
+//                Encrypted("alias/pii")
+//            })
+//        })
+//    })
+//
+func Encrypted(keyAlias string) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.StringKind {
+			incompatibleAttributeType("encrypted", a.Type.Name(), "a string")
+			return
+		}
+		if keyAlias == "" {
+			dslengine.ReportError("encryption key alias cannot be empty")
+			return
+		}
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.EncryptedMetadata] = []string{keyAlias}
+	}
+}
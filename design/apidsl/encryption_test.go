@@ -0,0 +1,51 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encrypted", func() {
+	var ut *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("on a string attribute", func() {
+		BeforeEach(func() {
+			ut = Type("Customer", func() {
+				Attribute("name", String)
+				Attribute("ssn", String, func() {
+					Encrypted("alias/pii")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("records the key alias", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			ssn := ut.Type.ToObject()["ssn"]
+			Ω(ssn.EncryptionKey()).Should(Equal("alias/pii"))
+			Ω(ut.EncryptedAttributes()).Should(Equal([]string{"ssn"}))
+		})
+	})
+
+	Context("on a non string attribute", func() {
+		BeforeEach(func() {
+			Type("Customer", func() {
+				Attribute("age", Integer, func() {
+					Encrypted("alias/pii")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,65 @@
+package apidsl
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// enumType implements the top level Enum DSL which defines a named enum type.
+func enumType(args []interface{}) *design.ScalarDefinition {
+	if len(args) < 2 {
+		dslengine.ReportError("Enum requires a name and at least one value")
+		return nil
+	}
+	name, ok := args[0].(string)
+	if !ok || name == "" {
+		dslengine.ReportError("the first argument of Enum must be the name of the type")
+		return nil
+	}
+	for _, s := range design.Design.Scalars {
+		if s.TypeName == name {
+			dslengine.ReportError("scalar %#v is defined twice", name)
+			return nil
+		}
+	}
+	values := args[1:]
+	var wire design.Primitive
+	switch values[0].(type) {
+	case string:
+		wire = design.String
+	case int:
+		wire = design.Integer
+	default:
+		dslengine.ReportError("enum %#v values must be strings or integers", name)
+		return nil
+	}
+	seen := make(map[interface{}]bool)
+	for _, v := range values {
+		if fmt.Sprintf("%T", v) != fmt.Sprintf("%T", values[0]) {
+			dslengine.ReportError("enum %#v values must all be strings or all be integers", name)
+			return nil
+		}
+		if seen[v] {
+			dslengine.ReportError("enum %#v defines the value %#v twice", name, v)
+			return nil
+		}
+		seen[v] = true
+	}
+	goType, native := camelize(name), "string"
+	if wire == design.Integer {
+		native = "int"
+	}
+	s := &design.ScalarDefinition{
+		Primitive:    wire,
+		TypeName:     name,
+		GoType:       goType,
+		ParseFunc:    "Parse" + goType,
+		FormatFunc:   native,
+		ValidateFunc: goType + ".Validate",
+		Values:       values,
+	}
+	design.Design.Scalars = append(design.Design.Scalars, s)
+	return s
+}
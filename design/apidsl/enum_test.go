@@ -0,0 +1,67 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Enum", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("defines a named enum type", func() {
+		s := Enum("order_status", "active", "archived")
+		ut := Type("Order", func() {
+			Attribute("status", s, func() {
+				Default("active")
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Scalars).Should(ConsistOf(s))
+		Ω(s.IsEnum()).Should(BeTrue())
+		Ω(s.Kind()).Should(Equal(StringKind))
+		Ω(s.GoType).Should(Equal("OrderStatus"))
+		Ω(s.ParseFunc).Should(Equal("ParseOrderStatus"))
+		Ω(ut.Type.ToObject()["status"].Type).Should(Equal(s))
+	})
+
+	It("defines integer enums", func() {
+		s := Enum("Priority", 1, 2, 3)
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(s.Kind()).Should(Equal(IntegerKind))
+		Ω(s.FormatFunc).Should(Equal("int"))
+	})
+
+	It("rejects invalid defaults", func() {
+		s := Enum("Status", "active", "archived")
+		Type("Order", func() {
+			Attribute("status", s, func() {
+				Default("deleted")
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+
+	It("rejects mixed values", func() {
+		Ω(Enum("Status", "active", 1)).Should(BeNil())
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+
+	It("still adds enum validations to attributes", func() {
+		ut := Type("Order", func() {
+			Attribute("status", String, func() {
+				Enum("active", "archived")
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(ut.Type.ToObject()["status"].Validation.Values).Should(Equal([]interface{}{"active", "archived"}))
+	})
+})
@@ -0,0 +1,57 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Error declares a sentinel error that action implementations return to produce the response with
+// the given name. goagen generates the error as a variable of the app package: ErrXxx for errors
+// declared in API and ErrResourceXxx for errors declared in Resource. The generated handlers match
+// the errors returned by the actions with errors.Is so that wrapped errors also produce the
+// response. The response must be an error response with no body or with the error media type, the
+// error name is used as the error response code. The optional argument is the error description.
+//
+// Error may appear in API or Resource, the errors apply to all the actions that define the
+// response. Example:
+//
+//	Resource("bottle", func() {
+//		Error("not_found", NotFound, "Bottle does not exist")
+//		Action("show", func() {
+//			Routing(GET("/:id"))
+//			Response(OK)
+//			Response(NotFound, ErrorMedia)
+//		})
+//	})
+//
+// The implementation of the show action can then return app.ErrBottleNotFound or an error wrapping
+// it, e.g. fmt.Errorf("bottle %d: %w", id, app.ErrBottleNotFound), to send a 404 response.
+func Error(name, response string, description ...string) {
+	if len(description) > 1 {
+		dslengine.ReportError("too many arguments in call to Error")
+		return
+	}
+	e := &design.ErrorDefinition{Name: name, Response: response}
+	if len(description) == 1 {
+		e.Description = description[0]
+	}
+	var errs *[]*design.ErrorDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		errs = &def.Errors
+		e.Parent = def
+	case *design.ResourceDefinition:
+		errs = &def.Errors
+		e.Parent = def
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	for _, other := range *errs {
+		if other.Name == name {
+			dslengine.ReportError("error %#v is defined twice", name)
+			return
+		}
+	}
+	*errs = append(*errs, e)
+}
@@ -0,0 +1,58 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// EmitEvent makes the generated code publish a CloudEvents v1.0 event after each successful call
+// to the action. The event type defaults to "<api>.<resource>.<action>", the optional argument
+// overrides it. The event source is the API name, the subject is the request path and the data is
+// the result rendered by the action response if it uses a media type. The events are sent to the
+// publisher registered with cloudevents.UsePublisher.
+//
+// EmitEvent may appear in Action or in Resource in which case all the resource actions that have
+// a POST, PUT, PATCH or DELETE route publish events, the event type cannot be given in this case.
+// Example:
+//
+//	Resource("bottle", func() {
+//		EmitEvent()
+//		Action("create", func() {
+//			Routing(POST(""))
+//			Payload(BottlePayload)
+//			Response(Created, BottleMedia)
+//		})
+//		Action("rate", func() {
+//			Routing(PUT("/:bottleID/rating"))
+//			EmitEvent("com.example.bottle.rated")
+//			Response(NoContent)
+//		})
+//	})
+func EmitEvent(eventType ...string) {
+	if len(eventType) > 1 {
+		dslengine.ReportError("too many arguments in call to EmitEvent")
+		return
+	}
+	e := &design.EventDefinition{}
+	if len(eventType) == 1 {
+		e.Type = eventType[0]
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		if def.Event != nil {
+			dslengine.ReportError("event already defined")
+			return
+		}
+		e.Parent = def
+		def.Event = e
+	case *design.ResourceDefinition:
+		if def.Event != nil {
+			dslengine.ReportError("event already defined")
+			return
+		}
+		e.Parent = def
+		def.Event = e
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
@@ -0,0 +1,117 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EmitEvent", func() {
+	var resourceDSL, actionDSL func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("shop", nil)
+		verb = POST
+		resourceDSL = func() {}
+		actionDSL = func() {
+			EmitEvent("com.example.bottle.created")
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			resourceDSL()
+			Action("create", func() {
+				Routing(verb(""))
+				actionDSL()
+				Response(NoContent)
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the action event", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		a := Design.Resources["bottle"].Actions["create"]
+		Ω(a.AllEvent()).ShouldNot(BeNil())
+		Ω(a.EventType()).Should(Equal("com.example.bottle.created"))
+	})
+
+	Context("with no event type", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				EmitEvent()
+			}
+		})
+
+		It("uses the default event type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			a := Design.Resources["bottle"].Actions["create"]
+			Ω(a.EventType()).Should(Equal("shop.bottle.create"))
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				EmitEvent()
+			}
+			actionDSL = func() {}
+		})
+
+		It("sets the event of the mutating actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["bottle"]
+			Ω(r.Actions["create"].AllEvent()).Should(Equal(r.Event))
+			Ω(r.Actions["show"].AllEvent()).Should(BeNil())
+		})
+	})
+
+	Context("in a resource with an event type", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				EmitEvent("com.example.bottle")
+			}
+			actionDSL = func() {}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("event type cannot be set in a resource"))
+		})
+	})
+
+	Context("with a GET route", func() {
+		BeforeEach(func() {
+			verb = GET
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("only actions with a POST, PUT, PATCH or DELETE route may emit events"))
+		})
+	})
+
+	Context("used twice", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				EmitEvent()
+				EmitEvent()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("event already defined"))
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Frozen prevents any change to the attributes of a type or media type compared to the schema
+// snapshot: the design validation fails if an attribute is added, removed, changes type or
+// becomes required or optional. Frozen may appear in Type or MediaType.
+//
+// The schema snapshot is generated with "goagen snapshot" and should be committed along with the
+// design. Delete and regenerate the snapshot to accept intentional changes. Example:
+//
+//    var Money = Type("Money", func() {
+//        Frozen()
+//        Attribute("amount", Integer)
+//        Attribute("currency", String)
+//    })
+//
+func Frozen() {
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		for _, ut := range design.Design.Types {
+			if ut.AttributeDefinition == def {
+				ut.Frozen = true
+				return
+			}
+		}
+		dslengine.IncompatibleDSL()
+	case *design.MediaTypeDefinition:
+		def.Frozen = true
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
+// AdditiveOnly makes the design validation reject the breaking changes made to the API types
+// compared to the schema snapshot: attribute removals, attribute type changes other than
+// widening (integer to number or any type to Any) and attributes becoming required. Adding
+// optional attributes and types remains possible. AdditiveOnly may only appear in API.
+func AdditiveOnly() {
+	if api, ok := apiDefinition(); ok {
+		api.AdditiveOnly = true
+	}
+}
+
+// SchemaSnapshot sets the path to the schema snapshot file used to validate the Frozen types and
+// the AdditiveOnly mode. Relative paths are relative to the directory where goagen runs. The
+// default is "goa.snapshot.json". SchemaSnapshot may only appear in API.
+func SchemaSnapshot(path string) {
+	if api, ok := apiDefinition(); ok {
+		api.Snapshot = path
+	}
+}
@@ -0,0 +1,149 @@
+package apidsl_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schema evolution", func() {
+	var snapshot string
+	var frozen, additive bool
+	var changes func()
+
+	define := func(attributes func()) {
+		API("evolving", func() {
+			SchemaSnapshot(snapshot)
+			if additive {
+				AdditiveOnly()
+			}
+		})
+		Type("Money", func() {
+			if frozen {
+				Frozen()
+			}
+			attributes()
+		})
+	}
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "snapshot")
+		Ω(err).ShouldNot(HaveOccurred())
+		snapshot = filepath.Join(dir, "goa.snapshot.json")
+		frozen, additive = false, false
+		changes = nil
+
+		dslengine.Reset()
+		define(func() {
+			Attribute("amount", Integer)
+			Attribute("currency", String)
+			Required("amount")
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		Ω(NewSnapshot(Design).Write(snapshot)).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Reset()
+		define(changes)
+		dslengine.Run()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(filepath.Dir(snapshot))
+	})
+
+	Context("with a frozen type", func() {
+		BeforeEach(func() {
+			frozen = true
+		})
+
+		Context("that did not change", func() {
+			BeforeEach(func() {
+				changes = func() {
+					Attribute("amount", Integer)
+					Attribute("currency", String)
+					Required("amount")
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an added attribute", func() {
+			BeforeEach(func() {
+				changes = func() {
+					Attribute("amount", Integer)
+					Attribute("currency", String)
+					Attribute("rate", Number)
+					Required("amount")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`frozen type Money adds attribute "rate"`))
+			})
+		})
+	})
+
+	Context("in additive-only mode", func() {
+		BeforeEach(func() {
+			additive = true
+		})
+
+		Context("with an added optional attribute and a widened type", func() {
+			BeforeEach(func() {
+				changes = func() {
+					Attribute("amount", Number)
+					Attribute("currency", String)
+					Attribute("rate", Number)
+					Required("amount")
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with breaking changes", func() {
+			BeforeEach(func() {
+				changes = func() {
+					Attribute("amount", String)
+					Required("amount")
+				}
+			})
+
+			It("produces errors", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`type Money removes attribute "currency"`))
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`type Money narrows the type of attribute "amount" from integer to string`))
+			})
+		})
+
+		Context("with a renamed attribute that lists the old name as alias", func() {
+			BeforeEach(func() {
+				changes = func() {
+					Attribute("value", Integer, func() {
+						Alias("amount")
+					})
+					Attribute("currency", String)
+					Required("value")
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+})
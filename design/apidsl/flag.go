@@ -0,0 +1,38 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Flag gates the action behind the feature flag with the given name so that unreleased endpoints
+// can ship dark. The generated controller looks up the flag in the provider registered with
+// feature.UseProvider and sends the NotFound response of the action when the flag is off,
+// making the endpoint indistinguishable from a missing one. The optional status selects the
+// response sent instead, it must be 404 or 403 and the action must declare the corresponding
+// response. Flag may only appear in Action. Example:
+//
+//	Action("checkout", func() {
+//		Routing(POST("/checkout"))
+//		Flag("new-checkout")
+//		Response(OK)
+//		Response(NotFound)
+//	})
+func Flag(name string, status ...int) {
+	if len(status) > 1 {
+		dslengine.ReportError("too many arguments in call to Flag")
+		return
+	}
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Flag != nil {
+		dslengine.ReportError("flag already defined")
+		return
+	}
+	a.Flag = &design.FlagDefinition{Name: name, Parent: a}
+	if len(status) == 1 {
+		a.Flag.Status = status[0]
+	}
+}
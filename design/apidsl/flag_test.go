@@ -0,0 +1,93 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flag", func() {
+	var actionDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("checkout", func() {
+			Action("create", func() {
+				Routing(POST("/checkout"))
+				Response(OK)
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with a NotFound response", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Flag("new-checkout")
+				Response(NotFound)
+				Response(Forbidden)
+			}
+		})
+
+		It("gates the action with the NotFound response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			f := Design.Resources["checkout"].Actions["create"].Flag
+			Ω(f).ShouldNot(BeNil())
+			Ω(f.Name).Should(Equal("new-checkout"))
+			Ω(f.Response()).ShouldNot(BeNil())
+			Ω(f.Response().Status).Should(Equal(404))
+		})
+	})
+
+	Context("with an explicit status", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Flag("new-checkout", 403)
+				Response(NotFound)
+				Response(Forbidden)
+			}
+		})
+
+		It("gates the action with the corresponding response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			f := Design.Resources["checkout"].Actions["create"].Flag
+			Ω(f.Response().Status).Should(Equal(403))
+		})
+	})
+
+	Context("with no NotFound or Forbidden response", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Flag("new-checkout")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("action must declare a NotFound or Forbidden response"))
+		})
+	})
+
+	Context("with an invalid status", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Flag("new-checkout", 400)
+				Response(BadRequest)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must be a 404 or a 403"))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Flags defines a set of named flags such as permissions. The first argument is the name of the
+// type, the others the flag names. Values are sent on the wire as arrays of flag names and the
+// generated code defines a bitmask Go type with one constant per flag as well as methods to set,
+// clear and test flags. Flags may only appear at the top level and the type may be used wherever
+// a type is expected. At most 64 flags may be defined. Example:
+//
+//	var Permissions = Flags("Permissions", "read", "write", "admin")
+//
+//	var _ = Type("Grant", func() {
+//		Attribute("permissions", Permissions)
+//	})
+//
+// The generated code for the example above defines the Permissions type with the PermissionsRead,
+// PermissionsWrite and PermissionsAdmin constants. The client methods accept the flag names of
+// parameters and headers as a []string. The built-in validations do not apply to flags.
+func Flags(name string, names ...string) *design.FlagsDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	for _, f := range design.Design.Flags {
+		if f.TypeName == name {
+			dslengine.ReportError("flags %#v is defined twice", name)
+			return nil
+		}
+	}
+	f := design.NewFlagsDefinition(name, camelize(name), names...)
+	design.Design.Flags = append(design.Design.Flags, f)
+	return f
+}
@@ -0,0 +1,60 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Flags", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("defines a flags type", func() {
+		f := Flags("permissions", "read", "write", "admin")
+		ut := Type("Grant", func() {
+			Attribute("permissions", f, func() {
+				Default([]interface{}{"read"})
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Flags).Should(ConsistOf(f))
+		Ω(f.Kind()).Should(Equal(ArrayKind))
+		Ω(f.ElemType.Type).Should(Equal(String))
+		Ω(f.ElemType.Validation.Values).Should(Equal([]interface{}{"read", "write", "admin"}))
+		Ω(f.GoType).Should(Equal("Permissions"))
+		Ω(ut.Type.ToObject()["permissions"].Type).Should(Equal(f))
+	})
+
+	It("rejects unknown flag names in defaults", func() {
+		f := Flags("Permissions", "read", "write")
+		Type("Grant", func() {
+			Attribute("permissions", f, func() {
+				Default([]interface{}{"delete"})
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+
+	It("rejects duplicate flag names", func() {
+		Flags("Permissions", "read", "read")
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+
+	It("rejects validations", func() {
+		f := Flags("Permissions", "read", "write")
+		Type("Grant", func() {
+			Attribute("permissions", f, func() {
+				MinLength(1)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+})
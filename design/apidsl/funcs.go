@@ -0,0 +1,204 @@
+// Code generated by gen_funcs.go, DO NOT EDIT.
+
+package apidsl
+
+import "github.com/goadesign/goa/dslengine"
+
+func init() {
+	dslengine.RegisterFuncs("github.com/goadesign/goa/design/apidsl", map[string]interface{}{
+		"API":                   API,
+		"APIKeySecurity":        APIKeySecurity,
+		"AbsentFields":          AbsentFields,
+		"AccessCodeFlow":        AccessCodeFlow,
+		"Action":                Action,
+		"AdditiveOnly":          AdditiveOnly,
+		"Alias":                 Alias,
+		"ApplicationFlow":       ApplicationFlow,
+		"ArrayOf":               ArrayOf,
+		"ArrayStyle":            ArrayStyle,
+		"Attribute":             Attribute,
+		"Attributes":            Attributes,
+		"Audit":                 Audit,
+		"AutoHead":              AutoHead,
+		"AutoOptions":           AutoOptions,
+		"BasePath":              BasePath,
+		"BasicAuthSecurity":     BasicAuthSecurity,
+		"CONNECT":               CONNECT,
+		"CRUD":                  CRUD,
+		"Cache":                 Cache,
+		"CanonicalActionName":   CanonicalActionName,
+		"Capture":               Capture,
+		"ClientCertRequired":    ClientCertRequired,
+		"CollectionOf":          CollectionOf,
+		"CompensatedBy":         CompensatedBy,
+		"Compress":              Compress,
+		"CompressTypes":         CompressTypes,
+		"Computed":              Computed,
+		"Const":                 Const,
+		"Consumes":              Consumes,
+		"Contact":               Contact,
+		"ContentType":           ContentType,
+		"ContextValue":          ContextValue,
+		"Cookie":                Cookie,
+		"Credentials":           Credentials,
+		"DELETE":                DELETE,
+		"Decompress":            Decompress,
+		"Default":               Default,
+		"DefaultFunc":           DefaultFunc,
+		"DefaultMedia":          DefaultMedia,
+		"Description":           Description,
+		"DisallowUnknownFields": DisallowUnknownFields,
+		"Docs":                  Docs,
+		"Downloadable":          Downloadable,
+		"DuplicateAttributes":   DuplicateAttributes,
+		"Email":                 Email,
+		"EmitAliases":           EmitAliases,
+		"EmitEvent":             EmitEvent,
+		"Encodings":             Encodings,
+		"Encrypted":             Encrypted,
+		"Enum":                  Enum,
+		"Envelope":              Envelope,
+		"Error":                 Error,
+		"Example":               Example,
+		"Exclude":               Exclude,
+		"Expose":                Expose,
+		"FilenameFrom":          FilenameFrom,
+		"Files":                 Files,
+		"Flag":                  Flag,
+		"Flags":                 Flags,
+		"Format":                Format,
+		"FormatWith":            FormatWith,
+		"Frozen":                Frozen,
+		"Function":              Function,
+		"GET":                   GET,
+		"GoType":                GoType,
+		"HEAD":                  HEAD,
+		"HashOf":                HashOf,
+		"Header":                Header,
+		"Headers":               Headers,
+		"Host":                  Host,
+		"IdentifierTemplate":    IdentifierTemplate,
+		"ImplicitFlow":          ImplicitFlow,
+		"Import":                Import,
+		"Inline":                Inline,
+		"Interceptor":           Interceptor,
+		"JSONRPC":               JSONRPC,
+		"JWTSecurity":           JWTSecurity,
+		"License":               License,
+		"Link":                  Link,
+		"Links":                 Links,
+		"LoadShed":              LoadShed,
+		"Location":              Location,
+		"MQTT":                  MQTT,
+		"Maintenance":           Maintenance,
+		"ManageAPIKeys":         ManageAPIKeys,
+		"MaxAge":                MaxAge,
+		"MaxArrayLength":        MaxArrayLength,
+		"MaxCapturedBody":       MaxCapturedBody,
+		"MaxConcurrent":         MaxConcurrent,
+		"MaxDecompressedSize":   MaxDecompressedSize,
+		"MaxDepth":              MaxDepth,
+		"MaxLength":             MaxLength,
+		"MaxMapSize":            MaxMapSize,
+		"MaxUploadSize":         MaxUploadSize,
+		"MaxWait":               MaxWait,
+		"Maximum":               Maximum,
+		"Media":                 Media,
+		"MediaType":             MediaType,
+		"Member":                Member,
+		"Metadata":              Metadata,
+		"MethodOverride":        MethodOverride,
+		"Methods":               Methods,
+		"MinLength":             MinLength,
+		"MinSize":               MinSize,
+		"MinVersion":            MinVersion,
+		"Minimum":               Minimum,
+		"Module":                Module,
+		"Name":                  Name,
+		"NoExample":             NoExample,
+		"NoSecurity":            NoSecurity,
+		"OAuth2Security":        OAuth2Security,
+		"OPTIONS":               OPTIONS,
+		"OptionalFields":        OptionalFields,
+		"OptionalPayload":       OptionalPayload,
+		"Origin":                Origin,
+		"Outbox":                Outbox,
+		"OverrideField":         OverrideField,
+		"OverrideHeader":        OverrideHeader,
+		"PATCH":                 PATCH,
+		"PII":                   PII,
+		"POST":                  POST,
+		"PUT":                   PUT,
+		"Package":               Package,
+		"Param":                 Param,
+		"Params":                Params,
+		"Parent":                Parent,
+		"ParseWith":             ParseWith,
+		"Partial":               Partial,
+		"PasswordFlow":          PasswordFlow,
+		"Pattern":               Pattern,
+		"Payload":               Payload,
+		"PayloadLimits":         PayloadLimits,
+		"PresignedUpload":       PresignedUpload,
+		"Produces":              Produces,
+		"Profile":               Profile,
+		"Protocol":              Protocol,
+		"QoS":                   QoS,
+		"Query":                 Query,
+		"ReadOnly":              ReadOnly,
+		"ReadsPayload":          ReadsPayload,
+		"ReadsResult":           ReadsResult,
+		"Redact":                Redact,
+		"Reference":             Reference,
+		"RequestID":             RequestID,
+		"Required":              Required,
+		"Resource":              Resource,
+		"Response":              Response,
+		"ResponseTemplate":      ResponseTemplate,
+		"ResultTopic":           ResultTopic,
+		"ResumableUpload":       ResumableUpload,
+		"Role":                  Role,
+		"Routing":               Routing,
+		"SWR":                   SWR,
+		"Scalar":                Scalar,
+		"Schedule":              Schedule,
+		"SchemaSnapshot":        SchemaSnapshot,
+		"Scheme":                Scheme,
+		"Scope":                 Scope,
+		"Security":              Security,
+		"Sensitive":             Sensitive,
+		"Server":                Server,
+		"SessionData":           SessionData,
+		"SessionEndpoints":      SessionEndpoints,
+		"SessionSecurity":       SessionSecurity,
+		"Status":                Status,
+		"Step":                  Step,
+		"Steps":                 Steps,
+		"StreamingPayload":      StreamingPayload,
+		"StringEncoded":         StringEncoded,
+		"TLS":                   TLS,
+		"TRACE":                 TRACE,
+		"Tenant":                Tenant,
+		"TermsOfService":        TermsOfService,
+		"TimeFormat":            TimeFormat,
+		"Title":                 Title,
+		"TokenURL":              TokenURL,
+		"Trailer":               Trailer,
+		"Trailers":              Trailers,
+		"Trait":                 Trait,
+		"Type":                  Type,
+		"TypeName":              TypeName,
+		"URL":                   URL,
+		"UseTrait":              UseTrait,
+		"ValidateWith":          ValidateWith,
+		"Var":                   Var,
+		"Variable":              Variable,
+		"VaryOn":                VaryOn,
+		"VendorPrefix":          VendorPrefix,
+		"Version":               Version,
+		"View":                  View,
+		"Workflow":              Workflow,
+		"WritesPayload":         WritesPayload,
+		"WritesResult":          WritesResult,
+	})
+}
@@ -0,0 +1,214 @@
+//go:build ignore
+// +build ignore
+
+// gen_funcs generates the registration of the apidsl DSL functions with the DSL engine and the
+// design/dsl package that re-exports them together with the design built-in types so that
+// designs may use the DSL without dot imports.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const apidslPath = "github.com/goadesign/goa/design/apidsl"
+
+// designTypes lists the design built-in data types re-exported by the dsl package.
+var designTypes = []string{"Boolean", "Integer", "Number", "String", "DateTime", "UUID", "Any"}
+
+// designAliases lists the design types aliased by the dsl package so that designs can declare
+// variables holding definitions.
+var designAliases = []string{
+	"APIDefinition",
+	"ActionDefinition",
+	"AttributeDefinition",
+	"MediaTypeDefinition",
+	"ResourceDefinition",
+	"UserTypeDefinition",
+}
+
+func main() {
+	funcs, consts, vars, err := exported(".")
+	if err != nil {
+		fail(err)
+	}
+	responses, err := responseNames(filepath.Join("..", "api.go"))
+	if err != nil {
+		fail(err)
+	}
+	data := map[string]interface{}{
+		"Funcs":     funcs,
+		"Consts":    consts,
+		"Vars":      vars,
+		"Types":     designTypes,
+		"Aliases":   designAliases,
+		"Responses": responses,
+		"Path":      apidslPath,
+	}
+	if err := write("funcs.go", funcsT, data); err != nil {
+		fail(err)
+	}
+	if err := os.MkdirAll(filepath.Join("..", "dsl"), 0755); err != nil {
+		fail(err)
+	}
+	if err := write(filepath.Join("..", "dsl", "dsl.go"), dslT, data); err != nil {
+		fail(err)
+	}
+}
+
+// exported returns the names of the exported functions, constants and variables declared in the
+// non test Go files of the given directory.
+func exported(dir string) (funcs, consts, vars []string, err error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != "gen_funcs.go" && fi.Name() != "funcs.go"
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pkg, ok := pkgs["apidsl"]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("package apidsl not found in %s", dir)
+	}
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.IsExported() {
+					funcs = append(funcs, d.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
+					if !ok {
+						continue
+					}
+					for _, n := range vs.Names {
+						if !n.IsExported() {
+							continue
+						}
+						if d.Tok == token.CONST {
+							consts = append(consts, n.Name)
+						} else {
+							vars = append(vars, n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(funcs)
+	sort.Strings(consts)
+	sort.Strings(vars)
+	return
+}
+
+// responseNames returns the names of the built-in responses declared in the given design
+// package file.
+func responseNames(path string) ([]string, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.CONST || d.Doc == nil || !strings.Contains(d.Doc.Text(), "built-in response names") {
+			continue
+		}
+		for _, spec := range d.Specs {
+			for _, n := range spec.(*ast.ValueSpec).Names {
+				names = append(names, n.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no built-in response names found in %s", path)
+	}
+	return names, nil
+}
+
+// write renders the given template to the file with the given path.
+func write(path, tmpl string, data map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := template.Must(template.New(path).Parse(tmpl)).Execute(&buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, src, 0644)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+const funcsT = `// Code generated by gen_funcs.go, DO NOT EDIT.
+
+package apidsl
+
+import "github.com/goadesign/goa/dslengine"
+
+func init() {
+	dslengine.RegisterFuncs({{ printf "%q" .Path }}, map[string]interface{}{
+{{ range .Funcs }}		{{ printf "%q" . }}: {{ . }},
+{{ end }}	})
+}
+`
+
+const dslT = `// Code generated by gen_funcs.go in package apidsl, DO NOT EDIT.
+
+package dsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+)
+
+// Built-in data types, see the design package.
+const (
+{{ range .Types }}	{{ . }} = design.{{ . }}
+{{ end }})
+
+// Built-in response names, see the design package.
+const (
+{{ range .Responses }}	{{ . }} = design.{{ . }}
+{{ end }})
+
+// ErrorMedia is the built-in media type for error responses.
+var ErrorMedia = design.ErrorMedia
+
+// Definition types, see the design package.
+type (
+{{ range .Aliases }}	{{ . }} = design.{{ . }}
+{{ end }})
+
+// Constants of the apidsl package.
+const (
+{{ range .Consts }}	{{ . }} = apidsl.{{ . }}
+{{ end }})
+
+// Variables of the apidsl package.
+var (
+{{ range .Vars }}	{{ . }} = apidsl.{{ . }}
+{{ end }})
+
+// DSL functions, see the apidsl package.
+var (
+{{ range .Funcs }}	{{ . }} = apidsl.{{ . }}
+{{ end }})
+`
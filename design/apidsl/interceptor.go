@@ -0,0 +1,130 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Interceptor defines a cross-cutting concern that runs around the actions it applies to, or
+// applies a previously defined interceptor to a resource or an action. The interceptor DSL lists
+// the payload and result attributes the interceptor reads and writes, the generated code exposes
+// them to the interceptor implementation via typed structs. Interceptors applied to a resource
+// apply to all its actions and run before the interceptors applied to the actions.
+//
+// Interceptor may appear at the top level, in API, Resource or Action. When used in a Resource or
+// Action the first argument may be the name of an interceptor defined elsewhere or the value
+// returned by Interceptor. Examples:
+//
+//    var AuditInterceptor = Interceptor("audit", func() {
+//        Description("Records who did what")
+//        ReadsPayload("user_id")
+//        WritesResult("audit_id")
+//    })
+//
+//    Action("create", func() {
+//        Interceptor(AuditInterceptor)
+//        Interceptor("trace", func() {
+//            WritesPayload("trace_id")
+//        })
+//    })
+//
+func Interceptor(name interface{}, dsl ...func()) *design.InterceptorDefinition {
+	var def *design.InterceptorDefinition
+	switch val := name.(type) {
+	case string:
+		for _, i := range design.Design.Interceptors {
+			if i.Name == val {
+				def = i
+				break
+			}
+		}
+		if def != nil && len(dsl) != 0 {
+			dslengine.ReportError("cannot redefine interceptor with name %q", val)
+			return nil
+		}
+	case *design.InterceptorDefinition:
+		if len(dsl) != 0 {
+			dslengine.ReportError("cannot redefine interceptor %q", val.Name)
+			return nil
+		}
+		def = val
+	default:
+		dslengine.ReportError("invalid value for 'name' parameter, specify a string or a *InterceptorDefinition")
+		return nil
+	}
+
+	switch parent := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *dslengine.TopLevelDefinition:
+		if def != nil {
+			dslengine.ReportError("cannot redefine interceptor with name %q", def.Name)
+			return nil
+		}
+		def = &design.InterceptorDefinition{Name: name.(string)}
+		if len(dsl) != 0 {
+			def.DSLFunc = dsl[0]
+		}
+		design.Design.Interceptors = append(design.Design.Interceptors, def)
+	case *design.ResourceDefinition:
+		if def = useInterceptor(name, def, dsl); def != nil {
+			parent.Interceptors = append(parent.Interceptors, def)
+		}
+	case *design.ActionDefinition:
+		if def = useInterceptor(name, def, dsl); def != nil {
+			parent.Interceptors = append(parent.Interceptors, def)
+		}
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+
+	return def
+}
+
+// ReadsPayload lists the names of the payload attributes read by the interceptor.
+func ReadsPayload(attrs ...string) {
+	if i, ok := interceptorDefinition(); ok {
+		i.ReadPayload = append(i.ReadPayload, attrs...)
+	}
+}
+
+// WritesPayload lists the names of the payload attributes written by the interceptor. The
+// interceptor writes the payload attributes before the action handler runs.
+func WritesPayload(attrs ...string) {
+	if i, ok := interceptorDefinition(); ok {
+		i.WritePayload = append(i.WritePayload, attrs...)
+	}
+}
+
+// ReadsResult lists the names of the result attributes read by the interceptor. The result
+// attributes are defined by the media types of the action successful responses.
+func ReadsResult(attrs ...string) {
+	if i, ok := interceptorDefinition(); ok {
+		i.ReadResult = append(i.ReadResult, attrs...)
+	}
+}
+
+// WritesResult lists the names of the result attributes written by the interceptor. The result
+// attributes are set on the response media type when the action sends it.
+func WritesResult(attrs ...string) {
+	if i, ok := interceptorDefinition(); ok {
+		i.WriteResult = append(i.WriteResult, attrs...)
+	}
+}
+
+// useInterceptor returns the interceptor applied to a resource or an action. It defines the
+// interceptor inline if a DSL is given and no interceptor with the same name exists.
+func useInterceptor(name interface{}, def *design.InterceptorDefinition, dsl []func()) *design.InterceptorDefinition {
+	if def != nil {
+		return def
+	}
+	if len(dsl) == 0 {
+		dslengine.ReportError("interceptor %q not found", name)
+		return nil
+	}
+	def = &design.InterceptorDefinition{Name: name.(string)}
+	if !dslengine.Execute(dsl[0], def) {
+		return nil
+	}
+	design.Design.Interceptors = append(design.Design.Interceptors, def)
+	return def
+}
@@ -0,0 +1,100 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Interceptor", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with interceptors applied to a resource and an action", func() {
+		BeforeEach(func() {
+			API("intercepted", func() {
+				Interceptor("audit", func() {
+					Description("Records who did what")
+					ReadsPayload("user_id")
+					WritesResult("audit_id")
+				})
+			})
+			MediaType("application/vnd.audited", func() {
+				Attributes(func() {
+					Attribute("audit_id", String)
+				})
+				View("default", func() {
+					Attribute("audit_id")
+				})
+			})
+			Resource("res", func() {
+				Interceptor("audit")
+				Action("create", func() {
+					Routing(POST("/"))
+					Payload(func() {
+						Attribute("user_id", String)
+						Attribute("trace_id", String)
+					})
+					Interceptor("trace", func() {
+						WritesPayload("trace_id")
+					})
+					Response(OK, "application/vnd.audited")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("defines the interceptors and applies them in order", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Interceptors).Should(HaveLen(2))
+			audit := Design.Interceptors[0]
+			Ω(audit.Description).Should(Equal("Records who did what"))
+			Ω(audit.ReadPayload).Should(Equal([]string{"user_id"}))
+			Ω(audit.WriteResult).Should(Equal([]string{"audit_id"}))
+			action := Design.Resources["res"].Actions["create"]
+			all := action.AllInterceptors()
+			Ω(all).Should(HaveLen(2))
+			Ω(all[0]).Should(Equal(audit))
+			Ω(all[1].Name).Should(Equal("trace"))
+			Ω(all[1].WritePayload).Should(Equal([]string{"trace_id"}))
+		})
+	})
+
+	Context("with an unknown interceptor", func() {
+		BeforeEach(func() {
+			API("intercepted", nil)
+			Resource("res", func() {
+				Interceptor("unknown")
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an interceptor accessing an undefined attribute", func() {
+		BeforeEach(func() {
+			API("intercepted", nil)
+			Resource("res", func() {
+				Action("show", func() {
+					Routing(GET("/"))
+					Interceptor("audit", func() {
+						ReadsPayload("user_id")
+					})
+					Response(NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`payload attribute "user_id" accessed by interceptor "audit" is not defined`))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// JSONRPC exposes the API actions over a single JSON-RPC 2.0 (https://www.jsonrpc.org/specification)
+// endpoint mounted on the given path with the POST method. The method of a call is the name of the
+// action prefixed with the name of its resource, e.g. "bottle.show". The call params must be an
+// object: the members named after the action path and querystring parameters set these parameters
+// and the other members make up the request payload. The generated code dispatches each call to the
+// action through the service mux so that the action middleware runs as for HTTP requests. Batch
+// calls and notifications are supported.
+//
+// The responses with a status code of 400 or more produce JSON-RPC errors. The errors declared with
+// Error map to the server error codes starting at -32001, the other errors map to -32602 (invalid
+// params) for 400 responses, -32603 (internal error) for 5xx responses and -32000 otherwise.
+//
+// JSONRPC may only appear in API. Example:
+//
+//	API("cellar", func() {
+//		JSONRPC("/rpc")
+//	})
+func JSONRPC(path string) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if api.JSONRPC != nil {
+		dslengine.ReportError("JSON-RPC endpoint already defined")
+		return
+	}
+	api.JSONRPC = &design.JSONRPCDefinition{Path: path}
+}
@@ -0,0 +1,68 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSONRPC", func() {
+	var path string
+	var route string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		path = "/rpc"
+		route = "/bottles"
+	})
+
+	JustBeforeEach(func() {
+		API("cellar", func() {
+			JSONRPC(path)
+			Error("not_found", NotFound)
+		})
+		Resource("bottle", func() {
+			Error("not_found", NotFound)
+			Error("sold_out", Conflict)
+			Action("create", func() {
+				Routing(POST(route))
+				Response(NotFound)
+				Response(Conflict)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("defines the JSON-RPC endpoint", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.JSONRPC).ShouldNot(BeNil())
+		Ω(Design.JSONRPC.Path).Should(Equal("/rpc"))
+	})
+
+	It("lists the declared errors without duplicates", func() {
+		Ω(Design.JSONRPC.ErrorNames()).Should(Equal([]string{"not_found", "sold_out"}))
+	})
+
+	Context("with a relative path", func() {
+		BeforeEach(func() {
+			path = "rpc"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a path used by an action", func() {
+		BeforeEach(func() {
+			route = "/rpc"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("already used"))
+		})
+	})
+})
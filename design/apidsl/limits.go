@@ -0,0 +1,71 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// PayloadLimits caps the complexity of the request bodies: the nesting depth of their arrays and
+// objects, the number of elements of the arrays and the number of keys of the objects. The
+// generated code scans JSON request bodies before decoding them and rejects those that exceed
+// the limits with a 400 response so that no memory is allocated for the payloads of malicious
+// requests. PayloadLimits may appear in API to apply to all the actions or in Resource to override
+// the API limits for the resource actions.
+//
+// The DSL uses MaxDepth, MaxArrayLength and MaxMapSize to set the limits, a limit that is not set
+// does not apply. Example:
+//
+//	API("cellar", func() {
+//		PayloadLimits(func() {
+//			MaxDepth(10)
+//			MaxArrayLength(1000)
+//			MaxMapSize(100)
+//		})
+//	})
+func PayloadLimits(dsl func()) {
+	l := &design.LimitsDefinition{}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if def.Limits != nil {
+			dslengine.ReportError("payload limits already defined")
+			return
+		}
+		l.Parent = def
+		def.Limits = l
+	case *design.ResourceDefinition:
+		if def.Limits != nil {
+			dslengine.ReportError("payload limits already defined")
+			return
+		}
+		l.Parent = def
+		def.Limits = l
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	dslengine.Execute(dsl, l)
+}
+
+// MaxDepth sets the maximum nesting depth of the arrays and objects of the request bodies, see
+// PayloadLimits. MaxDepth may only appear in PayloadLimits.
+func MaxDepth(depth int) {
+	if l, ok := limitsDefinition(); ok {
+		l.MaxDepth = depth
+	}
+}
+
+// MaxArrayLength sets the maximum number of elements of the arrays of the request bodies, see
+// PayloadLimits. MaxArrayLength may only appear in PayloadLimits.
+func MaxArrayLength(length int) {
+	if l, ok := limitsDefinition(); ok {
+		l.MaxArrayLength = length
+	}
+}
+
+// MaxMapSize sets the maximum number of keys of the objects of the request bodies, see
+// PayloadLimits. MaxMapSize may only appear in PayloadLimits.
+func MaxMapSize(size int) {
+	if l, ok := limitsDefinition(); ok {
+		l.MaxMapSize = size
+	}
+}
@@ -0,0 +1,80 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PayloadLimits", func() {
+	var apiLimits, resourceLimits func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiLimits = func() {
+			MaxDepth(10)
+			MaxArrayLength(1000)
+		}
+		resourceLimits = nil
+	})
+
+	JustBeforeEach(func() {
+		API("limited", func() {
+			PayloadLimits(apiLimits)
+		})
+		Resource("bottle", func() {
+			if resourceLimits != nil {
+				PayloadLimits(resourceLimits)
+			}
+		})
+		dslengine.Run()
+	})
+
+	It("sets the API limits", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Resources["bottle"].AllLimits()).Should(Equal(Design.Limits))
+		Ω(Design.Limits.MaxDepth).Should(Equal(10))
+		Ω(Design.Limits.MaxArrayLength).Should(Equal(1000))
+		Ω(Design.Limits.MaxMapSize).Should(Equal(0))
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resourceLimits = func() {
+				MaxMapSize(50)
+			}
+		})
+
+		It("overrides the API limits", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].AllLimits().MaxMapSize).Should(Equal(50))
+			Ω(Design.Resources["bottle"].AllLimits().MaxDepth).Should(Equal(0))
+		})
+	})
+
+	Context("with no limit", func() {
+		BeforeEach(func() {
+			apiLimits = func() {}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must set at least one of"))
+		})
+	})
+
+	Context("with a negative limit", func() {
+		BeforeEach(func() {
+			apiLimits = func() {
+				MaxDepth(-1)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("maximum nesting depth cannot be negative"))
+		})
+	})
+})
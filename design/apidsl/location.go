@@ -0,0 +1,33 @@
+package apidsl
+
+import "github.com/goadesign/goa/design"
+
+// Location sets the Location header of a Created or redirect response. The value is either the name
+// of a result attribute whose value is copied to the header or an href template whose
+// "{attribute}" placeholders are replaced with the values of the corresponding result attributes.
+// The generated response helpers set the header automatically, Location also adds the header to
+// the response headers so that it gets documented.
+//
+// Location must appear in Response or ResponseTemplate. Examples:
+//
+//	Response(Created, func() {
+//		Media(BottleMedia)
+//		Location("href")
+//	})
+//
+//	Response(Created, func() {
+//		Media(BottleMedia)
+//		Location("/accounts/{account_id}/bottles/{id}")
+//	})
+func Location(value string) {
+	if r, ok := responseDefinition(); ok {
+		r.LocationHeader = value
+		if r.Headers == nil {
+			r.Headers = &design.AttributeDefinition{Type: design.Object{}}
+		}
+		r.Headers.Type.ToObject()["Location"] = &design.AttributeDefinition{
+			Type:        design.String,
+			Description: "URL of the created or target resource",
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Maintenance declares the response sent to requests made to endpoints in maintenance mode. The
+// generated controllers check the service maintenance switch (goa.Service.Maintenance) before
+// handling each request so that operators can put individual resources, actions or the whole API
+// in maintenance without redeploying. The maintenance response is also documented for all the
+// actions in the generated swagger specification.
+//
+// The response status defaults to 503 and the response has no body by default. The optional DSL
+// may use Status, Description and Media to override the status, describe the response and render
+// the standard error media type in the response body. Maintenance must appear in API. Example:
+//
+//	API("cellar", func() {
+//		Maintenance(func() {
+//			Description("The cellar is being restocked")
+//			Media(ErrorMedia)
+//		})
+//	})
+func Maintenance(dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to Maintenance")
+		return
+	}
+	a, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if a.Maintenance != nil {
+		dslengine.ReportError("maintenance response already defined")
+		return
+	}
+	resp := &design.ResponseDefinition{
+		Name:        "Maintenance",
+		Status:      503,
+		Description: "The service is under maintenance",
+		Parent:      a,
+	}
+	if len(dsl) == 1 && !dslengine.Execute(dsl[0], resp) {
+		return
+	}
+	a.Maintenance = resp
+}
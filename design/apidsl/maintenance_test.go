@@ -0,0 +1,85 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Maintenance", func() {
+	var apiDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiDSL)
+		dslengine.Run()
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			apiDSL = func() { Maintenance() }
+		})
+
+		It("defines an empty 503 response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Maintenance).ShouldNot(BeNil())
+			Ω(Design.Maintenance.Status).Should(Equal(503))
+			Ω(Design.Maintenance.MediaType).Should(BeEmpty())
+		})
+	})
+
+	Context("with the error media type", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Maintenance(func() {
+					Status(502)
+					Description("Restocking")
+					Media(ErrorMedia)
+				})
+			}
+		})
+
+		It("sets the response status and media type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Maintenance.Status).Should(Equal(502))
+			Ω(Design.Maintenance.Description).Should(Equal("Restocking"))
+			Ω(Design.Maintenance.MediaType).Should(Equal(ErrorMediaIdentifier))
+			Ω(Design.MediaTypes).Should(HaveKey(CanonicalIdentifier(ErrorMediaIdentifier)))
+		})
+	})
+
+	Context("with another media type", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Maintenance(func() {
+					Media("text/html")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("maintenance response media type must be the error media type"))
+		})
+	})
+
+	Context("defined twice", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Maintenance()
+				Maintenance()
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("maintenance response already defined"))
+		})
+	})
+})
@@ -4,20 +4,20 @@ import (
 	"fmt"
 	"mime"
 	"strings"
+	"unicode"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Counter used to create unique media type names for identifier-less media types.
-var mediaTypeCount int
-
 // MediaType implements the media type definition DSL. A media type definition describes the
 // representation of a resource used in a response body.
 //
 // Media types are defined with a unique identifier as defined by RFC6838. The identifier also
 // defines the default value for the Content-Type header of responses. The ContentType DSL allows
-// overridding the default as shown in the example below.
+// overridding the default as shown in the example below. Media types may also be declared with a
+// name such as "bottle" when the API defines an identifier template or a vendor prefix, the
+// identifier is then built from the template, see IdentifierTemplate.
 //
 // The media type definition includes a listing of all the potential attributes that can appear in
 // the body. Views specify which of the attributes are actually rendered so that the same media type
@@ -83,11 +83,29 @@ func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
 	}
 	canonicalID := design.CanonicalIdentifier(identifier)
 	// Validate that media type identifier doesn't clash
-	if _, ok := design.Design.MediaTypes[canonicalID]; ok {
-		dslengine.ReportError("media type %#v with canonical identifier %#v is defined twice", identifier, canonicalID)
+	if mt, ok := design.Design.MediaTypes[canonicalID]; ok {
+		dslengine.ReportError("media type %#v with canonical identifier %#v is defined twice%s",
+			identifier, canonicalID, conflict(mt.Module))
 		return nil
 	}
 	identifier = mime.FormatMediaType(identifier, params)
+	typeName := importedName(mediaTypeName(identifier))
+	// Now save the type in the API media types map
+	mt := design.NewMediaTypeDefinition(typeName, identifier, apidsl)
+	mt.Location = dslengine.CallerLocation()
+	mt.Module = importedModule()
+	design.Design.MediaTypes[canonicalID] = mt
+	return mt
+}
+
+// mediaTypeName returns the default type name of the media type with the given identifier. The
+// name is built from the last part of the identifier stripped of its "vnd." prefix and suffix, e.g.
+// "Bottle" for "application/vnd.goa.bottle+json". Identifiers whose last part is empty produce
+// names built from all the identifier parts so that the names only depend on the identifiers.
+func mediaTypeName(identifier string) string {
+	if i := strings.Index(identifier, ";"); i != -1 {
+		identifier = identifier[:i]
+	}
 	lastPart := identifier
 	lastPartIndex := strings.LastIndex(identifier, "/")
 	if lastPartIndex > -1 {
@@ -102,15 +120,16 @@ func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
 	for i, e := range elems {
 		elems[i] = strings.Title(e)
 	}
-	typeName := strings.Join(elems, "")
-	if typeName == "" {
-		mediaTypeCount++
-		typeName = fmt.Sprintf("MediaType%d", mediaTypeCount)
+	if name := strings.Join(elems, ""); name != "" {
+		return name
 	}
-	// Now save the type in the API media types map
-	mt := design.NewMediaTypeDefinition(typeName, identifier, apidsl)
-	design.Design.MediaTypes[canonicalID] = mt
-	return mt
+	elems = strings.FieldsFunc(identifier, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, e := range elems {
+		elems[i] = strings.Title(e)
+	}
+	return strings.Join(elems, "") + "Media"
 }
 
 // Media sets a response media type by name or by reference using a value returned by MediaType:
@@ -137,7 +156,7 @@ func Media(val interface{}, viewName ...string) {
 				r.MediaType = m.Identifier
 			}
 		} else if identifier, ok := val.(string); ok {
-			r.MediaType = identifier
+			r.MediaType = design.Design.ExpandIdentifier(identifier)
 		} else {
 			dslengine.ReportError("media type must be a string or a pointer to MediaTypeDefinition, got %#v", val)
 		}
@@ -217,7 +236,8 @@ func ContentType(typ string) {
 // rendered when the view is used to produce a response. The attribute names must appear in the
 // media type definition. If an attribute is itself a media type then the view may specify which
 // view to use when rendering the attribute using the View function in the View apidsl. If not
-// specified then the view named "default" is used. Examples:
+// specified then the view named "default" is used. Views may also define computed attributes
+// whose values are supplied when the view is rendered, see Computed. Examples:
 //
 //	View("default", func() {
 //		Attribute("id")		// "id" and "name" must be media type attributes
@@ -296,6 +316,15 @@ func buildView(name string, mt *design.MediaTypeDefinition, at *design.Attribute
 			mto = mt.Type.ToArray().ElemType.Type.ToObject()
 		}
 		for n, cat := range o {
+			if cat.IsComputed() {
+				if _, ok := mto[n]; ok {
+					return nil, fmt.Errorf("computed attribute %#v is also a media type attribute", n)
+				}
+				if mt.Type.IsArray() {
+					return nil, fmt.Errorf("computed attribute %#v must be defined in a view of the collection element media type", n)
+				}
+				continue
+			}
 			if existing, ok := mto[n]; ok {
 				dup := design.DupAtt(existing)
 				dup.View = cat.View
@@ -360,21 +389,56 @@ func Link(name string, view ...string) {
 // actions. This function can be called from any place where a media type can be used.
 // The resulting media type identifier is built from the element media type by appending the media
 // type parameter "type" with value "collection".
+//
+// The elements may also be user types or primitives (given as values or by name), in which case
+// the element media type identifier is synthesized from the type name, e.g.
+// "application/vnd.account+json" for a user type named "Account", and the collection only defines
+// the default view which renders the elements as is:
+//
+//	CollectionOf(Account)	// AccountCollection, application/vnd.account+json; type=collection
+//	CollectionOf(String)	// StringCollection, application/vnd.string+json; type=collection
+//
+// Collections are rendered as JSON arrays unless the DSL uses Envelope, in which case they are
+// rendered as an object that holds the elements in its "items" attribute together with the
+// envelope attributes:
+//
+//	CollectionOf(Bottle, func() {
+//		Envelope(func() {
+//			Attribute("total", Integer, "Total number of bottles")
+//			Attribute("next_cursor", String, "Cursor of next page if any")
+//			Required("total")
+//		})
+//	})
 func CollectionOf(v interface{}, apidsl ...func()) *design.MediaTypeDefinition {
-	var m *design.MediaTypeDefinition
-	var ok bool
-	m, ok = v.(*design.MediaTypeDefinition)
-	if !ok {
-		if id, ok := v.(string); ok {
-			m = design.Design.MediaTypes[design.CanonicalIdentifier(id)]
+	var elem design.DataType
+	switch actual := v.(type) {
+	case *design.MediaTypeDefinition:
+		elem = actual
+	case *design.UserTypeDefinition, design.Primitive, *design.ScalarDefinition:
+		elem = actual.(design.DataType)
+	case string:
+		if m := design.Design.MediaTypes[design.CanonicalIdentifier(actual)]; m != nil {
+			elem = m
+		} else if ut := design.Design.Types[actual]; ut != nil {
+			elem = ut
 		}
 	}
-	if m == nil {
-		dslengine.ReportError("invalid CollectionOf argument: not a media type and not a known media type identifier")
+	if elem == nil {
+		dslengine.ReportError("invalid CollectionOf argument: not a media type, user type or primitive and not a known media type identifier or type name")
 		// don't return nil to avoid panics, the error will get reported at the end
 		return design.NewMediaTypeDefinition("InvalidCollection", "text/plain", nil)
 	}
-	id := m.Identifier
+	if s, ok := elem.(*design.ScalarDefinition); ok && s.TypeName == "" {
+		dslengine.ReportError("invalid CollectionOf argument: scalar must be named")
+		return design.NewMediaTypeDefinition("InvalidCollection", "text/plain", nil)
+	}
+	m, isMediaType := elem.(*design.MediaTypeDefinition)
+	var id string
+	if isMediaType {
+		id = m.Identifier
+	} else {
+		id = "application/vnd." + strings.ToLower(collectionElemName(elem)) + "+json"
+	}
 	mediatype, params, err := mime.ParseMediaType(id)
 	if err != nil {
 		dslengine.ReportError("invalid media type identifier %#v: %s", id, err)
@@ -401,12 +465,21 @@ func CollectionOf(v interface{}, apidsl ...func()) *design.MediaTypeDefinition {
 		if mt, ok := mediaTypeDefinition(); ok {
 			// Cannot compute collection type name before element media type DSL has executed
 			// since the DSL may modify element type name via the TypeName function.
-			mt.TypeName = m.TypeName + "Collection"
-			mt.AttributeDefinition = &design.AttributeDefinition{Type: ArrayOf(m)}
+			mt.TypeName = collectionElemName(elem) + "Collection"
+			mt.AttributeDefinition = &design.AttributeDefinition{Type: ArrayOf(elem)}
 			if len(apidsl) > 0 {
 				dslengine.Execute(apidsl[0], mt)
 			}
-			if mt.Views == nil {
+			if !isMediaType {
+				if len(mt.Views) > 0 {
+					dslengine.ReportError("collections of user types and primitives cannot define views")
+				}
+				mt.Views = map[string]*design.ViewDefinition{design.DefaultView: {
+					AttributeDefinition: &design.AttributeDefinition{Type: mt.Type},
+					Name:                design.DefaultView,
+					Parent:              mt,
+				}}
+			} else if mt.Views == nil {
 				// If the apidsl didn't create any views (or there is no apidsl at all)
 				// then inherit the views from the collection element.
 				mt.Views = make(map[string]*design.ViewDefinition)
@@ -414,6 +487,9 @@ func CollectionOf(v interface{}, apidsl ...func()) *design.MediaTypeDefinition {
 					mt.Views[n] = v
 				}
 			}
+			if mt.Envelope != nil {
+				buildEnvelope(mt, elem)
+			}
 		}
 	})
 	// Do not execute the apidsl right away, will be done last to make sure the element apidsl has run
@@ -421,3 +497,100 @@ func CollectionOf(v interface{}, apidsl ...func()) *design.MediaTypeDefinition {
 	design.GeneratedMediaTypes[canonical] = mt
 	return mt
 }
+
+// Envelope wraps the elements of a collection media type in an envelope object. The envelope holds
+// the elements in its "items" attribute and the attributes defined by the DSL, e.g. the total
+// number of elements and the cursor of the next page. The envelope attributes are rendered by all
+// the views of the collection, the items using the corresponding views of the element media type.
+//
+// Envelope must appear in the CollectionOf DSL, see CollectionOf for an example.
+func Envelope(apidsl ...func()) {
+	mt, ok := mediaTypeDefinition()
+	if !ok {
+		return
+	}
+	if mt.Type == nil || !mt.Type.IsArray() {
+		dslengine.ReportError("Envelope must appear in the CollectionOf DSL")
+		return
+	}
+	if mt.Envelope != nil {
+		dslengine.ReportError("multiple envelope definitions for collection %#v", mt.TypeName)
+		return
+	}
+	env := &design.AttributeDefinition{Type: make(design.Object)}
+	if len(apidsl) > 0 {
+		if !dslengine.Execute(apidsl[0], env) {
+			return
+		}
+	}
+	if _, ok := env.Type.ToObject()[design.EnvelopeItems]; ok {
+		dslengine.ReportError("envelope cannot define attribute %#v which holds the collection elements", design.EnvelopeItems)
+		return
+	}
+	mt.Envelope = env
+}
+
+// buildEnvelope turns the collection media type into the envelope object holding the collection
+// elements in its "items" attribute. The envelope defines the same views as the collection.
+func buildEnvelope(mt *design.MediaTypeDefinition, elem design.DataType) {
+	items := &design.AttributeDefinition{
+		Type:        &design.Array{ElemType: &design.AttributeDefinition{Type: elem}},
+		Description: "Collection elements",
+	}
+	required := []string{design.EnvelopeItems}
+	if mt.Envelope.Validation != nil {
+		required = append(required, mt.Envelope.Validation.Required...)
+	}
+	obj := design.Object{design.EnvelopeItems: items}
+	for n, att := range mt.Envelope.Type.ToObject() {
+		obj[n] = att
+	}
+	mt.AttributeDefinition = &design.AttributeDefinition{
+		Type:        obj,
+		Description: mt.Description,
+		Validation:  &dslengine.ValidationDefinition{Required: required},
+	}
+	views := make(map[string]*design.ViewDefinition, len(mt.Views))
+	for n := range mt.Views {
+		vobj := make(design.Object, len(obj))
+		for an, att := range obj {
+			vobj[an] = design.DupAtt(att)
+		}
+		views[n] = &design.ViewDefinition{
+			AttributeDefinition: &design.AttributeDefinition{Type: vobj},
+			Name:                n,
+			Parent:              mt,
+		}
+	}
+	mt.Views = views
+}
+
+// collectionElemName returns the name of the collection element type used to build the names of
+// the collection type and of the synthesized element media type identifier.
+func collectionElemName(elem design.DataType) string {
+	switch actual := elem.(type) {
+	case *design.MediaTypeDefinition:
+		return actual.TypeName
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.ScalarDefinition:
+		return actual.TypeName
+	case design.Primitive:
+		switch actual {
+		case design.Boolean:
+			return "Boolean"
+		case design.Integer:
+			return "Integer"
+		case design.Number:
+			return "Number"
+		case design.String:
+			return "String"
+		case design.DateTime:
+			return "DateTime"
+		case design.UUID:
+			return "UUID"
+		}
+		return "Any"
+	}
+	return elem.Name()
+}
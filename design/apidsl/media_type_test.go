@@ -67,6 +67,29 @@ var _ = Describe("MediaType", func() {
 		})
 	})
 
+	Context("with an identifier that does not define a name", func() {
+		BeforeEach(func() {
+			name = "application/vnd.+json"
+			MediaType("application/foo", nil)
+		})
+
+		It("derives the type name from the identifier", func() {
+			Ω(mt).ShouldNot(BeNil())
+			Ω(mt.TypeName).Should(Equal("ApplicationVndJsonMedia"))
+		})
+	})
+
+	Context("with an identifier defining parameters", func() {
+		BeforeEach(func() {
+			name = "application/vnd.goa.bottle; version=1"
+		})
+
+		It("derives the type name from the identifier sans parameters", func() {
+			Ω(mt).ShouldNot(BeNil())
+			Ω(mt.TypeName).Should(Equal("GoaBottle"))
+		})
+	})
+
 	Context("with a content type", func() {
 		const attName = "att"
 		const contentType = "application/json"
@@ -327,6 +350,130 @@ var _ = Describe("CollectionOf", func() {
 			Ω(et.Type.(*MediaTypeDefinition).Identifier).Should(Equal("application/vnd.example+json"))
 		})
 	})
+
+	Context("with an envelope", func() {
+		var col *MediaTypeDefinition
+		BeforeEach(func() {
+			dslengine.Reset()
+			mt := MediaType("application/vnd.example", func() {
+				Attribute("id")
+				Attribute("name")
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			col = CollectionOf(mt, func() {
+				Envelope(func() {
+					Attribute("total", Integer)
+					Attribute("next_cursor", String)
+					Required("total")
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("produces an envelope object", func() {
+			Ω(col.IsEnvelope()).Should(BeTrue())
+			Ω(col.Type.IsObject()).Should(BeTrue())
+			Ω(col.Type.ToObject()).Should(HaveKey("items"))
+			Ω(col.Type.ToObject()).Should(HaveKey("total"))
+			Ω(col.Type.ToObject()).Should(HaveKey("next_cursor"))
+			Ω(col.AllRequired()).Should(ConsistOf("items", "total"))
+			Ω(col.EnvelopeElem().Identifier).Should(Equal("application/vnd.example"))
+			Ω(col.Views).Should(HaveKey("default"))
+			Ω(col.Views).Should(HaveKey("tiny"))
+		})
+
+		It("projects the items using the envelope view", func() {
+			p, _, err := col.Project("tiny")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(p.IsEnvelope()).Should(BeTrue())
+			elem := p.EnvelopeElem()
+			Ω(elem).ShouldNot(BeNil())
+			Ω(elem.TypeName).Should(Equal("ExampleTiny"))
+			Ω(p.Type.ToObject()).Should(HaveKey("total"))
+		})
+	})
+
+	Context("of a user type", func() {
+		var col *MediaTypeDefinition
+		BeforeEach(func() {
+			dslengine.Reset()
+			Type("Account", func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+			})
+			col = CollectionOf("Account")
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("synthesizes the collection media type", func() {
+			Ω(col.TypeName).Should(Equal("AccountCollection"))
+			Ω(col.Identifier).Should(Equal("application/vnd.account+json; type=collection"))
+			Ω(col.Type.ToArray().ElemType.Type).Should(Equal(Design.Types["Account"]))
+			Ω(col.Views).Should(HaveKey("default"))
+		})
+
+		It("projects the default view", func() {
+			p, _, err := col.Project("default")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(p.TypeName).Should(Equal("AccountCollection"))
+			Ω(p.Type.ToArray().ElemType.Type).Should(Equal(Design.Types["Account"]))
+			_, _, err = col.Project("tiny")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("of a primitive", func() {
+		var col *MediaTypeDefinition
+		BeforeEach(func() {
+			dslengine.Reset()
+			col = CollectionOf(DateTime)
+		})
+
+		It("synthesizes the collection media type", func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(col.TypeName).Should(Equal("DateTimeCollection"))
+			Ω(col.Identifier).Should(Equal("application/vnd.datetime+json; type=collection"))
+			Ω(col.Type.ToArray().ElemType.Type).Should(Equal(DateTime))
+		})
+	})
+
+	Context("with an envelope defining items", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			mt := MediaType("application/vnd.example", func() {
+				Attribute("id")
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			CollectionOf(mt, func() {
+				Envelope(func() {
+					Attribute("items", Integer)
+				})
+			})
+		})
+
+		It("reports an error", func() {
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`envelope cannot define attribute "items"`))
+		})
+	})
 })
 
 var _ = Describe("Example", func() {
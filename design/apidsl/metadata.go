@@ -39,6 +39,19 @@ import (
 //
 //        Metadata("swagger:summary", "Short summary of what action does")
 //
+// `openapi:extension:x-xxx` (or `swagger:extension:x-xxx`): sets the vendor extension x-xxx in the
+// generated Swagger specification. Values that are valid JSON are decoded, other values are used as
+// strings. Applicable to the API, actions, media types and attributes (schemas and parameters).
+//
+//        Metadata("openapi:extension:x-amazon-apigateway-integration", `{"type":"http_proxy"}`)
+//
+// `gateway:rate-limit`: sets the rate limit enforced by the API gateway configurations generated by
+// the "gateway" command. The value has the form "<requests>/<period>" where period is one of
+// "second", "minute", "hour" or "day".
+// Applicable to the API, resources and actions.
+//
+//        Metadata("gateway:rate-limit", "100/minute")
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
@@ -0,0 +1,66 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// MethodOverride enables HTTP method tunneling so that clients behind proxies that only allow
+// GET and POST requests can reach the PUT, PATCH and DELETE endpoints. The generated service
+// routes POST requests that carry the overriding method in the "X-HTTP-Method-Override" header or
+// in the "_method" field of a URL encoded form body as if they used that method. Other methods
+// cannot be tunneled.
+//
+// The optional DSL may change the name of the header and of the form field using OverrideHeader
+// and OverrideField, an empty name disables the corresponding source. MethodOverride may only
+// appear in API. Example:
+//
+//    API("cellar", func() {
+//        MethodOverride(func() {
+//            OverrideHeader("X-Method")
+//            OverrideField("") // Only use the header
+//        })
+//    })
+//
+func MethodOverride(dsl ...func()) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to MethodOverride")
+		return
+	}
+	if api.MethodOverride != nil {
+		dslengine.ReportError("method override already defined")
+		return
+	}
+	m := &design.MethodOverrideDefinition{
+		Header: design.DefaultMethodOverrideHeader,
+		Field:  design.DefaultMethodOverrideField,
+	}
+	if len(dsl) == 1 && !dslengine.Execute(dsl[0], m) {
+		return
+	}
+	if m.Header == "" && m.Field == "" {
+		dslengine.ReportError("method override must use a header or a form field")
+		return
+	}
+	api.MethodOverride = m
+}
+
+// OverrideHeader sets the name of the header that carries the overriding method, see
+// MethodOverride. OverrideHeader may only appear in MethodOverride.
+func OverrideHeader(name string) {
+	if m, ok := methodOverrideDefinition(); ok {
+		m.Header = name
+	}
+}
+
+// OverrideField sets the name of the URL encoded form field that carries the overriding method,
+// see MethodOverride. OverrideField may only appear in MethodOverride.
+func OverrideField(name string) {
+	if m, ok := methodOverrideDefinition(); ok {
+		m.Field = name
+	}
+}
@@ -0,0 +1,68 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MethodOverride", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		API("test", dsl)
+		dslengine.Run()
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			dsl = func() { MethodOverride() }
+		})
+
+		It("uses the default header and form field", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.MethodOverride).ShouldNot(BeNil())
+			Ω(Design.MethodOverride.Header).Should(Equal(DefaultMethodOverrideHeader))
+			Ω(Design.MethodOverride.Field).Should(Equal(DefaultMethodOverrideField))
+		})
+	})
+
+	Context("with a custom header and no form field", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				MethodOverride(func() {
+					OverrideHeader("X-Method")
+					OverrideField("")
+				})
+			}
+		})
+
+		It("records them", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.MethodOverride.Header).Should(Equal("X-Method"))
+			Ω(Design.MethodOverride.Field).Should(BeEmpty())
+		})
+	})
+
+	Context("with neither header nor form field", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				MethodOverride(func() {
+					OverrideHeader("")
+					OverrideField("")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
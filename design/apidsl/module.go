@@ -0,0 +1,150 @@
+package apidsl
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// imports lists the design module imports being executed, outermost first.
+var imports []*design.ImportDefinition
+
+// Module declares a design module: a set of top-level definitions that multiple designs can share,
+// for example health check resources or error types. The module is identified by the import path
+// of the Go package declaring it. The DSL declares types, media types and resources and runs each
+// time a design imports the module with Import. Definitions should refer to other definitions of
+// the module using the values returned by the DSL functions rather than by name since the names
+// change when the module is imported with a prefix. Module must appear at the top level of the Go
+// package declaring the module and returns the module path. Example:
+//
+//	package common
+//
+//	var Path = Module("github.com/org/common-design", func() {
+//		var Health = MediaType("application/vnd.health+json", func() {
+//			Attributes(func() {
+//				Attribute("status", String)
+//			})
+//			View("default", func() {
+//				Attribute("status")
+//			})
+//		})
+//		Resource("health", func() {
+//			Action("check", func() {
+//				Routing(GET("/health"))
+//				Response(OK, Health)
+//			})
+//		})
+//	})
+func Module(path string, dsl func()) string {
+	if path == "" {
+		dslengine.ReportError("design module path cannot be empty")
+		return path
+	}
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return path
+	}
+	if _, ok := design.Modules[path]; ok {
+		dslengine.ReportError("design module %s declared twice", path)
+		return path
+	}
+	design.Modules[path] = &design.ModuleDefinition{
+		Path:     path,
+		Location: dslengine.CallerLocation(),
+		DSLFunc:  dsl,
+	}
+	return path
+}
+
+// Import adds the definitions of the design module with the given path to the design. The Go
+// package declaring the module must be imported by the design package, usually with a blank
+// import. The optional prefix is added to the names of the module types, media types and
+// resources followed by an underscore so that they do not conflict with the design definitions,
+// e.g. the resource "health" becomes "common_health" and its generated controller
+// CommonHealthController when the prefix is "common". Import reports an error when a module
+// definition has the same name as another definition.
+//
+// Importing the same module multiple times with the same prefix, for example by the design and by
+// another imported module, imports it once. Import must appear at the top level of the design.
+// Example:
+//
+//	import _ "github.com/org/common-design"
+//
+//	var _ = Import("github.com/org/common-design", "common")
+func Import(path string, prefix ...string) *design.ImportDefinition {
+	if len(prefix) > 1 {
+		dslengine.ReportError("too many arguments in call to Import")
+		return nil
+	}
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	m, ok := design.Modules[path]
+	if !ok {
+		dslengine.ReportError("unknown design module %s, make sure the design package imports the Go package declaring it", path)
+		return nil
+	}
+	var p string
+	if len(prefix) == 1 {
+		p = prefix[0]
+	}
+	for _, imp := range design.Design.Imports {
+		if imp.Module == m {
+			if imp.Prefix != p {
+				dslengine.ReportError("design module %s already imported with prefix %#v", path, imp.Prefix)
+				return nil
+			}
+			return imp
+		}
+	}
+	imp := &design.ImportDefinition{
+		Module:   m,
+		Prefix:   p,
+		Location: dslengine.CallerLocation(),
+	}
+	design.Design.Imports = append(design.Design.Imports, imp)
+	if m.DSLFunc != nil {
+		imports = append(imports, imp)
+		defer func() { imports = imports[:len(imports)-1] }()
+		m.DSLFunc()
+	}
+	return imp
+}
+
+// importedName returns the name of the definition with the given name taking into account the
+// prefixes of the design modules being imported.
+func importedName(name string) string {
+	for i := len(imports) - 1; i >= 0; i-- {
+		if p := imports[i].Prefix; p != "" {
+			name = p + "_" + name
+		}
+	}
+	return name
+}
+
+// importedModule returns the path of the design module being imported if any.
+func importedModule() string {
+	if len(imports) == 0 {
+		return ""
+	}
+	return imports[len(imports)-1].Module.Path
+}
+
+// conflict returns the explanation appended to the errors reporting definitions declared twice
+// when the definitions come from imported design modules.
+func conflict(module string) string {
+	cur := importedModule()
+	if module == "" && cur == "" {
+		return ""
+	}
+	from := func(m string) string {
+		if m == "" {
+			return "the design"
+		}
+		return "design module " + m
+	}
+	return fmt.Sprintf(" by %s and %s, use an import prefix to avoid the conflict",
+		from(module), from(cur))
+}
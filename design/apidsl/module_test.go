@@ -0,0 +1,127 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Import", func() {
+	const common = "example.com/common"
+	const outer = "example.com/outer"
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		delete(Modules, common)
+		delete(Modules, outer)
+		Module(common, func() {
+			health := MediaType("application/vnd.health+json", func() {
+				Attributes(func() {
+					Attribute("status", String)
+				})
+				View("default", func() {
+					Attribute("status")
+				})
+			})
+			Type("error", func() {
+				Attribute("message", String)
+			})
+			Resource("health", func() {
+				Action("check", func() {
+					Routing(GET("/health"))
+					Response(OK, health)
+				})
+			})
+		})
+		Module(outer, func() {
+			Import(common, "inner")
+		})
+		API("test", nil)
+	})
+
+	AfterEach(func() {
+		delete(Modules, common)
+		delete(Modules, outer)
+	})
+
+	Context("with no prefix", func() {
+		It("adds the module definitions to the design", func() {
+			imp := Import(common)
+			Ω(dslengine.Run()).Should(Succeed())
+			Ω(imp).ShouldNot(BeNil())
+			Ω(Design.Imports).Should(ConsistOf(imp))
+			Ω(Design.Types).Should(HaveKey("error"))
+			Ω(Design.Types["error"].Module).Should(Equal(common))
+			Ω(Design.Resources).Should(HaveKey("health"))
+			Ω(Design.Resources["health"].Module).Should(Equal(common))
+			Ω(Design.Resources["health"].Actions["check"].Responses["OK"].MediaType).Should(Equal("application/vnd.health+json"))
+		})
+	})
+
+	Context("with a prefix", func() {
+		It("prefixes the definition names", func() {
+			Import(common, "common")
+			Ω(dslengine.Run()).Should(Succeed())
+			Ω(Design.Types).Should(HaveKey("common_error"))
+			Ω(Design.Resources).Should(HaveKey("common_health"))
+			Ω(Design.MediaTypes["application/vnd.health"].TypeName).Should(Equal("common_Health"))
+		})
+	})
+
+	Context("with nested imports", func() {
+		It("combines the prefixes", func() {
+			Import(outer, "outer")
+			Ω(dslengine.Run()).Should(Succeed())
+			Ω(Design.Imports).Should(HaveLen(2))
+			Ω(Design.Resources).Should(HaveKey("outer_inner_health"))
+			Ω(Design.Resources["outer_inner_health"].Module).Should(Equal(common))
+		})
+	})
+
+	Context("with a module imported twice", func() {
+		It("imports the module once", func() {
+			imp := Import(common)
+			Ω(Import(common)).Should(Equal(imp))
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Imports).Should(HaveLen(1))
+		})
+
+		It("reports different prefixes", func() {
+			Import(common)
+			Ω(Import(common, "common")).Should(BeNil())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`design module example.com/common already imported with prefix ""`))
+		})
+	})
+
+	Context("with a conflicting definition", func() {
+		It("reports the conflict", func() {
+			Resource("health", nil)
+			Import(common)
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`resource "health" is defined twice by the design and design module example.com/common, use an import prefix to avoid the conflict`))
+		})
+
+		It("reports conflicts with definitions declared after the import", func() {
+			Import(common)
+			Type("error", nil)
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`type "error" defined twice by design module example.com/common and the design`))
+		})
+	})
+
+	Context("with an unknown module", func() {
+		It("reports an error", func() {
+			Ω(Import("example.com/unknown")).Should(BeNil())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("unknown design module example.com/unknown"))
+		})
+	})
+
+	Context("with a module declared twice", func() {
+		It("reports an error", func() {
+			Module(common, nil)
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("design module example.com/common declared twice"))
+		})
+	})
+})
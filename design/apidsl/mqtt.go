@@ -0,0 +1,66 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// MQTT binds the action to an MQTT topic for IoT backends that share the HTTP design. The code
+// generator produces a subscriber adapter that decodes the messages published to the topic as the
+// action payload and calls the action, and a helper that publishes the action results. The topic
+// levels that start with ":" capture the values of the action parameters with the same names, they
+// are subscribed to using the "+" wildcard. The optional DSL sets the QoS and the topic the results
+// are published to. The messages carry no headers or credentials so the action cannot be secured
+// or have required headers, use NoSecurity for actions of secured resources or APIs.
+//
+// MQTT may only appear in Action. Example:
+//
+//	Action("report", func() {
+//		Routing(POST("/devices/:deviceID/telemetry"))
+//		Payload(Telemetry)
+//		Response(OK, Ack)
+//		MQTT("devices/:deviceID/telemetry", func() {
+//			QoS(1)
+//			ResultTopic("devices/:deviceID/ack")
+//		})
+//	})
+func MQTT(topic string, dsl ...func()) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to MQTT")
+		return
+	}
+	if a.MQTT != nil {
+		dslengine.ReportError("MQTT binding already defined")
+		return
+	}
+	a.MQTT = &design.MQTTDefinition{Topic: topic, Parent: a}
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], a.MQTT)
+	}
+}
+
+// QoS sets the quality of service of the MQTT subscription and of the published results: 0 (at
+// most once, the default), 1 (at least once) or 2 (exactly once). QoS may only appear in MQTT.
+func QoS(qos int) {
+	m, ok := dslengine.CurrentDefinition().(*design.MQTTDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	m.QoS = qos
+}
+
+// ResultTopic sets the MQTT topic the successful action results are published to. The topic may
+// use the parameters captured by the MQTT topic. ResultTopic may only appear in MQTT.
+func ResultTopic(topic string) {
+	m, ok := dslengine.CurrentDefinition().(*design.MQTTDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	m.ResultTopic = topic
+}
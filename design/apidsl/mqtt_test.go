@@ -0,0 +1,106 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MQTT", func() {
+	var topic, resultTopic string
+	var qos int
+	var secured bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		topic = "devices/:deviceID/telemetry"
+		resultTopic = "devices/:deviceID/ack"
+		qos = 1
+		secured = false
+	})
+
+	JustBeforeEach(func() {
+		basic := BasicAuthSecurity("basic")
+		Resource("device", func() {
+			if secured {
+				Security(basic)
+			}
+			Action("report", func() {
+				Routing(POST("/devices/:deviceID/telemetry"))
+				Params(func() {
+					Param("deviceID", Integer)
+				})
+				MQTT(topic, func() {
+					QoS(qos)
+					ResultTopic(resultTopic)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("binds the action to the topic", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		m := Design.Resources["device"].Actions["report"].MQTT
+		Ω(m).ShouldNot(BeNil())
+		Ω(m.Topic).Should(Equal("devices/:deviceID/telemetry"))
+		Ω(m.QoS).Should(Equal(1))
+		Ω(m.ResultTopic).Should(Equal("devices/:deviceID/ack"))
+		Ω(Design.UsesMQTT()).Should(BeTrue())
+	})
+
+	Context("with a topic parameter that is not an action parameter", func() {
+		BeforeEach(func() {
+			topic = "devices/:serial/telemetry"
+			resultTopic = ""
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("not a parameter of the action"))
+		})
+	})
+
+	Context("with a result topic parameter not captured by the topic", func() {
+		BeforeEach(func() {
+			resultTopic = "fleets/:fleetID/ack"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a wildcard", func() {
+		BeforeEach(func() {
+			topic = "devices/+/telemetry"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an invalid QoS", func() {
+		BeforeEach(func() {
+			qos = 3
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a secured action", func() {
+		BeforeEach(func() {
+			secured = true
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("MQTT actions cannot be secured"))
+		})
+	})
+})
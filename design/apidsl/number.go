@@ -0,0 +1,44 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// StringEncoded sets the wire representation of an Integer, Number, Int64 or UInt64 attribute, or
+// of the elements of an array of such, to JSON strings holding the decimal representation of the
+// values. This avoids the loss of precision of JavaScript clients which represent all numbers as
+// 64 bit floats. The generated fields keep their Go type. BigInt and Decimal values are always
+// encoded as strings. StringEncoded attributes do not support the built-in validations.
+//
+// StringEncoded may appear in Attribute, Member, Param or Header. Example:
+//
+//	Attribute("id", Int64, func() {
+//		StringEncoded()
+//	})
+func StringEncoded() {
+	a, ok := attributeDefinition()
+	if !ok {
+		return
+	}
+	t := a.Type
+	if t != nil && t.IsArray() {
+		t = t.ToArray().ElemType.Type
+	}
+	s := design.StringEncodedScalar(t)
+	if s == nil {
+		name := "untyped attribute"
+		if t != nil {
+			name = t.Name()
+		}
+		dslengine.ReportError("%s cannot be encoded as a string, StringEncoded only applies to numeric types", name)
+		return
+	}
+	if a.Type.IsArray() {
+		elem := *a.Type.ToArray().ElemType
+		elem.Type = s
+		a.Type = &design.Array{ElemType: &elem}
+		return
+	}
+	a.Type = s
+}
@@ -0,0 +1,60 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StringEncoded", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("encodes numeric attributes as strings", func() {
+		ut := Type("Account", func() {
+			Attribute("id", Int64, func() {
+				StringEncoded()
+			})
+			Attribute("counts", ArrayOf(Integer), func() {
+				StringEncoded()
+			})
+			Attribute("balance", Decimal, func() {
+				StringEncoded()
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		obj := ut.Type.ToObject()
+		id := obj["id"].Type.(*ScalarDefinition)
+		Ω(id.Kind()).Should(Equal(StringKind))
+		Ω(id.GoType).Should(Equal("int64"))
+		count := obj["counts"].Type.ToArray().ElemType.Type.(*ScalarDefinition)
+		Ω(count.Kind()).Should(Equal(StringKind))
+		Ω(count.GoType).Should(Equal("int"))
+		Ω(obj["balance"].Type).Should(Equal(Decimal))
+	})
+
+	It("rejects non numeric attributes", func() {
+		Type("Account", func() {
+			Attribute("name", String, func() {
+				StringEncoded()
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+	})
+
+	It("does not generate pointers to reference types", func() {
+		ut := Type("Account", func() {
+			Attribute("balance", Decimal)
+			Attribute("id", Int64)
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(ut.IsPrimitivePointer("balance")).Should(BeFalse())
+		Ω(ut.IsPrimitivePointer("id")).Should(BeTrue())
+	})
+})
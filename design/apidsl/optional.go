@@ -0,0 +1,54 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// OptionalFields sets the policy used to generate the fields of the optional primitive attributes
+// so that services can distinguish an absent value from the zero value without using pointers
+// everywhere. The policy is one of:
+//
+//    design.PointerFields:  pointer fields, nil when absent (default)
+//    design.NullableFields: goa.NullString, goa.NullInt etc. fields whose Valid field is false
+//                           when absent
+//    design.PresenceFields: plain value fields and a presence bitmap exposed by generated HasXXX
+//                           methods
+//
+// OptionalFields may appear in API to set the default policy of all the types, media types and
+// payloads, or in Type, MediaType, Payload or the DSL of an object attribute. Example:
+//
+//    var UpdateAccountPayload = Type("UpdateAccountPayload", func() {
+//        OptionalFields(PresenceFields)
+//        Attribute("name", String)
+//        Attribute("age", Integer)
+//    })
+//
+// The generated UpdateAccountPayload type has Name string and Age int fields and HasName and
+// HasAge methods that report whether the request body set them.
+func OptionalFields(policy design.FieldPolicy) {
+	switch policy {
+	case design.PointerFields, design.NullableFields, design.PresenceFields:
+	default:
+		dslengine.ReportError("invalid optional field policy %#v, must be one of %#v, %#v or %#v",
+			policy, design.PointerFields, design.NullableFields, design.PresenceFields)
+		return
+	}
+	var att *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		def.FieldPolicy = policy
+		return
+	case *design.AttributeDefinition:
+		att = def
+	case *design.MediaTypeDefinition:
+		att = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if att.Metadata == nil {
+		att.Metadata = make(dslengine.MetadataDefinition)
+	}
+	att.Metadata[design.FieldPolicyMetadata] = []string{string(policy)}
+}
@@ -0,0 +1,104 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OptionalFields", func() {
+	var ut *UserTypeDefinition
+	var policy FieldPolicy
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		policy = NullableFields
+	})
+
+	JustBeforeEach(func() {
+		ut = Type("Account", func() {
+			OptionalFields(policy)
+			Attribute("name", String)
+			Attribute("id", UUID)
+			Attribute("age", Integer, func() {
+				Default(18)
+			})
+			Attribute("email", String)
+			Required("email")
+		})
+		dslengine.Run()
+	})
+
+	It("sets the type field policy", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(ut.FieldPolicy()).Should(Equal(NullableFields))
+		Ω(ut.IsNullable("name")).Should(BeTrue())
+		Ω(ut.IsPrimitivePointer("name")).Should(BeFalse())
+	})
+
+	It("keeps pointers for the types without wrappers", func() {
+		Ω(ut.IsNullable("id")).Should(BeFalse())
+		Ω(ut.IsPrimitivePointer("id")).Should(BeTrue())
+	})
+
+	It("ignores required attributes and attributes with defaults", func() {
+		Ω(ut.IsNullable("email")).Should(BeFalse())
+		Ω(ut.IsNullable("age")).Should(BeFalse())
+	})
+
+	Context("with the presence policy", func() {
+		BeforeEach(func() {
+			policy = PresenceFields
+		})
+
+		It("records the presence of the optional attributes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(ut.PresenceAttributes()).Should(Equal([]string{"id", "name"}))
+			Ω(ut.PresenceIndex("name")).Should(Equal(1))
+			Ω(ut.PresenceIndex("email")).Should(Equal(-1))
+			Ω(ut.IsPrimitivePointer("name")).Should(BeFalse())
+		})
+	})
+
+	Context("with an invalid policy", func() {
+		BeforeEach(func() {
+			policy = "bitmap"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("set in the API", func() {
+		var mt *MediaTypeDefinition
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				OptionalFields(PresenceFields)
+			})
+			ut = Type("Account", func() {
+				Attribute("name", String)
+			})
+			mt = MediaType("application/vnd.account", func() {
+				OptionalFields(PointerFields)
+				Attributes(func() {
+					Attribute("name", String)
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("applies to the types that do not override it", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(ut.FieldPolicy()).Should(Equal(PresenceFields))
+			Ω(mt.FieldPolicy()).Should(Equal(PointerFields))
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Outbox makes the requests of the action run in a transaction of the store registered with
+// outbox.UseStore. The action context exposes the transaction in its Outbox field: the action
+// implementation writes its changes and records events with the transaction so that both commit
+// atomically. The events defined with EmitEvent are recorded in the transaction instead of being
+// published directly. The transaction commits before the response is sent if the action succeeds
+// and rolls back otherwise, an outbox.Relay delivers the committed events to the event publisher.
+//
+// Outbox may appear in Action or in Resource in which case it applies to all the resource actions
+// that have a POST, PUT, PATCH or DELETE route. Example:
+//
+//	Resource("bottle", func() {
+//		Outbox()
+//		Action("create", func() {
+//			Routing(POST(""))
+//			EmitEvent()
+//			Payload(BottlePayload)
+//			Response(Created, BottleMedia)
+//		})
+//	})
+func Outbox() {
+	var md *dslengine.MetadataDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ResourceDefinition:
+		md = &def.Metadata
+	case *design.ActionDefinition:
+		md = &def.Metadata
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if *md == nil {
+		*md = make(dslengine.MetadataDefinition)
+	}
+	(*md)[design.OutboxMetadata] = []string{}
+}
@@ -0,0 +1,75 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outbox", func() {
+	var resourceDSL, actionDSL func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = POST
+		resourceDSL = func() {}
+		actionDSL = func() {
+			Outbox()
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			resourceDSL()
+			Action("create", func() {
+				Routing(verb(""))
+				actionDSL()
+				Response(NoContent)
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("runs the action in an outbox transaction", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		r := Design.Resources["bottle"]
+		Ω(r.Actions["create"].UsesOutbox()).Should(BeTrue())
+		Ω(r.Actions["show"].UsesOutbox()).Should(BeFalse())
+		Ω(Design.UsesOutbox()).Should(BeTrue())
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Outbox()
+			}
+			actionDSL = func() {}
+		})
+
+		It("applies to the mutating actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["bottle"]
+			Ω(r.Actions["create"].UsesOutbox()).Should(BeTrue())
+			Ω(r.Actions["show"].UsesOutbox()).Should(BeFalse())
+		})
+	})
+
+	Context("with a GET route", func() {
+		BeforeEach(func() {
+			verb = GET
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("only actions with a POST, PUT, PATCH or DELETE route may use the transactional outbox"))
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Partial declares that the enclosing view purposely omits some of the required attributes of its
+// media type. Views must otherwise render all the required attributes so that the responses they
+// produce pass the media type validations. The "link" view is always partial.
+//
+// Partial must appear in View. Example:
+//
+//	MediaType("application/vnd.goa.example.bottle", func() {
+//		Attributes(func() {
+//			Attribute("id", Integer)
+//			Attribute("name")
+//			Attribute("vintage", Integer)
+//			Required("id", "name", "vintage")
+//		})
+//		View("default", func() {
+//			Attribute("id")
+//			Attribute("name")
+//			Attribute("vintage")
+//		})
+//		View("tiny", func() {
+//			Partial()
+//			Attribute("id")
+//			Attribute("name")
+//		})
+//	})
+func Partial() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.PartialViewMetadata] = []string{}
+	}
+}
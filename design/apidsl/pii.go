@@ -0,0 +1,34 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// PII classifies the attribute as holding personally identifiable information of the given class,
+// e.g. "email", "phone" or "address". Classes are free form strings. The "pii" goagen command
+// generates a data-flow report listing the endpoints that receive or return each class of PII.
+// Classified attributes nested in payloads, parameters, headers and response media types are all
+// reported.
+//
+// Example:
+//
+//    var User = Type("User", func() {
+//        Attribute("email", String, func() {
+//            Format("email")
+//            PII("email")
+//        })
+//    })
+//
+func PII(class string) {
+	if a, ok := attributeDefinition(); ok {
+		if class == "" {
+			dslengine.ReportError("PII class cannot be empty")
+			return
+		}
+		if a.Metadata == nil {
+			a.Metadata = make(dslengine.MetadataDefinition)
+		}
+		a.Metadata[design.PIIMetadata] = []string{class}
+	}
+}
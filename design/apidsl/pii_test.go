@@ -0,0 +1,45 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PII", func() {
+	var ut *UserTypeDefinition
+	var class string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		class = "email"
+	})
+
+	JustBeforeEach(func() {
+		ut = Type("User", func() {
+			Attribute("name", String)
+			Attribute("email", String, func() {
+				PII(class)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("records the PII class", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(ut.Type.ToObject()["email"].PIIClass()).Should(Equal("email"))
+		Ω(ut.Type.ToObject()["name"].PIIClass()).Should(BeEmpty())
+	})
+
+	Context("with an empty class", func() {
+		BeforeEach(func() {
+			class = ""
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,43 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// PresignedUpload makes the action hand out presigned PUT URLs that let clients upload files
+// directly to the given object storage bucket. The URLs are issued by the presigner registered
+// with upload.UsePresigner (see the middleware/upload package) which abstracts the storage
+// provider (S3, GCS etc.). The optional argument is the number of seconds during which the URLs
+// are valid, it defaults to 900.
+//
+// Requests sent to the action routes get a JSON response describing the presigned URL and the
+// object key. The generated code also mounts the completion callback routes under the action
+// routes paths followed by "/:upload_id/complete": clients call them once the file is uploaded,
+// the action is then invoked and its context exposes the uploaded object in the Upload field.
+// PresignedUpload actions must use POST routes and define a NoContent response. Example:
+//
+//	Action("upload", func() {
+//		Routing(POST("/avatars"))
+//		PresignedUpload("avatars", 300)
+//		Response(NoContent)
+//	})
+func PresignedUpload(bucket string, expiry ...uint) {
+	if len(expiry) > 1 {
+		dslengine.ReportError("too many arguments in call to PresignedUpload")
+		return
+	}
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.PresignedUpload != nil {
+		dslengine.ReportError("presigned upload already defined")
+		return
+	}
+	p := &design.PresignedUploadDefinition{Bucket: bucket, Expiry: design.DefaultPresignExpiry, Parent: a}
+	if len(expiry) == 1 {
+		p.Expiry = expiry[0]
+	}
+	a.PresignedUpload = p
+}
@@ -0,0 +1,95 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PresignedUpload", func() {
+	var dsl func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = POST
+		dsl = func() {
+			PresignedUpload("avatars", 300)
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("user", func() {
+			BasePath("/users")
+			Action("avatar", func() {
+				Routing(verb("/avatars"))
+				dsl()
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the presigned upload settings", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		p := Design.Resources["user"].Actions["avatar"].PresignedUpload
+		Ω(p).ShouldNot(BeNil())
+		Ω(p.Bucket).Should(Equal("avatars"))
+		Ω(p.Expiry).Should(Equal(uint(300)))
+		Ω(p.Paths()).Should(Equal([]string{"/users/avatars/:upload_id/complete"}))
+	})
+
+	Context("with no expiry", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				PresignedUpload("avatars")
+			}
+		})
+
+		It("uses the default expiry", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			p := Design.Resources["user"].Actions["avatar"].PresignedUpload
+			Ω(p.Expiry).Should(Equal(uint(DefaultPresignExpiry)))
+		})
+	})
+
+	Context("with an empty bucket", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				PresignedUpload("")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("bucket name cannot be empty"))
+		})
+	})
+
+	Context("with a non POST route", func() {
+		BeforeEach(func() {
+			verb = GET
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("presigned upload actions may only have POST routes, got GET"))
+		})
+	})
+
+	Context("with a resumable upload", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				PresignedUpload("avatars")
+				ResumableUpload()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("actions cannot define both a presigned upload and a resumable upload"))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+package apidsl
+
+import "github.com/goadesign/goa/dslengine"
+
+// Profile runs dsl only when the profile with the given name is active. Profiles make it possible
+// to keep definitions that must not always be exposed, such as internal-only endpoints or debug
+// resources, in the same design as the public API: definitions declared in the DSL of an inactive
+// profile do not exist so they are excluded from all the generated code and specifications.
+// Profiles are activated with the goagen --profile flag which accepts a comma separated list of
+// profile names, all profiles are inactive by default.
+//
+// Profile may appear anywhere, including at the top level of the design and inside other
+// profiles. Profile returns true if the profile is active. Example:
+//
+//	var _ = Profile("internal", func() {
+//		Resource("debug", func() {
+//			Action("vars", func() {
+//				Routing(GET("/debug/vars"))
+//				Response(OK)
+//			})
+//		})
+//	})
+//
+//	var _ = Resource("bottle", func() {
+//		Action("show", func() {
+//			Routing(GET("/:id"))
+//			Response(OK)
+//		})
+//		Profile("internal", func() {
+//			Action("purge", func() {
+//				Routing(DELETE("/:id/purge"))
+//				Response(NoContent)
+//			})
+//		})
+//	})
+//
+// generates the "debug" resource and the "purge" action only when running:
+//
+//	goagen bootstrap -d github.com/example/cellar/design --profile internal
+func Profile(name string, dsl func()) bool {
+	if name == "" {
+		dslengine.ReportError("profile name cannot be empty")
+		return false
+	}
+	if !dslengine.ProfileActive(name) {
+		return false
+	}
+	dsl()
+	return true
+}
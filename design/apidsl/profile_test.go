@@ -0,0 +1,79 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profile", func() {
+	var profiles []string
+	var active bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		profiles = nil
+	})
+
+	JustBeforeEach(func() {
+		prev := dslengine.ActiveProfiles
+		dslengine.ActiveProfiles = profiles
+		defer func() { dslengine.ActiveProfiles = prev }()
+		API("test", nil)
+		active = Profile("internal", func() {
+			Resource("debug", func() {
+				Action("vars", func() {
+					Routing(GET("/debug/vars"))
+				})
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+			Profile("internal", func() {
+				Action("purge", func() {
+					Routing(DELETE("/:id/purge"))
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with no active profile", func() {
+		It("skips the profile DSL", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(active).Should(BeFalse())
+			Ω(Design.Resources).ShouldNot(HaveKey("debug"))
+			Ω(Design.Resources["bottle"].Actions).Should(HaveKey("show"))
+			Ω(Design.Resources["bottle"].Actions).ShouldNot(HaveKey("purge"))
+		})
+	})
+
+	Context("with another active profile", func() {
+		BeforeEach(func() {
+			profiles = []string{"debug"}
+		})
+
+		It("skips the profile DSL", func() {
+			Ω(active).Should(BeFalse())
+			Ω(Design.Resources).ShouldNot(HaveKey("debug"))
+		})
+	})
+
+	Context("with the profile active", func() {
+		BeforeEach(func() {
+			profiles = []string{"debug", "internal"}
+		})
+
+		It("runs the profile DSL", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(active).Should(BeTrue())
+			Ω(Design.Resources).Should(HaveKey("debug"))
+			Ω(Design.Resources["bottle"].Actions).Should(HaveKey("show"))
+			Ω(Design.Resources["bottle"].Actions).Should(HaveKey("purge"))
+		})
+	})
+})
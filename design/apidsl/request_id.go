@@ -0,0 +1,42 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// RequestID enables request correlation: the generated middleware reads the request ID from the
+// given header or generates one if the request does not have it, stores it in the request context
+// and writes it to the response header. The generated error responses include the request ID in
+// their "request_id" meta. The header defaults to "X-Request-Id". The generated client sends the
+// request ID stored in the request context using the same header.
+//
+// RequestID may only appear in API. Example:
+//
+//    API("cellar", func() {
+//        RequestID("X-Correlation-Id")
+//    })
+//
+func RequestID(header ...string) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if len(header) > 1 {
+		dslengine.ReportError("too many arguments in call to RequestID")
+		return
+	}
+	if api.RequestID != nil {
+		dslengine.ReportError("request ID already defined")
+		return
+	}
+	h := design.DefaultRequestIDHeader
+	if len(header) == 1 {
+		if header[0] == "" {
+			dslengine.ReportError("request ID header name cannot be empty")
+			return
+		}
+		h = header[0]
+	}
+	api.RequestID = &design.RequestIDDefinition{Header: h}
+}
@@ -0,0 +1,54 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RequestID", func() {
+	var header []string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		header = nil
+	})
+
+	JustBeforeEach(func() {
+		API("correlated", func() {
+			RequestID(header...)
+		})
+		dslengine.Run()
+	})
+
+	Context("with no header", func() {
+		It("uses the default header", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.RequestID).ShouldNot(BeNil())
+			Ω(Design.RequestID.Header).Should(Equal(DefaultRequestIDHeader))
+		})
+	})
+
+	Context("with a header", func() {
+		BeforeEach(func() {
+			header = []string{"X-Correlation-Id"}
+		})
+
+		It("sets the header", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.RequestID.Header).Should(Equal("X-Correlation-Id"))
+		})
+	})
+
+	Context("with an empty header", func() {
+		BeforeEach(func() {
+			header = []string{""}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -55,11 +55,14 @@ func Resource(name string, dsl func()) *design.ResourceDefinition {
 		return nil
 	}
 
-	if _, ok := design.Design.Resources[name]; ok {
-		dslengine.ReportError("resource %#v is defined twice", name)
+	name = importedName(name)
+	if r, ok := design.Design.Resources[name]; ok {
+		dslengine.ReportError("resource %#v is defined twice%s", name, conflict(r.Module))
 		return nil
 	}
-	resource := design.NewResourceDefinition(name, dsl)
+	resource := design.NewResourceDefinition(name, checkedCache(dsl))
+	resource.Location = dslengine.CallerLocation()
+	resource.Module = importedModule()
 	design.Design.Resources[name] = resource
 	return resource
 }
@@ -92,7 +95,7 @@ func DefaultMedia(val interface{}, viewName ...string) {
 				m.Resource = r
 			}
 		} else if identifier, ok := val.(string); ok {
-			r.MediaType = identifier
+			r.MediaType = design.Design.ExpandIdentifier(identifier)
 		} else {
 			dslengine.ReportError("media type must be a string or a *design.MediaTypeDefinition, got %#v", val)
 			return
@@ -78,6 +78,41 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
+	Context("with parent resources forming a cycle", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Parent("bar")
+				Action("show", func() { Routing(GET("/:fooID")) })
+			}
+			Resource("bar", func() {
+				Parent("foo")
+				Action("show", func() { Routing(GET("/:barID")) })
+			})
+		})
+
+		It("reports the cycle", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("form a cycle"))
+		})
+	})
+
+	Context("with a parent whose canonical action has no route", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() { Parent("bar") }
+			Resource("bar", func() {
+				CanonicalActionName("show")
+				Action("show", func() {})
+			})
+		})
+
+		It("produces an invalid resource definition", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`Canonical action "show" of parent resource "bar" has no route`))
+		})
+	})
+
 	Context("with actions", func() {
 		const actionName = "action"
 
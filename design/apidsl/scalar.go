@@ -0,0 +1,78 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Scalar defines a custom primitive type. The first argument is the name of the scalar, the second
+// the primitive type used to represent values on the wire: String, Integer, Number or Boolean. The
+// DSL sets the Go type used by the generated code and the functions that convert values between
+// the wire and Go representations. Scalar may only appear at the top level and the scalar may be
+// used wherever a type is expected: Attribute, Param, Header, ArrayOf, HashOf etc. Example:
+//
+//    var Decimal = Scalar("Decimal", String, func() {
+//        Description("A precise decimal number")
+//        GoType("github.com/shopspring/decimal", "decimal.Decimal")
+//        ParseWith("decimal.NewFromString")         // func(string) (decimal.Decimal, error)
+//        FormatWith("decimal.Decimal.String")       // func(decimal.Decimal) string
+//        ValidateWith("money.ValidateAmount")       // func(decimal.Decimal) error
+//    })
+//
+// The request bodies, parameters and headers are parsed with the parse function and validated
+// with the validate function if any. The response bodies encode the Go values directly so the Go
+// type should marshal to the wire representation, the client uses the format function to build
+// request parameters and headers. The built-in validations (Pattern, MinLength etc.) do not apply
+// to scalars, use ValidateWith instead.
+func Scalar(name string, wire design.Primitive, dsl func()) *design.ScalarDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	for _, s := range design.Design.Scalars {
+		if s.TypeName == name {
+			dslengine.ReportError("scalar %#v is defined twice", name)
+			return nil
+		}
+	}
+	s := &design.ScalarDefinition{Primitive: wire, TypeName: name, DSLFunc: dsl}
+	design.Design.Scalars = append(design.Design.Scalars, s)
+	return s
+}
+
+// GoType sets the Go type of a custom scalar: path is the import path of the package defining it
+// and typeName the qualified type name as used in the generated code, e.g. "decimal.Decimal". The
+// parse, format and validate functions may be defined in the same package. GoType may only appear
+// in Scalar.
+func GoType(path, typeName string) {
+	if s, ok := scalarDefinition(); ok {
+		s.PackagePath = path
+		s.GoType = typeName
+	}
+}
+
+// ParseWith sets the qualified name of the function used to convert wire values into Go values.
+// The function signature is func(wire) (GoType, error) where wire is the Go type of the scalar
+// primitive (string, int, float64 or bool). ParseWith may only appear in Scalar.
+func ParseWith(fn string) {
+	if s, ok := scalarDefinition(); ok {
+		s.ParseFunc = fn
+	}
+}
+
+// FormatWith sets the qualified name of the function used to convert Go values into wire values.
+// The function signature is func(GoType) wire. Method expressions such as
+// "decimal.Decimal.String" may be used. FormatWith may only appear in Scalar.
+func FormatWith(fn string) {
+	if s, ok := scalarDefinition(); ok {
+		s.FormatFunc = fn
+	}
+}
+
+// ValidateWith sets the qualified name of the function used to validate Go values. The function
+// signature is func(GoType) error. ValidateWith may only appear in Scalar.
+func ValidateWith(fn string) {
+	if s, ok := scalarDefinition(); ok {
+		s.ValidateFunc = fn
+	}
+}
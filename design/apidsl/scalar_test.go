@@ -0,0 +1,69 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scalar", func() {
+	var s *ScalarDefinition
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = func() {
+			Description("A precise decimal number")
+			GoType("github.com/shopspring/decimal", "decimal.Decimal")
+			ParseWith("decimal.NewFromString")
+			FormatWith("decimal.Decimal.String")
+		}
+	})
+
+	JustBeforeEach(func() {
+		s = Scalar("Decimal", String, dsl)
+		dslengine.Run()
+	})
+
+	It("defines the scalar", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Scalars).Should(ConsistOf(s))
+		Ω(s.Name()).Should(Equal("Decimal"))
+		Ω(s.Kind()).Should(Equal(StringKind))
+		Ω(s.Description).Should(Equal("A precise decimal number"))
+		Ω(s.PackagePath).Should(Equal("github.com/shopspring/decimal"))
+		Ω(s.GoType).Should(Equal("decimal.Decimal"))
+		Ω(s.ParseFunc).Should(Equal("decimal.NewFromString"))
+		Ω(s.FormatFunc).Should(Equal("decimal.Decimal.String"))
+	})
+
+	Context("with no Go type", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				ParseWith("decimal.NewFromString")
+				FormatWith("decimal.Decimal.String")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("used by an attribute with validations", func() {
+		JustBeforeEach(func() {
+			Type("Price", func() {
+				Attribute("amount", s, func() {
+					MinLength(1)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,35 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Schedule makes the service call the action periodically on the schedule given by the standard
+// 5 field cron expression, see cron.Parse for the syntax. The generated scaffold registers the
+// action with a cron.Runner that calls it through the service mux so that the same action logic
+// serves the HTTP requests and the scheduled calls. A run does not start while the previous run of
+// the action is in progress. The action cannot have a payload, path parameters, required
+// parameters or required headers. The runner sends no credentials so the action must not be
+// secured, use NoSecurity for actions of secured resources or APIs. Actions of APIs that read the
+// tenant from a header cannot be scheduled.
+//
+// Schedule may only appear in Action. Example:
+//
+//	Action("purge", func() {
+//		Routing(DELETE("/expired"))
+//		Schedule("*/5 * * * *")
+//		NoSecurity()
+//		Response(NoContent)
+//	})
+func Schedule(spec string) {
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Schedule != nil {
+		dslengine.ReportError("schedule already defined")
+		return
+	}
+	a.Schedule = &design.ScheduleDefinition{Spec: spec, Parent: a}
+}
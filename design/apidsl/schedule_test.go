@@ -0,0 +1,126 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	var spec, path string
+	var payload, secured, unsecured, header bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		spec = "*/5 * * * *"
+		path = "/expired"
+		payload = false
+		secured = false
+		unsecured = false
+		header = false
+	})
+
+	JustBeforeEach(func() {
+		if secured {
+			API("reports", func() {
+				Security(APIKeySecurity("key", func() {
+					Header("X-Api-Key")
+				}))
+			})
+		}
+		Resource("report", func() {
+			Action("purge", func() {
+				Routing(DELETE(path))
+				Schedule(spec)
+				if payload {
+					Payload(func() {
+						Attribute("before", DateTime)
+					})
+				}
+				if unsecured {
+					NoSecurity()
+				}
+				if header {
+					Headers(func() {
+						Header("X-Reason")
+						Required("X-Reason")
+					})
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("schedules the action", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		s := Design.Resources["report"].Actions["purge"].Schedule
+		Ω(s).ShouldNot(BeNil())
+		Ω(s.Spec).Should(Equal("*/5 * * * *"))
+		Ω(Design.UsesSchedule()).Should(BeTrue())
+	})
+
+	Context("with an invalid cron expression", func() {
+		BeforeEach(func() {
+			spec = "*/5 * * *"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("expected 5 fields"))
+		})
+	})
+
+	Context("with a path parameter", func() {
+		BeforeEach(func() {
+			path = "/:reportID"
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a payload", func() {
+		BeforeEach(func() {
+			payload = true
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a required header", func() {
+		BeforeEach(func() {
+			header = true
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("required headers"))
+		})
+	})
+
+	Context("with a secured API", func() {
+		BeforeEach(func() {
+			secured = true
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot be secured"))
+		})
+
+		Context("and an unsecured action", func() {
+			BeforeEach(func() {
+				unsecured = true
+			})
+
+			It("schedules the action", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+})
@@ -284,6 +284,23 @@ func Scope(name string, desc ...string) {
 	}
 }
 
+// Role defines a role required to access the action. Role may only be used within a Security
+// block. The generated code compares the required roles to the "roles" claim of the
+// authenticated principal and responds with the action Forbidden response when one is missing:
+//
+//    Security(JWT, func() {
+//        Scope("read:bottles")
+//        Role("admin")         // Requires the "admin" role
+//    })
+//
+func Role(name string) {
+	if current, ok := dslengine.CurrentDefinition().(*design.SecurityDefinition); ok {
+		current.Roles = append(current.Roles, name)
+		return
+	}
+	dslengine.IncompatibleDSL()
+}
+
 // inHeader is called by `Header()`, see documentation there.
 func inHeader(headerName string) {
 	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
@@ -175,6 +175,43 @@ var _ = Describe("Security", func() {
 
 	})
 
+	Context("with roles", func() {
+		It("should record the roles required by the action", func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Scope("read:bottles", "Read bottles")
+				})
+			})
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+					Security("jwt", func() {
+						Scope("read:bottles")
+						Role("admin")
+					})
+				})
+			})
+
+			dslengine.Run()
+
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			security := Design.Resources["one"].Actions["first"].Security
+			Ω(security.Scopes).Should(Equal([]string{"read:bottles"}))
+			Ω(security.Roles).Should(Equal([]string{"admin"}))
+		})
+
+		It("should fail when used outside of Security", func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Role("admin")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with resources and actions", func() {
 		It("should fallback properly to lower-level security", func() {
 			API("", func() {
@@ -0,0 +1,65 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Server defines a named environment serving the API such as "production" or "staging". The
+// server DSL sets the server URL with URL. The URL may be a template whose variables are defined
+// with Variable. Protocol selects the HTTP protocol spoken by the server. Server definitions are
+// listed in the generated Swagger specification, the generated client can select the server its
+// requests are sent to. The first server default URL sets the API host and scheme if they are not
+// defined.
+//
+// Server may only appear in API. Example:
+//
+//    Server("production", func() {
+//        Description("Production hosts")
+//        URL("https://{region}.api.example.com")
+//        Variable("region", String, "AWS region", func() {
+//            Enum("us-east-1", "eu-west-1")
+//            Default("us-east-1")
+//        })
+//        Protocol("h2")
+//    })
+//
+func Server(name string, dsl func()) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	for _, s := range api.Servers {
+		if s.Name == name {
+			dslengine.ReportError("cannot redefine server with name %q", name)
+			return
+		}
+	}
+	api.Servers = append(api.Servers, &design.ServerDefinition{Name: name, DSLFunc: dsl})
+}
+
+// Variable defines a server URL template variable. Variable accepts the same arguments as
+// Attribute, server variables must be strings and must define a default value.
+func Variable(name string, args ...interface{}) {
+	s, ok := dslengine.CurrentDefinition().(*design.ServerDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if s.Variables == nil {
+		s.Variables = &design.AttributeDefinition{Type: design.Object{}}
+	}
+	dslengine.Execute(func() { Attribute(name, args...) }, s.Variables)
+}
+
+// Protocol sets the HTTP protocol spoken by the server: "http/1.1" (default), "h2" for HTTP/2
+// over TLS or "h2c" for HTTP/2 over cleartext TCP. The scaffolded service serves HTTP/2 when a
+// server uses it and the generated client connects to h2c servers with a HTTP/2 transport.
+// Protocol may only appear in Server.
+func Protocol(protocol string) {
+	if s, ok := dslengine.CurrentDefinition().(*design.ServerDefinition); ok {
+		s.Protocol = protocol
+		return
+	}
+	dslengine.IncompatibleDSL()
+}
@@ -0,0 +1,104 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with a URL template", func() {
+		BeforeEach(func() {
+			API("multi", func() {
+				Server("production", func() {
+					Description("Production hosts")
+					URL("https://{region}.api.example.com")
+					Variable("region", String, "AWS region", func() {
+						Enum("us-east-1", "eu-west-1")
+						Default("us-east-1")
+					})
+				})
+				Server("local", func() {
+					URL("http://localhost:8080")
+					Protocol("h2c")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("defines the servers", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Servers).Should(HaveLen(2))
+			prod := Design.Servers[0]
+			Ω(prod.Name).Should(Equal("production"))
+			Ω(prod.Description).Should(Equal("Production hosts"))
+			Ω(prod.URL).Should(Equal("https://{region}.api.example.com"))
+			Ω(prod.VariableNames()).Should(Equal([]string{"region"}))
+			Ω(prod.DefaultURL()).Should(Equal("https://us-east-1.api.example.com"))
+			Ω(Design.Servers[1].Protocol).Should(Equal(ProtocolH2C))
+			Ω(Design.UsesProtocol(ProtocolH2C)).Should(BeTrue())
+			Ω(Design.UsesProtocol(ProtocolHTTP2)).Should(BeFalse())
+		})
+
+		It("sets the API host and scheme from the first server", func() {
+			Ω(Design.Host).Should(Equal("us-east-1.api.example.com"))
+			Ω(Design.Schemes).Should(Equal([]string{"https"}))
+		})
+	})
+
+	Context("with a variable without default value", func() {
+		BeforeEach(func() {
+			API("multi", func() {
+				Server("production", func() {
+					URL("https://{region}.api.example.com")
+					Variable("region", String)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must have a default value"))
+		})
+	})
+
+	Context("with an undefined variable", func() {
+		BeforeEach(func() {
+			API("multi", func() {
+				Server("production", func() {
+					URL("https://{region}.api.example.com")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("is not defined"))
+		})
+	})
+
+	Context("with HTTP/2 over a cleartext URL", func() {
+		BeforeEach(func() {
+			API("multi", func() {
+				Server("local", func() {
+					URL("http://localhost:8080")
+					Protocol("h2")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("requires TLS"))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// SessionSecurity defines a cookie based session security scheme. Clients log in to obtain a
+// session cookie which authenticates the subsequent requests, the session data is kept server
+// side in a session store. Cookie sets the name of the session cookie ("session" by default) and
+// SessionData the design type describing the data stored in the sessions.
+//
+// Example:
+//
+//    var UserSession = Type("UserSession", func() {
+//        Attribute("user_id", Integer)
+//        Attribute("name", String)
+//    })
+//
+//    var Session = SessionSecurity("session", func() {
+//        Description("Cookie based sessions")
+//        Cookie("sid")
+//        SessionData(UserSession)
+//    })
+//
+func SessionSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *dslengine.TopLevelDefinition:
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+
+	if securitySchemeRedefined(name) {
+		return nil
+	}
+
+	def := &design.SecuritySchemeDefinition{
+		Kind:       design.SessionSecurityKind,
+		SchemeName: name,
+		Type:       "apiKey",
+		In:         "header",
+		Name:       "Cookie",
+		CookieName: design.DefaultSessionCookie,
+	}
+
+	if len(dsl) != 0 {
+		def.DSLFunc = dsl[0]
+	}
+
+	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
+
+	return def
+}
+
+// Cookie sets the name of the cookie holding the session ID. Cookie may only be used in a
+// SessionSecurity definition.
+func Cookie(name string) {
+	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if current.Kind == design.SessionSecurityKind {
+			current.CookieName = name
+			return
+		}
+	}
+	dslengine.IncompatibleDSL()
+}
+
+// SessionData sets the type of the data stored in the sessions. The type must be a user type or a
+// media type. SessionData may only be used in a SessionSecurity definition.
+func SessionData(t design.DataType) {
+	current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition)
+	if !ok || current.Kind != design.SessionSecurityKind {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		current.SessionData = actual
+	case *design.MediaTypeDefinition:
+		current.SessionData = actual.UserTypeDefinition
+	default:
+		dslengine.ReportError("session data must be a user type or a media type")
+	}
+}
+
+// SessionEndpoints defines the "session" resource used to log in and out of a SessionSecurity
+// scheme. The "login" action accepts a username and a password and starts a session, the "logout"
+// action requires a session and ends it. The scheme can be given by name or using the value
+// returned by SessionSecurity. The optional DSL is run at the end of the resource definition and
+// may override the base path or the login payload. SessionEndpoints must appear at the top level.
+// Example:
+//
+//    var _ = SessionEndpoints(Session, func() {
+//        BasePath("/auth")
+//    })
+//
+// The generated main scaffold implements the actions using a session.Store shared with the
+// scheme middleware.
+func SessionEndpoints(scheme interface{}, dsl ...func()) *design.ResourceDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	var name string
+	switch val := scheme.(type) {
+	case string:
+		name = val
+	case *design.SecuritySchemeDefinition:
+		name = val.SchemeName
+	default:
+		dslengine.ReportError("invalid value for 'scheme' parameter, specify a string or a *SecuritySchemeDefinition")
+		return nil
+	}
+
+	return Resource("session", func() {
+		var def *design.SecuritySchemeDefinition
+		for _, s := range design.Design.SecuritySchemes {
+			if s.SchemeName == name {
+				def = s
+			}
+		}
+		if def == nil {
+			dslengine.ReportError("security scheme %q not found", name)
+			return
+		}
+		if def.Kind != design.SessionSecurityKind {
+			dslengine.ReportError("SessionEndpoints requires a SessionSecurity scheme, %q is not one", name)
+			return
+		}
+		Description("Logs users in and out of the " + name + " security scheme")
+		BasePath("/session")
+		Metadata(design.SessionsMetadata, name)
+		Action("login", func() {
+			Description("Start a session, the response sets the session cookie")
+			Routing(POST("/login"))
+			NoSecurity()
+			Payload(func() {
+				Attribute("username", design.String, "User name")
+				Attribute("password", design.String, "User password")
+				Required("username", "password")
+			})
+			Response(design.NoContent)
+			Response(design.Unauthorized)
+		})
+		Action("logout", func() {
+			Description("End the session and clear the session cookie")
+			Routing(POST("/logout"))
+			Security(def)
+			Response(design.NoContent)
+		})
+		if len(dsl) > 0 {
+			dsl[0]()
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SessionSecurity", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with a cookie and session data", func() {
+		var scheme *SecuritySchemeDefinition
+		var res *ResourceDefinition
+
+		BeforeEach(func() {
+			API("sessions", func() {})
+			data := Type("UserSession", func() {
+				Attribute("user_id", Integer)
+			})
+			scheme = SessionSecurity("session", func() {
+				Cookie("sid")
+				SessionData(data)
+			})
+			res = SessionEndpoints(scheme)
+			dslengine.Run()
+		})
+
+		It("defines the scheme", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(scheme.Kind).Should(Equal(SessionSecurityKind))
+			Ω(scheme.CookieName).Should(Equal("sid"))
+			Ω(scheme.SessionData).ShouldNot(BeNil())
+			Ω(scheme.SessionData.TypeName).Should(Equal("UserSession"))
+		})
+
+		It("defines the login and logout endpoints", func() {
+			Ω(res.SessionScheme()).Should(Equal(scheme))
+			Ω(res.Actions).Should(HaveKey("login"))
+			Ω(res.Actions["login"].Security).Should(BeNil())
+			Ω(res.Actions["logout"].Security.Scheme).Should(Equal(scheme))
+		})
+	})
+
+	Context("with no cookie", func() {
+		var scheme *SecuritySchemeDefinition
+
+		BeforeEach(func() {
+			API("sessions", func() {})
+			scheme = SessionSecurity("session")
+			dslengine.Run()
+		})
+
+		It("uses the default cookie name", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(scheme.CookieName).Should(Equal(DefaultSessionCookie))
+		})
+	})
+
+	Context("with Cookie in another scheme", func() {
+		BeforeEach(func() {
+			API("sessions", func() {})
+			APIKeySecurity("key", func() {
+				Cookie("sid")
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
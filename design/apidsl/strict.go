@@ -0,0 +1,49 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DisallowUnknownFields makes the generated code reject the request bodies that contain attributes
+// the design does not declare. The request fails with a 400 response whose body is the invalid
+// request error listing the unknown attributes, nested objects are checked as well. Request bodies
+// are otherwise decoded leniently and the unknown attributes ignored.
+//
+// DisallowUnknownFields may appear in API, Resource or Action to apply to all the payloads of the
+// corresponding actions, or in Type and Payload to apply to the actions using the payload type.
+// Example:
+//
+//	Resource("bottle", func() {
+//		Action("create", func() {
+//			Routing(POST(""))
+//			Payload(func() {
+//				DisallowUnknownFields()
+//				Member("name")
+//				Required("name")
+//			})
+//			Response(Created)
+//		})
+//	})
+func DisallowUnknownFields() {
+	var md *dslengine.MetadataDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		md = &def.Metadata
+	case *design.ResourceDefinition:
+		md = &def.Metadata
+	case *design.ActionDefinition:
+		md = &def.Metadata
+	case *design.UserTypeDefinition:
+		md = &def.Metadata
+	case *design.AttributeDefinition:
+		md = &def.Metadata
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if *md == nil {
+		*md = make(dslengine.MetadataDefinition)
+	}
+	(*md)[design.StrictMetadata] = []string{}
+}
@@ -0,0 +1,83 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DisallowUnknownFields", func() {
+	var apiDSL, resourceDSL, payloadDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = func() {}
+		resourceDSL = func() {}
+		payloadDSL = func() {}
+	})
+
+	JustBeforeEach(func() {
+		API("strict", apiDSL)
+		Resource("bottle", func() {
+			resourceDSL()
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(func() {
+					payloadDSL()
+					Member("name")
+				})
+				Response(NoContent)
+			})
+			Action("update", func() {
+				Routing(PUT("/:id"))
+				Payload(func() {
+					Member("name")
+				})
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("is disabled by default", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Resources["bottle"].Actions["create"].DisallowUnknownFields()).Should(BeFalse())
+	})
+
+	Context("in a payload", func() {
+		BeforeEach(func() {
+			payloadDSL = DisallowUnknownFields
+		})
+
+		It("applies to the action payload only", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].Actions["create"].DisallowUnknownFields()).Should(BeTrue())
+			Ω(Design.Resources["bottle"].Actions["update"].DisallowUnknownFields()).Should(BeFalse())
+		})
+	})
+
+	Context("in a resource", func() {
+		BeforeEach(func() {
+			resourceDSL = DisallowUnknownFields
+		})
+
+		It("applies to all the resource actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].Actions["create"].DisallowUnknownFields()).Should(BeTrue())
+			Ω(Design.Resources["bottle"].Actions["update"].DisallowUnknownFields()).Should(BeTrue())
+		})
+	})
+
+	Context("in the API", func() {
+		BeforeEach(func() {
+			apiDSL = DisallowUnknownFields
+		})
+
+		It("applies to all the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].Actions["update"].DisallowUnknownFields()).Should(BeTrue())
+		})
+	})
+})
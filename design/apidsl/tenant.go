@@ -0,0 +1,59 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Tenant declares that the API is multi-tenant and describes how requests identify their tenant.
+// The tenant identifier is read either from a header (use Header) or from a path parameter (use
+// Param). The arguments of Header and Param are the same as Attribute's and may define
+// validations for the identifier which must be a string. Path based tenancy prefixes the routes of
+// all the resources with the tenant parameter wildcard unless the API base path already contains
+// it. The generated code extracts and validates the tenant identifier and stores it in the request
+// context.
+//
+// Tenant may only appear in API. Examples:
+//
+//    Tenant(func() {
+//        Description("Tenant owning the resources")
+//        Header("X-Tenant-ID", String, func() {
+//            Pattern("^[a-z0-9-]+$")
+//        })
+//    })
+//
+//    Tenant(func() {
+//        Param("tenant_id") // routes become /:tenant_id/...
+//    })
+//
+func Tenant(dsl func()) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	if api.Tenant != nil {
+		dslengine.ReportError("tenant already defined")
+		return
+	}
+	api.Tenant = &design.TenantDefinition{DSLFunc: dsl}
+}
+
+// tenantAttribute sets the attribute describing the identifier of the current tenant definition.
+// It returns false if the attribute DSL fails.
+func tenantAttribute(name string, args ...interface{}) bool {
+	def, ok := dslengine.CurrentDefinition().(*design.TenantDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return false
+	}
+	if def.Attribute != nil {
+		dslengine.ReportError("tenant identifier already defined through Header or Param")
+		return false
+	}
+	parent := &design.AttributeDefinition{Type: design.Object{}}
+	if !dslengine.Execute(func() { Attribute(name, args...) }, parent) {
+		return false
+	}
+	def.Attribute = parent.Type.ToObject()[name]
+	return def.Attribute != nil
+}
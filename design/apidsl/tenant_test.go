@@ -0,0 +1,89 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tenant", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with a tenant header", func() {
+		BeforeEach(func() {
+			API("saas", func() {
+				Tenant(func() {
+					Description("Tenant owning the resources")
+					Header("X-Tenant-ID", String, func() {
+						Pattern("^[a-z]+$")
+					})
+				})
+			})
+			Resource("res", func() {
+				Action("show", func() {
+					Routing(GET("/"))
+					Response(NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("adds the required tenant header to the resources", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Tenant).ShouldNot(BeNil())
+			Ω(Design.Tenant.Description).Should(Equal("Tenant owning the resources"))
+			Ω(Design.Tenant.Header).Should(Equal("X-Tenant-ID"))
+			headers := Design.Resources["res"].Headers
+			Ω(headers).ShouldNot(BeNil())
+			Ω(headers.Type.ToObject()).Should(HaveKey("X-Tenant-ID"))
+			Ω(headers.IsRequired("X-Tenant-ID")).Should(BeTrue())
+			Ω(headers.Type.ToObject()["X-Tenant-ID"].Validation.Pattern).Should(Equal("^[a-z]+$"))
+		})
+	})
+
+	Context("with a tenant path parameter", func() {
+		BeforeEach(func() {
+			API("saas", func() {
+				BasePath("/api")
+				Tenant(func() {
+					Param("tenant_id")
+				})
+			})
+			Resource("res", func() {
+				BasePath("/res")
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("prefixes the routes with the tenant wildcard", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.BasePath).Should(Equal("/api/:tenant_id"))
+			route := Design.Resources["res"].Actions["show"].Routes[0]
+			Ω(route.FullPath()).Should(Equal("/api/:tenant_id/res/:id"))
+			Ω(Design.Params.Type.ToObject()).Should(HaveKey("tenant_id"))
+		})
+	})
+
+	Context("with no tenant identifier", func() {
+		BeforeEach(func() {
+			API("saas", func() {
+				Tenant(func() {
+					Description("Tenant")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
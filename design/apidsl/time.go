@@ -0,0 +1,49 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// TimeFormat sets the wire representation of a Time, Date or Duration attribute or of the
+// elements of an array of such. The generated fields use time.Time and time.Duration regardless of
+// the format. The supported formats are:
+//
+//	Time:     TimeRFC3339 (default) or TimeUnix (integer number of seconds since the epoch)
+//	Date:     DateISO8601 (default)
+//	Duration: DurationISO8601 (default) or DurationSeconds (number of seconds)
+//
+// TimeFormat may appear in Attribute, Member, Param or Header. Example:
+//
+//	Attribute("created_at", Time, func() {
+//		TimeFormat(TimeUnix)
+//	})
+//	Param("timeout", Duration, func() {
+//		TimeFormat(DurationSeconds)
+//	})
+func TimeFormat(enc design.TimeEncoding) {
+	a, ok := attributeDefinition()
+	if !ok {
+		return
+	}
+	t := a.Type
+	if t != nil && t.IsArray() {
+		t = t.ToArray().ElemType.Type
+	}
+	s := design.TimeScalar(t, enc)
+	if s == nil {
+		name := "untyped attribute"
+		if t != nil {
+			name = t.Name()
+		}
+		dslengine.ReportError("%s does not support the %#v time format", name, string(enc))
+		return
+	}
+	if a.Type.IsArray() {
+		elem := *a.Type.ToArray().ElemType
+		elem.Type = s
+		a.Type = &design.Array{ElemType: &elem}
+		return
+	}
+	a.Type = s
+}
@@ -0,0 +1,55 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TimeFormat", func() {
+	var ut *UserTypeDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("selects the wire representation", func() {
+		ut = Type("Event", func() {
+			Attribute("at", Time)
+			Attribute("created_at", Time, func() {
+				TimeFormat(TimeUnix)
+			})
+			Attribute("timeouts", ArrayOf(Duration), func() {
+				TimeFormat(DurationSeconds)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		obj := ut.Type.ToObject()
+		Ω(obj["at"].Type).Should(Equal(Time))
+		created := obj["created_at"].Type.(*ScalarDefinition)
+		Ω(created.Kind()).Should(Equal(IntegerKind))
+		Ω(created.GoType).Should(Equal("time.Time"))
+		Ω(created.ParseFunc).Should(Equal("goa.ParseUnixTime"))
+		elem := obj["timeouts"].Type.ToArray().ElemType.Type.(*ScalarDefinition)
+		Ω(elem.Kind()).Should(Equal(NumberKind))
+		Ω(elem.GoType).Should(Equal("time.Duration"))
+	})
+
+	It("rejects unsupported formats", func() {
+		Type("Event", func() {
+			Attribute("on", Date, func() {
+				TimeFormat(TimeUnix)
+			})
+			Attribute("name", String, func() {
+				TimeFormat(TimeRFC3339)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring("Date does not support"))
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring("string does not support"))
+	})
+})
@@ -0,0 +1,65 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// TLS defines the TLS requirements of the API servers. TLS may appear in API to set the
+// requirements of the generated service and of all the servers that use TLS, or in Server to
+// override them for that server. The scaffolded service listens with a TLS configuration that
+// enforces the requirements, the generated client can load the certificates needed to satisfy
+// them. Examples:
+//
+//    TLS(func() {
+//        MinVersion("1.2")
+//        ClientCertRequired()
+//    })
+//
+//    Server("production", func() {
+//        URL("https://api.example.com")
+//        TLS(func() {
+//            MinVersion("1.3")
+//        })
+//    })
+//
+func TLS(dsl func()) {
+	def := &design.TLSDefinition{}
+	switch parent := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		if parent.TLS != nil {
+			dslengine.ReportError("TLS requirements already defined")
+			return
+		}
+		def.Parent = parent
+		parent.TLS = def
+	case *design.ServerDefinition:
+		if parent.TLS != nil {
+			dslengine.ReportError("TLS requirements already defined")
+			return
+		}
+		def.Parent = parent
+		parent.TLS = def
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	dslengine.Execute(dsl, def)
+}
+
+// MinVersion sets the minimum TLS protocol version accepted by the servers, one of "1.0", "1.1",
+// "1.2" or "1.3". The default is "1.2". MinVersion may only appear in TLS.
+func MinVersion(version string) {
+	if t, ok := tlsDefinition(); ok {
+		t.MinVersion = version
+	}
+}
+
+// ClientCertRequired specifies that the servers require the clients to authenticate with a
+// certificate signed by a trusted authority (mutual TLS). ClientCertRequired may only appear in
+// TLS.
+func ClientCertRequired() {
+	if t, ok := tlsDefinition(); ok {
+		t.ClientCertRequired = true
+	}
+}
@@ -0,0 +1,87 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	Context("with API and server requirements", func() {
+		BeforeEach(func() {
+			API("secure", func() {
+				TLS(func() {
+					ClientCertRequired()
+				})
+				Server("production", func() {
+					URL("https://api.example.com")
+					TLS(func() {
+						MinVersion("1.3")
+					})
+				})
+				Server("staging", func() {
+					URL("https://staging.example.com")
+				})
+				Server("local", func() {
+					URL("http://localhost:8080")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("sets the TLS requirements", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.TLS).ShouldNot(BeNil())
+			Ω(Design.TLS.ClientCertRequired).Should(BeTrue())
+			Ω(Design.TLS.Version()).Should(Equal(DefaultTLSMinVersion))
+			Ω(Design.Servers[0].TLS.Version()).Should(Equal("1.3"))
+			Ω(Design.Servers[0].TLS.ClientCertRequired).Should(BeFalse())
+		})
+
+		It("applies the API requirements to the servers that use TLS", func() {
+			Ω(Design.Servers[1].TLS).Should(Equal(Design.TLS))
+			Ω(Design.Servers[2].TLS).Should(BeNil())
+		})
+	})
+
+	Context("with an unsupported version", func() {
+		BeforeEach(func() {
+			API("secure", func() {
+				TLS(func() {
+					MinVersion("2.0")
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("unsupported TLS version"))
+		})
+	})
+
+	Context("on a server that does not use TLS", func() {
+		BeforeEach(func() {
+			API("secure", func() {
+				Server("local", func() {
+					URL("http://localhost:8080")
+					TLS(func() {
+						ClientCertRequired()
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("does not use TLS"))
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Trailers defines the HTTP trailers sent after the body of a response, typically a streamed or
+// chunked response whose checksum or record count is only known once the body is written. The
+// DSL syntax is identical to the one of Headers, trailers must be optional primitives.
+//
+// The generated response helpers announce the trailers in the Trailer header and the generated
+// context exposes a Write<Response>Trailers method that sets their values once the body has been
+// written. Streaming handlers that write the body directly call Announce<Response>Trailers before
+// writing it. The generated client exposes a Decode<Action><Resource><Response>Trailers method
+// that reads the trailers once the response body has been read entirely.
+//
+// Trailers must appear in Response or ResponseTemplate. Example:
+//
+//	Response(OK, func() {
+//		Media("application/x-ndjson")
+//		Trailers(func() {
+//			Trailer("X-Checksum", String, "SHA-256 checksum of the body")
+//			Trailer("X-Record-Count", Integer, "Number of records in the body")
+//		})
+//	})
+func Trailers(dsl func()) {
+	r, ok := responseDefinition()
+	if !ok {
+		return
+	}
+	trailers := &design.AttributeDefinition{Type: design.Object{}}
+	if dslengine.Execute(dsl, trailers) {
+		r.Trailers = trailers
+	}
+}
+
+// Trailer is an alias of Attribute used to define a response trailer in Trailers.
+func Trailer(name string, args ...interface{}) {
+	Attribute(name, args...)
+}
@@ -0,0 +1,83 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trailers", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = func() {
+			Trailer("x-checksum", String)
+			Trailer("X-Record-Count", Integer)
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			Action("export", func() {
+				Routing(GET("/export"))
+				Response(OK, "application/x-ndjson", func() {
+					Headers(func() {
+						Header("X-Format")
+					})
+					Trailers(dsl)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the response trailers", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		resp := Design.Resources["bottle"].Actions["export"].Responses["OK"]
+		Ω(resp.Trailers).ShouldNot(BeNil())
+		Ω(resp.TrailerNames()).Should(Equal([]string{"X-Checksum", "X-Record-Count"}))
+	})
+
+	Context("with a trailer that is not a primitive", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Trailer("X-Ids", ArrayOf(Integer))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("trailer X-Ids must be a string, integer, number, boolean, UUID or date time"))
+		})
+	})
+
+	Context("with a required trailer", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Trailer("X-Checksum", String)
+				Required("X-Checksum")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("trailer X-Checksum cannot be required"))
+		})
+	})
+
+	Context("with a trailer also defined as a header", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Trailer("x-format", String)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("trailer x-format is also defined as a header"))
+		})
+	})
+})
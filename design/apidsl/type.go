@@ -28,10 +28,11 @@ import (
 //
 // This function returns the newly defined type so the value can be used throughout the dsl.
 func Type(name string, dsl func()) *design.UserTypeDefinition {
+	name = importedName(name)
 	if design.Design.Types == nil {
 		design.Design.Types = make(map[string]*design.UserTypeDefinition)
-	} else if _, ok := design.Design.Types[name]; ok {
-		dslengine.ReportError("type %#v defined twice", name)
+	} else if t, ok := design.Design.Types[name]; ok {
+		dslengine.ReportError("type %#v defined twice%s", name, conflict(t.Module))
 		return nil
 	}
 
@@ -43,6 +44,8 @@ func Type(name string, dsl func()) *design.UserTypeDefinition {
 	t := &design.UserTypeDefinition{
 		TypeName:            name,
 		AttributeDefinition: &design.AttributeDefinition{DSLFunc: dsl},
+		Location:            dslengine.CallerLocation(),
+		Module:              importedModule(),
 	}
 	if dsl == nil {
 		t.Type = design.String
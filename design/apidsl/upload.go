@@ -0,0 +1,60 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ResumableUpload makes the action receive files using the tus resumable upload protocol
+// (https://tus.io/protocols/resumable-upload.html). The action routes create the uploads, the
+// generated code also mounts the HEAD and PATCH routes that report the upload offsets and receive
+// the upload chunks under the action routes paths followed by "/:upload_id". The chunks are
+// written to the store registered with upload.UseStore (see the middleware/upload package) and
+// the action is invoked once an upload completes, its context exposes the completed upload in the
+// Upload field. ResumableUpload actions must use POST routes and define a NoContent response.
+//
+// The optional DSL lists the upload metadata sent by the clients in the Upload-Metadata header
+// using Attribute and Required, metadata attributes must be strings. MaxUploadSize limits the
+// size of the uploads. Example:
+//
+//	Action("upload", func() {
+//		Routing(POST("/uploads"))
+//		ResumableUpload(func() {
+//			MaxUploadSize(1 << 30)
+//			Attribute("filename", String)
+//			Attribute("filetype", String)
+//			Required("filename")
+//		})
+//		Response(NoContent)
+//	})
+func ResumableUpload(dsl ...func()) {
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments in call to ResumableUpload")
+		return
+	}
+	a, ok := actionDefinition()
+	if !ok {
+		return
+	}
+	if a.Upload != nil {
+		dslengine.ReportError("resumable upload already defined")
+		return
+	}
+	u := &design.UploadDefinition{
+		Metadata: &design.AttributeDefinition{Type: design.Object{}},
+		Parent:   a,
+	}
+	a.Upload = u
+	if len(dsl) == 1 {
+		dslengine.Execute(dsl[0], u)
+	}
+}
+
+// MaxUploadSize sets the maximum size in bytes of the uploads received by the action, creation
+// requests announcing bigger uploads are rejected with a 413 response. MaxUploadSize may only
+// appear in ResumableUpload.
+func MaxUploadSize(size int64) {
+	if u, ok := uploadDefinition(); ok {
+		u.MaxSize = size
+	}
+}
@@ -0,0 +1,123 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResumableUpload", func() {
+	var dsl, actionDSL func()
+	var verb func(string) *RouteDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = POST
+		actionDSL = nil
+		dsl = func() {
+			ResumableUpload(func() {
+				MaxUploadSize(1 << 20)
+				Attribute("filetype", String)
+				Attribute("filename", String, "Name of uploaded file")
+				Required("filename")
+			})
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("file", func() {
+			BasePath("/files")
+			Action("upload", func() {
+				Routing(verb("/uploads"))
+				dsl()
+				if actionDSL != nil {
+					actionDSL()
+				}
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the upload settings", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		u := Design.Resources["file"].Actions["upload"].Upload
+		Ω(u).ShouldNot(BeNil())
+		Ω(u.MaxSize).Should(Equal(int64(1 << 20)))
+		Ω(u.Metadata.Type.ToObject()).Should(HaveLen(2))
+		Ω(u.RequiredMetadata()).Should(Equal([]string{"filename"}))
+		Ω(u.Paths()).Should(Equal([]string{"/files/uploads/:upload_id"}))
+	})
+
+	Context("with no DSL", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				ResumableUpload()
+			}
+		})
+
+		It("accepts uploads with no metadata", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			u := Design.Resources["file"].Actions["upload"].Upload
+			Ω(u.MaxSize).Should(BeZero())
+			Ω(u.RequiredMetadata()).Should(BeEmpty())
+		})
+	})
+
+	Context("with metadata that is not a string", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				ResumableUpload(func() {
+					Attribute("size", Integer)
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`upload metadata "size" must be a string`))
+		})
+	})
+
+	Context("with a non POST route", func() {
+		BeforeEach(func() {
+			verb = PUT
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("resumable upload actions may only have POST routes, got PUT"))
+		})
+	})
+
+	Context("with a payload", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Payload(func() {
+					Attribute("name", String)
+				})
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("resumable upload actions cannot define a payload"))
+		})
+	})
+
+	Context("used twice", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				ResumableUpload()
+				ResumableUpload()
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("resumable upload already defined"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package apidsl
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Const declares a design constant with the given name and value and returns the value. Design
+// constants make it possible to name values used throughout the design such as base paths or
+// default page sizes. Unlike design variables (see Var) constants cannot be given a different
+// value at generation time. The value must be a string, a bool, an int or a float64. Const should
+// appear at the top level of the design. Example:
+//
+//	var DefaultPageSize = Const("default_page_size", 20).(int)
+//
+//	var _ = Resource("bottle", func() {
+//		Action("list", func() {
+//			Routing(GET(""))
+//			Params(func() {
+//				Param("limit", Integer, func() {
+//					Default(DefaultPageSize)
+//				})
+//			})
+//		})
+//	})
+func Const(name string, value interface{}) interface{} {
+	if err := checkVarType(value); err != nil {
+		dslengine.ReportError("invalid value for design constant %s: %s", name, err)
+		return value
+	}
+	dslengine.DeclareVar(name, false)
+	return value
+}
+
+// Var declares a design variable with the given name and default value and returns the value of
+// the variable. Design variables parameterize designs shared by multiple deployments: the goagen
+// --vars flag sets their values with a comma separated list of name=value pairs. The value given
+// at generation time is converted to the type of the default value which must be a string, a
+// bool, an int or a float64. Var should appear at the top level of the design. Example:
+//
+//	var (
+//		Host     = Var("host", "localhost:8080").(string)
+//		BasePath = Var("base_path", "/cellar").(string)
+//	)
+//
+//	var _ = API("cellar", func() {
+//		Host(Host)
+//		BasePath(BasePath)
+//	})
+//
+// generates the code for the production deployment when running:
+//
+//	goagen bootstrap -d github.com/example/cellar/design --vars host=cellar.example.com,base_path=/v1
+func Var(name string, value interface{}) interface{} {
+	if err := checkVarType(value); err != nil {
+		dslengine.ReportError("invalid default value for design variable %s: %s", name, err)
+		return value
+	}
+	val, ok := dslengine.DeclareVar(name, true)
+	if !ok {
+		return value
+	}
+	v, err := parseVar(val, value)
+	if err != nil {
+		dslengine.ReportError("invalid value %q for design variable %s: %s", val, name, err)
+		return value
+	}
+	return v
+}
+
+// checkVarType returns an error if the type of the given value is not supported by design
+// constants and variables.
+func checkVarType(value interface{}) error {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %T, must be string, bool, int or float64", value)
+	}
+}
+
+// parseVar converts val to the type of the default value def.
+func parseVar(val string, def interface{}) (interface{}, error) {
+	switch def.(type) {
+	case bool:
+		return strconv.ParseBool(val)
+	case int:
+		return strconv.Atoi(val)
+	case float64:
+		return strconv.ParseFloat(val, 64)
+	default:
+		return val, nil
+	}
+}
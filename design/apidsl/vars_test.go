@@ -0,0 +1,114 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Const and Var", func() {
+	var overrides, prev map[string]string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dslengine.UndeclareVars()
+		overrides = nil
+		prev = dslengine.VarOverrides
+	})
+
+	JustBeforeEach(func() {
+		dslengine.VarOverrides = overrides
+	})
+
+	AfterEach(func() {
+		dslengine.VarOverrides = prev
+		dslengine.UndeclareVars()
+	})
+
+	Context("with no value given at generation time", func() {
+		It("returns the default values", func() {
+			Ω(Const("page_size", 20)).Should(Equal(20))
+			Ω(Var("host", "localhost:8080")).Should(Equal("localhost:8080"))
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with values given at generation time", func() {
+		BeforeEach(func() {
+			overrides = map[string]string{"host": "cellar.example.com", "debug": "true", "limit": "50", "ratio": "0.5"}
+		})
+
+		It("returns the values converted to the type of the default values", func() {
+			Ω(Var("host", "localhost:8080")).Should(Equal("cellar.example.com"))
+			Ω(Var("debug", false)).Should(Equal(true))
+			Ω(Var("limit", 20)).Should(Equal(50))
+			Ω(Var("ratio", 1.0)).Should(Equal(0.5))
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("parameterizes the design", func() {
+			host := Var("host", "localhost:8080").(string)
+			limit := Var("limit", 20).(int)
+			Var("debug", false)
+			Var("ratio", 1.0)
+			API("test", func() {
+				Host(host)
+			})
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Param("limit", Integer, func() {
+							Default(limit)
+						})
+					})
+				})
+			})
+			Ω(dslengine.Run()).Should(Succeed())
+			Ω(Design.Host).Should(Equal("cellar.example.com"))
+			Ω(Design.Resources["bottle"].Actions["list"].Params.Type.ToObject()["limit"].DefaultValue).Should(Equal(50))
+		})
+
+		It("reports values given to undeclared variables", func() {
+			Var("host", "localhost:8080")
+			API("test", nil)
+			Ω(dslengine.Run()).Should(HaveOccurred())
+			Ω(dslengine.Errors).Should(HaveLen(3))
+			Ω(dslengine.Errors[0].Error()).Should(Equal("unknown design variable debug"))
+		})
+
+		It("reports invalid values", func() {
+			Var("host", 8080)
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid value "cellar.example.com" for design variable host`))
+		})
+	})
+
+	Context("with a value given to a constant", func() {
+		BeforeEach(func() {
+			overrides = map[string]string{"limit": "50"}
+		})
+
+		It("reports an error", func() {
+			Ω(Const("limit", 20)).Should(Equal(20))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("design constant limit cannot be given a value at generation time"))
+		})
+	})
+
+	Context("with an unsupported type", func() {
+		It("reports an error", func() {
+			Var("tags", []string{"a"})
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("unsupported type []string"))
+		})
+	})
+
+	Context("with a name declared twice", func() {
+		It("reports an error", func() {
+			Const("limit", 20)
+			Var("limit", 20)
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("design variable or constant limit already declared"))
+		})
+	})
+})
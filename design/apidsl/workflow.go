@@ -0,0 +1,95 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Workflow documents a multi-endpoint flow made of steps that each call an action of the API. A
+// step may define the action that undoes it, the compensating actions of the completed steps are
+// called in reverse order when a later step fails (saga pattern). The code generator produces a
+// typed state machine that runs the steps and the compensation hooks, the service provides the
+// implementation of each step. The docs generator renders a sequence diagram of the workflow.
+//
+// Workflow may only appear in API, the steps are given with Steps. Workflow also accepts DSL
+// functions that set its description. Example:
+//
+//	Workflow("order_fulfillment", Steps(
+//		Step("reserve", "inventory", "reserve", CompensatedBy("inventory", "release")),
+//		Step("charge", "payment", "charge", CompensatedBy("payment", "refund")),
+//		Step("ship", "shipment", "create"),
+//	), func() {
+//		Description("Reserves the ordered items, charges the customer and ships the order")
+//	})
+func Workflow(name string, dsl ...func()) {
+	api, ok := apiDefinition()
+	if !ok {
+		return
+	}
+	for _, w := range api.Workflows {
+		if w.Name == name {
+			dslengine.ReportError("cannot redefine workflow with name %q", name)
+			return
+		}
+	}
+	api.Workflows = append(api.Workflows, &design.WorkflowDefinition{
+		Name: name,
+		DSLFunc: func() {
+			for _, d := range dsl {
+				d()
+			}
+		},
+	})
+}
+
+// Steps defines the steps of a workflow in execution order, see Workflow.
+func Steps(steps ...func()) func() {
+	return func() {
+		w, ok := dslengine.CurrentDefinition().(*design.WorkflowDefinition)
+		if !ok {
+			dslengine.IncompatibleDSL()
+			return
+		}
+		if len(w.Steps) > 0 {
+			dslengine.ReportError("steps already defined")
+			return
+		}
+		for _, s := range steps {
+			s()
+		}
+	}
+}
+
+// Step defines a workflow step named name that calls the action of the resource. The optional
+// settings define how the step is undone, see CompensatedBy. Step may only be used as an argument
+// of Steps.
+func Step(name, resource, action string, settings ...func()) func() {
+	return func() {
+		w, ok := dslengine.CurrentDefinition().(*design.WorkflowDefinition)
+		if !ok {
+			dslengine.IncompatibleDSL()
+			return
+		}
+		s := &design.WorkflowStepDefinition{Name: name, Resource: resource, Action: action, Parent: w}
+		w.Steps = append(w.Steps, s)
+		for _, setting := range settings {
+			if !dslengine.Execute(setting, s) {
+				return
+			}
+		}
+	}
+}
+
+// CompensatedBy sets the action of the resource that undoes a workflow step when a later step
+// fails. CompensatedBy may only be used as an argument of Step.
+func CompensatedBy(resource, action string) func() {
+	return func() {
+		s, ok := dslengine.CurrentDefinition().(*design.WorkflowStepDefinition)
+		if !ok {
+			dslengine.IncompatibleDSL()
+			return
+		}
+		s.CompensationResource = resource
+		s.CompensationAction = action
+	}
+}
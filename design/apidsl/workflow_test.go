@@ -0,0 +1,131 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Workflow", func() {
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = func() {
+			Workflow("order_fulfillment", Steps(
+				Step("reserve", "inventory", "reserve", CompensatedBy("inventory", "release")),
+				Step("ship", "shipment", "create"),
+			), func() {
+				Description("Fulfills orders")
+			})
+		}
+	})
+
+	JustBeforeEach(func() {
+		API("shop", dsl)
+		Resource("inventory", func() {
+			Action("reserve", func() {
+				Routing(POST("/reservations"))
+			})
+			Action("release", func() {
+				Routing(DELETE("/reservations/:id"))
+			})
+		})
+		Resource("shipment", func() {
+			Action("create", func() {
+				Routing(POST("/shipments"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("defines the workflow steps", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Workflows).Should(HaveLen(1))
+		w := Design.Workflows[0]
+		Ω(w.Name).Should(Equal("order_fulfillment"))
+		Ω(w.Description).Should(Equal("Fulfills orders"))
+		Ω(w.Steps).Should(HaveLen(2))
+		Ω(w.Steps[0].CallAction()).Should(Equal(Design.Resources["inventory"].Actions["reserve"]))
+		Ω(w.Steps[0].CompensationCallAction()).Should(Equal(Design.Resources["inventory"].Actions["release"]))
+		Ω(w.Steps[1].Name).Should(Equal("ship"))
+		Ω(w.Steps[1].CompensationCallAction()).Should(BeNil())
+	})
+
+	Context("with an unknown action", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Workflow("order_fulfillment", Steps(
+					Step("reserve", "inventory", "reserve", CompensatedBy("inventory", "cancel")),
+					Step("ship", "shipping", "create"),
+				))
+			}
+		})
+
+		It("produces errors", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown compensating action "cancel" of resource "inventory"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unknown action "create" of resource "shipping"`))
+		})
+	})
+
+	Context("with duplicate steps", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Workflow("order_fulfillment", Steps(
+					Step("reserve", "inventory", "reserve"),
+					Step("reserve", "inventory", "reserve"),
+				))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`duplicate step "reserve"`))
+		})
+	})
+
+	Context("with a reserved step name", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Workflow("order_fulfillment", Steps(
+					Step("completed", "inventory", "reserve"),
+				))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`step name "completed" is reserved`))
+		})
+	})
+
+	Context("with no step", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Workflow("order_fulfillment")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("workflow must define at least one step"))
+		})
+	})
+
+	Context("defined twice", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Workflow("order_fulfillment", Steps(Step("reserve", "inventory", "reserve")))
+				Workflow("order_fulfillment", Steps(Step("reserve", "inventory", "reserve")))
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`cannot redefine workflow with name "order_fulfillment"`))
+		})
+	})
+})
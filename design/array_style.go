@@ -0,0 +1,54 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// ParamStyle describes how the values of array parameters are serialized in the request URLs.
+type ParamStyle string
+
+const (
+	// StyleRepeated serializes each value as a separate parameter: ?id=1&id=2. This is the
+	// default style.
+	StyleRepeated ParamStyle = "repeated"
+	// StyleComma serializes the values as a single comma separated parameter: ?id=1,2.
+	StyleComma ParamStyle = "comma"
+	// StylePipe serializes the values as a single pipe separated parameter: ?id=1|2.
+	StylePipe ParamStyle = "pipe"
+	// StyleSpace serializes the values as a single space separated parameter: ?id=1%202.
+	StyleSpace ParamStyle = "space"
+)
+
+// ArrayStyleMetadata is the metadata key set by ArrayStyle on the array parameters that declare a
+// style.
+const ArrayStyleMetadata = "param:style"
+
+// Delimiter returns the string that separates the values serialized with the style, empty for
+// StyleRepeated.
+func (s ParamStyle) Delimiter() string {
+	switch s {
+	case StyleComma:
+		return ","
+	case StylePipe:
+		return "|"
+	case StyleSpace:
+		return " "
+	}
+	return ""
+}
+
+// ArrayStyle returns the style used to serialize the values of the array parameter.
+func (a *AttributeDefinition) ArrayStyle() ParamStyle {
+	if s, ok := a.Metadata[ArrayStyleMetadata]; ok && len(s) > 0 {
+		return ParamStyle(s[0])
+	}
+	return StyleRepeated
+}
+
+// validateArrayStyle makes sure the style is only set on array attributes.
+func (a *AttributeDefinition) validateArrayStyle(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	if _, ok := a.Metadata[ArrayStyleMetadata]; !ok {
+		return
+	}
+	if !a.Type.IsArray() {
+		verr.Add(parent, "%sarray style may only be set on array attributes", ctx)
+	}
+}
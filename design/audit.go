@@ -0,0 +1,92 @@
+package design
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// AuditRedactMetadata is the metadata key that marks payload attributes whose values are masked
+// in the audit events, e.g. Metadata("audit:redact").
+const AuditRedactMetadata = "audit:redact"
+
+// AuditDefinition describes the audit event emitted after each call to an action.
+type AuditDefinition struct {
+	// Event is the name of the audit event, e.g. "bottle.update".
+	Event string
+	// Redact lists the paths to the payload attributes whose values are masked in the audit
+	// event payload snapshot, e.g. "password" or "card.number".
+	Redact []string
+	// Parent is the audited action.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (a *AuditDefinition) Context() string {
+	if a.Parent != nil {
+		return fmt.Sprintf("audit of %s", a.Parent.Context())
+	}
+	return "audit"
+}
+
+// RedactedPaths returns the paths to the payload attributes masked in the audit events: the
+// paths listed with Redact followed by the top level payload attributes tagged with the
+// AuditRedactMetadata metadata or encrypted with Encrypted.
+func (a *AuditDefinition) RedactedPaths() []string {
+	paths := append([]string{}, a.Redact...)
+	if a.Parent == nil || a.Parent.Payload == nil {
+		return paths
+	}
+	a.Parent.Payload.Type.ToObject().IterateAttributes(func(n string, att *AttributeDefinition) error {
+		if _, ok := att.Metadata[AuditRedactMetadata]; !ok && att.EncryptionKey() == "" {
+			return nil
+		}
+		for _, p := range paths {
+			if p == n {
+				return nil
+			}
+		}
+		paths = append(paths, n)
+		return nil
+	})
+	return paths
+}
+
+// Validate makes sure the audit event has a name and that the redacted paths refer to payload
+// attributes.
+func (a *AuditDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if a.Event == "" {
+		verr.Add(a, "audit event name cannot be empty")
+	}
+	for _, p := range a.Redact {
+		var payload *AttributeDefinition
+		if a.Parent != nil && a.Parent.Payload != nil {
+			payload = a.Parent.Payload.AttributeDefinition
+		}
+		if !hasAttributePath(payload, strings.Split(p, ".")) {
+			verr.Add(a, "redacted attribute %#v is not a payload attribute", p)
+		}
+	}
+	return verr.AsError()
+}
+
+// hasAttributePath returns true if the attribute path exists in att, array elements are
+// traversed transparently.
+func hasAttributePath(att *AttributeDefinition, path []string) bool {
+	if att == nil {
+		return false
+	}
+	if len(path) == 0 {
+		return true
+	}
+	if arr := att.Type.ToArray(); arr != nil {
+		return hasAttributePath(arr.ElemType, path)
+	}
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return false
+	}
+	return hasAttributePath(obj[path[0]], path[1:])
+}
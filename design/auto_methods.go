@@ -0,0 +1,125 @@
+package design
+
+import "sort"
+
+// AutoHeadPaths returns the full paths of the GET routes of the action that get a generated HEAD
+// handler, see AutoHead. It returns nil unless the API enables AutoHead.
+func (a *ActionDefinition) AutoHeadPaths() []string {
+	if !Design.AutoHead {
+		return nil
+	}
+	methods := Design.routeMethods()
+	var paths []string
+	for _, r := range a.Routes {
+		if r.Verb != "GET" {
+			continue
+		}
+		p := r.FullPath()
+		if !methods[p]["HEAD"] {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// AutoOptionsPaths returns the full paths of the resource routes that get a generated OPTIONS
+// handler, see AutoOptions. Paths shared by multiple resources are only returned for the first
+// resource in alphabetical order. Paths with an explicit OPTIONS route or belonging to a resource
+// with a CORS policy (which already handles preflight OPTIONS requests) are skipped. It returns
+// nil unless the API enables AutoOptions.
+func (r *ResourceDefinition) AutoOptionsPaths() []string {
+	if !Design.AutoOptions {
+		return nil
+	}
+	methods := Design.routeMethods()
+	owners := make(map[string]string)
+	excluded := make(map[string]bool)
+	Design.IterateResources(func(res *ResourceDefinition) error {
+		cors := len(res.AllOrigins()) > 0
+		for _, p := range res.routePaths() {
+			if cors || methods[p]["OPTIONS"] {
+				excluded[p] = true
+			}
+			if _, ok := owners[p]; !ok {
+				owners[p] = res.Name
+			}
+		}
+		return nil
+	})
+	var paths []string
+	for _, p := range r.routePaths() {
+		if !excluded[p] && owners[p] == r.Name {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// AllowedMethods returns the HTTP methods of the routes with the given full path including the
+// generated HEAD and OPTIONS handlers sorted alphabetically.
+func (a *APIDefinition) AllowedMethods(path string) []string {
+	set := a.routeMethods()[path]
+	if set == nil {
+		return nil
+	}
+	var methods []string
+	for m := range set {
+		methods = append(methods, m)
+	}
+	if a.AutoHead && set["GET"] && !set["HEAD"] {
+		methods = append(methods, "HEAD")
+	}
+	if a.AutoOptions && !set["OPTIONS"] {
+		methods = append(methods, "OPTIONS")
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// routeMethods returns the HTTP methods of the API routes and file servers indexed by full path.
+func (a *APIDefinition) routeMethods() map[string]map[string]bool {
+	methods := make(map[string]map[string]bool)
+	add := func(path, verb string) {
+		if methods[path] == nil {
+			methods[path] = make(map[string]bool)
+		}
+		methods[path][verb] = true
+	}
+	a.IterateResources(func(r *ResourceDefinition) error {
+		r.IterateActions(func(ac *ActionDefinition) error {
+			for _, ro := range ac.Routes {
+				add(ro.FullPath(), ro.Verb)
+			}
+			return nil
+		})
+		r.IterateFileServers(func(fs *FileServerDefinition) error {
+			add(fs.RequestPath, "GET")
+			return nil
+		})
+		return nil
+	})
+	return methods
+}
+
+// routePaths returns the distinct full paths of the resource routes and file servers in order.
+func (r *ResourceDefinition) routePaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	r.IterateActions(func(ac *ActionDefinition) error {
+		for _, ro := range ac.Routes {
+			add(ro.FullPath())
+		}
+		return nil
+	})
+	r.IterateFileServers(func(fs *FileServerDefinition) error {
+		add(fs.RequestPath)
+		return nil
+	})
+	return paths
+}
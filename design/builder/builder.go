@@ -0,0 +1,283 @@
+/*
+Package builder provides a typed API for constructing goa designs directly from Go code without
+running DSL functions. It is intended for tools that generate designs dynamically, for example
+from parsed external specifications. The designs built with this package go through the same
+validation and finalization passes as designs defined with the DSL. Example:
+
+	b := builder.New("cellar")
+	bottle := b.MediaType("Bottle", "application/vnd.goa.bottle+json", design.Object{
+		"id":   &design.AttributeDefinition{Type: design.Integer},
+		"name": &design.AttributeDefinition{Type: design.String},
+	}, "id").View("default", "id", "name")
+	b.Resource("bottle", "/bottles").
+		Action("show", "GET", "/:id").
+		Param("id", design.Integer, true).
+		Response(design.OK, 0, bottle.Definition(), "default")
+	api, err := b.Build()
+
+The definitions returned by the builder may be modified directly to set properties the builder
+does not cover.
+*/
+package builder
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	_ "github.com/goadesign/goa/design/apidsl" // Registers the design DSL roots
+	"github.com/goadesign/goa/dslengine"
+)
+
+type (
+	// Builder builds an API design.
+	Builder struct {
+		api *design.APIDefinition
+	}
+
+	// MediaTypeBuilder builds a media type definition.
+	MediaTypeBuilder struct {
+		mt *design.MediaTypeDefinition
+	}
+
+	// ResourceBuilder builds a resource definition.
+	ResourceBuilder struct {
+		b *Builder
+		r *design.ResourceDefinition
+	}
+
+	// ActionBuilder builds an action definition.
+	ActionBuilder struct {
+		b *Builder
+		a *design.ActionDefinition
+	}
+)
+
+// New returns a builder for the API with the given name.
+func New(name string) *Builder {
+	api := design.NewAPIDefinition()
+	api.Name = name
+	return &Builder{api: api}
+}
+
+// API returns the API definition being built.
+func (b *Builder) API() *design.APIDefinition {
+	return b.api
+}
+
+// Type adds a user type with the given name, attributes and required attributes to the design.
+func (b *Builder) Type(name string, attributes design.Object, required ...string) *design.UserTypeDefinition {
+	if b.api.Types == nil {
+		b.api.Types = make(map[string]*design.UserTypeDefinition)
+	}
+	ut := &design.UserTypeDefinition{
+		AttributeDefinition: object(attributes, required),
+		TypeName:            name,
+	}
+	b.api.Types[name] = ut
+	return ut
+}
+
+// MediaType adds a media type with the given type name, identifier, attributes and required
+// attributes to the design. Use View to define the media type views.
+func (b *Builder) MediaType(typeName, identifier string, attributes design.Object, required ...string) *MediaTypeBuilder {
+	if b.api.MediaTypes == nil {
+		b.api.MediaTypes = make(map[string]*design.MediaTypeDefinition)
+	}
+	mt := design.NewMediaTypeDefinition(typeName, identifier, nil)
+	mt.AttributeDefinition = object(attributes, required)
+	b.api.MediaTypes[design.CanonicalIdentifier(identifier)] = mt
+	return &MediaTypeBuilder{mt: mt}
+}
+
+// View adds a view rendering the given attributes to the media type. All media types must define
+// a "default" view.
+func (m *MediaTypeBuilder) View(name string, attributes ...string) *MediaTypeBuilder {
+	if m.mt.Views == nil {
+		m.mt.Views = make(map[string]*design.ViewDefinition)
+	}
+	obj := m.mt.Type.ToObject()
+	vobj := make(design.Object, len(attributes))
+	for _, n := range attributes {
+		att, ok := obj[n]
+		if !ok {
+			// Left for validation to report
+			att = &design.AttributeDefinition{}
+		}
+		vobj[n] = design.DupAtt(att)
+	}
+	m.mt.Views[name] = &design.ViewDefinition{
+		AttributeDefinition: &design.AttributeDefinition{Type: vobj},
+		Name:                name,
+		Parent:              m.mt,
+	}
+	return m
+}
+
+// Definition returns the media type definition being built.
+func (m *MediaTypeBuilder) Definition() *design.MediaTypeDefinition {
+	return m.mt
+}
+
+// Resource adds a resource with the given name and base path to the design.
+func (b *Builder) Resource(name, basePath string) *ResourceBuilder {
+	if b.api.Resources == nil {
+		b.api.Resources = make(map[string]*design.ResourceDefinition)
+	}
+	r := design.NewResourceDefinition(name, nil)
+	r.BasePath = basePath
+	b.api.Resources[name] = r
+	return &ResourceBuilder{b: b, r: r}
+}
+
+// DefaultMedia sets the resource default media type and view.
+func (r *ResourceBuilder) DefaultMedia(mt *design.MediaTypeDefinition, view string) *ResourceBuilder {
+	r.r.MediaType = mt.Identifier
+	r.r.DefaultViewName = view
+	mt.Resource = r.r
+	return r
+}
+
+// Definition returns the resource definition being built.
+func (r *ResourceBuilder) Definition() *design.ResourceDefinition {
+	return r.r
+}
+
+// Action adds an action with the given name and route to the resource. Action returns the
+// existing action with the given name if any, adding the route to it.
+func (r *ResourceBuilder) Action(name, verb, path string) *ActionBuilder {
+	if r.r.Actions == nil {
+		r.r.Actions = make(map[string]*design.ActionDefinition)
+	}
+	a, ok := r.r.Actions[name]
+	if !ok {
+		a = &design.ActionDefinition{Parent: r.r, Name: name}
+		r.r.Actions[name] = a
+	}
+	ab := &ActionBuilder{b: r.b, a: a}
+	return ab.Route(verb, path)
+}
+
+// Route adds a route to the action.
+func (a *ActionBuilder) Route(verb, path string) *ActionBuilder {
+	a.a.Routes = append(a.a.Routes, &design.RouteDefinition{
+		Verb:   strings.ToUpper(verb),
+		Path:   path,
+		Parent: a.a,
+	})
+	return a
+}
+
+// Param adds a path or query string parameter to the action.
+func (a *ActionBuilder) Param(name string, t design.DataType, required bool) *ActionBuilder {
+	if a.a.Params == nil {
+		a.a.Params = &design.AttributeDefinition{Type: make(design.Object)}
+	}
+	a.a.Params.Type.ToObject()[name] = &design.AttributeDefinition{Type: t}
+	if required {
+		if a.a.Params.Validation == nil {
+			a.a.Params.Validation = &dslengine.ValidationDefinition{}
+		}
+		a.a.Params.Validation.AddRequired([]string{name})
+	}
+	return a
+}
+
+// Payload sets the action request payload. User types are used as is, other types are wrapped in
+// a user type named after the action and resource, e.g. "CreateBottlePayload".
+func (a *ActionBuilder) Payload(t design.DataType) *ActionBuilder {
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		a.a.Payload = actual
+	case *design.MediaTypeDefinition:
+		a.a.Payload = a.payload(design.DupAtt(actual.AttributeDefinition))
+	default:
+		a.a.Payload = a.payload(&design.AttributeDefinition{Type: t})
+	}
+	return a
+}
+
+// Response adds a response to the action. A status of 0 uses the status of the API or standard
+// response with the same name, e.g. design.OK or design.NotFound. The view is only used if t is a
+// media type.
+func (a *ActionBuilder) Response(name string, status int, t design.DataType, view string) *ActionBuilder {
+	if a.a.Responses == nil {
+		a.a.Responses = make(map[string]*design.ResponseDefinition)
+	}
+	var resp *design.ResponseDefinition
+	if r, ok := a.b.api.Responses[name]; ok {
+		resp = r.Dup()
+	} else if r, ok := a.b.api.DefaultResponses[name]; ok {
+		resp = r.Dup()
+	} else {
+		resp = &design.ResponseDefinition{Name: name}
+	}
+	if status != 0 {
+		resp.Status = status
+	}
+	if t != nil {
+		if mt, ok := t.(*design.MediaTypeDefinition); ok {
+			resp.MediaType = mt.Identifier
+			resp.ViewName = view
+		}
+		resp.Type = t
+	}
+	resp.Parent = a.a
+	a.a.Responses[name] = resp
+	return a
+}
+
+// Definition returns the action definition being built.
+func (a *ActionBuilder) Definition() *design.ActionDefinition {
+	return a.a
+}
+
+// Build validates and finalizes the design and returns the resulting API definition. The design
+// is evaluated in its own dslengine.Context so that building does not affect the global design.
+func (b *Builder) Build() (*design.APIDefinition, error) {
+	ctx := dslengine.NewContext()
+	var err error
+	evalErr := ctx.Eval(func() {
+		*design.Design = *b.api
+		err = dslengine.Run()
+	})
+	if err == nil {
+		err = evalErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return design.DesignIn(ctx), nil
+}
+
+// payload returns the user type wrapping the given payload attribute.
+func (a *ActionBuilder) payload(att *design.AttributeDefinition) *design.UserTypeDefinition {
+	return &design.UserTypeDefinition{
+		AttributeDefinition: att,
+		TypeName:            fmt.Sprintf("%s%sPayload", camelize(a.a.Name), camelize(a.a.Parent.Name)),
+	}
+}
+
+// object returns an object attribute with the given attributes and required attributes.
+func object(attributes design.Object, required []string) *design.AttributeDefinition {
+	if attributes == nil {
+		attributes = make(design.Object)
+	}
+	att := &design.AttributeDefinition{Type: attributes}
+	if len(required) > 0 {
+		att.Validation = &dslengine.ValidationDefinition{Required: required}
+	}
+	return att
+}
+
+// camelize returns the CamelCase version of the given name, e.g. "BottleList" for "bottle_list".
+func camelize(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, p := range parts {
+		parts[i] = strings.Title(p)
+	}
+	return strings.Join(parts, "")
+}
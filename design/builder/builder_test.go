@@ -0,0 +1,84 @@
+package builder_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/builder"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Builder", func() {
+	var b *builder.Builder
+	var bottle *builder.MediaTypeBuilder
+	var api *APIDefinition
+	var err error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		b = builder.New("cellar")
+		bottle = b.MediaType("Bottle", "application/vnd.goa.bottle+json", Object{
+			"id":   &AttributeDefinition{Type: Integer},
+			"name": &AttributeDefinition{Type: String},
+		}, "id").View("default", "id", "name")
+	})
+
+	JustBeforeEach(func() {
+		api, err = b.Build()
+	})
+
+	Context("with a valid design", func() {
+		BeforeEach(func() {
+			b.Type("BottleInput", Object{
+				"name": &AttributeDefinition{Type: String},
+			}, "name")
+			r := b.Resource("bottle", "/bottles").DefaultMedia(bottle.Definition(), "default")
+			r.Action("show", "GET", "/:id").
+				Param("id", Integer, true).
+				Response(OK, 0, bottle.Definition(), "default").
+				Response(NotFound, 0, nil, "")
+			r.Action("create", "POST", "").
+				Payload(b.API().Types["BottleInput"]).
+				Response(Created, 0, nil, "")
+			r.Action("rate", "PUT", "/:id/rating").
+				Payload(Integer).
+				Response(NoContent, 0, nil, "")
+		})
+
+		It("builds the design", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(api).ShouldNot(BeNil())
+			Ω(api.Name).Should(Equal("cellar"))
+			Ω(api.MediaTypes).Should(HaveKey("application/vnd.goa.bottle"))
+			res := api.Resources["bottle"]
+			Ω(res).ShouldNot(BeNil())
+			show := res.Actions["show"]
+			Ω(show.Routes).Should(HaveLen(1))
+			Ω(show.Routes[0].FullPath()).Should(Equal("/bottles/:id"))
+			Ω(show.Responses[OK].Status).Should(Equal(200))
+			Ω(show.Responses[OK].MediaType).Should(Equal("application/vnd.goa.bottle+json"))
+			Ω(show.Responses[NotFound].Status).Should(Equal(404))
+			Ω(res.Actions["create"].Payload.TypeName).Should(Equal("BottleInput"))
+			Ω(res.Actions["rate"].Payload.TypeName).Should(Equal("RateBottlePayload"))
+		})
+
+		It("runs the finalizers", func() {
+			Ω(api.Produces).ShouldNot(BeEmpty())
+		})
+
+		It("does not modify the global design", func() {
+			Ω(Design.Name).Should(BeEmpty())
+		})
+	})
+
+	Context("with an invalid design", func() {
+		BeforeEach(func() {
+			bottle.View("tiny", "unknown")
+		})
+
+		It("returns the validation errors", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(api).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// CacheDefinition describes how the responses of actions may be cached by clients, proxies and
+// the service itself.
+type CacheDefinition struct {
+	// MaxAge is the number of seconds during which the responses are fresh.
+	MaxAge uint
+	// StaleWhileRevalidate is the number of seconds after the responses become stale during
+	// which caches may serve them while revalidating them in the background.
+	StaleWhileRevalidate uint
+	// Vary lists the request headers that select the cached responses.
+	Vary []string
+	// Parent is the action or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *CacheDefinition) Context() string {
+	if c.Parent != nil {
+		return "cache policy of " + c.Parent.Context()
+	}
+	return "cache policy"
+}
+
+// Validate makes sure the cache policy is valid.
+func (c *CacheDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	for _, v := range c.Vary {
+		if v == "" {
+			verr.Add(c, "vary header name cannot be empty")
+		}
+	}
+	return verr
+}
+
+// CacheControl returns the value of the Cache-Control header corresponding to the policy.
+func (c *CacheDefinition) CacheControl() string {
+	cc := fmt.Sprintf("max-age=%d", c.MaxAge)
+	if c.StaleWhileRevalidate > 0 {
+		cc += fmt.Sprintf(", stale-while-revalidate=%d", c.StaleWhileRevalidate)
+	}
+	return cc
+}
+
+// HasGETRoute returns true if at least one of the action routes uses the GET method.
+func (a *ActionDefinition) HasGETRoute() bool {
+	for _, r := range a.Routes {
+		if r.Verb == "GET" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllCache returns the cache policy of the action: the action policy if any, the resource policy
+// if the action has a GET route, nil otherwise.
+func (a *ActionDefinition) AllCache() *CacheDefinition {
+	if a.Cache != nil {
+		return a.Cache
+	}
+	if a.Parent != nil && a.HasGETRoute() {
+		return a.Parent.Cache
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// SensitiveMetadata is the metadata key set by Sensitive on the attributes whose values are masked
+// in the captured requests and responses.
+const SensitiveMetadata = "sensitive"
+
+// DefaultCaptureMaxBodySize is the maximum number of response body bytes captured when the design
+// does not specify one.
+const DefaultCaptureMaxBodySize = 64 << 10
+
+// CaptureDefinition describes how the requests and responses of the actions are captured.
+type CaptureDefinition struct {
+	// MaxBodySize is the maximum number of response body bytes captured.
+	MaxBodySize int
+	// Parent is the API or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *CaptureDefinition) Context() string {
+	if c.Parent != nil {
+		return "capture of " + c.Parent.Context()
+	}
+	return "capture"
+}
+
+// Validate makes sure the capture settings are valid.
+func (c *CaptureDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if c.MaxBodySize <= 0 {
+		verr.Add(c, "maximum captured body size must be greater than 0")
+	}
+	return verr
+}
+
+// AllCapture returns the capture settings of the resource actions: the resource settings if any,
+// the API settings otherwise. It returns nil if the requests are not captured.
+func (r *ResourceDefinition) AllCapture() *CaptureDefinition {
+	if r.Capture != nil {
+		return r.Capture
+	}
+	return Design.Capture
+}
+
+// IsSensitive returns true if the attribute values must be masked in the captured requests and
+// responses, that is if the attribute is marked with Sensitive or encrypted with Encrypted.
+func (a *AttributeDefinition) IsSensitive() bool {
+	if _, ok := a.Metadata[SensitiveMetadata]; ok {
+		return true
+	}
+	return a.EncryptionKey() != ""
+}
+
+// SensitivePayloadPaths returns the paths to the sensitive attributes of the action payload, e.g.
+// "card.number". Array elements are traversed transparently.
+func (a *ActionDefinition) SensitivePayloadPaths() []string {
+	if a.Payload == nil {
+		return nil
+	}
+	return sensitivePaths(a.Payload.AttributeDefinition, "", make(map[string]bool))
+}
+
+// SensitiveResponsePaths returns the paths to the sensitive attributes of the media types of the
+// action responses sorted alphabetically.
+func (a *ActionDefinition) SensitiveResponsePaths() []string {
+	set := make(map[string]bool)
+	for _, r := range a.Responses {
+		mt := Design.MediaTypeWithIdentifier(r.MediaType)
+		if mt == nil {
+			continue
+		}
+		for _, p := range sensitivePaths(mt.AttributeDefinition, "", map[string]bool{mt.TypeName: true}) {
+			set[p] = true
+		}
+	}
+	return sortedKeys(set)
+}
+
+// SensitiveHeaders returns the names of the sensitive request headers of the action and its
+// resource sorted alphabetically. The list includes the header that carries the API key or JWT
+// credentials of the action security scheme if any.
+func (a *ActionDefinition) SensitiveHeaders() []string {
+	set := make(map[string]bool)
+	if s := a.credentialScheme(); s != nil && s.In != "query" {
+		set[s.Name] = true
+	}
+	for _, h := range []*AttributeDefinition{a.Parent.Headers, a.Headers} {
+		if h == nil || h.Type == nil {
+			continue
+		}
+		for n, att := range h.Type.ToObject() {
+			if att.IsSensitive() {
+				set[n] = true
+			}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// SensitiveParams returns the names of the sensitive parameters of the action sorted
+// alphabetically. The list includes the query string parameter that carries the API key or JWT
+// credentials of the action security scheme if any.
+func (a *ActionDefinition) SensitiveParams() []string {
+	set := make(map[string]bool)
+	if s := a.credentialScheme(); s != nil && s.In == "query" {
+		set[s.Name] = true
+	}
+	if params := a.AllParams(); params != nil && params.Type != nil {
+		for n, att := range params.Type.ToObject() {
+			if att.IsSensitive() {
+				set[n] = true
+			}
+		}
+	}
+	return sortedKeys(set)
+}
+
+// credentialScheme returns the security scheme of the action if its credentials are carried by a
+// custom header or a query string parameter, nil otherwise.
+func (a *ActionDefinition) credentialScheme() *SecuritySchemeDefinition {
+	if a.Security == nil || a.Security.Scheme == nil || a.Security.Scheme.Name == "" {
+		return nil
+	}
+	switch s := a.Security.Scheme; s.Kind {
+	case APIKeySecurityKind, JWTSecurityKind:
+		return s
+	}
+	return nil
+}
+
+// sensitivePaths returns the paths to the sensitive attributes nested in att, seen records the
+// user types being traversed to avoid infinite recursions.
+func sensitivePaths(att *AttributeDefinition, prefix string, seen map[string]bool) []string {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	var name string
+	switch actual := att.Type.(type) {
+	case *UserTypeDefinition:
+		name = actual.TypeName
+	case *MediaTypeDefinition:
+		name = actual.TypeName
+	}
+	if name != "" {
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+	}
+	if arr := att.Type.ToArray(); arr != nil {
+		return sensitivePaths(arr.ElemType, prefix, seen)
+	}
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return nil
+	}
+	var paths []string
+	obj.IterateAttributes(func(n string, child *AttributeDefinition) error {
+		p := n
+		if prefix != "" {
+			p = prefix + "." + n
+		}
+		if child.IsSensitive() {
+			paths = append(paths, p)
+			return nil
+		}
+		paths = append(paths, sensitivePaths(child, p, seen)...)
+		return nil
+	})
+	return paths
+}
+
+// sortedKeys returns the keys of the given set sorted alphabetically.
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DefaultCompressionMinSize is the minimum size in bytes of the compressed response bodies when
+// the design does not specify one.
+const DefaultCompressionMinSize = 1024
+
+// DefaultCompressionEncodings lists the encodings used to compress the response bodies when the
+// design does not specify them, in order of preference.
+var DefaultCompressionEncodings = []string{"gzip", "deflate"}
+
+// CompressionDefinition describes how the response bodies are compressed.
+type CompressionDefinition struct {
+	// Encodings lists the names of the supported encodings in order of preference.
+	Encodings []string
+	// MinSize is the minimum size in bytes of the compressed response bodies.
+	MinSize int
+	// ContentTypes lists the media types of the compressed response bodies, all bodies are
+	// compressed if empty.
+	ContentTypes []string
+	// Parent is the API or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *CompressionDefinition) Context() string {
+	if c.Parent != nil {
+		return "compression of " + c.Parent.Context()
+	}
+	return "compression"
+}
+
+// Validate makes sure the compression settings are valid.
+func (c *CompressionDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if c.MinSize < 0 {
+		verr.Add(c, "minimum size cannot be negative")
+	}
+	if len(c.Encodings) == 0 {
+		verr.Add(c, "must use at least one encoding")
+	}
+	seen := make(map[string]bool)
+	for _, e := range c.Encodings {
+		if e == "" || e == "identity" {
+			verr.Add(c, "invalid encoding %#v", e)
+		}
+		if seen[e] {
+			verr.Add(c, "encoding %#v is listed twice", e)
+		}
+		seen[e] = true
+	}
+	return verr
+}
+
+// AllCompression returns the compression settings of the resource actions: the resource settings if
+// any, the API settings otherwise. It returns nil if the responses are not compressed.
+func (r *ResourceDefinition) AllCompression() *CompressionDefinition {
+	if r.Compression != nil {
+		return r.Compression
+	}
+	return Design.Compression
+}
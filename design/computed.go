@@ -0,0 +1,39 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// ComputedMetadata is the metadata key set by Computed on the view attributes whose values are
+// computed when the view is rendered.
+const ComputedMetadata = "view:computed"
+
+// IsComputed returns true if the attribute is a computed view attribute, see the Computed DSL.
+func (a *AttributeDefinition) IsComputed() bool {
+	_, ok := a.Metadata[ComputedMetadata]
+	return ok
+}
+
+// ComputedAttributes returns the names of the top level computed attributes of the object sorted
+// alphabetically. It returns nil if the attribute is not an object.
+func (a *AttributeDefinition) ComputedAttributes() []string {
+	if a == nil || a.Type == nil || !a.Type.IsObject() {
+		return nil
+	}
+	var names []string
+	a.Type.ToObject().IterateAttributes(func(n string, att *AttributeDefinition) error {
+		if att.IsComputed() {
+			names = append(names, n)
+		}
+		return nil
+	})
+	return names
+}
+
+// validateComputed makes sure computed attributes only appear in views.
+func (a *AttributeDefinition) validateComputed(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	if _, ok := parent.(*ViewDefinition); ok {
+		return
+	}
+	for _, n := range a.ComputedAttributes() {
+		verr.Add(parent, "%sfield %s is computed, computed attributes can only be defined in views", ctx, n)
+	}
+}
@@ -0,0 +1,57 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DefaultRetryAfter is the number of seconds clients are asked to wait before retrying the
+// requests rejected by load shedding when the design does not specify it.
+const DefaultRetryAfter = 1
+
+// DefaultMaxWait is the number of seconds requests exceeding the budget wait for a slot before
+// being rejected when the design does not specify it.
+const DefaultMaxWait = 30
+
+// ConcurrencyDefinition describes the concurrency budget of actions.
+type ConcurrencyDefinition struct {
+	// Max is the maximum number of requests handled concurrently by each action.
+	Max int
+	// Shed is true if the requests exceeding the budget are rejected, false if they wait for
+	// a slot.
+	Shed bool
+	// RetryAfter is the number of seconds clients are asked to wait before retrying the
+	// rejected requests.
+	RetryAfter uint
+	// MaxWait is the number of seconds requests exceeding the budget wait for a slot before
+	// being rejected when Shed is false, 0 means no bound.
+	MaxWait uint
+	// Parent is the action or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *ConcurrencyDefinition) Context() string {
+	if c.Parent != nil {
+		return "concurrency limit of " + c.Parent.Context()
+	}
+	return "concurrency limit"
+}
+
+// Validate makes sure the concurrency budget is valid.
+func (c *ConcurrencyDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if c.Max <= 0 {
+		verr.Add(c, "maximum number of concurrent requests must be greater than 0, use MaxConcurrent to set it")
+	}
+	return verr
+}
+
+// AllConcurrency returns the concurrency budget of the action: the action budget if any, the
+// resource budget otherwise.
+func (a *ActionDefinition) AllConcurrency() *ConcurrencyDefinition {
+	if a.Concurrency != nil {
+		return a.Concurrency
+	}
+	if a.Parent != nil {
+		return a.Parent.Concurrency
+	}
+	return nil
+}
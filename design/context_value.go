@@ -0,0 +1,82 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ContextValueDefinition describes a value that actions expect in the request context, for
+// example the authenticated user, the tenant or the locale. The generated code provides typed
+// accessors and middleware that store the value in the context so that user code never relies on
+// untyped context keys.
+type ContextValueDefinition struct {
+	// Name of the context value
+	Name string
+	// Description of the context value
+	Description string
+	// Type of the context value
+	Type DataType
+}
+
+// Context returns the generic definition name used in error messages.
+func (v *ContextValueDefinition) Context() string {
+	if v.Name != "" {
+		return fmt.Sprintf("context value %#v", v.Name)
+	}
+	return "unnamed context value"
+}
+
+// Validate makes sure the context value is properly named and typed. Context values must use
+// primitive types, user types, media types or arrays and hashes of these.
+func (v *ContextValueDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if v.Name == "" {
+		verr.Add(v, "context value name cannot be empty")
+	}
+	if v.Type == nil {
+		verr.Add(v, "context value type cannot be empty")
+	} else if hasInlineObject(v.Type) {
+		verr.Add(v, "context value type cannot be an inline object, use a user type instead")
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// AllContextValues returns the values the action expects in the request context: the values
+// expected by all the resource actions followed by the action values, each value being listed
+// once.
+func (a *ActionDefinition) AllContextValues() []*ContextValueDefinition {
+	var res []*ContextValueDefinition
+	seen := make(map[*ContextValueDefinition]bool)
+	var all []*ContextValueDefinition
+	if a.Parent != nil {
+		all = append(all, a.Parent.ContextValues...)
+	}
+	all = append(all, a.ContextValues...)
+	for _, v := range all {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// hasInlineObject returns true if the given type is or contains an object that is not a user type.
+func hasInlineObject(t DataType) bool {
+	switch actual := t.(type) {
+	case Object:
+		return true
+	case *Array:
+		return hasInlineObject(actual.ElemType.Type)
+	case *Hash:
+		return hasInlineObject(actual.KeyType.Type) || hasInlineObject(actual.ElemType.Type)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,73 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ReadOnlyMetadata is the metadata key set by ReadOnly on the media type attributes whose values
+// are set by the service and are therefore omitted from the payloads generated by CRUD.
+const ReadOnlyMetadata = "crud:readonly"
+
+// CRUDActions lists the names of the actions generated by CRUD in order.
+var CRUDActions = []string{"list", "show", "create", "update", "delete"}
+
+// CRUDDefinition describes the standard actions generated by CRUD for a resource.
+type CRUDDefinition struct {
+	// Resource the actions are added to.
+	Resource *ResourceDefinition
+	// MediaType is the media type of the resource.
+	MediaType *MediaTypeDefinition
+	// Excluded lists the names of the actions that are not generated.
+	Excluded []string
+}
+
+// Context returns the generic definition name used in error messages.
+func (c *CRUDDefinition) Context() string {
+	return fmt.Sprintf("CRUD of %s", c.Resource.Context())
+}
+
+// IsExcluded returns true if the action with the given name is excluded.
+func (c *CRUDDefinition) IsExcluded(action string) bool {
+	for _, e := range c.Excluded {
+		if e == action {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnly returns true if the attribute is read-only, see the ReadOnly DSL.
+func (a *AttributeDefinition) IsReadOnly() bool {
+	_, ok := a.Metadata[ReadOnlyMetadata]
+	return ok
+}
+
+// WritableAttributes returns a copy of the object attribute without its read-only and computed
+// attributes. The required attributes of the copy are the writable required attributes of a if
+// required is true, none otherwise. It returns nil if a is not an object.
+func (a *AttributeDefinition) WritableAttributes(required bool) *AttributeDefinition {
+	if a == nil || a.Type == nil || !a.Type.IsObject() {
+		return nil
+	}
+	obj := make(Object)
+	for n, att := range a.Type.ToObject() {
+		if att.IsReadOnly() || att.IsComputed() {
+			continue
+		}
+		obj[n] = DupAtt(att)
+	}
+	res := &AttributeDefinition{Type: obj, Description: a.Description}
+	if required {
+		for _, n := range a.AllRequired() {
+			if _, ok := obj[n]; ok {
+				if res.Validation == nil {
+					res.Validation = &dslengine.ValidationDefinition{}
+				}
+				res.Validation.Required = append(res.Validation.Required, n)
+			}
+		}
+	}
+	return res
+}
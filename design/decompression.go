@@ -0,0 +1,43 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DefaultDecompressionMaxSize is the maximum size in bytes of the decompressed request bodies
+// when the design does not specify one.
+const DefaultDecompressionMaxSize = 10 << 20
+
+// DecompressionDefinition describes how the request bodies compressed with gzip or deflate are
+// decoded.
+type DecompressionDefinition struct {
+	// MaxSize is the maximum size in bytes of the decompressed request bodies.
+	MaxSize int64
+	// Parent is the API or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (d *DecompressionDefinition) Context() string {
+	if d.Parent != nil {
+		return "decompression of " + d.Parent.Context()
+	}
+	return "decompression"
+}
+
+// Validate makes sure the decompression settings are valid.
+func (d *DecompressionDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if d.MaxSize <= 0 {
+		verr.Add(d, "maximum decompressed size must be greater than 0")
+	}
+	return verr
+}
+
+// AllDecompression returns the decompression settings of the resource actions: the resource
+// settings if any, the API settings otherwise. It returns nil if compressed request bodies are not
+// accepted.
+func (r *ResourceDefinition) AllDecompression() *DecompressionDefinition {
+	if r.Decompression != nil {
+		return r.Decompression
+	}
+	return Design.Decompression
+}
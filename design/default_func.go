@@ -0,0 +1,48 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DefaultFuncMetadata is the metadata key set by DefaultFunc on the attributes whose default
+// value is computed by a provider at decode time.
+const DefaultFuncMetadata = "default:func"
+
+// builtinDefaultFuncs lists the providers registered by the goa package together with the type
+// of the values they compute and its DSL name.
+var builtinDefaultFuncs = map[string]struct {
+	Type     Primitive
+	TypeName string
+}{
+	"uuid": {UUID, "UUID"},
+	"now":  {DateTime, "DateTime"},
+}
+
+// DefaultFunc returns the name of the provider that computes the default value of the attribute,
+// empty if the attribute has no default value provider.
+func (a *AttributeDefinition) DefaultFunc() string {
+	if f, ok := a.Metadata[DefaultFuncMetadata]; ok && len(f) > 0 {
+		return f[0]
+	}
+	return ""
+}
+
+// validateDefaultFunc makes sure the default value provider is only set on primitive attributes
+// that have no literal default value and that the built-in providers compute values of the
+// attribute type.
+func (a *AttributeDefinition) validateDefaultFunc(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	name := a.DefaultFunc()
+	if name == "" {
+		return
+	}
+	p, ok := a.Type.(Primitive)
+	if !ok || p == Any {
+		verr.Add(parent, "%sdefault value provider %#v may only be set on attributes of primitive type", ctx, name)
+		return
+	}
+	if a.DefaultValue != nil {
+		verr.Add(parent, "%sattribute cannot have both a default value and a default value provider", ctx)
+	}
+	if b, ok := builtinDefaultFuncs[name]; ok && b.Type != p {
+		verr.Add(parent, "%sdefault value provider %#v may only be used with %s attributes",
+			ctx, name, b.TypeName)
+	}
+}
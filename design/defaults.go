@@ -0,0 +1,161 @@
+package design
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Finalize coerces the default values and enum values of the attribute and of its child
+// attributes to the Go kinds used by the generated code: integer values are converted to int and
+// number values to float64. This makes it possible to use any numeric literal in the design, e.g.
+// Default(1) on a Number attribute. The attributes of user types are finalized with the user
+// type.
+func (a *AttributeDefinition) Finalize() {
+	if a == nil || a.Type == nil {
+		return
+	}
+	if a.DefaultValue != nil {
+		a.DefaultValue = coerceValue(a.Type, a.DefaultValue)
+	}
+	if a.Validation != nil {
+		for i, v := range a.Validation.Values {
+			a.Validation.Values[i] = coerceValue(a.Type, v)
+		}
+	}
+	switch actual := a.Type.(type) {
+	case Object:
+		for _, att := range actual {
+			att.Finalize()
+		}
+	case *Array:
+		actual.ElemType.Finalize()
+	case *Hash:
+		actual.KeyType.Finalize()
+		actual.ElemType.Finalize()
+	}
+}
+
+// coerceValue converts the numeric values contained in v to the Go kinds used by the generated
+// code for the given type. Values that cannot be converted are returned unchanged so that
+// validation reports them.
+func coerceValue(t DataType, v interface{}) interface{} {
+	switch t.Kind() {
+	case IntegerKind:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return int(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return int(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			if f := rv.Float(); f == math.Trunc(f) {
+				return int(f)
+			}
+		}
+	case NumberKind:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(rv.Uint())
+		case reflect.Float32:
+			return rv.Float()
+		}
+	case ArrayKind:
+		if vals, ok := v.([]interface{}); ok {
+			elem := t.ToArray().ElemType.Type
+			res := make([]interface{}, len(vals))
+			for i, e := range vals {
+				res[i] = coerceValue(elem, e)
+			}
+			return res
+		}
+	case HashKind:
+		if vals, ok := v.(map[interface{}]interface{}); ok {
+			h := t.ToHash()
+			res := make(map[interface{}]interface{}, len(vals))
+			for k, e := range vals {
+				res[coerceValue(h.KeyType.Type, k)] = coerceValue(h.ElemType.Type, e)
+			}
+			return res
+		}
+	case UserTypeKind:
+		return coerceValue(t.(*UserTypeDefinition).Type, v)
+	case MediaTypeKind:
+		return coerceValue(t.(*MediaTypeDefinition).Type, v)
+	}
+	return v
+}
+
+// validateDefault checks that the default value of the attribute is compatible with its type and
+// satisfies its validations.
+func (a *AttributeDefinition) validateDefault(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	if a.DefaultValue == nil {
+		return
+	}
+	if !a.Type.IsCompatible(a.DefaultValue) {
+		verr.Add(parent, "%sdefault value %#v is incompatible with attribute of type %s", ctx, a.DefaultValue, a.Type.Name())
+		return
+	}
+	val := a.Validation
+	if val == nil {
+		return
+	}
+	// TODO: We only do the default value and enum check just for primitive types.
+	// Issue 388 (https://github.com/goadesign/goa/issues/388) will address this for other types.
+	// Validations run before finalization so compare the coerced values.
+	if a.Type.IsPrimitive() && val.Values != nil {
+		var found bool
+		def := coerceValue(a.Type, a.DefaultValue)
+		for _, e := range val.Values {
+			if coerceValue(a.Type, e) == def {
+				found = true
+				break
+			}
+		}
+		if !found {
+			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, val.Values)
+		}
+	}
+	switch def := coerceValue(a.Type, a.DefaultValue).(type) {
+	case string:
+		if val.Pattern != "" {
+			if re, err := regexp.Compile(val.Pattern); err == nil && !re.MatchString(def) {
+				verr.Add(parent, "%sdefault value %#v does not match the pattern %#v", ctx, def, val.Pattern)
+			}
+		}
+		a.validateDefaultLength(ctx, parent, verr, utf8.RuneCountInString(def))
+	case int, float64:
+		var f float64
+		if i, ok := def.(int); ok {
+			f = float64(i)
+		} else {
+			f = def.(float64)
+		}
+		if val.Minimum != nil && f < *val.Minimum {
+			verr.Add(parent, "%sdefault value %#v is lower than the minimum %v", ctx, def, *val.Minimum)
+		}
+		if val.Maximum != nil && f > *val.Maximum {
+			verr.Add(parent, "%sdefault value %#v is greater than the maximum %v", ctx, def, *val.Maximum)
+		}
+	case []interface{}:
+		a.validateDefaultLength(ctx, parent, verr, len(def))
+	}
+}
+
+// validateDefaultLength checks the length of the default value against the length validations.
+func (a *AttributeDefinition) validateDefaultLength(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors, l int) {
+	val := a.Validation
+	if val.MinLength != nil && l < *val.MinLength {
+		verr.Add(parent, "%sdefault value %#v is shorter than the minimum length %d", ctx, a.DefaultValue, *val.MinLength)
+	}
+	if val.MaxLength != nil && l > *val.MaxLength {
+		verr.Add(parent, "%sdefault value %#v is longer than the maximum length %d", ctx, a.DefaultValue, *val.MaxLength)
+	}
+}
+
@@ -71,8 +71,79 @@ type (
 		// resources and actions, unless overridden by Resource or
 		// Action-level Security() calls.
 		Security *SecurityDefinition
+		// Interceptors lists the interceptors available to the API resources
+		// and actions.
+		Interceptors []*InterceptorDefinition
+		// Scalars lists the custom scalar types defined with Scalar.
+		Scalars []*ScalarDefinition
+		// Flags lists the flags types defined with Flags.
+		Flags []*FlagsDefinition
+		// ContextValues lists the values that the API resources and actions
+		// may expect in the request context.
+		ContextValues []*ContextValueDefinition
+		// Tenant describes how requests identify the tenant that owns the
+		// resources if the API is multi-tenant.
+		Tenant *TenantDefinition
+		// Servers lists the environments serving the API.
+		Servers []*ServerDefinition
+		// Workflows lists the multi-endpoint flows of the API.
+		Workflows []*WorkflowDefinition
+		// TLS describes the TLS requirements of the API servers unless
+		// overridden by the server definitions.
+		TLS *TLSDefinition
+		// RequestID describes how requests are correlated if enabled.
+		RequestID *RequestIDDefinition
+		// JSONRPC describes the JSON-RPC 2.0 endpoint exposing the API actions if any.
+		JSONRPC *JSONRPCDefinition
+		// Snapshot is the path to the schema snapshot file used to validate the changes made
+		// to the API types, see SnapshotFile.
+		Snapshot string
+		// AdditiveOnly is true if the validation rejects the breaking changes made to the
+		// API types compared to the schema snapshot.
+		AdditiveOnly bool
+		// FieldPolicy is the default policy used to generate the fields of the optional
+		// primitive attributes of the API types, media types and payloads.
+		FieldPolicy FieldPolicy
+		// AbsentPolicy is the default policy used to encode the optional attributes of
+		// the API media types that are absent from the response.
+		AbsentPolicy AbsentPolicy
+		// DuplicatePolicy is the default policy applied to the attributes declared
+		// multiple times in the same object, see DuplicateAttributes.
+		DuplicatePolicy DuplicatePolicy
 		// NoExamples indicates whether to bypass automatic example generation.
 		NoExamples bool
+		// IdentifierTemplate is the template used to build the identifiers of the media
+		// types declared with a name instead of an identifier, see IdentifierTemplate.
+		IdentifierTemplate string
+		// VendorPrefix is the organization name used in the media type identifiers built
+		// from the identifier template, see VendorPrefix.
+		VendorPrefix string
+		// AutoHead is true if the generated controllers handle HEAD requests for all the
+		// GET routes, see AutoHead.
+		AutoHead bool
+		// AutoOptions is true if the generated controllers handle OPTIONS requests for all
+		// the route paths, see AutoOptions.
+		AutoOptions bool
+		// MethodOverride describes how clients tunnel HTTP methods through POST requests if
+		// enabled.
+		MethodOverride *MethodOverrideDefinition
+		// Compression describes how the action responses are compressed if enabled.
+		Compression *CompressionDefinition
+		// Decompression describes how the compressed request bodies are decoded if
+		// enabled.
+		Decompression *DecompressionDefinition
+		// Limits caps the nesting depth and the sizes of the arrays and objects of the
+		// request bodies if any.
+		Limits *LimitsDefinition
+		// Maintenance is the response sent to the requests made to endpoints in maintenance
+		// mode if any, see Maintenance.
+		Maintenance *ResponseDefinition
+		// Capture describes how the action requests and responses are captured if enabled.
+		Capture *CaptureDefinition
+		// Imports lists the design modules imported by the design, see Import.
+		Imports []*ImportDefinition
+		// Errors lists the sentinel errors all the actions may return, see Error.
+		Errors []*ErrorDefinition
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
@@ -110,6 +181,10 @@ type (
 	ResourceDefinition struct {
 		// Resource name
 		Name string
+		// Location is where the resource is declared in the design sources if known.
+		Location dslengine.Location
+		// Module is the import path of the design module declaring the resource if any.
+		Module string
 		// Schemes is the supported API URL schemes
 		Schemes []string
 		// Common URL prefix to all resource action HTTP requests
@@ -136,6 +211,28 @@ type (
 		Headers *AttributeDefinition
 		// Origins defines the CORS policies that apply to this resource.
 		Origins map[string]*CORSDefinition
+		// Compression describes how the resource action responses are compressed if
+		// enabled, it overrides the API compression.
+		Compression *CompressionDefinition
+		// Decompression describes how the compressed request bodies of the resource actions
+		// are decoded if enabled, it overrides the API decompression.
+		Decompression *DecompressionDefinition
+		// Limits caps the nesting depth and the sizes of the arrays and objects of the
+		// request bodies of the resource actions if any, it overrides the API limits.
+		Limits *LimitsDefinition
+		// Cache describes how the responses of the resource GET actions may be cached if
+		// any.
+		Cache *CacheDefinition
+		// Capture describes how the resource action requests and responses are captured if
+		// enabled, it overrides the API settings.
+		Capture *CaptureDefinition
+		// Concurrency describes the concurrency budget of each resource action if any.
+		Concurrency *ConcurrencyDefinition
+		// Event describes the events published after each successful call to the resource
+		// actions that mutate resources if any.
+		Event *EventDefinition
+		// Errors lists the sentinel errors the resource actions may return, see Error.
+		Errors []*ErrorDefinition
 		// DSLFunc contains the DSL used to create this definition if any.
 		DSLFunc func()
 		// metadata is a list of key/value pairs
@@ -143,6 +240,11 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// Interceptors lists the interceptors that apply to all the resource actions.
+		Interceptors []*InterceptorDefinition
+		// ContextValues lists the values all the resource actions expect in the
+		// request context.
+		ContextValues []*ContextValueDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -197,12 +299,17 @@ type (
 		ViewName string
 		// Response header definitions
 		Headers *AttributeDefinition
+		// Response trailer definitions, see the Trailers DSL
+		Trailers *AttributeDefinition
 		// Parent action or resource
 		Parent dslengine.Definition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Standard is true if the response definition comes from the goa default responses
 		Standard bool
+		// LocationHeader is the name of the result attribute or the href template used to
+		// compute the value of the Location header, see the Location DSL.
+		LocationHeader string
 	}
 
 	// ResponseTemplateDefinition defines a response template.
@@ -224,6 +331,8 @@ type (
 	ActionDefinition struct {
 		// Action name, e.g. "create"
 		Name string
+		// Location is where the action is declared in the design sources if known.
+		Location dslengine.Location
 		// Action description, e.g. "Creates a task"
 		Description string
 		// Docs points to the API external documentation
@@ -244,12 +353,44 @@ type (
 		Payload *UserTypeDefinition
 		// PayloadOptional is true if the request payload is optional, false otherwise.
 		PayloadOptional bool
+		// StreamingPayload is the type of the elements of the streaming request payload if
+		// any.
+		StreamingPayload *UserTypeDefinition
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Interceptors lists the interceptors that apply to the action in addition to the
+		// resource interceptors.
+		Interceptors []*InterceptorDefinition
+		// ContextValues lists the values the action expects in the request context in
+		// addition to the values expected by all the resource actions.
+		ContextValues []*ContextValueDefinition
+		// Audit describes the audit event emitted after each call to the action if any.
+		Audit *AuditDefinition
+		// Cache describes how the action responses may be cached if any, it overrides the
+		// resource cache policy.
+		Cache *CacheDefinition
+		// Concurrency describes the concurrency budget of the action if any, it overrides
+		// the resource budget.
+		Concurrency *ConcurrencyDefinition
+		// Flag describes the feature flag gating the action if any.
+		Flag *FlagDefinition
+		// Download describes how the binary result of the action is downloaded if any.
+		Download *DownloadDefinition
+		// Upload describes the resumable uploads received by the action if any.
+		Upload *UploadDefinition
+		// PresignedUpload describes the presigned uploads handled by the action if any.
+		PresignedUpload *PresignedUploadDefinition
+		// Event describes the events published after each successful call to the action if
+		// any, it overrides the resource definition.
+		Event *EventDefinition
+		// MQTT describes the MQTT topic the action subscribes to if any.
+		MQTT *MQTTDefinition
+		// Schedule describes when the service calls the action periodically if any.
+		Schedule *ScheduleDefinition
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -321,8 +462,9 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Optional member default value
 		DefaultValue interface{}
-		// Optional member example value
-		Example interface{}
+		// Optional member example value, generated on demand by GenerateExample if not
+		// set by the design.
+		Example interface{} `dslengine:"lazy"`
 		// Optional view used to render Attribute (only applies to media type attributes).
 		View string
 		// NonZeroAttributes lists the names of the child attributes that cannot have a
@@ -330,6 +472,9 @@ type (
 		NonZeroAttributes map[string]bool
 		// DSLFunc contains the initialization DSL. This is used for user types.
 		DSLFunc func()
+
+		// duplicates lists the child attributes declared multiple times, see AddDuplicate.
+		duplicates []string
 	}
 
 	// ContainerDefinition defines a generic container definition that contains attributes.
@@ -382,57 +527,11 @@ func NewAPIDefinition() *APIDefinition {
 		Name:     OK,
 		Template: t,
 	}
-	for _, p := range []struct {
-		status int
-		name   string
-	}{
-		{100, Continue},
-		{101, SwitchingProtocols},
-		{200, OK},
-		{201, Created},
-		{202, Accepted},
-		{203, NonAuthoritativeInfo},
-		{204, NoContent},
-		{205, ResetContent},
-		{206, PartialContent},
-		{300, MultipleChoices},
-		{301, MovedPermanently},
-		{302, Found},
-		{303, SeeOther},
-		{304, NotModified},
-		{305, UseProxy},
-		{307, TemporaryRedirect},
-		{400, BadRequest},
-		{401, Unauthorized},
-		{402, PaymentRequired},
-		{403, Forbidden},
-		{404, NotFound},
-		{405, MethodNotAllowed},
-		{406, NotAcceptable},
-		{407, ProxyAuthRequired},
-		{408, RequestTimeout},
-		{409, Conflict},
-		{410, Gone},
-		{411, LengthRequired},
-		{412, PreconditionFailed},
-		{413, RequestEntityTooLarge},
-		{414, RequestURITooLong},
-		{415, UnsupportedMediaType},
-		{416, RequestedRangeNotSatisfiable},
-		{417, ExpectationFailed},
-		{418, Teapot},
-		{422, UnprocessableEntity},
-		{500, InternalServerError},
-		{501, NotImplemented},
-		{502, BadGateway},
-		{503, ServiceUnavailable},
-		{504, GatewayTimeout},
-		{505, HTTPVersionNotSupported},
-	} {
-		api.DefaultResponses[p.name] = &ResponseDefinition{
-			Name:        p.name,
-			Description: http.StatusText(p.status),
-			Status:      p.status,
+	for name, status := range ResponseCatalog {
+		api.DefaultResponses[name] = &ResponseDefinition{
+			Name:        name,
+			Description: http.StatusText(status),
+			Status:      status,
 		}
 	}
 	return api
@@ -454,6 +553,17 @@ func (a *APIDefinition) IterateSets(iterator dslengine.SetIterator) {
 	// First run the top level API DSL to initialize responses and
 	// response templates needed by resources.
 	iterator([]dslengine.Definition{a})
+	a.resolveIdentifiers()
+
+	// Then the custom scalars and flags used by the types
+	var scalars []dslengine.Definition
+	for _, s := range a.Scalars {
+		scalars = append(scalars, dslengine.Definition(s))
+	}
+	for _, f := range a.Flags {
+		scalars = append(scalars, dslengine.Definition(f))
+	}
+	iterator(scalars)
 
 	// Then run the user type DSLs
 	typeAttributes := make([]dslengine.Definition, len(a.Types))
@@ -483,6 +593,32 @@ func (a *APIDefinition) IterateSets(iterator dslengine.SetIterator) {
 	}
 	iterator(securitySchemes)
 
+	// Then the interceptor definitions
+	var interceptors []dslengine.Definition
+	for _, i := range a.Interceptors {
+		interceptors = append(interceptors, dslengine.Definition(i))
+	}
+	iterator(interceptors)
+
+	// Then the context value definitions
+	var contextValues []dslengine.Definition
+	for _, v := range a.ContextValues {
+		contextValues = append(contextValues, dslengine.Definition(v))
+	}
+	iterator(contextValues)
+
+	// Then the server definitions
+	var servers []dslengine.Definition
+	for _, s := range a.Servers {
+		servers = append(servers, dslengine.Definition(s))
+	}
+	iterator(servers)
+
+	// Then the tenant definition
+	if a.Tenant != nil {
+		iterator([]dslengine.Definition{a.Tenant})
+	}
+
 	// And now that we have everything the resources.  The resource
 	// lifecycle handlers dispatch to their children elements, like
 	// Actions, etc..
@@ -494,13 +630,21 @@ func (a *APIDefinition) IterateSets(iterator dslengine.SetIterator) {
 		return nil
 	})
 	iterator(resources)
+
+	// Finally the workflow definitions which refer to the resource actions
+	workflows := make([]dslengine.Definition, len(a.Workflows))
+	for i, w := range a.Workflows {
+		workflows[i] = w
+	}
+	iterator(workflows)
 }
 
 // Reset sets all the API definition fields to their zero value except the default responses and
-// default response templates.
+// default response templates. It also clears the projected media types cache.
 func (a *APIDefinition) Reset() {
 	n := NewAPIDefinition()
 	*a = *n
+	ProjectedMediaTypes.Reset()
 }
 
 // Context returns the generic definition name used in error messages.
@@ -581,7 +725,7 @@ func (a *APIDefinition) RandomGenerator() *RandomGenerator {
 // values sans suffix match. So for example "application/vnd.foo+xml",
 // "application/vnd.foo+json" and "application/vnd.foo" all match.
 func (a *APIDefinition) MediaTypeWithIdentifier(id string) *MediaTypeDefinition {
-	canonicalID := CanonicalIdentifier(id)
+	canonicalID := CanonicalIdentifier(a.ExpandIdentifier(id))
 	for _, mt := range a.MediaTypes {
 		if canonicalID == CanonicalIdentifier(mt.Identifier) {
 			return mt
@@ -617,13 +761,22 @@ func (a *APIDefinition) DSL() func() {
 // Finalize sets the Consumes and Produces fields to the defaults if empty.
 // Also it records built-in media types that are used by the user design.
 func (a *APIDefinition) Finalize() {
+	a.Params.Finalize()
+	a.finalizeFieldPolicy()
+	a.finalizeAbsentPolicy()
 	if len(a.Consumes) == 0 {
 		a.Consumes = DefaultDecoders
 	}
 	if len(a.Produces) == 0 {
 		a.Produces = DefaultEncoders
 	}
-	found := false
+	found := a.Maintenance != nil && a.Maintenance.MediaType != ""
+	if found {
+		if a.MediaTypes == nil {
+			a.MediaTypes = make(map[string]*MediaTypeDefinition)
+		}
+		a.MediaTypes[CanonicalIdentifier(ErrorMediaIdentifier)] = ErrorMedia
+	}
 	a.IterateResources(func(r *ResourceDefinition) error {
 		if found {
 			return nil
@@ -830,6 +983,8 @@ func (r *ResourceDefinition) DSL() func() {
 // parameters, initializes querystring parameters, sets path parameters as non zero attributes
 // and sets the fallbacks for security schemes.
 func (r *ResourceDefinition) Finalize() {
+	r.Params.Finalize()
+	r.Headers.Finalize()
 	r.IterateFileServers(func(f *FileServerDefinition) error {
 		f.Finalize()
 		return nil
@@ -954,7 +1109,9 @@ func (a *AttributeDefinition) IsNonZero(attName string) bool {
 }
 
 // IsPrimitivePointer returns true if the field generated for the given attribute should be a
-// pointer to a primitive type. The target attribute must be an object.
+// pointer to a primitive type. The target attribute must be an object. Optional primitive
+// attributes are pointers unless the object uses the nullable or presence field policy, see
+// FieldPolicy, or the Go type of the custom scalar is already a pointer.
 func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if !a.Type.IsObject() {
 		panic("checking pointer field on non-object") // bug
@@ -963,8 +1120,16 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
-	if att.Type.IsPrimitive() {
-		return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
+	if s, ok := att.Type.(*ScalarDefinition); ok && s.IsReference() {
+		return false
+	}
+	if att.Type.IsPrimitive() && a.isOptional(attName) {
+		switch a.FieldPolicy() {
+		case PointerFields:
+			return true
+		case NullableFields:
+			return !hasNullWrapper(att.Type)
+		}
 	}
 	return false
 }
@@ -1014,7 +1179,12 @@ func (a *AttributeDefinition) GenerateExample(rand *RandomGenerator, seen []stri
 		seen = append(seen, key)
 	}
 
+	flags, isFlags := a.Type.(*FlagsDefinition)
 	switch {
+	case isFlags:
+		// Flags are arrays of distinct flag names, see FlagsDefinition.GenerateExample.
+		a.Example = flags.GenerateExample(rand, seen)
+
 	case a.Type.IsArray():
 		a.Example = a.arrayExample(rand, seen)
 
@@ -1103,6 +1273,92 @@ func (a *AttributeDefinition) objectExample(rand *RandomGenerator, seen []string
 	return a.Example
 }
 
+// RandomValue returns a random value of the attribute type that satisfies the attribute validations
+// as well as the validations of any nested attribute. Contrary to GenerateExample RandomValue ignores
+// the examples defined in the design and does not record the values it produces so that successive
+// calls return different values.
+func (a *AttributeDefinition) RandomValue(rand *RandomGenerator) interface{} {
+	return a.randomValue(rand, nil)
+}
+
+func (a *AttributeDefinition) randomValue(rand *RandomGenerator, seen []string) interface{} {
+	// Avoid infinite loops
+	var key string
+	if mt, ok := a.Type.(*MediaTypeDefinition); ok {
+		key = mt.Identifier
+	} else if ut, ok := a.Type.(*UserTypeDefinition); ok {
+		key = ut.TypeName
+	}
+	if key != "" {
+		count := 0
+		for _, k := range seen {
+			if k == key {
+				count++
+			}
+		}
+		if count > 1 {
+			return nil
+		}
+		seen = append(seen, key)
+	}
+
+	switch {
+	case a.Type.IsArray():
+		ary := a.Type.ToArray()
+		ln := newExampleGenerator(a, rand).ExampleLength()
+		res := make([]interface{}, 0, ln)
+		for i := 0; i < ln; i++ {
+			if v := ary.ElemType.randomValue(rand, seen); v != nil {
+				res = append(res, v)
+			}
+		}
+		return ary.MakeSlice(res)
+
+	case a.Type.IsHash():
+		h := a.Type.ToHash()
+		ln := newExampleGenerator(a, rand).ExampleLength()
+		res := make(map[interface{}]interface{})
+		for i := 0; i < ln; i++ {
+			k := h.KeyType.randomValue(rand, seen)
+			v := h.ElemType.randomValue(rand, seen)
+			if k != nil && v != nil {
+				res[k] = v
+			}
+		}
+		return h.MakeMap(res)
+
+	case a.Type.IsObject():
+		actual := a
+		if mt, ok := a.Type.(*MediaTypeDefinition); ok {
+			v := a.View
+			if v == "" {
+				v = DefaultView
+			}
+			projected, _, err := mt.Project(v)
+			if err != nil {
+				panic(err) // bug
+			}
+			actual = projected.AttributeDefinition
+		}
+		obj := actual.Type.ToObject()
+		keys := make([]string, 0, len(obj))
+		for n := range obj {
+			keys = append(keys, n)
+		}
+		sort.Strings(keys)
+		res := make(map[string]interface{})
+		for _, n := range keys {
+			if v := obj[n].randomValue(rand, seen); v != nil {
+				res[n] = v
+			}
+		}
+		return res
+
+	default:
+		return newExampleGenerator(a, rand).Generate(seen)
+	}
+}
+
 // Merge merges the argument attributes into the target and returns the target overriding existing
 // attributes with identical names.
 // This only applies to attributes of type Object and Merge panics if the
@@ -1254,15 +1510,19 @@ func (r *ResponseDefinition) Finalize() {
 // Dup returns a copy of the response definition.
 func (r *ResponseDefinition) Dup() *ResponseDefinition {
 	res := ResponseDefinition{
-		Name:        r.Name,
-		Status:      r.Status,
-		Description: r.Description,
-		MediaType:   r.MediaType,
-		ViewName:    r.ViewName,
+		Name:           r.Name,
+		Status:         r.Status,
+		Description:    r.Description,
+		MediaType:      r.MediaType,
+		ViewName:       r.ViewName,
+		LocationHeader: r.LocationHeader,
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
 	}
+	if r.Trailers != nil {
+		res.Trailers = DupAtt(r.Trailers)
+	}
 	return &res
 }
 
@@ -1284,6 +1544,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if r.LocationHeader == "" {
+		r.LocationHeader = other.LocationHeader
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1298,6 +1561,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 			}
 		}
 	}
+	if r.Trailers == nil && other.Trailers != nil {
+		r.Trailers = DupAtt(other.Trailers)
+	}
 }
 
 // Context returns the generic definition name used in error messages.
@@ -1436,6 +1702,11 @@ func (a *ActionDefinition) Finalize() {
 	if a.Payload != nil {
 		a.Payload.Finalize()
 	}
+	a.Params.Finalize()
+	a.Headers.Finalize()
+	for _, r := range a.Responses {
+		r.Headers.Finalize()
+	}
 
 	a.mergeResponses()
 	a.initImplicitParams()
@@ -1471,7 +1742,15 @@ func (a *ActionDefinition) UserTypes() map[string]*UserTypeDefinition {
 // Iteration stops if an iterator returns an error and in this case IterateHeaders returns that
 // error.
 func (a *ActionDefinition) IterateHeaders(it HeaderIterator) error {
-	mergedHeaders := a.Parent.Headers.Merge(a.Headers)
+	var mergedHeaders *AttributeDefinition
+	if a.Parent.Headers != nil {
+		// Merge into a copy so that the resource headers are left untouched.
+		mergedHeaders = &AttributeDefinition{Type: Object{}}
+		for n, h := range a.Parent.Headers.Type.ToObject() {
+			mergedHeaders.Type.ToObject()[n] = h
+		}
+	}
+	mergedHeaders = mergedHeaders.Merge(a.Headers)
 
 	isRequired := func(name string) bool {
 		// header required in either the Resource or Action scope?
@@ -1,6 +1,7 @@
 package design_test
 
 import (
+	"fmt"
 	"path"
 
 	"github.com/goadesign/goa/design"
@@ -162,3 +163,21 @@ var _ = Describe("FullPath", func() {
 		})
 	})
 })
+
+var _ = Describe("GenerateExample", func() {
+	It("generates distinct flag names for flags attributes", func() {
+		flags := design.NewFlagsDefinition("Permissions", "Permissions", "read", "write", "admin")
+		for i := 0; i < 10; i++ {
+			att := &design.AttributeDefinition{Type: flags}
+			ex := att.GenerateExample(design.NewRandomGenerator(fmt.Sprintf("seed%d", i)), nil)
+			Ω(flags.IsCompatible(ex)).Should(BeTrue())
+			names, ok := ex.([]string)
+			Ω(ok).Should(BeTrue())
+			seen := make(map[string]bool)
+			for _, n := range names {
+				Ω(seen[n]).Should(BeFalse(), "duplicate flag name %s", n)
+				seen[n] = true
+			}
+		}
+	})
+})
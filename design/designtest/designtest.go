@@ -0,0 +1,225 @@
+/*
+Package designtest provides matchers that assert properties of an evaluated design so that teams
+can write unit tests for their own design and catch accidental changes made to the DSL in CI.
+
+The matchers implement the Gomega matcher interface and may be used with Ω or Expect:
+
+	var _ = Describe("design", func() {
+		BeforeEach(func() {
+			designtest.Eval(GinkgoT())
+		})
+
+		It("exposes the bottle show endpoint", func() {
+			Ω(design.Design).Should(designtest.HasEndpoint("bottle", "show"))
+			Ω(design.Design).Should(designtest.ResponseUsesMediaType("bottle", "show", "OK", "application/vnd.goa.example.bottle+json"))
+			Ω(design.Design).Should(designtest.AttributeRequired("BottlePayload", "vintage"))
+		})
+	})
+
+They may also be used with the testing package via Check:
+
+	func TestDesign(t *testing.T) {
+		api := designtest.Eval(t)
+		designtest.Check(t, api, designtest.HasEndpoint("bottle", "show"))
+	}
+
+The test package must import the design package so that its DSL is registered before Eval runs
+it.
+*/
+package designtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goatest"
+)
+
+type (
+	// Matcher asserts a property of an evaluated design. It implements the Gomega matcher
+	// interface, the actual value given to its methods is the API definition or nil to use
+	// design.Design.
+	Matcher interface {
+		// Match returns true if the design has the property.
+		Match(actual interface{}) (bool, error)
+		// FailureMessage describes why the design does not have the property.
+		FailureMessage(actual interface{}) string
+		// NegatedFailureMessage describes why the design has the property.
+		NegatedFailureMessage(actual interface{}) string
+	}
+
+	// matcher implements Matcher with a function that checks the property.
+	matcher struct {
+		// desc describes the property, e.g. `has endpoint "bottle" "show"`.
+		desc string
+		// check returns true if the API has the property, otherwise it returns the reason
+		// why it doesn't.
+		check func(api *design.APIDefinition) (bool, string)
+		// reason is the reason returned by the last call to check.
+		reason string
+	}
+)
+
+// Eval runs the DSL of the design packages imported by the test and returns the resulting API
+// definition. It fails the test if the DSL produces errors.
+func Eval(t goatest.TInterface) *design.APIDefinition {
+	if err := dslengine.Run(); err != nil {
+		t.Fatalf("design evaluation failed: %s", err)
+		return nil
+	}
+	return design.Design
+}
+
+// Check reports a test error for each matcher that does not match api.
+func Check(t goatest.TInterface, api *design.APIDefinition, matchers ...Matcher) {
+	for _, m := range matchers {
+		ok, err := m.Match(api)
+		if err != nil {
+			t.Errorf("%s", err)
+			continue
+		}
+		if !ok {
+			t.Errorf("%s", m.FailureMessage(api))
+		}
+	}
+}
+
+// HasEndpoint matches designs that define the action of the resource.
+func HasEndpoint(resource, action string) Matcher {
+	return &matcher{
+		desc: fmt.Sprintf("has endpoint %q %q", resource, action),
+		check: func(api *design.APIDefinition) (bool, string) {
+			_, reason := lookupAction(api, resource, action)
+			return reason == "", reason
+		},
+	}
+}
+
+// ResponseUsesMediaType matches designs where the response of the action of the resource uses the
+// media type with the given identifier or type name.
+func ResponseUsesMediaType(resource, action, response, mediaType string) Matcher {
+	return &matcher{
+		desc: fmt.Sprintf("response %q of %q %q uses media type %q", response, resource, action, mediaType),
+		check: func(api *design.APIDefinition) (bool, string) {
+			a, reason := lookupAction(api, resource, action)
+			if a == nil {
+				return false, reason
+			}
+			resp, ok := a.Responses[response]
+			if !ok {
+				return false, fmt.Sprintf("action %q of resource %q has no response %q", action, resource, response)
+			}
+			if resp.MediaType == "" {
+				return false, fmt.Sprintf("response %q has no media type", response)
+			}
+			if design.CanonicalIdentifier(resp.MediaType) == design.CanonicalIdentifier(mediaType) {
+				return true, ""
+			}
+			if mt := api.MediaTypeWithIdentifier(resp.MediaType); mt != nil && mt.TypeName == mediaType {
+				return true, ""
+			}
+			return false, fmt.Sprintf("response %q uses media type %q", response, resp.MediaType)
+		},
+	}
+}
+
+// AttributeRequired matches designs where the attribute at the given path of the user type or
+// media type with the given name is required. Paths to nested attributes use dots to separate the
+// attribute names, e.g. "address.city". Media types may also be given by identifier.
+func AttributeRequired(typeName, path string) Matcher {
+	return &matcher{
+		desc: fmt.Sprintf("attribute %q of %q is required", path, typeName),
+		check: func(api *design.APIDefinition) (bool, string) {
+			var att *design.AttributeDefinition
+			if ut, ok := api.Types[typeName]; ok {
+				att = ut.AttributeDefinition
+			} else if mt := lookupMediaType(api, typeName); mt != nil {
+				att = mt.AttributeDefinition
+			} else {
+				return false, fmt.Sprintf("no type %q", typeName)
+			}
+			names := strings.Split(path, ".")
+			for i, n := range names {
+				obj := att.Type.ToObject()
+				child, ok := obj[n]
+				if !ok {
+					return false, fmt.Sprintf("no attribute %q", strings.Join(names[:i+1], "."))
+				}
+				if !att.IsRequired(n) {
+					return false, fmt.Sprintf("attribute %q is optional", strings.Join(names[:i+1], "."))
+				}
+				att = child
+			}
+			return true, ""
+		},
+	}
+}
+
+// Match returns true if actual has the property.
+func (m *matcher) Match(actual interface{}) (bool, error) {
+	api, err := apiDefinition(actual)
+	if err != nil {
+		return false, err
+	}
+	ok, reason := m.check(api)
+	m.reason = reason
+	return ok, nil
+}
+
+// FailureMessage describes why actual does not have the property.
+func (m *matcher) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected design to satisfy: %s\n%s", m.desc, m.reason)
+}
+
+// NegatedFailureMessage describes why actual has the property.
+func (m *matcher) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected design not to satisfy: %s", m.desc)
+}
+
+// apiDefinition returns the API definition given to a matcher.
+func apiDefinition(actual interface{}) (*design.APIDefinition, error) {
+	switch a := actual.(type) {
+	case nil:
+		if design.Design == nil {
+			return nil, fmt.Errorf("no design, make sure the design package is imported and evaluated")
+		}
+		return design.Design, nil
+	case *design.APIDefinition:
+		if a == nil {
+			return nil, fmt.Errorf("nil API definition")
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("designtest matchers expect a *design.APIDefinition, got %T", actual)
+	}
+}
+
+// lookupAction returns the action of the resource if it exists, otherwise it returns the reason
+// why it doesn't.
+func lookupAction(api *design.APIDefinition, resource, action string) (*design.ActionDefinition, string) {
+	r, ok := api.Resources[resource]
+	if !ok {
+		return nil, fmt.Sprintf("no resource %q", resource)
+	}
+	a, ok := r.Actions[action]
+	if !ok {
+		return nil, fmt.Sprintf("resource %q has no action %q", resource, action)
+	}
+	return a, ""
+}
+
+// lookupMediaType returns the media type with the given type name or identifier, nil if there
+// isn't one.
+func lookupMediaType(api *design.APIDefinition, name string) *design.MediaTypeDefinition {
+	for _, mt := range api.MediaTypes {
+		if mt.TypeName == name {
+			return mt
+		}
+	}
+	if strings.Contains(name, "/") {
+		return api.MediaTypeWithIdentifier(name)
+	}
+	return nil
+}
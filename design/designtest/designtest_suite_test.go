@@ -0,0 +1,13 @@
+package designtest_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestDesignTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DesignTest Suite")
+}
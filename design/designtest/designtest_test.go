@@ -0,0 +1,134 @@
+package designtest_test
+
+import (
+	"fmt"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/design/designtest"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// recorder implements goatest.TInterface and records the test errors.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("designtest", func() {
+	var t *recorder
+	var api *APIDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		t = new(recorder)
+		API("cellar", func() {})
+		var BottlePayload = Type("BottlePayload", func() {
+			Attribute("name", String)
+			Attribute("origin", func() {
+				Attribute("country", String)
+				Attribute("region", String)
+				Required("country")
+			})
+			Required("name", "origin")
+		})
+		var BottleMedia = MediaType("application/vnd.goa.example.bottle+json", func() {
+			TypeName("Bottle")
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+				Required("id")
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+			})
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK, BottleMedia)
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(BottlePayload)
+				Response(Created)
+			})
+		})
+		api = designtest.Eval(t)
+	})
+
+	It("evaluates the design", func() {
+		Ω(t.errors).Should(BeEmpty())
+		Ω(api).ShouldNot(BeNil())
+		Ω(api.Name).Should(Equal("cellar"))
+	})
+
+	It("matches the endpoints", func() {
+		Ω(api).Should(designtest.HasEndpoint("bottle", "show"))
+		Ω(api).ShouldNot(designtest.HasEndpoint("bottle", "delete"))
+		Ω(api).ShouldNot(designtest.HasEndpoint("account", "show"))
+	})
+
+	It("matches the response media types by identifier or type name", func() {
+		Ω(api).Should(designtest.ResponseUsesMediaType("bottle", "show", "OK", "application/vnd.goa.example.bottle+json"))
+		Ω(api).Should(designtest.ResponseUsesMediaType("bottle", "show", "OK", "Bottle"))
+		Ω(api).ShouldNot(designtest.ResponseUsesMediaType("bottle", "show", "OK", "application/json"))
+		Ω(api).ShouldNot(designtest.ResponseUsesMediaType("bottle", "create", "Created", "Bottle"))
+	})
+
+	It("matches the required attributes", func() {
+		Ω(api).Should(designtest.AttributeRequired("BottlePayload", "name"))
+		Ω(api).Should(designtest.AttributeRequired("BottlePayload", "origin.country"))
+		Ω(api).ShouldNot(designtest.AttributeRequired("BottlePayload", "origin.region"))
+		Ω(api).ShouldNot(designtest.AttributeRequired("BottlePayload", "vintage"))
+		Ω(api).Should(designtest.AttributeRequired("Bottle", "id"))
+		Ω(api).ShouldNot(designtest.AttributeRequired("Bottle", "name"))
+	})
+
+	It("uses the global design when given nil", func() {
+		Ω(nil).Should(designtest.HasEndpoint("bottle", "show"))
+	})
+
+	It("describes the failures", func() {
+		m := designtest.AttributeRequired("BottlePayload", "origin.region")
+		ok, err := m.Match(api)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(ok).Should(BeFalse())
+		Ω(m.FailureMessage(api)).Should(ContainSubstring(`attribute "origin.region" is optional`))
+	})
+
+	It("reports the failures with Check", func() {
+		designtest.Check(t, api, designtest.HasEndpoint("bottle", "show"), designtest.HasEndpoint("bottle", "delete"))
+		Ω(t.errors).Should(HaveLen(1))
+		Ω(t.errors[0]).Should(ContainSubstring(`resource "bottle" has no action "delete"`))
+	})
+
+	Context("with an invalid design", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			t = new(recorder)
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response("Unknown")
+				})
+			})
+			api = designtest.Eval(t)
+		})
+
+		It("fails the test", func() {
+			Ω(t.errors).Should(HaveLen(1))
+			Ω(t.errors[0]).Should(ContainSubstring("design evaluation failed"))
+		})
+	})
+})
@@ -0,0 +1,42 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// validateDispatch makes sure the action can be called by the runtime packages that dispatch
+// calls to the service mux without an HTTP request, e.g. cron or mqtt. These calls carry no
+// headers and no credentials: the action cannot have required headers or be secured and the
+// actions of multi-tenant APIs that read the tenant from a header cannot be called. kind
+// qualifies the action in the error messages, e.g. "scheduled".
+func (a *ActionDefinition) validateDispatch(def dslengine.Definition, kind string, verr *dslengine.ValidationErrors) {
+	var headers []string
+	if a.Parent != nil {
+		a.IterateHeaders(func(name string, required bool, h *AttributeDefinition) error {
+			if required {
+				headers = append(headers, name)
+			}
+			return nil
+		})
+	}
+	if len(headers) > 0 {
+		verr.Add(def, "%s actions cannot have required headers, got %v", kind, headers)
+	}
+	if Design != nil && Design.Tenant != nil && Design.Tenant.Header != "" {
+		verr.Add(def, "%s actions cannot be defined in multi-tenant APIs that read the tenant from the %s header", kind, Design.Tenant.Header)
+	}
+	if a.secured() {
+		verr.Add(def, "%s actions cannot be secured, use NoSecurity", kind)
+	}
+}
+
+// secured returns true if the action requires credentials. The security of the action is
+// inherited from the resource and the API until it is finalized.
+func (a *ActionDefinition) secured() bool {
+	sec := a.Security
+	if sec == nil && a.Parent != nil {
+		sec = a.Parent.Security
+	}
+	if sec == nil && Design != nil {
+		sec = Design.Security
+	}
+	return sec != nil && sec.Scheme != nil && sec.Scheme.Kind != NoSecurityKind
+}
@@ -0,0 +1,74 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DownloadDefinition describes an action whose binary result is a file that clients may download
+// in several requests using Range requests and resume tokens.
+type DownloadDefinition struct {
+	// Filename is the name of the action parameter whose value is used as the file name in the
+	// Content-Disposition header, empty if the header does not list a file name.
+	Filename string
+	// Inline is true if the Content-Disposition header asks user agents to display the file
+	// rather than to save it.
+	Inline bool
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (d *DownloadDefinition) Context() string {
+	if d.Parent != nil {
+		return "download of " + d.Parent.Context()
+	}
+	return "download"
+}
+
+// Disposition returns the disposition type of the Content-Disposition header.
+func (d *DownloadDefinition) Disposition() string {
+	if d.Inline {
+		return "inline"
+	}
+	return "attachment"
+}
+
+// Response returns the OK response of the action that carries the file content, nil if there
+// isn't one.
+func (d *DownloadDefinition) Response() *ResponseDefinition {
+	if d.Parent == nil {
+		return nil
+	}
+	return d.Parent.Responses[OK]
+}
+
+// Validate makes sure the download is valid: the action must have a GET route, a binary OK
+// response and the file name attribute must be a string parameter.
+func (d *DownloadDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	a := d.Parent
+	if a == nil {
+		return verr
+	}
+	if !a.HasGETRoute() {
+		verr.Add(d, "only actions with a GET route may be downloadable")
+	}
+	resp := d.Response()
+	switch {
+	case resp == nil:
+		verr.Add(d, "downloadable actions must define an OK response")
+	case resp.Type != nil || (Design != nil && Design.MediaTypeWithIdentifier(resp.MediaType) != nil):
+		verr.Add(d, "the OK response of downloadable actions cannot use a media type or a type, the file content is sent as is")
+	}
+	if d.Filename != "" {
+		var att *AttributeDefinition
+		if params := a.AllParams(); params != nil {
+			att = params.Type.ToObject()[d.Filename]
+		}
+		switch {
+		case att == nil:
+			verr.Add(d, "file name parameter %#v is not defined", d.Filename)
+		case att.Type.Kind() != StringKind:
+			verr.Add(d, "file name parameter %#v must be a string", d.Filename)
+		}
+	}
+	return verr
+}
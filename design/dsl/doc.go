@@ -0,0 +1,29 @@
+/*
+Package dsl exposes the goa API DSL under a single package name so that designs can be written
+without dot imports:
+
+	package design
+
+	import "github.com/goadesign/goa/design/dsl"
+
+	var _ = dsl.API("cellar", func() {
+		dsl.Title("The virtual wine cellar")
+	})
+
+	var Bottle = dsl.MediaType("application/vnd.bottle", func() {
+		dsl.Attributes(func() {
+			dsl.Attribute("id", dsl.Integer, "ID of bottle")
+			dsl.Attribute("name", dsl.String, "Name of bottle")
+		})
+		dsl.View("default", func() {
+			dsl.Attribute("id")
+			dsl.Attribute("name")
+		})
+	})
+
+The package re-exports the DSL functions of the apidsl package together with the built-in data
+types of the design package. The functions are the apidsl functions themselves so that both
+packages may be used in the same design. The package is generated from the apidsl package, run
+"go generate" in the apidsl directory after adding DSL functions.
+*/
+package dsl
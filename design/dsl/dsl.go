@@ -0,0 +1,294 @@
+// Code generated by gen_funcs.go in package apidsl, DO NOT EDIT.
+
+package dsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+)
+
+// Built-in data types, see the design package.
+const (
+	Boolean  = design.Boolean
+	Integer  = design.Integer
+	Number   = design.Number
+	String   = design.String
+	DateTime = design.DateTime
+	UUID     = design.UUID
+	Any      = design.Any
+)
+
+// Built-in response names, see the design package.
+const (
+	Continue                     = design.Continue
+	SwitchingProtocols           = design.SwitchingProtocols
+	OK                           = design.OK
+	Created                      = design.Created
+	Accepted                     = design.Accepted
+	NonAuthoritativeInfo         = design.NonAuthoritativeInfo
+	NoContent                    = design.NoContent
+	ResetContent                 = design.ResetContent
+	PartialContent               = design.PartialContent
+	MultiStatus                  = design.MultiStatus
+	MultipleChoices              = design.MultipleChoices
+	MovedPermanently             = design.MovedPermanently
+	Found                        = design.Found
+	SeeOther                     = design.SeeOther
+	NotModified                  = design.NotModified
+	UseProxy                     = design.UseProxy
+	TemporaryRedirect            = design.TemporaryRedirect
+	PermanentRedirect            = design.PermanentRedirect
+	BadRequest                   = design.BadRequest
+	Unauthorized                 = design.Unauthorized
+	PaymentRequired              = design.PaymentRequired
+	Forbidden                    = design.Forbidden
+	NotFound                     = design.NotFound
+	MethodNotAllowed             = design.MethodNotAllowed
+	NotAcceptable                = design.NotAcceptable
+	ProxyAuthRequired            = design.ProxyAuthRequired
+	RequestTimeout               = design.RequestTimeout
+	Conflict                     = design.Conflict
+	Gone                         = design.Gone
+	LengthRequired               = design.LengthRequired
+	PreconditionFailed           = design.PreconditionFailed
+	RequestEntityTooLarge        = design.RequestEntityTooLarge
+	RequestURITooLong            = design.RequestURITooLong
+	UnsupportedMediaType         = design.UnsupportedMediaType
+	RequestedRangeNotSatisfiable = design.RequestedRangeNotSatisfiable
+	ExpectationFailed            = design.ExpectationFailed
+	Teapot                       = design.Teapot
+	UnprocessableEntity          = design.UnprocessableEntity
+	Locked                       = design.Locked
+	PreconditionRequired         = design.PreconditionRequired
+	TooManyRequests              = design.TooManyRequests
+	RequestHeaderFieldsTooLarge  = design.RequestHeaderFieldsTooLarge
+	UnavailableForLegalReasons   = design.UnavailableForLegalReasons
+	InternalServerError          = design.InternalServerError
+	NotImplemented               = design.NotImplemented
+	BadGateway                   = design.BadGateway
+	ServiceUnavailable           = design.ServiceUnavailable
+	GatewayTimeout               = design.GatewayTimeout
+	HTTPVersionNotSupported      = design.HTTPVersionNotSupported
+	InsufficientStorage          = design.InsufficientStorage
+)
+
+// ErrorMedia is the built-in media type for error responses.
+var ErrorMedia = design.ErrorMedia
+
+// Definition types, see the design package.
+type (
+	APIDefinition       = design.APIDefinition
+	ActionDefinition    = design.ActionDefinition
+	AttributeDefinition = design.AttributeDefinition
+	MediaTypeDefinition = design.MediaTypeDefinition
+	ResourceDefinition  = design.ResourceDefinition
+	UserTypeDefinition  = design.UserTypeDefinition
+)
+
+// Constants of the apidsl package.
+const (
+	APIKeyMediaIdentifier = apidsl.APIKeyMediaIdentifier
+)
+
+// Variables of the apidsl package.
+var (
+	SupportedValidationFormats = apidsl.SupportedValidationFormats
+)
+
+// DSL functions, see the apidsl package.
+var (
+	API                   = apidsl.API
+	APIKeySecurity        = apidsl.APIKeySecurity
+	AbsentFields          = apidsl.AbsentFields
+	AccessCodeFlow        = apidsl.AccessCodeFlow
+	Action                = apidsl.Action
+	AdditiveOnly          = apidsl.AdditiveOnly
+	Alias                 = apidsl.Alias
+	ApplicationFlow       = apidsl.ApplicationFlow
+	ArrayOf               = apidsl.ArrayOf
+	ArrayStyle            = apidsl.ArrayStyle
+	Attribute             = apidsl.Attribute
+	Attributes            = apidsl.Attributes
+	Audit                 = apidsl.Audit
+	AutoHead              = apidsl.AutoHead
+	AutoOptions           = apidsl.AutoOptions
+	BasePath              = apidsl.BasePath
+	BasicAuthSecurity     = apidsl.BasicAuthSecurity
+	CONNECT               = apidsl.CONNECT
+	CRUD                  = apidsl.CRUD
+	Cache                 = apidsl.Cache
+	CanonicalActionName   = apidsl.CanonicalActionName
+	Capture               = apidsl.Capture
+	ClientCertRequired    = apidsl.ClientCertRequired
+	CollectionOf          = apidsl.CollectionOf
+	CompensatedBy         = apidsl.CompensatedBy
+	Compress              = apidsl.Compress
+	CompressTypes         = apidsl.CompressTypes
+	Computed              = apidsl.Computed
+	Const                 = apidsl.Const
+	Consumes              = apidsl.Consumes
+	Contact               = apidsl.Contact
+	ContentType           = apidsl.ContentType
+	ContextValue          = apidsl.ContextValue
+	Cookie                = apidsl.Cookie
+	Credentials           = apidsl.Credentials
+	DELETE                = apidsl.DELETE
+	Decompress            = apidsl.Decompress
+	Default               = apidsl.Default
+	DefaultFunc           = apidsl.DefaultFunc
+	DefaultMedia          = apidsl.DefaultMedia
+	Description           = apidsl.Description
+	DisallowUnknownFields = apidsl.DisallowUnknownFields
+	Docs                  = apidsl.Docs
+	Downloadable          = apidsl.Downloadable
+	DuplicateAttributes   = apidsl.DuplicateAttributes
+	Email                 = apidsl.Email
+	EmitAliases           = apidsl.EmitAliases
+	EmitEvent             = apidsl.EmitEvent
+	Encodings             = apidsl.Encodings
+	Encrypted             = apidsl.Encrypted
+	Enum                  = apidsl.Enum
+	Envelope              = apidsl.Envelope
+	Error                 = apidsl.Error
+	Example               = apidsl.Example
+	Exclude               = apidsl.Exclude
+	Expose                = apidsl.Expose
+	FilenameFrom          = apidsl.FilenameFrom
+	Files                 = apidsl.Files
+	Flag                  = apidsl.Flag
+	Flags                 = apidsl.Flags
+	Format                = apidsl.Format
+	FormatWith            = apidsl.FormatWith
+	Frozen                = apidsl.Frozen
+	Function              = apidsl.Function
+	GET                   = apidsl.GET
+	GoType                = apidsl.GoType
+	HEAD                  = apidsl.HEAD
+	HashOf                = apidsl.HashOf
+	Header                = apidsl.Header
+	Headers               = apidsl.Headers
+	Host                  = apidsl.Host
+	IdentifierTemplate    = apidsl.IdentifierTemplate
+	ImplicitFlow          = apidsl.ImplicitFlow
+	Import                = apidsl.Import
+	Inline                = apidsl.Inline
+	Interceptor           = apidsl.Interceptor
+	JSONRPC               = apidsl.JSONRPC
+	JWTSecurity           = apidsl.JWTSecurity
+	License               = apidsl.License
+	Link                  = apidsl.Link
+	Links                 = apidsl.Links
+	LoadShed              = apidsl.LoadShed
+	Location              = apidsl.Location
+	MQTT                  = apidsl.MQTT
+	Maintenance           = apidsl.Maintenance
+	ManageAPIKeys         = apidsl.ManageAPIKeys
+	MaxAge                = apidsl.MaxAge
+	MaxArrayLength        = apidsl.MaxArrayLength
+	MaxCapturedBody       = apidsl.MaxCapturedBody
+	MaxConcurrent         = apidsl.MaxConcurrent
+	MaxDecompressedSize   = apidsl.MaxDecompressedSize
+	MaxDepth              = apidsl.MaxDepth
+	MaxLength             = apidsl.MaxLength
+	MaxMapSize            = apidsl.MaxMapSize
+	MaxUploadSize         = apidsl.MaxUploadSize
+	MaxWait               = apidsl.MaxWait
+	Maximum               = apidsl.Maximum
+	Media                 = apidsl.Media
+	MediaType             = apidsl.MediaType
+	Member                = apidsl.Member
+	Metadata              = apidsl.Metadata
+	MethodOverride        = apidsl.MethodOverride
+	Methods               = apidsl.Methods
+	MinLength             = apidsl.MinLength
+	MinSize               = apidsl.MinSize
+	MinVersion            = apidsl.MinVersion
+	Minimum               = apidsl.Minimum
+	Module                = apidsl.Module
+	Name                  = apidsl.Name
+	NoExample             = apidsl.NoExample
+	NoSecurity            = apidsl.NoSecurity
+	OAuth2Security        = apidsl.OAuth2Security
+	OPTIONS               = apidsl.OPTIONS
+	OptionalFields        = apidsl.OptionalFields
+	OptionalPayload       = apidsl.OptionalPayload
+	Origin                = apidsl.Origin
+	Outbox                = apidsl.Outbox
+	OverrideField         = apidsl.OverrideField
+	OverrideHeader        = apidsl.OverrideHeader
+	PATCH                 = apidsl.PATCH
+	PII                   = apidsl.PII
+	POST                  = apidsl.POST
+	PUT                   = apidsl.PUT
+	Package               = apidsl.Package
+	Param                 = apidsl.Param
+	Params                = apidsl.Params
+	Parent                = apidsl.Parent
+	ParseWith             = apidsl.ParseWith
+	Partial               = apidsl.Partial
+	PasswordFlow          = apidsl.PasswordFlow
+	Pattern               = apidsl.Pattern
+	Payload               = apidsl.Payload
+	PayloadLimits         = apidsl.PayloadLimits
+	PresignedUpload       = apidsl.PresignedUpload
+	Produces              = apidsl.Produces
+	Profile               = apidsl.Profile
+	Protocol              = apidsl.Protocol
+	QoS                   = apidsl.QoS
+	Query                 = apidsl.Query
+	ReadOnly              = apidsl.ReadOnly
+	ReadsPayload          = apidsl.ReadsPayload
+	ReadsResult           = apidsl.ReadsResult
+	Redact                = apidsl.Redact
+	Reference             = apidsl.Reference
+	RequestID             = apidsl.RequestID
+	Required              = apidsl.Required
+	Resource              = apidsl.Resource
+	Response              = apidsl.Response
+	ResponseTemplate      = apidsl.ResponseTemplate
+	ResultTopic           = apidsl.ResultTopic
+	ResumableUpload       = apidsl.ResumableUpload
+	Role                  = apidsl.Role
+	Routing               = apidsl.Routing
+	SWR                   = apidsl.SWR
+	Scalar                = apidsl.Scalar
+	Schedule              = apidsl.Schedule
+	SchemaSnapshot        = apidsl.SchemaSnapshot
+	Scheme                = apidsl.Scheme
+	Scope                 = apidsl.Scope
+	Security              = apidsl.Security
+	Sensitive             = apidsl.Sensitive
+	Server                = apidsl.Server
+	SessionData           = apidsl.SessionData
+	SessionEndpoints      = apidsl.SessionEndpoints
+	SessionSecurity       = apidsl.SessionSecurity
+	Status                = apidsl.Status
+	Step                  = apidsl.Step
+	Steps                 = apidsl.Steps
+	StreamingPayload      = apidsl.StreamingPayload
+	StringEncoded         = apidsl.StringEncoded
+	TLS                   = apidsl.TLS
+	TRACE                 = apidsl.TRACE
+	Tenant                = apidsl.Tenant
+	TermsOfService        = apidsl.TermsOfService
+	TimeFormat            = apidsl.TimeFormat
+	Title                 = apidsl.Title
+	TokenURL              = apidsl.TokenURL
+	Trailer               = apidsl.Trailer
+	Trailers              = apidsl.Trailers
+	Trait                 = apidsl.Trait
+	Type                  = apidsl.Type
+	TypeName              = apidsl.TypeName
+	URL                   = apidsl.URL
+	UseTrait              = apidsl.UseTrait
+	ValidateWith          = apidsl.ValidateWith
+	Var                   = apidsl.Var
+	Variable              = apidsl.Variable
+	VaryOn                = apidsl.VaryOn
+	VendorPrefix          = apidsl.VendorPrefix
+	Version               = apidsl.Version
+	View                  = apidsl.View
+	Workflow              = apidsl.Workflow
+	WritesPayload         = apidsl.WritesPayload
+	WritesResult          = apidsl.WritesResult
+)
@@ -0,0 +1,13 @@
+package dsl_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestDSL(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DSL Suite")
+}
@@ -0,0 +1,66 @@
+package dsl_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/dsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("dsl", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("evaluates designs written without dot imports", func() {
+		dsl.API("cellar", func() {
+			dsl.Title("The virtual wine cellar")
+		})
+		bottle := dsl.MediaType("application/vnd.bottle", func() {
+			dsl.Attributes(func() {
+				dsl.Attribute("id", dsl.Integer, "ID of bottle")
+				dsl.Attribute("name", dsl.String)
+				dsl.Required("id")
+			})
+			dsl.View("default", func() {
+				dsl.Attribute("id")
+				dsl.Attribute("name")
+			})
+		})
+		dsl.Resource("bottle", func() {
+			dsl.Action("show", func() {
+				dsl.Routing(dsl.GET("/:id"))
+				dsl.Response(dsl.OK, bottle)
+			})
+		})
+		Ω(dslengine.Run()).Should(Succeed())
+		Ω(design.Design.Title).Should(Equal("The virtual wine cellar"))
+		Ω(design.Design.MediaTypes).Should(HaveKey("application/vnd.bottle"))
+		Ω(design.Design.Resources["bottle"].Actions).Should(HaveKey("show"))
+	})
+
+	It("re-exports all the apidsl functions", func() {
+		fset := token.NewFileSet()
+		filter := func(fi os.FileInfo) bool {
+			return !strings.HasSuffix(fi.Name(), "_test.go") && fi.Name() != "gen_funcs.go"
+		}
+		pkgs, err := parser.ParseDir(fset, "../apidsl", filter, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		f, err := parser.ParseFile(fset, "dsl.go", nil, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		for _, file := range pkgs["apidsl"].Files {
+			for _, decl := range file.Decls {
+				if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.IsExported() {
+					Ω(f.Scope.Lookup(fn.Name.Name)).ShouldNot(BeNil(), "missing "+fn.Name.Name+", run go generate in the apidsl package")
+				}
+			}
+		}
+	})
+})
@@ -57,7 +57,7 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 // DupType creates a copy of the given data type.
 func (d *dupper) DupType(t DataType) DataType {
 	switch actual := t.(type) {
-	case Primitive:
+	case Primitive, *ScalarDefinition, *FlagsDefinition:
 		return t
 	case *Array:
 		return &Array{ElemType: d.DupAttribute(actual.ElemType)}
@@ -0,0 +1,58 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DuplicatePolicy describes how the attributes declared multiple times in the same object are
+// handled.
+type DuplicatePolicy string
+
+const (
+	// DuplicateError reports an error for each attribute declared multiple times. It also
+	// reports an error when an attribute declaration changes the type of the attribute with
+	// the same name defined by the reference type, see Reference. This is the default policy.
+	DuplicateError DuplicatePolicy = "error"
+	// DuplicateLastWins keeps the last declaration of the attributes declared multiple times.
+	DuplicateLastWins DuplicatePolicy = "last-wins"
+	// DuplicateMerge merges the declarations of the attributes declared multiple times: each
+	// declaration applies on top of the previous ones the same way declarations apply on top
+	// of the attributes of the reference type.
+	DuplicateMerge DuplicatePolicy = "merge"
+)
+
+// DuplicatePolicyMetadata is the metadata key set by DuplicateAttributes on the object attributes
+// that do not use the API policy.
+const DuplicatePolicyMetadata = "duplicate:policy"
+
+// DuplicatePolicy returns the policy applied to the child attributes declared multiple times in
+// the object attribute.
+func (a *AttributeDefinition) DuplicatePolicy() DuplicatePolicy {
+	if p, ok := a.Metadata[DuplicatePolicyMetadata]; ok && len(p) > 0 {
+		return DuplicatePolicy(p[0])
+	}
+	if Design != nil && Design.DuplicatePolicy != "" {
+		return Design.DuplicatePolicy
+	}
+	return DuplicateError
+}
+
+// AddDuplicate records that the child attribute with the given name was declared multiple times
+// or, if referenced is true, that its declaration changes the type defined by the reference type.
+// Validate reports an error for each recorded duplicate.
+func (a *AttributeDefinition) AddDuplicate(name string, referenced bool) {
+	msg := fmt.Sprintf("attribute %#v is declared multiple times", name)
+	if referenced {
+		msg = fmt.Sprintf("attribute %#v changes the type of the referenced attribute", name)
+	}
+	a.duplicates = append(a.duplicates, msg)
+}
+
+// validateDuplicates reports the attributes recorded by AddDuplicate.
+func (a *AttributeDefinition) validateDuplicates(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	for _, msg := range a.duplicates {
+		verr.Add(parent, "%s%s, use DuplicateAttributes to change the policy", ctx, msg)
+	}
+}
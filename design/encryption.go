@@ -0,0 +1,30 @@
+package design
+
+// EncryptedMetadata is the metadata key set by Encrypted on the attributes whose values are
+// encrypted on the wire, its value is the alias of the encryption key.
+const EncryptedMetadata = "encrypted:key"
+
+// EncryptionKey returns the alias of the key used to encrypt the attribute values or the empty
+// string if the attribute is not encrypted.
+func (a *AttributeDefinition) EncryptionKey() string {
+	if keys, ok := a.Metadata[EncryptedMetadata]; ok && len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// EncryptedAttributes returns the names of the top level attributes of the object that are
+// encrypted on the wire sorted alphabetically. It returns nil if the attribute is not an object.
+func (a *AttributeDefinition) EncryptedAttributes() []string {
+	if a == nil || a.Type == nil || !a.Type.IsObject() {
+		return nil
+	}
+	var names []string
+	a.Type.ToObject().IterateAttributes(func(n string, att *AttributeDefinition) error {
+		if att.EncryptionKey() != "" {
+			names = append(names, n)
+		}
+		return nil
+	})
+	return names
+}
@@ -0,0 +1,34 @@
+package design
+
+// EnvelopeItems is the name of the attribute of collection envelopes that holds the collection
+// elements.
+const EnvelopeItems = "items"
+
+// IsEnvelope returns true if the media type is a collection rendered as an envelope object that
+// holds the elements in its "items" attribute, see the Envelope DSL.
+func (m *MediaTypeDefinition) IsEnvelope() bool {
+	return m.Envelope != nil
+}
+
+// EnvelopeElem returns the media type of the elements of an envelope collection, nil if the media
+// type is not an envelope.
+func (m *MediaTypeDefinition) EnvelopeElem() *MediaTypeDefinition {
+	if !m.IsEnvelope() || !m.Type.IsObject() {
+		return nil
+	}
+	items, ok := m.Type.ToObject()[EnvelopeItems]
+	if !ok || !items.Type.IsArray() {
+		return nil
+	}
+	elem, _ := items.Type.ToArray().ElemType.Type.(*MediaTypeDefinition)
+	return elem
+}
+
+// envelopeElem returns the element media type of the envelope m if n is the name of the
+// attribute holding the envelope items, nil otherwise.
+func envelopeElem(m *MediaTypeDefinition, n string) *MediaTypeDefinition {
+	if n != EnvelopeItems {
+		return nil
+	}
+	return m.EnvelopeElem()
+}
@@ -0,0 +1,111 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ErrorDefinition describes a sentinel error that action implementations return to produce one of
+// the action responses. The generated code matches the errors returned by the actions against the
+// sentinel errors with errors.Is and sends the corresponding response.
+type ErrorDefinition struct {
+	// Name of the error, also used as the code of the error responses.
+	Name string
+	// Response is the name of the response sent when an action returns the error.
+	Response string
+	// Description of the error.
+	Description string
+	// Parent is the API or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *ErrorDefinition) Context() string {
+	ctx := fmt.Sprintf("error %#v", e.Name)
+	if e.Parent != nil {
+		ctx += " of " + e.Parent.Context()
+	}
+	return ctx
+}
+
+// Validate makes sure the error maps to an error response with no body or with the error media
+// type in all the actions that define the response and that at least one action does.
+func (e *ErrorDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if e.Name == "" {
+		verr.Add(e, "error name cannot be empty")
+	}
+	if e.Response == "" {
+		verr.Add(e, "error response cannot be empty")
+		return verr
+	}
+	found := false
+	check := func(a *ActionDefinition) error {
+		resp, ok := a.Responses[e.Response]
+		if !ok {
+			return nil
+		}
+		found = true
+		if resp.Status < 400 {
+			verr.Add(e, "response %#v of %s has status code %d, errors must map to responses with status code 400 or more", e.Response, a.Context(), resp.Status)
+		}
+		if !errorBody(resp) {
+			verr.Add(e, "response %#v of %s must have no body or use the error media type", e.Response, a.Context())
+		}
+		return nil
+	}
+	if r, ok := e.Parent.(*ResourceDefinition); ok {
+		r.IterateActions(check)
+	} else {
+		Design.IterateResources(func(r *ResourceDefinition) error {
+			return r.IterateActions(check)
+		})
+	}
+	if !found {
+		verr.Add(e, "no action defines response %#v", e.Response)
+	}
+	return verr
+}
+
+// AllErrors returns the errors that apply to the resource actions: the errors defined on the API
+// followed by the errors defined on the resource.
+func (r *ResourceDefinition) AllErrors() []*ErrorDefinition {
+	var errs []*ErrorDefinition
+	if Design != nil {
+		errs = append(errs, Design.Errors...)
+	}
+	return append(errs, r.Errors...)
+}
+
+// MappedErrors returns the errors defined on the API or the parent resource that map to one of
+// the action responses.
+func (a *ActionDefinition) MappedErrors() []*ErrorDefinition {
+	if a.Parent == nil {
+		return nil
+	}
+	var errs []*ErrorDefinition
+	for _, e := range a.Parent.AllErrors() {
+		if _, ok := a.Responses[e.Response]; ok {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// errorBody returns true if the response has no body or if its body uses the error media type.
+func errorBody(resp *ResponseDefinition) bool {
+	if mt, ok := resp.Type.(*MediaTypeDefinition); ok {
+		return mt.IsError()
+	}
+	if resp.Type != nil {
+		return false
+	}
+	if resp.MediaType == "" {
+		return true
+	}
+	if mt := Design.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+		return mt.IsError()
+	}
+	return CanonicalIdentifier(resp.MediaType) == CanonicalIdentifier(ErrorMediaIdentifier)
+}
@@ -0,0 +1,106 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var apiErrors, resourceErrors func()
+	var notFound func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiErrors = func() {}
+		resourceErrors = func() {
+			Error("not_found", NotFound, "Bottle does not exist")
+		}
+		notFound = func() {
+			Response(NotFound, ErrorMedia)
+		}
+	})
+
+	JustBeforeEach(func() {
+		API("test", apiErrors)
+		Resource("bottle", func() {
+			resourceErrors()
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK, "application/json")
+				notFound()
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Response(OK, "application/json")
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("mapping to an error response", func() {
+		It("maps the error in the actions that define the response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			res := Design.Resources["bottle"]
+			Ω(res.Errors).Should(HaveLen(1))
+			Ω(res.Errors[0].Description).Should(Equal("Bottle does not exist"))
+			Ω(res.Actions["show"].MappedErrors()).Should(Equal(res.Errors))
+			Ω(res.Actions["create"].MappedErrors()).Should(BeEmpty())
+		})
+	})
+
+	Context("declared in the API", func() {
+		BeforeEach(func() {
+			apiErrors = func() {
+				Error("not_found", NotFound)
+			}
+			resourceErrors = func() {}
+		})
+
+		It("applies to the actions of all resources", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].Actions["show"].MappedErrors()).Should(Equal(Design.Errors))
+		})
+	})
+
+	Context("defined twice", func() {
+		BeforeEach(func() {
+			resourceErrors = func() {
+				Error("not_found", NotFound)
+				Error("not_found", NotFound)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`error "not_found" is defined twice`))
+		})
+	})
+
+	Context("mapping to a response no action defines", func() {
+		BeforeEach(func() {
+			notFound = func() {}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`no action defines response "NotFound"`))
+		})
+	})
+
+	Context("mapping to a successful response", func() {
+		BeforeEach(func() {
+			resourceErrors = func() {
+				Error("not_found", OK)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("errors must map to responses with status code 400 or more"))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must have no body or use the error media type"))
+		})
+	})
+})
@@ -0,0 +1,73 @@
+package design
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// EventDefinition describes the CloudEvents published after each successful call to the actions
+// that mutate resources.
+type EventDefinition struct {
+	// Type is the CloudEvents type of the events, empty to use the default type built from the
+	// API, resource and action names.
+	Type string
+	// Parent is the action or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *EventDefinition) Context() string {
+	if e.Parent != nil {
+		return "event of " + e.Parent.Context()
+	}
+	return "event"
+}
+
+// Validate makes sure the event definition is valid: resources cannot set the event type since
+// it is specific to each action.
+func (e *EventDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if _, ok := e.Parent.(*ResourceDefinition); ok && e.Type != "" {
+		verr.Add(e, "event type cannot be set in a resource, set it in each action instead")
+	}
+	return verr
+}
+
+// IsMutating returns true if at least one of the action routes uses the POST, PUT, PATCH or
+// DELETE method.
+func (a *ActionDefinition) IsMutating() bool {
+	for _, r := range a.Routes {
+		switch r.Verb {
+		case "POST", "PUT", "PATCH", "DELETE":
+			return true
+		}
+	}
+	return false
+}
+
+// AllEvent returns the events published by the action: the action definition if any, the
+// resource definition if the action mutates resources, nil otherwise.
+func (a *ActionDefinition) AllEvent() *EventDefinition {
+	if a.Event != nil {
+		return a.Event
+	}
+	if a.Parent != nil && a.IsMutating() {
+		return a.Parent.Event
+	}
+	return nil
+}
+
+// EventType returns the CloudEvents type of the events published by the action: the type given
+// in the design if any, "<api>.<resource>.<action>" otherwise.
+func (a *ActionDefinition) EventType() string {
+	if a.Event != nil && a.Event.Type != "" {
+		return a.Event.Type
+	}
+	var api string
+	if Design != nil {
+		api = Design.Name
+	}
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s", api, a.Parent.Name, a.Name))
+}
@@ -0,0 +1,61 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// FlagDefinition describes the feature flag gating an action.
+type FlagDefinition struct {
+	// Name is the name of the flag looked up in the flag provider.
+	Name string
+	// Status is the status of the response sent when the flag is off, 0 to use the NotFound
+	// response of the action if any, the Forbidden response otherwise.
+	Status int
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (f *FlagDefinition) Context() string {
+	if f.Parent != nil {
+		return "flag of " + f.Parent.Context()
+	}
+	return "flag"
+}
+
+// Validate makes sure the flag has a name and that the action declares the response sent when the
+// flag is off.
+func (f *FlagDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if f.Name == "" {
+		verr.Add(f, "flag name cannot be empty")
+	}
+	switch f.Status {
+	case 0, 404, 403:
+	default:
+		verr.Add(f, "invalid status %d, the response sent when the flag is off must be a 404 or a 403", f.Status)
+		return verr
+	}
+	if f.Response() == nil {
+		verr.Add(f, "action must declare a NotFound or Forbidden response sent when the flag is off")
+	}
+	return verr
+}
+
+// Response returns the response of the action sent when the flag is off, nil if the action does
+// not declare it.
+func (f *FlagDefinition) Response() *ResponseDefinition {
+	if f.Parent == nil {
+		return nil
+	}
+	statuses := []int{404, 403}
+	if f.Status != 0 {
+		statuses = []int{f.Status}
+	}
+	for _, status := range statuses {
+		for _, r := range f.Parent.Responses {
+			if r.Status == status {
+				return r
+			}
+		}
+	}
+	return nil
+}
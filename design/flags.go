@@ -0,0 +1,135 @@
+package design
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// FlagsDefinition describes a set of named flags such as permissions. Values are sent on the wire as
+// arrays of flag names and the generated code represents them with a bitmask Go type. Since the
+// definition embeds the wire array type, flags are accepted wherever a DataType is.
+type FlagsDefinition struct {
+	// Array is the wire representation of the flags: an array of strings whose values are
+	// the flag names.
+	*Array
+	// TypeName is the name of the flags type in the design.
+	TypeName string
+	// GoType is the name of the generated bitmask Go type.
+	GoType string
+	// Names lists the flag names in bit order.
+	Names []string
+}
+
+// MaxFlags is the maximum number of flags a flags type may define, the generated Go types are
+// uint64 bitmasks.
+const MaxFlags = 64
+
+// NewFlagsDefinition creates a flags type with the given name, Go type and flag names.
+func NewFlagsDefinition(name, goType string, names ...string) *FlagsDefinition {
+	values := make([]interface{}, len(names))
+	for i, n := range names {
+		values[i] = n
+	}
+	elem := &AttributeDefinition{
+		Type:       String,
+		Validation: &dslengine.ValidationDefinition{Values: values},
+	}
+	return &FlagsDefinition{
+		Array:    &Array{ElemType: elem},
+		TypeName: name,
+		GoType:   goType,
+		Names:    names,
+	}
+}
+
+// Context returns the generic definition name used in error messages.
+func (f *FlagsDefinition) Context() string {
+	if f.TypeName != "" {
+		return fmt.Sprintf("flags %#v", f.TypeName)
+	}
+	return "unnamed flags"
+}
+
+// IsCompatible returns true if val is an array of flag names.
+func (f *FlagsDefinition) IsCompatible(val interface{}) bool {
+	if !f.Array.IsCompatible(val) {
+		return false
+	}
+	v := reflect.ValueOf(val)
+	for i := 0; i < v.Len(); i++ {
+		if !f.isName(v.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateExample returns a random array of distinct flag names.
+func (f *FlagsDefinition) GenerateExample(r *RandomGenerator, seen []string) interface{} {
+	var names []string
+	for _, n := range f.Names {
+		if r.Int()%2 == 0 {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 && len(f.Names) > 0 {
+		names = f.Names[:1]
+	}
+	return names
+}
+
+// isName returns true if v is one of the flag names.
+func (f *FlagsDefinition) isName(v interface{}) bool {
+	for _, n := range f.Names {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate makes sure the flag names are unique and that there are not too many of them.
+func (f *FlagsDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if f.TypeName == "" {
+		verr.Add(f, "flags name cannot be empty")
+	}
+	if len(f.Names) == 0 {
+		verr.Add(f, "flags must define at least one flag")
+	}
+	if len(f.Names) > MaxFlags {
+		verr.Add(f, "flags cannot define more than %d flags", MaxFlags)
+	}
+	seen := make(map[string]bool, len(f.Names))
+	for _, n := range f.Names {
+		if n == "" {
+			verr.Add(f, "flag names cannot be empty")
+		}
+		if seen[n] {
+			verr.Add(f, "flag %#v is defined twice", n)
+		}
+		seen[n] = true
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// validateFlags makes sure the attribute does not define the built-in validations which do not
+// apply to the Go type of flags.
+func (a *AttributeDefinition) validateFlags(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	f, ok := a.Type.(*FlagsDefinition)
+	if !ok || a.Validation == nil {
+		return
+	}
+	v := a.Validation
+	if v.Values != nil || v.Format != "" || v.Pattern != "" || v.Minimum != nil || v.Maximum != nil ||
+		v.MinLength != nil || v.MaxLength != nil {
+		verr.Add(parent, "%sflags %s do not support validations", ctx, f.TypeName)
+	}
+}
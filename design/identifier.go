@@ -0,0 +1,110 @@
+package design
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DefaultIdentifierTemplate is the template used to build the identifiers of the media types
+// declared with a name when the API defines a vendor prefix but no identifier template.
+const DefaultIdentifierTemplate = "application/vnd.{org}.{name}+json"
+
+// IdentifierTemplateOrDefault returns the template used to build the identifiers of the media
+// types declared with a name, the empty string if the API does not define an identifier template
+// or a vendor prefix.
+func (a *APIDefinition) IdentifierTemplateOrDefault() string {
+	if a.IdentifierTemplate != "" {
+		return a.IdentifierTemplate
+	}
+	if a.VendorPrefix != "" {
+		return DefaultIdentifierTemplate
+	}
+	return ""
+}
+
+// ExpandIdentifier returns the identifier of the media type declared with the given name, e.g.
+// "bottle" or "bottle; type=collection", built from the API identifier template. The "{org}",
+// "{name}" and "{v}" placeholders of the template are replaced with the API vendor prefix, the
+// media type name and the API version respectively. The parameters of name are added to the
+// parameters of the template. ExpandIdentifier returns name unchanged if it is already an
+// identifier or if the API does not define an identifier template.
+func (a *APIDefinition) ExpandIdentifier(name string) string {
+	tmpl := a.IdentifierTemplateOrDefault()
+	if tmpl == "" || !isMediaTypeName(name) {
+		return name
+	}
+	base, params, err := mime.ParseMediaType(name)
+	if err != nil {
+		return name
+	}
+	expanded := strings.NewReplacer(
+		"{org}", strings.ToLower(a.VendorPrefix),
+		"{name}", strings.ToLower(base),
+		"{v}", a.Version,
+	).Replace(tmpl)
+	ebase, eparams, err := mime.ParseMediaType(expanded)
+	if err != nil {
+		return name
+	}
+	for k, v := range params {
+		eparams[k] = v
+	}
+	return mime.FormatMediaType(ebase, eparams)
+}
+
+// isMediaTypeName returns true if id is a media type name rather than an identifier, that is if
+// it does not contain a type and a subtype separated with a slash.
+func isMediaTypeName(id string) bool {
+	if i := strings.Index(id, ";"); i != -1 {
+		id = id[:i]
+	}
+	return id != "" && !strings.Contains(id, "/")
+}
+
+// resolveIdentifiers sets the identifiers of the media types declared with a name, including the
+// collections of such media types, using the API identifier template. It is called once the API
+// DSL has run and before the media type DSLs run.
+func (a *APIDefinition) resolveIdentifiers() {
+	if a.IdentifierTemplateOrDefault() == "" {
+		return
+	}
+	resolve := func(root map[string]*MediaTypeDefinition) {
+		for key, mt := range root {
+			if !isMediaTypeName(mt.Identifier) {
+				continue
+			}
+			mt.Identifier = a.ExpandIdentifier(mt.Identifier)
+			delete(root, key)
+			canonicalID := CanonicalIdentifier(mt.Identifier)
+			if other, ok := root[canonicalID]; ok && other != mt {
+				dslengine.ReportError("media type %#v with canonical identifier %#v is defined twice", mt.Identifier, canonicalID)
+				continue
+			}
+			root[canonicalID] = mt
+		}
+	}
+	resolve(a.MediaTypes)
+	resolve(GeneratedMediaTypes)
+	for _, r := range a.Responses {
+		r.MediaType = a.ExpandIdentifier(r.MediaType)
+	}
+}
+
+// validateIdentifierTemplate makes sure the identifier template expands to valid identifiers.
+func (a *APIDefinition) validateIdentifierTemplate(verr *dslengine.ValidationErrors) {
+	tmpl := a.IdentifierTemplateOrDefault()
+	if tmpl == "" {
+		return
+	}
+	if strings.Contains(tmpl, "{org}") && a.VendorPrefix == "" {
+		verr.Add(a, "identifier template %#v uses the {org} placeholder but the API does not define a vendor prefix", tmpl)
+	}
+	if strings.Contains(tmpl, "{v}") && a.Version == "" {
+		verr.Add(a, "identifier template %#v uses the {v} placeholder but the API does not define a version", tmpl)
+	}
+	if id := a.ExpandIdentifier("name"); isMediaTypeName(id) {
+		verr.Add(a, "identifier template %#v does not produce valid media type identifiers", tmpl)
+	}
+}
@@ -0,0 +1,134 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// InterceptorDefinition describes a cross-cutting concern that runs around the actions it is
+// attached to. The definition lists the payload and result attributes the interceptor reads and
+// writes so that the generated code can provide typed access to them.
+type InterceptorDefinition struct {
+	// Name of the interceptor
+	Name string
+	// Description of the interceptor
+	Description string
+	// ReadPayload lists the names of the payload attributes read by the interceptor.
+	ReadPayload []string
+	// WritePayload lists the names of the payload attributes written by the interceptor.
+	WritePayload []string
+	// ReadResult lists the names of the result attributes read by the interceptor.
+	ReadResult []string
+	// WriteResult lists the names of the result attributes written by the interceptor.
+	WriteResult []string
+	// DSLFunc contains the DSL used to create this definition if any.
+	DSLFunc func()
+}
+
+// DSL returns the DSL function
+func (i *InterceptorDefinition) DSL() func() {
+	return i.DSLFunc
+}
+
+// Context returns the generic definition name used in error messages.
+func (i *InterceptorDefinition) Context() string {
+	if i.Name != "" {
+		return fmt.Sprintf("interceptor %#v", i.Name)
+	}
+	return "unnamed interceptor"
+}
+
+// PayloadAttributes returns the sorted names of the payload attributes read or written by the
+// interceptor.
+func (i *InterceptorDefinition) PayloadAttributes() []string {
+	return mergeNames(i.ReadPayload, i.WritePayload)
+}
+
+// ResultAttributes returns the sorted names of the result attributes read or written by the
+// interceptor.
+func (i *InterceptorDefinition) ResultAttributes() []string {
+	return mergeNames(i.ReadResult, i.WriteResult)
+}
+
+// Validate makes sure the interceptor is properly named and accesses at least one attribute.
+func (i *InterceptorDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if i.Name == "" {
+		verr.Add(i, "interceptor name cannot be empty")
+	}
+	if len(i.PayloadAttributes()) == 0 && len(i.ResultAttributes()) == 0 {
+		verr.Add(i, "interceptor must read or write at least one payload or result attribute")
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// AllInterceptors returns the interceptors that apply to the action: the resource interceptors
+// followed by the action interceptors, each interceptor being listed once.
+func (a *ActionDefinition) AllInterceptors() []*InterceptorDefinition {
+	var res []*InterceptorDefinition
+	seen := make(map[*InterceptorDefinition]bool)
+	var all []*InterceptorDefinition
+	if a.Parent != nil {
+		all = append(all, a.Parent.Interceptors...)
+	}
+	all = append(all, a.Interceptors...)
+	for _, i := range all {
+		if !seen[i] {
+			seen[i] = true
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// ResultAttribute returns the definition of the result attribute with the given name. The result
+// attributes are looked up in the media types of the action successful responses. ResultAttribute
+// returns nil if none of the media types define the attribute.
+func (a *ActionDefinition) ResultAttribute(name string) *AttributeDefinition {
+	var statuses []int
+	byStatus := make(map[int]*ResponseDefinition)
+	for _, r := range a.Responses {
+		if r.Status >= 200 && r.Status < 300 {
+			statuses = append(statuses, r.Status)
+			byStatus[r.Status] = r
+		}
+	}
+	sort.Ints(statuses)
+	for _, s := range statuses {
+		r := byStatus[s]
+		var mt *MediaTypeDefinition
+		if r.Type != nil {
+			mt, _ = r.Type.(*MediaTypeDefinition)
+		} else if Design != nil {
+			mt = Design.MediaTypeWithIdentifier(r.MediaType)
+		}
+		if mt == nil || !mt.Type.IsObject() {
+			continue
+		}
+		if att, ok := mt.Type.ToObject()[name]; ok {
+			return att
+		}
+	}
+	return nil
+}
+
+// mergeNames returns the sorted union of the given names.
+func mergeNames(a, b []string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, n := range append(append([]string{}, a...), b...) {
+		if !seen[n] {
+			seen[n] = true
+			res = append(res, n)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,76 @@
+package design
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// JSONRPCDefinition describes the JSON-RPC 2.0 endpoint that exposes the API actions. The calls
+// made to the endpoint are routed to the actions named "<resource>.<action>" by the method of the
+// call.
+type JSONRPCDefinition struct {
+	// Path is the path of the JSON-RPC endpoint, e.g. "/rpc".
+	Path string
+}
+
+// Context returns the generic definition name used in error messages.
+func (j *JSONRPCDefinition) Context() string {
+	return "JSON-RPC endpoint"
+}
+
+// Validate makes sure the endpoint path is absolute, that no action POST route uses it and that
+// the declared errors fit in the range of the JSON-RPC server error codes.
+func (j *JSONRPCDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if !strings.HasPrefix(j.Path, "/") {
+		verr.Add(j, "JSON-RPC endpoint path must start with /, got %#v", j.Path)
+	}
+	if Design == nil {
+		return verr
+	}
+	Design.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(a *ActionDefinition) error {
+			for _, ro := range a.Routes {
+				if ro.Verb == "POST" && ro.FullPath() == j.Path {
+					verr.Add(j, "JSON-RPC endpoint path %#v is already used by %s", j.Path, a.Context())
+				}
+			}
+			return nil
+		})
+	})
+	if n := len(j.ErrorNames()); n > 99 {
+		verr.Add(j, "too many declared errors (%d) to map to JSON-RPC server error codes, the maximum is 99", n)
+	}
+	return verr
+}
+
+// ErrorNames returns the names of the errors declared in the API and its resources without
+// duplicates: the errors of the API first followed by the errors of the resources sorted by
+// resource name. The JSON-RPC error code of the error at index i is -32001 - i.
+func (j *JSONRPCDefinition) ErrorNames() []string {
+	if Design == nil {
+		return nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	add := func(errs []*ErrorDefinition) {
+		for _, e := range errs {
+			if !seen[e.Name] {
+				seen[e.Name] = true
+				names = append(names, e.Name)
+			}
+		}
+	}
+	add(Design.Errors)
+	var resources []string
+	for n := range Design.Resources {
+		resources = append(resources, n)
+	}
+	sort.Strings(resources)
+	for _, n := range resources {
+		add(Design.Resources[n].Errors)
+	}
+	return names
+}
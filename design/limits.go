@@ -0,0 +1,51 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// LimitsDefinition caps the complexity of the request bodies so that services reject payloads
+// crafted to exhaust their resources before decoding them. A zero value means no limit.
+type LimitsDefinition struct {
+	// MaxDepth is the maximum nesting depth of the arrays and objects.
+	MaxDepth int
+	// MaxArrayLength is the maximum number of elements of the arrays.
+	MaxArrayLength int
+	// MaxMapSize is the maximum number of keys of the objects.
+	MaxMapSize int
+	// Parent is the API or resource definition.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (l *LimitsDefinition) Context() string {
+	if l.Parent != nil {
+		return "payload limits of " + l.Parent.Context()
+	}
+	return "payload limits"
+}
+
+// Validate makes sure the limits are not negative and that at least one is set.
+func (l *LimitsDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if l.MaxDepth < 0 {
+		verr.Add(l, "maximum nesting depth cannot be negative")
+	}
+	if l.MaxArrayLength < 0 {
+		verr.Add(l, "maximum array length cannot be negative")
+	}
+	if l.MaxMapSize < 0 {
+		verr.Add(l, "maximum map size cannot be negative")
+	}
+	if l.MaxDepth == 0 && l.MaxArrayLength == 0 && l.MaxMapSize == 0 {
+		verr.Add(l, "payload limits must set at least one of MaxDepth, MaxArrayLength or MaxMapSize")
+	}
+	return verr
+}
+
+// AllLimits returns the payload limits of the resource actions: the resource limits if any, the
+// API limits otherwise. It returns nil if the request bodies are not limited.
+func (r *ResourceDefinition) AllLimits() *LimitsDefinition {
+	if r.Limits != nil {
+		return r.Limits
+	}
+	return Design.Limits
+}
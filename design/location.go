@@ -0,0 +1,72 @@
+package design
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// LocationRegex is the regular expression used to capture the result attributes referenced by a
+// Location href template.
+var LocationRegex = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+// IsLocationTemplate returns true if the response Location header is computed from an href template
+// rather than copied from a result attribute.
+func (r *ResponseDefinition) IsLocationTemplate() bool {
+	return strings.Contains(r.LocationHeader, "{")
+}
+
+// LocationAttributes returns the names of the result attributes used to compute the value of the
+// response Location header in order of appearance. It returns nil if the response does not define
+// a Location header.
+func (r *ResponseDefinition) LocationAttributes() []string {
+	if r.LocationHeader == "" {
+		return nil
+	}
+	if !r.IsLocationTemplate() {
+		return []string{r.LocationHeader}
+	}
+	var names []string
+	for _, m := range LocationRegex.FindAllStringSubmatch(r.LocationHeader, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// validateLocation records errors in verr if the response Location header cannot be computed: the
+// status code must be 201 or a redirect and the attributes must be primitive attributes of the
+// response media type.
+func (r *ResponseDefinition) validateLocation(verr *dslengine.ValidationErrors) {
+	if r.LocationHeader == "" {
+		return
+	}
+	if r.Status != 201 && (r.Status < 300 || r.Status > 399) {
+		verr.Add(r, "Location can only be used in responses with status code 201 or 3xx, status is %d", r.Status)
+	}
+	var mt *MediaTypeDefinition
+	if m, ok := r.Type.(*MediaTypeDefinition); ok {
+		mt = m
+	} else if r.MediaType != "" {
+		mt = Design.MediaTypeWithIdentifier(r.MediaType)
+	}
+	if mt == nil || !mt.IsObject() {
+		verr.Add(r, "Location %#v requires the response media type to be an object", r.LocationHeader)
+		return
+	}
+	names := r.LocationAttributes()
+	if len(names) == 0 {
+		verr.Add(r, "Location template %#v does not reference any result attribute", r.LocationHeader)
+	}
+	o := mt.Type.ToObject()
+	for _, n := range names {
+		att, ok := o[n]
+		if !ok {
+			verr.Add(r, "Location uses attribute %#v which is not defined by media type %#v", n, mt.Identifier)
+			continue
+		}
+		if !att.Type.IsPrimitive() {
+			verr.Add(r, "Location uses attribute %#v which is not a primitive", n)
+		}
+	}
+}
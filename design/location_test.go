@@ -0,0 +1,103 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Location", func() {
+	var status int
+	var location string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		status = 201
+		location = "/bottles/{id}"
+	})
+
+	JustBeforeEach(func() {
+		bottle := MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("href", String)
+				Attribute("tags", ArrayOf(String))
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("href")
+				Attribute("tags")
+			})
+		})
+		Resource("bottle", func() {
+			Action("create", func() {
+				Routing(POST(""))
+				Response(Created, func() {
+					Status(status)
+					Media(bottle)
+					Location(location)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with an href template", func() {
+		It("records the template and documents the header", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["bottle"].Actions["create"].Responses[Created]
+			Ω(r.LocationHeader).Should(Equal("/bottles/{id}"))
+			Ω(r.IsLocationTemplate()).Should(BeTrue())
+			Ω(r.LocationAttributes()).Should(Equal([]string{"id"}))
+			Ω(r.Headers.Type.ToObject()).Should(HaveKey("Location"))
+		})
+	})
+
+	Context("with an attribute name", func() {
+		BeforeEach(func() {
+			location = "href"
+		})
+
+		It("uses the attribute", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["bottle"].Actions["create"].Responses[Created]
+			Ω(r.IsLocationTemplate()).Should(BeFalse())
+			Ω(r.LocationAttributes()).Should(Equal([]string{"href"}))
+		})
+	})
+
+	Context("with an unknown attribute", func() {
+		BeforeEach(func() {
+			location = "/bottles/{unknown}"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`Location uses attribute "unknown" which is not defined by media type "application/vnd.bottle"`))
+		})
+	})
+
+	Context("with a non primitive attribute", func() {
+		BeforeEach(func() {
+			location = "tags"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`Location uses attribute "tags" which is not a primitive`))
+		})
+	})
+
+	Context("with a status that does not use the Location header", func() {
+		BeforeEach(func() {
+			status = 200
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("Location can only be used in responses with status code 201 or 3xx, status is 200"))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package design
+
+const (
+	// DefaultMethodOverrideHeader is the name of the header that carries the overriding HTTP
+	// method when the design does not specify one.
+	DefaultMethodOverrideHeader = "X-HTTP-Method-Override"
+
+	// DefaultMethodOverrideField is the name of the form field that carries the overriding
+	// HTTP method when the design does not specify one.
+	DefaultMethodOverrideField = "_method"
+)
+
+// MethodOverrideDefinition describes how clients tunnel PUT, PATCH and DELETE requests through
+// POST requests. The overriding method is read from a request header or from a field of URL
+// encoded form bodies.
+type MethodOverrideDefinition struct {
+	// Header is the name of the header that carries the overriding method, empty if the
+	// header is not used.
+	Header string
+	// Field is the name of the form field that carries the overriding method, empty if form
+	// bodies are not used.
+	Field string
+}
+
+// Context returns the generic definition name used in error messages.
+func (m *MethodOverrideDefinition) Context() string {
+	return "method override"
+}
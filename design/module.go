@@ -0,0 +1,36 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+type (
+	// ModuleDefinition describes a design module: a set of top-level definitions declared by a
+	// Go package so that multiple designs can share them, see Import.
+	ModuleDefinition struct {
+		// Path is the import path identifying the module.
+		Path string
+		// Location is where the module is declared in the design sources if known.
+		Location dslengine.Location
+		// DSLFunc contains the DSL declaring the module definitions.
+		DSLFunc func()
+	}
+
+	// ImportDefinition describes the import of a design module by the design.
+	ImportDefinition struct {
+		// Module is the imported module.
+		Module *ModuleDefinition
+		// Prefix is the prefix added to the names of the module types, media types and
+		// resources if any.
+		Prefix string
+		// Location is where the module is imported in the design sources if known.
+		Location dslengine.Location
+	}
+)
+
+// Modules indexes the design modules by import path. Unlike the design definitions the modules
+// are not reset with the design so that they may be imported again.
+var Modules = make(map[string]*ModuleDefinition)
+
+// Context returns the generic definition name used in error messages.
+func (m *ModuleDefinition) Context() string {
+	return "design module " + m.Path
+}
@@ -0,0 +1,101 @@
+package design
+
+import (
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// MQTTDefinition describes the MQTT topic an action subscribes to. The messages published to the
+// topic carry the action payload, the topic levels starting with ":" capture action parameters.
+type MQTTDefinition struct {
+	// Topic is the topic the action subscribes to, e.g. "devices/:deviceID/telemetry".
+	Topic string
+	// QoS is the quality of service of the subscription and of the published results.
+	QoS int
+	// ResultTopic is the topic the action results are published to if any, it may use the
+	// parameters captured by Topic.
+	ResultTopic string
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (m *MQTTDefinition) Context() string {
+	if m.Parent != nil {
+		return "MQTT binding of " + m.Parent.Context()
+	}
+	return "MQTT binding"
+}
+
+// Validate makes sure the topics are valid MQTT topic names, that the QoS is 0, 1 or 2, that
+// the topic parameters are action parameters and that the action can be called without headers or
+// credentials, see validateDispatch.
+func (m *MQTTDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if m.QoS < 0 || m.QoS > 2 {
+		verr.Add(m, "QoS must be 0, 1 or 2, got %d", m.QoS)
+	}
+	validateTopic := func(kind, topic string) {
+		if topic == "" {
+			verr.Add(m, "%s cannot be empty", kind)
+			return
+		}
+		if strings.ContainsAny(topic, "+#") {
+			verr.Add(m, "%s %#v cannot contain MQTT wildcards, capture topic levels with :name instead", kind, topic)
+		}
+	}
+	validateTopic("topic", m.Topic)
+	if m.ResultTopic != "" {
+		validateTopic("result topic", m.ResultTopic)
+		captured := make(map[string]bool)
+		for _, p := range TopicParams(m.Topic) {
+			captured[p] = true
+		}
+		for _, p := range TopicParams(m.ResultTopic) {
+			if !captured[p] {
+				verr.Add(m, "result topic parameter %#v is not captured by topic %#v", p, m.Topic)
+			}
+		}
+	}
+	if m.Parent == nil {
+		return verr
+	}
+	var params Object
+	if ps := m.Parent.AllParams(); ps != nil {
+		params = ps.Type.ToObject()
+	}
+	for _, p := range TopicParams(m.Topic) {
+		if _, ok := params[p]; !ok {
+			verr.Add(m, "topic parameter %#v is not a parameter of the action", p)
+		}
+	}
+	m.Parent.validateDispatch(m, "MQTT", verr)
+	return verr
+}
+
+// TopicParams returns the names of the parameters captured by the levels of the topic that start
+// with ":".
+func TopicParams(topic string) []string {
+	var params []string
+	for _, l := range strings.Split(topic, "/") {
+		if strings.HasPrefix(l, ":") && len(l) > 1 {
+			params = append(params, l[1:])
+		}
+	}
+	return params
+}
+
+// UsesMQTT returns true if at least one of the API actions is bound to an MQTT topic.
+func (a *APIDefinition) UsesMQTT() bool {
+	found := false
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(act *ActionDefinition) error {
+			if act.MQTT != nil {
+				found = true
+			}
+			return nil
+		})
+	})
+	return found
+}
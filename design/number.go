@@ -0,0 +1,142 @@
+package design
+
+import "math/big"
+
+var (
+	// Int64 is the type for 64 bit integers, the generated fields are int64. Values are JSON
+	// integers unless StringEncoded is used.
+	Int64 = &ScalarDefinition{
+		Primitive:    Integer,
+		TypeName:     "Int64",
+		GoType:       "int64",
+		ParseFunc:    "goa.ParseInt64",
+		FormatFunc:   "goa.FormatInt64",
+		SchemaFormat: "int64",
+	}
+
+	// UInt64 is the type for 64 bit unsigned integers, the generated fields are uint64. Values
+	// are JSON integers unless StringEncoded is used. Values greater than the maximum int64
+	// value can only be represented with StringEncoded.
+	UInt64 = &ScalarDefinition{
+		Primitive:    Integer,
+		TypeName:     "UInt64",
+		GoType:       "uint64",
+		ParseFunc:    "goa.ParseUint64",
+		FormatFunc:   "goa.FormatUint64",
+		SchemaFormat: "uint64",
+		example: func(r *RandomGenerator) interface{} {
+			return r.Int() % 1000
+		},
+	}
+
+	// BigInt is the type for arbitrary precision integers, the generated fields are *big.Int.
+	// Values are JSON strings holding the decimal representation of the integer.
+	BigInt = &ScalarDefinition{
+		Primitive:    String,
+		TypeName:     "BigInt",
+		GoType:       "*big.Int",
+		PackagePath:  "math/big",
+		ParseFunc:    "goa.ParseBigInt",
+		FormatFunc:   "goa.FormatBigInt",
+		SchemaFormat: "big-integer",
+		MarshalWire:  true,
+		example: func(r *RandomGenerator) interface{} {
+			return new(big.Int).Mul(big.NewInt(int64(r.Int())), big.NewInt(1e9)).String()
+		},
+	}
+
+	// Decimal is the type for exact decimal numbers such as amounts of money, the generated
+	// fields are *big.Rat. Values are JSON strings holding the decimal representation of the
+	// number, e.g. "12.30". Use Scalar to define decimals based on other Go types.
+	Decimal = &ScalarDefinition{
+		Primitive:    String,
+		TypeName:     "Decimal",
+		GoType:       "*big.Rat",
+		PackagePath:  "math/big",
+		ParseFunc:    "goa.ParseDecimal",
+		FormatFunc:   "goa.FormatDecimal",
+		SchemaFormat: "decimal",
+		MarshalWire:  true,
+		example: func(r *RandomGenerator) interface{} {
+			return big.NewRat(int64(r.Int()%100000), 100).FloatString(2)
+		},
+	}
+
+	// stringEncoded lists the variants of the numeric types produced by StringEncoded.
+	stringEncoded = []*ScalarDefinition{
+		{
+			Primitive:    String,
+			TypeName:     "Integer",
+			GoType:       "int",
+			ParseFunc:    "goa.ParseIntString",
+			FormatFunc:   "goa.FormatIntString",
+			SchemaFormat: "int64",
+			MarshalWire:  true,
+			example: func(r *RandomGenerator) interface{} {
+				return big.NewInt(int64(r.Int())).String()
+			},
+		},
+		{
+			Primitive:    String,
+			TypeName:     "Number",
+			GoType:       "float64",
+			ParseFunc:    "goa.ParseFloatString",
+			FormatFunc:   "goa.FormatFloatString",
+			SchemaFormat: "double",
+			MarshalWire:  true,
+			example: func(r *RandomGenerator) interface{} {
+				return big.NewRat(int64(r.Int()%100000), 100).FloatString(2)
+			},
+		},
+		{
+			Primitive:    String,
+			TypeName:     "Int64",
+			GoType:       "int64",
+			ParseFunc:    "goa.ParseInt64String",
+			FormatFunc:   "goa.FormatInt64String",
+			SchemaFormat: "int64",
+			MarshalWire:  true,
+			example: func(r *RandomGenerator) interface{} {
+				return big.NewInt(int64(r.Int())).String()
+			},
+		},
+		{
+			Primitive:    String,
+			TypeName:     "UInt64",
+			GoType:       "uint64",
+			ParseFunc:    "goa.ParseUint64String",
+			FormatFunc:   "goa.FormatUint64String",
+			SchemaFormat: "uint64",
+			MarshalWire:  true,
+			example: func(r *RandomGenerator) interface{} {
+				return big.NewInt(int64(r.Int())).String()
+			},
+		},
+		BigInt,
+		Decimal,
+	}
+)
+
+// StringEncodedScalar returns the variant of the numeric type t whose values are encoded as JSON
+// strings, nil if t is not Integer, Number, Int64, UInt64, BigInt or Decimal.
+func StringEncodedScalar(t DataType) *ScalarDefinition {
+	var name, goType string
+	switch actual := t.(type) {
+	case Primitive:
+		if actual != Integer && actual != Number {
+			return nil
+		}
+		name = map[Primitive]string{Integer: "Integer", Number: "Number"}[actual]
+		goType = map[Primitive]string{Integer: "int", Number: "float64"}[actual]
+	case *ScalarDefinition:
+		name, goType = actual.TypeName, actual.GoType
+	default:
+		return nil
+	}
+	for _, s := range stringEncoded {
+		if s.TypeName == name && s.GoType == goType {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// validateObjectParam makes sure the parameter whose type is the given object user type can be
+// bound from the query string: it may not be a path parameter and its attributes must be
+// primitives or arrays of primitives. The values of the attributes are read from the query string
+// parameters named after the parameter and the attribute separated with a dot, e.g.
+// ?filter.status=active&filter.min=3.
+func (a *ActionDefinition) validateObjectParam(name string, ut *UserTypeDefinition, wildcards []string, verr *dslengine.ValidationErrors) {
+	for _, wc := range wildcards {
+		if wc == name {
+			verr.Add(a, "path parameter %s cannot be an object, only query parameters may use an object user type", name)
+			return
+		}
+	}
+	if _, ok := ut.Type.(Object); !ok {
+		verr.Add(a, "parameter %s must use a user type whose type is an object", name)
+		return
+	}
+	if ut.FieldPolicy() != PointerFields {
+		verr.Add(a, "user type %s of parameter %s cannot use the %s optional fields policy", ut.TypeName, name, ut.FieldPolicy())
+	}
+	for n, att := range ut.Type.ToObject() {
+		t := att.Type
+		if arr, ok := t.(*Array); ok {
+			t = arr.ElemType.Type
+		}
+		if _, ok := t.(Primitive); !ok || t.Kind() == AnyKind {
+			verr.Add(a, "attribute %s of parameter %s must be a primitive or an array of primitives", n, name)
+		}
+	}
+}
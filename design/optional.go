@@ -0,0 +1,138 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// FieldPolicy describes how the fields of the optional primitive attributes of an object are
+// generated so that services may distinguish an absent value from the zero value.
+type FieldPolicy string
+
+const (
+	// PointerFields generates pointer fields, nil when the attribute is absent. This is the
+	// default policy.
+	PointerFields FieldPolicy = "pointer"
+	// NullableFields generates fields using the goa.Null* wrappers (goa.NullString,
+	// goa.NullInt etc.) whose Valid field is false when the attribute is absent. Optional
+	// attributes whose type has no wrapper (UUID, any) remain pointers.
+	NullableFields FieldPolicy = "nullable"
+	// PresenceFields generates plain value fields and records the attributes present in the
+	// request in a bitmap exposed by the generated HasXXX methods.
+	PresenceFields FieldPolicy = "presence"
+)
+
+// FieldPolicyMetadata is the metadata key set by OptionalFields on the object attributes whose
+// optional primitive fields do not use the default pointer policy.
+const FieldPolicyMetadata = "optional:policy"
+
+// FieldPolicy returns the policy used to generate the optional primitive fields of the object
+// attribute.
+func (a *AttributeDefinition) FieldPolicy() FieldPolicy {
+	if p, ok := a.Metadata[FieldPolicyMetadata]; ok && len(p) > 0 {
+		return FieldPolicy(p[0])
+	}
+	return PointerFields
+}
+
+// IsNullable returns true if the field generated for the given attribute uses a goa.Null*
+// wrapper. The target attribute must be an object.
+func (a *AttributeDefinition) IsNullable(attName string) bool {
+	att := a.Type.ToObject()[attName]
+	if att == nil || !att.Type.IsPrimitive() {
+		return false
+	}
+	return a.FieldPolicy() == NullableFields && a.isOptional(attName) && hasNullWrapper(att.Type)
+}
+
+// HasPresence returns true if the presence of the given attribute is recorded in the bitmap of
+// the generated struct. The target attribute must be an object.
+func (a *AttributeDefinition) HasPresence(attName string) bool {
+	att := a.Type.ToObject()[attName]
+	if att == nil || !att.Type.IsPrimitive() {
+		return false
+	}
+	return a.FieldPolicy() == PresenceFields && a.isOptional(attName)
+}
+
+// PresenceAttributes returns the sorted names of the attributes whose presence is recorded. The
+// position of a name in the slice is the index of its bit in the bitmap.
+func (a *AttributeDefinition) PresenceAttributes() []string {
+	o := a.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	var names []string
+	for n := range o {
+		if a.HasPresence(n) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PresenceIndex returns the index of the bit recording the presence of the given attribute or -1
+// if the attribute presence is not recorded.
+func (a *AttributeDefinition) PresenceIndex(attName string) int {
+	for i, n := range a.PresenceAttributes() {
+		if n == attName {
+			return i
+		}
+	}
+	return -1
+}
+
+// isOptional returns true if the given attribute may be absent from the object and has no zero
+// value substitute.
+func (a *AttributeDefinition) isOptional(attName string) bool {
+	return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
+}
+
+// hasNullWrapper returns true if goa defines a Null* wrapper for the given primitive type.
+func hasNullWrapper(t DataType) bool {
+	if _, ok := t.(*ScalarDefinition); ok {
+		return false
+	}
+	switch t.Kind() {
+	case BooleanKind, IntegerKind, NumberKind, StringKind, DateTimeKind:
+		return true
+	}
+	return false
+}
+
+// finalizeFieldPolicy applies the API field policy to the types, media types and payloads that do
+// not define their own.
+func (a *APIDefinition) finalizeFieldPolicy() {
+	if a.FieldPolicy == "" || a.FieldPolicy == PointerFields {
+		return
+	}
+	apply := func(att *AttributeDefinition) {
+		if att == nil || !att.Type.IsObject() {
+			return
+		}
+		if _, ok := att.Metadata[FieldPolicyMetadata]; ok {
+			return
+		}
+		if att.Metadata == nil {
+			att.Metadata = make(dslengine.MetadataDefinition)
+		}
+		att.Metadata[FieldPolicyMetadata] = []string{string(a.FieldPolicy)}
+	}
+	for _, t := range a.Types {
+		apply(t.AttributeDefinition)
+	}
+	for _, mt := range a.MediaTypes {
+		if !mt.IsError() {
+			apply(mt.AttributeDefinition)
+		}
+	}
+	for _, r := range a.Resources {
+		for _, action := range r.Actions {
+			if action.Payload != nil {
+				apply(action.Payload.AttributeDefinition)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package design
+
+// OutboxMetadata is the metadata key set by Outbox on the resources and actions whose requests
+// run in a transaction of the outbox store.
+const OutboxMetadata = "outbox"
+
+// UsesOutbox returns true if the requests of the action run in a transaction of the outbox store
+// that records the action events. The setting applies if it is defined on the action or on its
+// resource and the action mutates resources.
+func (a *ActionDefinition) UsesOutbox() bool {
+	if _, ok := a.Metadata[OutboxMetadata]; ok {
+		return true
+	}
+	if a.Parent != nil && a.IsMutating() {
+		if _, ok := a.Parent.Metadata[OutboxMetadata]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesOutbox returns true if at least one of the API actions uses the transactional outbox.
+func (a *APIDefinition) UsesOutbox() bool {
+	found := false
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(act *ActionDefinition) error {
+			if act.UsesOutbox() {
+				found = true
+			}
+			return nil
+		})
+	})
+	return found
+}
@@ -0,0 +1,36 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// PartialViewMetadata is the metadata key set by Partial on the views that do not render all the
+// required attributes of their media type.
+const PartialViewMetadata = "view:partial"
+
+// IsPartial returns true if the view was declared with Partial and may thus omit required
+// attributes of its media type. The "link" view is always partial as links are meant to render a
+// subset of the media type attributes.
+func (v *ViewDefinition) IsPartial() bool {
+	if v.Name == "link" {
+		return true
+	}
+	if v.AttributeDefinition == nil {
+		return false
+	}
+	_, ok := v.Metadata[PartialViewMetadata]
+	return ok
+}
+
+// validateRequired records an error for each required attribute of the parent media type that the
+// view does not render unless the view is partial. Responses rendered with such a view would fail
+// the validations of the media type.
+func (v *ViewDefinition) validateRequired(verr *dslengine.ValidationErrors) {
+	if v.Parent == nil || v.Parent.Validation == nil || v.IsPartial() {
+		return
+	}
+	o := v.Type.ToObject()
+	for _, n := range v.Parent.Validation.Required {
+		if _, ok := o[n]; !ok {
+			verr.Add(v, "view does not render required attribute %#v, add it to the view or use Partial to declare a view that omits required attributes", n)
+		}
+	}
+}
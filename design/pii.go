@@ -0,0 +1,14 @@
+package design
+
+// PIIMetadata is the metadata key set by PII on the attributes holding personally identifiable
+// information, its value is the PII class, e.g. "email".
+const PIIMetadata = "pii:class"
+
+// PIIClass returns the class of personally identifiable information held by the attribute or the
+// empty string if the attribute is not classified.
+func (a *AttributeDefinition) PIIClass() string {
+	if classes, ok := a.Metadata[PIIMetadata]; ok && len(classes) > 0 {
+		return classes[0]
+	}
+	return ""
+}
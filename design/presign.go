@@ -0,0 +1,71 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// DefaultPresignExpiry is the number of seconds during which presigned upload URLs are valid when
+// the design does not specify it.
+const DefaultPresignExpiry = 900
+
+// PresignedUploadDefinition describes an action that lets clients upload files directly to an
+// object storage bucket using presigned PUT URLs. The action is invoked by the completion callback
+// requests sent by the clients once the files are uploaded.
+type PresignedUploadDefinition struct {
+	// Bucket is the name of the bucket the files are uploaded to.
+	Bucket string
+	// Expiry is the number of seconds during which the presigned URLs are valid.
+	Expiry uint
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (p *PresignedUploadDefinition) Context() string {
+	if p.Parent != nil {
+		return "presigned upload of " + p.Parent.Context()
+	}
+	return "presigned upload"
+}
+
+// Paths returns the full paths of the completion callback routes, one per action route: the
+// route path followed by the upload identifier wildcard and "/complete".
+func (p *PresignedUploadDefinition) Paths() []string {
+	if p.Parent == nil {
+		return nil
+	}
+	paths := make([]string, len(p.Parent.Routes))
+	for i, r := range p.Parent.Routes {
+		paths[i] = r.FullPath() + "/:" + UploadIDParam + "/complete"
+	}
+	return paths
+}
+
+// Validate makes sure the presigned upload is valid: the bucket must be set and the action must
+// only have POST routes, no payload and a NoContent response.
+func (p *PresignedUploadDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if p.Bucket == "" {
+		verr.Add(p, "bucket name cannot be empty")
+	}
+	if p.Expiry == 0 {
+		verr.Add(p, "presigned URL expiry must be greater than 0")
+	}
+	a := p.Parent
+	if a == nil {
+		return verr
+	}
+	for _, r := range a.Routes {
+		if r.Verb != "POST" {
+			verr.Add(p, "presigned upload actions may only have POST routes, got %s", r.Verb)
+		}
+	}
+	if a.Payload != nil {
+		verr.Add(p, "presigned upload actions cannot define a payload")
+	}
+	if a.Upload != nil {
+		verr.Add(p, "actions cannot define both a presigned upload and a resumable upload")
+	}
+	if _, ok := a.Responses[NoContent]; !ok {
+		verr.Add(p, "presigned upload actions must define a NoContent response sent once uploads complete")
+	}
+	return verr
+}
@@ -0,0 +1,53 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// PanicResponse returns the response with status code 500 defined in the API with ResponseTemplate
+// if any. The generated recovery middleware renders the panics of the handlers using this
+// response.
+func (a *APIDefinition) PanicResponse() *ResponseDefinition {
+	names := make([]string, 0, len(a.Responses))
+	for n := range a.Responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if r := a.Responses[n]; r.Status == 500 {
+			return r
+		}
+	}
+	return nil
+}
+
+// PanicContentType returns the content type of the responses sent by the generated recovery
+// middleware, the empty string if the API panic response has no body.
+func (a *APIDefinition) PanicContentType() string {
+	r := a.PanicResponse()
+	if r == nil {
+		return ""
+	}
+	mt, ok := r.Type.(*MediaTypeDefinition)
+	if !ok {
+		if r.MediaType == "" {
+			return ""
+		}
+		if mt = a.MediaTypeWithIdentifier(r.MediaType); mt == nil {
+			return r.MediaType
+		}
+	}
+	if mt.ContentType != "" {
+		return mt.ContentType
+	}
+	return mt.Identifier
+}
+
+// validatePanicResponse makes sure the API panic response can render internal errors.
+func (a *APIDefinition) validatePanicResponse(verr *dslengine.ValidationErrors) {
+	if r := a.PanicResponse(); r != nil && !errorBody(r) {
+		verr.Add(a, "response %#v renders the panics recovered by the generated middleware and must have no body or use the error media type", r.Name)
+	}
+}
@@ -0,0 +1,50 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Panic response", func() {
+	var panicDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		panicDSL = func() {
+			Status(500)
+			Media(ErrorMedia)
+		}
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			ResponseTemplate(InternalServerError, panicDSL)
+		})
+		dslengine.Run()
+	})
+
+	Context("using the error media type", func() {
+		It("is the API panic response", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.PanicResponse()).ShouldNot(BeNil())
+			Ω(Design.PanicContentType()).Should(Equal(ErrorMediaIdentifier))
+		})
+	})
+
+	Context("using another media type", func() {
+		BeforeEach(func() {
+			panicDSL = func() {
+				Status(500)
+				Media("application/json")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`response "InternalServerError" renders the panics recovered by the generated middleware and must have no body or use the error media type`))
+		})
+	})
+})
@@ -0,0 +1,18 @@
+package design
+
+// DefaultRequestIDHeader is the name of the header that carries the request ID when the design
+// does not specify one.
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// RequestIDDefinition describes how the API correlates requests using request IDs. The request
+// ID is read from a request header or generated, stored in the request context, written back to
+// the response header and added to the error responses.
+type RequestIDDefinition struct {
+	// Header is the name of the header that carries the request ID.
+	Header string
+}
+
+// Context returns the generic definition name used in error messages.
+func (r *RequestIDDefinition) Context() string {
+	return "request ID"
+}
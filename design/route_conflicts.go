@@ -0,0 +1,139 @@
+package design
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// routeSegment kinds ordered by routing priority: the router prefers literal segments over
+// wildcards and wildcards over catch-alls.
+const (
+	literalSegment = iota
+	wildcardSegment
+	catchAllSegment
+)
+
+// routeEndpoint is a route or file server mounted by the API.
+type routeEndpoint struct {
+	Verb     string
+	Path     string
+	Def      dslengine.Definition
+	Desc     string
+	segments []string
+}
+
+// validateRouteConflicts makes sure no two routes with the same HTTP method match the same
+// requests. Routes with the same shape (e.g. "/bottles/:id" and "/bottles/:bottleID") conflict,
+// routes that only overlap for some requests (e.g. "/bottles/:id" and "/bottles/new" or
+// "/files/*path" and "/files/index") are ambiguous since the router silently sends the requests
+// matching both to the most specific route.
+func (a *APIDefinition) validateRouteConflicts(verr *dslengine.ValidationErrors) {
+	var endpoints []*routeEndpoint
+	a.IterateResources(func(r *ResourceDefinition) error {
+		r.IterateActions(func(ac *ActionDefinition) error {
+			for _, ro := range ac.Routes {
+				desc := fmt.Sprintf("resource %#v action %#v", r.Name, ac.Name)
+				endpoints = append(endpoints, newRouteEndpoint(ro.Verb, ro.FullPath(), ac, desc))
+			}
+			return nil
+		})
+		r.IterateFileServers(func(fs *FileServerDefinition) error {
+			desc := fmt.Sprintf("resource %#v file server %#v", r.Name, fs.FilePath)
+			endpoints = append(endpoints, newRouteEndpoint("GET", fs.RequestPath, fs, desc))
+			return nil
+		})
+		return nil
+	})
+	for i, e := range endpoints {
+		for _, other := range endpoints[i+1:] {
+			if e.Verb != other.Verb {
+				continue
+			}
+			switch {
+			case e.sameShape(other):
+				verr.Add(e.Def, `route "%s %s" conflicts with route "%s %s" of %s: both routes match the same requests`,
+					e.Verb, e.Path, other.Verb, other.Path, other.Desc)
+			case e.overlaps(other):
+				shadowed, by := e, other
+				if e.moreSpecific(other) {
+					shadowed, by = other, e
+				}
+				verr.Add(shadowed.Def, `route "%s %s" is shadowed by route "%s %s" of %s: requests matching both routes are sent to the latter`,
+					shadowed.Verb, shadowed.Path, by.Verb, by.Path, by.Desc)
+			}
+		}
+	}
+}
+
+// newRouteEndpoint initializes a route endpoint.
+func newRouteEndpoint(verb, path string, def dslengine.Definition, desc string) *routeEndpoint {
+	return &routeEndpoint{
+		Verb:     verb,
+		Path:     path,
+		Def:      def,
+		Desc:     desc,
+		segments: strings.Split(strings.TrimPrefix(path, "/"), "/"),
+	}
+}
+
+// sameShape returns true if both routes have the same literal segments and wildcards of the same
+// kinds at the same positions.
+func (e *routeEndpoint) sameShape(other *routeEndpoint) bool {
+	if len(e.segments) != len(other.segments) {
+		return false
+	}
+	for i, s := range e.segments {
+		o := other.segments[i]
+		k := segmentKind(s)
+		if k != segmentKind(o) || k == literalSegment && s != o {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns true if some request paths match both routes.
+func (e *routeEndpoint) overlaps(other *routeEndpoint) bool {
+	for i, s := range e.segments {
+		if i >= len(other.segments) {
+			return false
+		}
+		o := other.segments[i]
+		k, ok := segmentKind(s), segmentKind(o)
+		if k == catchAllSegment || ok == catchAllSegment {
+			return true
+		}
+		if k == literalSegment && ok == literalSegment && s != o {
+			return false
+		}
+	}
+	return len(e.segments) == len(other.segments)
+}
+
+// moreSpecific returns true if the router prefers e over other for the requests matching both.
+func (e *routeEndpoint) moreSpecific(other *routeEndpoint) bool {
+	for i, s := range e.segments {
+		if i >= len(other.segments) {
+			return false
+		}
+		k, ok := segmentKind(s), segmentKind(other.segments[i])
+		if k != ok {
+			return k < ok
+		}
+	}
+	return false
+}
+
+// segmentKind returns the kind of the given route path segment.
+func segmentKind(s string) int {
+	switch {
+	case strings.HasPrefix(s, ":"):
+		return wildcardSegment
+	case strings.HasPrefix(s, "*"):
+		return catchAllSegment
+	default:
+		return literalSegment
+	}
+}
@@ -0,0 +1,82 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route conflicts validation", func() {
+	var routes func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		routes = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			Action("show", func() { Routing(GET("/:id")) })
+		})
+		Resource("other", routes)
+		dslengine.Run()
+	})
+
+	Context("with distinct routes", func() {
+		BeforeEach(func() {
+			routes = func() {
+				Action("list", func() { Routing(GET("/bottles")) })
+				Action("delete", func() { Routing(DELETE("/bottles/:id")) })
+				Action("drink", func() { Routing(POST("/bottles/:id/drink")) })
+			}
+		})
+
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a route matching the same requests", func() {
+		BeforeEach(func() {
+			routes = func() {
+				Action("get", func() { Routing(GET("/bottles/:id")) })
+			}
+		})
+
+		It("reports the conflict", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`route "GET /bottles/:id" conflicts with route "GET /bottles/:id" of resource "other" action "get"`))
+		})
+	})
+
+	Context("with a literal route overlapping a wildcard route", func() {
+		BeforeEach(func() {
+			routes = func() {
+				Action("latest", func() { Routing(GET("/bottles/latest")) })
+			}
+		})
+
+		It("reports the shadowed route", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`route "GET /bottles/:id" is shadowed by route "GET /bottles/latest" of resource "other" action "latest"`))
+		})
+	})
+
+	Context("with a catch-all route", func() {
+		BeforeEach(func() {
+			routes = func() {
+				Action("any", func() { Routing(GET("/bottles/*path")) })
+			}
+		})
+
+		It("reports the shadowed route", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`route "GET /bottles/*path" is shadowed by route "GET /bottles/:id" of resource "bottle" action "show"`))
+		})
+	})
+})
@@ -0,0 +1,145 @@
+package design
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ScalarDefinition describes a custom primitive type such as a decimal, a duration or a country
+// code. Values are sent on the wire using the embedded primitive type (String, Integer, Number or
+// Boolean) and the generated code converts them to and from the user Go type with the parse and
+// format functions given in the design. Since the kind of a scalar is the kind of its wire
+// primitive, scalars are accepted wherever a DataType is.
+type ScalarDefinition struct {
+	// Primitive is the wire representation of the scalar.
+	Primitive
+	// TypeName is the name of the scalar in the design.
+	TypeName string
+	// Description of the scalar
+	Description string
+	// GoType is the qualified name of the Go type, e.g. "decimal.Decimal".
+	GoType string
+	// PackagePath is the import path of the package defining the Go type and functions.
+	PackagePath string
+	// ParseFunc is the qualified name of the function that converts a wire value into a Go
+	// value: func(wire) (GoType, error).
+	ParseFunc string
+	// FormatFunc is the qualified name of the function that converts a Go value into a wire
+	// value: func(GoType) wire.
+	FormatFunc string
+	// ValidateFunc is the optional qualified name of the function that validates Go values:
+	// func(GoType) error.
+	ValidateFunc string
+	// SchemaFormat is the optional JSON schema format of the wire values, e.g. "date".
+	SchemaFormat string
+	// Encoding is the wire representation of the built-in Time, Date and Duration scalars.
+	Encoding TimeEncoding
+	// MarshalWire is true if the generated types must encode and decode the values with the
+	// format and parse functions because the JSON encoding of the Go type differs from the wire
+	// representation.
+	MarshalWire bool
+	// Values lists the values of enum types, see the Enum DSL. The generated code defines the
+	// Go type and one constant per value.
+	Values []interface{}
+	// DSLFunc contains the DSL used to create this definition if any.
+	DSLFunc func()
+	// example produces example wire values, random primitive values are used if nil.
+	example func(r *RandomGenerator) interface{}
+}
+
+// Name returns the scalar name.
+func (s *ScalarDefinition) Name() string { return s.TypeName }
+
+// DSL returns the DSL function.
+func (s *ScalarDefinition) DSL() func() {
+	return s.DSLFunc
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *ScalarDefinition) Context() string {
+	if s.TypeName != "" {
+		return fmt.Sprintf("scalar %#v", s.TypeName)
+	}
+	return "unnamed scalar"
+}
+
+// IsReference returns true if the Go type is a pointer, e.g. "*big.Int". The fields of reference
+// scalars are never pointers to pointers: nil denotes absent values.
+func (s *ScalarDefinition) IsReference() bool {
+	return strings.HasPrefix(s.GoType, "*")
+}
+
+// IsEnum returns true if the scalar is an enum type defined with Enum.
+func (s *ScalarDefinition) IsEnum() bool {
+	return len(s.Values) > 0
+}
+
+// IsCompatible returns true if val is compatible with the wire primitive and, for enum types, is
+// one of the enum values.
+func (s *ScalarDefinition) IsCompatible(val interface{}) bool {
+	if !s.Primitive.IsCompatible(val) {
+		return false
+	}
+	if !s.IsEnum() {
+		return true
+	}
+	for _, v := range s.Values {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateExample returns a random wire value.
+func (s *ScalarDefinition) GenerateExample(r *RandomGenerator, seen []string) interface{} {
+	if s.IsEnum() {
+		return s.Values[r.Int()%len(s.Values)]
+	}
+	if s.example != nil {
+		return s.example(r)
+	}
+	return s.Primitive.GenerateExample(r, seen)
+}
+
+// Validate makes sure the scalar wire type is supported and that the Go type and conversion
+// functions are set.
+func (s *ScalarDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if s.TypeName == "" {
+		verr.Add(s, "scalar name cannot be empty")
+	}
+	switch s.Primitive {
+	case String, Integer, Number, Boolean:
+	default:
+		verr.Add(s, "scalar wire type must be String, Integer, Number or Boolean")
+	}
+	if s.GoType == "" {
+		verr.Add(s, "missing Go type, use GoType to set it")
+	}
+	if s.ParseFunc == "" || s.FormatFunc == "" {
+		verr.Add(s, "missing parse or format function, use ParseWith and FormatWith to set them")
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// validateScalar makes sure the attribute does not define the built-in validations which do not
+// apply to the Go type of custom scalars.
+func (a *AttributeDefinition) validateScalar(ctx string, parent dslengine.Definition, verr *dslengine.ValidationErrors) {
+	s, ok := a.Type.(*ScalarDefinition)
+	if !ok || a.Validation == nil {
+		return
+	}
+	v := a.Validation
+	if v.Values != nil || v.Format != "" || v.Pattern != "" || v.Minimum != nil || v.Maximum != nil ||
+		v.MinLength != nil || v.MaxLength != nil {
+		verr.Add(parent, "%scustom scalar %s does not support validations, use ValidateWith in the scalar definition instead", ctx, s.TypeName)
+	}
+}
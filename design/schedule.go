@@ -0,0 +1,64 @@
+package design
+
+import (
+	"github.com/goadesign/goa/cron"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ScheduleDefinition describes the schedule of an action called periodically by the service in
+// addition to the HTTP requests.
+type ScheduleDefinition struct {
+	// Spec is the cron expression of the schedule, e.g. "*/5 * * * *".
+	Spec string
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *ScheduleDefinition) Context() string {
+	if s.Parent != nil {
+		return "schedule of " + s.Parent.Context()
+	}
+	return "schedule"
+}
+
+// Validate makes sure the cron expression is valid and that the action can be called without
+// input: it must have a route with no path parameter, no payload and no required parameter. See
+// validateDispatch for the restrictions on headers and security.
+func (s *ScheduleDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if _, err := cron.Parse(s.Spec); err != nil {
+		verr.Add(s, "%s", err)
+	}
+	a := s.Parent
+	if a == nil {
+		return verr
+	}
+	if len(a.Routes) == 0 {
+		verr.Add(s, "scheduled actions must have a route")
+	} else if wcs := a.Routes[0].Params(); len(wcs) > 0 {
+		verr.Add(s, "scheduled actions cannot have path parameters, got %v", wcs)
+	}
+	if a.Payload != nil {
+		verr.Add(s, "scheduled actions cannot have a payload")
+	}
+	if ps := a.AllParams(); ps != nil && len(ps.AllRequired()) > 0 {
+		verr.Add(s, "scheduled actions cannot have required parameters, got %v", ps.AllRequired())
+	}
+	a.validateDispatch(s, "scheduled", verr)
+	return verr
+}
+
+// UsesSchedule returns true if at least one of the API actions is scheduled.
+func (a *APIDefinition) UsesSchedule() bool {
+	found := false
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(act *ActionDefinition) error {
+			if act.Schedule != nil {
+				found = true
+			}
+			return nil
+		})
+	})
+	return found
+}
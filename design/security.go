@@ -20,8 +20,19 @@ const (
 	JWTSecurityKind
 	// NoSecurityKind means to have no security for this endpoint.
 	NoSecurityKind
+	// SessionSecurityKind means an "apiKey" security type where the key is a session ID read
+	// from a cookie.
+	SessionSecurityKind
 )
 
+// DefaultSessionCookie is the name of the cookie holding the session ID when the session security
+// scheme does not specify one.
+const DefaultSessionCookie = "session"
+
+// APIKeysMetadata is the metadata key set by ManageAPIKeys on the resource that administers the
+// keys of an APIKeySecurity scheme, its value is the name of the scheme.
+const APIKeysMetadata = "apikeys:manage"
+
 // SecurityDefinition defines security requirements for an Action
 type SecurityDefinition struct {
 	// Scheme defines the Security Scheme used for this action.
@@ -29,6 +40,9 @@ type SecurityDefinition struct {
 
 	// Scopes are scopes required for this action
 	Scopes []string `json:"scopes,omitempty"`
+
+	// Roles are roles required for this action
+	Roles []string `json:"roles,omitempty"`
 }
 
 // Context returns the generic definition name used in error messages.
@@ -67,6 +81,10 @@ type SecuritySchemeDefinition struct {
 	TokenURL string `json:"token_url,omitempty"`
 	// AuthorizationURL holds URL for retrieving authorization codes with oauth2
 	AuthorizationURL string `json:"authorization_url,omitempty"`
+	// CookieName is the name of the cookie holding the session ID of session schemes.
+	CookieName string `json:"cookie_name,omitempty"`
+	// SessionData is the type of the data stored in the sessions of session schemes.
+	SessionData *UserTypeDefinition `json:"-"`
 }
 
 // DSL returns the DSL function
@@ -86,10 +104,29 @@ func (s *SecuritySchemeDefinition) Context() string {
 		dslFunc = "APIKeySecurity"
 	case JWTSecurityKind:
 		dslFunc = "JWTSecurity"
+	case SessionSecurityKind:
+		dslFunc = "SessionSecurity"
 	}
 	return dslFunc
 }
 
+// CredentialHeader returns the name of the request header that carries the credentials of the
+// scheme, the empty string if the credentials are carried by a query string parameter.
+func (s *SecuritySchemeDefinition) CredentialHeader() string {
+	switch s.Kind {
+	case APIKeySecurityKind, JWTSecurityKind:
+		if s.In == "query" {
+			return ""
+		}
+		if s.Name != "" {
+			return s.Name
+		}
+	case SessionSecurityKind:
+		return "Cookie"
+	}
+	return "Authorization"
+}
+
 // Validate ensures that TokenURL and AuthorizationURL are valid URLs.
 func (s *SecuritySchemeDefinition) Validate() error {
 	_, err := url.Parse(s.TokenURL)
@@ -127,3 +164,37 @@ func (s *SecuritySchemeDefinition) Finalize() {
 		s.AuthorizationURL = au.String()
 	}
 }
+
+// SessionsMetadata is the metadata key set by SessionEndpoints on the resource that logs users in
+// and out of a SessionSecurity scheme, its value is the name of the scheme.
+const SessionsMetadata = "session:endpoints"
+
+// ManagedAPIKeys returns the API key security scheme whose keys are administered by the resource
+// or nil if the resource was not defined with ManageAPIKeys.
+func (r *ResourceDefinition) ManagedAPIKeys() *SecuritySchemeDefinition {
+	names, ok := r.Metadata[APIKeysMetadata]
+	if !ok || len(names) == 0 {
+		return nil
+	}
+	for _, scheme := range Design.SecuritySchemes {
+		if scheme.SchemeName == names[0] {
+			return scheme
+		}
+	}
+	return nil
+}
+
+// SessionScheme returns the session security scheme whose sessions are started and ended by the
+// resource or nil if the resource was not defined with SessionEndpoints.
+func (r *ResourceDefinition) SessionScheme() *SecuritySchemeDefinition {
+	names, ok := r.Metadata[SessionsMetadata]
+	if !ok || len(names) == 0 {
+		return nil
+	}
+	for _, scheme := range Design.SecuritySchemes {
+		if scheme.SchemeName == names[0] {
+			return scheme
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package design
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+const (
+	// ProtocolHTTP1 is the protocol of servers that speak HTTP/1.1, it is the default.
+	ProtocolHTTP1 = "http/1.1"
+	// ProtocolHTTP2 is the protocol of servers that speak HTTP/2 over TLS.
+	ProtocolHTTP2 = "h2"
+	// ProtocolH2C is the protocol of servers that speak HTTP/2 over cleartext TCP.
+	ProtocolH2C = "h2c"
+)
+
+// ServerVariableRegex matches the variables of server URL templates, e.g. "{region}".
+var ServerVariableRegex = regexp.MustCompile(`\{([a-zA-Z0-9_]+)\}`)
+
+// ServerDefinition describes a named environment serving the API, e.g. "production" or
+// "staging". The server URL may be a template whose variables are described by the definition.
+type ServerDefinition struct {
+	// Name of the server environment
+	Name string
+	// Description of the server environment
+	Description string
+	// URL template of the server, e.g. "https://{region}.api.example.com"
+	URL string
+	// Variables describes the URL template variables, it is an object whose attributes are
+	// strings with default values.
+	Variables *AttributeDefinition
+	// TLS describes the TLS requirements of the server, it defaults to the API TLS
+	// requirements.
+	TLS *TLSDefinition
+	// Protocol is the HTTP protocol spoken by the server, one of ProtocolHTTP1, ProtocolHTTP2
+	// or ProtocolH2C. It defaults to ProtocolHTTP1.
+	Protocol string
+	// DSLFunc contains the DSL used to create this definition if any.
+	DSLFunc func()
+}
+
+// DSL returns the DSL function
+func (s *ServerDefinition) DSL() func() {
+	return s.DSLFunc
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *ServerDefinition) Context() string {
+	if s.Name != "" {
+		return fmt.Sprintf("server %#v", s.Name)
+	}
+	return "unnamed server"
+}
+
+// URLVariables returns the names of the variables used in the server URL template in order of
+// appearance.
+func (s *ServerDefinition) URLVariables() []string {
+	var names []string
+	for _, m := range ServerVariableRegex.FindAllStringSubmatch(s.URL, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// VariableNames returns the sorted names of the server variables.
+func (s *ServerDefinition) VariableNames() []string {
+	if s.Variables == nil {
+		return nil
+	}
+	var names []string
+	for n := range s.Variables.Type.ToObject() {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsesProtocol returns true if any of the API servers speaks the given protocol.
+func (a *APIDefinition) UsesProtocol(protocol string) bool {
+	for _, s := range a.Servers {
+		if s.Protocol == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// Defaults returns the default values of the server variables indexed by name.
+func (s *ServerDefinition) Defaults() map[string]string {
+	defaults := make(map[string]string)
+	if s.Variables == nil {
+		return defaults
+	}
+	for n, att := range s.Variables.Type.ToObject() {
+		if def, ok := att.DefaultValue.(string); ok {
+			defaults[n] = def
+		}
+	}
+	return defaults
+}
+
+// DefaultURL returns the server URL where the variables are replaced with their default values.
+func (s *ServerDefinition) DefaultURL() string {
+	defaults := s.Defaults()
+	return ServerVariableRegex.ReplaceAllStringFunc(s.URL, func(v string) string {
+		if def, ok := defaults[v[1:len(v)-1]]; ok {
+			return def
+		}
+		return v
+	})
+}
+
+// Validate makes sure the server URL is valid and all its variables are defined as strings with
+// default values.
+func (s *ServerDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if s.Name == "" {
+		verr.Add(s, "server name cannot be empty")
+	}
+	if s.URL == "" {
+		verr.Add(s, "server URL cannot be empty, use URL to set it")
+	}
+	var vars Object
+	if s.Variables != nil {
+		vars = s.Variables.Type.ToObject()
+	}
+	used := make(map[string]bool)
+	for _, n := range s.URLVariables() {
+		used[n] = true
+		if _, ok := vars[n]; !ok {
+			verr.Add(s, "variable %#v used in URL %#v is not defined", n, s.URL)
+		}
+	}
+	for _, n := range s.VariableNames() {
+		att := vars[n]
+		if !used[n] {
+			verr.Add(s, "variable %#v is not used in URL %#v", n, s.URL)
+		}
+		if att.Type.Kind() != StringKind {
+			verr.Add(s, "variable %#v must be a string", n)
+		}
+		if att.DefaultValue == nil {
+			verr.Add(s, "variable %#v must have a default value", n)
+		}
+	}
+	if s.URL != "" {
+		if u, err := url.Parse(s.DefaultURL()); err != nil {
+			verr.Add(s, "invalid URL %#v: %s", s.URL, err)
+		} else if u.Scheme == "" || u.Host == "" {
+			verr.Add(s, "invalid URL %#v, URL must be absolute", s.URL)
+		} else {
+			secure := u.Scheme == "https" || u.Scheme == "wss"
+			if s.TLS != nil && !secure {
+				verr.Add(s, "server defines TLS requirements but URL %#v does not use TLS", s.URL)
+			}
+			if s.Protocol == ProtocolHTTP2 && !secure {
+				verr.Add(s, "protocol %#v requires TLS but URL %#v does not use it, use %#v instead", s.Protocol, s.URL, ProtocolH2C)
+			}
+			if s.Protocol == ProtocolH2C && secure {
+				verr.Add(s, "protocol %#v cannot be used with TLS URL %#v, use %#v instead", s.Protocol, s.URL, ProtocolHTTP2)
+			}
+		}
+	}
+	switch s.Protocol {
+	case "", ProtocolHTTP1, ProtocolHTTP2, ProtocolH2C:
+	default:
+		verr.Add(s, "unsupported protocol %#v, protocol must be one of %#v, %#v or %#v",
+			s.Protocol, ProtocolHTTP1, ProtocolHTTP2, ProtocolH2C)
+	}
+	if s.TLS != nil {
+		verr.Merge(s.TLS.Validate())
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// Finalize sets the API host and scheme from the default URL of the first server if the API does
+// not define them. It also sets the server TLS requirements to the API ones if the server uses TLS
+// and does not define them.
+func (s *ServerDefinition) Finalize() {
+	if Design == nil {
+		return
+	}
+	if s.TLS == nil && Design.TLS != nil && strings.HasPrefix(strings.ToLower(s.URL), "https:") {
+		s.TLS = Design.TLS
+	}
+	if len(Design.Servers) == 0 || Design.Servers[0] != s {
+		return
+	}
+	u, err := url.Parse(s.DefaultURL())
+	if err != nil {
+		return // validated in Validate
+	}
+	if Design.Host == "" {
+		Design.Host = u.Host
+	}
+	if len(Design.Schemes) == 0 {
+		Design.Schemes = []string{strings.ToLower(u.Scheme)}
+	}
+}
@@ -0,0 +1,307 @@
+package design
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DefaultSnapshotFile is the path of the schema snapshot file relative to the directory where
+// goagen runs unless overridden with the SchemaSnapshot DSL.
+const DefaultSnapshotFile = "goa.snapshot.json"
+
+type (
+	// Snapshot records the attributes of the API user types and media types. The snapshot is
+	// generated with the "snapshot" goagen command and committed along with the design so that
+	// the design validation may detect changes to the Frozen types and, in AdditiveOnly mode,
+	// breaking changes to all types.
+	Snapshot struct {
+		// Types maps the type names to the type snapshots.
+		Types map[string]*TypeSnapshot `json:"types"`
+	}
+
+	// TypeSnapshot records the attributes of a user type or media type.
+	TypeSnapshot struct {
+		// Attributes maps the attribute names to the attribute snapshots. The attributes of
+		// nested inline objects are recorded using dotted names, e.g. "address.city".
+		Attributes map[string]*AttributeSnapshot `json:"attributes"`
+	}
+
+	// AttributeSnapshot records the type of an attribute and whether it is required.
+	AttributeSnapshot struct {
+		// Type is the signature of the attribute type, e.g. "string" or "[]Bottle".
+		Type string `json:"type"`
+		// Required is true if the attribute is required.
+		Required bool `json:"required,omitempty"`
+		// Aliases lists the legacy names of the attribute, see the Alias DSL.
+		Aliases []string `json:"aliases,omitempty"`
+	}
+)
+
+// SnapshotFile returns the path to the schema snapshot file.
+func (a *APIDefinition) SnapshotFile() string {
+	if a.Snapshot != "" {
+		return a.Snapshot
+	}
+	return DefaultSnapshotFile
+}
+
+// NewSnapshot records the attributes of the object user types and media types of the given API.
+func NewSnapshot(api *APIDefinition) *Snapshot {
+	s := &Snapshot{Types: make(map[string]*TypeSnapshot)}
+	api.IterateUserTypes(func(ut *UserTypeDefinition) error {
+		if ts := newTypeSnapshot(ut); ts != nil {
+			s.Types[ut.TypeName] = ts
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
+		if ts := newTypeSnapshot(mt.UserTypeDefinition); ts != nil {
+			s.Types[mt.TypeName] = ts
+		}
+		return nil
+	})
+	return s
+}
+
+// LoadSnapshot reads the snapshot stored in the given file. It returns nil and no error if the
+// file does not exist.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema snapshot %s: %s", path, err)
+	}
+	return &s, nil
+}
+
+// Write stores the snapshot in the given file.
+func (s *Snapshot) Write(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// validateEvolution compares the API types with the snapshot file if there is one. It reports
+// any change to the attributes of the frozen types and, if the API is in additive-only mode, the
+// removal of attributes, the narrowing of attribute types and the attributes that became
+// required.
+func (a *APIDefinition) validateEvolution(verr *dslengine.ValidationErrors) {
+	frozen := make(map[string]*UserTypeDefinition)
+	a.IterateUserTypes(func(ut *UserTypeDefinition) error {
+		if ut.Frozen {
+			frozen[ut.TypeName] = ut
+		}
+		return nil
+	})
+	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
+		if mt.Frozen {
+			frozen[mt.TypeName] = mt.UserTypeDefinition
+		}
+		return nil
+	})
+	if len(frozen) == 0 && !a.AdditiveOnly {
+		return
+	}
+	old, err := LoadSnapshot(a.SnapshotFile())
+	if err != nil {
+		verr.Add(a, "%s", err)
+		return
+	}
+	if old == nil {
+		return
+	}
+	current := NewSnapshot(a)
+	names := make([]string, 0, len(current.Types))
+	for n := range current.Types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		prev, ok := old.Types[n]
+		if !ok {
+			continue
+		}
+		var def dslengine.Definition = a
+		if ut, ok := frozen[n]; ok {
+			def = ut
+			for _, msg := range prev.changes(current.Types[n]) {
+				verr.Add(def, "frozen type %s %s", n, msg)
+			}
+			continue
+		}
+		if a.AdditiveOnly {
+			for _, msg := range prev.breakingChanges(current.Types[n]) {
+				verr.Add(def, "type %s %s, the API is additive-only", n, msg)
+			}
+		}
+	}
+	if a.AdditiveOnly {
+		for n := range old.Types {
+			if _, ok := current.Types[n]; !ok {
+				verr.Add(a, "type %s was removed, the API is additive-only", n)
+			}
+		}
+	}
+}
+
+// changes lists all the differences between the attributes of the two snapshots.
+func (ts *TypeSnapshot) changes(other *TypeSnapshot) []string {
+	var msgs []string
+	for _, n := range sortedAttributes(ts) {
+		if _, ok := other.Attributes[n]; !ok {
+			msgs = append(msgs, fmt.Sprintf("removes attribute %q", n))
+		}
+	}
+	for _, n := range sortedAttributes(other) {
+		prev, ok := ts.Attributes[n]
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("adds attribute %q", n))
+			continue
+		}
+		cur := other.Attributes[n]
+		if prev.Type != cur.Type {
+			msgs = append(msgs, fmt.Sprintf("changes the type of attribute %q from %s to %s", n, prev.Type, cur.Type))
+		}
+		if prev.Required != cur.Required {
+			msgs = append(msgs, fmt.Sprintf("changes whether attribute %q is required", n))
+		}
+	}
+	return msgs
+}
+
+// breakingChanges lists the attribute removals, type narrowings and new required attributes
+// between the two snapshots. Renaming an attribute is not a breaking change if the new attribute
+// lists the old name as alias.
+func (ts *TypeSnapshot) breakingChanges(other *TypeSnapshot) []string {
+	var msgs []string
+	for _, n := range sortedAttributes(ts) {
+		prev := ts.Attributes[n]
+		cur, ok := other.Attributes[n]
+		if !ok {
+			cur, ok = other.aliased(n)
+		}
+		if !ok {
+			msgs = append(msgs, fmt.Sprintf("removes attribute %q", n))
+			continue
+		}
+		if !widens(prev.Type, cur.Type) {
+			msgs = append(msgs, fmt.Sprintf("narrows the type of attribute %q from %s to %s", n, prev.Type, cur.Type))
+		}
+		if !prev.Required && cur.Required {
+			msgs = append(msgs, fmt.Sprintf("makes attribute %q required", n))
+		}
+	}
+	for _, n := range sortedAttributes(other) {
+		if _, ok := ts.Attributes[n]; ok || !other.Attributes[n].Required || ts.renamed(other.Attributes[n]) {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("adds required attribute %q", n))
+	}
+	return msgs
+}
+
+// aliased returns the snapshot of the attribute whose aliases include name.
+func (ts *TypeSnapshot) aliased(name string) (*AttributeSnapshot, bool) {
+	for _, n := range sortedAttributes(ts) {
+		for _, alias := range ts.Attributes[n].Aliases {
+			if alias == name {
+				return ts.Attributes[n], true
+			}
+		}
+	}
+	return nil, false
+}
+
+// renamed returns true if one of the aliases of att is the name of an attribute of ts.
+func (ts *TypeSnapshot) renamed(att *AttributeSnapshot) bool {
+	for _, alias := range att.Aliases {
+		if _, ok := ts.Attributes[alias]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// widens returns true if values of type from are also values of type to.
+func widens(from, to string) bool {
+	return from == to || to == "any" || (from == "integer" && to == "number")
+}
+
+// newTypeSnapshot records the attributes of the given type, nil if the type is not an object.
+func newTypeSnapshot(ut *UserTypeDefinition) *TypeSnapshot {
+	if ut == nil || ut.AttributeDefinition == nil || ut.Type == nil || !ut.Type.IsObject() {
+		return nil
+	}
+	ts := &TypeSnapshot{Attributes: make(map[string]*AttributeSnapshot)}
+	recordAttributes(ts, ut.AttributeDefinition, "")
+	return ts
+}
+
+// recordAttributes records the attributes of the given object attribute prefixing their names.
+func recordAttributes(ts *TypeSnapshot, att *AttributeDefinition, prefix string) {
+	for n, child := range att.Type.ToObject() {
+		name := prefix + n
+		ts.Attributes[name] = &AttributeSnapshot{
+			Type:     typeSignature(child.Type),
+			Required: att.IsRequired(n),
+		}
+		for _, alias := range child.Aliases() {
+			ts.Attributes[name].Aliases = append(ts.Attributes[name].Aliases, prefix+alias)
+		}
+		if o, ok := child.Type.(Object); ok {
+			recordAttributes(ts, &AttributeDefinition{Type: o, Validation: child.Validation}, name+".")
+		}
+	}
+}
+
+// typeSignature returns a string describing the given type.
+func typeSignature(t DataType) string {
+	switch actual := t.(type) {
+	case *ScalarDefinition:
+		return actual.TypeName
+	case *FlagsDefinition:
+		return actual.TypeName
+	case Primitive:
+		switch actual.Kind() {
+		case DateTimeKind:
+			return "datetime"
+		case UUIDKind:
+			return "uuid"
+		}
+		return actual.Name()
+	case *Array:
+		return "[]" + typeSignature(actual.ElemType.Type)
+	case *Hash:
+		return "map[" + typeSignature(actual.KeyType.Type) + "]" + typeSignature(actual.ElemType.Type)
+	case *UserTypeDefinition:
+		return actual.TypeName
+	case *MediaTypeDefinition:
+		return actual.TypeName
+	default:
+		return t.Name()
+	}
+}
+
+// sortedAttributes returns the names of the attributes of the given snapshot sorted
+// alphabetically.
+func sortedAttributes(ts *TypeSnapshot) []string {
+	names := make([]string, 0, len(ts.Attributes))
+	for n := range ts.Attributes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// apiState is the saved state of the API DSL root.
+type apiState struct {
+	api       APIDefinition
+	projected map[string]*MediaTypeDefinition
+}
+
+// SaveState returns a copy of the API definition, see dslengine.StatefulRoot.
+func (a *APIDefinition) SaveState() interface{} {
+	return &apiState{api: *a, projected: copyMediaTypes(ProjectedMediaTypes)}
+}
+
+// RestoreState restores the API definition from a state returned by SaveState.
+func (a *APIDefinition) RestoreState(state interface{}) {
+	s := state.(*apiState)
+	*a = s.api
+	ProjectedMediaTypes.Reset()
+	for k, mt := range s.projected {
+		ProjectedMediaTypes[k] = mt
+	}
+}
+
+// SaveState returns a copy of the generated media types, see dslengine.StatefulRoot.
+func (r MediaTypeRoot) SaveState() interface{} {
+	return copyMediaTypes(r)
+}
+
+// RestoreState restores the generated media types from a state returned by SaveState.
+func (r MediaTypeRoot) RestoreState(state interface{}) {
+	r.Reset()
+	for k, mt := range state.(map[string]*MediaTypeDefinition) {
+		r[k] = mt
+	}
+}
+
+// DesignIn returns the API definition built by the last evaluation run with the given context, nil
+// if the context was never used. The definition must not be modified while another evaluation
+// runs as definitions may refer to the global Design, see dslengine.Context.
+func DesignIn(ctx *dslengine.Context) *APIDefinition {
+	s, ok := ctx.State(Design.DSLName()).(*apiState)
+	if !ok {
+		return nil
+	}
+	api := s.api
+	return &api
+}
+
+// copyMediaTypes returns a shallow copy of the given media types map.
+func copyMediaTypes(m map[string]*MediaTypeDefinition) map[string]*MediaTypeDefinition {
+	c := make(map[string]*MediaTypeDefinition, len(m))
+	for k, mt := range m {
+		c[k] = mt
+	}
+	return c
+}
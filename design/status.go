@@ -0,0 +1,123 @@
+package design
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ResponseCatalog lists the status codes of the standard responses available to all APIs indexed
+// by response name. Use RegisterResponse to extend the catalog.
+var ResponseCatalog = map[string]int{
+	Continue:                     100,
+	SwitchingProtocols:           101,
+	OK:                           200,
+	Created:                      201,
+	Accepted:                     202,
+	NonAuthoritativeInfo:         203,
+	NoContent:                    204,
+	ResetContent:                 205,
+	PartialContent:               206,
+	MultiStatus:                  207,
+	MultipleChoices:              300,
+	MovedPermanently:             301,
+	Found:                        302,
+	SeeOther:                     303,
+	NotModified:                  304,
+	UseProxy:                     305,
+	TemporaryRedirect:            307,
+	PermanentRedirect:            308,
+	BadRequest:                   400,
+	Unauthorized:                 401,
+	PaymentRequired:              402,
+	Forbidden:                    403,
+	NotFound:                     404,
+	MethodNotAllowed:             405,
+	NotAcceptable:                406,
+	ProxyAuthRequired:            407,
+	RequestTimeout:               408,
+	Conflict:                     409,
+	Gone:                         410,
+	LengthRequired:               411,
+	PreconditionFailed:           412,
+	RequestEntityTooLarge:        413,
+	RequestURITooLong:            414,
+	UnsupportedMediaType:         415,
+	RequestedRangeNotSatisfiable: 416,
+	ExpectationFailed:            417,
+	Teapot:                       418,
+	UnprocessableEntity:          422,
+	Locked:                       423,
+	PreconditionRequired:         428,
+	TooManyRequests:              429,
+	RequestHeaderFieldsTooLarge:  431,
+	UnavailableForLegalReasons:   451,
+	InternalServerError:          500,
+	NotImplemented:               501,
+	BadGateway:                   502,
+	ServiceUnavailable:           503,
+	GatewayTimeout:               504,
+	HTTPVersionNotSupported:      505,
+	InsufficientStorage:          507,
+}
+
+// RegisterResponse adds a standard response with the given name and status code to the catalog.
+// The response can then be used by name in the Response DSL of any API the same way as the
+// built-in responses. RegisterResponse is meant to be called by plugins prior to the DSL
+// executing, typically from an init function. An empty description defaults to the standard
+// status text.
+func RegisterResponse(name string, status int, description string) {
+	if description == "" {
+		description = http.StatusText(status)
+	}
+	ResponseCatalog[name] = status
+	if Design != nil {
+		Design.DefaultResponses[name] = &ResponseDefinition{
+			Name:        name,
+			Description: description,
+			Status:      status,
+		}
+	}
+}
+
+// ValidStatus returns true if status is a valid HTTP status code.
+func ValidStatus(status int) bool {
+	return status >= 100 && status <= 599
+}
+
+// BodyAllowed returns false if the HTTP specification prevents responses with the given status code
+// from having a body (1xx, 204, 205 and 304 responses).
+func BodyAllowed(status int) bool {
+	switch {
+	case status < 200:
+		return false
+	case status == 204, status == 205, status == 304:
+		return false
+	}
+	return true
+}
+
+// validateStatus records errors in verr if the response status code is not a valid HTTP status
+// code or if the response defines a body when the status code or the parent action method do not
+// allow for one.
+func (r *ResponseDefinition) validateStatus(verr *dslengine.ValidationErrors) {
+	if !ValidStatus(r.Status) {
+		verr.Add(r, "invalid HTTP status code %d", r.Status)
+		return
+	}
+	if r.MediaType == "" && r.Type == nil {
+		return
+	}
+	if !BodyAllowed(r.Status) {
+		verr.Add(r, "responses with status code %d cannot have a body, remove the media type", r.Status)
+		return
+	}
+	if a, ok := r.Parent.(*ActionDefinition); ok && len(a.Routes) > 0 {
+		for _, route := range a.Routes {
+			if route.Verb != "HEAD" {
+				return
+			}
+		}
+		verr.Add(r, "responses to HEAD requests cannot have a body, remove the media type")
+	}
+}
@@ -0,0 +1,101 @@
+package design_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Response status", func() {
+	var verb func(string) *RouteDefinition
+	var responseDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = GET
+		responseDSL = func() {
+			Response(OK, "application/json")
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(verb("/:id"))
+				responseDSL()
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with a valid response", func() {
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with an invalid status code", func() {
+		BeforeEach(func() {
+			responseDSL = func() {
+				Response("Weird", func() {
+					Status(99)
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("invalid HTTP status code 99"))
+		})
+	})
+
+	Context("with a body on a status that does not allow one", func() {
+		BeforeEach(func() {
+			responseDSL = func() {
+				Response(NoContent, func() {
+					Media("application/json")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("responses with status code 204 cannot have a body"))
+		})
+	})
+
+	Context("with a body on a response to a HEAD request", func() {
+		BeforeEach(func() {
+			verb = HEAD
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("responses to HEAD requests cannot have a body"))
+		})
+	})
+
+	Context("using a response registered in the catalog", func() {
+		BeforeEach(func() {
+			RegisterResponse("Throttled", 429, "")
+			responseDSL = func() {
+				Response("Throttled")
+			}
+		})
+
+		AfterEach(func() {
+			delete(ResponseCatalog, "Throttled")
+		})
+
+		It("uses the catalog status and description", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			r := Design.Resources["bottle"].Actions["show"].Responses["Throttled"]
+			Ω(r).ShouldNot(BeNil())
+			Ω(r.Status).Should(Equal(429))
+			Ω(r.Description).Should(Equal("Too Many Requests"))
+			Ω(r.Standard).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package design
+
+// StrictMetadata is the metadata key set by DisallowUnknownFields on the API, resources, actions
+// and payload types whose request bodies may not contain attributes that the design does not
+// declare.
+const StrictMetadata = "payload:strict"
+
+// DisallowUnknownFields returns true if the generated code rejects the request bodies of the action
+// that contain undeclared attributes. The setting applies if it is defined on the action payload,
+// the action, its resource or the API.
+func (a *ActionDefinition) DisallowUnknownFields() bool {
+	if a.Payload != nil {
+		if _, ok := a.Payload.Metadata[StrictMetadata]; ok {
+			return true
+		}
+	}
+	if _, ok := a.Metadata[StrictMetadata]; ok {
+		return true
+	}
+	if a.Parent != nil {
+		if _, ok := a.Parent.Metadata[StrictMetadata]; ok {
+			return true
+		}
+	}
+	if Design != nil {
+		if _, ok := Design.Metadata[StrictMetadata]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package design
+
+import (
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// TenantDefinition describes how the API requests identify the tenant that owns the resources
+// they access. The tenant identifier is read either from a request header or from a path
+// parameter. Path based tenancy prefixes the resource routes with the tenant parameter wildcard
+// unless the API base path already contains it.
+type TenantDefinition struct {
+	// Description of the tenancy
+	Description string
+	// Header is the name of the header that carries the tenant identifier if any.
+	Header string
+	// Param is the name of the path parameter that carries the tenant identifier if any.
+	Param string
+	// Attribute describes the tenant identifier and its validations.
+	Attribute *AttributeDefinition
+	// DSLFunc contains the DSL used to create this definition if any.
+	DSLFunc func()
+}
+
+// DSL returns the DSL function
+func (t *TenantDefinition) DSL() func() {
+	return t.DSLFunc
+}
+
+// Context returns the generic definition name used in error messages.
+func (t *TenantDefinition) Context() string {
+	return "tenant"
+}
+
+// Name returns the name of the header or path parameter that carries the tenant identifier.
+func (t *TenantDefinition) Name() string {
+	if t.Header != "" {
+		return t.Header
+	}
+	return t.Param
+}
+
+// Prefixed returns true if the tenant path parameter wildcard must be added to the API base path.
+func (t *TenantDefinition) Prefixed() bool {
+	if t.Param == "" || Design == nil {
+		return false
+	}
+	for _, wc := range ExtractWildcards(Design.BasePath) {
+		if wc == t.Param {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate makes sure the tenant identifier is read from exactly one header or path parameter
+// and that it is a string.
+func (t *TenantDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if t.Header == "" && t.Param == "" {
+		verr.Add(t, "tenant must be read from a header or a path parameter, use Header or Param")
+	}
+	if t.Header != "" && t.Param != "" {
+		verr.Add(t, "tenant cannot be read from both a header and a path parameter")
+	}
+	if t.Attribute != nil && t.Attribute.Type.Kind() != StringKind {
+		verr.Add(t, "tenant identifier must be a string")
+	}
+	if t.Prefixed() && Design != nil {
+		Design.IterateResources(func(r *ResourceDefinition) error {
+			for _, wc := range ExtractWildcards(r.FullPath()) {
+				if wc == t.Param {
+					verr.Add(r, "resource path %#v cannot use the tenant wildcard %#v, set it in the API base path instead",
+						r.FullPath(), wc)
+				}
+			}
+			return nil
+		})
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// Finalize prefixes the API base path with the tenant wildcard for path based tenancy and adds
+// the tenant identifier to the API parameters or to the headers of all the resources so that
+// the generated code extracts and validates it.
+func (t *TenantDefinition) Finalize() {
+	if Design == nil || t.Attribute == nil {
+		return
+	}
+	tenant := func() *AttributeDefinition {
+		att := &AttributeDefinition{Type: Object{t.Name(): DupAtt(t.Attribute)}}
+		att.Validation = &dslengine.ValidationDefinition{Required: []string{t.Name()}}
+		return att
+	}
+	if t.Param != "" {
+		if t.Prefixed() {
+			Design.BasePath = strings.TrimSuffix(Design.BasePath, "/") + "/:" + t.Param
+		}
+		if Design.Params == nil {
+			Design.Params = tenant()
+		} else {
+			Design.Params.Merge(tenant())
+			if Design.Params.Validation == nil {
+				Design.Params.Validation = &dslengine.ValidationDefinition{}
+			}
+			Design.Params.Validation.AddRequired([]string{t.Param})
+		}
+		return
+	}
+	Design.IterateResources(func(r *ResourceDefinition) error {
+		if r.Headers == nil {
+			r.Headers = tenant()
+			return nil
+		}
+		r.Headers.Merge(tenant())
+		if r.Headers.Validation == nil {
+			r.Headers.Validation = &dslengine.ValidationDefinition{}
+		}
+		r.Headers.Validation.AddRequired([]string{t.Header})
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+package design
+
+import (
+	"time"
+
+	"github.com/goadesign/goa"
+)
+
+// TimeEncoding is the wire representation of the Time, Date and Duration primitives, see the
+// TimeFormat DSL.
+type TimeEncoding string
+
+const (
+	// TimeRFC3339 encodes Time values as RFC3339 strings, this is the default.
+	TimeRFC3339 TimeEncoding = "rfc3339"
+	// TimeUnix encodes Time values as integer numbers of seconds since the unix epoch.
+	TimeUnix TimeEncoding = "unix"
+	// DateISO8601 encodes Date values as ISO8601 calendar dates ("2006-01-02"), this is the
+	// only Date encoding.
+	DateISO8601 TimeEncoding = "date"
+	// DurationISO8601 encodes Duration values as ISO8601 durations ("PT1H30M"), this is the
+	// default.
+	DurationISO8601 TimeEncoding = "iso8601"
+	// DurationSeconds encodes Duration values as (possibly fractional) numbers of seconds.
+	DurationSeconds TimeEncoding = "seconds"
+)
+
+var (
+	// Time is the type for a point in time, the generated fields are time.Time. Values are
+	// RFC3339 strings unless another format is set with TimeFormat.
+	Time = &ScalarDefinition{
+		Primitive:    String,
+		TypeName:     "Time",
+		GoType:       "time.Time",
+		PackagePath:  "time",
+		ParseFunc:    "goa.ParseTime",
+		FormatFunc:   "goa.FormatTime",
+		SchemaFormat: "date-time",
+		Encoding:     TimeRFC3339,
+		example: func(r *RandomGenerator) interface{} {
+			return r.DateTime().UTC().Format(time.RFC3339)
+		},
+	}
+
+	// Date is the type for a calendar date, the generated fields are time.Time. Values are
+	// ISO8601 dates.
+	Date = &ScalarDefinition{
+		Primitive:    String,
+		TypeName:     "Date",
+		GoType:       "time.Time",
+		PackagePath:  "time",
+		ParseFunc:    "goa.ParseDate",
+		FormatFunc:   "goa.FormatDate",
+		SchemaFormat: "date",
+		Encoding:     DateISO8601,
+		MarshalWire:  true,
+		example: func(r *RandomGenerator) interface{} {
+			return r.DateTime().UTC().Format("2006-01-02")
+		},
+	}
+
+	// Duration is the type for an amount of time, the generated fields are time.Duration.
+	// Values are ISO8601 durations unless another format is set with TimeFormat.
+	Duration = &ScalarDefinition{
+		Primitive:    String,
+		TypeName:     "Duration",
+		GoType:       "time.Duration",
+		PackagePath:  "time",
+		ParseFunc:    "goa.ParseISO8601Duration",
+		FormatFunc:   "goa.FormatISO8601Duration",
+		SchemaFormat: "duration",
+		Encoding:     DurationISO8601,
+		MarshalWire:  true,
+		example: func(r *RandomGenerator) interface{} {
+			return goa.FormatISO8601Duration(time.Duration(r.Int()%86400) * time.Second)
+		},
+	}
+
+	// unixTime is the Time scalar encoded with TimeUnix.
+	unixTime = &ScalarDefinition{
+		Primitive:   Integer,
+		TypeName:    "Time",
+		GoType:      "time.Time",
+		PackagePath: "time",
+		ParseFunc:   "goa.ParseUnixTime",
+		FormatFunc:  "goa.FormatUnixTime",
+		Encoding:    TimeUnix,
+		MarshalWire: true,
+		example: func(r *RandomGenerator) interface{} {
+			return int(r.DateTime().Unix())
+		},
+	}
+
+	// durationSeconds is the Duration scalar encoded with DurationSeconds.
+	durationSeconds = &ScalarDefinition{
+		Primitive:   Number,
+		TypeName:    "Duration",
+		GoType:      "time.Duration",
+		PackagePath: "time",
+		ParseFunc:   "goa.ParseDurationSeconds",
+		FormatFunc:  "goa.FormatDurationSeconds",
+		Encoding:    DurationSeconds,
+		MarshalWire: true,
+		example: func(r *RandomGenerator) interface{} {
+			return float64(r.Int() % 86400)
+		},
+	}
+)
+
+// TimeScalar returns the variant of the Time, Date or Duration scalar t that uses the given wire
+// encoding, nil if t is not one of these scalars or does not support the encoding.
+func TimeScalar(t DataType, enc TimeEncoding) *ScalarDefinition {
+	s, ok := t.(*ScalarDefinition)
+	if !ok {
+		return nil
+	}
+	for _, v := range []*ScalarDefinition{Time, unixTime, Date, Duration, durationSeconds} {
+		if v.TypeName == s.TypeName && v.GoType == s.GoType && v.Encoding == enc {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+// TLSVersions lists the TLS protocol versions that may be required by the API servers.
+var TLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+// DefaultTLSMinVersion is the minimum TLS version used when the design does not specify one.
+const DefaultTLSMinVersion = "1.2"
+
+// TLSDefinition describes the TLS requirements of the API or of one of its servers.
+type TLSDefinition struct {
+	// MinVersion is the minimum TLS protocol version accepted by the servers, e.g. "1.2".
+	MinVersion string
+	// ClientCertRequired is true if the servers require clients to authenticate with a
+	// certificate (mutual TLS).
+	ClientCertRequired bool
+	// Parent is the API or server definition that owns the TLS requirements.
+	Parent dslengine.Definition
+}
+
+// Context returns the generic definition name used in error messages.
+func (t *TLSDefinition) Context() string {
+	if t.Parent != nil {
+		return "TLS of " + t.Parent.Context()
+	}
+	return "TLS"
+}
+
+// Version returns the minimum TLS version or DefaultTLSMinVersion if it isn't set.
+func (t *TLSDefinition) Version() string {
+	if t.MinVersion == "" {
+		return DefaultTLSMinVersion
+	}
+	return t.MinVersion
+}
+
+// Validate makes sure the minimum TLS version is supported.
+func (t *TLSDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if t.MinVersion != "" {
+		supported := false
+		for _, v := range TLSVersions {
+			if v == t.MinVersion {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			verr.Add(t, "unsupported TLS version %#v, version must be one of %v", t.MinVersion, TLSVersions)
+		}
+	}
+	return verr.AsError()
+}
+
+// UsesTLS returns true if the API or any of its servers defines TLS requirements.
+func (a *APIDefinition) UsesTLS() bool {
+	if a.TLS != nil {
+		return true
+	}
+	for _, s := range a.Servers {
+		if s.TLS != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package design
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// TrailerNames returns the sorted canonical names of the response trailers, nil if the response
+// does not define trailers.
+func (r *ResponseDefinition) TrailerNames() []string {
+	if r.Trailers == nil {
+		return nil
+	}
+	obj := r.Trailers.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, http.CanonicalHeaderKey(n))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateTrailers records errors in verr if the response trailers cannot be sent: trailers must
+// be primitives and cannot be required since the response body may end before they are written.
+func (r *ResponseDefinition) validateTrailers(verr *dslengine.ValidationErrors) {
+	if r.Trailers == nil {
+		return
+	}
+	verr.Merge(r.Trailers.Validate("response trailers", r))
+	obj := r.Trailers.Type.ToObject()
+	for n, att := range obj {
+		if p, ok := att.Type.(Primitive); !ok || p == Any {
+			verr.Add(r, "trailer %s must be a string, integer, number, boolean, UUID or date time", n)
+		}
+		if r.Headers != nil {
+			for h := range r.Headers.Type.ToObject() {
+				if strings.EqualFold(h, n) {
+					verr.Add(r, "trailer %s is also defined as a header", n)
+				}
+			}
+		}
+	}
+	for _, n := range r.Trailers.AllRequired() {
+		verr.Add(r, "trailer %s cannot be required", n)
+	}
+}
@@ -113,6 +113,13 @@ type (
 		*AttributeDefinition
 		// Name of type
 		TypeName string
+		// Frozen is true if the attributes of the type may not change compared to the
+		// schema snapshot.
+		Frozen bool
+		// Location is where the type is declared in the design sources if known.
+		Location dslengine.Location
+		// Module is the import path of the design module declaring the type if any.
+		Module string
 	}
 
 	// MediaTypeDefinition describes the rendering of a resource using property and link
@@ -135,6 +142,9 @@ type (
 		Views map[string]*ViewDefinition
 		// Resource this media type is the canonical representation for if any
 		Resource *ResourceDefinition
+		// Envelope lists the attributes of the envelope object that wraps the elements of
+		// a collection media type if any, see the Envelope DSL.
+		Envelope *AttributeDefinition
 	}
 )
 
@@ -555,7 +565,7 @@ func UserTypes(dt DataType) map[string]*UserTypeDefinition {
 		}
 	}
 	switch actual := dt.(type) {
-	case Primitive:
+	case Primitive, *ScalarDefinition, *FlagsDefinition:
 		return nil
 	case *Array:
 		return UserTypes(actual.ElemType.Type)
@@ -670,13 +680,14 @@ func (u *UserTypeDefinition) IsCompatible(val interface{}) bool {
 	return u.Type == nil || u.Type.IsCompatible(val)
 }
 
-// Finalize merges base type attributes.
+// Finalize merges base type attributes and coerces the default values.
 func (u *UserTypeDefinition) Finalize() {
 	if u.Reference != nil {
 		if bat := u.AttributeDefinition; bat != nil {
 			u.AttributeDefinition.Inherit(bat)
 		}
 	}
+	u.AttributeDefinition.Finalize()
 
 	u.GenerateExample(Design.RandomGenerator(), nil)
 }
@@ -812,6 +823,15 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 			},
 		},
 	}
+	for _, key := range []string{FieldPolicyMetadata, AbsentPolicyMetadata} {
+		if policy, ok := m.Metadata[key]; ok {
+			if p.Metadata == nil {
+				p.Metadata = make(dslengine.MetadataDefinition)
+			}
+			p.Metadata[key] = policy
+		}
+	}
+	p.Envelope = m.Envelope
 	p.Views = map[string]*ViewDefinition{"default": {
 		Name:                "default",
 		AttributeDefinition: DupAtt(v.AttributeDefinition),
@@ -867,6 +887,13 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 						return nil, nil, fmt.Errorf("view %#v on field %#v cannot be computed: %s", view, n, err)
 					}
 					at.Type = pr
+				} else if elem := envelopeElem(m, n); elem != nil {
+					// Envelope items are rendered using the envelope view
+					pr, _, err := elem.Project(view)
+					if err != nil {
+						return nil, nil, fmt.Errorf("view %#v on envelope items cannot be computed: %s", view, err)
+					}
+					at.Type = &Array{ElemType: &AttributeDefinition{Type: pr}}
 				}
 				projectedObj[n] = at
 			}
@@ -877,7 +904,10 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 
 func (m *MediaTypeDefinition) projectCollection(view string) (*MediaTypeDefinition, *UserTypeDefinition, error) {
 	// Project the collection element media type
-	e := m.ToArray().ElemType.Type.(*MediaTypeDefinition) // validation checked this cast would work
+	e, ok := m.ToArray().ElemType.Type.(*MediaTypeDefinition)
+	if !ok {
+		return m.projectPlainCollection(view)
+	}
 	pe, le, err2 := e.Project(view)
 	if err2 != nil {
 		return nil, nil, fmt.Errorf("collection element: %s", err2)
@@ -923,6 +953,32 @@ func (m *MediaTypeDefinition) projectCollection(view string) (*MediaTypeDefiniti
 	return p, links, nil
 }
 
+// projectPlainCollection projects a collection of user types or primitives, see CollectionOf. The
+// elements are rendered as is so the only view is the default view.
+func (m *MediaTypeDefinition) projectPlainCollection(view string) (*MediaTypeDefinition, *UserTypeDefinition, error) {
+	if view != DefaultView {
+		return nil, nil, fmt.Errorf("unknown view %#v", view)
+	}
+	p := &MediaTypeDefinition{
+		Identifier: m.projectIdentifier(view),
+		UserTypeDefinition: &UserTypeDefinition{
+			AttributeDefinition: &AttributeDefinition{
+				Description: m.Description,
+				Type:        m.Type,
+				Validation:  m.Validation,
+				Example:     m.Example,
+			},
+			TypeName: m.TypeName,
+		},
+	}
+	p.Views = map[string]*ViewDefinition{DefaultView: {
+		AttributeDefinition: DupAtt(m.Views[view].AttributeDefinition),
+		Name:                DefaultView,
+		Parent:              p,
+	}}
+	return p, nil, nil
+}
+
 // projectIdentifier computes the projected media type identifier by adding the "view" param.  We
 // need the projected media type identifier to be different so that looking up projected media types
 // from ProjectedMediaTypes works correctly. It's also good for clients.
@@ -991,7 +1047,7 @@ func walk(at *AttributeDefinition, walker func(*AttributeDefinition) error, seen
 		return walk(ut.AttributeDefinition, walker, seen)
 	}
 	switch actual := at.Type.(type) {
-	case Primitive:
+	case Primitive, *ScalarDefinition, *FlagsDefinition:
 		return nil
 	case *Array:
 		return walk(actual.ElemType, walker, seen)
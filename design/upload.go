@@ -0,0 +1,93 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// UploadIDParam is the name of the path wildcard that identifies the uploads in the paths of the
+// routes that receive the upload chunks.
+const UploadIDParam = "upload_id"
+
+// UploadDefinition describes an action that receives files using the tus resumable upload
+// protocol. The action is invoked once an upload completes.
+type UploadDefinition struct {
+	// MaxSize is the maximum size in bytes of the uploads, 0 means no limit.
+	MaxSize int64
+	// Metadata describes the upload metadata sent by the clients when creating uploads, it is
+	// an object whose attributes are strings.
+	Metadata *AttributeDefinition
+	// Parent is the action definition.
+	Parent *ActionDefinition
+}
+
+// Context returns the generic definition name used in error messages.
+func (u *UploadDefinition) Context() string {
+	if u.Parent != nil {
+		return "resumable upload of " + u.Parent.Context()
+	}
+	return "resumable upload"
+}
+
+// Attribute returns the upload metadata attribute so that the metadata may be defined with
+// Attribute.
+func (u *UploadDefinition) Attribute() *AttributeDefinition {
+	return u.Metadata
+}
+
+// Paths returns the full paths of the routes that receive the upload chunks, one per action
+// route: the route path followed by the upload identifier wildcard.
+func (u *UploadDefinition) Paths() []string {
+	if u.Parent == nil {
+		return nil
+	}
+	paths := make([]string, len(u.Parent.Routes))
+	for i, r := range u.Parent.Routes {
+		paths[i] = r.FullPath() + "/:" + UploadIDParam
+	}
+	return paths
+}
+
+// RequiredMetadata returns the sorted names of the metadata that clients must provide when
+// creating uploads.
+func (u *UploadDefinition) RequiredMetadata() []string {
+	if u.Metadata == nil || u.Metadata.Validation == nil {
+		return nil
+	}
+	required := append([]string(nil), u.Metadata.Validation.Required...)
+	sort.Strings(required)
+	return required
+}
+
+// Validate makes sure the upload is valid: the action must only have POST routes, no payload, a
+// NoContent response and the metadata attributes must be strings.
+func (u *UploadDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if u.MaxSize < 0 {
+		verr.Add(u, "maximum upload size cannot be negative")
+	}
+	if u.Metadata != nil {
+		for n, att := range u.Metadata.Type.ToObject() {
+			if att.Type.Kind() != StringKind {
+				verr.Add(u, "upload metadata %#v must be a string", n)
+			}
+		}
+	}
+	a := u.Parent
+	if a == nil {
+		return verr
+	}
+	for _, r := range a.Routes {
+		if r.Verb != "POST" {
+			verr.Add(u, "resumable upload actions may only have POST routes, got %s", r.Verb)
+		}
+	}
+	if a.Payload != nil {
+		verr.Add(u, "resumable upload actions cannot define a payload, the content is sent in chunks")
+	}
+	if _, ok := a.Responses[NoContent]; !ok {
+		verr.Add(u, "resumable upload actions must define a NoContent response sent once uploads complete")
+	}
+	return verr
+}
@@ -76,6 +76,11 @@ func (a *APIDefinition) Validate() error {
 	}
 
 	verr := new(dslengine.ValidationErrors)
+	// Resource parent cycles must be reported before anything computes the full paths of
+	// the routes which would otherwise recurse infinitely.
+	if a.validateParentCycles(verr); len(verr.Errors) > 0 {
+		return verr
+	}
 	if a.Params != nil {
 		verr.Merge(a.Params.Validate("base parameters", a))
 	}
@@ -84,6 +89,31 @@ func (a *APIDefinition) Validate() error {
 	a.validateLicense(verr)
 	a.validateDocs(verr)
 	a.validateOrigins(verr)
+	a.validateInterceptors(verr)
+	a.validateIdentifierTemplate(verr)
+	for _, e := range a.Errors {
+		verr.Merge(e.Validate())
+	}
+	a.validatePanicResponse(verr)
+	if a.TLS != nil {
+		verr.Merge(a.TLS.Validate())
+	}
+	if a.Compression != nil {
+		verr.Merge(a.Compression.Validate())
+	}
+	if a.Decompression != nil {
+		verr.Merge(a.Decompression.Validate())
+	}
+	if a.Limits != nil {
+		verr.Merge(a.Limits.Validate())
+	}
+	if a.Capture != nil {
+		verr.Merge(a.Capture.Validate())
+	}
+	a.validateMaintenance(verr)
+	if a.JSONRPC != nil {
+		verr.Merge(a.JSONRPC.Validate())
+	}
 
 	var allRoutes []*routeInfo
 	a.IterateResources(func(r *ResourceDefinition) error {
@@ -141,6 +171,7 @@ func (a *APIDefinition) Validate() error {
 			}
 		}
 	}
+	a.validateRouteConflicts(verr)
 	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
 		verr.Merge(mt.Validate())
 		return nil
@@ -153,6 +184,7 @@ func (a *APIDefinition) Validate() error {
 		verr.Merge(r.Validate())
 		return nil
 	})
+	a.validateEvolution(verr)
 	for _, dec := range a.Consumes {
 		verr.Merge(dec.Validate())
 	}
@@ -198,6 +230,58 @@ func (a *APIDefinition) validateOrigins(verr *dslengine.ValidationErrors) {
 	}
 }
 
+// validateMaintenance makes sure the maintenance response has no body or uses the error media
+// type, the generated code cannot produce any other body.
+func (a *APIDefinition) validateMaintenance(verr *dslengine.ValidationErrors) {
+	m := a.Maintenance
+	if m == nil {
+		return
+	}
+	verr.Merge(m.Validate())
+	if m.MediaType != "" && CanonicalIdentifier(m.MediaType) != CanonicalIdentifier(ErrorMediaIdentifier) {
+		verr.Add(m, "maintenance response media type must be the error media type, got %#v", m.MediaType)
+	}
+	if m.Type != nil {
+		verr.Add(m, "maintenance response cannot define a body type")
+	}
+}
+
+// validateInterceptors makes sure the attributes accessed by each interceptor have the same type
+// in all the actions the interceptor applies to.
+func (a *APIDefinition) validateInterceptors(verr *dslengine.ValidationErrors) {
+	types := make(map[*InterceptorDefinition]map[string]string)
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(ac *ActionDefinition) error {
+			for _, i := range ac.AllInterceptors() {
+				if types[i] == nil {
+					types[i] = make(map[string]string)
+				}
+				check := func(kind, n string, att *AttributeDefinition) {
+					if att == nil {
+						return
+					}
+					key := kind + " " + n
+					if t, ok := types[i][key]; ok && t != att.Type.Name() {
+						verr.Add(ac, "%s attribute %#v accessed by interceptor %#v is of type %s but is of type %s in other actions",
+							kind, n, i.Name, att.Type.Name(), t)
+						return
+					}
+					types[i][key] = att.Type.Name()
+				}
+				for _, n := range i.PayloadAttributes() {
+					if ac.Payload != nil && ac.Payload.Type.IsObject() {
+						check("payload", n, ac.Payload.Type.ToObject()[n])
+					}
+				}
+				for _, n := range i.ResultAttributes() {
+					check("result", n, ac.ResultAttribute(n))
+				}
+			}
+			return nil
+		})
+	})
+}
+
 // Validate tests whether the resource definition is consistent: action names are valid and each action is
 // valid.
 func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
@@ -206,18 +290,45 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 		verr.Add(r, "Resource name cannot be empty")
 	}
 	r.validateActions(verr)
+	if r.MediaType != "" && r.DefaultViewName != "" {
+		validateView(verr, r, r.MediaType, r.DefaultViewName, "default media")
+	}
 	if r.ParentName != "" {
 		r.validateParent(verr)
 	}
 	for _, resp := range r.Responses {
 		verr.Merge(resp.Validate())
 	}
+	for _, e := range r.Errors {
+		verr.Merge(e.Validate())
+	}
 	if r.Params != nil {
 		verr.Merge(r.Params.Validate("resource parameters", r))
 	}
 	for _, origin := range r.Origins {
 		verr.Merge(origin.Validate())
 	}
+	if r.Compression != nil {
+		verr.Merge(r.Compression.Validate())
+	}
+	if r.Decompression != nil {
+		verr.Merge(r.Decompression.Validate())
+	}
+	if r.Limits != nil {
+		verr.Merge(r.Limits.Validate())
+	}
+	if r.Capture != nil {
+		verr.Merge(r.Capture.Validate())
+	}
+	if r.Cache != nil {
+		verr.Merge(r.Cache.Validate())
+	}
+	if r.Concurrency != nil {
+		verr.Merge(r.Concurrency.Validate())
+	}
+	if r.Event != nil {
+		verr.Merge(r.Event.Validate())
+	}
 	return verr.AsError()
 }
 
@@ -242,12 +353,29 @@ func (r *ResourceDefinition) validateParent(verr *dslengine.ValidationErrors) {
 	if !ok {
 		verr.Add(r, "Parent resource named %#v not found", r.ParentName)
 	} else {
-		if p.CanonicalAction() == nil {
+		if ca := p.CanonicalAction(); ca == nil {
 			verr.Add(r, "Parent resource %#v has no canonical action", r.ParentName)
+		} else if len(ca.Routes) == 0 {
+			verr.Add(r, "Canonical action %#v of parent resource %#v has no route", ca.Name, r.ParentName)
 		}
 	}
 }
 
+// validateParentCycles makes sure no resource is its own ancestor.
+func (a *APIDefinition) validateParentCycles(verr *dslengine.ValidationErrors) {
+	a.IterateResources(func(r *ResourceDefinition) error {
+		seen := map[string]bool{r.Name: true}
+		for p := a.Resources[r.ParentName]; p != nil; p = a.Resources[p.ParentName] {
+			if seen[p.Name] {
+				verr.Add(r, "Parent resources of %#v form a cycle through %#v", r.Name, p.Name)
+				break
+			}
+			seen[p.Name] = true
+		}
+		return nil
+	})
+}
+
 // Validate makes sure the CORS definition origin is valid.
 func (cors *CORSDefinition) Validate() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -332,9 +460,71 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
 	}
+	if sp := a.StreamingPayload; sp != nil {
+		if a.Payload != nil {
+			verr.Add(a, "action cannot define both a payload and a streaming payload")
+		}
+		if Design.Types[sp.TypeName] != sp {
+			verr.Add(a, "streaming payload element type %#v must be defined with Type", sp.TypeName)
+		}
+	}
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
+	for _, i := range a.AllInterceptors() {
+		for _, n := range i.PayloadAttributes() {
+			if a.Payload == nil || !a.Payload.Type.IsObject() || a.Payload.Type.ToObject()[n] == nil {
+				verr.Add(a, "payload attribute %#v accessed by interceptor %#v is not defined", n, i.Name)
+			}
+		}
+		for _, n := range i.ResultAttributes() {
+			if a.ResultAttribute(n) == nil {
+				verr.Add(a, "result attribute %#v accessed by interceptor %#v is not defined by any successful response media type", n, i.Name)
+			}
+		}
+	}
+	if a.Audit != nil {
+		verr.Merge(a.Audit.Validate())
+	}
+	if a.Cache != nil {
+		verr.Merge(a.Cache.Validate())
+		if !a.HasGETRoute() {
+			verr.Add(a, "only actions with a GET route may define a cache policy")
+		}
+	}
+	if a.Concurrency != nil {
+		verr.Merge(a.Concurrency.Validate())
+	}
+	if a.Flag != nil {
+		verr.Merge(a.Flag.Validate())
+	}
+	if a.Download != nil {
+		verr.Merge(a.Download.Validate())
+	}
+	if a.Upload != nil {
+		verr.Merge(a.Upload.Validate())
+	}
+	if a.PresignedUpload != nil {
+		verr.Merge(a.PresignedUpload.Validate())
+	}
+	if a.Event != nil {
+		verr.Merge(a.Event.Validate())
+		if !a.IsMutating() {
+			verr.Add(a, "only actions with a POST, PUT, PATCH or DELETE route may emit events")
+		}
+	}
+	if a.MQTT != nil {
+		verr.Merge(a.MQTT.Validate())
+		if len(a.Routes) == 0 {
+			verr.Add(a, "only actions with a route may subscribe to an MQTT topic")
+		}
+	}
+	if a.Schedule != nil {
+		verr.Merge(a.Schedule.Validate())
+	}
+	if _, ok := a.Metadata[OutboxMetadata]; ok && !a.IsMutating() {
+		verr.Add(a, "only actions with a POST, PUT, PATCH or DELETE route may use the transactional outbox")
+	}
 
 	return verr.AsError()
 }
@@ -399,7 +589,9 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 			verr.Add(a, "type of parameter %s cannot be nil", n)
 		}
 		if p.Type.Kind() == ObjectKind {
-			verr.Add(a, `parameter %s cannot be an object, only action payloads may be of type object`, n)
+			verr.Add(a, `parameter %s cannot be an object, only action payloads and query parameters using a user type may be of type object`, n)
+		} else if ut, ok := p.Type.(*UserTypeDefinition); ok && ut.IsObject() {
+			a.validateObjectParam(n, ut, wcs, verr)
 		} else if p.Type.Kind() == HashKind {
 			verr.Add(a, `parameter %s cannot be a hash, only action payloads may be of type hash`, n)
 		}
@@ -432,21 +624,11 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	if ctx != "" {
 		ctx += " - "
 	}
-	// If both Default and Enum are given, make sure the Default value is one of Enum values.
-	// TODO: We only do the default value and enum check just for primitive types.
-	// Issue 388 (https://github.com/goadesign/goa/issues/388) will address this for other types.
-	if a.Type.IsPrimitive() && a.DefaultValue != nil && a.Validation != nil && a.Validation.Values != nil {
-		var found bool
-		for _, e := range a.Validation.Values {
-			if e == a.DefaultValue {
-				found = true
-				break
-			}
-		}
-		if !found {
-			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
-		}
-	}
+	a.validateDefault(ctx, parent, verr)
+	a.validateScalar(ctx, parent, verr)
+	a.validateFlags(ctx, parent, verr)
+	a.validateArrayStyle(ctx, parent, verr)
+	a.validateDefaultFunc(ctx, parent, verr)
 	o := a.Type.ToObject()
 	if o != nil {
 		for _, n := range a.AllRequired() {
@@ -459,8 +641,13 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			}
 			if !found {
 				verr.Add(parent, `%srequired field "%s" does not exist`, ctx, n)
+			} else if o[n].DefaultFunc() != "" {
+				verr.Add(parent, `%srequired field "%s" cannot have a default value provider`, ctx, n)
 			}
 		}
+		a.validateAliases(ctx, parent, verr)
+		a.validateComputed(ctx, parent, verr)
+		a.validateDuplicates(ctx, parent, verr)
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
@@ -484,6 +671,13 @@ func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
 	}
 	if r.Status == 0 {
 		verr.Add(r, "response status not defined")
+	} else {
+		r.validateStatus(verr)
+	}
+	r.validateLocation(verr)
+	r.validateTrailers(verr)
+	if r.MediaType != "" && r.ViewName != "" {
+		validateView(verr, r, r.MediaType, r.ViewName, "response media")
 	}
 	return verr.AsError()
 }
@@ -524,10 +718,13 @@ func (m *MediaTypeDefinition) Validate() *dslengine.ValidationErrors {
 			if err := a.ElemType.Validate("array element", m); err != nil {
 				verr.Merge(err)
 			} else {
-				if _, ok := a.ElemType.Type.(*MediaTypeDefinition); !ok {
-					verr.Add(m, "collection media type array element type must be a media type, got %s", a.ElemType.Type.Name())
-				} else {
+				switch a.ElemType.Type.(type) {
+				case *MediaTypeDefinition:
 					obj = a.ElemType.Type.ToObject()
+				case *UserTypeDefinition, Primitive, *ScalarDefinition:
+					// Collection of user types or primitives, see CollectionOf
+				default:
+					verr.Add(m, "collection media type array element type must be a media type, a user type or a primitive, got %s", a.ElemType.Type.Name())
 				}
 			}
 		}
@@ -541,8 +738,7 @@ func (m *MediaTypeDefinition) Validate() *dslengine.ValidationErrors {
 				cmt, ok := att.Type.(*MediaTypeDefinition)
 				if !ok {
 					verr.Add(m, "attribute %s of media type defines a view for rendering but its type is not MediaTypeDefinition", n)
-				}
-				if _, ok := cmt.Views[att.View]; !ok {
+				} else if _, ok := cmt.Views[att.View]; !ok {
 					verr.Add(m, "attribute %s of media type uses unknown view %#v", n, att.View)
 				}
 			}
@@ -554,10 +750,23 @@ func (m *MediaTypeDefinition) Validate() *dslengine.ValidationErrors {
 			hasDefaultView = true
 		}
 		verr.Merge(v.Validate())
+		if !m.IsArray() {
+			v.validateRequired(verr)
+		}
 	}
 	if !hasDefaultView {
 		verr.Add(m, `media type does not define the default view, use View("default", ...) to define it.`)
 	}
+	if a := m.Type.ToArray(); a != nil && a.ElemType != nil {
+		// The items of collections are rendered using the element view with the same name.
+		if emt, ok := a.ElemType.Type.(*MediaTypeDefinition); ok {
+			for n := range m.Views {
+				if _, ok := emt.Views[n]; !ok {
+					verr.Add(m, "collection view %#v is not defined by the element media type %#v", n, emt.Identifier)
+				}
+			}
+		}
+	}
 
 	for _, l := range m.Links {
 		verr.Merge(l.Validate())
@@ -610,5 +819,30 @@ func (v *ViewDefinition) Validate() *dslengine.ValidationErrors {
 		verr.Add(v, "View must have a parent media type")
 	}
 	verr.Merge(v.AttributeDefinition.Validate("", v))
+	if o := v.Type.ToObject(); o != nil {
+		for n, att := range o {
+			if att.View == "" {
+				continue
+			}
+			if mt, ok := att.Type.(*MediaTypeDefinition); !ok {
+				verr.Add(v, "attribute %s is rendered using view %#v but its type is not a media type", n, att.View)
+			} else if _, ok := mt.Views[att.View]; !ok {
+				verr.Add(v, "attribute %s is rendered using view %#v which is not defined by media type %#v", n, att.View, mt.Identifier)
+			}
+		}
+	}
 	return verr.AsError()
 }
+
+// validateView records an error in verr if the media type with the given identifier does not
+// define the view with the given name. Media types that are not defined by the design are not
+// checked.
+func validateView(verr *dslengine.ValidationErrors, def dslengine.Definition, identifier, view, what string) {
+	mt := Design.MediaTypeWithIdentifier(identifier)
+	if mt == nil {
+		return
+	}
+	if _, ok := mt.Views[view]; !ok {
+		verr.Add(def, "%s type %#v does not define view %#v", what, identifier, view)
+	}
+}
@@ -78,6 +78,56 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a default value that doesn't match the pattern", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Pattern("^[a-z]+$")
+						Default("Foo")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`default value "Foo" does not match the pattern "^[a-z]+$"`))
+			})
+		})
+
+		Context("with a default value out of range", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Integer, func() {
+						Minimum(1)
+						Maximum(10)
+						Default(11)
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("default value 11 is greater than the maximum 10"))
+			})
+		})
+
+		Context("with an integer default value on a number attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Number, func() {
+						Enum(1.0, 2.5)
+						Default(1)
+					})
+				}
+			})
+
+			It("coerces the values to float64", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(att.DefaultValue).Should(Equal(1.0))
+				Ω(att.Validation.Values).Should(Equal([]interface{}{1.0, 2.5}))
+			})
+		})
+
 		Context("with a valid format validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -288,3 +338,268 @@ var _ = Describe("Validation", func() {
 		})
 	})
 })
+
+var _ = Describe("View validation", func() {
+	var mediaDSL, resourceDSL, collectionDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		mediaDSL = func() {
+			Attribute("account")
+		}
+		resourceDSL = nil
+		collectionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		account := MediaType("application/vnd.account", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+			})
+			View("link", func() {
+				Attribute("id")
+			})
+		})
+		bottle := MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("account", account)
+				Links(func() {
+					Link("account")
+				})
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("links")
+			})
+			View("extended", mediaDSL)
+		})
+		accounts := CollectionOf(account, collectionDSL)
+		Resource("bottle", func() {
+			DefaultMedia(bottle)
+			if resourceDSL != nil {
+				resourceDSL()
+			}
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+			})
+			Action("accounts", func() {
+				Routing(GET("/:id/accounts"))
+				Response(OK, accounts)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with consistent views", func() {
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a view rendering an attribute using an unknown view", func() {
+		BeforeEach(func() {
+			mediaDSL = func() {
+				Attribute("account", func() {
+					View("unknown")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`attribute account is rendered using view "unknown" which is not defined by media type "application/vnd.account"`))
+		})
+	})
+
+	Context("with a view rendering a non media type attribute using a view", func() {
+		BeforeEach(func() {
+			mediaDSL = func() {
+				Attribute("id", func() {
+					View("default")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`attribute id is rendered using view "default" but its type is not a media type`))
+		})
+	})
+
+	Context("with a response using an unknown view", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Response(NotFound, func() {
+					Media("application/vnd.bottle", "unknown")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`response media type "application/vnd.bottle" does not define view "unknown"`))
+		})
+	})
+
+	Context("with a default media using an unknown view", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				DefaultMedia("application/vnd.bottle", "unknown")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`default media type "application/vnd.bottle" does not define view "unknown"`))
+		})
+	})
+
+	Context("with a collection view not defined by the element", func() {
+		BeforeEach(func() {
+			collectionDSL = func() {
+				View("default")
+				View("extended", func() {
+					Attribute("id")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`collection view "extended" is not defined by the element media type "application/vnd.account"`))
+		})
+	})
+})
+
+var _ = Describe("Required attributes in views", func() {
+	var viewDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		viewDSL = func() {
+			Attribute("id")
+			Attribute("name")
+		}
+	})
+
+	JustBeforeEach(func() {
+		MediaType("application/vnd.account", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+				Attribute("href", String)
+				Required("id", "name")
+			})
+			View("default", viewDSL)
+			View("link", func() {
+				Attribute("href")
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with views rendering all required attributes", func() {
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a view omitting a required attribute", func() {
+		BeforeEach(func() {
+			viewDSL = func() {
+				Attribute("id")
+				Attribute("href")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(`view does not render required attribute "name"`))
+		})
+	})
+
+	Context("with a partial view omitting a required attribute", func() {
+		BeforeEach(func() {
+			viewDSL = func() {
+				Partial()
+				Attribute("id")
+				Attribute("href")
+			}
+		})
+
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Object parameters", func() {
+	var filterDSL func()
+	var route func(string) *RouteDefinition
+	var path string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		filterDSL = func() {
+			Attribute("status", String)
+			Attribute("min", Integer)
+			Attribute("tags", ArrayOf(String))
+		}
+		route = GET
+		path = ""
+	})
+
+	JustBeforeEach(func() {
+		filter := Type("Filter", filterDSL)
+		Resource("bottle", func() {
+			Action("list", func() {
+				Routing(route(path))
+				Params(func() {
+					Param("filter", filter)
+				})
+				Response(NoContent)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with a query parameter using an object user type", func() {
+		It("does not report errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a path parameter using an object user type", func() {
+		BeforeEach(func() {
+			path = "/:filter"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("path parameter filter cannot be an object"))
+		})
+	})
+
+	Context("with a nested object attribute", func() {
+		BeforeEach(func() {
+			filterDSL = func() {
+				Attribute("status", String)
+				Attribute("range", func() {
+					Attribute("min", Integer)
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("attribute range of parameter filter must be a primitive or an array of primitives"))
+		})
+	})
+})
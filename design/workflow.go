@@ -0,0 +1,134 @@
+package design
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+type (
+	// WorkflowDefinition describes a multi-endpoint flow: a sequence of steps that each call
+	// an action and may be undone by calling a compensating action if a later step fails.
+	WorkflowDefinition struct {
+		// Name of the workflow, e.g. "order_fulfillment"
+		Name string
+		// Description of the workflow
+		Description string
+		// Steps lists the workflow steps in execution order.
+		Steps []*WorkflowStepDefinition
+		// DSLFunc contains the DSL used to create this definition if any.
+		DSLFunc func()
+	}
+
+	// WorkflowStepDefinition describes a step of a workflow.
+	WorkflowStepDefinition struct {
+		// Name of the step, e.g. "reserve"
+		Name string
+		// Resource is the name of the resource of the action called by the step.
+		Resource string
+		// Action is the name of the action called by the step.
+		Action string
+		// CompensationResource is the name of the resource of the action that undoes the
+		// step if any.
+		CompensationResource string
+		// CompensationAction is the name of the action that undoes the step if any.
+		CompensationAction string
+		// Parent is the workflow definition.
+		Parent *WorkflowDefinition
+	}
+)
+
+// DSL returns the DSL function
+func (w *WorkflowDefinition) DSL() func() {
+	return w.DSLFunc
+}
+
+// Context returns the generic definition name used in error messages.
+func (w *WorkflowDefinition) Context() string {
+	if w.Name != "" {
+		return fmt.Sprintf("workflow %#v", w.Name)
+	}
+	return "unnamed workflow"
+}
+
+// Validate makes sure the workflow has at least one step, that the step names are unique and
+// that the actions called by the steps exist.
+func (w *WorkflowDefinition) Validate() error {
+	verr := new(dslengine.ValidationErrors)
+	if w.Name == "" {
+		verr.Add(w, "workflow name cannot be empty")
+	}
+	if len(w.Steps) == 0 {
+		verr.Add(w, "workflow must define at least one step")
+	}
+	seen := make(map[string]bool)
+	for _, s := range w.Steps {
+		if seen[s.Name] {
+			verr.Add(w, "duplicate step %#v", s.Name)
+		}
+		seen[s.Name] = true
+		verr.Merge(s.Validate())
+	}
+	err := verr.AsError()
+	if err == nil {
+		// *ValidationErrors(nil) != error(nil)
+		return nil
+	}
+	return err
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *WorkflowStepDefinition) Context() string {
+	var suffix string
+	if s.Parent != nil {
+		suffix = " of " + s.Parent.Context()
+	}
+	if s.Name != "" {
+		return fmt.Sprintf("step %#v%s", s.Name, suffix)
+	}
+	return "unnamed step" + suffix
+}
+
+// CallAction returns the action called by the step, nil if it does not exist.
+func (s *WorkflowStepDefinition) CallAction() *ActionDefinition {
+	return lookupAction(s.Resource, s.Action)
+}
+
+// CompensationCallAction returns the action that undoes the step, nil if there isn't one.
+func (s *WorkflowStepDefinition) CompensationCallAction() *ActionDefinition {
+	if s.CompensationAction == "" {
+		return nil
+	}
+	return lookupAction(s.CompensationResource, s.CompensationAction)
+}
+
+// Validate makes sure the step has a name and that the actions it calls exist.
+func (s *WorkflowStepDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	switch s.Name {
+	case "":
+		verr.Add(s, "step name cannot be empty")
+	case "pending", "completed", "compensated", "failed":
+		verr.Add(s, "step name %#v is reserved for the workflow states", s.Name)
+	}
+	if s.CallAction() == nil {
+		verr.Add(s, "unknown action %#v of resource %#v", s.Action, s.Resource)
+	}
+	if s.CompensationAction != "" && s.CompensationCallAction() == nil {
+		verr.Add(s, "unknown compensating action %#v of resource %#v", s.CompensationAction, s.CompensationResource)
+	}
+	return verr
+}
+
+// lookupAction returns the action with the given name of the resource with the given name, nil if
+// there isn't one.
+func lookupAction(resource, action string) *ActionDefinition {
+	if Design == nil {
+		return nil
+	}
+	r, ok := Design.Resources[resource]
+	if !ok {
+		return nil
+	}
+	return r.Actions[action]
+}
@@ -0,0 +1,95 @@
+package goa
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type (
+	// DispatchResponse is the response of an action called in-process with Dispatch.
+	DispatchResponse struct {
+		// Status is the response status code.
+		Status int
+		// Header holds the response headers.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+	}
+
+	// dispatchRecorder is the http.ResponseWriter used to record the action responses.
+	dispatchRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+// Dispatch sends req to the action that handles it through mux and returns the recorded response.
+// The packages that expose the actions over other transports, e.g. jsonrpc, mqtt or cron, use
+// Dispatch so that the action middleware, decoding and validation run as for the HTTP requests.
+func Dispatch(mux ServeMux, req *http.Request) *DispatchResponse {
+	rec := &dispatchRecorder{header: make(http.Header), status: http.StatusOK}
+	mux.ServeHTTP(rec, req)
+	return &DispatchResponse{Status: rec.status, Header: rec.header, Body: rec.body.Bytes()}
+}
+
+// DispatchURL returns the URL of the request that calls the action with the given route path and
+// parameters. The parameters with a single value whose names match a path wildcard replace the
+// wildcard, the other parameters are encoded in the query string. The values of ":name" wildcards
+// are escaped as a single path segment, the values of "*name" catch-all wildcards may span
+// multiple segments and have each segment escaped.
+func DispatchURL(path string, params url.Values) string {
+	query := make(url.Values)
+	for n, vals := range params {
+		if len(vals) == 1 {
+			if p, ok := replaceWildcard(path, n, vals[0]); ok {
+				path = p
+				continue
+			}
+		}
+		query[n] = vals
+	}
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// replaceWildcard replaces the wildcard of path with the given name with value, it returns false if
+// path does not contain the wildcard.
+func replaceWildcard(path, name, value string) (string, bool) {
+	elems := strings.Split(path, "/")
+	found := false
+	for i, e := range elems {
+		switch e {
+		case ":" + name:
+			elems[i] = url.PathEscape(value)
+			found = true
+		case "*" + name:
+			segs := strings.Split(value, "/")
+			for j, s := range segs {
+				segs[j] = url.PathEscape(s)
+			}
+			elems[i] = strings.Join(segs, "/")
+			found = true
+		}
+	}
+	return strings.Join(elems, "/"), found
+}
+
+// Header returns the response headers.
+func (r *dispatchRecorder) Header() http.Header {
+	return r.header
+}
+
+// WriteHeader records the response status code.
+func (r *dispatchRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// Write records the response body.
+func (r *dispatchRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
@@ -0,0 +1,44 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dispatch", func() {
+	It("records the action response", func() {
+		mux := goa.NewMux()
+		mux.Handle("GET", "/bottles/:id", func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+			rw.Header().Set("X-Id", params.Get("id"))
+			rw.WriteHeader(http.StatusAccepted)
+			rw.Write([]byte("bottle"))
+		})
+		req, err := http.NewRequest("GET", "/bottles/42", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		resp := goa.Dispatch(mux, req)
+		Ω(resp.Status).Should(Equal(http.StatusAccepted))
+		Ω(resp.Header.Get("X-Id")).Should(Equal("42"))
+		Ω(string(resp.Body)).Should(Equal("bottle"))
+	})
+})
+
+var _ = Describe("DispatchURL", func() {
+	It("replaces the wildcards and encodes the other parameters in the query string", func() {
+		u := goa.DispatchURL("/bottles/:id", url.Values{"id": {"a b"}, "sort": {"name"}})
+		Ω(u).Should(Equal("/bottles/a%20b?sort=name"))
+	})
+
+	It("escapes each segment of the catch-all wildcard values", func() {
+		u := goa.DispatchURL("/files/*path", url.Values{"path": {"a b/c?d"}})
+		Ω(u).Should(Equal("/files/a%20b/c%3Fd"))
+	})
+
+	It("encodes the parameters with multiple values in the query string", func() {
+		u := goa.DispatchURL("/bottles/:id", url.Values{"id": {"1", "2"}})
+		Ω(u).Should(Equal("/bottles/:id?id=1&id=2"))
+	})
+})
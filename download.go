@@ -0,0 +1,100 @@
+package goa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResumeTokenHeader is the name of the header that carries the opaque resume token of downloads.
+// ServeDownload sets it in the responses and clients send it back together with a Range header to
+// resume an interrupted download.
+const ResumeTokenHeader = "Resume-Token"
+
+// DownloadInfo describes a file sent by ServeDownload.
+type DownloadInfo struct {
+	// Filename is the name of the file listed in the Content-Disposition header if not empty.
+	// It is also used to infer the content type if ContentType is empty.
+	Filename string
+	// Inline is true if the Content-Disposition header asks user agents to display the file
+	// rather than to save it.
+	Inline bool
+	// ContentType is the value of the Content-Type header, it is inferred from the file name
+	// extension or from the content if empty.
+	ContentType string
+	// ModTime is the last modification time of the file, it may be zero if unknown in which
+	// case the resume token and ETag are derived from a hash of the content.
+	ModTime time.Time
+}
+
+// ResumeToken returns the opaque token that identifies the given version of a downloaded file.
+// The token only identifies the version if modtime is not zero, ServeDownload derives the token
+// from a hash of the content when the modification time is unknown.
+func ResumeToken(size int64, modtime time.Time) string {
+	token := strconv.FormatInt(size, 36)
+	if !modtime.IsZero() {
+		token += "-" + strconv.FormatInt(modtime.UnixNano(), 36)
+	}
+	return token
+}
+
+// ServeDownload writes the response that sends content as a downloadable file. It sets the
+// Content-Disposition header, the ETag and resume token headers and honors the Range, If-Range and
+// conditional request headers (see http.ServeContent). A request that carries a resume token and
+// no If-Range header gets the requested range only if the token still matches the content, the
+// full file otherwise. The content is read twice if info.ModTime is zero: once to compute the
+// hash the token is derived from and once to send it.
+func ServeDownload(rw *ResponseData, req *RequestData, content io.ReadSeeker, info DownloadInfo) error {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	token := ResumeToken(size, info.ModTime)
+	if info.ModTime.IsZero() {
+		if token, err = contentToken(content); err != nil {
+			return err
+		}
+	}
+	h := rw.Header()
+	h.Set(ResumeTokenHeader, token)
+	if h.Get("ETag") == "" {
+		h.Set("ETag", `"`+token+`"`)
+	}
+	disposition := "attachment"
+	if info.Inline {
+		disposition = "inline"
+	}
+	if info.Filename != "" {
+		if d := mime.FormatMediaType(disposition, map[string]string{"filename": info.Filename}); d != "" {
+			disposition = d
+		}
+	}
+	h.Set("Content-Disposition", disposition)
+	if info.ContentType != "" {
+		h.Set("Content-Type", info.ContentType)
+	}
+	if resume := req.Header.Get(ResumeTokenHeader); resume != "" && req.Header.Get("If-Range") == "" {
+		req.Header.Set("If-Range", `"`+resume+`"`)
+	}
+	http.ServeContent(rw, req.Request, info.Filename, info.ModTime, content)
+	return nil
+}
+
+// contentToken returns the resume token derived from the hash of content and rewinds it.
+func contentToken(content io.ReadSeeker) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return "h" + hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
@@ -0,0 +1,123 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("ServeDownload", func() {
+	const content = "0123456789"
+	var modtime = time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+	var info goa.DownloadInfo
+	var err error
+
+	BeforeEach(func() {
+		rw = httptest.NewRecorder()
+		req, err = http.NewRequest("GET", "/reports/q1.pdf", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		info = goa.DownloadInfo{Filename: "q1.pdf", ContentType: "application/pdf", ModTime: modtime}
+	})
+
+	JustBeforeEach(func() {
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		err = goa.ServeDownload(goa.ContextResponse(ctx), goa.ContextRequest(ctx), strings.NewReader(content), info)
+	})
+
+	It("sends the file", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Body.String()).Should(Equal(content))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("application/pdf"))
+		Ω(rw.Header().Get("Content-Disposition")).Should(Equal("attachment; filename=q1.pdf"))
+		Ω(rw.Header().Get("Accept-Ranges")).Should(Equal("bytes"))
+		token := goa.ResumeToken(int64(len(content)), modtime)
+		Ω(rw.Header().Get(goa.ResumeTokenHeader)).Should(Equal(token))
+		Ω(rw.Header().Get("ETag")).Should(Equal(`"` + token + `"`))
+	})
+
+	Context("with an unknown modification time", func() {
+		BeforeEach(func() {
+			info.ModTime = time.Time{}
+		})
+
+		It("derives the resume token from the content", func() {
+			token := rw.Header().Get(goa.ResumeTokenHeader)
+			Ω(token).ShouldNot(Equal(goa.ResumeToken(int64(len(content)), time.Time{})))
+			Ω(rw.Header().Get("ETag")).Should(Equal(`"` + token + `"`))
+		})
+
+		It("does not match the tokens of other contents of the same size", func() {
+			other := httptest.NewRecorder()
+			ctx := goa.NewContext(context.Background(), other, req, nil)
+			err := goa.ServeDownload(goa.ContextResponse(ctx), goa.ContextRequest(ctx), strings.NewReader("9876543210"), info)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other.Header().Get("ETag")).ShouldNot(Equal(rw.Header().Get("ETag")))
+		})
+
+		Context("with a stale resume token", func() {
+			BeforeEach(func() {
+				req.Header.Set("Range", "bytes=4-")
+				req.Header.Set(goa.ResumeTokenHeader, goa.ResumeToken(int64(len(content)), time.Time{}))
+			})
+
+			It("sends the full file", func() {
+				Ω(rw.Code).Should(Equal(200))
+				Ω(rw.Body.String()).Should(Equal(content))
+			})
+		})
+	})
+
+	Context("inline", func() {
+		BeforeEach(func() {
+			info.Inline = true
+			info.Filename = ""
+		})
+
+		It("sets the disposition", func() {
+			Ω(rw.Header().Get("Content-Disposition")).Should(Equal("inline"))
+		})
+	})
+
+	Context("with a Range request", func() {
+		BeforeEach(func() {
+			req.Header.Set("Range", "bytes=4-")
+		})
+
+		It("sends the requested part", func() {
+			Ω(rw.Code).Should(Equal(206))
+			Ω(rw.Body.String()).Should(Equal("456789"))
+		})
+
+		Context("with a matching resume token", func() {
+			BeforeEach(func() {
+				req.Header.Set(goa.ResumeTokenHeader, goa.ResumeToken(int64(len(content)), modtime))
+			})
+
+			It("sends the requested part", func() {
+				Ω(rw.Code).Should(Equal(206))
+				Ω(rw.Body.String()).Should(Equal("456789"))
+			})
+		})
+
+		Context("with a stale resume token", func() {
+			BeforeEach(func() {
+				req.Header.Set(goa.ResumeTokenHeader, goa.ResumeToken(int64(len(content)), modtime.Add(-time.Hour)))
+			})
+
+			It("sends the full file", func() {
+				Ω(rw.Code).Should(Equal(200))
+				Ω(rw.Body.String()).Should(Equal(content))
+			})
+		})
+	})
+})
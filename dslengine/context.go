@@ -0,0 +1,92 @@
+package dslengine
+
+import "sync"
+
+// Context holds the state of a DSL evaluation: the errors reported while running the DSL, the
+// stack of definitions being initialized and the state of the registered stateful roots.
+//
+// The package level functions (Run, Execute, ReportError, CurrentDefinition etc.) and the
+// generators operate on the global evaluation state which is shared by the whole process. A
+// Context provides sequential isolation only: Eval installs the context state as the global state
+// for the duration of the call and restores the previous state afterwards so that evaluating a
+// design with Eval - for example in tests - leaves the global design untouched. Eval calls are
+// serialized but calls to the package level functions made outside of Eval are not, they must not
+// run while another goroutine calls Eval. The definitions built by an evaluation may still refer
+// to the global definitions, e.g. design.Design, and the generators always read the global design
+// so the designs built with distinct contexts cannot be used side by side.
+type Context struct {
+	// Errors contains the DSL execution errors of the last evaluation if any.
+	Errors MultiError
+
+	stack  contextStack
+	states map[string]interface{}
+}
+
+// evalMu serializes evaluations.
+var evalMu sync.Mutex
+
+// NewContext returns an evaluation context whose roots start in their reset state.
+func NewContext() *Context {
+	return &Context{}
+}
+
+// Eval calls fn with c installed as the global evaluation state. fn typically declares the design
+// using the DSL functions and calls Run to execute it. The roots state produced by fn is saved
+// in c so that subsequent calls to Eval with the same context resume from it, see State. Roots
+// that do not implement StatefulRoot are shared by all evaluations. Eval returns the errors
+// reported during the evaluation if any.
+func (c *Context) Eval(fn func()) error {
+	evalMu.Lock()
+	defer evalMu.Unlock()
+
+	prevErrors, prevStack, prevMacros, prevFrozen, prevStates := Errors, ctxStack, macroStack, frozen, saveStates()
+	if c.states == nil {
+		for _, r := range roots {
+			if _, ok := r.(StatefulRoot); ok {
+				r.Reset()
+			}
+		}
+	} else {
+		restoreStates(c.states)
+	}
+	Errors, ctxStack, macroStack = c.Errors, c.stack, nil
+	defer func() {
+		c.Errors, c.stack, c.states = Errors, ctxStack, saveStates()
+		Errors, ctxStack, macroStack, frozen = prevErrors, prevStack, prevMacros, prevFrozen
+		restoreStates(prevStates)
+	}()
+
+	fn()
+	if len(Errors) > 0 {
+		return Errors
+	}
+	return nil
+}
+
+// State returns the state of the stateful root with the given DSL name as saved at the end of the
+// last call to Eval, nil if there is no such root or if Eval has not been called.
+func (c *Context) State(dslName string) interface{} {
+	return c.states[dslName]
+}
+
+// saveStates returns the state of the registered stateful roots indexed by DSL name.
+func saveStates() map[string]interface{} {
+	states := make(map[string]interface{})
+	for _, r := range roots {
+		if s, ok := r.(StatefulRoot); ok {
+			states[r.DSLName()] = s.SaveState()
+		}
+	}
+	return states
+}
+
+// restoreStates restores the state of the registered stateful roots.
+func restoreStates(states map[string]interface{}) {
+	for _, r := range roots {
+		if s, ok := r.(StatefulRoot); ok {
+			if state, ok := states[r.DSLName()]; ok {
+				s.RestoreState(state)
+			}
+		}
+	}
+}
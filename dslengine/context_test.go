@@ -0,0 +1,82 @@
+package dslengine_test
+
+import (
+	"sync"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context", func() {
+	var ctx1, ctx2 *dslengine.Context
+
+	evalDesign := func(ctx *dslengine.Context, name string) error {
+		return ctx.Eval(func() {
+			API(name, nil)
+			Type(name+"Type", func() {
+				Attribute("name")
+			})
+			dslengine.Run()
+		})
+	}
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("global", nil)
+		ctx1 = dslengine.NewContext()
+		ctx2 = dslengine.NewContext()
+	})
+
+	It("isolates the evaluated designs", func() {
+		Ω(evalDesign(ctx1, "foo")).ShouldNot(HaveOccurred())
+		Ω(evalDesign(ctx2, "bar")).ShouldNot(HaveOccurred())
+		Ω(Design.Name).Should(Equal("global"))
+		Ω(Design.Types).Should(BeEmpty())
+		foo, bar := DesignIn(ctx1), DesignIn(ctx2)
+		Ω(foo.Name).Should(Equal("foo"))
+		Ω(foo.Types).Should(HaveKey("fooType"))
+		Ω(foo.Types).ShouldNot(HaveKey("barType"))
+		Ω(bar.Name).Should(Equal("bar"))
+		Ω(bar.Types).Should(HaveKey("barType"))
+	})
+
+	It("isolates the errors", func() {
+		err := ctx1.Eval(func() {
+			dslengine.ReportError("boom")
+		})
+		Ω(err).Should(HaveOccurred())
+		Ω(ctx1.Errors).Should(HaveLen(1))
+		Ω(dslengine.Errors).Should(BeEmpty())
+		Ω(evalDesign(ctx2, "bar")).ShouldNot(HaveOccurred())
+	})
+
+	It("resumes from the saved state", func() {
+		Ω(evalDesign(ctx1, "foo")).ShouldNot(HaveOccurred())
+		Ω(ctx1.Eval(func() {
+			Ω(Design.Name).Should(Equal("foo"))
+		})).ShouldNot(HaveOccurred())
+		Ω(DesignIn(dslengine.NewContext())).Should(BeNil())
+	})
+
+	It("serializes concurrent evaluations", func() {
+		var wg sync.WaitGroup
+		ctxs := make([]*dslengine.Context, 10)
+		for i := range ctxs {
+			ctxs[i] = dslengine.NewContext()
+			wg.Add(1)
+			go func(ctx *dslengine.Context) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Ω(evalDesign(ctx, "concurrent")).ShouldNot(HaveOccurred())
+			}(ctxs[i])
+		}
+		wg.Wait()
+		for _, ctx := range ctxs {
+			Ω(DesignIn(ctx).Types).Should(HaveLen(1))
+		}
+		Ω(Design.Name).Should(Equal("global"))
+	})
+})
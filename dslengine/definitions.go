@@ -35,6 +35,16 @@ type (
 		Reset()
 	}
 
+	// StatefulRoot is the interface implemented by the DSL roots whose state can be saved and
+	// restored. The state of these roots is isolated when running DSLs with a Context.
+	StatefulRoot interface {
+		Root
+		// SaveState returns a copy of the root state.
+		SaveState() interface{}
+		// RestoreState replaces the root state with a state returned by SaveState.
+		RestoreState(interface{})
+	}
+
 	// Validate is the interface implemented by definitions that can be validated.
 	// Validation is done by the DSL dsl post execution.
 	Validate interface {
@@ -62,6 +72,14 @@ type (
 		Finalize()
 	}
 
+	// Location is a position in the design source code.
+	Location struct {
+		// File is the absolute path to the source file.
+		File string
+		// Line is the line number in File.
+		Line int
+	}
+
 	// SetIterator is the function signature used to iterate over definition sets with
 	// IterateSets.
 	SetIterator func(s DefinitionSet) error
@@ -0,0 +1,178 @@
+package dslengine
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// CheckMutations enables the detection of the changes made to the definitions once Run has
+// finalized them. When enabled Run records a fingerprint of each definition it finalizes and
+// CheckFrozen panics if a definition no longer matches its fingerprint. This catches generators
+// that modify the definitions shared with other generators and thus produce output that depends
+// on the order in which the generators run. goagen enables the detection in debug mode.
+var CheckMutations bool
+
+// frozenDefinition records the fingerprint of a finalized definition.
+type frozenDefinition struct {
+	def Definition
+	sum uint64
+}
+
+// frozen lists the definitions finalized by the last Run if CheckMutations is enabled.
+var frozen []*frozenDefinition
+
+// CheckFrozen panics if a definition finalized by the last Run was modified since, it does nothing
+// unless CheckMutations was enabled when Run executed. Only the exported fields of the definitions
+// are taken into account so that definitions may cache computed values in unexported fields.
+// Exported fields holding values computed on demand must be tagged with `dslengine:"lazy"`.
+func CheckFrozen() {
+	stop := frozenPointers()
+	for _, f := range frozen {
+		if fingerprint(f.def, stop) != f.sum {
+			panic(fmt.Sprintf("dslengine: %s was modified after the DSL was finalized, generators must not modify the design definitions", f.def.Context()))
+		}
+	}
+}
+
+// freeze records the definitions of the given set so that their fingerprints are computed once
+// all the definitions are finalized.
+func freeze(set DefinitionSet) {
+	for _, def := range set {
+		frozen = append(frozen, &frozenDefinition{def: def})
+	}
+}
+
+// computeFingerprints computes the fingerprints of the frozen definitions.
+func computeFingerprints() {
+	stop := frozenPointers()
+	for _, f := range frozen {
+		f.sum = fingerprint(f.def, stop)
+	}
+}
+
+// frozenPointers returns the addresses of the frozen definitions. The fingerprint of a definition
+// does not include the content of the other frozen definitions it refers to so that changes are
+// attributed to the modified definition.
+func frozenPointers() map[uintptr]bool {
+	ptrs := make(map[uintptr]bool, len(frozen))
+	for _, f := range frozen {
+		if v := reflect.ValueOf(f.def); v.Kind() == reflect.Ptr {
+			ptrs[v.Pointer()] = true
+		}
+	}
+	return ptrs
+}
+
+// fingerprint computes the fingerprint of the given definition.
+func fingerprint(def Definition, stop map[uintptr]bool) uint64 {
+	h := &hasher{Hash64: fnv.New64a(), stop: stop, seen: make(map[uintptr]bool)}
+	v := reflect.ValueOf(def)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		// Hash the content of the definition itself even though its address is in stop.
+		h.seen[v.Pointer()] = true
+		h.writeUint(uint64(v.Pointer()))
+		v = v.Elem()
+	}
+	h.write(v)
+	return h.Sum64()
+}
+
+// hasher computes fingerprints by walking values recursively.
+type hasher struct {
+	hash.Hash64
+	stop map[uintptr]bool // Addresses of the values whose content is not hashed
+	seen map[uintptr]bool // Addresses of the values already hashed
+}
+
+func (h *hasher) write(v reflect.Value) {
+	h.writeUint(uint64(v.Kind()))
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		p := v.Pointer()
+		h.writeUint(uint64(p))
+		if h.seen[p] || h.stop[p] {
+			return
+		}
+		h.seen[p] = true
+		h.write(v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		h.Write([]byte(v.Elem().Type().String()))
+		h.write(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath != "" || f.Tag.Get("dslengine") == "lazy" {
+				continue
+			}
+			h.write(v.Field(i))
+		}
+	case reflect.Map:
+		h.writeUint(uint64(v.Len()))
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		sort.Sort(byName{keys, names})
+		for _, k := range keys {
+			h.write(k)
+			h.write(v.MapIndex(k))
+		}
+	case reflect.Slice, reflect.Array:
+		h.writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h.write(v.Index(i))
+		}
+	case reflect.String:
+		h.writeUint(uint64(v.Len()))
+		h.Write([]byte(v.String()))
+	case reflect.Bool:
+		if v.Bool() {
+			h.writeUint(1)
+		} else {
+			h.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		h.writeUint(math.Float64bits(real(c)))
+		h.writeUint(math.Float64bits(imag(c)))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		h.writeUint(uint64(v.Pointer()))
+	}
+}
+
+func (h *hasher) writeUint(u uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], u)
+	h.Write(b[:])
+}
+
+// byName sorts map keys by their string representation.
+type byName struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (b byName) Len() int           { return len(b.keys) }
+func (b byName) Less(i, j int) bool { return b.names[i] < b.names[j] }
+func (b byName) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
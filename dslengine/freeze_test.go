@@ -0,0 +1,78 @@
+package dslengine_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckFrozen", func() {
+	var checkMutations bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		checkMutations = true
+	})
+
+	JustBeforeEach(func() {
+		prev := dslengine.CheckMutations
+		dslengine.CheckMutations = checkMutations
+		defer func() { dslengine.CheckMutations = prev }()
+		API("foo", nil)
+		Resource("bottle", func() {
+			Headers(func() {
+				Header("X-Account")
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Headers(func() {
+					Header("X-Request")
+				})
+				Params(func() {
+					Param("id", Integer)
+				})
+			})
+		})
+		Ω(dslengine.Run()).Should(Succeed())
+	})
+
+	It("does not panic when the definitions are left untouched", func() {
+		Ω(dslengine.CheckFrozen).ShouldNot(Panic())
+	})
+
+	It("does not panic when the definitions are read", func() {
+		a := Design.Resources["bottle"].Actions["show"]
+		a.AllParams()
+		a.IterateHeaders(func(string, bool, *AttributeDefinition) error { return nil })
+		a.Params.GenerateExample(Design.RandomGenerator(), nil)
+		Ω(dslengine.CheckFrozen).ShouldNot(Panic())
+	})
+
+	It("panics when a definition is modified", func() {
+		Design.Resources["bottle"].Description = "modified"
+		var msg interface{}
+		func() {
+			defer func() { msg = recover() }()
+			dslengine.CheckFrozen()
+		}()
+		Ω(msg).Should(ContainSubstring(`resource "bottle" was modified after the DSL was finalized`))
+	})
+
+	It("panics when a nested definition is modified", func() {
+		Design.Resources["bottle"].Actions["show"].Params.Type.ToObject()["id"].Type = String
+		Ω(dslengine.CheckFrozen).Should(Panic())
+	})
+
+	Context("with mutation checks disabled", func() {
+		BeforeEach(func() {
+			checkMutations = false
+		})
+
+		It("does not panic", func() {
+			Design.Resources["bottle"].Description = "modified"
+			Ω(dslengine.CheckFrozen).ShouldNot(Panic())
+		})
+	})
+})
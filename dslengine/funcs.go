@@ -0,0 +1,73 @@
+package dslengine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// funcRegistration records a DSL function registered by a DSL package.
+type funcRegistration struct {
+	pkg string  // Import path of the DSL package
+	fn  uintptr // Entry point of the function implementation
+}
+
+// dslFuncs lists the registered DSL functions indexed by name.
+var dslFuncs = make(map[string][]*funcRegistration)
+
+// RegisterFuncs records the DSL functions exported by the DSL package with the given import path,
+// indexed by name. DSL packages call RegisterFuncs in an init function so that Run detects the
+// designs that use DSL packages exporting functions with the same name but different
+// implementations: such packages cannot be dot imported together and mixing them up is error
+// prone when they are referred to by package name. Run reports an error for each conflicting
+// name. Registering the same implementation in different packages, as done by packages that
+// re-export the functions of another DSL, is not a conflict.
+// RegisterFuncs panics if one of the values is not a function.
+func RegisterFuncs(pkgPath string, funcs map[string]interface{}) {
+	for name, fn := range funcs {
+		v := reflect.ValueOf(fn)
+		if v.Kind() != reflect.Func || v.IsNil() {
+			panic(fmt.Sprintf("dslengine: DSL function %s of %s is a %T", name, pkgPath, fn))
+		}
+		reg := &funcRegistration{pkg: pkgPath, fn: v.Pointer()}
+		regs := dslFuncs[name]
+		found := false
+		for _, r := range regs {
+			if r.pkg == reg.pkg {
+				r.fn, found = reg.fn, true
+			}
+		}
+		if !found {
+			dslFuncs[name] = append(regs, reg)
+		}
+	}
+	dslPackages[pkgPath] = true
+}
+
+// checkFuncs records an error for each DSL function name registered by several packages with
+// different implementations.
+func checkFuncs() {
+	names := make([]string, 0, len(dslFuncs))
+	for name := range dslFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		regs := dslFuncs[name]
+		var pkgs []string
+		for _, r := range regs {
+			if r.fn != regs[0].fn {
+				pkgs = append(pkgs, r.pkg)
+			}
+		}
+		if len(pkgs) == 0 {
+			continue
+		}
+		pkgs = append([]string{regs[0].pkg}, pkgs...)
+		Errors = append(Errors, &Error{
+			GoError: fmt.Errorf("DSL function %s is defined with different semantics by packages %s",
+				name, strings.Join(pkgs, ", ")),
+		})
+	}
+}
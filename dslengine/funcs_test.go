@@ -0,0 +1,63 @@
+package dslengine_test
+
+import (
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func conflictingA() {}
+
+func conflictingB() {}
+
+var _ = Describe("RegisterFuncs", func() {
+	var funcs map[string]interface{}
+	var runErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dslengine.RegisterFuncs("example.com/a", map[string]interface{}{"Conflicting": conflictingA})
+		funcs = nil
+	})
+
+	JustBeforeEach(func() {
+		dslengine.RegisterFuncs("example.com/b", funcs)
+		API("foo", nil)
+		runErr = dslengine.Run()
+	})
+
+	AfterEach(func() {
+		dslengine.RegisterFuncs("example.com/b", map[string]interface{}{"Conflicting": conflictingA})
+	})
+
+	Context("with packages exporting the same function", func() {
+		BeforeEach(func() {
+			funcs = map[string]interface{}{"Conflicting": conflictingA, "Attribute": Attribute}
+		})
+
+		It("does not report an error", func() {
+			Ω(runErr).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with packages exporting different functions with the same name", func() {
+		BeforeEach(func() {
+			funcs = map[string]interface{}{"Conflicting": conflictingB}
+		})
+
+		It("reports the conflict", func() {
+			Ω(runErr).Should(HaveOccurred())
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(runErr.Error()).Should(Equal("DSL function Conflicting is defined with different semantics by packages example.com/a, example.com/b"))
+		})
+	})
+
+	Context("with a value that is not a function", func() {
+		It("panics", func() {
+			Ω(func() {
+				dslengine.RegisterFuncs("example.com/c", map[string]interface{}{"NotAFunc": 42})
+			}).Should(Panic())
+		})
+	})
+})
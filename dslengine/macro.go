@@ -0,0 +1,79 @@
+package dslengine
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// macroCall records the execution of a user defined DSL function.
+type macroCall struct {
+	name string // Name of the DSL function
+	file string // File of the design code calling the DSL function
+	line int    // Line of the design code calling the DSL function
+}
+
+// macroStack lists the user defined DSL functions being executed, outermost first.
+var macroStack []*macroCall
+
+// Macro runs dsl as the body of the user defined DSL function with the given name. User defined
+// DSL functions compose built-in DSL functions and wrapping their body with Macro makes them
+// behave like built-ins: the errors reported while the body runs, including the errors reported by
+// the built-in DSL functions it calls, are located at the design code that calls the outermost
+// user defined function rather than inside its body. Use Expect to check the definition the
+// function applies to. Example:
+//
+//	// StandardTimestamps adds the "created_at" and "updated_at" attributes to the type or media
+//	// type being defined.
+//	func StandardTimestamps() {
+//		dslengine.Macro("StandardTimestamps", func() {
+//			var att *design.AttributeDefinition
+//			if !dslengine.Expect(&att) {
+//				return
+//			}
+//			apidsl.Attribute("created_at", design.DateTime)
+//			apidsl.Attribute("updated_at", design.DateTime)
+//			apidsl.Required("created_at", "updated_at")
+//		})
+//	}
+//
+// Macro must be called directly by the user defined function.
+func Macro(name string, dsl func()) {
+	file, line := callerLocation(3)
+	macroStack = append(macroStack, &macroCall{name: name, file: file, line: line})
+	defer func() { macroStack = macroStack[:len(macroStack)-1] }()
+	dsl()
+}
+
+// Expect checks that the definition currently being built has the type of the value pointed to
+// by target, e.g. *design.AttributeDefinition, or implements it if it is an interface. On success
+// Expect sets the value pointed to by target to the definition and returns true. Otherwise Expect
+// reports an invalid use of the user defined DSL function being executed (or of the function
+// calling Expect when used outside of Macro) and returns false. Expect panics if target is not a
+// non-nil pointer.
+func Expect(target interface{}) bool {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		panic(fmt.Sprintf("dslengine: Expect target must be a non-nil pointer, got %T", target))
+	}
+	current := CurrentDefinition()
+	if reflect.TypeOf(current).AssignableTo(v.Elem().Type()) {
+		v.Elem().Set(reflect.ValueOf(current))
+		return true
+	}
+	name := callerName(caller())
+	if len(macroStack) > 0 {
+		name = macroStack[len(macroStack)-1].name
+	}
+	ReportError("invalid use of %s", name)
+	return false
+}
+
+// macroLocation returns the location of the design code calling the outermost user defined DSL
+// function being executed if any.
+func macroLocation() (file string, line int, ok bool) {
+	if len(macroStack) == 0 {
+		return "", 0, false
+	}
+	m := macroStack[0]
+	return m.file, m.line, true
+}
@@ -0,0 +1,179 @@
+package dslengine_test
+
+import (
+	"runtime"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// standardTimestamps is a user defined DSL function that adds timestamp attributes.
+func standardTimestamps() {
+	dslengine.Macro("StandardTimestamps", func() {
+		var att *AttributeDefinition
+		if !dslengine.Expect(&att) {
+			return
+		}
+		Attribute("created_at", DateTime)
+		Attribute("updated_at", DateTime)
+		Required("created_at", "updated_at")
+	})
+}
+
+// invalidAttribute is a user defined DSL function that calls a built-in with an invalid argument.
+func invalidAttribute() {
+	dslengine.Macro("InvalidAttribute", func() {
+		Attribute("baz", 42)
+	})
+}
+
+// nested is a user defined DSL function that calls another one.
+func nested() {
+	dslengine.Macro("Nested", func() {
+		invalidAttribute()
+	})
+}
+
+// declareType is a user defined DSL function that declares a type.
+func declareType() *UserTypeDefinition {
+	var t *UserTypeDefinition
+	dslengine.Macro("DeclareType", func() {
+		t = Type("declared", func() {
+			Attribute("id", Integer)
+		})
+	})
+	return t
+}
+
+// line returns the line of the code calling it.
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+var _ = Describe("Macro", func() {
+	var errLine int
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		errLine = 0
+	})
+
+	Context("with a macro used in the expected definition", func() {
+		BeforeEach(func() {
+			Type("bar", func() {
+				standardTimestamps()
+			})
+			dslengine.Run()
+		})
+
+		It("runs the macro DSL", func() {
+			Ω(dslengine.Errors).Should(BeEmpty())
+			bar := Design.Types["bar"]
+			Ω(bar).ShouldNot(BeNil())
+			Ω(bar.Type.ToObject()).Should(HaveKey("created_at"))
+			Ω(bar.Type.ToObject()).Should(HaveKey("updated_at"))
+			Ω(bar.IsRequired("created_at")).Should(BeTrue())
+		})
+	})
+
+	Context("with a macro used in an unexpected definition", func() {
+		BeforeEach(func() {
+			API("foo", func() {
+				errLine = line() + 1
+				standardTimestamps()
+			})
+			dslengine.Run()
+		})
+
+		It("reports an invalid use of the macro at the call site", func() {
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors[0].GoError.Error()).Should(HavePrefix("invalid use of StandardTimestamps"))
+			Ω(dslengine.Errors[0].File).Should(HaveSuffix("macro_test.go"))
+			Ω(dslengine.Errors[0].Line).Should(Equal(errLine))
+		})
+	})
+
+	Context("with a macro calling a built-in with an invalid argument", func() {
+		BeforeEach(func() {
+			Type("bar", func() {
+				errLine = line() + 1
+				invalidAttribute()
+			})
+			dslengine.Run()
+		})
+
+		It("reports the error at the call site", func() {
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors[0].GoError.Error()).Should(ContainSubstring("cannot use 42 (type int) as type"))
+			Ω(dslengine.Errors[0].File).Should(HaveSuffix("macro_test.go"))
+			Ω(dslengine.Errors[0].Line).Should(Equal(errLine))
+		})
+	})
+
+	Context("with nested macros", func() {
+		BeforeEach(func() {
+			Type("bar", func() {
+				errLine = line() + 1
+				nested()
+			})
+			dslengine.Run()
+		})
+
+		It("reports the error at the call site of the outermost macro", func() {
+			Ω(dslengine.Errors).Should(HaveLen(1))
+			Ω(dslengine.Errors[0].Line).Should(Equal(errLine))
+		})
+	})
+
+	Context("with a macro declaring a definition", func() {
+		var t *UserTypeDefinition
+
+		BeforeEach(func() {
+			errLine = line() + 1
+			t = declareType()
+		})
+
+		It("records the call site as the definition location", func() {
+			Ω(t.Location.File).Should(HaveSuffix("macro_test.go"))
+			Ω(t.Location.Line).Should(Equal(errLine))
+		})
+	})
+})
+
+var _ = Describe("Expect", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("sets the target to the current definition", func() {
+		var def *APIDefinition
+		var ok bool
+		API("foo", func() {
+			ok = dslengine.Expect(&def)
+		})
+		dslengine.Run()
+		Ω(ok).Should(BeTrue())
+		Ω(def).Should(Equal(Design))
+	})
+
+	It("accepts interfaces", func() {
+		var def dslengine.Definition
+		Ω(dslengine.Expect(&def)).Should(BeTrue())
+		Ω(def.Context()).Should(Equal("top-level"))
+	})
+
+	It("reports an invalid use of the calling function", func() {
+		var def *AttributeDefinition
+		Ω(dslengine.Expect(&def)).Should(BeFalse())
+		Ω(def).Should(BeNil())
+		Ω(dslengine.Errors).Should(HaveLen(1))
+	})
+
+	It("panics when the target is not a pointer", func() {
+		Ω(func() { dslengine.Expect(42) }).Should(Panic())
+	})
+})
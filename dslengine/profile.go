@@ -0,0 +1,37 @@
+package dslengine
+
+import (
+	"os"
+	"strings"
+)
+
+// ProfilesEnv is the name of the environment variable that lists the active DSL profiles as a
+// comma separated list of names. goagen sets it from its --profile flag when running the
+// generators.
+const ProfilesEnv = "GOAGEN_PROFILES"
+
+// ActiveProfiles lists the names of the active DSL profiles. It is initialized from the ProfilesEnv
+// environment variable before the design packages are initialized so that profiles used at the
+// top level of designs are taken into account.
+var ActiveProfiles = ParseProfiles(os.Getenv(ProfilesEnv))
+
+// ParseProfiles returns the profile names listed in the given comma separated list.
+func ParseProfiles(list string) []string {
+	var names []string
+	for _, n := range strings.Split(list, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// ProfileActive returns true if the profile with the given name is active.
+func ProfileActive(name string) bool {
+	for _, p := range ActiveProfiles {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
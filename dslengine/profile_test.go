@@ -0,0 +1,14 @@
+package dslengine_test
+
+import (
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseProfiles", func() {
+	It("parses comma separated lists", func() {
+		Ω(dslengine.ParseProfiles("")).Should(BeEmpty())
+		Ω(dslengine.ParseProfiles(" internal, ,debug ")).Should(Equal([]string{"internal", "debug"}))
+	})
+})
@@ -72,6 +72,7 @@ func Reset() {
 		r.Reset()
 	}
 	Errors = nil
+	frozen = nil
 }
 
 // Run runs the given root definitions. It iterates over the definition sets
@@ -87,6 +88,10 @@ func Run() error {
 		return err
 	}
 	Errors = nil
+	traceRecords = nil
+	frozen = nil
+	defer traceSummary()
+	checkFuncs()
 	executed := 0
 	recursed := 0
 	for executed < len(roots) {
@@ -101,6 +106,7 @@ func Run() error {
 			return fmt.Errorf("too many generated roots, infinite loop?")
 		}
 	}
+	checkVars()
 	if Errors != nil {
 		return Errors
 	}
@@ -113,6 +119,9 @@ func Run() error {
 	for _, root := range roots {
 		root.IterateSets(finalizeSet)
 	}
+	if CheckMutations {
+		computeFingerprints()
+	}
 
 	return nil
 }
@@ -129,7 +138,7 @@ func Execute(dsl func(), def Definition) bool {
 	}
 	initCount := len(Errors)
 	ctxStack = append(ctxStack, def)
-	dsl()
+	traced("dsl", def, dsl)
 	ctxStack = ctxStack[:len(ctxStack)-1]
 	return len(Errors) <= initCount
 }
@@ -205,8 +214,7 @@ func PrintFilesOrFail(files []string, err error) {
 // IncompatibleDSL should be called by DSL functions when they are
 // invoked in an incorrect context (e.g. "Params" in "Resource").
 func IncompatibleDSL() {
-	elems := strings.Split(caller(), ".")
-	ReportError("invalid use of %s", elems[len(elems)-1])
+	ReportError("invalid use of %s", callerName(caller()))
 }
 
 // InvalidArgError records an invalid argument error.
@@ -247,9 +255,51 @@ func (s contextStack) Current() Definition {
 // computeErrorLocation implements a heuristic to find the location in the user
 // code where the error occurred. It walks back the callstack until the file
 // doesn't match "/goa/design/*.go" or one of the DSL package paths.
+// Errors reported while a user defined DSL function runs are located at the
+// code calling the function, see Macro.
 // When successful it returns the file name and line number, empty string and
 // 0 otherwise.
 func computeErrorLocation() (file string, line int) {
+	var ok bool
+	if file, line, ok = macroLocation(); !ok {
+		file, line = callerLocation(3)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	wd, err = filepath.Abs(wd)
+	if err != nil {
+		return
+	}
+	f, err := filepath.Rel(wd, file)
+	if err != nil {
+		return
+	}
+	file = f
+	return
+}
+
+// CallerLocation returns the location of the design code calling the DSL function currently
+// executing. It walks back the callstack the same way error locations are computed. The returned
+// location is the zero value if it cannot be determined.
+func CallerLocation() Location {
+	file, line, ok := macroLocation()
+	if !ok {
+		file, line = callerLocation(3)
+	}
+	if file == "" {
+		return Location{}
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return Location{File: file, Line: line}
+}
+
+// callerLocation returns the file and line of the first frame starting at the given depth that
+// does not belong to one of the DSL packages.
+func callerLocation(depth int) (file string, line int) {
 	skipFunc := func(file string) bool {
 		if strings.HasSuffix(file, "_test.go") { // Be nice with tests
 			return false
@@ -262,25 +312,11 @@ func computeErrorLocation() (file string, line int) {
 		}
 		return false
 	}
-	depth := 2
 	_, file, line, _ = runtime.Caller(depth)
 	for skipFunc(file) {
 		depth++
 		_, file, line, _ = runtime.Caller(depth)
 	}
-	wd, err := os.Getwd()
-	if err != nil {
-		return
-	}
-	wd, err = filepath.Abs(wd)
-	if err != nil {
-		return
-	}
-	f, err := filepath.Rel(wd, file)
-	if err != nil {
-		return
-	}
-	file = f
 	return
 }
 
@@ -309,9 +345,11 @@ func validateSet(set DefinitionSet) error {
 	errors := &ValidationErrors{}
 	for _, def := range set {
 		if validate, ok := def.(Validate); ok {
-			if err := validate.Validate(); err != nil {
-				errors.AddError(def, err)
-			}
+			traced("validate", def, func() {
+				if err := validate.Validate(); err != nil {
+					errors.AddError(def, err)
+				}
+			})
 		}
 	}
 	err := errors.AsError()
@@ -325,9 +363,12 @@ func validateSet(set DefinitionSet) error {
 func finalizeSet(set DefinitionSet) error {
 	for _, def := range set {
 		if finalize, ok := def.(Finalize); ok {
-			finalize.Finalize()
+			traced("finalize", def, finalize.Finalize)
 		}
 	}
+	if CheckMutations {
+		freeze(set)
+	}
 	return nil
 }
 
@@ -413,6 +454,12 @@ func sortDependenciesR(root Root, seen map[string]bool, sorted *[]Root, depFunc
 	*sorted = append(*sorted, root)
 }
 
+// callerName returns the unqualified name of the function with the given qualified name.
+func callerName(fn string) string {
+	elems := strings.Split(fn, ".")
+	return elems[len(elems)-1]
+}
+
 // caller returns the name of calling function.
 func caller() string {
 	pc, file, _, ok := runtime.Caller(2)
@@ -0,0 +1,89 @@
+package dslengine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trace is the writer the DSL engine writes execution traces to, tracing is disabled when nil.
+// When tracing is enabled the engine writes one line per definition as its DSL, validation and
+// finalization passes run with the time the pass took. DSL lines are indented according to the
+// nesting of the definitions and also list the depth of the definition tree built by the DSL.
+// Run writes a summary listing the slowest definitions once done.
+var Trace io.Writer
+
+// TraceSummarySize is the number of definitions listed in the trace summary written by Run.
+var TraceSummarySize = 10
+
+type (
+	// traceRecord records the execution of one pass on one definition.
+	traceRecord struct {
+		pass     string
+		def      string
+		duration time.Duration
+	}
+
+	// byDuration sorts trace records by decreasing duration.
+	byDuration []*traceRecord
+)
+
+var (
+	// traceRecords lists the passes executed since Run started.
+	traceRecords []*traceRecord
+
+	// traceMaxDepth is the maximum definition nesting depth reached while tracing a DSL.
+	traceMaxDepth int
+)
+
+// traced runs fn which executes the given pass on def and traces its execution if tracing is
+// enabled.
+func traced(pass string, def Definition, fn func()) {
+	if Trace == nil {
+		fn()
+		return
+	}
+	level := len(ctxStack)
+	prevMax := traceMaxDepth
+	traceMaxDepth = level
+	start := time.Now()
+	fn()
+	d := time.Since(start)
+	ctx := def.Context()
+	traceRecords = append(traceRecords, &traceRecord{pass: pass, def: ctx, duration: d})
+	if pass == "dsl" {
+		indent := ""
+		if level > 1 {
+			indent = strings.Repeat("  ", level-1)
+		}
+		fmt.Fprintf(Trace, "%s%-8s %s %s (depth %d)\n", indent, pass, ctx, d, traceMaxDepth-level+1)
+	} else {
+		fmt.Fprintf(Trace, "%-8s %s %s\n", pass, ctx, d)
+	}
+	if prevMax > traceMaxDepth {
+		traceMaxDepth = prevMax
+	}
+}
+
+// traceSummary writes the slowest traced passes.
+func traceSummary() {
+	if Trace == nil || len(traceRecords) == 0 {
+		return
+	}
+	recs := make([]*traceRecord, len(traceRecords))
+	copy(recs, traceRecords)
+	sort.Stable(byDuration(recs))
+	if len(recs) > TraceSummarySize {
+		recs = recs[:TraceSummarySize]
+	}
+	fmt.Fprintf(Trace, "slowest definitions (%d passes traced):\n", len(traceRecords))
+	for _, r := range recs {
+		fmt.Fprintf(Trace, "  %-8s %s %s\n", r.pass, r.def, r.duration)
+	}
+}
+
+func (b byDuration) Len() int           { return len(b) }
+func (b byDuration) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byDuration) Less(i, j int) bool { return b[i].duration > b[j].duration }
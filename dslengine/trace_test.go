@@ -0,0 +1,58 @@
+package dslengine_test
+
+import (
+	"bytes"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trace", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		buf = new(bytes.Buffer)
+		dslengine.Trace = buf
+		API("foo", nil)
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+			})
+		})
+	})
+
+	AfterEach(func() {
+		dslengine.Trace = nil
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	It("traces the passes", func() {
+		out := buf.String()
+		Ω(out).Should(MatchRegexp(`(?m)^  dsl +resource "bottle" action "show" .* \(depth 1\)$`))
+		Ω(out).Should(MatchRegexp(`(?m)^dsl +resource "bottle" .* \(depth 2\)$`))
+		Ω(out).Should(ContainSubstring(`validate API "foo"`))
+		Ω(out).Should(ContainSubstring(`finalize resource "bottle"`))
+	})
+
+	It("writes a summary", func() {
+		Ω(buf.String()).Should(ContainSubstring("slowest definitions"))
+	})
+
+	Context("when disabled", func() {
+		BeforeEach(func() {
+			dslengine.Trace = nil
+		})
+
+		It("does not trace", func() {
+			Ω(buf.Len()).Should(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+package dslengine
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// VarsEnv is the name of the environment variable that lists the values of the design variables
+// given at generation time as a comma separated list of name=value pairs. goagen sets it from its
+// --vars flag when running the generators.
+const VarsEnv = "GOAGEN_VARS"
+
+// VarOverrides maps the names of design variables to the values given at generation time. It is
+// initialized from the VarsEnv environment variable before the design packages are initialized.
+var VarOverrides map[string]string
+
+// declaredVars maps the names of the declared design variables and constants to whether they may
+// be overridden.
+var declaredVars = make(map[string]bool)
+
+func init() {
+	var err error
+	if VarOverrides, err = ParseVars(os.Getenv(VarsEnv)); err != nil {
+		Errors = append(Errors, &Error{GoError: fmt.Errorf("invalid %s: %s", VarsEnv, err)})
+	}
+}
+
+// ParseVars returns the design variable values listed in the given comma separated list of
+// name=value pairs.
+func ParseVars(list string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		elems := strings.SplitN(pair, "=", 2)
+		name := strings.TrimSpace(elems[0])
+		if len(elems) != 2 || name == "" {
+			return nil, fmt.Errorf("%q is not of the form name=value", pair)
+		}
+		if _, ok := vars[name]; ok {
+			return nil, fmt.Errorf("value of %s given twice", name)
+		}
+		vars[name] = strings.TrimSpace(elems[1])
+	}
+	return vars, nil
+}
+
+// DeclareVar records the declaration of the design variable or constant with the given name. It
+// returns the value given at generation time for variables if any. DeclareVar reports an error if
+// the name is already declared or if a value is given for a constant.
+func DeclareVar(name string, overridable bool) (string, bool) {
+	if _, ok := declaredVars[name]; ok {
+		ReportError("design variable or constant %s already declared", name)
+		return "", false
+	}
+	declaredVars[name] = overridable
+	val, ok := VarOverrides[name]
+	if ok && !overridable {
+		ReportError("design constant %s cannot be given a value at generation time", name)
+		return "", false
+	}
+	return val, ok
+}
+
+// UndeclareVars forgets about all the declared design variables and constants. It is intended for
+// tests that declare the same variables multiple times.
+func UndeclareVars() {
+	declaredVars = make(map[string]bool)
+}
+
+// checkVars records an error for each value given at generation time to an undeclared design
+// variable.
+func checkVars() {
+	var names []string
+	for name := range VarOverrides {
+		if _, ok := declaredVars[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		Errors = append(Errors, &Error{GoError: fmt.Errorf("unknown design variable %s", name)})
+	}
+}
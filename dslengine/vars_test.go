@@ -0,0 +1,24 @@
+package dslengine_test
+
+import (
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseVars", func() {
+	It("parses comma separated lists of name=value pairs", func() {
+		Ω(dslengine.ParseVars("")).Should(BeEmpty())
+		Ω(dslengine.ParseVars(" host = example.com,,base_path=/v1=2 ")).Should(Equal(map[string]string{
+			"host":      "example.com",
+			"base_path": "/v1=2",
+		}))
+	})
+
+	It("rejects invalid pairs", func() {
+		_, err := dslengine.ParseVars("host")
+		Ω(err).Should(MatchError(`"host" is not of the form name=value`))
+		_, err = dslengine.ParseVars("host=a,host=b")
+		Ω(err).Should(MatchError("value of host given twice"))
+	})
+})
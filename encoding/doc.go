@@ -29,5 +29,21 @@ The DSL above causes the generated code to use the JSON encoder implemented in t
 github.com/goadesign/goa/encoding/json rather than the stdlib JSON encoder. Third party encoders
 can easily be used via adapter packages that expose the NewDecoder and NewEcoder methods expected
 by the generated code, see the json package as an example.
+
+The encoders and decoders can also be swapped without changing the design: the generated app
+package exposes the Encoders and Decoders maps that the controllers register indexed by content
+type. Replace an entry before mounting the controllers to use another implementation, for example
+jsoniter or sonic for JSON:
+
+	app.Encoders["application/json"] = func(w io.Writer) goa.Encoder {
+		return jsoniter.ConfigCompatibleWithStandardLibrary.NewEncoder(w)
+	}
+	app.Decoders["application/json"] = func(r io.Reader) goa.Decoder {
+		return sonic.ConfigStd.NewDecoder(r)
+	}
+	app.MountBottleController(service, NewBottleController(service))
+
+The benchmarks of the json package compare the performance of the standard library and codec
+based JSON encoders and decoders.
 */
 package encoding
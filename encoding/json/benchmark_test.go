@@ -0,0 +1,76 @@
+package json_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/encoding/json"
+)
+
+// The benchmarks below compare the encoders and decoders that can be registered for the
+// "application/json" content type: the standard library adapters exposed by the goa package and
+// the codec based adapters exposed by this package. Run them with:
+//
+//	go test -run NONE -bench . github.com/goadesign/goa/encoding/json
+
+type benchBottle struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name"`
+	Vintage  int               `json:"vintage"`
+	Rating   float64           `json:"rating"`
+	Varietal []string          `json:"varietal"`
+	Tags     map[string]string `json:"tags"`
+}
+
+var benchBottles = func() []*benchBottle {
+	bottles := make([]*benchBottle, 100)
+	for i := range bottles {
+		bottles[i] = &benchBottle{
+			ID:       i,
+			Name:     "Number 8",
+			Vintage:  2012,
+			Rating:   4.5,
+			Varietal: []string{"merlot", "cabernet"},
+			Tags:     map[string]string{"region": "napa", "color": "red"},
+		}
+	}
+	return bottles
+}()
+
+func benchmarkEncode(b *testing.B, fn goa.EncoderFunc) {
+	var buf bytes.Buffer
+	enc := goa.NewHTTPEncoder()
+	enc.Register(fn, "application/json")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := enc.Encode(benchBottles, &buf, "application/json"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecode(b *testing.B, fn goa.DecoderFunc) {
+	var buf bytes.Buffer
+	if err := goa.NewJSONEncoder(&buf).Encode(benchBottles); err != nil {
+		b.Fatal(err)
+	}
+	body := buf.Bytes()
+	dec := goa.NewHTTPDecoder()
+	dec.Register(fn, "application/json")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var bottles []*benchBottle
+		if err := dec.Decode(&bottles, bytes.NewReader(body), "application/json"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodeStdlib(b *testing.B) { benchmarkEncode(b, goa.NewJSONEncoder) }
+func BenchmarkEncodeCodec(b *testing.B)  { benchmarkEncode(b, json.NewEncoder) }
+func BenchmarkDecodeStdlib(b *testing.B) { benchmarkDecode(b, goa.NewJSONDecoder) }
+func BenchmarkDecodeCodec(b *testing.B)  { benchmarkDecode(b, json.NewDecoder) }
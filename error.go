@@ -47,6 +47,10 @@ var (
 	// ErrUnauthorized is a generic unauthorized error.
 	ErrUnauthorized = NewErrorClass("unauthorized", 401)
 
+	// ErrForbidden is the error produced when the authenticated principal lacks the scopes or
+	// roles required by the action.
+	ErrForbidden = NewErrorClass("forbidden", 403)
+
 	// ErrInvalidRequest is the class of errors produced by the generated code when a request
 	// parameter or payload fails to validate.
 	ErrInvalidRequest = NewErrorClass("invalid_request", 400)
@@ -58,6 +62,10 @@ var (
 	// MaxRequestBodyLength bytes.
 	ErrRequestBodyTooLarge = NewErrorClass("request_too_large", 413)
 
+	// ErrUnsupportedEncoding is the error produced when a request body is compressed with an
+	// encoding the service does not support.
+	ErrUnsupportedEncoding = NewErrorClass("unsupported_encoding", 415)
+
 	// ErrNoAuthMiddleware is the error produced when no auth middleware is mounted for a
 	// security scheme defined in the design.
 	ErrNoAuthMiddleware = NewErrorClass("no_auth_middleware", 500)
@@ -71,6 +79,10 @@ var (
 
 	// ErrInternal is the class of error used for uncaught errors.
 	ErrInternal = NewErrorClass("internal", 500)
+
+	// ErrServiceUnavailable is the error produced when a request is rejected because the
+	// service is overloaded.
+	ErrServiceUnavailable = NewErrorClass("service_unavailable", 503)
 )
 
 type (
@@ -171,6 +183,13 @@ func MissingAttributeError(ctx, name string) error {
 	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
 }
 
+// UnknownAttributeError is the error produced when a request payload contains an attribute that
+// is not defined in the design and the design disallows unknown fields.
+func UnknownAttributeError(ctx, name string) error {
+	msg := fmt.Sprintf("attribute %#v of %s is not defined in the design", name, ctx)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
+}
+
 // MissingHeaderError is the error produced when a request is missing a required header.
 func MissingHeaderError(name string) error {
 	msg := fmt.Sprintf("missing required HTTP header %#v", name)
@@ -224,6 +243,13 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
 }
 
+// InvalidScalarError is the error produced when the value of a parameter or payload field cannot
+// be parsed into the custom scalar defined in the design or fails the scalar validation.
+func InvalidScalarError(ctx string, target interface{}, scalar string, scalarError error) error {
+	msg := fmt.Sprintf("%s must be a valid %s but got value %#v, %s", ctx, scalar, target, scalarError.Error())
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "expected", scalar, "error", scalarError.Error())
+}
+
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
@@ -228,6 +228,16 @@ var _ = Describe("InvalidRangeError", func() {
 	})
 })
 
+var _ = Describe("InvalidScalarError", func() {
+	It("creates a http error", func() {
+		valErr := InvalidScalarError("ctx", "1.2.3", "Decimal", errors.New("can't convert 1.2.3 to decimal"))
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring("ctx must be a valid Decimal"))
+		Ω(err.Detail).Should(ContainSubstring("can't convert"))
+	})
+})
+
 var _ = Describe("InvalidLengthError", func() {
 	const ctx = "ctx"
 	const value = 42
@@ -0,0 +1,67 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// jsonZeros returns the Go literal of the map given to goa.EmitJSONZeros for the optional child
+// attributes of att if att uses the design.EmitZero absent policy, an empty string otherwise.
+func jsonZeros(att *design.AttributeDefinition) string {
+	obj := att.Type.ToObject()
+	if obj == nil || att.AbsentPolicy() != design.EmitZero {
+		return ""
+	}
+	var zeros []string
+	obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		if att.IsRequired(n) || att.HasDefaultValue(n) {
+			return nil
+		}
+		zero := jsonZero(catt.Type)
+		if zero == "" {
+			return nil
+		}
+		name := n
+		if v, ok := catt.Metadata["struct:tag:json"]; ok && len(v) > 0 && v[0] != "" {
+			name = v[0]
+		}
+		zeros = append(zeros, fmt.Sprintf("%q: %q", name, zero))
+		return nil
+	})
+	if len(zeros) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("map[string]string{%s}", strings.Join(zeros, ", "))
+}
+
+// jsonZero returns the JSON encoding of the zero value of the given type, an empty string for the
+// Any type that has no zero value other than null.
+func jsonZero(t design.DataType) string {
+	switch actual := t.(type) {
+	case *design.UserTypeDefinition:
+		return jsonZero(actual.Type)
+	case *design.MediaTypeDefinition:
+		return jsonZero(actual.Type)
+	}
+	switch {
+	case t.IsArray():
+		return "[]"
+	case t.IsObject(), t.IsHash():
+		return "{}"
+	}
+	switch t.Kind() {
+	case design.BooleanKind:
+		return "false"
+	case design.IntegerKind, design.NumberKind:
+		return "0"
+	case design.StringKind:
+		return `""`
+	case design.DateTimeKind:
+		return `"0001-01-01T00:00:00Z"`
+	case design.UUIDKind:
+		return `"00000000-0000-0000-0000-000000000000"`
+	}
+	return ""
+}
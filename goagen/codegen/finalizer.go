@@ -11,6 +11,7 @@ import (
 
 var (
 	assignmentT      *template.Template
+	funcAssignmentT  *template.Template
 	arrayAssignmentT *template.Template
 )
 
@@ -27,6 +28,9 @@ func init() {
 	if assignmentT, err = template.New("assignment").Funcs(fm).Parse(assignmentTmpl); err != nil {
 		panic(err)
 	}
+	if funcAssignmentT, err = template.New("funcAssignment").Funcs(fm).Parse(funcAssignmentTmpl); err != nil {
+		panic(err)
+	}
 	if arrayAssignmentT, err = template.New("arrAssignment").Funcs(fm).Parse(arrayAssignmentTmpl); err != nil {
 		panic(err)
 	}
@@ -62,9 +66,18 @@ func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth in
 					"catt":       catt,
 					"depth":      depth,
 					"isDatetime": catt.Type == design.DateTime,
-					"defaultVal": printVal(catt.Type, catt.DefaultValue),
+					"defaultVal": PrintVal(catt.Type, catt.DefaultValue),
 				}
 				assignments = append(assignments, RunTemplate(assignmentT, data))
+			} else if f := catt.DefaultFunc(); f != "" && !att.IsRequired(n) {
+				data := map[string]interface{}{
+					"target":   target,
+					"field":    n,
+					"depth":    depth,
+					"provider": f,
+					"type":     GoNativeType(catt.Type),
+				}
+				assignments = append(assignments, RunTemplate(funcAssignmentT, data))
 			}
 			assignment := RecursiveFinalizer(
 				catt,
@@ -95,9 +108,13 @@ func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth in
 	return strings.Join(assignments, "\n")
 }
 
-// printVal prints the given value corresponding to the given data type.
+// PrintVal returns the Go literal of the given value corresponding to the given data type.
 // The value is already checked for the compatibility with the data type.
-func printVal(t design.DataType, val interface{}) string {
+func PrintVal(t design.DataType, val interface{}) string {
+	if f, ok := t.(*design.FlagsDefinition); ok {
+		// Flags values hold flag names
+		return PrintVal(f.Array, val)
+	}
 	switch {
 	case t.IsPrimitive():
 		// For primitive types, simply print the value
@@ -116,7 +133,7 @@ func printVal(t design.DataType, val interface{}) string {
 		var buffer bytes.Buffer
 		buffer.WriteString(fmt.Sprintf("%s{", GoTypeName(t, nil, 0, false)))
 		for k, v := range hval {
-			buffer.WriteString(fmt.Sprintf("%s: %s, ", printVal(h.KeyType.Type, k), printVal(h.ElemType.Type, v)))
+			buffer.WriteString(fmt.Sprintf("%s: %s, ", PrintVal(h.KeyType.Type, k), PrintVal(h.ElemType.Type, v)))
 		}
 		buffer.Truncate(buffer.Len() - 2) // remove ", "
 		buffer.WriteString("}")
@@ -131,7 +148,7 @@ func printVal(t design.DataType, val interface{}) string {
 		var buffer bytes.Buffer
 		buffer.WriteString(fmt.Sprintf("%s{", GoTypeName(t, nil, 0, false)))
 		for _, e := range aval {
-			buffer.WriteString(fmt.Sprintf("%s, ", printVal(a.ElemType.Type, e)))
+			buffer.WriteString(fmt.Sprintf("%s, ", PrintVal(a.ElemType.Type, e)))
 		}
 		buffer.Truncate(buffer.Len() - 2) // remove ", "
 		buffer.WriteString("}")
@@ -151,6 +168,12 @@ const (
 {{ tabs .depth }}	{{ .target }}.{{ goify .field true }} = {{ .defaultVal }}
 }{{ end }}`
 
+	funcAssignmentTmpl = `{{ tabs .depth }}if {{ .target }}.{{ goify .field true }} == nil {
+{{ tabs .depth }}	if v, ok := goa.DefaultValue({{ printf "%q" .provider }}).({{ .type }}); ok {
+{{ tabs .depth }}		{{ .target }}.{{ goify .field true }} = &v
+{{ tabs .depth }}	}
+{{ tabs .depth }}}`
+
 	arrayAssignmentTmpl = `{{ $assignment := recursiveFinalizer .elemType "e" (add .depth 1) }}{{/*
 */}}{{ if $assignment }}{{ tabs .depth }}for _, e := range {{ .target }} {
 {{ $assignment }}
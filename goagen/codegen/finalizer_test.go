@@ -2,6 +2,7 @@ package codegen_test
 
 import (
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -49,6 +50,23 @@ var _ = Describe("Struct finalize code generation", func() {
 				Ω(assignments).Should(Equal(arrayAssignmentCode))
 			})
 		})
+		Context("given a field with a default value provider", func() {
+			BeforeEach(func() {
+				att = &design.AttributeDefinition{
+					Type: &design.Object{
+						"foo": &design.AttributeDefinition{
+							Type:     design.UUID,
+							Metadata: dslengine.MetadataDefinition{design.DefaultFuncMetadata: []string{"uuid"}},
+						},
+					},
+				}
+				target = "ut"
+			})
+			It("calls the provider", func() {
+				assignments := codegen.RecursiveFinalizer(att, target, 0)
+				Ω(assignments).Should(Equal(funcAssignmentCode))
+			})
+		})
 		Context("given a hash field", func() {
 			BeforeEach(func() {
 				att = &design.AttributeDefinition{
@@ -104,6 +122,12 @@ if ut.Foo == nil {
 	ut.Foo = []string{"bar", "baz"}
 }`
 
+	funcAssignmentCode = `if ut.Foo == nil {
+	if v, ok := goa.DefaultValue("uuid").(uuid.UUID); ok {
+		ut.Foo = &v
+	}
+}`
+
 	hashAssignmentCode = `if ut.Foo == nil {
 	ut.Foo = map[string]string{"bar": "baz"}
 }`
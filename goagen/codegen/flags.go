@@ -0,0 +1,57 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// IsFlags returns true if the given type is a flags type.
+func IsFlags(t design.DataType) bool {
+	_, ok := t.(*design.FlagsDefinition)
+	return ok
+}
+
+// FlagConst returns the name of the Go constant generated for the flag with the given name, e.g.
+// "PermissionsRead".
+func FlagConst(f *design.FlagsDefinition, name string) string {
+	return f.GoType + Goify(name, true)
+}
+
+// FlagsNamesVar returns the name of the generated variable listing the flag names of f.
+func FlagsNamesVar(f *design.FlagsDefinition) string {
+	return Goify(f.GoType, false) + "Names"
+}
+
+// FlagsVal returns the Go expression that combines the flags whose names are listed in val, e.g.
+// "PermissionsRead | PermissionsWrite".
+func FlagsVal(f *design.FlagsDefinition, val interface{}) string {
+	names, _ := val.([]interface{})
+	if len(names) == 0 {
+		return f.GoType + "(0)"
+	}
+	consts := make([]string, len(names))
+	for i, n := range names {
+		consts[i] = FlagConst(f, fmt.Sprintf("%v", n))
+	}
+	return strings.Join(consts, " | ")
+}
+
+// flagsPublicizer produces the code that parses the flag names held by the private struct field
+// source into the public struct field target. The names are validated prior to publicizing so the
+// parse error is ignored.
+func flagsPublicizer(f *design.FlagsDefinition, source, target string, depth int, init bool) string {
+	assign := "="
+	if init {
+		assign = ":="
+	}
+	return fmt.Sprintf("%s%s, _ %s Parse%s(%s)", Tabs(depth), target, assign, f.GoType, source)
+}
+
+// flagsChecker produces the code that makes sure the names held by the private struct field
+// target are flag names.
+func flagsChecker(f *design.FlagsDefinition, target, context string, depth int) string {
+	check := ValidationChecker(f.ElemType, false, true, false, "e", context+"[*]", depth+1, false)
+	return fmt.Sprintf("%sfor _, e := range %s {\n%s\n%s}", Tabs(depth), target, check, Tabs(depth))
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -129,9 +131,26 @@ func CanonicalParams(r *design.ResourceDefinition) []string {
 	return params
 }
 
+// TLSVersion returns the name of the crypto/tls package constant that corresponds to the given
+// TLS version, e.g. "tls.VersionTLS12" for "1.2".
+func TLSVersion(version string) string {
+	return "tls.VersionTLS" + strings.Replace(version, ".", "", -1)
+}
+
 // Casing exceptions
 var toLower = map[string]string{"OAuth": "oauth"}
 
+var (
+	// identRegex matches the characters that may not appear in identifiers.
+	identRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	// camelRegex matches the lower case letters or digits followed by an upper case letter.
+	camelRegex = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	// acronymRegex matches the end of acronyms followed by a capitalized word.
+	acronymRegex = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	// underscoresRegex matches consecutive underscores.
+	underscoresRegex = regexp.MustCompile(`_+`)
+)
+
 // SnakeCase produces the snake_case version of the given CamelCase string.
 func SnakeCase(name string) string {
 	for u, l := range toLower {
@@ -164,3 +183,63 @@ func SnakeCase(name string) string {
 	}
 	return b.String()
 }
+
+// SnakeIdentifier returns the lower snake case identifier built from the given name. Contrary to
+// SnakeCase it also splits acronyms followed by a capitalized word, replaces the characters that
+// may not appear in identifiers with underscores and trims the leading and trailing underscores,
+// e.g. "HTTPServer-name" becomes "http_server_name". The result may be empty or start with a
+// digit.
+func SnakeIdentifier(name string) string {
+	name = camelRegex.ReplaceAllString(name, "${1}_${2}")
+	name = acronymRegex.ReplaceAllString(name, "${1}_${2}")
+	name = strings.ToLower(identRegex.ReplaceAllString(name, "_"))
+	return strings.Trim(underscoresRegex.ReplaceAllString(name, "_"), "_")
+}
+
+// CamelIdentifier returns the camel case identifier built from the words of SnakeIdentifier, the
+// first letter is upper case if upper is true. The identifier is prefixed with an underscore if it
+// would be empty or start with a digit otherwise.
+func CamelIdentifier(name string, upper bool) string {
+	words := strings.Split(SnakeIdentifier(name), "_")
+	for i, w := range words {
+		if w != "" && (i > 0 || upper) {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	res := strings.Join(words, "")
+	if res == "" || res[0] >= '0' && res[0] <= '9' {
+		res = "_" + res
+	}
+	return res
+}
+
+// SortedAttributeNames returns the names of the attributes of the given object sorted
+// alphabetically.
+func SortedAttributeNames(o design.Object) []string {
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SortedUserTypeNames returns the names of the given user types sorted alphabetically.
+func SortedUserTypeNames(types map[string]*design.UserTypeDefinition) []string {
+	names := make([]string, 0, len(types))
+	for n := range types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SortedMediaTypeNames returns the identifiers of the given media types sorted alphabetically.
+func SortedMediaTypeNames(types map[string]*design.MediaTypeDefinition) []string {
+	names := make([]string, 0, len(types))
+	for n := range types {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SnakeIdentifier", func() {
+	It("splits the camel case words and acronyms", func() {
+		Ω(codegen.SnakeIdentifier("HTTPServer")).Should(Equal("http_server"))
+		Ω(codegen.SnakeIdentifier("bottleID")).Should(Equal("bottle_id"))
+	})
+
+	It("replaces the characters that may not appear in identifiers", func() {
+		Ω(codegen.SnakeIdentifier("-x-request--id-")).Should(Equal("x_request_id"))
+	})
+})
+
+var _ = Describe("CamelIdentifier", func() {
+	It("joins the words", func() {
+		Ω(codegen.CamelIdentifier("x-request-id", false)).Should(Equal("xRequestId"))
+		Ω(codegen.CamelIdentifier("x-request-id", true)).Should(Equal("XRequestId"))
+	})
+
+	It("prefixes the identifiers that would start with a digit", func() {
+		Ω(codegen.CamelIdentifier("2fa", false)).Should(Equal("_2fa"))
+		Ω(codegen.CamelIdentifier("--", false)).Should(Equal("_"))
+	})
+})
+
+var _ = Describe("SortedAttributeNames", func() {
+	It("sorts the names alphabetically", func() {
+		o := design.Object{"b": &design.AttributeDefinition{}, "a": &design.AttributeDefinition{}}
+		Ω(codegen.SortedAttributeNames(o)).Should(Equal([]string{"a", "b"}))
+	})
+})
@@ -53,14 +53,36 @@ func RecursivePublicizer(att *design.AttributeDefinition, source, target string,
 			att = ds.Definition()
 		}
 		o.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
-			publication := Publicizer(
-				catt,
-				fmt.Sprintf("%s.%s", source, Goify(n, true)),
-				fmt.Sprintf("%s.%s", target, Goify(n, true)),
-				catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n),
-				depth+1,
-				false,
-			)
+			var publication string
+			if s, ok := catt.Type.(*design.ScalarDefinition); ok {
+				publication = scalarPublicizer(s,
+					fmt.Sprintf("%s.%s", source, Goify(n, true)),
+					fmt.Sprintf("%s.%s", target, Goify(n, true)),
+					att.IsPrimitivePointer(n),
+					depth+1,
+				)
+				if att.HasPresence(n) {
+					publication += fmt.Sprintf("\n%s%s.presence.Set(%d)",
+						Tabs(depth+1), target, att.PresenceIndex(n))
+				}
+			} else if att.IsNullable(n) {
+				wrapper, f := NullWrapper(catt.Type)
+				publication = fmt.Sprintf("%s%s.%s = %s{%s: *%s.%s, Valid: true}",
+					Tabs(depth+1), target, Goify(n, true), wrapper, f, source, Goify(n, true))
+			} else {
+				publication = Publicizer(
+					catt,
+					fmt.Sprintf("%s.%s", source, Goify(n, true)),
+					fmt.Sprintf("%s.%s", target, Goify(n, true)),
+					catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n),
+					depth+1,
+					false,
+				)
+				if att.HasPresence(n) {
+					publication += fmt.Sprintf("\n%s%s.presence.Set(%d)",
+						Tabs(depth+1), target, att.PresenceIndex(n))
+				}
+			}
 			publication = fmt.Sprintf("%sif %s.%s != nil {\n%s\n%s}",
 				Tabs(depth), source, Goify(n, true), publication, Tabs(depth))
 			publications = append(publications, publication)
@@ -82,6 +104,15 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 		"init":        init,
 	}
 	switch {
+	case IsScalar(att.Type):
+		assign := "="
+		if init {
+			assign = ":="
+		}
+		publication = fmt.Sprintf("%s%s, _ %s %s(%s)",
+			Tabs(depth), targetField, assign, att.Type.(*design.ScalarDefinition).ParseFunc, sourceField)
+	case IsFlags(att.Type):
+		publication = flagsPublicizer(att.Type.(*design.FlagsDefinition), sourceField, targetField, depth, init)
 	case att.Type.IsPrimitive():
 		publication = RunTemplate(simplePublicizeT, data)
 	case att.Type.IsObject():
@@ -94,15 +125,14 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 		}
 	case att.Type.IsArray():
 		// If the array element is primitive type, we can simply copy the elements over (i.e) []string
-		if att.Type.HasAttributes() {
+		if att.Type.HasAttributes() || IsScalar(att.Type.ToArray().ElemType.Type) {
 			data["elemType"] = att.Type.ToArray().ElemType
 			publication = RunTemplate(arrayPublicizeT, data)
 		} else {
 			publication = RunTemplate(simplePublicizeT, data)
 		}
 	case att.Type.IsHash():
-		if att.Type.HasAttributes() {
-			h := att.Type.ToHash()
+		if h := att.Type.ToHash(); att.Type.HasAttributes() || IsScalar(h.KeyType.Type) || IsScalar(h.ElemType.Type) {
 			data["keyType"] = h.KeyType
 			data["elemType"] = h.ElemType
 			publication = RunTemplate(hashPublicizeT, data)
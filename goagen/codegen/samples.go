@@ -0,0 +1,215 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Sample is a ready-to-run command line that calls an action.
+type Sample struct {
+	// Lang is the language of the sample as expected by the "x-code-samples" OpenAPI extension.
+	Lang string `json:"lang"`
+	// Label is the name of the tool used by the sample.
+	Label string `json:"label"`
+	// Source is the sample command line.
+	Source string `json:"source"`
+}
+
+// sampleRequest holds the elements of the request made by a sample.
+type sampleRequest struct {
+	verb     string
+	url      string   // shell quoted URL
+	headers  []string // required header names and example values
+	body     string   // JSON encoded example payload, empty if none
+	cookie   string   // session cookie name if any
+	basic    bool     // whether the credentials are basic auth credentials
+	header   string   // name of the header holding the credentials if not basic or session
+	prefix   string   // prefix of the credentials in the header
+	envValue string   // environment variable placeholder holding the credentials
+}
+
+// ActionSamples returns the curl and HTTPie command lines that call the action of the given route
+// on the API served at baseURL. The commands use the examples of the path params, of the required
+// query params and headers and of the payload. The credentials are read from the environment
+// variable named after the action security scheme, see SampleEnvVar. ActionSamples returns nil for
+// WebSocket actions.
+func ActionSamples(route *design.RouteDefinition, baseURL string) ([]*Sample, error) {
+	action := route.Parent
+	if action.WebSocket() {
+		return nil, nil
+	}
+	r, err := newSampleRequest(route, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return []*Sample{
+		{Lang: "Shell", Label: "curl", Source: r.curl()},
+		{Lang: "Shell", Label: "HTTPie", Source: r.httpie()},
+	}, nil
+}
+
+// SampleEnvVar returns the name of the environment variable holding the credentials for the
+// given security scheme in the samples.
+func SampleEnvVar(scheme *design.SecuritySchemeDefinition) string {
+	name := strings.ToUpper(SnakeCase(Goify(scheme.SchemeName, true)))
+	switch scheme.Kind {
+	case design.BasicAuthSecurityKind:
+		return name + "_CREDENTIALS"
+	case design.APIKeySecurityKind:
+		return name + "_KEY"
+	case design.SessionSecurityKind:
+		return name + "_SESSION"
+	default:
+		return name + "_TOKEN"
+	}
+}
+
+// ShellQuote returns the given string quoted for POSIX shells.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// newSampleRequest computes the request made by the samples of the given route. The examples
+// are generated with a random generator seeded with the resource and action names so that all
+// the samples of an action agree.
+func newSampleRequest(route *design.RouteDefinition, baseURL string) (*sampleRequest, error) {
+	action := route.Parent
+	rand := design.NewRandomGenerator(action.Parent.Name + "#" + action.Name)
+	example := func(att *design.AttributeDefinition) string {
+		switch ex := att.GenerateExample(rand, nil).(type) {
+		case nil:
+			return ""
+		case []interface{}:
+			if len(ex) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%v", ex[0])
+		default:
+			return fmt.Sprintf("%v", ex)
+		}
+	}
+
+	params := action.AllParams()
+	path := design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(wc string) string {
+		if params != nil {
+			if att, ok := params.Type.ToObject()[wc[2:]]; ok {
+				return "/" + url.PathEscape(example(att))
+			}
+		}
+		return "/" + wc[2:]
+	})
+	values := url.Values{}
+	if q := action.QueryParams; q != nil {
+		o := q.Type.ToObject()
+		for _, n := range SortedAttributeNames(o) {
+			if q.IsRequired(n) {
+				values.Set(n, example(o[n]))
+			}
+		}
+	}
+
+	r := &sampleRequest{verb: route.Verb}
+	var keyParam string
+	if sec := action.Security; sec != nil && sec.Scheme.Kind != design.NoSecurityKind {
+		scheme := sec.Scheme
+		r.envValue = "$" + SampleEnvVar(scheme)
+		switch scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			r.basic = true
+		case design.APIKeySecurityKind:
+			if scheme.In == "query" {
+				keyParam = scheme.Name
+			} else {
+				r.header = scheme.Name
+			}
+		case design.SessionSecurityKind:
+			r.cookie = scheme.CookieName
+		default:
+			r.header, r.prefix = "Authorization", "Bearer "
+		}
+	}
+	if h := action.Headers; h != nil {
+		o := h.Type.ToObject()
+		for _, n := range SortedAttributeNames(o) {
+			if h.IsRequired(n) {
+				r.headers = append(r.headers, n, example(o[n]))
+			}
+		}
+	}
+
+	u := strings.TrimSuffix(baseURL, "/") + path
+	query := values.Encode()
+	if keyParam != "" {
+		if query != "" {
+			query += "&"
+		}
+		query += url.QueryEscape(keyParam) + "="
+	}
+	if query != "" {
+		u += "?" + query
+	}
+	r.url = ShellQuote(u)
+	if keyParam != "" {
+		r.url += `"` + r.envValue + `"`
+		r.envValue = ""
+	}
+
+	if action.Payload != nil {
+		ex := action.Payload.GenerateExample(rand, nil)
+		if ex != nil && ex != "-" {
+			b, err := json.Marshal(ex)
+			if err != nil {
+				return nil, err
+			}
+			r.body = string(b)
+		}
+	}
+	return r, nil
+}
+
+// curl returns the curl command line making the request.
+func (r *sampleRequest) curl() string {
+	lines := []string{"curl -X " + r.verb + " " + r.url}
+	switch {
+	case r.envValue == "":
+	case r.basic:
+		lines = append(lines, `-u "`+r.envValue+`"`)
+	case r.cookie != "":
+		lines = append(lines, `-b "`+r.cookie+"="+r.envValue+`"`)
+	default:
+		lines = append(lines, `-H "`+r.header+": "+r.prefix+r.envValue+`"`)
+	}
+	for i := 0; i < len(r.headers); i += 2 {
+		lines = append(lines, "-H "+ShellQuote(r.headers[i]+": "+r.headers[i+1]))
+	}
+	if r.body != "" {
+		lines = append(lines, "-H 'Content-Type: application/json'", "-d "+ShellQuote(r.body))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
+
+// httpie returns the HTTPie command line making the request. The payload is read from the
+// standard input which HTTPie sends as JSON.
+func (r *sampleRequest) httpie() string {
+	lines := []string{"http " + r.verb + " " + r.url}
+	if r.body != "" {
+		lines[0] = "echo " + ShellQuote(r.body) + " | " + lines[0]
+	}
+	switch {
+	case r.envValue == "":
+	case r.basic:
+		lines = append(lines, `-a "`+r.envValue+`"`)
+	case r.cookie != "":
+		lines = append(lines, `"Cookie:`+r.cookie+"="+r.envValue+`"`)
+	default:
+		lines = append(lines, `"`+r.header+":"+r.prefix+r.envValue+`"`)
+	}
+	for i := 0; i < len(r.headers); i += 2 {
+		lines = append(lines, ShellQuote(r.headers[i]+":"+r.headers[i+1]))
+	}
+	return strings.Join(lines, " \\\n  ")
+}
@@ -0,0 +1,136 @@
+package codegen_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ActionSamples", func() {
+	var action *design.ActionDefinition
+	var samples []*codegen.Sample
+	var err error
+	var api *design.APIDefinition
+
+	BeforeEach(func() {
+		api = design.Design
+		action = &design.ActionDefinition{
+			Name: "list",
+			Params: &design.AttributeDefinition{
+				Type: design.Object{
+					"account": &design.AttributeDefinition{Type: design.String, Example: "acme"},
+					"q":       &design.AttributeDefinition{Type: design.String, Example: "it's"},
+				},
+			},
+			QueryParams: &design.AttributeDefinition{
+				Type: design.Object{
+					"q": &design.AttributeDefinition{Type: design.String, Example: "it's"},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"q"}},
+			},
+			Headers: &design.AttributeDefinition{
+				Type: design.Object{
+					"X-Tenant": &design.AttributeDefinition{Type: design.String, Example: "t1"},
+					"X-Trace":  &design.AttributeDefinition{Type: design.String, Example: "t2"},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"X-Tenant"}},
+			},
+		}
+		action.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "/:account/bottles", Parent: action}}
+		action.Parent = &design.ResourceDefinition{Name: "bottle"}
+		design.Design = &design.APIDefinition{Name: "cellar"}
+	})
+
+	AfterEach(func() {
+		design.Design = api
+	})
+
+	JustBeforeEach(func() {
+		samples, err = codegen.ActionSamples(action.Routes[0], "https://example.com/")
+	})
+
+	Context("with basic auth", func() {
+		BeforeEach(func() {
+			action.Security = &design.SecurityDefinition{Scheme: &design.SecuritySchemeDefinition{
+				Kind:       design.BasicAuthSecurityKind,
+				SchemeName: "admin_basic",
+			}}
+		})
+
+		It("generates the curl and HTTPie samples", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(samples).Should(HaveLen(2))
+			Ω(samples[0].Lang).Should(Equal("Shell"))
+			Ω(samples[0].Label).Should(Equal("curl"))
+			Ω(samples[0].Source).Should(Equal("curl -X GET 'https://example.com/acme/bottles?q=it%27s' \\\n" +
+				"  -u \"$ADMIN_BASIC_CREDENTIALS\" \\\n" +
+				"  -H 'X-Tenant: t1'"))
+			Ω(samples[1].Label).Should(Equal("HTTPie"))
+			Ω(samples[1].Source).Should(Equal("http GET 'https://example.com/acme/bottles?q=it%27s' \\\n" +
+				"  -a \"$ADMIN_BASIC_CREDENTIALS\" \\\n" +
+				"  'X-Tenant:t1'"))
+		})
+	})
+
+	Context("with an API key in the query string", func() {
+		BeforeEach(func() {
+			action.Security = &design.SecurityDefinition{Scheme: &design.SecuritySchemeDefinition{
+				Kind:       design.APIKeySecurityKind,
+				SchemeName: "key",
+				In:         "query",
+				Name:       "k",
+			}}
+		})
+
+		It("appends the key placeholder to the URL", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(samples[0].Source).Should(HavePrefix(`curl -X GET 'https://example.com/acme/bottles?q=it%27s&k='"$KEY_KEY" \`))
+		})
+	})
+
+	Context("with a session cookie", func() {
+		BeforeEach(func() {
+			action.Security = &design.SecurityDefinition{Scheme: &design.SecuritySchemeDefinition{
+				Kind:       design.SessionSecurityKind,
+				SchemeName: "session",
+				CookieName: "sid",
+			}}
+		})
+
+		It("sends the cookie", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(samples[0].Source).Should(ContainSubstring(`-b "sid=$SESSION_SESSION"`))
+			Ω(samples[1].Source).Should(ContainSubstring(`"Cookie:sid=$SESSION_SESSION"`))
+		})
+	})
+
+	Context("with a payload containing a quote", func() {
+		BeforeEach(func() {
+			action.Payload = &design.UserTypeDefinition{
+				TypeName: "Payload",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"name": &design.AttributeDefinition{Type: design.String, Example: "O'Neil"}},
+				},
+			}
+		})
+
+		It("quotes the payload", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(samples[0].Source).Should(HaveSuffix(`-d '{"name":"O'\''Neil"}'`))
+			Ω(samples[1].Source).Should(HavePrefix(`echo '{"name":"O'\''Neil"}' | http GET`))
+		})
+	})
+
+	Context("with a WebSocket action", func() {
+		BeforeEach(func() {
+			action.Schemes = []string{"ws"}
+		})
+
+		It("does not generate samples", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(samples).Should(BeEmpty())
+		})
+	})
+})
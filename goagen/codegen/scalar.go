@@ -0,0 +1,319 @@
+package codegen
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// ScalarImports returns the imports of the packages that define the Go types of the API custom
+// scalars as well as the packages used by the built-in scalars (Time, Decimal etc.) and by the
+// WireMarshalers methods. Generators add them to the files that may refer to scalars, the unused
+// imports are removed when the code is formatted.
+func ScalarImports(api *design.APIDefinition) []*ImportSpec {
+	imports := []*ImportSpec{
+		SimpleImport("encoding/json"),
+		SimpleImport("math/big"),
+		SimpleImport("strconv"),
+		SimpleImport("time"),
+		SimpleImport("github.com/goadesign/goa"),
+	}
+	seen := map[string]bool{"math/big": true, "time": true}
+	for _, s := range api.Scalars {
+		if s.PackagePath == "" || seen[s.PackagePath] {
+			continue
+		}
+		seen[s.PackagePath] = true
+		name := s.GoType
+		if i := strings.Index(name, "."); i > 0 {
+			name = name[:i]
+		}
+		if name == path.Base(s.PackagePath) {
+			imports = append(imports, SimpleImport(s.PackagePath))
+		} else {
+			imports = append(imports, NewImport(name, s.PackagePath))
+		}
+	}
+	return imports
+}
+
+// wireField describes a struct field holding custom scalars encoded with the scalar format and
+// parse functions, see WireMarshalers.
+type wireField struct {
+	Field, Tag, WireType string
+	Scalar               *design.ScalarDefinition
+	Pointer, Ref, Array  bool
+}
+
+// WireMarshalers produces the MarshalJSON and UnmarshalJSON methods of the public struct generated
+// for att if the struct has fields holding scalars whose JSON encoding differs from their wire
+// representation (see design.ScalarDefinition.MarshalWire) or fields with legacy names (see
+// design.AttributeDefinition.Aliases) or if its absent attributes are encoded with their zero
+// values (see design.EmitZero). The methods convert the scalar fields with the scalar format and
+// parse functions, decode the legacy names, set the absent attributes and let the other fields use
+// the default encoding. recv is the method receiver name and typeName the name of the struct.
+func WireMarshalers(att *design.AttributeDefinition, recv, typeName string) string {
+	fields := wireFields(att)
+	decAliases, encAliases := jsonAliases(att)
+	zeros := jsonZeros(att)
+	var encDecl, decDecl, enc, dec []string
+	for _, f := range fields {
+		encWire, decWire := "[]"+f.WireType, "[]"+f.WireType
+		if !f.Array {
+			encWire, decWire = f.WireType, "*"+f.WireType
+			if f.Pointer || f.Ref {
+				encWire = "*" + f.WireType
+			}
+		}
+		encDecl = append(encDecl, fmt.Sprintf("\t\t%s %s `json:%q`", f.Field, encWire, f.Tag))
+		decDecl = append(decDecl, fmt.Sprintf("\t\t%s %s `json:%q`", f.Field, decWire, f.Tag))
+		switch {
+		case f.Array:
+			enc = append(enc, fmt.Sprintf("\tfor _, e := range %[1]s.%[2]s {\n\t\tenc.%[2]s = append(enc.%[2]s, %[3]s(e))\n\t}",
+				recv, f.Field, f.Scalar.FormatFunc))
+			dec = append(dec, fmt.Sprintf("\tif dec.%[2]s != nil {\n\t\t%[1]s.%[2]s = make([]%[3]s, len(dec.%[2]s))\n"+
+				"\t\tfor i, e := range dec.%[2]s {\n\t\t\tv, err := %[4]s(e)\n\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n"+
+				"\t\t\t%[1]s.%[2]s[i] = v\n\t\t}\n\t}", recv, f.Field, f.Scalar.GoType, f.Scalar.ParseFunc))
+			continue
+		case f.Pointer:
+			enc = append(enc, fmt.Sprintf("\tif %[1]s.%[2]s != nil {\n\t\tv := %[3]s(*%[1]s.%[2]s)\n\t\tenc.%[2]s = &v\n\t}",
+				recv, f.Field, f.Scalar.FormatFunc))
+		case f.Ref:
+			enc = append(enc, fmt.Sprintf("\tif %[1]s.%[2]s != nil {\n\t\tv := %[3]s(%[1]s.%[2]s)\n\t\tenc.%[2]s = &v\n\t}",
+				recv, f.Field, f.Scalar.FormatFunc))
+		default:
+			enc = append(enc, fmt.Sprintf("\tenc.%[2]s = %[3]s(%[1]s.%[2]s)", recv, f.Field, f.Scalar.FormatFunc))
+		}
+		assign := "v"
+		if f.Pointer {
+			assign = "&v"
+		}
+		dec = append(dec, fmt.Sprintf("\tif dec.%[2]s != nil {\n\t\tv, err := %[3]s(*dec.%[2]s)\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n"+
+			"\t\t%[1]s.%[2]s = %[4]s\n\t}", recv, f.Field, f.Scalar.ParseFunc, assign))
+	}
+	var methods []string
+	if len(fields) > 0 || encAliases != "" || zeros != "" {
+		var code string
+		if len(fields) > 0 {
+			code = fmt.Sprintf("\tenc := struct {\n\t\t*alias\n%s\n\t}{alias: (*alias)(%s)}\n%s\n",
+				strings.Join(encDecl, "\n"), recv, strings.Join(enc, "\n"))
+		} else {
+			code = fmt.Sprintf("\tenc := (*alias)(%s)\n", recv)
+		}
+		switch {
+		case zeros != "" && encAliases != "":
+			code += fmt.Sprintf("\tb, err := json.Marshal(enc)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n"+
+				"\tif b, err = goa.EmitJSONZeros(b, %s); err != nil {\n\t\treturn nil, err\n\t}\n"+
+				"\treturn goa.EmitJSONAliases(b, %s)", zeros, encAliases)
+		case zeros != "":
+			code += fmt.Sprintf("\tb, err := json.Marshal(enc)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n"+
+				"\treturn goa.EmitJSONZeros(b, %s)", zeros)
+		case encAliases != "":
+			code += fmt.Sprintf("\tb, err := json.Marshal(enc)\n\tif err != nil {\n\t\treturn nil, err\n\t}\n"+
+				"\treturn goa.EmitJSONAliases(b, %s)", encAliases)
+		default:
+			code += "\treturn json.Marshal(enc)"
+		}
+		methods = append(methods, fmt.Sprintf(`// MarshalJSON encodes %[2]s using the wire representation of its attributes.
+func (%[1]s *%[2]s) MarshalJSON() ([]byte, error) {
+	type alias %[2]s
+%[3]s
+}`, recv, typeName, code))
+	}
+	if len(fields) > 0 || decAliases != "" {
+		var code string
+		if decAliases != "" {
+			code = fmt.Sprintf("\tdata, err := goa.ResolveJSONAliases(data, %s)\n\tif err != nil {\n\t\treturn err\n\t}\n", decAliases)
+		}
+		if len(fields) > 0 {
+			code += fmt.Sprintf("\tdec := struct {\n\t\t*alias\n%s\n\t}{alias: (*alias)(%s)}\n"+
+				"\tif err := json.Unmarshal(data, &dec); err != nil {\n\t\treturn err\n\t}\n%s\n\treturn nil",
+				strings.Join(decDecl, "\n"), recv, strings.Join(dec, "\n"))
+		} else {
+			code += fmt.Sprintf("\treturn json.Unmarshal(data, (*alias)(%s))", recv)
+		}
+		methods = append(methods, fmt.Sprintf(`// UnmarshalJSON decodes %[2]s from the wire representation of its attributes.
+func (%[1]s *%[2]s) UnmarshalJSON(data []byte) error {
+	type alias %[2]s
+%[3]s
+}`, recv, typeName, code))
+	}
+	return strings.Join(methods, "\n\n")
+}
+
+// AliasUnmarshaler produces the UnmarshalJSON method of the private struct generated for att if
+// the struct has fields with legacy names (see design.AttributeDefinition.Aliases). The method
+// decodes the fields given with their legacy names. It also produces the JSONAliases method that
+// lets goa.DecodeRequestStrict accept the legacy names (see goa.JSONAliaser). recv is the method
+// receiver name and typeName the name of the struct.
+func AliasUnmarshaler(att *design.AttributeDefinition, recv, typeName string) string {
+	aliases, _ := jsonAliases(att)
+	if aliases == "" {
+		return ""
+	}
+	return fmt.Sprintf(`// JSONAliases returns the names of the %[2]s attributes indexed by legacy name.
+func (%[1]s *%[2]s) JSONAliases() map[string]string {
+	return %[3]s
+}
+
+// UnmarshalJSON decodes %[2]s accepting the legacy names of its attributes.
+func (%[1]s *%[2]s) UnmarshalJSON(data []byte) error {
+	type alias %[2]s
+	data, err := goa.ResolveJSONAliases(data, %[1]s.JSONAliases())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*alias)(%[1]s))
+}`, recv, typeName, aliases)
+}
+
+// jsonAliases returns the Go literals of the maps given to goa.ResolveJSONAliases and
+// goa.EmitJSONAliases for the child attributes of att, empty strings if none define aliases.
+func jsonAliases(att *design.AttributeDefinition) (dec, enc string) {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return
+	}
+	var decs, encs []string
+	obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		aliases := catt.Aliases()
+		if len(aliases) == 0 {
+			return nil
+		}
+		name := n
+		if v, ok := catt.Metadata["struct:tag:json"]; ok && len(v) > 0 && v[0] != "" {
+			name = v[0]
+		}
+		quoted := make([]string, len(aliases))
+		for i, a := range aliases {
+			decs = append(decs, fmt.Sprintf("%q: %q", a, name))
+			quoted[i] = fmt.Sprintf("%q", a)
+		}
+		if catt.EmitsAliases() {
+			encs = append(encs, fmt.Sprintf("%q: {%s}", name, strings.Join(quoted, ", ")))
+		}
+		return nil
+	})
+	if len(decs) > 0 {
+		dec = fmt.Sprintf("map[string]string{%s}", strings.Join(decs, ", "))
+	}
+	if len(encs) > 0 {
+		enc = fmt.Sprintf("map[string][]string{%s}", strings.Join(encs, ", "))
+	}
+	return
+}
+
+// wireFields lists the fields of the public struct generated for att that hold scalars or arrays
+// of scalars encoded with the scalar functions.
+func wireFields(att *design.AttributeDefinition) []*wireField {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return nil
+	}
+	var fields []*wireField
+	obj.IterateAttributes(func(n string, catt *design.AttributeDefinition) error {
+		t, array := catt.Type, false
+		if t.IsArray() {
+			t, array = t.ToArray().ElemType.Type, true
+		}
+		s, ok := t.(*design.ScalarDefinition)
+		if !ok || !s.MarshalWire {
+			return nil
+		}
+		tag := n
+		if v, ok := catt.Metadata["struct:tag:json"]; ok && len(v) > 0 {
+			tag = strings.Join(v, ",")
+		} else if !att.IsRequired(n) && !att.HasDefaultValue(n) && att.AbsentPolicy() == design.OmitAbsent {
+			tag += ",omitempty"
+		}
+		fields = append(fields, &wireField{
+			Field:    GoifyAtt(catt, n, true),
+			Tag:      tag,
+			WireType: GoNativeType(s.Primitive),
+			Scalar:   s,
+			Pointer:  !array && att.IsPrimitivePointer(n),
+			Ref:      !array && s.IsReference(),
+			Array:    array,
+		})
+		return nil
+	})
+	return fields
+}
+
+// EnumConst returns the name of the Go constant generated for the given value of the enum type s,
+// e.g. "StatusActive".
+func EnumConst(s *design.ScalarDefinition, val interface{}) string {
+	if i, ok := val.(int); ok && i < 0 {
+		return fmt.Sprintf("%sMinus%d", s.GoType, -i)
+	}
+	return s.GoType + Goify(fmt.Sprintf("%v", val), true)
+}
+
+// IsScalar returns true if the given type is a custom scalar.
+func IsScalar(t design.DataType) bool {
+	_, ok := t.(*design.ScalarDefinition)
+	return ok
+}
+
+// scalarChecker produces the code that validates the value of a custom scalar attribute. The
+// fields of private structs hold wire values which are parsed, the fields of public structs hold
+// Go values.
+func scalarChecker(s *design.ScalarDefinition, isPointer bool, target, context string, depth int, private bool) string {
+	val := target
+	if isPointer && (private || !s.IsReference()) {
+		val = "*" + target
+	}
+	tabs := Tabs(depth)
+	var code string
+	fail := fmt.Sprintf("%s\terr = goa.MergeErrors(err, goa.InvalidScalarError(`%s`, %s, %q, err2))\n",
+		tabs, context, val, s.TypeName)
+	switch {
+	case private && s.ValidateFunc != "":
+		code = fmt.Sprintf("%sif v, err2 := %s(%s); err2 != nil {\n%s%s} else if err2 := %s(v); err2 != nil {\n%s%s}",
+			tabs, s.ParseFunc, val, fail, tabs, s.ValidateFunc, fail, tabs)
+	case private:
+		code = fmt.Sprintf("%sif _, err2 := %s(%s); err2 != nil {\n%s%s}", tabs, s.ParseFunc, val, fail, tabs)
+	case s.ValidateFunc != "":
+		code = fmt.Sprintf("%sif err2 := %s(%s); err2 != nil {\n%s%s}", tabs, s.ValidateFunc, val, fail, tabs)
+	default:
+		return ""
+	}
+	if isPointer {
+		code = fmt.Sprintf("%sif %s != nil {\n%s\n%s}", tabs, target, indent(code), tabs)
+	}
+	return code
+}
+
+// elemChecker produces the code that validates the array element "e". Elements of custom scalar
+// arrays hold values rather than pointers.
+func elemChecker(elem *design.AttributeDefinition, context string, depth int, private bool) string {
+	if s, ok := elem.Type.(*design.ScalarDefinition); ok {
+		return scalarChecker(s, false, "e", context, depth, private)
+	}
+	return RecursiveChecker(elem, false, false, false, "e", context, depth, private)
+}
+
+// scalarPublicizer produces the code that parses the wire value held by the private struct field
+// source into the public struct field target. The value is validated prior to publicizing so the
+// parse error is ignored.
+func scalarPublicizer(s *design.ScalarDefinition, source, target string, pointer bool, depth int) string {
+	assign := "v"
+	if pointer {
+		assign = "&v"
+	}
+	return fmt.Sprintf("%sif v, err := %s(*%s); err == nil {\n%s\t%s = %s\n%s}",
+		Tabs(depth), s.ParseFunc, source, Tabs(depth), target, assign, Tabs(depth))
+}
+
+// indent adds a tab at the beginning of each non empty line of code.
+func indent(code string) string {
+	lines := strings.Split(code, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "\t" + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
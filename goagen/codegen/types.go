@@ -67,7 +67,7 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 	def := ds.Definition()
 	t := def.Type
 	switch actual := t.(type) {
-	case design.Primitive:
+	case design.Primitive, *design.ScalarDefinition, *design.FlagsDefinition:
 		return GoTypeName(t, nil, tabs, private)
 	case *design.Array:
 		d := GoTypeDef(actual.ElemType, tabs, jsonTags, private)
@@ -111,7 +111,9 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 		WriteTabs(&buffer, tabs+1)
 		field := obj[name]
 		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
+		if !private && def.IsNullable(name) {
+			typedef, _ = NullWrapper(field.Type)
+		} else if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
 			typedef = "*" + typedef
 		}
 		fname := GoifyAtt(field, name, true)
@@ -126,6 +128,12 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 		}
 		buffer.WriteString(fmt.Sprintf("%s%s %s%s\n", desc, fname, typedef, tags))
 	}
+	if !private && len(def.PresenceAttributes()) > 0 {
+		WriteTabs(&buffer, tabs+1)
+		buffer.WriteString("// presence records the optional attributes set in the request.\n")
+		WriteTabs(&buffer, tabs+1)
+		buffer.WriteString("presence goa.Presence\n")
+	}
 	WriteTabs(&buffer, tabs)
 	buffer.WriteString("}")
 	return buffer.String()
@@ -154,7 +162,7 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 	}
 	// Default algorithm
 	var omit string
-	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name)) {
+	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name) && parent.AbsentPolicy() == design.OmitAbsent) {
 		omit = ",omitempty"
 	}
 	return fmt.Sprintf(" `form:\"%s%s\" json:\"%s%s\" xml:\"%s%s\"`", name, omit, name, omit, name, omit)
@@ -190,6 +198,16 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 	switch actual := t.(type) {
 	case design.Primitive:
 		return GoNativeType(t)
+	case *design.ScalarDefinition:
+		if private {
+			return GoNativeType(actual.Primitive)
+		}
+		return actual.GoType
+	case *design.FlagsDefinition:
+		if private {
+			return "[]string"
+		}
+		return actual.GoType
 	case *design.Array:
 		return "[]" + GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private)
 	case design.Object:
@@ -239,6 +257,10 @@ func GoNativeType(t design.DataType) string {
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
+	case *design.ScalarDefinition:
+		return GoNativeType(actual.Primitive)
+	case *design.FlagsDefinition:
+		return "[]string"
 	case *design.Array:
 		return "[]" + GoNativeType(actual.ElemType.Type)
 	case design.Object:
@@ -735,3 +757,44 @@ const transformHashTmpl = `{{ tabs .Depth }}{{ .TargetCtx }} = make(map[{{ gotyp
 */}}{{ tabs .Depth }}	{{ .TargetCtx }}[tk] = tv
 {{ tabs .Depth }}}
 `
+
+// NullWrapper returns the name of the goa.Null* type used to generate the fields of the optional
+// attributes of the given primitive type under the nullable field policy and the name of the
+// wrapper field holding the value.
+func NullWrapper(t design.DataType) (string, string) {
+	switch t.Kind() {
+	case design.BooleanKind:
+		return "goa.NullBool", "Bool"
+	case design.IntegerKind:
+		return "goa.NullInt", "Int"
+	case design.NumberKind:
+		return "goa.NullFloat64", "Float64"
+	case design.StringKind:
+		return "goa.NullString", "String"
+	case design.DateTimeKind:
+		return "goa.NullTime", "Time"
+	}
+	panic(fmt.Sprintf("no null wrapper for %s", t.Name())) // bug
+}
+
+// PresenceMethods produces the HasXXX and SetXXX methods of the public struct generated for att
+// for each attribute whose presence is recorded, see design.PresenceFields. recv is the method
+// receiver name and typeRef the Go reference to the struct.
+func PresenceMethods(att *design.AttributeDefinition, recv, typeRef string) string {
+	var methods []string
+	obj := att.Type.ToObject()
+	for i, n := range att.PresenceAttributes() {
+		field := GoifyAtt(obj[n], n, true)
+		methods = append(methods, fmt.Sprintf(`// Has%[3]s returns true if the %[4]q attribute is set.
+func (%[1]s %[2]s) Has%[3]s() bool {
+	return %[1]s.presence.Has(%[5]d)
+}
+
+// Set%[3]s sets the %[4]q attribute and records its presence.
+func (%[1]s %[2]s) Set%[3]s(v %[6]s) {
+	%[1]s.%[3]s = v
+	%[1]s.presence.Set(%[5]d)
+}`, recv, typeRef, field, n, i, GoTypeRef(obj[n].Type, nil, 0, false)))
+	}
+	return strings.Join(methods, "\n\n")
+}
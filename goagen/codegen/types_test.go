@@ -628,3 +628,222 @@ var _ = Describe("GoTypeDesc", func() {
 		})
 	})
 })
+
+var _ = Describe("optional field policies", func() {
+	var att *AttributeDefinition
+	var policy FieldPolicy
+
+	JustBeforeEach(func() {
+		att = &AttributeDefinition{
+			Type: Object{
+				"name": &AttributeDefinition{Type: String, Validation: &dslengine.ValidationDefinition{MinLength: intPtr(2)}},
+				"age":  &AttributeDefinition{Type: Integer},
+			},
+			Metadata: dslengine.MetadataDefinition{FieldPolicyMetadata: []string{string(policy)}},
+		}
+	})
+
+	Context("using the nullable policy", func() {
+		BeforeEach(func() {
+			policy = NullableFields
+		})
+
+		It("generates goa.Null* fields", func() {
+			st := codegen.GoTypeDef(att, 0, false, false)
+			Ω(st).Should(Equal("struct {\n\tAge goa.NullInt\n\tName goa.NullString\n}"))
+		})
+
+		It("keeps pointers in the private struct", func() {
+			st := codegen.GoTypeDef(att, 0, false, true)
+			Ω(st).Should(Equal("struct {\n\tAge *int\n\tName *string\n}"))
+		})
+
+		It("validates the valid values", func() {
+			code := codegen.RecursiveChecker(att, false, false, false, "ut", "response", 1, false)
+			Ω(code).Should(ContainSubstring("if ut.Name.Valid {"))
+			Ω(code).Should(ContainSubstring("utf8.RuneCountInString(ut.Name.String) < 2"))
+		})
+
+		It("wraps the publicized values", func() {
+			code := codegen.RecursivePublicizer(att, "src", "pub", 1)
+			Ω(code).Should(ContainSubstring("pub.Name = goa.NullString{String: *src.Name, Valid: true}"))
+		})
+	})
+
+	Context("using the presence policy", func() {
+		BeforeEach(func() {
+			policy = PresenceFields
+		})
+
+		It("generates value fields and the presence bitmap", func() {
+			st := codegen.GoTypeDef(att, 0, false, false)
+			Ω(st).Should(Equal("struct {\n\tAge int\n\tName string\n\t// presence records the optional attributes set in the request.\n\tpresence goa.Presence\n}"))
+		})
+
+		It("validates the present values", func() {
+			code := codegen.RecursiveChecker(att, false, false, false, "ut", "response", 1, false)
+			Ω(code).Should(ContainSubstring("if ut.presence.Has(1) {"))
+		})
+
+		It("records the presence of the publicized values", func() {
+			code := codegen.RecursivePublicizer(att, "src", "pub", 1)
+			Ω(code).Should(ContainSubstring("pub.Name = *src.Name\n\t\tpub.presence.Set(1)"))
+		})
+
+		It("generates the presence methods", func() {
+			code := codegen.PresenceMethods(att, "ut", "*Account")
+			Ω(code).Should(ContainSubstring("func (ut *Account) HasAge() bool {\n\treturn ut.presence.Has(0)\n}"))
+			Ω(code).Should(ContainSubstring("func (ut *Account) SetName(v string) {"))
+		})
+	})
+})
+
+func intPtr(i int) *int { return &i }
+
+var _ = Describe("custom scalars", func() {
+	var scalar *ScalarDefinition
+	var att *AttributeDefinition
+
+	BeforeEach(func() {
+		scalar = &ScalarDefinition{
+			Primitive:    String,
+			TypeName:     "Decimal",
+			GoType:       "decimal.Decimal",
+			PackagePath:  "github.com/shopspring/decimal",
+			ParseFunc:    "decimal.NewFromString",
+			FormatFunc:   "decimal.Decimal.String",
+			ValidateFunc: "money.Validate",
+		}
+		att = &AttributeDefinition{
+			Type: Object{
+				"price":  &AttributeDefinition{Type: scalar},
+				"prices": &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: scalar}}},
+			},
+		}
+	})
+
+	It("uses the Go type in the public struct", func() {
+		st := codegen.GoTypeDef(att, 0, false, false)
+		Ω(st).Should(Equal("struct {\n\tPrice *decimal.Decimal\n\tPrices []decimal.Decimal\n}"))
+	})
+
+	It("uses the wire type in the private struct", func() {
+		st := codegen.GoTypeDef(att, 0, false, true)
+		Ω(st).Should(Equal("struct {\n\tPrice *string\n\tPrices []string\n}"))
+	})
+
+	It("imports the scalar packages", func() {
+		imports := codegen.ScalarImports(&APIDefinition{Scalars: []*ScalarDefinition{scalar}})
+		Ω(imports).Should(HaveLen(6))
+		Ω(imports[5].Path).Should(Equal("github.com/shopspring/decimal"))
+	})
+
+	It("parses the private values", func() {
+		code := codegen.RecursiveChecker(att, false, false, false, "ut", "request", 1, true)
+		Ω(code).Should(ContainSubstring("decimal.NewFromString(*ut.Price)"))
+	})
+
+	It("validates the public values", func() {
+		code := codegen.RecursiveChecker(att, false, false, false, "ut", "request", 1, false)
+		Ω(code).Should(ContainSubstring("money.Validate(*ut.Price)"))
+	})
+
+	It("parses the publicized values", func() {
+		code := codegen.RecursivePublicizer(att, "src", "pub", 1)
+		Ω(code).Should(ContainSubstring("decimal.NewFromString(*src.Price)"))
+	})
+})
+
+var _ = Describe("WireMarshalers", func() {
+	var att *AttributeDefinition
+
+	BeforeEach(func() {
+		att = &AttributeDefinition{
+			Type: Object{
+				"name":    &AttributeDefinition{Type: String},
+				"timeout": &AttributeDefinition{Type: Duration},
+				"on":      &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: Date}}},
+				"at":      &AttributeDefinition{Type: Time},
+			},
+			Validation: &dslengine.ValidationDefinition{Required: []string{"timeout"}},
+		}
+	})
+
+	It("encodes the scalars whose JSON encoding differs from the wire", func() {
+		code := codegen.WireMarshalers(att, "ut", "Event")
+		Ω(code).Should(ContainSubstring("func (ut *Event) MarshalJSON() ([]byte, error) {"))
+		Ω(code).Should(ContainSubstring("Timeout string `json:\"timeout\"`"))
+		Ω(code).Should(ContainSubstring("On []string `json:\"on,omitempty\"`"))
+		Ω(code).Should(ContainSubstring("enc.Timeout = goa.FormatISO8601Duration(ut.Timeout)"))
+		Ω(code).Should(ContainSubstring("v, err := goa.ParseDate(e)"))
+		Ω(code).ShouldNot(ContainSubstring("At "))
+	})
+
+	It("produces nothing for types with no such scalars", func() {
+		delete(att.Type.ToObject(), "timeout")
+		delete(att.Type.ToObject(), "on")
+		Ω(codegen.WireMarshalers(att, "ut", "Event")).Should(BeEmpty())
+	})
+
+	It("decodes and emits the legacy attribute names", func() {
+		delete(att.Type.ToObject(), "timeout")
+		delete(att.Type.ToObject(), "on")
+		att.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{
+			AliasMetadata:     []string{"title"},
+			EmitAliasMetadata: []string{"true"},
+		}
+		code := codegen.WireMarshalers(att, "ut", "Event")
+		Ω(code).Should(ContainSubstring(`data, err := goa.ResolveJSONAliases(data, map[string]string{"title": "name"})`))
+		Ω(code).Should(ContainSubstring(`return goa.EmitJSONAliases(b, map[string][]string{"name": {"title"}})`))
+		Ω(code).Should(ContainSubstring("return json.Unmarshal(data, (*alias)(ut))"))
+	})
+
+	It("lists the legacy attribute names of the private struct", func() {
+		delete(att.Type.ToObject(), "timeout")
+		delete(att.Type.ToObject(), "on")
+		att.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{AliasMetadata: []string{"title"}}
+		code := codegen.AliasUnmarshaler(att, "ut", "event")
+		Ω(code).Should(ContainSubstring("func (ut *event) JSONAliases() map[string]string {\n\treturn map[string]string{\"title\": \"name\"}\n}"))
+		Ω(code).Should(ContainSubstring("data, err := goa.ResolveJSONAliases(data, ut.JSONAliases())"))
+	})
+
+	Context("with the null absent policy", func() {
+		BeforeEach(func() {
+			att.Metadata = dslengine.MetadataDefinition{AbsentPolicyMetadata: []string{string(EmitNull)}}
+		})
+
+		It("does not omit the absent attributes", func() {
+			st := codegen.GoTypeDef(att, 0, true, false)
+			Ω(st).Should(ContainSubstring("Name *string `form:\"name\" json:\"name\" xml:\"name\"`"))
+			code := codegen.WireMarshalers(att, "ut", "Event")
+			Ω(code).Should(ContainSubstring("On []string `json:\"on\"`"))
+			Ω(code).ShouldNot(ContainSubstring("EmitJSONZeros"))
+		})
+
+		It("omits the absent attributes of private structs", func() {
+			st := codegen.GoTypeDef(att, 0, true, true)
+			Ω(st).Should(ContainSubstring("Name *string `form:\"name,omitempty\" json:\"name,omitempty\" xml:\"name,omitempty\"`"))
+		})
+	})
+
+	Context("with the zero absent policy", func() {
+		BeforeEach(func() {
+			att.Metadata = dslengine.MetadataDefinition{AbsentPolicyMetadata: []string{string(EmitZero)}}
+		})
+
+		It("emits the zero values of the absent attributes", func() {
+			code := codegen.WireMarshalers(att, "ut", "Event")
+			Ω(code).Should(ContainSubstring(`return goa.EmitJSONZeros(b, map[string]string{"at": "\"\"", "name": "\"\"", "on": "[]"})`))
+		})
+
+		It("chains the legacy attribute names", func() {
+			att.Type.ToObject()["name"].Metadata = dslengine.MetadataDefinition{
+				AliasMetadata:     []string{"title"},
+				EmitAliasMetadata: []string{"true"},
+			}
+			code := codegen.WireMarshalers(att, "ut", "Event")
+			Ω(code).Should(ContainSubstring("if b, err = goa.EmitJSONZeros(b, "))
+			Ω(code).Should(ContainSubstring(`return goa.EmitJSONAliases(b, map[string][]string{"name": {"title"}})`))
+		})
+	})
+})
@@ -32,6 +32,9 @@ func init() {
 		"goifyAtt":         GoifyAtt,
 		"add":              Add,
 		"recursiveChecker": RecursiveChecker,
+		"isScalar":         IsScalar,
+		"isFlags":          IsFlags,
+		"elemChecker":      elemChecker,
 	}
 	if arrayValT, err = template.New("array").Funcs(fm).Parse(arrayValTmpl); err != nil {
 		panic(err)
@@ -62,6 +65,13 @@ func init() {
 // RecursiveChecker produces Go code that runs the validation checks recursively over the given
 // attribute.
 func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
+	if f, ok := att.Type.(*design.FlagsDefinition); ok {
+		if !private {
+			// Public flags are bitmasks built from validated names.
+			return ""
+		}
+		return flagsChecker(f, target, context, depth)
+	}
 	var checks []string
 	if o := att.Type.ToObject(); o != nil {
 		if ds, ok := att.Type.(design.DataStructure); ok {
@@ -119,16 +129,36 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 				if catt.Type.IsObject() {
 					dp++
 				}
+				// Optional fields generated with the nullable or presence policies are
+				// not pointers, their value is validated only when set.
+				ctarget := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true))
+				required := att.IsRequired(n)
+				var guard string
+				if !private && att.IsNullable(n) {
+					_, f := NullWrapper(catt.Type)
+					guard = ctarget + ".Valid"
+					ctarget += "." + f
+					required = true
+					dp++
+				} else if !private && att.HasPresence(n) {
+					guard = fmt.Sprintf("%s.presence.Has(%d)", target, att.PresenceIndex(n))
+					required = true
+					dp++
+				}
 				validation = RecursiveChecker(
 					catt,
 					att.IsNonZero(n),
-					att.IsRequired(n),
+					required,
 					att.HasDefaultValue(n),
-					fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
+					ctarget,
 					fmt.Sprintf("%s.%s", context, n),
 					dp,
 					private,
 				)
+				if validation != "" && guard != "" {
+					validation = fmt.Sprintf("%sif %s {\n%s\n%s}",
+						Tabs(depth), guard, validation, Tabs(depth))
+				}
 			}
 			if validation != "" {
 				if catt.Type.IsObject() {
@@ -175,6 +205,12 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
 	t := target
 	isPointer := private || (!required && !hasDefault && !nonzero)
+	if s, ok := att.Type.(*design.ScalarDefinition); ok {
+		return scalarChecker(s, isPointer, target, context, depth, private)
+	}
+	if IsFlags(att.Type) {
+		return ""
+	}
 	if isPointer && att.Type.IsPrimitive() {
 		t = "*" + t
 	}
@@ -296,7 +332,7 @@ func constant(formatName string) string {
 }
 
 const (
-	arrayValTmpl = `{{$validation := recursiveChecker .elemType false false false "e" (printf "%s[*]" .context) (add .depth 1) .private}}{{/*
+	arrayValTmpl = `{{$validation := elemChecker .elemType (printf "%s[*]" .context) (add .depth 1) .private}}{{/*
 */}}{{if $validation}}{{tabs .depth}}for _, e := range {{.target}} {
 {{$validation}}
 {{tabs .depth}}}{{end}}`
@@ -342,10 +378,10 @@ const (
 {{end}}{{tabs .depth}}}`
 
 	requiredValTmpl = `{{range $r := .required}}{{$catt := index $.attribute.Type.ToObject $r}}{{/*
-*/}}{{if and (not $.private) (eq $catt.Type.Kind 4)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == "" {
+*/}}{{if and (not $.private) (eq $catt.Type.Kind 4) (not (isScalar $catt.Type))}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == "" {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
-{{else if or $.private (not $catt.Type.IsPrimitive)}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == nil {
+{{else if or $.private (and (not $catt.Type.IsPrimitive) (not (isFlags $catt.Type)))}}{{tabs $.depth}}if {{$.target}}.{{goifyAtt $catt $r true}} == nil {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
 {{end}}{{end}}`
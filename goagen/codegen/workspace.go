@@ -55,6 +55,7 @@ var (
 	// DefaultFuncMap is the FuncMap used to initialize all source file templates.
 	DefaultFuncMap = template.FuncMap{
 		"add":                 func(a, b int) int { return a + b },
+		"aliasUnmarshaler":    AliasUnmarshaler,
 		"commandLine":         CommandLine,
 		"comment":             Comment,
 		"goify":               Goify,
@@ -65,14 +66,17 @@ var (
 		"gotypedesc":          GoTypeDesc,
 		"gotyperef":           GoTypeRef,
 		"join":                strings.Join,
+		"presenceMethods":     PresenceMethods,
 		"recursiveFinalizer":  RecursiveFinalizer,
 		"recursiveValidate":   RecursiveChecker,
 		"recursivePublicizer": RecursivePublicizer,
 		"tabs":                Tabs,
 		"tempvar":             Tempvar,
 		"title":               strings.Title,
+		"tlsVersion":          TLSVersion,
 		"toLower":             strings.ToLower,
 		"validationChecker":   ValidationChecker,
+		"wireMarshalers":      WireMarshalers,
 	}
 )
 
@@ -299,7 +303,10 @@ func (f *SourceFile) ExecuteTemplate(name, source string, funcMap template.FuncM
 }
 
 // PackagePath returns the Go package path for the directory that lives under the given absolute
-// file path.
+// file path. Directories living under a "vendor" directory map to the import path of the vendored
+// package. Directories that do not live under GOPATH are resolved using the module path declared
+// in the closest parent go.mod file so that generated code may be written inside monorepos with
+// custom layouts.
 func PackagePath(path string) (string, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -313,12 +320,57 @@ func PackagePath(path string) (string, error) {
 		if filepath.HasPrefix(absPath, gopath) {
 			base := filepath.FromSlash(gopath + "/src")
 			rel, err := filepath.Rel(base, absPath)
-			return filepath.ToSlash(rel), err
+			return vendorless(filepath.ToSlash(rel)), err
 		}
 	}
+	if pkgPath, ok := modulePackagePath(absPath); ok {
+		return vendorless(pkgPath), nil
+	}
 	return "", fmt.Errorf("%s does not contain a Go package", absPath)
 }
 
+// vendorless strips the vendor directory prefix from the given package path if any.
+func vendorless(pkgPath string) string {
+	if strings.HasPrefix(pkgPath, "vendor/") {
+		return pkgPath[len("vendor/"):]
+	}
+	if i := strings.LastIndex(pkgPath, "/vendor/"); i > -1 {
+		return pkgPath[i+len("/vendor/"):]
+	}
+	return pkgPath
+}
+
+// modulePackagePath looks for a go.mod file in the given directory or its parents and computes
+// the package path from the module path it declares.
+func modulePackagePath(absPath string) (string, bool) {
+	dir := absPath
+	for {
+		if b, err := ioutil.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(b), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) < 2 || fields[0] != "module" {
+					continue
+				}
+				modPath := strings.Trim(fields[1], `"`)
+				rel, err := filepath.Rel(dir, absPath)
+				if err != nil {
+					return "", false
+				}
+				if rel == "." {
+					return modPath, true
+				}
+				return modPath + "/" + filepath.ToSlash(rel), true
+			}
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
 // PackageSourcePath returns the absolute path to the given package source.
 func PackageSourcePath(pkg string) (string, error) {
 	buildCtx := build.Default
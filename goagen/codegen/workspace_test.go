@@ -0,0 +1,85 @@
+package codegen_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PackagePath", func() {
+	var root, path string
+	var oldGOPATH string
+
+	var pkgPath string
+	var err error
+
+	BeforeEach(func() {
+		oldGOPATH = os.Getenv("GOPATH")
+		root, err = ioutil.TempDir("", "goagen")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		pkgPath, err = codegen.PackagePath(path)
+	})
+
+	AfterEach(func() {
+		os.Setenv("GOPATH", oldGOPATH)
+		os.RemoveAll(root)
+	})
+
+	Context("with a directory under GOPATH", func() {
+		BeforeEach(func() {
+			os.Setenv("GOPATH", root)
+			path = filepath.Join(root, "src", "github.com", "foo", "bar")
+		})
+
+		It("returns the path relative to GOPATH/src", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(pkgPath).Should(Equal("github.com/foo/bar"))
+		})
+	})
+
+	Context("with a vendored directory under GOPATH", func() {
+		BeforeEach(func() {
+			os.Setenv("GOPATH", root)
+			path = filepath.Join(root, "src", "github.com", "foo", "vendor", "github.com", "baz", "app")
+		})
+
+		It("returns the vendored package import path", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(pkgPath).Should(Equal("github.com/baz/app"))
+		})
+	})
+
+	Context("with a directory in a module outside of GOPATH", func() {
+		BeforeEach(func() {
+			os.Setenv("GOPATH", filepath.Join(root, "gopath"))
+			mod := filepath.Join(root, "monorepo")
+			Ω(os.MkdirAll(mod, 0755)).ShouldNot(HaveOccurred())
+			content := []byte("module example.com/monorepo\n")
+			Ω(ioutil.WriteFile(filepath.Join(mod, "go.mod"), content, 0644)).ShouldNot(HaveOccurred())
+			path = filepath.Join(mod, "services", "users", "app")
+		})
+
+		It("uses the module path", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(pkgPath).Should(Equal("example.com/monorepo/services/users/app"))
+		})
+	})
+
+	Context("with a directory outside of GOPATH and modules", func() {
+		BeforeEach(func() {
+			os.Setenv("GOPATH", filepath.Join(root, "gopath"))
+			path = filepath.Join(root, "nowhere")
+		})
+
+		It("fails", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultConfig is the name of the configuration file loaded when the --config flag is not set.
+const defaultConfig = "goa.yaml"
+
+// config is the content of the goagen configuration file. The top level scalar and list values
+// set the global flags, e.g. "design" or "out". The top level maps set the flags of the command
+// with the same name, e.g. "client" or "bootstrap", and take precedence over the global values.
+// The "plugins" map holds the flags passed to the third-party generators indexed by generator
+// package import path. The flags given on the command line take precedence over the file:
+//
+//	design: github.com/acme/cellar/design
+//	out: .
+//	bootstrap:
+//	  only: server,openapi
+//	client:
+//	  tool: cellar-cli
+//	plugins:
+//	  github.com/acme/gen_events:
+//	    broker: nats
+type config struct {
+	// Plugins lists the third-party generator flags indexed by generator package import path.
+	Plugins map[string]map[string]interface{} `yaml:"plugins"`
+	// Settings lists the global and command flags.
+	Settings map[string]interface{} `yaml:",inline"`
+}
+
+// cfg is the loaded configuration, nil if there is no configuration file.
+var cfg *config
+
+// loadConfig loads the configuration file given by the --config flag of the command, goa.yaml if
+// the flag is not set and the file exists, and sets the command flags that are not set on the
+// command line.
+func loadConfig(c *cobra.Command) error {
+	path := defaultConfig
+	if f := c.Flags().Lookup("config"); f != nil && f.Changed {
+		path = f.Value.String()
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %s", err)
+	}
+	var conf config
+	if err := yaml.Unmarshal(b, &conf); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %s", path, err)
+	}
+	cfg = &conf
+
+	// Set the command flags first so they take precedence over the global flags.
+	if s, ok := conf.Settings[c.Name()].(map[interface{}]interface{}); ok {
+		for _, k := range sortedKeys(s) {
+			if err := setFlag(c, k, s[k]); err != nil {
+				return fmt.Errorf("invalid configuration file %s: %s: %s", path, c.Name(), err)
+			}
+		}
+	}
+	for k, v := range conf.Settings {
+		if _, ok := v.(map[interface{}]interface{}); ok {
+			continue
+		}
+		if c.Root().PersistentFlags().Lookup(k) == nil {
+			return fmt.Errorf("invalid configuration file %s: unknown global setting %q", path, k)
+		}
+		if err := setFlag(c, k, v); err != nil {
+			return fmt.Errorf("invalid configuration file %s: %s", path, err)
+		}
+	}
+	return nil
+}
+
+// pluginFlags returns the flags of the third-party generator with the given package import path
+// set in the configuration file.
+func pluginFlags(pkgPath string) map[string]string {
+	if cfg == nil {
+		return nil
+	}
+	flags := make(map[string]string)
+	for k, v := range cfg.Plugins[pkgPath] {
+		flags[k] = flagValue(v)
+	}
+	return flags
+}
+
+// setFlag sets the command flag with the given name unless it was set on the command line.
+func setFlag(c *cobra.Command, name string, v interface{}) error {
+	f := c.Flags().Lookup(name)
+	if f == nil {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if f.Changed {
+		return nil
+	}
+	return c.Flags().Set(name, flagValue(v))
+}
+
+// flagValue returns the flag value corresponding to the configuration value v: the comma separated
+// list of the elements for lists and the string representation of the value otherwise.
+func flagValue(v interface{}) string {
+	if l, ok := v.([]interface{}); ok {
+		elems := make([]string, len(l))
+		for i, e := range l {
+			elems[i] = fmt.Sprint(e)
+		}
+		return strings.Join(elems, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// sortedKeys returns the sorted keys of a YAML map.
+func sortedKeys(m map[interface{}]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
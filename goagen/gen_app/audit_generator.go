@@ -0,0 +1,219 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ActionAuditTemplateData holds the data needed to generate the function that emits the audit
+// event of an action.
+type ActionAuditTemplateData struct {
+	Context      string   // Name of action context type
+	ResourceName string   // Name of resource
+	ActionName   string   // Name of action
+	Event        string   // Name of audit event
+	Params       []string // Names of path parameters identifying the accessed resources
+	HasPayload   bool     // Whether the action has a payload
+	Redact       []string // Paths to redacted payload attributes
+	SchemeName   string   // Name of action security scheme if any
+	SchemeKind   string   // Kind of action security scheme: "basic", "jwt" or ""
+}
+
+// generateAudit generates the audit event emitter interface and the functions that emit the
+// audit events of the audited actions.
+func (g *Generator) generateAudit() error {
+	var actions []*ActionAuditTemplateData
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Audit == nil {
+				return nil
+			}
+			data := &ActionAuditTemplateData{
+				Context:      codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Event:        a.Audit.Event,
+				Params:       auditParams(a),
+				HasPayload:   a.Payload != nil,
+				Redact:       a.Audit.RedactedPaths(),
+			}
+			if a.Security != nil && a.Security.Scheme != nil {
+				data.SchemeName = a.Security.Scheme.SchemeName
+				switch a.Security.Scheme.Kind {
+				case design.BasicAuthSecurityKind:
+					data.SchemeKind = "basic"
+				case design.JWTSecurityKind:
+					data.SchemeKind = "jwt"
+				}
+			}
+			actions = append(actions, data)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+
+	auditFile := filepath.Join(g.OutDir, "audit.go")
+	file, err := codegen.SourceFileFor(auditFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Audit Events", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("jwtgo", "github.com/dgrijalva/jwt-go"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/jwt"),
+	}
+	g.genfiles = append(g.genfiles, auditFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("audit", auditT, nil, actions); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// auditParams returns the names of the path parameters of the action routes in order of
+// appearance.
+func auditParams(a *design.ActionDefinition) []string {
+	var params []string
+	seen := make(map[string]bool)
+	for _, r := range a.Routes {
+		for _, p := range r.Params() {
+			if !seen[p] {
+				seen[p] = true
+				params = append(params, p)
+			}
+		}
+	}
+	return params
+}
+
+const (
+	// auditT generates the audit event emitter and the functions that emit the action events.
+	// template input: []*ActionAuditTemplateData
+	auditT = `// AuditEvent describes a call to an audited action.
+type AuditEvent struct {
+	// Name is the name of the audit event given in the design, e.g. "bottle.update".
+	Name string
+	// Resource is the name of the called action resource.
+	Resource string
+	// Action is the name of the called action.
+	Action string
+	// Actor identifies the caller authenticated by the action security scheme: the basic auth
+	// user name or the JWT subject. It is empty for other schemes, emitters may use the request
+	// context to identify the caller in this case.
+	Actor string
+	// Scheme is the name of the action security scheme if any.
+	Scheme string
+	// Params contains the request path parameters that identify the accessed resources.
+	Params map[string]string
+	// Payload is a snapshot of the request payload where the redacted attributes are masked.
+	Payload interface{}
+	// Status is the response status code, it is 0 if the action returned an error before
+	// writing the response.
+	Status int
+	// Err is the error returned by the action if any.
+	Err error
+	// Time is the time the action call completed.
+	Time time.Time
+}
+
+// AuditEmitter is the interface implemented by the audit log backends.
+type AuditEmitter interface {
+	// Emit is called after each call to an audited action.
+	Emit(ctx context.Context, event *AuditEvent)
+}
+
+// auditEmitterKey is the key used to store the audit emitter in the service context.
+type auditEmitterKey struct{}
+
+// UseAuditEmitter registers the emitter that receives the audit events with the service.
+func UseAuditEmitter(service *goa.Service, e AuditEmitter) {
+	service.Context = context.WithValue(service.Context, auditEmitterKey{}, e)
+}
+
+// auditRedacted is the value of the redacted attributes in the audit event payload snapshots.
+const auditRedacted = "[REDACTED]"
+
+// auditSnapshot returns a copy of v made of JSON compatible values where the values of the
+// attributes at the given paths are masked.
+func auditSnapshot(v interface{}, redact ...string) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var snapshot interface{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil
+	}
+	for _, p := range redact {
+		auditRedact(snapshot, strings.Split(p, "."))
+	}
+	return snapshot
+}
+
+// auditRedact masks the value at the given path in v.
+func auditRedact(v interface{}, path []string) {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			if _, ok := actual[path[0]]; ok {
+				actual[path[0]] = auditRedacted
+			}
+			return
+		}
+		auditRedact(actual[path[0]], path[1:])
+	case []interface{}:
+		for _, e := range actual {
+			auditRedact(e, path)
+		}
+	}
+}
+{{ range . }}
+// audit{{ .Context }} emits the {{ printf "%q" .Event }} audit event after a call to the {{ .ResourceName }} {{ .ActionName }}
+// action.
+func audit{{ .Context }}(ctx *{{ .Context }}, err error) {
+	e, ok := ctx.Value(auditEmitterKey{}).(AuditEmitter)
+	if !ok {
+		return
+	}
+	event := &AuditEvent{
+		Name:     {{ printf "%q" .Event }},
+		Resource: {{ printf "%q" .ResourceName }},
+		Action:   {{ printf "%q" .ActionName }},
+{{ if .SchemeName }}		Scheme:   {{ printf "%q" .SchemeName }},
+{{ end }}		Status:   ctx.ResponseData.Status,
+		Err:      err,
+		Time:     time.Now(),
+	}
+{{ if .Params }}	event.Params = map[string]string{
+{{ range .Params }}		{{ printf "%q" . }}: ctx.RequestData.Params.Get({{ printf "%q" . }}),
+{{ end }}	}
+{{ end }}{{ if .HasPayload }}	if ctx.Payload != nil {
+		event.Payload = auditSnapshot(ctx.Payload{{ range .Redact }}, {{ printf "%q" . }}{{ end }})
+	}
+{{ end }}{{ if eq .SchemeKind "basic" }}	if user, _, ok := ctx.RequestData.BasicAuth(); ok {
+		event.Actor = user
+	}
+{{ else if eq .SchemeKind "jwt" }}	if token := jwt.ContextJWT(ctx); token != nil {
+		if claims, ok := token.Claims.(jwtgo.MapClaims); ok {
+			event.Actor, _ = claims["sub"].(string)
+		}
+	}
+{{ end }}	e.Emit(ctx, event)
+}
+{{ end }}`
+)
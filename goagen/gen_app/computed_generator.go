@@ -0,0 +1,217 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// ComputedTemplateData holds the data needed to generate the computer interface of a
+	// projected media type that defines computed attributes.
+	ComputedTemplateData struct {
+		TypeName  string           // Go name of projected media type, e.g. "BottleTiny"
+		MediaType string           // Identifier of media type
+		ViewName  string           // Name of view that defines the computed attributes
+		Fields    []*ComputedField // Computed attributes
+	}
+
+	// ComputedField describes a computed attribute and the method of the computer interface
+	// that computes its value.
+	ComputedField struct {
+		Name        string // Design name of attribute
+		FieldName   string // Go name of struct field and computer method
+		Type        string // Go type of computed value
+		Description string // Description of attribute
+		Assign      string // Statement that sets the struct field to the computed value
+	}
+)
+
+// generateComputed generates the computer interfaces of the projected media types that define
+// computed attributes and the functions that apply them before the responses are sent.
+func (g *Generator) generateComputed() error {
+	var computed []*ComputedTemplateData
+	err := g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsError() || !mt.Type.IsObject() {
+			return nil
+		}
+		return mt.IterateViews(func(v *design.ViewDefinition) error {
+			p, _, err := mt.Project(v.Name)
+			if err != nil {
+				return err
+			}
+			if data := computedData(p); data != nil {
+				data.MediaType = mt.Identifier
+				data.ViewName = v.Name
+				computed = append(computed, data)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(computed) == 0 {
+		return nil
+	}
+
+	compFile := filepath.Join(g.OutDir, "computed.go")
+	file, err := codegen.SourceFileFor(compFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Computed Attributes", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
+	g.genfiles = append(g.genfiles, compFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("computed", computedT, nil, computed); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// computedData returns the data needed to generate the computer interface of the given projected
+// media type, nil if the media type does not define computed attributes.
+func computedData(projected *design.MediaTypeDefinition) *ComputedTemplateData {
+	names := projected.ComputedAttributes()
+	if len(names) == 0 {
+		return nil
+	}
+	data := &ComputedTemplateData{TypeName: codegen.GoTypeName(projected, nil, 0, false)}
+	obj := projected.Type.ToObject()
+	for _, n := range names {
+		att := obj[n]
+		field := codegen.GoifyAtt(att, n, true)
+		data.Fields = append(data.Fields, &ComputedField{
+			Name:        n,
+			FieldName:   field,
+			Type:        computedFieldType(att),
+			Description: att.Description,
+			Assign:      computedAssign(projected.AttributeDefinition, n, field, "v"+field),
+		})
+	}
+	return data
+}
+
+// computedFieldType returns the Go type of the value returned by the computer method of the given
+// attribute. Primitive values are pointers so that computers can leave the attribute unset.
+func computedFieldType(att *design.AttributeDefinition) string {
+	typ := codegen.GoTypeRef(att.Type, att.AllRequired(), 1, false)
+	if isReference(att.Type) || !att.Type.IsPrimitive() {
+		return typ
+	}
+	return "*" + typ
+}
+
+// computedAssign returns the statement that sets the field of the "res" struct to the computed
+// value held by the variable v if set.
+func computedAssign(parent *design.AttributeDefinition, name, field, v string) string {
+	att := parent.Type.ToObject()[name]
+	if parent.IsNullable(name) {
+		wrapper, val := codegen.NullWrapper(att.Type)
+		return fmt.Sprintf("if %s != nil {\nres.%s = %s{%s: *%s, Valid: true}\n}", v, field, wrapper, val, v)
+	}
+	if parent.HasPresence(name) {
+		return fmt.Sprintf("if %s != nil {\nres.Set%s(*%s)\n}", v, field, v)
+	}
+	if att.Type.IsPrimitive() && !isReference(att.Type) && !parent.IsPrimitivePointer(name) {
+		return fmt.Sprintf("if %s != nil {\nres.%s = *%s\n}", v, field, v)
+	}
+	return fmt.Sprintf("res.%s = %s", field, v)
+}
+
+// isReference returns true if t is a scalar whose Go type is a pointer.
+func isReference(t design.DataType) bool {
+	s, ok := t.(*design.ScalarDefinition)
+	return ok && s.IsReference()
+}
+
+// computeResult returns the statements of the response helper that set the computed attributes
+// of the response media type held by the "r" variable. Collections and envelopes are copied and
+// the computed attributes of their elements set.
+func computeResult(projected *design.MediaTypeDefinition) string {
+	if elem := projected.EnvelopeElem(); elem != nil {
+		data := computedData(elem)
+		if data == nil {
+			return ""
+		}
+		stmts := fmt.Sprintf("\t\t\te, err := compute%s(ctx.Context, e)\n", data.TypeName)
+		stmts += "\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n"
+		return envelopeItems(projected, stmts)
+	}
+	if projected.IsObject() {
+		data := computedData(projected)
+		if data == nil {
+			return ""
+		}
+		code := fmt.Sprintf("\trc, err := compute%s(ctx.Context, r)\n", data.TypeName)
+		code += "\tif err != nil {\n\t\treturn err\n\t}\n\tr = rc\n"
+		return code
+	}
+	if !projected.IsArray() {
+		return ""
+	}
+	elem, ok := projected.ToArray().ElemType.Type.(*design.MediaTypeDefinition)
+	if !ok {
+		return ""
+	}
+	data := computedData(elem)
+	if data == nil {
+		return ""
+	}
+	code := "\tif r != nil {\n"
+	code += fmt.Sprintf("\t\trc := make(%s, len(r))\n", codegen.GoTypeRef(projected, projected.AllRequired(), 0, false))
+	code += "\t\tfor i, e := range r {\n"
+	code += fmt.Sprintf("\t\t\tec, err := compute%s(ctx.Context, e)\n", data.TypeName)
+	code += "\t\t\tif err != nil {\n\t\t\t\treturn err\n\t\t\t}\n"
+	code += "\t\t\trc[i] = ec\n\t\t}\n\t\tr = rc\n\t}\n"
+	return code
+}
+
+const (
+	// computedT generates the computer interfaces and the functions applying them.
+	// template input: []*ComputedTemplateData
+	computedT = `// computedKey is the type used to store the computer implementations in the service context.
+type computedKey string
+{{ range . }}{{ $typeName := .TypeName }}
+// {{ .TypeName }}Computer computes the computed attributes of the {{ printf "%q" .ViewName }} view of the
+// {{ .MediaType }} media type. The methods are called before each response using the view is
+// sent, attributes whose computed value is nil are left unset.
+type {{ .TypeName }}Computer interface {
+{{ range .Fields }}	// {{ .FieldName }} computes the value of the {{ printf "%q" .Name }} attribute.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+	{{ .FieldName }}(ctx context.Context, r *{{ $typeName }}) ({{ .Type }}, error)
+{{ end }}}
+
+// Use{{ .TypeName }}Computer registers the implementation of {{ .TypeName }}Computer with the service.
+func Use{{ .TypeName }}Computer(service *goa.Service, c {{ .TypeName }}Computer) {
+	service.Context = context.WithValue(service.Context, computedKey({{ printf "%q" .TypeName }}), c)
+}
+
+// compute{{ .TypeName }} returns a copy of r with the computed attributes set by the
+// {{ .TypeName }}Computer registered with the service if any.
+func compute{{ .TypeName }}(ctx context.Context, r *{{ .TypeName }}) (*{{ .TypeName }}, error) {
+	c, ok := ctx.Value(computedKey({{ printf "%q" .TypeName }})).({{ .TypeName }}Computer)
+	if !ok || r == nil {
+		return r, nil
+	}
+	res := *r
+{{ range .Fields }}	v{{ .FieldName }}, err := c.{{ .FieldName }}(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	{{ .Assign }}
+{{ end }}	return &res, nil
+}
+{{ end }}`
+)
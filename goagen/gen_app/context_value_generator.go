@@ -0,0 +1,76 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateContextValues generates the typed accessors and middleware for the values stored in the
+// request context.
+func (g *Generator) generateContextValues() error {
+	if len(g.API.ContextValues) == 0 {
+		return nil
+	}
+
+	cvFile := filepath.Join(g.OutDir, "context_values.go")
+	file, err := codegen.SourceFileFor(cvFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Context Values", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	g.genfiles = append(g.genfiles, cvFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("contextValues", contextValuesT, nil, g.API.ContextValues); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// contextValuesT generates the context value accessors and middleware.
+	// template input: []*design.ContextValueDefinition
+	contextValuesT = `// contextValueKey is the type used to store the context values in the request context.
+type contextValueKey string
+{{ range . }}{{ $name := goify .Name true }}{{ $type := gotyperef .Type nil 0 false }}
+// With{{ $name }} returns a copy of ctx that holds the {{ printf "%q" .Name }} context value.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func With{{ $name }}(ctx context.Context, v {{ $type }}) context.Context {
+	return context.WithValue(ctx, contextValueKey({{ printf "%q" .Name }}), v)
+}
+
+// Context{{ $name }} returns the {{ printf "%q" .Name }} context value held by ctx, ok is false if ctx does not
+// hold the value.
+func Context{{ $name }}(ctx context.Context) (v {{ $type }}, ok bool) {
+	v, ok = ctx.Value(contextValueKey({{ printf "%q" .Name }})).({{ $type }})
+	return
+}
+
+// {{ $name }}Resolver is the function that computes the {{ printf "%q" .Name }} context value of a request.
+type {{ $name }}Resolver func(ctx context.Context, req *http.Request) ({{ $type }}, error)
+
+// New{{ $name }}Middleware returns a middleware that stores the {{ printf "%q" .Name }} context value
+// computed by resolve in the request context.
+func New{{ $name }}Middleware(resolve {{ $name }}Resolver) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			v, err := resolve(ctx, req)
+			if err != nil {
+				return err
+			}
+			return h(With{{ $name }}(ctx, v), rw, req)
+		}
+	}
+}
+{{ end }}`
+)
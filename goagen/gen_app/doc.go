@@ -1,5 +1,110 @@
 /*
 Package genapp provides the generator for the handlers, context data structures and tests of a goa
 application. It generates the glue between user code and the low level router.
+
+Unless test generation is disabled the generator also produces Go fuzz targets (requires Go 1.18)
+for the request decoding code of each action and the unmarshaling and validation code of each media
+type. The fuzz corpus is seeded with the design examples.
+
+Test generation also writes a golden JSON fixture for each media type view under testdata/wire,
+built from the design examples, and a wire_test.go file asserting that decoding each fixture into
+the generated type and encoding it back reproduces the fixture. The tests compare the decoded
+JSON values so that the order of the object fields does not matter. Since the fixtures only depend
+on the design, the tests fail when a change to the generated code alters the wire format.
+
+Designs that define interceptors get typed interceptors: for each interceptor the generator produces
+structs giving access to the payload and result attributes the interceptor reads and writes and a
+Use<Interceptor>Interceptor function that registers the implementation with the service. The
+generated controllers run the registered interceptors around the action handlers.
+
+Views that define computed attributes get a <MediaType>Computer interface with one method per
+computed attribute and a Use<MediaType>Computer function that registers its implementation with the
+service. The response helpers call the registered computer to set the computed attributes before
+sending the response.
+
+Context values declared in the design get typed accessors (With<Value> and Context<Value>) and a
+middleware constructor that stores the value computed by a resolver function in the request context.
+The action contexts fail to initialize if a value expected by the action is missing.
+
+Multi-tenant APIs get a TenantMiddleware that extracts the tenant identifier from the request header
+or path and stores it in the request context where ContextTenant retrieves it.
+
+APIs that enable request IDs get a RequestIDMiddleware that reads or generates the request ID,
+stores it in the request context and writes it to the response header. The generated error
+responses include the request ID in their "request_id" meta.
+
+APIs that enable method override wrap the service mux in initService so that POST requests tunneling
+PUT, PATCH or DELETE in the override header or form field are routed using that method.
+
+Actions of resources that enable compression wrap their handlers with the compress middleware. The
+payload unmarshal functions of resources that enable decompression decode gzip and deflate request
+bodies via goa.DecompressRequest before decoding the payload.
+
+Actions that define a cache policy wrap their handlers with the cache middleware. The server-side
+response cache is enabled by registering a store with cache.UseStore. The responses of secured
+actions are only cached by credentials, the responses of multi-tenant APIs by tenant, and the scope
+and role checks and audit events of cached actions run outside of the cache. Actions that define a
+concurrency budget wrap their handlers with the middleware.ConcurrencyLimit middleware.
+
+Actions of resources that enable request capture wrap their handlers with the capture middleware
+configured to mask the values of the sensitive attributes, headers and parameters.
+
+APIs that declare a maintenance response wrap all the action handlers with the middleware returned
+by the Handle method of the service maintenance switch so that the actions answer with the
+maintenance response while put in maintenance via goa.Service.Maintenance.
+
+Actions gated by a feature flag wrap their handlers with the feature.Gate middleware which sends
+the NotFound or Forbidden response of the action while the flag is off in the provider registered
+with feature.UseProvider.
+
+The contexts of actions that define a streaming payload expose a Stream field whose Recv method
+decodes and validates the payload elements one at a time using goa.PayloadStream.
+
+Responses that define trailers get a <Action><Resource><Response>Trailers struct and context
+methods that announce the trailers in the Trailer header (called by the response helpers) and set
+their values once the response body has been written.
+
+The contexts of downloadable actions expose a Download method that sends a file using
+goa.ServeDownload: it honors Range requests, sets the Content-Disposition header from the action
+parameter given to FilenameFrom and issues the resume token clients use to resume downloads.
+
+Resumable upload actions mount the upload.Resumable middleware on their routes as well as on the
+HEAD and PATCH routes that receive the upload chunks. Their contexts expose the completed upload in
+a <Action><Resource>Upload struct whose fields hold the upload metadata. Presigned upload actions
+mount the upload.Presigned middleware on their routes and on the completion callback routes, their
+contexts expose the uploaded object in the Upload field.
+
+Audited actions emit an AuditEvent after each call to the AuditEmitter registered with
+UseAuditEmitter. The event identifies the caller, the accessed resources and includes a snapshot of
+the request payload where the redacted attributes are masked.
+
+Actions that emit events publish a CloudEvents event to the publisher registered with
+cloudevents.UsePublisher once the action handler returns successfully. The event data is the
+result rendered by the handler when the response uses a media type.
+
+Actions that use the transactional outbox run in a transaction of the store registered with
+outbox.UseStore, their contexts expose it in the Outbox field. Their events are recorded in the
+transaction which commits before the response is sent, an outbox.Relay publishes them afterwards.
+
+Each workflow produces a typed state machine in workflows.go: the <Workflow>Steps interface lists
+the hooks that run the steps and undo them, New<Workflow>Workflow wraps the implementation in a
+workflow.Workflow that calls the compensation hooks in reverse order when a step fails.
+
+APIs that define a JSON-RPC endpoint get a MountJSONRPC function that mounts a jsonrpc.Server
+dispatching the calls to the actions through the service mux. The server maps the codes of the
+declared errors to JSON-RPC server error codes. MountJSONRPC returns the server so that the
+maximum request body length and batch size may be configured.
+
+Actions bound to MQTT topics are subscribed by the generated SubscribeMQTT function: the messages
+published to the topics are sent to the actions through the service mux by mqtt.Subscribe. Actions
+that define a result topic get a Publish<Action><Resource>Result function that publishes a
+result to the topic built from the given parameters.
+
+APIs with scheduled actions get a NewScheduler function that returns a cron.Runner calling each
+scheduled action through the service mux on its schedule.
+
+Actions that require security scopes or roles check them against the claims recorded by the auth
+middleware (see goa.Authorize) before calling the action handler. Requests lacking a scope or role
+are rejected using the action 403 response when it has no body or uses an error media type.
 */
 package genapp
@@ -0,0 +1,51 @@
+package genapp
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// downloadFilename returns the code that sets the file name of the download held by "info" from
+// the action parameter, the empty string if the download does not list a file name.
+func downloadFilename(data *ContextTemplateData) string {
+	d := data.Download
+	if d == nil || d.Filename == "" || data.Params == nil {
+		return ""
+	}
+	att, ok := data.Params.Type.ToObject()[d.Filename]
+	if !ok {
+		return ""
+	}
+	field := "ctx." + codegen.GoifyAtt(att, d.Filename, true)
+	if data.Params.IsPrimitivePointer(d.Filename) {
+		return fmt.Sprintf("\tif %s != nil {\n\t\tinfo.Filename = *%s\n\t}\n", field, field)
+	}
+	return fmt.Sprintf("\tinfo.Filename = %s\n", field)
+}
+
+// downloadContentType returns the content type of the file sent by the download, the empty
+// string if it must be inferred.
+func downloadContentType(d *design.DownloadDefinition) string {
+	if resp := d.Response(); resp != nil {
+		return resp.MediaType
+	}
+	return ""
+}
+
+const (
+	// ctxDownloadT generates the Download method of the contexts of downloadable actions.
+	// template input: *ContextTemplateData
+	ctxDownloadT = `
+// Download sends content as the file downloaded by the {{ .ResourceName }} {{ .ActionName }} action. It sends the
+// requested part of the file with status code 206 for Range requests, sets the Content-Disposition
+// header and the resume token clients send back to resume interrupted downloads. modtime is the
+// last modification time of the file, it may be zero if unknown in which case the resume token is
+// derived from a hash of the content.
+func (ctx *{{ .Name }}) Download(content io.ReadSeeker, modtime time.Time) error {
+	info := goa.DownloadInfo{ {{- with downloadContentType .Download }}ContentType: "{{ . }}", {{ end }}{{ if .Download.Inline }}Inline: true, {{ end }}ModTime: modtime}
+{{ downloadFilename . }}	return goa.ServeDownload(ctx.ResponseData, ctx.RequestData, content, info)
+}
+`
+)
@@ -64,13 +64,10 @@ func BuildEncoders(info []*design.EncodingDefinition, encoder bool) ([]*EncoderT
 // Also we assume that the encoding definitions have been validated: they have at least
 // one mime type and definitions with no package path use known encoders.
 func normalizeEncodingDefinitions(defs []*design.EncodingDefinition) []*design.EncodingDefinition {
-	// First splat all definitions so each only have one mime type
+	// First splat all definitions so each only have one mime type, the definitions are
+	// copied so that the design is left untouched.
 	var encs []*design.EncodingDefinition
 	for _, enc := range defs {
-		if len(enc.MIMETypes) == 1 {
-			encs = append(encs, enc)
-			continue
-		}
 		for _, m := range enc.MIMETypes {
 			encs = append(encs, &design.EncodingDefinition{
 				MIMETypes:   []string{m},
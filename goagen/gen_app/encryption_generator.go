@@ -0,0 +1,102 @@
+package genapp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// cipherFields returns the statements that encrypt or decrypt the encrypted top level attributes
+// of the struct held by the given variable. fn is the goa function called on each field:
+// "EncryptField" or "DecryptField". The struct is copied first so that the caller's value is left
+// untouched. The statements return the error produced by the cipher if any.
+func cipherFields(att *design.AttributeDefinition, fn, ctxVar, target, indent string) string {
+	names := att.EncryptedAttributes()
+	if len(names) == 0 {
+		return ""
+	}
+	if ds, ok := att.Type.(design.DataStructure); ok {
+		att = ds.Definition()
+	}
+	obj := att.Type.ToObject()
+	code := fmt.Sprintf("%sif %s != nil {\n", indent, target)
+	code += fmt.Sprintf("%s\tc := *%s\n", indent, target)
+	for _, n := range names {
+		field := codegen.GoifyAtt(obj[n], n, true)
+		alias := obj[n].EncryptionKey()
+		if att.IsPrimitivePointer(n) {
+			code += fmt.Sprintf("%s\tif c.%s != nil {\n", indent, field)
+			code += fmt.Sprintf("%s\t\tv := *c.%s\n", indent, field)
+			code += fmt.Sprintf("%s\t\tif err := goa.%s(%s, %q, &v); err != nil {\n", indent, fn, ctxVar, alias)
+			code += fmt.Sprintf("%s\t\t\treturn err\n%s\t\t}\n", indent, indent)
+			code += fmt.Sprintf("%s\t\tc.%s = &v\n%s\t}\n", indent, field, indent)
+			continue
+		}
+		if att.IsNullable(n) {
+			code += fmt.Sprintf("%s\tif c.%s.Valid {\n", indent, field)
+			code += fmt.Sprintf("%s\t\tif err := goa.%s(%s, %q, &c.%s.String); err != nil {\n", indent, fn, ctxVar, alias, field)
+			code += fmt.Sprintf("%s\t\t\treturn err\n%s\t\t}\n%s\t}\n", indent, indent, indent)
+			continue
+		}
+		code += fmt.Sprintf("%s\tif err := goa.%s(%s, %q, &c.%s); err != nil {\n", indent, fn, ctxVar, alias, field)
+		code += fmt.Sprintf("%s\t\treturn err\n%s\t}\n", indent, indent)
+	}
+	code += fmt.Sprintf("%s\t%s = &c\n%s}\n", indent, target, indent)
+	return code
+}
+
+// encryptResult returns the statements that encrypt the encrypted attributes of the response media
+// type held by the "r" variable before it is sent. Collections and envelopes are copied and their
+// elements encrypted.
+func encryptResult(projected *design.MediaTypeDefinition) string {
+	if elem := projected.EnvelopeElem(); elem != nil {
+		return envelopeItems(projected, cipherFields(elem.AttributeDefinition, "EncryptField", "ctx.Context", "e", "\t\t\t"))
+	}
+	if projected.IsObject() {
+		return cipherFields(projected.AttributeDefinition, "EncryptField", "ctx.Context", "r", "\t")
+	}
+	if !projected.IsArray() {
+		return ""
+	}
+	elem := projected.ToArray().ElemType
+	stmts := cipherFields(elem, "EncryptField", "ctx.Context", "e", "\t\t")
+	if stmts == "" {
+		return ""
+	}
+	code := "\tif r != nil {\n"
+	code += fmt.Sprintf("\t\trc := make(%s, len(r))\n", codegen.GoTypeRef(projected, projected.AllRequired(), 0, false))
+	code += "\t\tfor i, e := range r {\n"
+	code += indentLines(stmts)
+	code += "\t\t\trc[i] = e\n\t\t}\n\t\tr = rc\n\t}\n"
+	return code
+}
+
+// envelopeItems returns the statements that copy the envelope held by the "r" variable and apply
+// stmts to each element of its items held by the "e" variable. It returns the empty string if
+// stmts is empty.
+func envelopeItems(projected *design.MediaTypeDefinition, stmts string) string {
+	if stmts == "" {
+		return ""
+	}
+	items := projected.Type.ToObject()[design.EnvelopeItems]
+	field := codegen.GoifyAtt(items, design.EnvelopeItems, true)
+	code := "\tif r != nil {\n\t\trc := *r\n"
+	code += fmt.Sprintf("\t\trc.%s = make(%s, len(r.%s))\n", field, codegen.GoTypeRef(items.Type, nil, 0, false), field)
+	code += fmt.Sprintf("\t\tfor i, e := range r.%s {\n", field)
+	code += stmts
+	code += fmt.Sprintf("\t\t\trc.%s[i] = e\n\t\t}\n\t\tr = &rc\n\t}\n", field)
+	return code
+}
+
+// indentLines adds a tab at the beginning of each line of the given code.
+func indentLines(code string) string {
+	lines := strings.SplitAfter(code, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = "\t" + l
+		}
+	}
+	return strings.Join(lines, "")
+}
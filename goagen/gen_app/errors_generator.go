@@ -0,0 +1,80 @@
+package genapp
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ErrorTemplateData contains the information required to generate a sentinel error and to map it
+// to an action response.
+type ErrorTemplateData struct {
+	Name        string // Design name of the error, used as the error response code
+	VarName     string // Name of the generated variable
+	Description string // Description of the error
+	Response    string // Name of the response helper
+	Status      int    // Status of the response
+	Body        bool   // Whether the response renders the error media type
+}
+
+// errorsData returns the data used to generate the sentinel errors.
+func errorsData(errs []*design.ErrorDefinition) []*ErrorTemplateData {
+	data := make([]*ErrorTemplateData, len(errs))
+	for i, e := range errs {
+		data[i] = &ErrorTemplateData{
+			Name:        e.Name,
+			VarName:     errorVarName(e),
+			Description: e.Description,
+			Response:    codegen.Goify(e.Response, true),
+		}
+	}
+	return data
+}
+
+// mappedErrorsData returns the data used to map the errors returned by the action to its
+// responses.
+func mappedErrorsData(a *design.ActionDefinition) []*ErrorTemplateData {
+	errs := a.MappedErrors()
+	data := errorsData(errs)
+	for i, e := range errs {
+		resp := a.Responses[e.Response]
+		data[i].Status = resp.Status
+		data[i].Body = resp.Type != nil || resp.MediaType != ""
+	}
+	return data
+}
+
+// errorVarName returns the name of the variable holding the sentinel error: ErrXxx for API errors
+// and ErrResourceXxx for resource errors.
+func errorVarName(e *design.ErrorDefinition) string {
+	if r, ok := e.Parent.(*design.ResourceDefinition); ok {
+		return "Err" + codegen.Goify(r.Name, true) + codegen.Goify(e.Name, true)
+	}
+	return "Err" + codegen.Goify(e.Name, true)
+}
+
+const (
+	// errorsT generates the sentinel errors.
+	// template input: []*ErrorTemplateData
+	errorsT = `
+var (
+{{ range . }}	// {{ .VarName }} is returned by the actions to send {{ .Response }} responses.{{ if .Description }}
+	// {{ .Description }}{{ end }}
+	{{ .VarName }} = errors.New({{ printf "%q" .Name }})
+{{ end }})
+`
+
+	// mapErrorsT generates the functions that map the errors returned by the actions to their
+	// responses.
+	// template input: *ControllerTemplateData
+	mapErrorsT = `{{ range .Actions }}{{ if .Errors }}
+// handle{{ .Context }}Error sends the response that corresponds to the error returned by the
+// {{ .Name }} action if it matches one of the declared errors. It returns the error as is otherwise.
+func handle{{ .Context }}Error(rctx *{{ .Context }}, err error) error {
+	switch {
+{{ range .Errors }}	case errors.Is(err, {{ .VarName }}):
+		return rctx.{{ .Response }}({{ if .Body }}goa.NewErrorClass({{ printf "%q" .Name }}, {{ .Status }})(err){{ end }})
+{{ end }}	}
+	return err
+}
+{{ end }}{{ end }}`
+)
@@ -0,0 +1,92 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ActionEventTemplateData holds the data needed to generate the function that publishes the
+// CloudEvents of an action.
+type ActionEventTemplateData struct {
+	Context      string // Name of action context type
+	ResourceName string // Name of resource
+	ActionName   string // Name of action
+	Type         string // CloudEvents type
+	Source       string // CloudEvents source
+	Outbox       bool   // Whether the events are recorded in the request outbox transaction
+}
+
+// generateEvents generates the functions that publish the CloudEvents of the actions that emit
+// events.
+func (g *Generator) generateEvents() error {
+	var actions []*ActionEventTemplateData
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.AllEvent() == nil {
+				return nil
+			}
+			actions = append(actions, &ActionEventTemplateData{
+				Context:      codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Type:         a.EventType(),
+				Source:       "/" + g.API.Name,
+				Outbox:       a.UsesOutbox(),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+
+	eventsFile := filepath.Join(g.OutDir, "events.go")
+	file, err := codegen.SourceFileFor(eventsFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Events", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa/cloudevents"),
+	}
+	g.genfiles = append(g.genfiles, eventsFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("events", eventsT, nil, actions); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// eventsT generates the functions that publish the action events or record them in the
+	// outbox transaction of the request.
+	// template input: []*ActionEventTemplateData
+	eventsT = `{{ range . }}{{ if .Outbox }}
+// emit{{ .Context }} records the {{ printf "%q" .Type }} event in the outbox transaction of the request
+// after a successful call to the {{ .ResourceName }} {{ .ActionName }} action. The event data is the
+// result rendered by the action response if any. It returns the error that fails the request.
+func emit{{ .Context }}(ctx *{{ .Context }}, err error) error {
+	if err != nil || ctx.ResponseData.Status >= 400 {
+		return err
+	}
+	return ctx.Outbox.Record(ctx, cloudevents.New({{ printf "%q" .Type }}, {{ printf "%q" .Source }}, ctx.RequestData.URL.Path, ctx.eventData))
+}
+{{ else }}
+// emit{{ .Context }} publishes the {{ printf "%q" .Type }} event after a successful call to the {{ .ResourceName }}
+// {{ .ActionName }} action. The event data is the result rendered by the action response if any.
+func emit{{ .Context }}(ctx *{{ .Context }}, err error) {
+	if err != nil || ctx.ResponseData.Status >= 400 {
+		return
+	}
+	cloudevents.Publish(ctx, cloudevents.New({{ printf "%q" .Type }}, {{ printf "%q" .Source }}, ctx.RequestData.URL.Path, ctx.eventData))
+}
+{{ end }}{{ end }}`
+)
@@ -0,0 +1,224 @@
+package genapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// FuzzAction holds the data needed to generate the fuzz target of an action.
+type FuzzAction struct {
+	Name         string // Fuzz target name
+	ResourceName string // Name of resource
+	ActionName   string // Name of action
+	Context      string // Name of action context type
+	Unmarshal    string // Name of payload unmarshal function, empty if action has no payload
+	Verb         string // HTTP method of first action route
+	Path         string // Full path of first action route
+	ContentType  string // Content type used to send the request body
+	BodySeed     string // Go literal of body seeded from the design example
+	ParamsSeed   string // Go literal of query string seeded from the design examples
+}
+
+// FuzzMediaType holds the data needed to generate the fuzz target of a media type view.
+type FuzzMediaType struct {
+	Name     string // Fuzz target name
+	TypeName string // Go type name of media type view
+	Validate bool   // Whether the media type view has a Validate method
+	Seed     string // Go literal of JSON seeded from the design example
+}
+
+// generateFuzzTargets generates the Go fuzz targets exercising the request decoding code of each
+// action and the unmarshaling and validation code of each media type.
+func (g *Generator) generateFuzzTargets() error {
+	var (
+		actions    []*FuzzAction
+		mediaTypes []*FuzzMediaType
+		rand       = design.NewRandomGenerator(g.API.Name)
+	)
+	contentType := ""
+	if len(g.API.Consumes) > 0 && len(g.API.Consumes[0].MIMETypes) > 0 {
+		contentType = g.API.Consumes[0].MIMETypes[0]
+	}
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 || a.WebSocket() {
+				return nil
+			}
+			name := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true)
+			fa := &FuzzAction{
+				Name:         "Fuzz" + name,
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Context:      name + "Context",
+				Verb:         a.Routes[0].Verb,
+				Path:         a.Routes[0].FullPath(),
+				ContentType:  contentType,
+				BodySeed:     strconv.Quote(""),
+				ParamsSeed:   strconv.Quote(paramsSeed(a.AllParams(), rand)),
+			}
+			if a.Payload != nil {
+				fa.Unmarshal = "unmarshal" + name + "Payload"
+				fa.BodySeed = jsonSeed(a.Payload.AttributeDefinition, rand)
+			}
+			actions = append(actions, fa)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsError() || !(mt.Type.IsObject() || mt.Type.IsArray()) {
+			return nil
+		}
+		return mt.IterateViews(func(view *design.ViewDefinition) error {
+			p, _, err := mt.Project(view.Name)
+			if err != nil {
+				return err
+			}
+			typeName := codegen.GoTypeName(p, p.AllRequired(), 0, false)
+			mediaTypes = append(mediaTypes, &FuzzMediaType{
+				Name:     "Fuzz" + typeName,
+				TypeName: typeName,
+				Validate: codegen.RecursiveChecker(p.AttributeDefinition, false, false, false, "mt", "response", 1, false) != "",
+				Seed:     jsonSeed(p.AttributeDefinition, rand),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 && len(mediaTypes) == 0 {
+		return nil
+	}
+
+	fuzzFile := filepath.Join(g.OutDir, "fuzz_test.go")
+	file, err := codegen.SourceFileFor(fuzzFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Fuzz Targets", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("log"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("testing"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+	}
+	g.genfiles = append(g.genfiles, fuzzFile)
+	// Fuzzing requires Go 1.18 or later
+	file.Write([]byte("// +build go1.18\n\n"))
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Actions":    actions,
+		"MediaTypes": mediaTypes,
+	}
+	if err := file.ExecuteTemplate("fuzz", fuzzT, nil, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// jsonSeed returns the Go literal of the JSON encoding of the example for the given attribute.
+func jsonSeed(att *design.AttributeDefinition, rand *design.RandomGenerator) string {
+	example := att.GenerateExample(rand, nil)
+	if example == nil || example == "-" {
+		return strconv.Quote("")
+	}
+	b, err := json.Marshal(example)
+	if err != nil {
+		return strconv.Quote("")
+	}
+	return strconv.Quote(string(b))
+}
+
+// paramsSeed returns the query string built from the examples of the given params.
+func paramsSeed(params *design.AttributeDefinition, rand *design.RandomGenerator) string {
+	if params == nil {
+		return ""
+	}
+	obj := params.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	values := url.Values{}
+	for _, n := range names {
+		switch example := obj[n].GenerateExample(rand, nil).(type) {
+		case nil:
+		case []interface{}:
+			for _, e := range example {
+				values.Add(n, fmt.Sprintf("%v", e))
+			}
+		default:
+			if example != "-" {
+				values.Add(n, fmt.Sprintf("%v", example))
+			}
+		}
+	}
+	return values.Encode()
+}
+
+const (
+	// fuzzT generates the fuzz targets.
+	// template input: map[string]interface{}
+	fuzzT = `{{ if .Actions }}// fuzzService returns a service that uses the application encoders and decoders and discards
+// its logs.
+func fuzzService() *goa.Service {
+	service := goa.New("fuzz")
+	service.WithLogger(goa.NewLogger(log.New(ioutil.Discard, "", 0)))
+	initService(service)
+	return service
+}
+{{ end }}{{ range .Actions }}
+// {{ .Name }} feeds arbitrary request bodies and parameters to the decoding and validation code of
+// the {{ .ResourceName }} {{ .ActionName }} action.
+func {{ .Name }}(f *testing.F) {
+	f.Add([]byte({{ .BodySeed }}), {{ .ParamsSeed }})
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		params, err := url.ParseQuery(query)
+		if err != nil {
+			t.Skip()
+		}
+		req, err := http.NewRequest("{{ .Verb }}", "{{ .Path }}", bytes.NewReader(body))
+		if err != nil {
+			t.Skip()
+		}
+{{ if .ContentType }}		req.Header.Set("Content-Type", "{{ .ContentType }}")
+{{ end }}		service := fuzzService()
+		ctx := goa.NewContext(context.Background(), httptest.NewRecorder(), req, params)
+		New{{ .Context }}(ctx, service)
+{{ if .Unmarshal }}		{{ .Unmarshal }}(ctx, service, req)
+{{ end }}	})
+}
+{{ end }}{{ range .MediaTypes }}
+// {{ .Name }} feeds arbitrary JSON documents to the unmarshaling and validation code of the
+// {{ .TypeName }} media type.
+func {{ .Name }}(f *testing.F) {
+	f.Add([]byte({{ .Seed }}))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var mt {{ .TypeName }}
+		if err := json.Unmarshal(data, &mt); err != nil {
+			return
+		}
+{{ if .Validate }}		mt.Validate()
+{{ end }}	})
+}
+{{ end }}`
+)
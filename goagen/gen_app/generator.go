@@ -3,7 +3,9 @@ package genapp
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 
@@ -14,23 +16,25 @@ import (
 
 // Generator is the application code generator.
 type Generator struct {
-	API      *design.APIDefinition // The API definition
-	OutDir   string                // Path to output directory
-	Target   string                // Name of generated package
-	NoTest   bool                  // Whether to skip test generation
-	genfiles []string              // Generated files
+	API        *design.APIDefinition // The API definition
+	OutDir     string                // Path to output directory
+	OutPkgPath string                // Import path of parent of output directory, computed from GOPATH or go.mod if empty
+	Target     string                // Name of generated package
+	NoTest     bool                  // Whether to skip test generation
+	genfiles   []string              // Generated files
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, target, ver string
-		notest              bool
+		outDir, outPkgPath, target, ver string
+		notest                          bool
 	)
 
 	set := flag.NewFlagSet("app", flag.PanicOnError)
 	set.String("design", "", "")
 	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&outPkgPath, "out-pkg-path", "", "")
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&notest, "notest", false, "")
@@ -42,7 +46,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design}
+	g := &Generator{OutDir: outDir, OutPkgPath: outPkgPath, Target: target, NoTest: notest, API: design.Design}
 
 	return g.Generate()
 }
@@ -78,6 +82,42 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateSecurity(); err != nil {
 		return nil, err
 	}
+	if err := g.generateInterceptors(); err != nil {
+		return nil, err
+	}
+	if err := g.generateComputed(); err != nil {
+		return nil, err
+	}
+	if err := g.generateContextValues(); err != nil {
+		return nil, err
+	}
+	if err := g.generateTenant(); err != nil {
+		return nil, err
+	}
+	if err := g.generateRequestID(); err != nil {
+		return nil, err
+	}
+	if err := g.generateRecover(); err != nil {
+		return nil, err
+	}
+	if err := g.generateAudit(); err != nil {
+		return nil, err
+	}
+	if err := g.generateEvents(); err != nil {
+		return nil, err
+	}
+	if err := g.generateWorkflows(); err != nil {
+		return nil, err
+	}
+	if err := g.generateJSONRPC(); err != nil {
+		return nil, err
+	}
+	if err := g.generateMQTT(); err != nil {
+		return nil, err
+	}
+	if err := g.generateSchedule(); err != nil {
+		return nil, err
+	}
 	if err := g.generateHrefs(); err != nil {
 		return nil, err
 	}
@@ -91,11 +131,25 @@ func (g *Generator) Generate() (_ []string, err error) {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
 		}
+		if err := g.generateFuzzTargets(); err != nil {
+			return nil, err
+		}
+		if err := g.generateWireFixtures(); err != nil {
+			return nil, err
+		}
 	}
 
 	return g.genfiles, nil
 }
 
+// PackagePath returns the import path of the generated package.
+func (g *Generator) PackagePath() (string, error) {
+	if g.OutPkgPath != "" {
+		return path.Join(g.OutPkgPath, filepath.Base(g.OutDir)), nil
+	}
+	return codegen.PackagePath(g.OutDir)
+}
+
 // Cleanup removes the entire "app" directory if it was created by this generator.
 func (g *Generator) Cleanup() {
 	if len(g.genfiles) == 0 {
@@ -117,13 +171,18 @@ func (g *Generator) generateContexts() error {
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("io"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/upload"),
+		codegen.SimpleImport("github.com/goadesign/goa/outbox"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	g.genfiles = append(g.genfiles, ctxFile)
 	ctxWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
@@ -144,18 +203,37 @@ func (g *Generator) generateContexts() error {
 					non101[k] = v
 				}
 			}
+			var interceptors []*InterceptorTemplateData
+			for _, i := range a.AllInterceptors() {
+				interceptors = append(interceptors, interceptorData(g.API, i))
+			}
 			ctxData := ContextTemplateData{
-				Name:         ctxName,
-				ResourceName: r.Name,
-				ActionName:   a.Name,
-				Payload:      a.Payload,
-				Params:       params,
-				Headers:      headers,
-				Routes:       a.Routes,
-				Responses:    non101,
-				API:          g.API,
-				DefaultPkg:   g.Target,
-				Security:     a.Security,
+				Name:          ctxName,
+				ResourceName:  r.Name,
+				ActionName:    a.Name,
+				Payload:       a.Payload,
+				StreamElement: a.StreamingPayload,
+				Params:        params,
+				Headers:       headers,
+				Routes:        a.Routes,
+				Responses:     non101,
+				API:           g.API,
+				DefaultPkg:    g.Target,
+				Security:      a.Security,
+				Interceptors:  interceptors,
+				ContextValues: a.AllContextValues(),
+				Download:      a.Download,
+				Evented:       a.AllEvent() != nil,
+				Outbox:        a.UsesOutbox(),
+			}
+			if a.Upload != nil {
+				ctxData.Upload = fmt.Sprintf("%s%sUpload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+				ctxData.UploadDef = a.Upload
+			}
+			ctxData.Presigned = a.PresignedUpload != nil
+			if a.StreamingPayload != nil {
+				ctxData.Stream = fmt.Sprintf("%s%sStream", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+				ctxData.Decompression = r.AllDecompression()
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -176,12 +254,21 @@ func (g *Generator) generateControllers() error {
 	}
 	title := fmt.Sprintf("%s: Application Controllers", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("errors"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/cache"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/capture"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/compress"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/feature"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/upload"),
+		codegen.SimpleImport("github.com/goadesign/goa/outbox"),
 		codegen.SimpleImport("regexp"),
+		codegen.SimpleImport("time"),
 	}
 	encoders, err := BuildEncoders(g.API.Produces, true)
 	if err != nil {
@@ -210,6 +297,11 @@ func (g *Generator) generateControllers() error {
 	}
 	ctlWr.WriteHeader(title, g.Target, imports)
 	ctlWr.WriteInitService(encoders, decoders)
+	if len(g.API.Errors) > 0 {
+		if err := ctlWr.WriteErrors(errorsData(g.API.Errors)); err != nil {
+			return err
+		}
+	}
 
 	var controllersData []*ControllerTemplateData
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
@@ -235,6 +327,7 @@ func (g *Generator) generateControllers() error {
 			Resource:       codegen.Goify(r.Name, true),
 			PreflightPaths: r.PreflightPaths(),
 			FileServers:    fileServers,
+			Errors:         errorsData(r.Errors),
 		}
 		ierr := r.IterateActions(func(a *design.ActionDefinition) error {
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
@@ -247,6 +340,67 @@ func (g *Generator) generateControllers() error {
 				"Payload":         a.Payload,
 				"PayloadOptional": a.PayloadOptional,
 				"Security":        a.Security,
+				"Intercepted":     len(a.AllInterceptors()) > 0,
+				"Audited":         a.Audit != nil,
+				"Evented":         a.AllEvent() != nil,
+				"Outbox":          a.UsesOutbox(),
+				"HeadPaths":       a.AutoHeadPaths(),
+				"Decompression":   r.AllDecompression(),
+				"Limits":          r.AllLimits(),
+				"Strict":          a.DisallowUnknownFields(),
+				"Concurrency":     a.AllConcurrency(),
+				"Errors":          mappedErrorsData(a),
+			}
+			if u := uploadData(a); u != nil {
+				action["Upload"] = u
+			}
+			if p := presignData(a); p != nil {
+				action["Presign"] = p
+			}
+			if c := r.AllCapture(); c != nil {
+				action["Capture"] = &CaptureTemplateData{
+					Resource:    r.Name,
+					Action:      a.Name,
+					MaxBodySize: c.MaxBodySize,
+					Payload:     a.SensitivePayloadPaths(),
+					Response:    a.SensitiveResponsePaths(),
+					Headers:     a.SensitiveHeaders(),
+					Params:      a.SensitiveParams(),
+				}
+			}
+			if m := g.API.Maintenance; m != nil {
+				action["Maintenance"] = &MaintenanceTemplateData{
+					Resource: r.Name,
+					Action:   a.Name,
+					Status:   m.Status,
+					Error:    m.MediaType != "",
+				}
+			}
+			if f := a.Flag; f != nil {
+				resp := f.Response()
+				action["Flag"] = &FlagTemplateData{
+					Name:   f.Name,
+					Status: resp.Status,
+					Error:  design.CanonicalIdentifier(resp.MediaType) == design.CanonicalIdentifier(design.ErrorMediaIdentifier),
+				}
+			}
+			if a.Payload != nil && a.Payload.IsObject() {
+				action["DecryptPayload"] = cipherFields(a.Payload.AttributeDefinition, "DecryptField", "ctx", "rctx.Payload", "\t\t")
+			}
+			if sec := a.Security; sec != nil && (len(sec.Scopes) > 0 || len(sec.Roles) > 0) {
+				forbidden, withErr := forbiddenResponse(a)
+				action["Authorized"] = true
+				action["Forbidden"] = forbidden
+				action["ForbiddenError"] = withErr
+			}
+			if c := a.AllCache(); c != nil {
+				cache := cacheData(g.API, a, c)
+				// Check the scopes and roles and emit the audit events outside of the cache
+				// so that they also apply to the cached responses.
+				cache.Authorized, _ = action["Authorized"].(bool)
+				cache.Audited = a.Audit != nil
+				action["Authorized"], action["Audited"] = false, false
+				action["Cache"] = cache
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -258,6 +412,13 @@ func (g *Generator) generateControllers() error {
 			data.Encoders = encoders
 			data.Decoders = decoders
 			data.Origins = r.AllOrigins()
+			data.Compression = r.AllCompression()
+			for _, p := range r.AutoOptionsPaths() {
+				data.OptionsPaths = append(data.OptionsPaths, &OptionsTemplateData{
+					Path:    p,
+					Methods: g.API.AllowedMethods(p),
+				})
+			}
 			controllersData = append(controllersData, data)
 		}
 		return nil
@@ -272,6 +433,58 @@ func (g *Generator) generateControllers() error {
 	return ctlWr.FormatCode()
 }
 
+// cacheData returns the cache policy of the action. The tenant header if any is added to the
+// headers the responses vary on so that the tenants never share cached responses.
+func cacheData(api *design.APIDefinition, a *design.ActionDefinition, c *design.CacheDefinition) *CacheTemplateData {
+	data := &CacheTemplateData{
+		MaxAge:               c.MaxAge,
+		StaleWhileRevalidate: c.StaleWhileRevalidate,
+		Vary:                 c.Vary,
+	}
+	if t := api.Tenant; t != nil && t.Header != "" {
+		found := false
+		for _, v := range c.Vary {
+			if http.CanonicalHeaderKey(v) == http.CanonicalHeaderKey(t.Header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			data.Vary = append(append([]string(nil), c.Vary...), t.Header)
+		}
+	}
+	if a.Security != nil {
+		data.Secured = true
+		data.Credential = a.Security.Scheme.CredentialHeader()
+	}
+	return data
+}
+
+// forbiddenResponse returns the name of the action response helper used to reject requests
+// lacking the required scopes or roles and whether the helper accepts the error. It returns an
+// empty name if the action does not define a 403 response with no body or an error media type.
+func forbiddenResponse(a *design.ActionDefinition) (string, bool) {
+	for _, resp := range a.Responses {
+		if resp.Status != 403 {
+			continue
+		}
+		name := codegen.Goify(resp.Name, true)
+		mt, ok := resp.Type.(*design.MediaTypeDefinition)
+		if resp.Type == nil {
+			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
+			ok = mt != nil
+		}
+		if ok {
+			if mt.IsError() {
+				return name, true
+			}
+		} else if resp.Type == nil && resp.MediaType == "" {
+			return name, false
+		}
+	}
+	return "", false
+}
+
 // generateControllers iterates through the API resources and generates the low level
 // controllers.
 func (g *Generator) generateSecurity() error {
@@ -287,10 +500,13 @@ func (g *Generator) generateSecurity() error {
 
 	title := fmt.Sprintf("%s: Application Security", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("errors"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/apikey"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/session"),
 	}
 	secWr.WriteHeader(title, g.Target, imports)
 
@@ -332,6 +548,13 @@ func (g *Generator) generateHrefs() error {
 			CanonicalTemplate: codegen.CanonicalTemplate(r),
 			CanonicalParams:   codegen.CanonicalParams(r),
 		}
+		if m != nil && m.Resource == r && data.CanonicalTemplate != "" {
+			hrefs, err := mediaTypeHrefs(m, r)
+			if err != nil {
+				return err
+			}
+			data.Hrefs = hrefs
+		}
 		return resWr.Execute(&data)
 	})
 	g.genfiles = append(g.genfiles, hrefFile)
@@ -341,6 +564,47 @@ func (g *Generator) generateHrefs() error {
 	return resWr.FormatCode()
 }
 
+// mediaTypeHrefs returns the data needed to generate the Href methods of the projections of the
+// canonical media type of r. The canonical parameters that match a primitive attribute of a
+// projection are read from the instance, the others are arguments of the method.
+func mediaTypeHrefs(m *design.MediaTypeDefinition, r *design.ResourceDefinition) ([]*MediaTypeHrefData, error) {
+	if !m.Type.IsObject() {
+		return nil, nil
+	}
+	params := r.CanonicalAction().Routes[0].Params()
+	var hrefs []*MediaTypeHrefData
+	err := m.IterateViews(func(v *design.ViewDefinition) error {
+		if v.Name == "link" {
+			return nil
+		}
+		p, _, err := m.Project(v.Name)
+		if err != nil {
+			return err
+		}
+		data := &MediaTypeHrefData{TypeName: codegen.GoTypeName(p, nil, 0, false)}
+		obj := p.Type.ToObject()
+		for _, n := range params {
+			arg := codegen.Goify(n, false)
+			att := obj[n]
+			if att == nil || !att.Type.IsPrimitive() || p.IsNullable(n) || p.HasPresence(n) {
+				data.Args = append(data.Args, arg)
+				data.Params = append(data.Params, arg)
+				continue
+			}
+			field := codegen.GoifyAtt(att, n, true)
+			if p.IsPrimitivePointer(n) {
+				data.Assigns = append(data.Assigns, fmt.Sprintf("\tvar %s interface{}\n\tif mt.%s != nil {\n\t\t%s = *mt.%s\n\t}", arg, field, arg, field))
+				data.Params = append(data.Params, arg)
+				continue
+			}
+			data.Params = append(data.Params, "mt."+field)
+		}
+		hrefs = append(hrefs, data)
+		return nil
+	})
+	return hrefs, err
+}
+
 // generateMediaTypes iterates through the media types and generate the data structures and
 // marshaling code.
 func (g *Generator) generateMediaTypes() error {
@@ -357,6 +621,7 @@ func (g *Generator) generateMediaTypes() error {
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	mtWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
 		if mt.IsError() {
@@ -386,11 +651,26 @@ func (g *Generator) generateUserTypes() error {
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	utWr.WriteHeader(title, g.Target, imports)
+	for _, s := range g.API.Scalars {
+		if !s.IsEnum() {
+			continue
+		}
+		if err = utWr.ExecuteEnum(s); err != nil {
+			return err
+		}
+	}
+	for _, f := range g.API.Flags {
+		if err = utWr.ExecuteFlags(f); err != nil {
+			return err
+		}
+	}
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -190,7 +190,7 @@ var _ = Describe("Generate", func() {
 
 			It("generates the corresponding code", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(8))
+				Ω(files).Should(HaveLen(9))
 
 				isSource("contexts.go", contextsCode)
 				isSource("controllers.go", controllersCode)
@@ -244,6 +244,879 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with an interceptor", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"user_id": &design.AttributeDefinition{Type: design.String},
+						},
+					},
+					TypeName: "GetWidgetPayload",
+				}
+				audit := &design.InterceptorDefinition{
+					Name:         "audit",
+					ReadPayload:  []string{"user_id"},
+					WritePayload: []string{"user_id"},
+				}
+				design.Design.Interceptors = []*design.InterceptorDefinition{audit}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+				design.Design.Resources["Widget"].Actions["get"].Interceptors = []*design.InterceptorDefinition{audit}
+			})
+
+			It("generates the typed interceptor", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "interceptors.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("type AuditInterceptor func(ctx context.Context, payload *AuditPayload, result *AuditResult, next func(context.Context) error) error"))
+				Ω(string(content)).Should(ContainSubstring("func UseAuditInterceptor(service *goa.Service, i AuditInterceptor)"))
+				Ω(string(content)).Should(ContainSubstring("payload.UserID = ctx.Payload.UserID"))
+				Ω(string(content)).Should(ContainSubstring("ctx.Payload.UserID = payload.UserID"))
+			})
+
+			It("runs the interceptor around the action handler", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("return interceptGetWidgetContext(rctx, ctrl.Get)"))
+			})
+		})
+
+		Context("with a context value", func() {
+			BeforeEach(func() {
+				locale := &design.ContextValueDefinition{Name: "locale", Type: design.String}
+				design.Design.ContextValues = []*design.ContextValueDefinition{locale}
+				design.Design.Resources["Widget"].Actions["get"].ContextValues = []*design.ContextValueDefinition{locale}
+			})
+
+			It("generates the typed accessors and middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "context_values.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func WithLocale(ctx context.Context, v string) context.Context"))
+				Ω(string(content)).Should(ContainSubstring("func ContextLocale(ctx context.Context) (v string, ok bool)"))
+				Ω(string(content)).Should(ContainSubstring("func NewLocaleMiddleware(resolve LocaleResolver) goa.Middleware"))
+			})
+
+			It("checks the value is set when creating the action context", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("if _, ok := ContextLocale(ctx); !ok {"))
+			})
+		})
+
+		Context("with a tenant header", func() {
+			BeforeEach(func() {
+				design.Design.Tenant = &design.TenantDefinition{
+					Header:    "X-Tenant-ID",
+					Attribute: &design.AttributeDefinition{Type: design.String},
+				}
+			})
+
+			It("generates the tenant middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "tenant.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`const TenantHeader = "X-Tenant-ID"`))
+				Ω(string(content)).Should(ContainSubstring("func ContextTenant(ctx context.Context) (tenant string, ok bool)"))
+				Ω(string(content)).Should(ContainSubstring("if tenant := req.Header.Get(TenantHeader); tenant != \"\" {"))
+			})
+		})
+
+		Context("with an audited action", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Audit = &design.AuditDefinition{Event: "widget.get", Parent: get}
+			})
+
+			It("generates the audit event emitter", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "audit.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func UseAuditEmitter(service *goa.Service, e AuditEmitter)"))
+				Ω(string(content)).Should(ContainSubstring("func auditGetWidgetContext(ctx *GetWidgetContext, err error)"))
+				Ω(string(content)).Should(ContainSubstring(`"id": ctx.RequestData.Params.Get("id"),`))
+			})
+
+			It("emits the audit event after the action handler", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("err = ctrl.Get(rctx)\n\t\tauditGetWidgetContext(rctx, err)\n\t\treturn err"))
+			})
+		})
+
+		Context("with an action emitting events", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Event = &design.EventDefinition{Parent: get}
+			})
+
+			It("generates the event publisher", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "events.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func emitGetWidgetContext(ctx *GetWidgetContext, err error)"))
+				Ω(string(content)).Should(ContainSubstring(`cloudevents.New("test api.widget.get", "/test api", ctx.RequestData.URL.Path, ctx.eventData)`))
+			})
+
+			It("records the result published in the events", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("eventData interface{}"))
+				Ω(string(content)).Should(ContainSubstring("ctx.eventData = r"))
+			})
+
+			It("publishes the event after the action handler", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("err = ctrl.Get(rctx)\n\t\temitGetWidgetContext(rctx, err)\n\t\treturn err"))
+			})
+		})
+
+		Context("with an action using the transactional outbox", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Event = &design.EventDefinition{Parent: get}
+				get.Metadata = dslengine.MetadataDefinition{design.OutboxMetadata: {}}
+			})
+
+			It("records the events in the outbox transaction", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "events.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func emitGetWidgetContext(ctx *GetWidgetContext, err error) error"))
+				Ω(string(content)).Should(ContainSubstring(`return ctx.Outbox.Record(ctx, cloudevents.New("test api.widget.get"`))
+			})
+
+			It("exposes the transaction in the context", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("Outbox outbox.Tx"))
+				Ω(string(content)).Should(ContainSubstring("rctx.Outbox = outbox.ContextTx(ctx)"))
+			})
+
+			It("runs the handler in a transaction", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("err = ctrl.Get(rctx)\n\t\terr = emitGetWidgetContext(rctx, err)\n\t\treturn err"))
+				Ω(string(content)).Should(ContainSubstring("h = outbox.Transactional()(h)"))
+			})
+		})
+
+		Context("with a workflow", func() {
+			BeforeEach(func() {
+				w := &design.WorkflowDefinition{Name: "widget_restock", Description: "Restocks widgets"}
+				w.Steps = []*design.WorkflowStepDefinition{
+					{Name: "reserve", Resource: "Widget", Action: "get", CompensationResource: "Widget", CompensationAction: "get", Parent: w},
+					{Name: "ship", Resource: "Widget", Action: "get", Parent: w},
+				}
+				design.Design.Workflows = []*design.WorkflowDefinition{w}
+			})
+
+			It("generates the workflow state machine", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "workflows.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`WidgetRestockReserve WidgetRestockState = "reserve"`))
+				Ω(string(content)).Should(ContainSubstring("WidgetRestockCompleted WidgetRestockState = workflow.StateCompleted"))
+				Ω(string(content)).Should(ContainSubstring("func NewWidgetRestockWorkflow(steps WidgetRestockSteps, onTransition func(from, to WidgetRestockState)) *WidgetRestockWorkflow"))
+			})
+
+			It("generates the step and compensation hooks", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "workflows.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("\tReserve(ctx context.Context) error\n"))
+				Ω(string(content)).Should(ContainSubstring("\tCompensateReserve(ctx context.Context) error\n"))
+				Ω(string(content)).ShouldNot(ContainSubstring("CompensateShip"))
+				Ω(string(content)).Should(ContainSubstring(`{Name: "reserve", Run: steps.Reserve, Compensate: steps.CompensateReserve},`))
+				Ω(string(content)).Should(ContainSubstring(`{Name: "ship", Run: steps.Ship},`))
+			})
+		})
+
+		Context("with a JSON-RPC endpoint", func() {
+			BeforeEach(func() {
+				design.Design.JSONRPC = &design.JSONRPCDefinition{Path: "/rpc"}
+				design.Design.Errors = []*design.ErrorDefinition{{Name: "not_found", Response: "NotFound", Parent: design.Design}}
+			})
+
+			It("generates the JSON-RPC endpoint", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "jsonrpc.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func MountJSONRPC(service *goa.Service) *jsonrpc.Server {"))
+				Ω(string(content)).Should(ContainSubstring("MaxBatchSize:         jsonrpc.DefaultMaxBatchSize,"))
+				Ω(string(content)).Should(ContainSubstring(`"Widget.get": {Verb: "GET", Path: "/:id", Params: []string{"id"}},`))
+				Ω(string(content)).Should(ContainSubstring(`"not_found": -32001,`))
+				Ω(string(content)).Should(ContainSubstring(`service.Mux.Handle("POST", "/rpc", server.MuxHandler())`))
+			})
+		})
+
+		Context("with an action bound to an MQTT topic", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.MQTT = &design.MQTTDefinition{Topic: "widgets/:id/get", QoS: 1, ResultTopic: "widgets/:id/result", Parent: get}
+			})
+
+			It("generates the subscription and the result publisher", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "mqtt.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func SubscribeMQTT(service *goa.Service, client mqtt.Client) error {"))
+				Ω(string(content)).Should(ContainSubstring(`{Topic: "widgets/:id/get", QoS: 1, ResultTopic: "widgets/:id/result", Verb: "GET", Path: "/:id"},`))
+				Ω(string(content)).Should(ContainSubstring("func PublishGetWidgetResult(client mqtt.Client, id string, result interface{}) error {"))
+				Ω(string(content)).Should(ContainSubstring(`"id": fmt.Sprint(id),`))
+			})
+		})
+
+		Context("with a scheduled action", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Schedule = &design.ScheduleDefinition{Spec: "*/5 * * * *", Parent: get}
+			})
+
+			It("registers the action with the cron runner", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "schedule.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func NewScheduler(service *goa.Service) *cron.Runner {"))
+				Ω(string(content)).Should(ContainSubstring(`if err := runner.Add("Widget.get", "*/5 * * * *", "GET", "/:id"); err != nil {`))
+			})
+		})
+
+		Context("with required scopes and roles", func() {
+			BeforeEach(func() {
+				scheme := &design.SecuritySchemeDefinition{
+					SchemeName: "jwt",
+					Kind:       design.JWTSecurityKind,
+					Type:       "apiKey",
+					In:         "header",
+					Name:       "Authorization",
+				}
+				design.Design.SecuritySchemes = []*design.SecuritySchemeDefinition{scheme}
+				design.Design.MediaTypes[design.CanonicalIdentifier(design.ErrorMediaIdentifier)] = design.ErrorMedia
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Security = &design.SecurityDefinition{
+					Scheme: scheme,
+					Scopes: []string{"read:widgets"},
+					Roles:  []string{"admin"},
+				}
+				get.Responses["Forbidden"] = &design.ResponseDefinition{
+					Name:      "Forbidden",
+					Status:    403,
+					MediaType: design.ErrorMediaIdentifier,
+				}
+			})
+
+			It("authorizes requests with the declared forbidden response", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`if err := goa.Authorize(ctx, []string{"read:widgets"}, []string{"admin"}); err != nil {`))
+				Ω(string(content)).Should(ContainSubstring("return rctx.Forbidden(err)"))
+			})
+		})
+
+		Context("with an API key security scheme", func() {
+			BeforeEach(func() {
+				design.Design.SecuritySchemes = []*design.SecuritySchemeDefinition{{
+					SchemeName: "api_key",
+					Kind:       design.APIKeySecurityKind,
+					Type:       "apiKey",
+					In:         "header",
+					Name:       "X-Api-Key",
+				}}
+			})
+
+			It("generates a middleware constructor backed by a key store", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "security.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func NewAPIKeyKeyStoreMiddleware(store apikey.KeyStore) goa.Middleware {"))
+				Ω(string(content)).Should(ContainSubstring("return apikey.New(store, NewAPIKeySecurity())"))
+			})
+		})
+
+		Context("with a session security scheme", func() {
+			BeforeEach(func() {
+				design.Design.SecuritySchemes = []*design.SecuritySchemeDefinition{{
+					SchemeName: "session",
+					Kind:       design.SessionSecurityKind,
+					Type:       "apiKey",
+					In:         "header",
+					Name:       "Cookie",
+					CookieName: "sid",
+				}}
+			})
+
+			It("generates the session middleware and helpers", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "security.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`CookieName: "sid",`))
+				Ω(string(content)).Should(ContainSubstring("func NewSessionSessionMiddleware(store session.Store) goa.Middleware {"))
+				Ω(string(content)).Should(ContainSubstring("func StartSessionSession(ctx context.Context, rw http.ResponseWriter, store session.Store, data map[string]interface{}) error {"))
+				Ω(string(content)).Should(ContainSubstring("func ContextSessionSession(ctx context.Context) (map[string]interface{}, error) {"))
+			})
+		})
+
+		Context("with an encrypted payload attribute", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"ssn": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{design.EncryptedMetadata: []string{"alias/pii"}},
+							},
+						},
+					},
+					TypeName: "GetWidgetPayload",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+			})
+
+			It("decrypts the attribute after reading the payload", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`goa.DecryptField(ctx, "alias/pii", `))
+			})
+		})
+
+		Context("with a view defining a computed attribute", func() {
+			BeforeEach(func() {
+				name := &design.AttributeDefinition{Type: design.String}
+				label := &design.AttributeDefinition{
+					Type:     design.String,
+					Metadata: dslengine.MetadataDefinition{design.ComputedMetadata: []string{}},
+				}
+				mt := &design.MediaTypeDefinition{
+					UserTypeDefinition: &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{"name": name},
+						},
+						TypeName: "Gadget",
+					},
+					Identifier: "application/vnd.gadget",
+				}
+				mt.Views = map[string]*design.ViewDefinition{"default": {
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"name": name, "label": label},
+					},
+					Name:   "default",
+					Parent: mt,
+				}}
+				design.Design.MediaTypes[mt.Identifier] = mt
+				design.Design.Resources["Widget"].Actions["get"].Responses["ok"].MediaType = mt.Identifier
+			})
+
+			It("generates the computer interface", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "computed.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("type GadgetComputer interface {"))
+				Ω(string(content)).Should(ContainSubstring("Label(ctx context.Context, r *Gadget) (*string, error)"))
+				Ω(string(content)).Should(ContainSubstring("func UseGadgetComputer(service *goa.Service, c GadgetComputer)"))
+				Ω(string(content)).Should(ContainSubstring("res.Label = vLabel"))
+			})
+
+			It("sets the computed attributes before sending the response", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("rc, err := computeGadget(ctx.Context, r)"))
+			})
+		})
+
+		Context("with a response defining a Location header", func() {
+			BeforeEach(func() {
+				id := &design.AttributeDefinition{Type: design.Integer}
+				mt := &design.MediaTypeDefinition{
+					UserTypeDefinition: &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type:       design.Object{"id": id},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
+						},
+						TypeName: "Gadget",
+					},
+					Identifier: "application/vnd.gadget",
+				}
+				mt.Views = map[string]*design.ViewDefinition{"default": {
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"id": id},
+					},
+					Name:   "default",
+					Parent: mt,
+				}}
+				design.Design.MediaTypes[mt.Identifier] = mt
+				resp := design.Design.Resources["Widget"].Actions["get"].Responses["ok"]
+				resp.Status = 201
+				resp.MediaType = mt.Identifier
+				resp.LocationHeader = "/gadgets/{id}"
+			})
+
+			It("sets the Location header before sending the response", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`ctx.ResponseData.Header().Set("Location", fmt.Sprintf("/gadgets/%v", r.ID))`))
+			})
+		})
+
+		Context("with a resource declaring an error", func() {
+			BeforeEach(func() {
+				res := design.Design.Resources["Widget"]
+				res.Errors = []*design.ErrorDefinition{{Name: "not_found", Response: "NotFound", Parent: res}}
+				design.Design.MediaTypes[design.CanonicalIdentifier(design.ErrorMediaIdentifier)] = design.ErrorMedia
+				res.Actions["get"].Responses["NotFound"] = &design.ResponseDefinition{
+					Name:      "NotFound",
+					Status:    404,
+					MediaType: design.ErrorMediaIdentifier,
+				}
+			})
+
+			It("generates the sentinel error and maps it to the response", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`ErrWidgetNotFound = errors.New("not_found")`))
+				Ω(string(content)).Should(ContainSubstring("return handleGetWidgetContextError(rctx, ctrl.Get(rctx))"))
+				Ω(string(content)).Should(ContainSubstring("case errors.Is(err, ErrWidgetNotFound):"))
+				Ω(string(content)).Should(ContainSubstring(`return rctx.NotFound(goa.NewErrorClass("not_found", 404)(err))`))
+			})
+		})
+
+		Context("with an API response with status code 500", func() {
+			BeforeEach(func() {
+				design.Design.Responses = map[string]*design.ResponseDefinition{
+					"InternalServerError": {
+						Name:      "InternalServerError",
+						Status:    500,
+						MediaType: design.ErrorMediaIdentifier,
+					},
+				}
+			})
+
+			It("generates the recovery middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "recover.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func RecoverMiddleware(service *goa.Service, reporter middleware.CrashReporter) goa.Middleware {"))
+				Ω(string(content)).Should(ContainSubstring(`return middleware.RecoverResponse(service, "application/vnd.goa.error", reporter)`))
+			})
+		})
+
+		Context("with encoders", func() {
+			BeforeEach(func() {
+				design.Design.Produces = []*design.EncodingDefinition{{
+					MIMETypes:   []string{"application/json", "application/vnd.api+json"},
+					PackagePath: "github.com/goadesign/goa/encoding/json",
+					Function:    "NewEncoder",
+				}}
+			})
+
+			It("exposes the encoders indexed by content type", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("var Encoders = map[string]goa.EncoderFunc{"))
+				Ω(string(content)).Should(ContainSubstring(`"application/vnd.api+json": json.NewEncoder,`))
+				Ω(string(content)).Should(ContainSubstring(`service.Encoder.Register(Encoders["application/vnd.api+json"], "application/vnd.api+json")`))
+				Ω(string(content)).Should(ContainSubstring(`service.Encoder.Register(Encoders["application/json"], "*/*")`))
+			})
+		})
+
+		Context("with a canonical media type", func() {
+			BeforeEach(func() {
+				res := design.Design.Resources["Widget"]
+				mt := &design.MediaTypeDefinition{
+					UserTypeDefinition: &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"id":   &design.AttributeDefinition{Type: design.String},
+								"name": &design.AttributeDefinition{Type: design.String},
+							},
+						},
+						TypeName: "Gadget",
+					},
+					Identifier: "application/vnd.gadget",
+					Resource:   res,
+				}
+				mt.Views = map[string]*design.ViewDefinition{
+					"default": {
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{"id": mt.Type.ToObject()["id"], "name": mt.Type.ToObject()["name"]},
+						},
+						Name:   "default",
+						Parent: mt,
+					},
+					"tiny": {
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{"name": mt.Type.ToObject()["name"]},
+						},
+						Name:   "tiny",
+						Parent: mt,
+					},
+				}
+				design.Design.MediaTypes[mt.Identifier] = mt
+				res.MediaType = mt.Identifier
+				res.Actions["get"].Responses["ok"].MediaType = mt.Identifier
+			})
+
+			It("generates the Href methods of the media type projections", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "hrefs.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func (mt *Gadget) Href() string {\n\tvar id interface{}\n\tif mt.ID != nil {\n\t\tid = *mt.ID\n\t}\n\treturn WidgetHref(id)\n}"))
+				Ω(string(content)).Should(ContainSubstring("func (mt *GadgetTiny) Href(id interface{}) string {\n\treturn WidgetHref(id)\n}"))
+			})
+		})
+
+		Context("with generated HEAD and OPTIONS handlers", func() {
+			BeforeEach(func() {
+				design.Design.AutoHead = true
+				design.Design.AutoOptions = true
+			})
+
+			It("mounts the HEAD and OPTIONS handlers", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`service.Mux.Handle("HEAD", "/:id", goa.HeadHandler(ctrl.MuxHandler("Get", h, nil)))`))
+				Ω(string(content)).Should(ContainSubstring(`service.Mux.Handle("OPTIONS", "/:id", ctrl.MuxHandler("options", goa.OptionsHandler("GET", "HEAD", "OPTIONS"), nil))`))
+			})
+		})
+
+		Context("with method override", func() {
+			BeforeEach(func() {
+				design.Design.MethodOverride = &design.MethodOverrideDefinition{
+					Header: design.DefaultMethodOverrideHeader,
+					Field:  design.DefaultMethodOverrideField,
+				}
+			})
+
+			It("routes the tunneled requests", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`service.Mux = goa.MethodOverrideMux(service.Mux, "X-HTTP-Method-Override", "_method")`))
+			})
+		})
+
+		Context("with response compression", func() {
+			BeforeEach(func() {
+				design.Design.Compression = &design.CompressionDefinition{
+					Encodings:    []string{"gzip"},
+					MinSize:      512,
+					ContentTypes: []string{"application/json"},
+				}
+			})
+
+			It("compresses the action responses", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("compressor := compress.Middleware(compress.Options{\n\t\tEncodings:    []string{\"gzip\"},\n\t\tMinSize:      512,\n\t\tContentTypes: []string{\"application/json\"},\n\t})"))
+				Ω(string(content)).Should(ContainSubstring("h = compressor(h)"))
+			})
+		})
+
+		Context("with request decompression", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+					},
+					TypeName: "Collection",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+				design.Design.Resources["Widget"].Decompression = &design.DecompressionDefinition{MaxSize: 2048}
+			})
+
+			It("decompresses the request body before decoding it", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {\n\tif err := goa.DecompressRequest(req, 2048); err != nil {\n\t\treturn err\n\t}\n\tvar payload Collection\n"))
+			})
+		})
+
+		Context("with payload limits", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+					},
+					TypeName: "Collection",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+				design.Design.Limits = &design.LimitsDefinition{MaxDepth: 5, MaxArrayLength: 100}
+			})
+
+			It("checks the limits before decoding the request body", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("\tif err := goa.LimitRequest(req, goa.PayloadLimits{MaxDepth: 5, MaxArrayLength: 100, MaxMapSize: 0}); err != nil {\n\t\treturn err\n\t}\n\tvar payload Collection\n"))
+			})
+		})
+
+		Context("with unknown fields disallowed", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+					},
+					TypeName: "Collection",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+				design.Design.Resources["Widget"].Metadata = dslengine.MetadataDefinition{design.StrictMetadata: {}}
+			})
+
+			It("decodes the request body strictly", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("\tvar payload Collection\n\tif err := service.DecodeRequestStrict(req, &payload); err != nil {\n"))
+			})
+		})
+
+		Context("with a streaming payload", func() {
+			BeforeEach(func() {
+				elem := &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type:       design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+					},
+					TypeName: "Event",
+				}
+				design.Design.Types = map[string]*design.UserTypeDefinition{"Event": elem}
+				design.Design.Resources["Widget"].Actions["get"].StreamingPayload = elem
+			})
+
+			It("generates the stream iterator", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("\tStream *GetWidgetStream\n"))
+				Ω(string(content)).Should(ContainSubstring("rctx.Stream = &GetWidgetStream{stream: goa.NewPayloadStream(req.Body)}"))
+				Ω(string(content)).Should(ContainSubstring("func (s *GetWidgetStream) Recv() (*Event, error) {\n\tvar elem event\n\tif err := s.stream.Decode(&elem); err != nil {\n\t\treturn nil, err\n\t}\n\tif err := elem.Validate(); err != nil {\n\t\treturn nil, err\n\t}\n\treturn elem.Publicize(), nil\n}"))
+			})
+		})
+
+		Context("with a cache policy", func() {
+			BeforeEach(func() {
+				design.Design.Resources["Widget"].Actions["get"].Cache = &design.CacheDefinition{
+					MaxAge:               60,
+					StaleWhileRevalidate: 300,
+					Vary:                 []string{"Accept"},
+				}
+			})
+
+			It("wraps the action handler with the cache middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`h = cache.Middleware(cache.Policy{MaxAge: 60, StaleWhileRevalidate: 300, Vary: []string{"Accept"}})(h)`))
+			})
+
+			Context("on a secured, authorized and audited action of a multi-tenant API", func() {
+				BeforeEach(func() {
+					scheme := &design.SecuritySchemeDefinition{
+						SchemeName: "api_key",
+						Kind:       design.APIKeySecurityKind,
+						Type:       "apiKey",
+						In:         "header",
+						Name:       "X-Api-Key",
+					}
+					design.Design.SecuritySchemes = []*design.SecuritySchemeDefinition{scheme}
+					design.Design.Tenant = &design.TenantDefinition{
+						Header:    "X-Tenant-ID",
+						Attribute: &design.AttributeDefinition{Type: design.String},
+					}
+					get := design.Design.Resources["Widget"].Actions["get"]
+					get.Security = &design.SecurityDefinition{Scheme: scheme, Roles: []string{"admin"}}
+					get.Audit = &design.AuditDefinition{Event: "widget.get", Parent: get}
+				})
+
+				It("only caches the responses by credentials and tenant", func() {
+					Ω(genErr).Should(BeNil())
+
+					content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(string(content)).Should(ContainSubstring(`h = cache.Middleware(cache.Policy{MaxAge: 60, StaleWhileRevalidate: 300, Vary: []string{"Accept", "X-Tenant-ID"}, Secured: true, Credential: "X-Api-Key"})(h)`))
+				})
+
+				It("authorizes the requests and emits the audit events outside of the cache", func() {
+					Ω(genErr).Should(BeNil())
+
+					content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+					Ω(err).ShouldNot(HaveOccurred())
+					code := string(content)
+					cached := strings.Index(code, "h = cache.Middleware(")
+					authorized := strings.Index(code, `if err := goa.Authorize(ctx, nil, []string{"admin"}); err != nil {`)
+					audited := strings.Index(code, "auditGetWidgetContext(rctx, err)")
+					Ω(cached).Should(BeNumerically(">", 0))
+					Ω(authorized).Should(BeNumerically(">", cached))
+					Ω(audited).Should(BeNumerically(">", cached))
+					Ω(strings.Count(code, "goa.Authorize(")).Should(Equal(1))
+					Ω(strings.Count(code, "auditGetWidgetContext(rctx, err)")).Should(Equal(1))
+				})
+			})
+		})
+
+		Context("with a concurrency budget", func() {
+			BeforeEach(func() {
+				design.Design.Resources["Widget"].Actions["get"].Concurrency = &design.ConcurrencyDefinition{
+					Max:        20,
+					Shed:       true,
+					RetryAfter: 2,
+					MaxWait:    10,
+				}
+			})
+
+			It("wraps the action handler with the concurrency limit middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("h = middleware.ConcurrencyLimit(20, true, 2, 10*time.Second)(h)"))
+			})
+		})
+
+		Context("with captured requests", func() {
+			BeforeEach(func() {
+				design.Design.Capture = &design.CaptureDefinition{MaxBodySize: 1024}
+				design.Design.Resources["Widget"].Actions["get"].Headers = &design.AttributeDefinition{
+					Type: design.Object{
+						"X-Api-Key": &design.AttributeDefinition{
+							Type:     design.String,
+							Metadata: dslengine.MetadataDefinition{design.SensitiveMetadata: {}},
+						},
+					},
+				}
+			})
+
+			It("wraps the action handler with the capture middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("h = capture.Middleware(capture.Options{"))
+				Ω(string(content)).Should(ContainSubstring(`Resource:    "Widget",`))
+				Ω(string(content)).Should(ContainSubstring("MaxBodySize: 1024,"))
+				Ω(string(content)).Should(ContainSubstring(`Headers:     []string{"X-Api-Key"},`))
+			})
+		})
+
+		Context("with a maintenance response", func() {
+			BeforeEach(func() {
+				design.Design.Maintenance = &design.ResponseDefinition{
+					Name:      "Maintenance",
+					Status:    503,
+					MediaType: design.ErrorMediaIdentifier,
+				}
+			})
+
+			It("checks the maintenance switch before handling the action requests", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`h = service.Maintenance.Handle("Widget", "get", goa.MaintenanceResponse{Status: 503, Error: true})(h)`))
+			})
+		})
+
+		Context("with a feature flag", func() {
+			BeforeEach(func() {
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Responses["NotFound"] = &design.ResponseDefinition{Name: "NotFound", Status: 404}
+				get.Flag = &design.FlagDefinition{Name: "new-widgets", Parent: get}
+			})
+
+			It("wraps the action handler with the feature gate middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`h = feature.Gate(feature.Options{Flag: "new-widgets", Status: 404, Error: false})(h)`))
+			})
+		})
+
+		Context("with a request ID", func() {
+			BeforeEach(func() {
+				design.Design.RequestID = &design.RequestIDDefinition{Header: "X-Correlation-Id"}
+				design.Design.MediaTypes[design.CanonicalIdentifier(design.ErrorMediaIdentifier)] = design.ErrorMedia
+				design.Design.Resources["Widget"].Actions["get"].Responses["BadRequest"] = &design.ResponseDefinition{
+					Name:      "BadRequest",
+					Status:    400,
+					MediaType: design.ErrorMediaIdentifier,
+				}
+			})
+
+			It("generates the request ID middleware", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "request_id.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`const RequestIDHeader = "X-Correlation-Id"`))
+				Ω(string(content)).Should(ContainSubstring("return middleware.CorrelationID(RequestIDHeader)"))
+			})
+
+			It("adds the request ID to the error responses", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("func (ctx *GetWidgetContext) BadRequest(r error) error {"))
+				Ω(string(content)).Should(ContainSubstring("r = middleware.ErrorWithRequestID(ctx.Context, r)"))
+			})
+		})
+
 	})
 })
 
@@ -0,0 +1,365 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// InterceptorTemplateData holds the data needed to generate the typed interceptor of a design
+	// interceptor.
+	InterceptorTemplateData struct {
+		Name        string              // Design name of interceptor
+		GoName      string              // Go name of interceptor, e.g. "Audit"
+		Description string              // Description of interceptor
+		Payload     []*InterceptorField // Payload attributes accessed by interceptor
+		Result      []*InterceptorField // Result attributes accessed by interceptor
+	}
+
+	// InterceptorField describes a field of the struct giving access to the payload or result
+	// attributes accessed by an interceptor.
+	InterceptorField struct {
+		Name      string // Design name of attribute
+		FieldName string // Go name of struct field
+		Type      string // Go type of struct field
+		Read      bool   // Whether the interceptor reads the attribute
+		Write     bool   // Whether the interceptor writes the attribute
+	}
+
+	// ActionInterceptorsTemplateData holds the data needed to generate the function that runs
+	// the interceptors of an action.
+	ActionInterceptorsTemplateData struct {
+		Context      string                   // Name of action context type
+		ResourceName string                   // Name of resource
+		ActionName   string                   // Name of action
+		Interceptors []*ActionInterceptorData // Interceptors that apply to action in order
+	}
+
+	// ActionInterceptorData holds the code that copies the action payload attributes to and
+	// from the interceptor payload struct.
+	ActionInterceptorData struct {
+		*InterceptorTemplateData
+		ReadPayload  []string // Statements copying the payload attributes read by interceptor
+		WritePayload []string // Statements copying back the payload attributes written by interceptor
+	}
+)
+
+// generateInterceptors generates the typed interceptors and the functions that run them around
+// the action handlers.
+func (g *Generator) generateInterceptors() error {
+	var (
+		interceptors []*InterceptorTemplateData
+		actions      []*ActionInterceptorsTemplateData
+	)
+	for _, i := range g.API.Interceptors {
+		if data := interceptorData(g.API, i); data != nil {
+			interceptors = append(interceptors, data)
+		}
+	}
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			all := a.AllInterceptors()
+			if len(all) == 0 {
+				return nil
+			}
+			data := &ActionInterceptorsTemplateData{
+				Context:      codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Context",
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+			}
+			for _, i := range all {
+				id := &ActionInterceptorData{InterceptorTemplateData: interceptorData(g.API, i)}
+				if a.Payload != nil && a.Payload.IsObject() {
+					for _, f := range id.Payload {
+						if f.Read {
+							id.ReadPayload = append(id.ReadPayload,
+								readField(a.Payload.AttributeDefinition, f, "payload", "ctx.Payload"))
+						}
+						if f.Write {
+							id.WritePayload = append(id.WritePayload,
+								writeField(a.Payload.AttributeDefinition, f, "payload", "ctx.Payload"))
+						}
+					}
+				}
+				data.Interceptors = append(data.Interceptors, id)
+			}
+			actions = append(actions, data)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(interceptors) == 0 {
+		return nil
+	}
+
+	intFile := filepath.Join(g.OutDir, "interceptors.go")
+	file, err := codegen.SourceFileFor(intFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Interceptors", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
+	g.genfiles = append(g.genfiles, intFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Interceptors": interceptors,
+		"Actions":      actions,
+	}
+	funcs := template.FuncMap{"reverseInterceptors": reverseInterceptors}
+	if err := file.ExecuteTemplate("interceptors", interceptorsT, funcs, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// interceptorData returns the data needed to generate the given interceptor. The types of the
+// struct fields are computed from the first action the interceptor applies to, design validation
+// guarantees they are the same for all actions. interceptorData returns nil if the interceptor
+// does not apply to any action.
+func interceptorData(api *design.APIDefinition, i *design.InterceptorDefinition) *InterceptorTemplateData {
+	var action *design.ActionDefinition
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if action != nil {
+				return nil
+			}
+			for _, ai := range a.AllInterceptors() {
+				if ai == i {
+					action = a
+				}
+			}
+			return nil
+		})
+	})
+	if action == nil {
+		return nil
+	}
+	data := &InterceptorTemplateData{
+		Name:        i.Name,
+		GoName:      codegen.Goify(i.Name, true),
+		Description: i.Description,
+	}
+	for _, n := range i.PayloadAttributes() {
+		if action.Payload == nil || !action.Payload.IsObject() {
+			break
+		}
+		if att := action.Payload.ToObject()[n]; att != nil {
+			data.Payload = append(data.Payload, &InterceptorField{
+				Name:      n,
+				FieldName: codegen.GoifyAtt(att, n, true),
+				Type:      interceptorFieldType(att),
+				Read:      contains(i.ReadPayload, n),
+				Write:     contains(i.WritePayload, n),
+			})
+		}
+	}
+	for _, n := range i.ResultAttributes() {
+		if att := action.ResultAttribute(n); att != nil {
+			data.Result = append(data.Result, &InterceptorField{
+				Name:      n,
+				FieldName: codegen.GoifyAtt(att, n, true),
+				Type:      interceptorFieldType(att),
+				Read:      contains(i.ReadResult, n),
+				Write:     contains(i.WriteResult, n),
+			})
+		}
+	}
+	return data
+}
+
+// interceptResult returns the code of the response helper that applies the result attributes
+// written by the interceptors to the response media type r and that exposes the result attributes
+// read by the interceptors. Attributes that are not part of the response view are skipped.
+func interceptResult(interceptors []*InterceptorTemplateData, projected *design.MediaTypeDefinition) string {
+	if !projected.IsObject() {
+		return ""
+	}
+	var code string
+	for _, i := range interceptors {
+		var stmts []string
+		for _, f := range i.Result {
+			if f.Write && hasField(projected.AttributeDefinition, f) {
+				stmts = append(stmts, writeField(projected.AttributeDefinition, f, "res", "r"))
+			}
+		}
+		for _, f := range i.Result {
+			if f.Read && hasField(projected.AttributeDefinition, f) {
+				stmts = append(stmts, readField(projected.AttributeDefinition, f, "res", "r"))
+			}
+		}
+		if len(stmts) == 0 {
+			continue
+		}
+		code += fmt.Sprintf("\tif res, ok := ctx.Value(interceptorResultKey(%q)).(*%sResult); ok && r != nil {\n",
+			i.Name, i.GoName)
+		for _, s := range stmts {
+			code += "\t\t" + s + "\n"
+		}
+		code += "\t}\n"
+	}
+	return code
+}
+
+// interceptorFieldType returns the Go type of the interceptor struct field for the given
+// attribute. Primitive fields are pointers so that interceptors can tell whether they are set.
+func interceptorFieldType(att *design.AttributeDefinition) string {
+	typedef := codegen.GoTypeDef(att, 1, true, false)
+	if att.Type.IsPrimitive() || att.Type.IsObject() {
+		typedef = "*" + typedef
+	}
+	return typedef
+}
+
+// hasField returns true if the given object attribute defines the interceptor field with the
+// same Go type.
+func hasField(parent *design.AttributeDefinition, f *InterceptorField) bool {
+	att := parent.Type.ToObject()[f.Name]
+	return att != nil && interceptorFieldType(att) == f.Type
+}
+
+// isPointerField returns true if the struct field generated for the given attribute of parent is
+// a pointer.
+func isPointerField(parent *design.AttributeDefinition, name string) bool {
+	att := parent.Type.ToObject()[name]
+	return att.Type.IsObject() || parent.IsPrimitivePointer(name)
+}
+
+// readField returns the statement that copies the attribute of the object target to the
+// interceptor struct field.
+func readField(parent *design.AttributeDefinition, f *InterceptorField, intVar, target string) string {
+	if parent.IsNullable(f.Name) {
+		_, v := codegen.NullWrapper(parent.Type.ToObject()[f.Name].Type)
+		return fmt.Sprintf("if %s.%s.Valid {\nv := %s.%s.%s\n%s.%s = &v\n}",
+			target, f.FieldName, target, f.FieldName, v, intVar, f.FieldName)
+	}
+	if parent.HasPresence(f.Name) {
+		return fmt.Sprintf("if %s.Has%s() {\n%s.%s = &%s.%s\n}",
+			target, f.FieldName, intVar, f.FieldName, target, f.FieldName)
+	}
+	ref := ""
+	if parent.Type.ToObject()[f.Name].Type.IsPrimitive() && !isPointerField(parent, f.Name) {
+		ref = "&"
+	}
+	return fmt.Sprintf("%s.%s = %s%s.%s", intVar, f.FieldName, ref, target, f.FieldName)
+}
+
+// writeField returns the statement that copies the interceptor struct field to the attribute of
+// the object target if set.
+func writeField(parent *design.AttributeDefinition, f *InterceptorField, intVar, target string) string {
+	if parent.IsNullable(f.Name) {
+		wrapper, v := codegen.NullWrapper(parent.Type.ToObject()[f.Name].Type)
+		return fmt.Sprintf("if %s.%s != nil {\n%s.%s = %s{%s: *%s.%s, Valid: true}\n}",
+			intVar, f.FieldName, target, f.FieldName, wrapper, v, intVar, f.FieldName)
+	}
+	if parent.HasPresence(f.Name) {
+		return fmt.Sprintf("if %s.%s != nil {\n%s.Set%s(*%s.%s)\n}",
+			intVar, f.FieldName, target, f.FieldName, intVar, f.FieldName)
+	}
+	deref := ""
+	if parent.Type.ToObject()[f.Name].Type.IsPrimitive() && !isPointerField(parent, f.Name) {
+		deref = "*"
+	}
+	return fmt.Sprintf("if %s.%s != nil {\n%s.%s = %s%s.%s\n}",
+		intVar, f.FieldName, target, f.FieldName, deref, intVar, f.FieldName)
+}
+
+// reverseInterceptors returns the given interceptors in reverse order so that the generated code
+// wraps the handler starting with the innermost interceptor.
+func reverseInterceptors(interceptors []*ActionInterceptorData) []*ActionInterceptorData {
+	res := make([]*ActionInterceptorData, len(interceptors))
+	for i, in := range interceptors {
+		res[len(interceptors)-1-i] = in
+	}
+	return res
+}
+
+// contains returns true if names contains name.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// interceptorsT generates the typed interceptors and the functions running them.
+	// template input: map[string]interface{}
+	interceptorsT = `type (
+	// interceptorKey is the type used to store interceptor implementations in the service
+	// context.
+	interceptorKey string
+
+	// interceptorResultKey is the type used to store the interceptor result structs in the
+	// request context.
+	interceptorResultKey string
+)
+{{ range .Interceptors }}
+// {{ .GoName }}Payload gives access to the payload attributes read and written by the {{ printf "%q" .Name }}
+// interceptor.
+type {{ .GoName }}Payload struct {
+{{ range .Payload }}	{{ .FieldName }} {{ .Type }}
+{{ end }}}
+
+// {{ .GoName }}Result gives access to the result attributes read and written by the {{ printf "%q" .Name }}
+// interceptor.
+type {{ .GoName }}Result struct {
+{{ range .Result }}	{{ .FieldName }} {{ .Type }}
+{{ end }}}
+
+// {{ .GoName }}Interceptor is the type of the {{ printf "%q" .Name }} interceptor implementation.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+// The interceptor must call next to run the rest of the chain and the action handler. The payload
+// attributes written by the interceptor must be set before calling next. The result attributes
+// written by the interceptor must also be set before calling next and the result attributes read
+// by the interceptor are available once the action has sent its response.
+type {{ .GoName }}Interceptor func(ctx context.Context, payload *{{ .GoName }}Payload, result *{{ .GoName }}Result, next func(context.Context) error) error
+
+// Use{{ .GoName }}Interceptor registers the implementation of the {{ printf "%q" .Name }} interceptor with
+// the service.
+func Use{{ .GoName }}Interceptor(service *goa.Service, i {{ .GoName }}Interceptor) {
+	service.Context = context.WithValue(service.Context, interceptorKey({{ printf "%q" .Name }}), i)
+}
+{{ end }}{{ range .Actions }}{{ $action := . }}
+// intercept{{ .Context }} runs the interceptors that apply to the {{ .ResourceName }} {{ .ActionName }} action
+// around the handler h.
+func intercept{{ .Context }}(ctx *{{ .Context }}, h func(*{{ .Context }}) error) error {
+	next := func(c context.Context) error {
+		ctx.Context = c
+		return h(ctx)
+	}
+{{ range $i := reverseInterceptors .Interceptors }}	if i, ok := ctx.Value(interceptorKey({{ printf "%q" .Name }})).({{ .GoName }}Interceptor); ok {
+		next = func(next func(context.Context) error) func(context.Context) error {
+			return func(c context.Context) error {
+				payload, result := &{{ .GoName }}Payload{}, &{{ .GoName }}Result{}
+{{ if .ReadPayload }}				if ctx.Payload != nil {
+{{ range .ReadPayload }}					{{ . }}
+{{ end }}				}
+{{ end }}				return i(c, payload, result, func(c context.Context) error {
+{{ if .WritePayload }}					if ctx.Payload != nil {
+{{ range .WritePayload }}						{{ . }}
+{{ end }}					}
+{{ end }}					return next(context.WithValue(c, interceptorResultKey({{ printf "%q" .Name }}), result))
+				})
+			}
+		}(next)
+	}
+{{ end }}	return next(ctx.Context)
+}
+{{ end }}`
+)
@@ -0,0 +1,114 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// JSONRPCTemplateData holds the data needed to generate the JSON-RPC endpoint.
+type JSONRPCTemplateData struct {
+	Path    string                       // Path of the JSON-RPC endpoint
+	Methods []*JSONRPCMethodTemplateData // Methods sorted by name
+	Codes   []*JSONRPCCodeTemplateData   // JSON-RPC codes of the declared errors
+}
+
+// JSONRPCMethodTemplateData holds the data needed to generate the route of a JSON-RPC method.
+type JSONRPCMethodTemplateData struct {
+	Name    string   // Name of the method, "<resource>.<action>"
+	Verb    string   // HTTP method of the action route
+	Path    string   // Full path of the action route
+	Params  []string // Names of the action path and querystring parameters
+	Payload bool     // Whether the action accepts a payload
+}
+
+// JSONRPCCodeTemplateData holds the JSON-RPC code of a declared error.
+type JSONRPCCodeTemplateData struct {
+	Name string // Name of the declared error
+	Code int    // JSON-RPC error code
+}
+
+// generateJSONRPC generates the function that mounts the JSON-RPC endpoint exposing the actions.
+func (g *Generator) generateJSONRPC() error {
+	if g.API.JSONRPC == nil {
+		return nil
+	}
+	data := &JSONRPCTemplateData{Path: g.API.JSONRPC.Path}
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			ro := a.Routes[0]
+			var params []string
+			if ps := a.AllParams(); ps != nil {
+				for n := range ps.Type.ToObject() {
+					params = append(params, n)
+				}
+			}
+			sort.Strings(params)
+			data.Methods = append(data.Methods, &JSONRPCMethodTemplateData{
+				Name:    r.Name + "." + a.Name,
+				Verb:    ro.Verb,
+				Path:    ro.FullPath(),
+				Params:  params,
+				Payload: a.Payload != nil,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	for i, n := range g.API.JSONRPC.ErrorNames() {
+		data.Codes = append(data.Codes, &JSONRPCCodeTemplateData{Name: n, Code: -32001 - i})
+	}
+
+	jsonrpcFile := filepath.Join(g.OutDir, "jsonrpc.go")
+	file, err := codegen.SourceFileFor(jsonrpcFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application JSON-RPC Endpoint", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/jsonrpc"),
+	}
+	g.genfiles = append(g.genfiles, jsonrpcFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("jsonrpc", jsonrpcT, nil, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// jsonrpcT generates the function that mounts the JSON-RPC endpoint.
+	// template input: *JSONRPCTemplateData
+	jsonrpcT = `// MountJSONRPC mounts the JSON-RPC 2.0 endpoint on POST {{ .Path }}. The endpoint dispatches the
+// calls to the actions mounted on the service mux, mount the controllers before calling it.
+// MountJSONRPC returns the endpoint server so that its request body length and batch size limits
+// may be configured.
+func MountJSONRPC(service *goa.Service) *jsonrpc.Server {
+	server := &jsonrpc.Server{
+		Mux: service.Mux,
+		Methods: map[string]*jsonrpc.Method{
+{{ range .Methods }}			{{ printf "%q" .Name }}: {Verb: {{ printf "%q" .Verb }}, Path: {{ printf "%q" .Path }}{{ if .Params }}, Params: []string{ {{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ printf "%q" $p }}{{ end }} }{{ end }}{{ if .Payload }}, Payload: true{{ end }}},
+{{ end }}		},
+{{ if .Codes }}		Codes: map[string]int{
+{{ range .Codes }}			{{ printf "%q" .Name }}: {{ .Code }},
+{{ end }}		},
+{{ end }}		MaxRequestBodyLength: jsonrpc.DefaultMaxRequestBodyLength,
+		MaxBatchSize:         jsonrpc.DefaultMaxBatchSize,
+	}
+	service.Mux.Handle("POST", {{ printf "%q" .Path }}, server.MuxHandler())
+	service.LogInfo("mount", "ctrl", "JSON-RPC", "action", "Call", "route", {{ printf "%q" (printf "POST %s" .Path) }})
+	return server
+}
+`
+)
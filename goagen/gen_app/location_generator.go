@@ -0,0 +1,55 @@
+package genapp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// locationHeader returns the statements that set the Location header of the response from the
+// result held by the "r" variable, see the Location DSL. It returns the empty string if the
+// response does not define a Location header or if the view being rendered does not include all
+// the attributes needed to compute it.
+func locationHeader(resp *design.ResponseDefinition, projected *design.MediaTypeDefinition) string {
+	names := resp.LocationAttributes()
+	if len(names) == 0 || !projected.IsObject() || projected.EnvelopeElem() != nil {
+		return ""
+	}
+	att := projected.AttributeDefinition
+	obj := att.Type.ToObject()
+	conds := []string{"r != nil"}
+	values := make([]string, len(names))
+	for i, n := range names {
+		a, ok := obj[n]
+		if !ok {
+			return ""
+		}
+		field := "r." + codegen.GoifyAtt(a, n, true)
+		switch {
+		case att.IsPrimitivePointer(n):
+			conds = append(conds, field+" != nil")
+			values[i] = "*" + field
+		case att.IsNullable(n):
+			_, wrapped := codegen.NullWrapper(a.Type)
+			conds = append(conds, field+".Valid")
+			values[i] = field + "." + wrapped
+		default:
+			values[i] = field
+		}
+	}
+	var value string
+	if resp.IsLocationTemplate() {
+		format := strings.Replace(resp.LocationHeader, "%", "%%", -1)
+		format = design.LocationRegex.ReplaceAllString(format, "%v")
+		value = fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(values, ", "))
+	} else if obj[names[0]].Type == design.String {
+		value = values[0]
+	} else {
+		value = fmt.Sprintf("fmt.Sprint(%s)", values[0])
+	}
+	code := fmt.Sprintf("\tif %s {\n", strings.Join(conds, " && "))
+	code += fmt.Sprintf("\t\tctx.ResponseData.Header().Set(\"Location\", %s)\n\t}\n", value)
+	return code
+}
@@ -0,0 +1,151 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// MQTTTemplateData holds the data needed to generate the MQTT binding of an action.
+type MQTTTemplateData struct {
+	Name         string        // Name of the action followed by the name of its resource
+	ResourceName string        // Name of resource
+	ActionName   string        // Name of action
+	Topic        string        // Topic the action subscribes to
+	QoS          int           // Quality of service
+	ResultTopic  string        // Topic the results are published to if any
+	Verb         string        // HTTP method of the action route
+	Path         string        // Full path of the action route
+	Params       []*ObjectType // Parameters captured by the result topic
+	Result       string        // Go type of the result
+}
+
+// generateMQTT generates the function that subscribes the actions bound to MQTT topics and the
+// helpers that publish their results.
+func (g *Generator) generateMQTT() error {
+	var bindings []*MQTTTemplateData
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.MQTT == nil || len(a.Routes) == 0 {
+				return nil
+			}
+			b := &MQTTTemplateData{
+				Name:         codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				ResourceName: r.Name,
+				ActionName:   a.Name,
+				Topic:        a.MQTT.Topic,
+				QoS:          a.MQTT.QoS,
+				ResultTopic:  a.MQTT.ResultTopic,
+				Verb:         a.Routes[0].Verb,
+				Path:         a.Routes[0].FullPath(),
+				Result:       g.mqttResult(a),
+			}
+			if b.ResultTopic != "" {
+				params := a.AllParams().Type.ToObject()
+				for _, n := range design.TopicParams(b.ResultTopic) {
+					b.Params = append(b.Params, &ObjectType{
+						Label: n,
+						Name:  codegen.Goify(n, false),
+						Type:  codegen.GoTypeRef(params[n].Type, nil, 0, false),
+					})
+				}
+			}
+			bindings = append(bindings, b)
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(bindings) == 0 {
+		return nil
+	}
+
+	mqttFile := filepath.Join(g.OutDir, "mqtt.go")
+	file, err := codegen.SourceFileFor(mqttFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application MQTT Bindings", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/mqtt"),
+	}
+	g.genfiles = append(g.genfiles, mqttFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("mqtt", mqttT, nil, bindings); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// mqttResult returns the Go type of the result of the action: the type of the first successful
+// response that uses an object or collection media type rendered with the response view,
+// interface{} if there isn't one.
+func (g *Generator) mqttResult(a *design.ActionDefinition) string {
+	names := make([]string, 0, len(a.Responses))
+	for n := range a.Responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var resp *design.ResponseDefinition
+	for _, n := range names {
+		r := a.Responses[n]
+		if r.Status >= 200 && r.Status < 300 && r.MediaType != "" {
+			resp = r
+			break
+		}
+	}
+	if resp == nil {
+		return "interface{}"
+	}
+	mt := g.API.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil || !mt.IsObject() && !mt.IsArray() {
+		return "interface{}"
+	}
+	view := resp.ViewName
+	if view == "" {
+		view = design.DefaultView
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return "interface{}"
+	}
+	return codegen.GoTypeRef(p, p.AllRequired(), 0, false)
+}
+
+const (
+	// mqttT generates the MQTT subscriptions and result publishers.
+	// template input: []*MQTTTemplateData
+	mqttT = `// SubscribeMQTT subscribes the actions bound to MQTT topics using client. The messages published to
+// the topics are sent to the actions through the service mux, mount the controllers before calling
+// it.
+func SubscribeMQTT(service *goa.Service, client mqtt.Client) error {
+	bindings := []*mqtt.Binding{
+{{ range . }}		{Topic: {{ printf "%q" .Topic }}, QoS: {{ .QoS }}{{ if .ResultTopic }}, ResultTopic: {{ printf "%q" .ResultTopic }}{{ end }}, Verb: {{ printf "%q" .Verb }}, Path: {{ printf "%q" .Path }}},
+{{ end }}	}
+	for _, b := range bindings {
+		if err := mqtt.Subscribe(service, client, b); err != nil {
+			return err
+		}
+		service.LogInfo("subscribe", "topic", b.Topic, "route", b.Verb+" "+b.Path)
+	}
+	return nil
+}
+{{ range . }}{{ if .ResultTopic }}
+// Publish{{ .Name }}Result publishes the result of the {{ .ResourceName }} {{ .ActionName }} action to the
+// {{ printf "%q" .ResultTopic }} topic.
+func Publish{{ .Name }}Result(client mqtt.Client{{ range .Params }}, {{ .Name }} {{ .Type }}{{ end }}, result {{ .Result }}) error {
+	topic := mqtt.Topic({{ printf "%q" .ResultTopic }}, map[string]string{
+{{ range .Params }}		{{ printf "%q" .Label }}: fmt.Sprint({{ .Name }}),
+{{ end }}	})
+	return mqtt.Publish(client, topic, {{ .QoS }}, result)
+}
+{{ end }}{{ end }}`
+)
@@ -0,0 +1,52 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateRecover generates the middleware that renders the panics of the handlers using the
+// response with status code 500 defined in the API.
+func (g *Generator) generateRecover() error {
+	if g.API.PanicResponse() == nil {
+		return nil
+	}
+
+	recoverFile := filepath.Join(g.OutDir, "recover.go")
+	file, err := codegen.SourceFileFor(recoverFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Panic Recovery", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+	}
+	g.genfiles = append(g.genfiles, recoverFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	data := map[string]interface{}{
+		"Response":    g.API.PanicResponse(),
+		"ContentType": g.API.PanicContentType(),
+	}
+	if err := file.ExecuteTemplate("recover", recoverT, nil, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// recoverT generates the panic recovery middleware.
+	// template input: map[string]interface{}
+	recoverT = `// RecoverMiddleware returns a middleware that recovers the panics of the handlers and sends the
+// {{ .Response.Name }} response defined in the API instead.{{ if .ContentType }} The response renders an internal
+// error that includes the request ID if any.{{ end }} The panic message and stack trace are logged
+// and never sent to the client. reporter is notified of each panic if not nil.
+func RecoverMiddleware(service *goa.Service, reporter middleware.CrashReporter) goa.Middleware {
+	return middleware.RecoverResponse(service, {{ printf "%q" .ContentType }}, reporter)
+}
+`
+)
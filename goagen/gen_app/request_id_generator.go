@@ -0,0 +1,55 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateRequestID generates the middleware that correlates the requests using request IDs.
+func (g *Generator) generateRequestID() error {
+	if g.API.RequestID == nil {
+		return nil
+	}
+
+	reqIDFile := filepath.Join(g.OutDir, "request_id.go")
+	file, err := codegen.SourceFileFor(reqIDFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Request ID", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+	}
+	g.genfiles = append(g.genfiles, reqIDFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("requestID", requestIDT, nil, g.API.RequestID); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// requestIDT generates the request ID middleware and accessor.
+	// template input: *design.RequestIDDefinition
+	requestIDT = `// RequestIDHeader is the name of the header that carries the request ID.
+const RequestIDHeader = {{ printf "%q" .Header }}
+
+// RequestIDMiddleware returns a middleware that reads the request ID from the RequestIDHeader
+// header or generates one, stores it in the request context and writes it to the response
+// header. The error responses written by the ErrorHandler middleware include the request ID.
+func RequestIDMiddleware() goa.Middleware {
+	return middleware.CorrelationID(RequestIDHeader)
+}
+
+// ContextRequestID returns the request ID held by ctx.
+func ContextRequestID(ctx context.Context) string {
+	return middleware.ContextRequestID(ctx)
+}
+`
+)
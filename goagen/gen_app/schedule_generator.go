@@ -0,0 +1,77 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ScheduleTemplateData holds the data needed to register a scheduled action with the cron runner.
+type ScheduleTemplateData struct {
+	Name string // Name of the job, "<resource>.<action>"
+	Spec string // Cron expression
+	Verb string // HTTP method of the action route
+	Path string // Full path of the action route
+}
+
+// generateSchedule generates the function that creates the cron runner calling the scheduled
+// actions.
+func (g *Generator) generateSchedule() error {
+	var jobs []*ScheduleTemplateData
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Schedule == nil || len(a.Routes) == 0 {
+				return nil
+			}
+			jobs = append(jobs, &ScheduleTemplateData{
+				Name: r.Name + "." + a.Name,
+				Spec: a.Schedule.Spec,
+				Verb: a.Routes[0].Verb,
+				Path: a.Routes[0].FullPath(),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	scheduleFile := filepath.Join(g.OutDir, "schedule.go")
+	file, err := codegen.SourceFileFor(scheduleFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Schedule", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/cron"),
+	}
+	g.genfiles = append(g.genfiles, scheduleFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("schedule", scheduleT, nil, jobs); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// scheduleT generates the function that creates the cron runner.
+	// template input: []*ScheduleTemplateData
+	scheduleT = `// NewScheduler returns a cron runner that calls the scheduled actions through the service mux,
+// mount the controllers before running it.
+func NewScheduler(service *goa.Service) *cron.Runner {
+	runner := cron.NewRunner(service)
+{{ range . }}	if err := runner.Add({{ printf "%q" .Name }}, {{ printf "%q" .Spec }}, {{ printf "%q" .Verb }}, {{ printf "%q" .Path }}); err != nil {
+		panic(err) // bug: the cron expressions are validated by goagen
+	}
+{{ end }}	return runner
+}
+`
+)
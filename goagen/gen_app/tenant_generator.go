@@ -0,0 +1,75 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateTenant generates the middleware that extracts the tenant identifier of the requests
+// and the accessors to the identifier stored in the request context.
+func (g *Generator) generateTenant() error {
+	if g.API.Tenant == nil {
+		return nil
+	}
+
+	tenantFile := filepath.Join(g.OutDir, "tenant.go")
+	file, err := codegen.SourceFileFor(tenantFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Tenancy", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	g.genfiles = append(g.genfiles, tenantFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("tenant", tenantT, nil, g.API.Tenant); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// tenantT generates the tenant middleware and accessors.
+	// template input: *design.TenantDefinition
+	tenantT = `{{ if .Header }}// TenantHeader is the name of the header that carries the tenant identifier.
+const TenantHeader = {{ printf "%q" .Header }}
+{{ else }}// TenantParam is the name of the path parameter that carries the tenant identifier.
+const TenantParam = {{ printf "%q" .Param }}
+{{ end }}
+// tenantKey is the key used to store the tenant identifier in the request context.
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx that holds the given tenant identifier.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// ContextTenant returns the tenant identifier held by ctx, ok is false if ctx does not hold a
+// tenant identifier.
+func ContextTenant(ctx context.Context) (tenant string, ok bool) {
+	tenant, ok = ctx.Value(tenantKey{}).(string)
+	return
+}
+
+// TenantMiddleware returns a middleware that extracts the tenant identifier from the request
+// {{ if .Header }}header{{ else }}path{{ end }} and stores it in the request context. The action contexts validate the identifier.
+func TenantMiddleware() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+{{ if .Header }}			if tenant := req.Header.Get(TenantHeader); tenant != "" {
+{{ else }}			if tenant := goa.ContextRequest(ctx).Params.Get(TenantParam); tenant != "" {
+{{ end }}				ctx = WithTenant(ctx, tenant)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+`
+)
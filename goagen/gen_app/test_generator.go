@@ -50,6 +50,7 @@ type ObjectType struct {
 	Type        string
 	Pointer     string
 	Validatable bool
+	IsObject    bool
 }
 
 func (g *Generator) generateResourceTest() error {
@@ -62,7 +63,7 @@ func (g *Generator) generateResourceTest() error {
 	if err != nil {
 		return err
 	}
-	appPkg, err := codegen.PackagePath(g.OutDir)
+	appPkg, err := g.PackagePath()
 	if err != nil {
 		return err
 	}
@@ -79,9 +80,11 @@ func (g *Generator) generateResourceTest() error {
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport(appPkg),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.SimpleImport("github.com/goadesign/goa/goatest"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 
 	return g.API.IterateResources(func(res *design.ResourceDefinition) error {
 		filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_testing.go")
@@ -199,8 +202,8 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 		ActionName:     actionName,
 		ResourceName:   ctrlName,
 		Comment:        comment,
-		Params:         pathParams(action, route),
-		QueryParams:    queryParams(action),
+		Params:         g.pathParams(action, route),
+		QueryParams:    g.queryParams(action),
 		Payload:        payload,
 		ReturnType:     returnType,
 		ControllerName: fmt.Sprintf("%s.%sController", g.Target, ctrlName),
@@ -213,12 +216,12 @@ func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action
 }
 
 // pathParams returns the path params for the given action and route.
-func pathParams(action *design.ActionDefinition, route *design.RouteDefinition) []*ObjectType {
-	return paramFromNames(action, route.Params())
+func (g *Generator) pathParams(action *design.ActionDefinition, route *design.RouteDefinition) []*ObjectType {
+	return g.paramFromNames(action, route.Params())
 }
 
 // queryParams returns the query string params for the given action.
-func queryParams(action *design.ActionDefinition) []*ObjectType {
+func (g *Generator) queryParams(action *design.ActionDefinition) []*ObjectType {
 	var qparams []string
 	if qps := action.QueryParams; qps != nil {
 		for pname := range qps.Type.ToObject() {
@@ -226,10 +229,10 @@ func queryParams(action *design.ActionDefinition) []*ObjectType {
 		}
 	}
 	sort.Strings(qparams)
-	return paramFromNames(action, qparams)
+	return g.paramFromNames(action, qparams)
 }
 
-func paramFromNames(action *design.ActionDefinition, names []string) (params []*ObjectType) {
+func (g *Generator) paramFromNames(action *design.ActionDefinition, names []string) (params []*ObjectType) {
 	for _, paramName := range names {
 		for name, att := range action.Params.Type.ToObject() {
 			if name == paramName {
@@ -237,7 +240,12 @@ func paramFromNames(action *design.ActionDefinition, names []string) (params []*
 				param.Label = name
 				param.Name = codegen.Goify(name, false)
 				param.Type = codegen.GoTypeRef(att.Type, nil, 0, false)
-				if att.Type.IsPrimitive() && action.Params.IsPrimitivePointer(name) {
+				if att.Type.IsObject() {
+					// Object params use a user type of the app package encoded with dotted keys.
+					param.Type = fmt.Sprintf("%s.%s", g.Target, codegen.GoTypeName(att.Type, nil, 0, false))
+					param.Pointer = "*"
+					param.IsObject = true
+				} else if att.Type.IsPrimitive() && action.Params.IsPrimitivePointer(name) {
 					param.Pointer = "*"
 				}
 				params = append(params, param)
@@ -315,11 +323,12 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	// Setup request context
 	rw := httptest.NewRecorder()
 {{ if $test.QueryParams}}	query := url.Values{}
-{{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
+{{ range $param := $test.QueryParams }}{{ if $param.IsObject }}	goaclient.EncodeQueryObject(query, {{ printf "%q" $param.Label }}, {{ $param.Name }})
+{{ else }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
 {{ template "convertParam" $param }}
 		query[{{ printf "%q" $param.Label }}] = sliceVal
 	}
-{{ end }}{{ end }}	u := &url.URL{
+{{ end }}{{ end }}{{ end }}	u := &url.URL{
 		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
 {{ if $test.QueryParams }}		RawQuery: query.Encode(),
 {{ end }}	}
@@ -329,11 +338,12 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	}
 	prms := url.Values{}
 {{ range $param := $test.Params }}	prms["{{ $param.Label }}"] = []string{fmt.Sprintf("%v",{{ $param.Name}})}
-{{ end }}{{ range $param := $test.QueryParams }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
+{{ end }}{{ range $param := $test.QueryParams }}{{ if $param.IsObject }}	goaclient.EncodeQueryObject(prms, {{ printf "%q" $param.Label }}, {{ $param.Name }})
+{{ else }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
 {{ template "convertParam" $param }}
 		prms[{{ printf "%q" $param.Label }}] = sliceVal
 	}
-{{ end }}	if ctx == nil {
+{{ end }}{{ end }}	if ctx == nil {
 		ctx = context.Background()
 	}
 	goaCtx := goa.NewContext(goa.WithAction(ctx, "{{ $test.ResourceName }}Test"), rw, req, prms)
@@ -61,6 +61,11 @@ var _ = Describe("Generate", func() {
 				TypeName:            "CustomName",
 			}
 
+			filterType := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: design.Object{"status": &design.AttributeDefinition{Type: design.String}}},
+				TypeName:            "Filter",
+			}
+
 			intAttr := &design.AttributeDefinition{
 				Type:       design.Object{"foo": &design.AttributeDefinition{Type: design.Integer}},
 				Validation: &dslengine.ValidationDefinition{Required: []string{"foo"}},
@@ -103,6 +108,7 @@ var _ = Describe("Generate", func() {
 										"uuid":     &design.AttributeDefinition{Type: design.UUID},
 										"optional": &design.AttributeDefinition{Type: design.Integer},
 										"required": &design.AttributeDefinition{Type: design.DateTime},
+										"filter":   &design.AttributeDefinition{Type: filterType},
 									},
 									Validation: &dslengine.ValidationDefinition{Required: []string{"required"}},
 								},
@@ -110,6 +116,7 @@ var _ = Describe("Generate", func() {
 									Type: design.Object{
 										"optional": &design.AttributeDefinition{Type: design.Integer},
 										"required": &design.AttributeDefinition{Type: design.DateTime},
+										"filter":   &design.AttributeDefinition{Type: filterType},
 									},
 									Validation: &dslengine.ValidationDefinition{Required: []string{"required"}},
 								},
@@ -168,7 +175,7 @@ var _ = Describe("Generate", func() {
 
 		It("does not call Validate on the resulting media type when it does not exist", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(11))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -177,7 +184,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates the ActionRouteResponse test methods ", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(11))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -188,6 +195,30 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring("GetFooOK(t goatest.TInterface, ctx context.Context, service *goa.Service, ctrl app.FooController, payload app.CustomName) (http.ResponseWriter, error)"))
 		})
 
+		It("generates the fuzz targets", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "fuzz_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("// +build go1.18"))
+			Ω(content).Should(ContainSubstring("func FuzzShowFoo(f *testing.F) {"))
+			Ω(content).Should(ContainSubstring("func FuzzGetFoo(f *testing.F) {"))
+			Ω(content).Should(ContainSubstring("unmarshalGetFooPayload(ctx, service, req)"))
+			Ω(content).Should(ContainSubstring("func FuzzIntContainer(f *testing.F) {"))
+		})
+
+		It("generates the wire format fixtures and tests", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "wire_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("func TestWireIntContainer(t *testing.T) {"))
+			Ω(content).Should(ContainSubstring(`assertWire(t, "testdata/wire/int_container.json", &mt)`))
+			Ω(content).Should(ContainSubstring("reflect.DeepEqual(got, expected)"))
+
+			fixture, err := ioutil.ReadFile(filepath.Join(outDir, "app", "testdata", "wire", "int_container.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(fixture)).Should(MatchRegexp(`^{\n  "foo": \d+\n}\n$`))
+		})
+
 		It("generates the route path parameters", func() {
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
@@ -206,6 +237,16 @@ var _ = Describe("Generate", func() {
 			Ω(content).ShouldNot(ContainSubstring(`if required != nil`))
 		})
 
+		It("encodes the object query parameters with dotted keys", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring(`filter *app.Filter`))
+			Ω(content).Should(ContainSubstring(`goaclient.EncodeQueryObject(query, "filter", filter)`))
+			Ω(content).Should(ContainSubstring(`goaclient.EncodeQueryObject(prms, "filter", filter)`))
+			Ω(content).ShouldNot(ContainSubstring(`query["filter"]`))
+		})
+
 		It("generates calls to new Context ", func() {
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
@@ -0,0 +1,87 @@
+package genapp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// TrailerField describes the field of the struct generated for a response trailer.
+type TrailerField struct {
+	Name        string // Canonical trailer name
+	Field       string // Go field name
+	Type        string // Go type of field value, the field is a pointer to this type
+	Description string // Trailer description
+	Format      string // Go expression that formats the value of the field held by "t"
+	Parse       string // Go expression that parses the value held by "raw", empty for strings
+}
+
+// TrailerFields returns the fields of the struct generated for the trailers of the given response
+// sorted by trailer name, nil if the response does not define trailers.
+func TrailerFields(resp *design.ResponseDefinition) []*TrailerField {
+	if resp.Trailers == nil {
+		return nil
+	}
+	var fields []*TrailerField
+	resp.Trailers.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		field := codegen.Goify(n, true)
+		f := &TrailerField{
+			Name:        http.CanonicalHeaderKey(n),
+			Field:       field,
+			Type:        codegen.GoNativeType(att.Type),
+			Description: att.Description,
+		}
+		switch att.Type.Kind() {
+		case design.IntegerKind:
+			f.Format = fmt.Sprintf("strconv.Itoa(*t.%s)", field)
+			f.Parse = "strconv.Atoi(raw)"
+		case design.NumberKind:
+			f.Format = fmt.Sprintf("strconv.FormatFloat(*t.%s, 'f', -1, 64)", field)
+			f.Parse = "strconv.ParseFloat(raw, 64)"
+		case design.BooleanKind:
+			f.Format = fmt.Sprintf("strconv.FormatBool(*t.%s)", field)
+			f.Parse = "strconv.ParseBool(raw)"
+		case design.UUIDKind:
+			f.Format = fmt.Sprintf("t.%s.String()", field)
+			f.Parse = "uuid.FromString(raw)"
+		case design.DateTimeKind:
+			f.Format = fmt.Sprintf("t.%s.Format(time.RFC3339)", field)
+			f.Parse = "time.Parse(time.RFC3339, raw)"
+		default:
+			f.Format = "*t." + field
+		}
+		fields = append(fields, f)
+		return nil
+	})
+	return fields
+}
+
+const (
+	// ctxTrailersT generates the trailers struct and the methods that announce and write the
+	// trailers of a response.
+	// template input: map[string]interface{}
+	ctxTrailersT = `{{ $resp := goify .Response.Name true }}{{ $type := printf "%s%s%sTrailers" (goify .Context.ActionName true) (goify .Context.ResourceName true) $resp }}
+// {{ $type }} holds the trailers of the {{ .Response.Name }} response of the {{ .Context.ResourceName }} {{ .Context.ActionName }} action.
+type {{ $type }} struct {
+{{ range .Trailers }}{{ if .Description }}	{{ comment .Description }}
+{{ end }}	{{ .Field }} *{{ .Type }}
+{{ end }}}
+
+// Announce{{ $resp }}Trailers lists the trailers of the {{ .Response.Name }} response in the Trailer header.
+// The response helpers call it automatically, handlers that write the response body directly must
+// call it before writing the body.
+func (ctx *{{ .Context.Name }}) Announce{{ $resp }}Trailers() {
+	ctx.ResponseData.AnnounceTrailers({{ range $i, $t := .Trailers }}{{ if $i }}, {{ end }}"{{ $t.Name }}"{{ end }})
+}
+
+// Write{{ $resp }}Trailers sets the trailers of the {{ .Response.Name }} response, call it once the response
+// body has been written.
+func (ctx *{{ .Context.Name }}) Write{{ $resp }}Trailers(t *{{ $type }}) {
+{{ range .Trailers }}	if t.{{ .Field }} != nil {
+		ctx.ResponseData.SetTrailer("{{ .Name }}", {{ .Format }})
+	}
+{{ end }}}
+`
+)
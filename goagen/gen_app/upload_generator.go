@@ -0,0 +1,107 @@
+package genapp
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// UploadTemplateData contains the information required to mount the routes of a resumable upload
+// action.
+type UploadTemplateData struct {
+	MaxSize  int64    // Maximum upload size, 0 means no limit
+	Required []string // Required metadata
+	Paths    []string // Full paths of the routes that receive the upload chunks
+}
+
+// PresignTemplateData contains the information required to mount the routes of a presigned
+// upload action.
+type PresignTemplateData struct {
+	Bucket string   // Bucket name
+	Expiry uint     // Number of seconds during which the presigned URLs are valid
+	Paths  []string // Full paths of the completion callback routes
+}
+
+// UploadField describes the field of the struct generated for a metadata of a resumable upload.
+type UploadField struct {
+	Name        string // Metadata name
+	Field       string // Go field name
+	Pointer     bool   // Whether the field is a pointer
+	Description string // Metadata description
+}
+
+// uploadData returns the data used to mount the routes of the given resumable upload action, nil
+// if the action does not receive resumable uploads.
+func uploadData(a *design.ActionDefinition) *UploadTemplateData {
+	u := a.Upload
+	if u == nil {
+		return nil
+	}
+	return &UploadTemplateData{
+		MaxSize:  u.MaxSize,
+		Required: u.RequiredMetadata(),
+		Paths:    u.Paths(),
+	}
+}
+
+// presignData returns the data used to mount the routes of the given presigned upload action, nil
+// if the action does not handle presigned uploads.
+func presignData(a *design.ActionDefinition) *PresignTemplateData {
+	p := a.PresignedUpload
+	if p == nil {
+		return nil
+	}
+	return &PresignTemplateData{
+		Bucket: p.Bucket,
+		Expiry: p.Expiry,
+		Paths:  p.Paths(),
+	}
+}
+
+// UploadFields returns the fields of the struct generated for the metadata of the given upload
+// sorted by metadata name.
+func UploadFields(u *design.UploadDefinition) []*UploadField {
+	if u.Metadata == nil {
+		return nil
+	}
+	var fields []*UploadField
+	u.Metadata.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		fields = append(fields, &UploadField{
+			Name:        n,
+			Field:       codegen.GoifyAtt(att, n, true),
+			Pointer:     !u.Metadata.IsRequired(n),
+			Description: att.Description,
+		})
+		return nil
+	})
+	return fields
+}
+
+const (
+	// uploadT generates the type that describes the completed uploads of a resumable upload
+	// action.
+	// template input: *ContextTemplateData
+	uploadT = `
+// {{ .Upload }} is the upload completed by the {{ .ResourceName }} {{ .ActionName }} action.
+type {{ .Upload }} struct {
+	// ID identifies the upload in the store registered with upload.UseStore.
+	ID string
+	// Size is the size of the upload in bytes.
+	Size int64
+{{ range uploadFields .UploadDef }}{{ if .Description }}	{{ comment .Description }}
+{{ end }}	{{ .Field }} {{ if .Pointer }}*{{ end }}string
+{{ end }}}
+
+// new{{ .Upload }} builds the completed upload from the upload information, it returns nil if the
+// request did not complete an upload.
+func new{{ .Upload }}(info *upload.Info) *{{ .Upload }} {
+	if info == nil {
+		return nil
+	}
+	u := &{{ .Upload }}{ID: info.ID, Size: info.Length}
+{{ range uploadFields .UploadDef }}	if v, ok := info.Metadata[{{ printf "%q" .Name }}]; ok {
+		u.{{ .Field }} = {{ if .Pointer }}&{{ end }}v
+	}
+{{ end }}	return u
+}
+`
+)
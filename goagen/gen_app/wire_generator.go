@@ -0,0 +1,175 @@
+package genapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// WireFixture holds the data needed to generate the wire format test of a media type view.
+type WireFixture struct {
+	Name       string // Test function name
+	TypeName   string // Go type name of media type view
+	Identifier string // Media type identifier
+	View       string // Name of view
+	File       string // Path of fixture relative to the package directory
+}
+
+// generateWireFixtures generates the golden JSON fixtures of each media type view built from the
+// design examples together with the tests asserting that the generated types reproduce them. The
+// tests compare the decoded JSON values rather than the bytes so that the order of the fields
+// written by generated MarshalJSON methods does not matter. The fixtures only depend on the design
+// so that changes to the generated marshaling code that alter the wire format make the tests fail.
+func (g *Generator) generateWireFixtures() error {
+	var (
+		fixtures []*WireFixture
+		rand     = design.NewRandomGenerator(g.API.Name)
+		dir      = filepath.Join(g.OutDir, "testdata", "wire")
+	)
+	err := g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsError() || !(mt.Type.IsObject() || mt.Type.IsArray()) {
+			return nil
+		}
+		return mt.IterateViews(func(view *design.ViewDefinition) error {
+			p, _, err := mt.Project(view.Name)
+			if err != nil {
+				return err
+			}
+			example := wireValue(p.AttributeDefinition, p.GenerateExample(rand, nil))
+			if example == nil || example == "-" {
+				return nil
+			}
+			b, err := json.MarshalIndent(example, "", "  ")
+			if err != nil {
+				return nil
+			}
+			if len(fixtures) == 0 {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return err
+				}
+			}
+			typeName := codegen.GoTypeName(p, p.AllRequired(), 0, false)
+			file := codegen.SnakeCase(typeName) + ".json"
+			if err := ioutil.WriteFile(filepath.Join(dir, file), append(b, '\n'), 0644); err != nil {
+				return err
+			}
+			fixtures = append(fixtures, &WireFixture{
+				Name:       "TestWire" + typeName,
+				TypeName:   typeName,
+				Identifier: mt.Identifier,
+				View:       view.Name,
+				File:       filepath.ToSlash(filepath.Join("testdata", "wire", file)),
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return nil
+	}
+	g.genfiles = append(g.genfiles, dir)
+
+	wireFile := filepath.Join(g.OutDir, "wire_test.go")
+	file, err := codegen.SourceFileFor(wireFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Wire Format Tests", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("reflect"),
+		codegen.SimpleImport("testing"),
+	}
+	g.genfiles = append(g.genfiles, wireFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("wire", wireT, nil, fixtures); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// wireValue returns the value encoded by the generated code for the given example: the example
+// fields that are not attributes of the type, e.g. attributes hidden by the view, are removed and
+// the fields of attributes that emit aliases are duplicated under their legacy names.
+func wireValue(att *design.AttributeDefinition, example interface{}) interface{} {
+	if fields, ok := example.(map[string]interface{}); ok {
+		obj := att.Type.ToObject()
+		if obj == nil {
+			return fields
+		}
+		res := make(map[string]interface{}, len(fields))
+		for n, v := range fields {
+			catt, ok := obj[n]
+			if !ok {
+				continue
+			}
+			res[n] = wireValue(catt, v)
+			if catt.EmitsAliases() {
+				for _, alias := range catt.Aliases() {
+					res[alias] = res[n]
+				}
+			}
+		}
+		return res
+	}
+	if arr := att.Type.ToArray(); arr != nil {
+		v := reflect.ValueOf(example)
+		if v.Kind() != reflect.Slice {
+			return example
+		}
+		res := make([]interface{}, v.Len())
+		for i := range res {
+			res[i] = wireValue(arr.ElemType, v.Index(i).Interface())
+		}
+		return res
+	}
+	return example
+}
+
+const (
+	// wireT generates the wire format tests.
+	// template input: []*WireFixture
+	wireT = `// assertWire checks that decoding the fixture in v and encoding v back produces the same JSON
+// value as the fixture, regardless of the order of the object fields.
+func assertWire(t *testing.T, fixture string, v interface{}) {
+	data, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %s", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode fixture %s: %s", fixture, err)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode fixture %s: %s", fixture, err)
+	}
+	var got, expected interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("failed to decode encoded fixture %s: %s", fixture, err)
+	}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("invalid fixture %s: %s", fixture, err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wire format of %s changed, got:\n%s\nexpected:\n%s", fixture, b, data)
+	}
+}
+{{ range . }}
+// {{ .Name }} checks the wire format of the {{ .View }} view of the {{ .Identifier }} media type.
+func {{ .Name }}(t *testing.T) {
+	var mt {{ .TypeName }}
+	assertWire(t, {{ printf "%q" .File }}, &mt)
+}
+{{ end }}`
+)
@@ -0,0 +1,142 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// WorkflowTemplateData holds the data needed to generate the state machine of a workflow.
+	WorkflowTemplateData struct {
+		Name        string                      // Workflow name
+		TypeName    string                      // Prefix of the generated type names
+		Description string                      // Workflow description
+		Steps       []*WorkflowStepTemplateData // Workflow steps in execution order
+	}
+
+	// WorkflowStepTemplateData holds the data needed to generate the hooks of a workflow step.
+	WorkflowStepTemplateData struct {
+		Name                 string // Step name
+		Method               string // Name of the method that runs the step
+		Resource             string // Name of the resource of the action called by the step
+		Action               string // Name of the action called by the step
+		CompensationResource string // Name of the resource of the compensating action if any
+		CompensationAction   string // Name of the compensating action if any
+	}
+)
+
+// workflowData returns the data needed to generate the state machine of the given workflow.
+func workflowData(w *design.WorkflowDefinition) *WorkflowTemplateData {
+	data := &WorkflowTemplateData{
+		Name:        w.Name,
+		TypeName:    codegen.Goify(w.Name, true),
+		Description: w.Description,
+	}
+	for _, s := range w.Steps {
+		data.Steps = append(data.Steps, &WorkflowStepTemplateData{
+			Name:                 s.Name,
+			Method:               codegen.Goify(s.Name, true),
+			Resource:             s.Resource,
+			Action:               s.Action,
+			CompensationResource: s.CompensationResource,
+			CompensationAction:   s.CompensationAction,
+		})
+	}
+	return data
+}
+
+// generateWorkflows generates the state machines of the API workflows.
+func (g *Generator) generateWorkflows() error {
+	if len(g.API.Workflows) == 0 {
+		return nil
+	}
+	workflows := make([]*WorkflowTemplateData, len(g.API.Workflows))
+	for i, w := range g.API.Workflows {
+		workflows[i] = workflowData(w)
+	}
+
+	workflowsFile := filepath.Join(g.OutDir, "workflows.go")
+	file, err := codegen.SourceFileFor(workflowsFile)
+	if err != nil {
+		return err
+	}
+	title := fmt.Sprintf("%s: Application Workflows", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa/workflow"),
+	}
+	g.genfiles = append(g.genfiles, workflowsFile)
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("workflows", workflowsT, nil, workflows); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const (
+	// workflowsT generates the state machines of the workflows.
+	// template input: []*WorkflowTemplateData
+	workflowsT = `{{ range . }}{{ $w := . }}
+// {{ .TypeName }}State is the state of a {{ printf "%q" .Name }} workflow: the name of the running step
+// or one of the final states.
+type {{ .TypeName }}State string
+
+const (
+	// {{ .TypeName }}Pending is the state of the workflows that have not started.
+	{{ .TypeName }}Pending {{ .TypeName }}State = workflow.StatePending
+{{ range .Steps }}	// {{ $w.TypeName }}{{ .Method }} is the state of the workflows running the {{ printf "%q" .Name }} step.
+	{{ $w.TypeName }}{{ .Method }} {{ $w.TypeName }}State = {{ printf "%q" .Name }}
+{{ end }}	// {{ .TypeName }}Completed is the state of the workflows whose steps all completed.
+	{{ .TypeName }}Completed {{ .TypeName }}State = workflow.StateCompleted
+	// {{ .TypeName }}Compensated is the state of the workflows whose completed steps were undone
+	// after a step failed.
+	{{ .TypeName }}Compensated {{ .TypeName }}State = workflow.StateCompensated
+	// {{ .TypeName }}Failed is the state of the workflows whose completed steps could not be undone.
+	{{ .TypeName }}Failed {{ .TypeName }}State = workflow.StateFailed
+)
+
+// {{ .TypeName }}Steps is the interface implemented by the service to run the steps of the
+// {{ printf "%q" .Name }} workflow.{{ if .Description }}
+//
+{{ comment .Description }}{{ end }}
+type {{ .TypeName }}Steps interface {
+{{ range .Steps }}	// {{ .Method }} runs the {{ printf "%q" .Name }} step by calling the {{ .Resource }} {{ .Action }} action.
+	{{ .Method }}(ctx context.Context) error
+{{ if .CompensationAction }}	// Compensate{{ .Method }} undoes the {{ printf "%q" .Name }} step by calling the {{ .CompensationResource }} {{ .CompensationAction }} action.
+	Compensate{{ .Method }}(ctx context.Context) error
+{{ end }}{{ end }}}
+
+// {{ .TypeName }}Workflow runs the steps of the {{ printf "%q" .Name }} workflow in order, the completed
+// steps are compensated in reverse order when a step fails.
+type {{ .TypeName }}Workflow struct {
+	*workflow.Workflow
+}
+
+// New{{ .TypeName }}Workflow returns a {{ printf "%q" .Name }} workflow that runs the given steps.
+// onTransition is called each time the workflow state changes if not nil.
+func New{{ .TypeName }}Workflow(steps {{ .TypeName }}Steps, onTransition func(from, to {{ .TypeName }}State)) *{{ .TypeName }}Workflow {
+	w := &workflow.Workflow{
+		Name: {{ printf "%q" .Name }},
+		Steps: []*workflow.Step{
+{{ range .Steps }}			{Name: {{ printf "%q" .Name }}, Run: steps.{{ .Method }}{{ if .CompensationAction }}, Compensate: steps.Compensate{{ .Method }}{{ end }}},
+{{ end }}		},
+	}
+	if onTransition != nil {
+		w.OnTransition = func(from, to string) {
+			onTransition({{ .TypeName }}State(from), {{ .TypeName }}State(to))
+		}
+	}
+	return &{{ .TypeName }}Workflow{Workflow: w}
+}
+
+// State returns the current state of the workflow.
+func (w *{{ .TypeName }}Workflow) State() {{ .TypeName }}State {
+	return {{ .TypeName }}State(w.Workflow.State())
+}
+{{ end }}`
+)
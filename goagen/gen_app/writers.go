@@ -67,17 +67,28 @@ type (
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
-		Name         string // e.g. "ListBottleContext"
-		ResourceName string // e.g. "bottles"
-		ActionName   string // e.g. "list"
-		Params       *design.AttributeDefinition
-		Payload      *design.UserTypeDefinition
-		Headers      *design.AttributeDefinition
-		Routes       []*design.RouteDefinition
-		Responses    map[string]*design.ResponseDefinition
-		API          *design.APIDefinition
-		DefaultPkg   string
-		Security     *design.SecurityDefinition
+		Name          string // e.g. "ListBottleContext"
+		ResourceName  string // e.g. "bottles"
+		ActionName    string // e.g. "list"
+		Params        *design.AttributeDefinition
+		Payload       *design.UserTypeDefinition
+		Stream        string // Name of the streaming payload iterator type if any
+		StreamElement *design.UserTypeDefinition
+		Decompression *design.DecompressionDefinition // Streaming payload decompression settings if any
+		Headers       *design.AttributeDefinition
+		Routes        []*design.RouteDefinition
+		Responses     map[string]*design.ResponseDefinition
+		API           *design.APIDefinition
+		DefaultPkg    string
+		Security      *design.SecurityDefinition
+		Interceptors  []*InterceptorTemplateData
+		ContextValues []*design.ContextValueDefinition
+		Download      *design.DownloadDefinition // Download settings of downloadable actions if any
+		Upload        string                     // Name of the completed upload type if any
+		UploadDef     *design.UploadDefinition   // Resumable upload settings if any
+		Presigned     bool                       // Whether the action handles presigned uploads
+		Evented       bool                       // Whether the action publishes events
+		Outbox        bool                       // Whether the action runs in an outbox transaction
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
@@ -90,6 +101,56 @@ type (
 		Decoders       []*EncoderTemplateData         // Decoder data
 		Origins        []*design.CORSDefinition       // CORS policies
 		PreflightPaths []string
+		OptionsPaths   []*OptionsTemplateData        // Paths handled by the generated OPTIONS handlers
+		Compression    *design.CompressionDefinition // Response compression settings if any
+		Errors         []*ErrorTemplateData          // Sentinel errors declared by the resource
+	}
+
+	// OptionsTemplateData contains the information required to mount a generated OPTIONS
+	// handler.
+	OptionsTemplateData struct {
+		Path    string   // Full path
+		Methods []string // HTTP methods listed in the Allow header
+	}
+
+	// MaintenanceTemplateData contains the information required to check the maintenance
+	// switch of an action.
+	MaintenanceTemplateData struct {
+		Resource string // Design name of the resource
+		Action   string // Design name of the action
+		Status   int    // Status of the maintenance response
+		Error    bool   // Whether the maintenance response renders the error media type
+	}
+
+	// FlagTemplateData contains the information required to gate an action behind a feature
+	// flag.
+	FlagTemplateData struct {
+		Name   string // Name of the feature flag
+		Status int    // Status of the response sent when the flag is off
+		Error  bool   // Whether the response renders the error media type
+	}
+
+	// CaptureTemplateData contains the information required to capture the requests and
+	// responses of an action.
+	CaptureTemplateData struct {
+		Resource    string   // Design name of the resource
+		Action      string   // Design name of the action
+		MaxBodySize int      // Maximum number of captured response body bytes
+		Payload     []string // Paths to the sensitive payload attributes
+		Response    []string // Paths to the sensitive response attributes
+		Headers     []string // Names of the sensitive headers
+		Params      []string // Names of the sensitive parameters
+	}
+
+	// CacheTemplateData contains the information required to cache the responses of an action.
+	CacheTemplateData struct {
+		MaxAge               uint     // Number of seconds during which the responses are fresh
+		StaleWhileRevalidate uint     // Number of seconds during which stale responses are served
+		Vary                 []string // Request headers that select the cached responses
+		Secured              bool     // Whether the action requires credentials
+		Credential           string   // Request header carrying the credentials if any
+		Authorized           bool     // Whether the scopes and roles are checked before the cache
+		Audited              bool     // Whether the audit events are emitted around the cache
 	}
 
 	// ResourceData contains the information required to generate the resource GoGenerator
@@ -100,6 +161,16 @@ type (
 		Type              *design.MediaTypeDefinition // Type of resource media type
 		CanonicalTemplate string                      // CanonicalFormat represents the resource canonical path in the form of a fmt.Sprintf format.
 		CanonicalParams   []string                    // CanonicalParams is the list of parameter names that appear in the resource canonical path in order.
+		Hrefs             []*MediaTypeHrefData        // Hrefs lists the Href methods of the resource media type projections.
+	}
+
+	// MediaTypeHrefData contains the information required to generate the Href method of a
+	// projected media type whose canonical href can be built from its attributes.
+	MediaTypeHrefData struct {
+		TypeName string   // Go name of projected media type, e.g. "BottleTiny"
+		Args     []string // Canonical parameters that are not attributes of the media type
+		Assigns  []string // Statements that initialize the variables holding the attribute values
+		Params   []string // Arguments of the resource href function in order
 	}
 
 	// EncoderTemplateData contains the data needed to render the registration code for a single
@@ -200,12 +271,22 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 	}
 	fn := template.FuncMap{
 		"newCoerceData":      newCoerceData,
+		"coerceScalar":       coerceScalar,
+		"isScalar":           codegen.IsScalar,
 		"arrayAttribute":     arrayAttribute,
+		"isFlags":            codegen.IsFlags,
+		"flagsAssignment":    flagsAssignment,
 		"canonicalHeaderKey": http.CanonicalHeaderKey,
+		"defaultAssignment":  defaultAssignment,
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
 	}
+	if data.Stream != "" {
+		if err := w.ExecuteTemplate("stream", streamT, nil, data); err != nil {
+			return err
+		}
+	}
 	if data.Payload != nil {
 		found := false
 		for _, t := range design.Design.Types {
@@ -220,11 +301,32 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			}
 		}
 	}
+	if data.Upload != "" {
+		ufn := template.FuncMap{"uploadFields": UploadFields}
+		if err := w.ExecuteTemplate("upload", uploadT, ufn, data); err != nil {
+			return err
+		}
+	}
+	if data.Download != nil {
+		dfn := template.FuncMap{
+			"downloadFilename":    downloadFilename,
+			"downloadContentType": downloadContentType,
+		}
+		if err := w.ExecuteTemplate("download", ctxDownloadT, dfn, data); err != nil {
+			return err
+		}
+	}
 	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
 			"Response": resp,
 		}
+		if trailers := TrailerFields(resp); len(trailers) > 0 {
+			respData["Trailers"] = trailers
+			if err := w.ExecuteTemplate("trailers", ctxTrailersT, nil, respData); err != nil {
+				return err
+			}
+		}
 		var mt *design.MediaTypeDefinition
 		if resp.Type != nil {
 			var ok bool
@@ -258,6 +360,17 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				respData["ViewName"] = view
 				respData["MediaType"] = mt
 				respData["ContentType"] = mt.ContentType
+				intercept := computeResult(projected)
+				intercept += interceptResult(data.Interceptors, projected)
+				intercept += locationHeader(resp, projected)
+				intercept += encryptResult(projected)
+				if data.Evented && resp.Status < 400 {
+					intercept += "\tctx.eventData = r\n"
+				}
+				if mt.IsError() && design.Design.RequestID != nil {
+					intercept += "\tr = middleware.ErrorWithRequestID(ctx.Context, r)\n"
+				}
+				respData["Intercept"] = intercept
 				if view == "default" {
 					respData["RespName"] = codegen.Goify(resp.Name, true)
 				} else {
@@ -297,6 +410,11 @@ func (w *ControllersWriter) WriteInitService(encoders, decoders []*EncoderTempla
 	return nil
 }
 
+// WriteErrors writes the sentinel errors declared by the API.
+func (w *ControllersWriter) WriteErrors(errs []*ErrorTemplateData) error {
+	return w.ExecuteTemplate("errors", errorsT, nil, errs)
+}
+
 // Execute writes the handlers GoGenerator
 func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 	if len(data) == 0 {
@@ -309,6 +427,14 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
 			return err
 		}
+		if len(d.Errors) > 0 {
+			if err := w.ExecuteTemplate("errors", errorsT, nil, d.Errors); err != nil {
+				return err
+			}
+		}
+		if err := w.ExecuteTemplate("mapErrors", mapErrorsT, nil, d); err != nil {
+			return err
+		}
 		if len(d.Origins) > 0 {
 			if err := w.ExecuteTemplate("handleCORS", handleCORST, nil, d); err != nil {
 				return err
@@ -405,6 +531,22 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	return w.ExecuteTemplate("types", userTypeT, nil, t)
 }
 
+// ExecuteEnum writes the code for the given enum type.
+func (w *UserTypesWriter) ExecuteEnum(s *design.ScalarDefinition) error {
+	fm := make(template.FuncMap)
+	fm["enumConst"] = codegen.EnumConst
+	fm["printVal"] = codegen.PrintVal
+	return w.ExecuteTemplate("enum", enumT, fm, s)
+}
+
+// ExecuteFlags writes the code for the given flags type.
+func (w *UserTypesWriter) ExecuteFlags(f *design.FlagsDefinition) error {
+	fm := make(template.FuncMap)
+	fm["flagConst"] = codegen.FlagConst
+	fm["flagsNames"] = codegen.FlagsNamesVar
+	return w.ExecuteTemplate("flags", flagsT, fm, f)
+}
+
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
 func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
 	return map[string]interface{}{
@@ -417,9 +559,65 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 	}
 }
 
+// coerceScalar returns the code that parses the raw parameter or header value into the custom
+// scalar held by the given coerce data, see newCoerceData. The raw value is first converted to the
+// scalar wire type.
+func coerceScalar(data map[string]interface{}) string {
+	name := data["Name"].(string)
+	s := data["Attribute"].(*design.AttributeDefinition).Type.(*design.ScalarDefinition)
+	tabs := codegen.Tabs(data["Depth"].(int))
+	raw := "raw" + codegen.Goify(name, true)
+	assign := "v"
+	if data["Pointer"].(bool) {
+		assign = "&v"
+	}
+	fail := fmt.Sprintf("%s\terr = goa.MergeErrors(err, goa.InvalidParamTypeError(%q, %s, %q))\n", tabs, name, raw, s.TypeName)
+	code, wire := tabs, "w"
+	switch s.Kind() {
+	case design.BooleanKind:
+		code = fmt.Sprintf("%sif w, err2 := strconv.ParseBool(%s); err2 != nil {\n%s%s} else ", tabs, raw, fail, tabs)
+	case design.IntegerKind:
+		code = fmt.Sprintf("%sif w, err2 := strconv.Atoi(%s); err2 != nil {\n%s%s} else ", tabs, raw, fail, tabs)
+	case design.NumberKind:
+		code = fmt.Sprintf("%sif w, err2 := strconv.ParseFloat(%s, 64); err2 != nil {\n%s%s} else ", tabs, raw, fail, tabs)
+	default:
+		wire = raw
+	}
+	code += fmt.Sprintf("if v, err2 := %s(%s); err2 != nil {\n%s%s} else {\n%s\t%s = %s\n%s}\n",
+		s.ParseFunc, wire, fail, tabs, tabs, data["Pkg"], assign, tabs)
+	return code
+}
+
+// flagsAssignment returns the code that parses the flag names held by the variable src into the
+// flags parameter or header target.
+func flagsAssignment(name string, att *design.AttributeDefinition, target, src string, depth int) string {
+	f := att.Type.(*design.FlagsDefinition)
+	tabs := codegen.Tabs(depth)
+	return fmt.Sprintf("%sif v, err2 := Parse%s(%s); err2 != nil {\n%s\terr = goa.MergeErrors(err, goa.InvalidParamTypeError(%q, %s, %q))\n%s} else {\n%s\t%s = v\n%s}\n",
+		tabs, f.GoType, src, tabs, name, src, f.TypeName, tabs, tabs, target, tabs)
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
-	return a.Type.(*design.Array).ElemType
+	return a.Type.ToArray().ElemType
+}
+
+// defaultAssignment returns the code that assigns the default value of the attribute to target.
+func defaultAssignment(att *design.AttributeDefinition, target string, depth int) string {
+	tabs := codegen.Tabs(depth)
+	if s, ok := att.Type.(*design.ScalarDefinition); ok {
+		return fmt.Sprintf("%s%s, _ = %s(%s)", tabs, target, s.ParseFunc, codegen.PrintVal(att.Type, att.DefaultValue))
+	}
+	if f, ok := att.Type.(*design.FlagsDefinition); ok {
+		return fmt.Sprintf("%s%s = %s", tabs, target, codegen.FlagsVal(f, att.DefaultValue))
+	}
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s%s, _ = time.Parse(time.RFC3339, %q)", tabs, target, att.DefaultValue)
+	case design.UUIDKind:
+		return fmt.Sprintf("%s%s = uuid.FromStringOrNil(%q)", tabs, target, att.DefaultValue)
+	}
+	return fmt.Sprintf("%s%s = %s", tabs, target, codegen.PrintVal(att.Type, att.DefaultValue))
 }
 
 const (
@@ -435,12 +633,20 @@ type {{ .Name }} struct {
 {{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
+{{ end }}{{ if .Stream }}	Stream *{{ .Stream }}
+{{ end }}{{ if .Upload }}	Upload *{{ .Upload }}
+{{ end }}{{ if .Presigned }}	Upload *upload.Object
+{{ end }}{{ if .Outbox }}	// Outbox is the transaction of the request, the changes and events recorded with it
+	// commit atomically.
+	Outbox outbox.Tx
+{{ end }}{{ if .Evented }}	// eventData is the result published in the action events.
+	eventData interface{}
 {{ end }}}
 `
 	// coerceT generates the code that coerces the generic deserialized
 	// data to the actual type.
 	// template input: map[string]interface{} as returned by newCoerceData
-	coerceT = `{{ if eq .Attribute.Type.Kind 1 }}{{/*
+	coerceT = `{{ if isScalar .Attribute.Type }}{{ coerceScalar . }}{{ else }}{{ if eq .Attribute.Type.Kind 1 }}{{/*
 
 */}}{{/* BooleanType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
@@ -502,7 +708,7 @@ type {{ .Name }} struct {
 */}}{{ if .Pointer }}{{ $tmp := tempvar }}{{ tabs .Depth }}{{ $tmp }} := interface{}(raw{{ goify .Name true }})
 {{ tabs .Depth }}{{ .Pkg }} = &{{ $tmp }}
 {{ else }}{{ tabs .Depth }}{{ .Pkg }} = raw{{ goify .Name true }}
-{{ end }}{{ end }}`
+{{ end }}{{ end }}{{ end }}`
 
 	// ctxNewT generates the code for the context factory method.
 	// template input: *ContextTemplateData
@@ -520,15 +726,18 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 {{ $mustValidate := $.Headers.IsRequired $name }}{{ if $mustValidate }}	if len(header{{ goify $name true }}) == 0 {
 		err = goa.MergeErrors(err, goa.MissingHeaderError("{{ $name }}"))
 	} else {
+{{ else if $.Headers.HasDefaultValue $name }}	if len(header{{ goify $name true }}) == 0 {
+{{ defaultAssignment $att (printf "rctx.%s" (goifyatt $att $name true)) 2 }}
+	} else {
 {{ else }}	if len(header{{ goify $name true }}) > 0 {
 {{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		req.Params["{{ $name }}"] = header{{ goify $name true }}
-{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := header{{ goify $name true }}
+{{ if and (eq (arrayAttribute $att).Type.Kind 4) (not (isScalar (arrayAttribute $att).Type)) }}		headers := header{{ goify $name true }}
 {{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range header{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
 */}}		}
-{{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = headers
-{{ else }}		raw{{ goify $name true}} := header{{ goify $name true}}[0]
+{{ end }}{{ if isFlags $att.Type }}{{ flagsAssignment $name $att (printf "rctx.%s" (goifyatt $att $name true)) "headers" 2 }}{{ else }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = headers
+{{ end }}{{ else }}		raw{{ goify $name true}} := header{{ goify $name true}}[0]
 		req.Params["{{ $name }}"] = []string{raw{{ goify $name true }}}
 {{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
@@ -536,23 +745,83 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Headers }}{{/*
 
-*/}}{{ if.Params }}{{ range $name, $att := .Params.Type.ToObject }}	param{{ goify $name true }} := req.Params["{{ $name }}"]
+*/}}{{ if.Params }}{{ range $name, $att := .Params.Type.ToObject }}{{ if $att.Type.IsObject }}{{/*
+
+*/}}{{ $obj := printf "obj%s" (goify $name true) }}	{{ $obj }} := &{{ gotypename $att.Type nil 0 false }}{}
+	set{{ goify $name true }} := false
+{{ range $fname, $fatt := $att.Type.ToObject }}{{ $wire := printf "%s.%s" $name $fname }}{{ $field := printf "%s.%s" $obj (goifyatt $fatt $fname true) }}{{/*
+*/}}	param{{ goify $wire true }} := {{ with $fatt.ArrayStyle.Delimiter }}goa.SplitParams(req.Params["{{ $wire }}"], {{ printf "%q" . }}){{ else }}req.Params["{{ $wire }}"]{{ end }}
+	if len(param{{ goify $wire true }}) > 0 {
+		set{{ goify $name true }} = true
+{{ if $fatt.Type.IsArray }}{{ if eq (arrayAttribute $fatt).Type.Kind 4 }}		{{ $field }} = param{{ goify $wire true }}
+{{ else }}		params := make({{ gotypedef $fatt 2 true false }}, len(param{{ goify $wire true }}))
+		for i, raw{{ goify $wire true }} := range param{{ goify $wire true }} {
+{{ template "Coerce" (newCoerceData $wire (arrayAttribute $fatt) false "params[i]" 3) }}{{/*
+*/}}		}
+		{{ $field }} = params
+{{ end }}{{ else }}		raw{{ goify $wire true }} := param{{ goify $wire true }}[0]
+{{ template "Coerce" (newCoerceData $wire $fatt ($att.Type.IsPrimitivePointer $fname) $field 2) }}{{ end }}	}
+{{ end }}	if set{{ goify $name true }} {
+		rctx.{{ goifyatt $att $name true }} = {{ $obj }}
+{{ if recursiveValidate $att.Type.AttributeDefinition false false false "ut" "response" 1 false }}		if err2 := {{ $obj }}.Validate(); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+{{ end }}	}{{ if $.MustValidate $name }} else {
+		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
+	}{{ end }}
+{{ else }}	param{{ goify $name true }} := {{ with $att.ArrayStyle.Delimiter }}goa.SplitParams(req.Params["{{ $name }}"], {{ printf "%q" . }}){{ else }}req.Params["{{ $name }}"]{{ end }}
 {{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}"))
 	} else {
+{{ else if $.Params.HasDefaultValue $name }}	if len(param{{ goify $name true }}) == 0 {
+{{ defaultAssignment $att (printf "rctx.%s" (goifyatt $att $name true)) 2 }}
+	} else {
 {{ else }}	if len(param{{ goify $name true }}) > 0 {
-{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
+{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if and (eq (arrayAttribute $att).Type.Kind 4) (not (isScalar (arrayAttribute $att).Type)) }}		params := param{{ goify $name true }}
 {{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
 */}}		}
-{{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
-{{ else }}		raw{{ goify $name true}} := param{{ goify $name true}}[0]
+{{ end }}{{ if isFlags $att.Type }}{{ flagsAssignment $name $att (printf "rctx.%s" (goifyatt $att $name true)) "params" 2 }}{{ else }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
+{{ end }}{{ else }}		raw{{ goify $name true}} := param{{ goify $name true}}[0]
 {{ template "Coerce" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
 {{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{ end }}{{/* if .Params */}}{{ range .ContextValues }}	if _, ok := Context{{ goify .Name true }}(ctx); !ok {
+		err = goa.MergeErrors(err, goa.ErrInternal("missing context value, make sure the middleware that sets it is mounted", "name", {{ printf "%q" .Name }}))
+	}
+{{ end }}{{ if .Stream }}{{ with .Decompression }}	if derr := goa.DecompressRequest(req.Request, {{ .MaxSize }}); derr != nil {
+		err = goa.MergeErrors(err, derr)
+	}
+{{ end }}	rctx.Stream = &{{ .Stream }}{stream: goa.NewPayloadStream(req.Body)}
+{{ end }}{{ if .Upload }}	rctx.Upload = new{{ .Upload }}(upload.ContextInfo(ctx))
+{{ end }}{{ if .Presigned }}	rctx.Upload = upload.ContextObject(ctx)
+{{ end }}{{ if .Outbox }}	rctx.Outbox = outbox.ContextTx(ctx)
+{{ end }}	return &rctx, err
+}
+`
+
+	// streamT generates the iterator type of an action streaming payload.
+	// template input: *ContextTemplateData
+	streamT = `{{ $elem := .StreamElement }}{{ $private := gotypename $elem $elem.AllRequired 0 true }}
+// {{ .Stream }} iterates over the elements of the {{ .ResourceName }} {{ .ActionName }} action streaming payload.
+type {{ .Stream }} struct {
+	stream *goa.PayloadStream
+}
+
+// Recv decodes and validates the next element of the streaming payload. It returns io.EOF once
+// all the elements have been received.
+func (s *{{ .Stream }}) Recv() ({{ gotyperef $elem $elem.AllRequired 0 false }}, error) {
+	var elem {{ $private }}
+	if err := s.stream.Decode(&elem); err != nil {
+		return nil, err
+	}{{ $assignment := recursiveFinalizer $elem.AttributeDefinition "elem" 1 }}{{ if $assignment }}
+	elem.Finalize(){{ end }}{{ $validation := recursiveValidate $elem.AttributeDefinition false false false "elem" "raw" 1 true }}{{ if $validation }}
+	if err := elem.Validate(); err != nil {
+		return nil, err
+	}{{ end }}
+	return elem.Publicize(), nil
 }
 `
 
@@ -561,7 +830,8 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 	ctxMTRespT = `// {{ goify .RespName true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ if .Response.Trailers }}	ctx.Announce{{ goify .Response.Name true }}Trailers()
+{{ end }}{{ .Intercept }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
@@ -570,7 +840,8 @@ func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Proje
 	ctxTRespT = `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .Type nil 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ if .Response.Trailers }}	ctx.Announce{{ goify .Response.Name true }}Trailers()
+{{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
@@ -580,6 +851,7 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .
 // {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.MediaType }}resp []byte{{ end }}) error {
 {{ if .Response.MediaType }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
+{{ end }}{{ if .Response.Trailers }}	ctx.Announce{{ goify .Response.Name true }}Trailers()
 {{ end }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
 	_, err := ctx.ResponseData.Write(resp)
 	return err{{ else }}
@@ -592,7 +864,9 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.
 	payloadT = `{{ $payload := .Payload }}{{ if .Payload.IsObject }}// {{ gotypename .Payload nil 0 true }} is the {{ .ResourceName }} {{ .ActionName }} action payload.{{/*
 */}}{{ $privateTypeName := gotypename .Payload nil 1 true }}
 type {{ $privateTypeName }} {{ gotypedef .Payload 0 true true }}
-
+{{ $unmarshaler := aliasUnmarshaler .Payload.AttributeDefinition "payload" $privateTypeName }}{{ if $unmarshaler }}
+{{ $unmarshaler }}
+{{ end }}
 {{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}// Finalize sets the default values defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
@@ -613,7 +887,11 @@ func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Publicize()
 
 // {{ gotypename .Payload nil 0 false }} is the {{ .ResourceName }} {{ .ActionName }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
-
+{{ $presence := presenceMethods .Payload.AttributeDefinition "payload" (gotyperef .Payload .Payload.AllRequired 0 false) }}{{ if $presence }}
+{{ $presence }}
+{{ end }}{{ $marshalers := wireMarshalers .Payload.AttributeDefinition "payload" (gotypename .Payload nil 0 false) }}{{ if $marshalers }}
+{{ $marshalers }}
+{{ end }}
 {{ $validation := recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}// Validate runs the validation rules defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
@@ -632,22 +910,41 @@ type {{ .Resource }}Controller interface {
 
 	// serviceT generates the service initialization code.
 	// template input: *ControllerTemplateData
-	serviceT = `
+	serviceT = `{{ if .Encoders }}
+// Encoders lists the constructors of the encoders used to write the response bodies indexed by
+// content type. Replace an entry before mounting the controllers to use a different encoder
+// implementation for the content type, e.g. a faster JSON encoder.
+var Encoders = map[string]goa.EncoderFunc{
+{{ range .Encoders }}{{ $enc := . }}{{ range .MIMETypes }}{{/*
+*/}}	{{ printf "%q" . }}: {{ $enc.PackageName }}.{{ $enc.Function }},
+{{ end }}{{ end }}}
+{{ end }}{{ if .Decoders }}
+// Decoders lists the constructors of the decoders used to read the request bodies indexed by
+// content type. Replace an entry before mounting the controllers to use a different decoder
+// implementation for the content type.
+var Decoders = map[string]goa.DecoderFunc{
+{{ range .Decoders }}{{ $dec := . }}{{ range .MIMETypes }}{{/*
+*/}}	{{ printf "%q" . }}: {{ $dec.PackageName }}.{{ $dec.Function }},
+{{ end }}{{ end }}}
+{{ end }}
 // initService sets up the service encoders, decoders and mux.
 func initService(service *goa.Service) {
 	// Setup encoders and decoders
-{{ range .Encoders }}{{/*
-*/}}	service.Encoder.Register({{ .PackageName }}.{{ .Function }}, "{{ join .MIMETypes "\", \"" }}")
-{{ end }}{{ range .Decoders }}{{/*
-*/}}	service.Decoder.Register({{ .PackageName }}.{{ .Function }}, "{{ join .MIMETypes "\", \"" }}")
-{{ end }}
+{{ range .Encoders }}{{ range .MIMETypes }}{{/*
+*/}}	service.Encoder.Register(Encoders[{{ printf "%q" . }}], {{ printf "%q" . }})
+{{ end }}{{ end }}{{ range .Decoders }}{{ range .MIMETypes }}{{/*
+*/}}	service.Decoder.Register(Decoders[{{ printf "%q" . }}], {{ printf "%q" . }})
+{{ end }}{{ end }}
 
 	// Setup default encoder and decoder
 {{ range .Encoders }}{{ if .Default }}{{/*
-*/}}	service.Encoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
+*/}}	service.Encoder.Register(Encoders[{{ printf "%q" (index .MIMETypes 0) }}], "*/*")
 {{ end }}{{ end }}{{ range .Decoders }}{{ if .Default }}{{/*
-*/}}	service.Decoder.Register({{ .PackageName }}.{{ .Function }}, "*/*")
-{{ end }}{{ end }}}
+*/}}	service.Decoder.Register(Decoders[{{ printf "%q" (index .MIMETypes 0) }}], "*/*")
+{{ end }}{{ end }}{{ with .API.MethodOverride }}
+	// Route the tunneled requests using the overriding method
+	service.Mux = goa.MethodOverrideMux(service.Mux, {{ printf "%q" .Header }}, {{ printf "%q" .Field }})
+{{ end }}}
 `
 
 	// mountT generates the code for a resource "Mount" function.
@@ -657,7 +954,12 @@ func initService(service *goa.Service) {
 func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
 	initService(service)
 	var h goa.Handler
-{{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}{{/*
+{{ with .Compression }}	compressor := compress.Middleware(compress.Options{
+		Encodings: {{ printf "%#v" .Encodings }},
+		MinSize:   {{ .MinSize }},
+{{ if .ContentTypes }}		ContentTypes: {{ printf "%#v" .ContentTypes }},
+{{ end }}	})
+{{ end }}{{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}{{/*
 */}}	service.Mux.Handle("OPTIONS", "{{ . }}", ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil))
 {{ end }}{{ end }}{{ range .Actions }}{{ $action := . }}
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -670,24 +972,86 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 		if err != nil {
 			return err
 		}
-{{ if .Payload }}		// Build the payload
+{{ if .Authorized }}		// Check the required scopes and roles
+		if err := goa.Authorize(ctx, {{ if .Security.Scopes }}{{ printf "%#v" .Security.Scopes }}{{ else }}nil{{ end }}, {{ if .Security.Roles }}{{ printf "%#v" .Security.Roles }}{{ else }}nil{{ end }}); err != nil {
+			return {{ if .Forbidden }}rctx.{{ .Forbidden }}({{ if .ForbiddenError }}err{{ end }}){{ else }}err{{ end }}
+		}
+{{ end }}{{ if .Payload }}		// Build the payload
 		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
 			rctx.Payload = rawPayload.({{ gotyperef .Payload nil 1 false }})
 {{ if not .PayloadOptional }}		} else {
 			return goa.MissingPayloadError()
 {{ end }}		}
-{{ end }}		return ctrl.{{ .Name }}(rctx)
-	}
-{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ end }}{{ with .DecryptPayload }}		// Decrypt the encrypted payload attributes
+{{ . }}{{ end }}{{ if or .Audited .Evented }}		err = {{ if .Intercepted }}intercept{{ .Context }}(rctx, ctrl.{{ .Name }}){{ else }}ctrl.{{ .Name }}(rctx){{ end }}
+{{ if .Audited }}		audit{{ .Context }}(rctx, err)
+{{ end }}{{ if .Evented }}		{{ if .Outbox }}err = {{ end }}emit{{ .Context }}(rctx, err)
+{{ end }}		return {{ if .Errors }}handle{{ .Context }}Error(rctx, err){{ else }}err{{ end }}
+{{ else }}		return {{ if .Errors }}handle{{ .Context }}Error(rctx, {{ end }}{{ if .Intercepted }}intercept{{ .Context }}(rctx, ctrl.{{ .Name }}){{ else }}ctrl.{{ .Name }}(rctx){{ end }}{{ if .Errors }}){{ end }}
+{{ end }}	}
+{{ if .Outbox }}	h = outbox.Transactional()(h)
+{{ end }}{{ with .Upload }}	h = upload.Resumable(upload.Options{MaxSize: {{ .MaxSize }}{{ if .Required }}, Required: {{ printf "%#v" .Required }}{{ end }}})(h)
+{{ end }}{{ with .Presign }}	h = upload.Presigned(upload.PresignOptions{Bucket: {{ printf "%q" .Bucket }}, Expiry: {{ .Expiry }} * time.Second})(h)
+{{ end }}{{ with .Cache }}	h = cache.Middleware(cache.Policy{MaxAge: {{ .MaxAge }}, StaleWhileRevalidate: {{ .StaleWhileRevalidate }}{{ if .Vary }}, Vary: {{ printf "%#v" .Vary }}{{ end }}{{ if .Secured }}, Secured: true{{ if .Credential }}, Credential: {{ printf "%q" .Credential }}{{ end }}{{ end }}})(h)
+{{ if or .Authorized .Audited }}	h = func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			// Check if there was an error loading the request
+			if err := goa.ContextError(ctx); err != nil {
+				return err
+			}
+{{ if or .Audited $action.Forbidden }}			// Build the context
+			rctx, err := New{{ $action.Context }}(ctx, service)
+			if err != nil {
+				return err
+			}
+{{ end }}{{ if .Authorized }}			// Check the required scopes and roles before serving cached responses
+			if err := goa.Authorize(ctx, {{ if $action.Security.Scopes }}{{ printf "%#v" $action.Security.Scopes }}{{ else }}nil{{ end }}, {{ if $action.Security.Roles }}{{ printf "%#v" $action.Security.Roles }}{{ else }}nil{{ end }}); err != nil {
+				return {{ if $action.Forbidden }}rctx.{{ $action.Forbidden }}({{ if $action.ForbiddenError }}err{{ end }}){{ else }}err{{ end }}
+			}
+{{ end }}{{ if .Audited }}{{ if $action.Payload }}			if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
+				rctx.Payload = rawPayload.({{ gotyperef $action.Payload nil 1 false }})
+			}
+{{ end }}			// Emit the audit event of the cached and computed responses
+			err = h(ctx, rw, req)
+			audit{{ $action.Context }}(rctx, err)
+			return err
+{{ else }}			return h(ctx, rw, req)
+{{ end }}		}
+	}(h)
+{{ end }}{{ end }}{{ with .Capture }}	h = capture.Middleware(capture.Options{
+		Resource:    {{ printf "%q" .Resource }},
+		Action:      {{ printf "%q" .Action }},
+		MaxBodySize: {{ .MaxBodySize }},
+{{ if .Payload }}		Payload:     {{ printf "%#v" .Payload }},
+{{ end }}{{ if .Response }}		Response:    {{ printf "%#v" .Response }},
+{{ end }}{{ if .Headers }}		Headers:     {{ printf "%#v" .Headers }},
+{{ end }}{{ if .Params }}		Params:      {{ printf "%#v" .Params }},
+{{ end }}	})(h)
+{{ end }}{{ if $.Compression }}	h = compressor(h)
+{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ end }}{{ with .Concurrency }}	h = middleware.ConcurrencyLimit({{ .Max }}, {{ .Shed }}, {{ .RetryAfter }}, {{ .MaxWait }}*time.Second)(h)
+{{ end }}{{ with .Maintenance }}	h = service.Maintenance.Handle({{ printf "%q" .Resource }}, {{ printf "%q" .Action }}, goa.MaintenanceResponse{Status: {{ .Status }}, Error: {{ .Error }}})(h)
+{{ end }}{{ with .Flag }}	h = feature.Gate(feature.Options{Flag: {{ printf "%q" .Name }}, Status: {{ .Status }}, Error: {{ .Error }}})(h)
 {{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ with .Upload }}{{ range .Paths }}	service.Mux.Handle("HEAD", {{ printf "%q" . }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "HEAD %s" .) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+	service.Mux.Handle("PATCH", {{ printf "%q" . }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "PATCH %s" .) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ end }}{{ with .Presign }}{{ range .Paths }}	service.Mux.Handle("POST", {{ printf "%q" . }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "POST %s" .) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ end }}{{ range .HeadPaths }}	service.Mux.Handle("HEAD", {{ printf "%q" . }}, goa.HeadHandler(ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, nil)))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "HEAD %s" .) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}{{ end }}{{ range .FileServers }}
 	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
 {{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" .RequestPath) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}{{ range .OptionsPaths }}
+	service.Mux.Handle("OPTIONS", {{ printf "%q" .Path }}, ctrl.MuxHandler("options", goa.OptionsHandler({{ range $i, $m := .Methods }}{{ if $i }}, {{ end }}{{ printf "%q" $m }}{{ end }}), nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "route", {{ printf "%q" (printf "OPTIONS %s" .Path) }})
 {{ end }}}
 `
 
@@ -728,12 +1092,18 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 	unmarshalT = `{{ range .Actions }}{{ if .Payload }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
 func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
-	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+{{ with .Decompression }}	if err := goa.DecompressRequest(req, {{ .MaxSize }}); err != nil {
+		return err
+	}
+{{ end }}{{ with .Limits }}	if err := goa.LimitRequest(req, goa.PayloadLimits{MaxDepth: {{ .MaxDepth }}, MaxArrayLength: {{ .MaxArrayLength }}, MaxMapSize: {{ .MaxMapSize }}}); err != nil {
+		return err
+	}
+{{ end }}	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	if err := service.DecodeRequest{{ if .Strict }}Strict{{ end }}(req, payload); err != nil {
 		return err
 	}{{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
 	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
-	if err := service.DecodeRequest(req, &payload); err != nil {
+	if err := service.DecodeRequest{{ if .Strict }}Strict{{ end }}(req, &payload); err != nil {
 		return err
 	}{{ end }}{{ $validation := recursiveValidate .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
 	if err := payload.Validate(); err != nil {
@@ -755,6 +1125,12 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 {{ end }}{{ if .CanonicalParams }}	return fmt.Sprintf("{{ .CanonicalTemplate }}", param{{ join .CanonicalParams ", param" }})
 {{ else }}	return "{{ .CanonicalTemplate }}"
 {{ end }}}
+{{ end }}{{ $name := .Name }}{{ range .Hrefs }}
+// Href returns the canonical href of the {{ .TypeName }} media type instance.
+func (mt *{{ .TypeName }}) Href({{ if .Args }}{{ join .Args ", " }} interface{}{{ end }}) string {
+{{ range .Assigns }}{{ . }}
+{{ end }}	return {{ $name }}Href({{ join .Params ", " }})
+}
 {{ end }}`
 
 	// mediaTypeT generates the code for a media type.
@@ -763,7 +1139,11 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 //
 // Identifier: {{ .Identifier }}{{ $typeName := gotypename . .AllRequired 0 false }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
-
+{{ $presence := presenceMethods .AttributeDefinition "mt" (gotyperef . .AllRequired 0 false) }}{{ if $presence }}
+{{ $presence }}
+{{ end }}{{ $marshalers := wireMarshalers .AttributeDefinition "mt" $typeName }}{{ if $marshalers }}
+{{ $marshalers }}
+{{ end }}
 {{ $validation := recursiveValidate .AttributeDefinition false false false "mt" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} media type instance.
 func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
@@ -787,7 +1167,9 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 	// template input: UserTypeTemplateData
 	userTypeT = `// {{ gotypedesc . false }}{{ $privateTypeName := gotypename . .AllRequired 0 true }}
 type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
-{{ $assignment := recursiveFinalizer .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values for {{$privateTypeName}} type instance.
+{{ $unmarshaler := aliasUnmarshaler .AttributeDefinition "ut" $privateTypeName }}{{ if $unmarshaler }}
+{{ $unmarshaler }}
+{{ end }}{{ $assignment := recursiveFinalizer .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values for {{$privateTypeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
@@ -806,11 +1188,158 @@ func (ut {{ gotyperef . .AllRequired 0 true }}) Publicize() {{ gotyperef . .AllR
 
 // {{ gotypedesc . true }}
 type {{ $typeName }} {{ gotypedef . 0 true false }}
-{{ $validation := recursiveValidate .AttributeDefinition false false false "ut" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} type instance.
+{{ $presence := presenceMethods .AttributeDefinition "ut" (gotyperef . .AllRequired 0 false) }}{{ if $presence }}
+{{ $presence }}
+{{ end }}{{ $marshalers := wireMarshalers .AttributeDefinition "ut" $typeName }}{{ if $marshalers }}
+{{ $marshalers }}
+{{ end }}{{ $validation := recursiveValidate .AttributeDefinition false false false "ut" "response" 1 false }}{{ if $validation }}// Validate validates the {{$typeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }{{ end }}
+`
+
+	// flagsT generates the code for a flags type.
+	// template input: *design.FlagsDefinition
+	flagsT = `{{ $names := flagsNames . }}// {{ .GoType }} is the {{ .TypeName }} flags type, values are encoded as arrays of flag names.
+type {{ .GoType }} uint64
+
+// {{ .GoType }} flags.
+const (
+{{ range $i, $n := .Names }}	{{ flagConst $ $n }}{{ if not $i }} {{ $.GoType }} = 1 << iota{{ end }}
+{{ end }})
+
+// {{ $names }} lists the {{ .GoType }} flag names in bit order.
+var {{ $names }} = []string{ {{ range $i, $n := .Names }}{{ if $i }}, {{ end }}{{ printf "%q" $n }}{{ end }} }
+
+// Parse{{ .GoType }} converts the given flag names into a {{ .GoType }}, it returns an error if a
+// name is not one of the flag names.
+func Parse{{ .GoType }}(names []string) ({{ .GoType }}, error) {
+	var f {{ .GoType }}
+	for _, n := range names {
+		found := false
+		for i, fn := range {{ $names }} {
+			if n == fn {
+				f |= 1 << uint(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, goa.InvalidEnumValueError("{{ .TypeName }}", n, []interface{}{ {{ range $i, $n := .Names }}{{ if $i }}, {{ end }}{{ printf "%q" $n }}{{ end }} })
+		}
+	}
+	return f, nil
+}
+
+// Has returns true if all the flags set in flags are set in f.
+func (f {{ .GoType }}) Has(flags {{ .GoType }}) bool {
+	return f&flags == flags
+}
+
+// Set sets the given flags in f.
+func (f *{{ .GoType }}) Set(flags {{ .GoType }}) {
+	*f |= flags
+}
+
+// Clear clears the given flags in f.
+func (f *{{ .GoType }}) Clear(flags {{ .GoType }}) {
+	*f &^= flags
+}
+
+// Names returns the names of the flags set in f in bit order.
+func (f {{ .GoType }}) Names() []string {
+	names := []string{}
+	for i, n := range {{ $names }} {
+		if f&(1<<uint(i)) != 0 {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// String returns the comma separated names of the flags set in f.
+func (f {{ .GoType }}) String() string {
+	return strings.Join(f.Names(), ",")
+}
+
+// MarshalJSON encodes f as the array of the names of the flags it sets.
+func (f {{ .GoType }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Names())
+}
+
+// UnmarshalJSON decodes an array of flag names into f.
+func (f *{{ .GoType }}) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	p, err := Parse{{ .GoType }}(names)
+	if err != nil {
+		return err
+	}
+	*f = p
+	return nil
+}
+`
+
+	// enumT generates the code for an enum type.
+	// template input: *design.ScalarDefinition
+	enumT = `{{ $native := gonative .Primitive }}// {{ .GoType }} is the {{ .TypeName }} enum type{{ if .Description }}: {{ .Description }}{{ end }}.
+type {{ .GoType }} {{ $native }}
+
+// {{ .GoType }} values.
+const (
+{{ range .Values }}	{{ enumConst $ . }} {{ $.GoType }} = {{ printVal $.Primitive . }}
+{{ end }})
+
+// Parse{{ .GoType }} converts v into a {{ .GoType }}, it returns an error if v is not one of the
+// enum values.
+func Parse{{ .GoType }}(v {{ $native }}) ({{ .GoType }}, error) {
+	e := {{ .GoType }}(v)
+	return e, e.Validate()
+}
+
+// IsValid returns true if e is one of the enum values.
+func (e {{ .GoType }}) IsValid() bool {
+	switch e {
+	case {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ enumConst $ $v }}{{ end }}:
+		return true
+	}
+	return false
+}
+
+// Validate returns an error if e is not one of the enum values.
+func (e {{ .GoType }}) Validate() error {
+	if !e.IsValid() {
+		return goa.InvalidEnumValueError("{{ .TypeName }}", {{ $native }}(e), []interface{}{ {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ printVal $.Primitive $v }}{{ end }} })
+	}
+	return nil
+}
+
+// String returns the wire representation of e.
+func (e {{ .GoType }}) String() string {
+	return {{ if eq $native "string" }}string(e){{ else }}strconv.Itoa(int(e)){{ end }}
+}
+
+// MarshalJSON encodes e using its wire representation.
+func (e {{ .GoType }}) MarshalJSON() ([]byte, error) {
+	return json.Marshal({{ $native }}(e))
+}
+
+// UnmarshalJSON decodes e and makes sure it is one of the enum values.
+func (e *{{ .GoType }}) UnmarshalJSON(data []byte) error {
+	var v {{ $native }}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	p, err := Parse{{ .GoType }}(v)
+	if err != nil {
+		return err
+	}
+	*e = p
+	return nil
+}
 `
 
 	// securitySchemesT generates the code for the security module.
@@ -842,7 +1371,9 @@ func {{ $funcName }}() *goa.{{ .Context }} {
 {{ end }}{{/*
 */}}		},{{ end }}{{/*
 */}}{{ else if eq .Context "BasicAuthSecurity" }}{{/*
-*/}}{{ else if eq .Context "JWTSecurity" }}{{/*
+*/}}{{ else if eq .Context "SessionSecurity" }}{{/*
+*/}}		CookieName: {{ printf "%q" .CookieName }},
+{{ else if eq .Context "JWTSecurity" }}{{/*
 */}}		In:   {{ if eq .In "header" }}goa.LocHeader{{ else }}goa.LocQuery{{ end }},
 		Name:             {{ printf "%q" .Name }},
 		TokenURL:         {{ printf "%q" .TokenURL }},{{ with .Scopes }}
@@ -856,7 +1387,51 @@ func {{ $funcName }}() *goa.{{ .Context }} {
 {{ end }}	return &def
 }
 
-{{ end }}// handleSecurity creates a handler that runs the auth middleware for the security scheme.
+{{ if eq .Context "APIKeySecurity" }}{{ $funcName := printf "New%sKeyStoreMiddleware" (goify .SchemeName true) }}{{/*
+*/}}// {{ $funcName }} creates a {{ .SchemeName }} auth middleware that validates the API keys using the
+// given key store.
+func {{ $funcName }}(store apikey.KeyStore) goa.Middleware {
+	return apikey.New(store, New{{ goify .SchemeName true }}Security())
+}
+
+{{ end }}{{ if eq .Context "SessionSecurity" }}{{ $name := goify .SchemeName true }}{{/*
+*/}}{{ $data := "map[string]interface{}" }}{{ if .SessionData }}{{ $data = gotyperef .SessionData nil 0 false }}{{ end }}{{/*
+*/}}// New{{ $name }}SessionMiddleware creates a {{ .SchemeName }} auth middleware that loads the sessions from
+// the given store.
+func New{{ $name }}SessionMiddleware(store session.Store) goa.Middleware {
+	return session.New(store, New{{ $name }}Security())
+}
+
+// Start{{ $name }}Session starts a {{ .SchemeName }} session holding the given data and sets the
+// session cookie.
+func Start{{ $name }}Session(ctx context.Context, rw http.ResponseWriter, store session.Store, data {{ $data }}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = session.Start(ctx, rw, store, New{{ $name }}Security(), b)
+	return err
+}
+
+// End{{ $name }}Session ends the {{ .SchemeName }} session of the request and clears the session cookie.
+func End{{ $name }}Session(ctx context.Context, rw http.ResponseWriter, store session.Store) error {
+	return session.End(ctx, rw, store, New{{ $name }}Security())
+}
+
+// Context{{ $name }}Session returns the data of the {{ .SchemeName }} session loaded by the middleware.
+func Context{{ $name }}Session(ctx context.Context) ({{ $data }}, error) {
+	s := session.ContextSession(ctx)
+	if s == nil {
+		return nil, errors.New("no {{ .SchemeName }} session")
+	}
+	var data {{ $data }}
+	if err := json.Unmarshal(s.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+{{ end }}{{ end }}// handleSecurity creates a handler that runs the auth middleware for the security scheme.
 func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Handler {
 	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		scheme := ctx.Value(authMiddlewareKey(schemeName))
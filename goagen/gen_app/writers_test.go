@@ -50,6 +50,7 @@ var _ = Describe("ContextsWriter", func() {
 			var params, headers *design.AttributeDefinition
 			var payload *design.UserTypeDefinition
 			var responses map[string]*design.ResponseDefinition
+			var download *design.DownloadDefinition
 
 			var data *genapp.ContextTemplateData
 
@@ -58,6 +59,7 @@ var _ = Describe("ContextsWriter", func() {
 				headers = nil
 				payload = nil
 				responses = nil
+				download = nil
 				data = nil
 			})
 
@@ -72,6 +74,7 @@ var _ = Describe("ContextsWriter", func() {
 					Responses:    responses,
 					API:          design.Design,
 					DefaultPkg:   "",
+					Download:     download,
 				}
 			})
 
@@ -130,6 +133,102 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a response defining trailers", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:      "OK",
+						Status:    200,
+						MediaType: "application/x-ndjson",
+						Trailers: &design.AttributeDefinition{
+							Type: design.Object{
+								"X-Checksum":     {Type: design.String, Description: "Body checksum"},
+								"X-Record-Count": {Type: design.Integer},
+							},
+						},
+					}}
+				})
+
+				It("announces and writes the trailers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(trailersCode))
+					Ω(written).Should(ContainSubstring("\tctx.AnnounceOKTrailers()\n\tctx.ResponseData.WriteHeader(200)"))
+				})
+			})
+
+			Context("with a downloadable action", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					params = &design.AttributeDefinition{
+						Type: design.Object{"name": {Type: design.String}},
+					}
+					responses = map[string]*design.ResponseDefinition{"OK": {
+						Name:      "OK",
+						Status:    200,
+						MediaType: "application/pdf",
+					}}
+					action := &design.ActionDefinition{Name: "list", Responses: responses}
+					download = &design.DownloadDefinition{Filename: "name", Inline: true, Parent: action}
+				})
+
+				It("writes the Download method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(downloadCode))
+				})
+			})
+
+			Context("with a resumable upload action", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+				})
+
+				It("writes the completed upload type", func() {
+					data.Upload = "ListBottlesUpload"
+					data.UploadDef = &design.UploadDefinition{
+						Metadata: &design.AttributeDefinition{
+							Type: design.Object{
+								"filename": {Type: design.String, Description: "Name of uploaded file"},
+								"filetype": {Type: design.String},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"filename"}},
+						},
+					}
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tUpload *ListBottlesUpload\n"))
+					Ω(written).Should(ContainSubstring("\trctx.Upload = newListBottlesUpload(upload.ContextInfo(ctx))\n"))
+					Ω(written).Should(ContainSubstring(uploadCode))
+				})
+			})
+
+			Context("with a presigned upload action", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+				})
+
+				It("exposes the uploaded object", func() {
+					data.Presigned = true
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tUpload *upload.Object\n"))
+					Ω(written).Should(ContainSubstring("\trctx.Upload = upload.ContextObject(ctx)\n"))
+				})
+			})
+
 			Context("with an integer param", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{Type: design.Integer}
@@ -274,6 +373,61 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a comma separated integer array param", func() {
+				BeforeEach(func() {
+					i := &design.AttributeDefinition{Type: design.Integer}
+					intArrayParam := &design.AttributeDefinition{
+						Type:     &design.Array{ElemType: i},
+						Metadata: dslengine.MetadataDefinition{design.ArrayStyleMetadata: {string(design.StyleComma)}},
+					}
+					dataType := design.Object{
+						"param": intArrayParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+				})
+
+				It("splits the delimited values", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`paramParam := goa.SplitParams(req.Params["param"], ",")`))
+					Ω(written).Should(ContainSubstring("params := make([]int, len(paramParam))"))
+				})
+			})
+
+			Context("with an object user type param", func() {
+				BeforeEach(func() {
+					filter := &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"status": &design.AttributeDefinition{Type: design.String},
+								"min":    &design.AttributeDefinition{Type: design.Integer},
+							},
+						},
+						TypeName: "Filter",
+					}
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"filter": &design.AttributeDefinition{Type: filter},
+						},
+					}
+				})
+
+				It("binds the object from the query string", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tFilter *Filter\n"))
+					Ω(written).Should(ContainSubstring(objectParamContextFactory))
+				})
+			})
+
 			Context("with an param using a reserved keyword as name", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{Type: design.Integer}
@@ -324,6 +478,28 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a param with a default value", func() {
+				BeforeEach(func() {
+					intParam := &design.AttributeDefinition{Type: design.Integer, DefaultValue: 20}
+					dataType := design.Object{
+						"limit": intParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+				})
+
+				It("assigns the default value when the param is absent", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tLimit int\n"))
+					Ω(written).Should(ContainSubstring("if len(paramLimit) == 0 {\n\t\trctx.Limit = 20\n\t} else {"))
+				})
+			})
+
 			Context("with a custom name param", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{
@@ -991,6 +1167,81 @@ var _ = Describe("HrefWriter", func() {
 	})
 })
 
+var _ = Describe("UserTypesWriter", func() {
+	var writer *genapp.UserTypesWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("types")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+		writer, err = genapp.NewUserTypesWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an enum type", func() {
+		var enum *design.ScalarDefinition
+
+		BeforeEach(func() {
+			enum = &design.ScalarDefinition{
+				Primitive:    design.String,
+				TypeName:     "Status",
+				GoType:       "Status",
+				ParseFunc:    "ParseStatus",
+				FormatFunc:   "string",
+				ValidateFunc: "Status.Validate",
+				Values:       []interface{}{"active", "archived"},
+			}
+		})
+
+		It("writes the type, constants and methods", func() {
+			err := writer.ExecuteEnum(enum)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("type Status string"))
+			Ω(written).Should(ContainSubstring(`StatusActive Status = "active"`))
+			Ω(written).Should(ContainSubstring("case StatusActive, StatusArchived:"))
+			Ω(written).Should(ContainSubstring("func ParseStatus(v string) (Status, error) {"))
+			Ω(written).Should(ContainSubstring("func (e *Status) UnmarshalJSON(data []byte) error {"))
+		})
+	})
+
+	Context("with a flags type", func() {
+		var flags *design.FlagsDefinition
+
+		BeforeEach(func() {
+			flags = design.NewFlagsDefinition("Permissions", "Permissions", "read", "write", "admin")
+		})
+
+		It("writes the bitmask type, constants and methods", func() {
+			err := writer.ExecuteFlags(flags)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("type Permissions uint64"))
+			Ω(written).Should(ContainSubstring("PermissionsRead Permissions = 1 << iota\n\tPermissionsWrite\n\tPermissionsAdmin\n"))
+			Ω(written).Should(ContainSubstring("var permissionsNames = []string{"))
+			Ω(written).Should(ContainSubstring("func ParsePermissions(names []string) (Permissions, error) {"))
+			Ω(written).Should(ContainSubstring("func (f Permissions) Has(flags Permissions) bool {"))
+			Ω(written).Should(ContainSubstring("func (f *Permissions) Set(flags Permissions) {"))
+			Ω(written).Should(ContainSubstring("func (f *Permissions) Clear(flags Permissions) {"))
+			Ω(written).Should(ContainSubstring("return json.Marshal(f.Names())"))
+		})
+	})
+})
+
 const (
 	emptyContext = `
 type ListBottleContext struct {
@@ -1577,3 +1828,101 @@ type BottlesController interface {
 }
 `
 )
+
+const objectParamContextFactory = `
+	objFilter := &Filter{}
+	setFilter := false
+	paramFilterMin := req.Params["filter.min"]
+	if len(paramFilterMin) > 0 {
+		setFilter = true
+		rawFilterMin := paramFilterMin[0]
+		if filterMin, err2 := strconv.Atoi(rawFilterMin); err2 == nil {
+			tmp2 := filterMin
+			tmp1 := &tmp2
+			objFilter.Min = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("filter.min", rawFilterMin, "integer"))
+		}
+	}
+	paramFilterStatus := req.Params["filter.status"]
+	if len(paramFilterStatus) > 0 {
+		setFilter = true
+		rawFilterStatus := paramFilterStatus[0]
+		objFilter.Status = &rawFilterStatus
+	}
+	if setFilter {
+		rctx.Filter = objFilter
+	}
+	return &rctx, err
+}
+`
+
+const uploadCode = `
+// ListBottlesUpload is the upload completed by the bottles list action.
+type ListBottlesUpload struct {
+	// ID identifies the upload in the store registered with upload.UseStore.
+	ID string
+	// Size is the size of the upload in bytes.
+	Size int64
+	// Name of uploaded file
+	Filename string
+	Filetype *string
+}
+
+// newListBottlesUpload builds the completed upload from the upload information, it returns nil if the
+// request did not complete an upload.
+func newListBottlesUpload(info *upload.Info) *ListBottlesUpload {
+	if info == nil {
+		return nil
+	}
+	u := &ListBottlesUpload{ID: info.ID, Size: info.Length}
+	if v, ok := info.Metadata["filename"]; ok {
+		u.Filename = v
+	}
+	if v, ok := info.Metadata["filetype"]; ok {
+		u.Filetype = &v
+	}
+	return u
+}
+`
+
+const downloadCode = `
+// Download sends content as the file downloaded by the bottles list action. It sends the
+// requested part of the file with status code 206 for Range requests, sets the Content-Disposition
+// header and the resume token clients send back to resume interrupted downloads. modtime is the
+// last modification time of the file, it may be zero if unknown in which case the resume token is
+// derived from a hash of the content.
+func (ctx *ListBottleContext) Download(content io.ReadSeeker, modtime time.Time) error {
+	info := goa.DownloadInfo{ContentType: "application/pdf", Inline: true, ModTime: modtime}
+	if ctx.Name != nil {
+		info.Filename = *ctx.Name
+	}
+	return goa.ServeDownload(ctx.ResponseData, ctx.RequestData, content, info)
+}
+`
+
+const trailersCode = `// ListBottlesOKTrailers holds the trailers of the OK response of the bottles list action.
+type ListBottlesOKTrailers struct {
+	// Body checksum
+	XChecksum *string
+	XRecordCount *int
+}
+
+// AnnounceOKTrailers lists the trailers of the OK response in the Trailer header.
+// The response helpers call it automatically, handlers that write the response body directly must
+// call it before writing the body.
+func (ctx *ListBottleContext) AnnounceOKTrailers() {
+	ctx.ResponseData.AnnounceTrailers("X-Checksum", "X-Record-Count")
+}
+
+// WriteOKTrailers sets the trailers of the OK response, call it once the response
+// body has been written.
+func (ctx *ListBottleContext) WriteOKTrailers(t *ListBottlesOKTrailers) {
+	if t.XChecksum != nil {
+		ctx.ResponseData.SetTrailer("X-Checksum", *t.XChecksum)
+	}
+	if t.XRecordCount != nil {
+		ctx.ResponseData.SetTrailer("X-Record-Count", strconv.Itoa(*t.XRecordCount))
+	}
+}
+`
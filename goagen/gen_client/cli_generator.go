@@ -7,6 +7,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -21,6 +22,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		codegen.SimpleImport("io/ioutil"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("os"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport(clientPkg),
 		codegen.SimpleImport(cliPkg),
@@ -28,6 +30,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 
 	funcs["defaultRouteParams"] = defaultRouteParams
 	funcs["defaultRouteTemplate"] = defaultRouteTemplate
@@ -52,9 +55,14 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 	hasBasicAuthSigners := false
 	hasAPIKeySigners := false
 	hasTokenSigners := false
+	hasSessionSigners := false
 	for _, s := range g.API.SecuritySchemes {
 		if signerType(s) != "" {
 			hasSigners = true
+			if s.Kind == design.SessionSecurityKind {
+				hasSessionSigners = true
+				continue
+			}
 			switch s.Type {
 			case "basic":
 				hasBasicAuthSigners = true
@@ -74,6 +82,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		HasBasicAuthSigners bool
 		HasAPIKeySigners    bool
 		HasTokenSigners     bool
+		HasSessionSigners   bool
 	}{
 		API:                 g.API,
 		Version:             version,
@@ -82,6 +91,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		HasBasicAuthSigners: hasBasicAuthSigners,
 		HasAPIKeySigners:    hasAPIKeySigners,
 		HasTokenSigners:     hasTokenSigners,
+		HasSessionSigners:   hasSessionSigners,
 	}
 	if err := file.ExecuteTemplate("main", mainTmpl, funcs, data); err != nil {
 		return err
@@ -90,6 +100,29 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 	return file.FormatCode()
 }
 
+// replaySigners returns the endpoints of the captured actions indexed by the name of the client
+// signer of their security scheme, the endpoints are identified by resource and action names
+// separated with "#". It returns nil if no action is captured.
+func replaySigners(api *design.APIDefinition) map[string][]string {
+	var signers map[string][]string
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		if res.AllCapture() == nil {
+			return nil
+		}
+		if signers == nil {
+			signers = make(map[string][]string)
+		}
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.Security != nil {
+				name := codegen.Goify(action.Security.Scheme.SchemeName, true)
+				signers[name] = append(signers[name], res.Name+"#"+action.Name)
+			}
+			return nil
+		})
+	})
+	return signers
+}
+
 func (g *Generator) generateCommands(commandsFile string, clientPkg string, funcs template.FuncMap) error {
 	file, err := codegen.SourceFileFor(commandsFile)
 	if err != nil {
@@ -102,6 +135,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	funcs["joinFieldNames"] = joinFieldhNames
 	funcs["routes"] = routes
 	funcs["flagType"] = flagType
+	funcs["flagDefault"] = flagDefault
 	funcs["cmdFieldType"] = cmdFieldTypeString
 	funcs["formatExample"] = formatExample
 	funcs["shouldAddExample"] = shouldAddExample
@@ -110,6 +144,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	commandsTmpl := template.Must(template.New("commands").Funcs(funcs).Parse(commandsTmpl))
 	commandsTmplWS := template.Must(template.New("commandsWS").Funcs(funcs).Parse(commandsTmplWS))
 	downloadCommandTmpl := template.Must(template.New("download").Funcs(funcs).Parse(downloadCommandTmpl))
+	replayCommandTmpl := template.Must(template.New("replay").Funcs(funcs).Parse(replayCommandTmpl))
 	registerTmpl := template.Must(template.New("register").Funcs(funcs).Parse(registerTmpl))
 
 	imports := []*codegen.ImportSpec{
@@ -123,12 +158,14 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/capture"),
 		codegen.SimpleImport("github.com/spf13/cobra"),
 		codegen.SimpleImport(clientPkg),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	if len(g.API.Resources) > 0 {
 		imports = append(imports, codegen.NewImport("goaclient", "github.com/goadesign/goa/client"))
 	}
@@ -150,6 +187,11 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	if len(fs) > 0 {
 		file.Write([]byte(downloadCommandType))
 	}
+	signers := replaySigners(g.API)
+	hasReplay := signers != nil
+	if hasReplay {
+		file.Write([]byte(replayCommandType))
+	}
 	file.Write([]byte(")\n\n"))
 
 	actions := make(map[string][]*design.ActionDefinition)
@@ -172,10 +214,12 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		Actions      map[string][]*design.ActionDefinition
 		Package      string
 		HasDownloads bool
+		HasReplay    bool
 	}{
 		Actions:      actions,
 		Package:      g.Target,
 		HasDownloads: hasDownloads,
+		HasReplay:    hasReplay,
 	}
 	if err := file.ExecuteTemplate("registerCmds", registerCmdsT, funcs, data); err != nil {
 		return err
@@ -218,6 +262,18 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 			return err
 		}
 	}
+	if hasReplay {
+		data := struct {
+			Package string
+			Signers map[string][]string
+		}{
+			Package: g.Target,
+			Signers: signers,
+		}
+		if err := replayCommandTmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
 	err = g.API.IterateResources(func(res *design.ResourceDefinition) error {
 		return res.IterateActions(func(action *design.ActionDefinition) error {
 			data := map[string]interface{}{
@@ -346,6 +402,9 @@ func joinNames(useNil bool, atts ...*design.AttributeDefinition) string {
 // resolve non required, non array Param/QueryParam for access via CII flags.
 // Some types need convertion from string to 'Type' before calling rich client Commands.
 func flagTypeVal(a *design.AttributeDefinition, key string, field string) string {
+	if codegen.IsScalar(a.Type) || a.Type.IsObject() {
+		return "%s"
+	}
 	switch a.Type {
 	case design.Integer:
 		return `intFlagVal("` + key + `", ` + field + ")"
@@ -363,6 +422,12 @@ func flagTypeVal(a *design.AttributeDefinition, key string, field string) string
 // Special types like Number/UUID need to be converted from String
 // %s maps to specialTypeResult.Temps
 func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
+	if isReference(a.Type) || a.Type.IsObject() {
+		return "%s"
+	}
+	if codegen.IsScalar(a.Type) {
+		return "*%s"
+	}
 	switch a.Type {
 	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
 		return "*%s"
@@ -375,6 +440,9 @@ func flagRequiredTypeVal(a *design.AttributeDefinition, field string) string {
 // Special types like Number/UUID need to be converted from String
 // %s maps to specialTypeResult.Temps
 func flagTypeArrayVal(a *design.AttributeDefinition, field string) string {
+	if codegen.IsScalar(a.Type.ToArray().ElemType.Type) {
+		return "%s"
+	}
 	switch a.Type.ToArray().ElemType.Type {
 	case design.Number, design.Boolean, design.UUID, design.DateTime, design.Any:
 		return "%s"
@@ -408,7 +476,7 @@ type specialTypeResult struct {
 // }
 // resp, err := c.ShowX(ctx, path, TMP2)
 //
-func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
+func handleSpecialTypes(pkg string, atts ...*design.AttributeDefinition) specialTypeResult {
 	result := specialTypeResult{}
 	for _, att := range atts {
 		if att == nil {
@@ -428,6 +496,39 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 			a := obj[n]
 			field := fmt.Sprintf("cmd.%s", codegen.Goify(n, true))
 
+			if a.Type.IsObject() {
+				tmpVar := codegen.Tempvar()
+				if att.IsRequired(n) {
+					names = append(names, tmpVar)
+				} else {
+					optNames = append(optNames, tmpVar)
+				}
+				typeName := codegen.GoTypeName(a.Type, nil, 0, false)
+				if pkg != "" {
+					typeName = pkg + "." + typeName
+				}
+				result.Output += fmt.Sprintf(`
+	var %s *%s
+	if %s != "" {
+		if err := json.Unmarshal([]byte(%s), &%s); err != nil {
+			goa.LogError(ctx, "argument parse failed", "err", err)
+			return err
+		}
+	}`, tmpVar, typeName, field, field, tmpVar)
+				continue
+			}
+
+			if code := scalarFlagVal(a, n, field, att.IsRequired(n), pkg); code != "" {
+				tmpVar := codegen.Tempvar()
+				if att.IsRequired(n) {
+					names = append(names, tmpVar)
+				} else {
+					optNames = append(optNames, tmpVar)
+				}
+				result.Output += fmt.Sprintf(code, tmpVar, tmpVar)
+				continue
+			}
+
 			var typeHandler string
 			if !a.Type.IsArray() {
 				switch a.Type {
@@ -481,6 +582,51 @@ func handleSpecialTypes(atts ...*design.AttributeDefinition) specialTypeResult {
 	return result
 }
 
+// scalarFlagVal returns the code that parses the flag of a custom scalar parameter or of an array
+// of custom scalars into a temporary variable, empty string if the attribute is not a scalar. The
+// code is a format string where both verbs are replaced with the name of the variable. The variable
+// is a pointer unless the attribute is an array or the scalar Go type is already a pointer,
+// optional flags that are not set leave it nil.
+func scalarFlagVal(a *design.AttributeDefinition, name, field string, required bool, pkg string) string {
+	s, ok := a.Type.(*design.ScalarDefinition)
+	array := false
+	if !ok && a.Type.IsArray() {
+		s, ok = a.Type.ToArray().ElemType.Type.(*design.ScalarDefinition)
+		array = true
+	}
+	if !ok {
+		return ""
+	}
+	goType, parseFunc := s.GoType, s.ParseFunc
+	if s.IsEnum() {
+		// enum types are generated in the client package
+		goType, parseFunc = pkg+"."+goType, pkg+"."+parseFunc
+	}
+	fail := "\t\tgoa.LogError(ctx, \"argument parse failed\", \"err\", err)\n\t\treturn err\n"
+	wire, arg := "", "w"
+	switch s.Kind() {
+	case design.NumberKind:
+		wire, arg = "\t\twv, err := strconv.ParseFloat(w, 64)\n\t\tif err != nil {\n\t"+fail+"\t\t}\n", "wv"
+	case design.BooleanKind:
+		wire, arg = "\t\twv, err := strconv.ParseBool(w)\n\t\tif err != nil {\n\t"+fail+"\t\t}\n", "wv"
+	}
+	parse := fmt.Sprintf("%s\t\tv, err := %s(%s)\n\t\tif err != nil {\n\t%s\t\t}\n", wire, parseFunc, arg, fail)
+	if array {
+		return fmt.Sprintf("\n\tvar %%s []%s\n\tfor _, w := range %s {\n%s\t\t%%s = append(%%[1]s, v)\n\t}",
+			goType, field, parse)
+	}
+	cond := fmt.Sprintf("hasFlag(%q)", name)
+	if required {
+		cond = "true"
+	}
+	ptr, ref := "*", "&"
+	if s.IsReference() {
+		ptr, ref = "", ""
+	}
+	return fmt.Sprintf("\n\tvar %%s %s%s\n\tif %s {\n\t\tw := %s\n%s\t\t%%s = %sv\n\t}",
+		ptr, goType, cond, field, parse, ref)
+}
+
 // routes create the action command "Use" suffix.
 func routes(action *design.ActionDefinition) string {
 	var buf bytes.Buffer
@@ -510,6 +656,9 @@ func routes(action *design.ActionDefinition) string {
 // signerSignature returns the callee signature for the signer factory function for the given security
 // scheme.
 func signerSignature(sec *design.SecuritySchemeDefinition) string {
+	if sec.Kind == design.SessionSecurityKind {
+		return "session string"
+	}
 	switch sec.Type {
 	case "basic":
 		return "user, pass string"
@@ -527,6 +676,9 @@ func signerSignature(sec *design.SecuritySchemeDefinition) string {
 // signerArgs returns the caller signature for the signer factory function for the given security
 // scheme.
 func signerArgs(sec *design.SecuritySchemeDefinition) string {
+	if sec.Kind == design.SessionSecurityKind {
+		return "session"
+	}
 	switch sec.Type {
 	case "basic":
 		return "user, pass"
@@ -565,8 +717,10 @@ func flagType(att *design.AttributeDefinition) string {
 		case design.BooleanKind:
 			return "StringSlice"
 		default:
-			return flagType(att.Type.(*design.Array).ElemType) + "Slice"
+			return flagType(att.Type.ToArray().ElemType) + "Slice"
 		}
+	case design.ObjectKind:
+		return "String"
 	case design.UserTypeKind:
 		return flagType(att.Type.(*design.UserTypeDefinition).AttributeDefinition)
 	case design.MediaTypeKind:
@@ -576,6 +730,34 @@ func flagType(att *design.AttributeDefinition) string {
 	}
 }
 
+// flagDefault returns the Go literal of the default value of the flag corresponding to the given
+// attribute. Number, boolean, date time and UUID flags are string flags so their default values
+// are quoted.
+func flagDefault(att *design.AttributeDefinition) string {
+	switch flagType(att) {
+	case "String":
+		return strconv.Quote(fmt.Sprint(att.DefaultValue))
+	case "IntSlice", "StringSlice":
+		vals, ok := att.DefaultValue.([]interface{})
+		if !ok {
+			break
+		}
+		elems := make([]string, len(vals))
+		for i, v := range vals {
+			if flagType(att) == "IntSlice" {
+				elems[i] = fmt.Sprint(v)
+			} else {
+				elems[i] = strconv.Quote(fmt.Sprint(v))
+			}
+		}
+		if flagType(att) == "IntSlice" {
+			return "[]int{" + strings.Join(elems, ", ") + "}"
+		}
+		return "[]string{" + strings.Join(elems, ", ") + "}"
+	}
+	return fmt.Sprintf("%#v", att.DefaultValue)
+}
+
 func shouldAddExample(ut *design.UserTypeDefinition) bool {
 	if ut == nil {
 		return false
@@ -608,8 +790,40 @@ func main() {
 	app.PersistentFlags().StringVarP(&c.Host, "host", "H", "{{ .API.Host }}", "API hostname")
 	app.PersistentFlags().DurationVarP(&httpClient.Timeout, "timeout", "t", time.Duration(20) * time.Second, "Set the request timeout")
 	app.PersistentFlags().BoolVar(&c.Dump, "dump", false, "Dump HTTP request and response.")
-
-{{ if .HasSigners }}	// Register signer flags
+{{ if .API.Servers }}	var server string
+	var serverVars []string
+	app.PersistentFlags().StringVar(&server, "server", "", "Name of the server the requests are sent to ({{ range $i, $s := .API.Servers }}{{ if $i }}, {{ end }}{{ $s.Name }}{{ end }}), overrides --host and --scheme")
+	app.PersistentFlags().StringSliceVar(&serverVars, "server-var", nil, "Value of a server URL variable formatted as name=value")
+{{ else if .API.UsesTLS }}	var server string
+{{ end }}{{ if .API.UsesTLS }}	var certFile, keyFile, caFile string
+	app.PersistentFlags().StringVar(&certFile, "cert-file", "", "Path to the PEM encoded client certificate used for mutual TLS")
+	app.PersistentFlags().StringVar(&keyFile, "key-file", "", "Path to the PEM encoded client certificate key used for mutual TLS")
+	app.PersistentFlags().StringVar(&caFile, "ca-file", "", "Path to the PEM encoded certificates of the authorities trusted to sign the server certificate")
+{{ end }}{{ if or .API.Servers .API.UsesTLS }}	app.PersistentPreRunE = func(*cobra.Command, []string) error {
+{{ if .API.UsesTLS }}		cfg, err := {{ .Package }}.NewTLSConfig(server, certFile, keyFile, caFile)
+		if err != nil {
+			return err
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: cfg}
+{{ end }}{{ if .API.Servers }}		if server != "" {
+			vars := make(map[string]string)
+			for _, v := range serverVars {
+				elems := strings.SplitN(v, "=", 2)
+				if len(elems) != 2 {
+					return fmt.Errorf("invalid server variable %q, format must be name=value", v)
+				}
+				vars[elems[0]] = elems[1]
+			}
+			if err := c.UseServer(server, vars); err != nil {
+				return err
+			}
+{{ if .API.UsesProtocol "h2c" }}			if t := {{ .Package }}.H2CTransport(server); t != nil {
+				httpClient.Transport = t
+			}
+{{ end }}		}
+{{ end }}		return nil
+	}
+{{ end }}{{ if .HasSigners }}	// Register signer flags
 {{ if .HasBasicAuthSigners }} var user, pass string
 	app.PersistentFlags().StringVar(&user, "user", "", "Username used for authentication")
 	app.PersistentFlags().StringVar(&pass, "pass", "", "Password used for authentication")
@@ -619,6 +833,8 @@ func main() {
 {{ end }}{{ if .HasTokenSigners }} var token, typ string
 	app.PersistentFlags().StringVar(&token, "token", "", "Token used for authentication")
 	app.PersistentFlags().StringVar(&typ, "token-type", "Bearer", "Token type used for authentication")
+{{ end }}{{ if .HasSessionSigners }} var session string
+	app.PersistentFlags().StringVar(&session, "session", "", "Session ID used for authentication")
 {{ end }}
 	// Parse flags and setup signers
 	app.ParseFlags(os.Args)
@@ -655,7 +871,11 @@ func newHTTPClient() *http.Client {
 // new{{ goify $security.SchemeName true }}Signer returns the request signer used for authenticating
 // against the {{ $security.SchemeName }} security scheme.
 func new{{ goify $security.SchemeName true }}Signer({{ signerSignature $security }}) goaclient.Signer {
-{{ if eq .Type "basic" }}	return &goaclient.BasicSigner{
+{{ if eq .Context "SessionSecurity" }}	return &goaclient.SessionSigner{
+		CookieName: "{{ $security.CookieName }}",
+		SessionID:  session,
+	}
+{{ else if eq .Type "basic" }}	return &goaclient.BasicSigner{
 		Username: user,
 		Password: pass,
 	}
@@ -699,6 +919,18 @@ const downloadCommandType = `// DownloadCommand is the command line data structu
 
 `
 
+const replayCommandType = `// ReplayCommand is the command line data structure for the replay command.
+	ReplayCommand struct {
+		// Resource is the name of the resource whose captured requests are replayed, all
+		// the requests are replayed if empty.
+		Resource string
+		// Action is the name of the action whose captured requests are replayed, all the
+		// requests are replayed if empty.
+		Action string
+	}
+
+`
+
 const commandsTmplWS = `
 {{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title .Resource.Name)) true }}// Run establishes a websocket connection for the {{ $cmdName }} command.
 func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
@@ -710,7 +942,7 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ else }}{{ $pparams := defaultRouteParams .Action }}	path = fmt.Sprintf({{ printf "%q" (defaultRouteTemplate .Action)}}, {{ joinFieldNames $pparams }})
 {{ end }}	}
 	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Package .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
 	ws, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{/*
 	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }})
 	if err != nil {
@@ -773,6 +1005,56 @@ found:
 }
 `
 
+const replayCommandTmpl = `
+// Run replays the requests captured in the files with the given paths against the host.
+func (cmd *ReplayCommand) Run(c *{{ .Package }}.Client, args []string) error {
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	for _, p := range args {
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		exchanges, err := capture.ReadExchanges(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %s", p, err)
+		}
+		for _, e := range exchanges {
+			if cmd.Resource != "" && e.Resource != cmd.Resource || cmd.Action != "" && e.Action != cmd.Action {
+				continue
+			}
+			req, err := capture.ReplayRequest(scheme, c.Host, e)
+			if err != nil {
+				return err
+			}
+{{ if .Signers }}			switch e.Resource + "#" + e.Action {
+{{ range $signer, $endpoints := .Signers }}			case {{ range $i, $e := $endpoints }}{{ if $i }}, {{ end }}{{ printf "%q" $e }}{{ end }}:
+				if c.{{ $signer }}Signer != nil {
+					c.{{ $signer }}Signer.Sign(req)
+				}
+{{ end }}			}
+{{ end }}			resp, err := c.Client.Do(ctx, req)
+			if err != nil {
+				goa.LogError(ctx, "failed", "err", err)
+				return err
+			}
+			resp.Body.Close()
+			captured := 0
+			if e.Response != nil {
+				captured = e.Response.Status
+			}
+			goa.LogInfo(ctx, "replayed", "resource", e.Resource, "action", e.Action, "status", resp.StatusCode, "captured", captured)
+		}
+	}
+	return nil
+}
+`
+
 const registerTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title .Resource.Name)) true }}// RegisterFlags registers the command flags with the command line.
 func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Client) {
 {{ if .Action.Payload }}	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
@@ -780,11 +1062,11 @@ func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Cl
 {{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
-*/}}{{ if $pparam.DefaultValue }}{{ printf "%#v" $pparam.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
+*/}}{{ if $pparam.DefaultValue }}{{ flagDefault $pparam }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
 {{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
 */}}{{ if not $param.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $param.Type false }}
 {{ end }}	cc.Flags().{{ flagType $param }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
-*/}}{{ if $param.DefaultValue }}{{ printf "%#v" $param.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $param.Description }}` + "`" + `)
+*/}}{{ if $param.DefaultValue }}{{ flagDefault $param }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $param.Description }}` + "`" + `)
 {{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
 */}} cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $header.DefaultValue }}{{ printf "%q" $header.DefaultValue }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks $header.Description }}` + "`" + `)
@@ -809,7 +1091,7 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ end }}		}
 	}
 {{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
-	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
+	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Package .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
 	*/}}{{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
 	*/}}{{ $params := joinNames true .Action.QueryParams .Action.Headers }}{{ if $params }}, {{ format $params $specialTypeResult.Temps }}{{ end }}{{/*
@@ -859,6 +1141,18 @@ Payload example:
 	}
 	dlc.Flags().StringVar(&dl.OutFile, "out", "", "Output file")
 	app.AddCommand(dlc)
+{{ end }}{{ if .HasReplay }}
+	rp := new(ReplayCommand)
+	rpc := &cobra.Command{
+		Use:   "replay [FILE]...",
+		Short: "Replay the requests captured in the given files against the host",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rp.Run(c, args)
+		},
+	}
+	rpc.Flags().StringVar(&rp.Resource, "resource", "", "Only replay the requests made to the given resource")
+	rpc.Flags().StringVar(&rp.Action, "action", "", "Only replay the requests made to the given action")
+	app.AddCommand(rpc)
 {{ end }}}
 
 func intFlagVal(name string, parsed int) *int {
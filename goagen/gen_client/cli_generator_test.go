@@ -341,5 +341,20 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring("jwt1Signer := newJWT1Signer()"))
 			Ω(content).Should(ContainSubstring("c.SetJWT1Signer(jwt1Signer)"))
 		})
+
+		Context("and captured requests", func() {
+			BeforeEach(func() {
+				design.Design.Capture = &design.CaptureDefinition{MaxBodySize: 1024}
+			})
+
+			It("generates the replay command", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring(`Use:   "replay [FILE]...",`))
+				Ω(content).Should(ContainSubstring(`case "foo#show":`))
+				Ω(content).Should(ContainSubstring("c.JWT1Signer.Sign(req)"))
+			})
+		})
 	})
 })
@@ -9,8 +9,21 @@ The generated code includes a client package with:
     * Helper functions to build the corresponding request paths
     * Structs for the action payloads and dependent types
     * Structs for the action media types and corresponding decoder functions
+    * One service interface per resource implemented by the client and by a generated mock that
+      records calls and returns canned responses
+    * A UseShadow method that mirrors a percentage of the requests to a secondary service and
+      reports the differences between the responses, e.g. to dark launch a new service version
+    * A UseServer method that selects one of the servers defined in the design
+    * A NewTLSConfig function that builds a TLS configuration satisfying the design TLS
+      requirements, including the client certificate used for mutual TLS
+    * A H2CTransport function that returns the HTTP/2 transport used to connect to h2c servers
 
 The generated code also includes a CLI tool with commands for each action and sub-commands for
-each resource.
+each resource. The --server and --server-var flags of the tool select the server the requests are
+sent to, the --cert-file, --key-file and --ca-file flags configure TLS.
+
+The tool of APIs that capture requests also includes a "replay" command that re-issues the
+requests recorded by the capture middleware against the host given with --host, signing them with
+the signers configured on the command line.
 */
 package genclient
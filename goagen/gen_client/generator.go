@@ -23,6 +23,7 @@ const typesFileName = "datatypes"
 type Generator struct {
 	API            *design.APIDefinition // The API definition
 	OutDir         string                // Path to output directory
+	OutPkgPath     string                // Import path of output directory, computed from GOPATH or go.mod if empty
 	Target         string                // Name of generated package
 	ToolDirName    string                // Name of tool directory where CLI main is generated once
 	Tool           string                // Name of CLI tool
@@ -36,14 +37,15 @@ type Generator struct {
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, target, toolDir, tool, ver string
-		notool                             bool
+		outDir, outPkgPath, target, toolDir, tool, ver string
+		notool                                         bool
 	)
 	dtool := defaultToolName(design.Design)
 
 	set := flag.NewFlagSet("client", flag.PanicOnError)
 	set.String("design", "", "")
 	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&outPkgPath, "out-pkg-path", "", "")
 	set.StringVar(&target, "pkg", "client", "")
 	set.StringVar(&toolDir, "tooldir", "tool", "")
 	set.StringVar(&tool, "tool", dtool, "")
@@ -58,7 +60,7 @@ func Generate() (files []string, err error) {
 
 	// Now proceed
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, API: design.Design}
+	g := &Generator{OutDir: outDir, OutPkgPath: outPkgPath, Target: target, ToolDirName: toolDir, Tool: tool, NoTool: notool, API: design.Design}
 
 	return g.Generate()
 }
@@ -138,18 +140,25 @@ func (g *Generator) Generate() (_ []string, err error) {
 			"signerType":         signerType,
 			"tempvar":            codegen.Tempvar,
 			"title":              strings.Title,
+			"tlsVersion":         codegen.TLSVersion,
 			"toString":           toString,
 			"typeName":           typeName,
+			"wireMarshalers":     codegen.WireMarshalers,
 			"format":             format,
 			"handleSpecialTypes": handleSpecialTypes,
 		}
-		clientPkg, err = codegen.PackagePath(pkgDir)
-		if err != nil {
-			return
-		}
-		cliPkg, err = codegen.PackagePath(cliDir)
-		if err != nil {
-			return
+		if g.OutPkgPath != "" {
+			clientPkg = path.Join(g.OutPkgPath, g.Target)
+			cliPkg = path.Join(g.OutPkgPath, g.ToolDirName, "cli")
+		} else {
+			clientPkg, err = codegen.PackagePath(pkgDir)
+			if err != nil {
+				return
+			}
+			cliPkg, err = codegen.PackagePath(cliDir)
+			if err != nil {
+				return
+			}
 		}
 		arrayToStringTmpl = template.Must(template.New("client").Funcs(funcs).Parse(arrayToStringT))
 	}
@@ -230,10 +239,18 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 
 	// Setup codegen
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("crypto/tls"),
+		codegen.SimpleImport("crypto/x509"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("net"),
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
+		codegen.SimpleImport("golang.org/x/net/http2"),
 	}
 	for _, packagePath := range packagePaths {
 		imports = append(imports, codegen.SimpleImport(packagePath))
@@ -277,6 +294,7 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDefinition, funcs template.FuncMap) error {
 	payloadTmpl := template.Must(template.New("payload").Funcs(funcs).Parse(payloadTmpl))
 	pathTmpl := template.Must(template.New("pathTemplate").Funcs(funcs).Parse(pathTmpl))
+	serviceTmpl := template.Must(template.New("service").Funcs(funcs).Parse(serviceTmpl))
 
 	resFilename := codegen.SnakeCase(res.Name)
 	if resFilename == typesFileName {
@@ -300,11 +318,14 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("path"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("sync"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	if err := file.WriteHeader("", g.Target, imports); err != nil {
 		return err
 	}
@@ -314,6 +335,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		return g.generateFileServer(file, fs, funcs)
 	})
 
+	var actions []*actionData
 	err = res.IterateActions(func(action *design.ActionDefinition) error {
 		if action.Payload != nil {
 			found := false
@@ -342,12 +364,29 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 				return err
 			}
 		}
-		return g.generateActionClient(action, file, funcs)
+		data, err := g.generateActionClient(action, file, funcs)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, data)
+		return nil
 	})
 	if err != nil {
 		return err
 	}
 
+	// Generate the service interface and mock used to substitute the client in tests
+	data := struct {
+		ResourceName string
+		Actions      []*actionData
+	}{
+		ResourceName: res.Name,
+		Actions:      actions,
+	}
+	if err := serviceTmpl.Execute(file, data); err != nil {
+		return err
+	}
+
 	return file.FormatCode()
 }
 
@@ -393,7 +432,7 @@ func (g *Generator) generateFileServer(file *codegen.SourceFile, fs *design.File
 	return fsTmpl.Execute(file, data)
 }
 
-func (g *Generator) generateActionClient(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+func (g *Generator) generateActionClient(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) (*actionData, error) {
 	var (
 		params        []string
 		names         []string
@@ -403,6 +442,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		clientsTmpl   = template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
 		requestsTmpl  = template.Must(template.New("requests").Funcs(funcs).Parse(requestsTmpl))
 		clientsWSTmpl = template.Must(template.New("clientsws").Funcs(funcs).Parse(clientsWSTmpl))
+		trailersTmpl  = template.Must(template.New("trailers").Funcs(funcs).Parse(trailersTmpl))
 	)
 	if action.Payload != nil {
 		params = append(params, "payload "+codegen.GoTypeRef(action.Payload, action.Payload.AllRequired(), 1, false))
@@ -423,19 +463,31 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 				Attribute: q,
 			}
 			if q.Type.IsPrimitive() {
-				param.MustToString = q.Type.Kind() != design.StringKind
+				param.MustToString = q.Type.Kind() != design.StringKind || codegen.IsScalar(q.Type)
 				if att.IsRequired(n) {
 					param.ValueName = varName
 					pdata = append(pdata, param)
 				} else {
 					param.ValueName = "*" + varName
+					if isReference(q.Type) {
+						param.ValueName = varName
+					}
 					param.CheckNil = true
 					optData = append(optData, param)
 				}
+			} else if q.Type.IsObject() {
+				param.IsObject = true
+				param.ValueName = varName
+				if att.IsRequired(n) {
+					pdata = append(pdata, param)
+				} else {
+					optData = append(optData, param)
+				}
 			} else {
 				if q.Type.IsArray() {
 					param.IsArray = true
 					param.ElemAttribute = q.Type.ToArray().ElemType
+					param.Delimiter = q.ArrayStyle().Delimiter()
 				}
 				param.MustToString = true
 				param.ValueName = varName
@@ -468,19 +520,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
 	}
-	data := struct {
-		Name            string
-		ResourceName    string
-		Description     string
-		Routes          []*design.RouteDefinition
-		HasPayload      bool
-		Params          string
-		ParamNames      string
-		CanonicalScheme string
-		Signer          string
-		QueryParams     []*paramData
-		Headers         []*paramData
-	}{
+	data := &actionData{
 		Name:            action.Name,
 		ResourceName:    action.Parent.Name,
 		Description:     action.Description,
@@ -492,14 +532,29 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer:          signer,
 		QueryParams:     queryParams,
 		Headers:         headers,
+		WebSocket:       action.WebSocket(),
 	}
-	if action.WebSocket() {
-		return clientsWSTmpl.Execute(file, data)
+	if data.WebSocket {
+		return data, clientsWSTmpl.Execute(file, data)
 	}
 	if err := clientsTmpl.Execute(file, data); err != nil {
-		return err
+		return nil, err
+	}
+	if err := requestsTmpl.Execute(file, data); err != nil {
+		return nil, err
 	}
-	return requestsTmpl.Execute(file, data)
+	err := action.IterateResponses(func(resp *design.ResponseDefinition) error {
+		trailers := genapp.TrailerFields(resp)
+		if len(trailers) == 0 {
+			return nil
+		}
+		return trailersTmpl.Execute(file, map[string]interface{}{
+			"Action":   data,
+			"Response": resp,
+			"Trailers": trailers,
+		})
+	})
+	return data, err
 }
 
 // fileServerMethod returns the name of the client method for downloading assets served by the given
@@ -552,6 +607,7 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	mtWr.WriteHeader(title, g.Target, imports)
 	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
 		if (mt.Type.IsObject() || mt.Type.IsArray()) && !mt.IsError() {
@@ -591,9 +647,25 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("unicode/utf8"),
+		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
+	imports = append(imports, codegen.ScalarImports(g.API)...)
 	utWr.WriteHeader(title, g.Target, imports)
+	for _, s := range g.API.Scalars {
+		if !s.IsEnum() {
+			continue
+		}
+		if err = utWr.ExecuteEnum(s); err != nil {
+			return err
+		}
+	}
+	for _, f := range g.API.Flags {
+		if err = utWr.ExecuteFlags(f); err != nil {
+			return err
+		}
+	}
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -681,21 +753,33 @@ func decodeGoTypeName(t design.DataType, required []string, tabs int, private bo
 
 // cmdFieldType computes the Go type name used to store command flags of the given design type.
 func cmdFieldType(t design.DataType, point bool) string {
+	if t.IsObject() {
+		return codegen.GoTypeRef(t, nil, 0, false)
+	}
 	var pointer, suffix string
-	if point && !t.IsArray() {
+	if point && !t.IsArray() && !isReference(t) {
 		pointer = "*"
 	}
 	suffix = codegen.GoNativeType(t)
+	if codegen.IsScalar(t) || t.IsArray() && codegen.IsScalar(t.ToArray().ElemType.Type) {
+		suffix = codegen.GoTypeName(t, nil, 0, false)
+	}
 	return pointer + suffix
 }
 
+// isReference returns true if t is a custom scalar whose Go type is a pointer.
+func isReference(t design.DataType) bool {
+	s, ok := t.(*design.ScalarDefinition)
+	return ok && s.IsReference()
+}
+
 // cmdFieldTypeString computes the Go type name used to store command flags of the given design type. Complex types are String
 func cmdFieldTypeString(t design.DataType, point bool) string {
 	var pointer, suffix string
 	if point && !t.IsArray() {
 		pointer = "*"
 	}
-	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind {
+	if t.Kind() == design.UUIDKind || t.Kind() == design.DateTimeKind || t.Kind() == design.AnyKind || t.Kind() == design.NumberKind || t.Kind() == design.BooleanKind || t.IsObject() {
 		suffix = "string"
 	} else if isArrayOfType(t, design.UUIDKind, design.DateTimeKind, design.AnyKind, design.NumberKind, design.BooleanKind) {
 		suffix = "[]string"
@@ -725,6 +809,9 @@ var arrayToStringTmpl *template.Template
 // toString generates Go code that converts the given simple type attribute into a string.
 func toString(name, target string, att *design.AttributeDefinition) string {
 	switch actual := att.Type.(type) {
+	case *design.ScalarDefinition:
+		wire := fmt.Sprintf("%s(%s)", actual.FormatFunc, name)
+		return toString(wire, target, &design.AttributeDefinition{Type: actual.Primitive})
 	case design.Primitive:
 		switch actual.Kind() {
 		case design.IntegerKind:
@@ -742,6 +829,8 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 		default:
 			panic("unknown primitive type")
 		}
+	case *design.FlagsDefinition:
+		return toString(name, target, &design.AttributeDefinition{Type: actual.Array})
 	case *design.Array:
 		data := map[string]interface{}{
 			"Name":     name,
@@ -777,6 +866,8 @@ func signerType(scheme *design.SecuritySchemeDefinition) string {
 		return "goaclient.APIKeySigner"
 	case design.BasicAuthSecurityKind:
 		return "goaclient.BasicSigner"
+	case design.SessionSecurityKind:
+		return "goaclient.SessionSigner"
 	}
 	return ""
 }
@@ -823,9 +914,28 @@ type paramData struct {
 	ElemAttribute *design.AttributeDefinition
 	MustToString  bool
 	IsArray       bool
+	IsObject      bool
+	Delimiter     string
 	CheckNil      bool
 }
 
+// actionData is the data structure holding the information needed to generate the client
+// methods of an action.
+type actionData struct {
+	Name            string
+	ResourceName    string
+	Description     string
+	Routes          []*design.RouteDefinition
+	HasPayload      bool
+	Params          string
+	ParamNames      string
+	CanonicalScheme string
+	Signer          string
+	QueryParams     []*paramData
+	Headers         []*paramData
+	WebSocket       bool
+}
+
 type byParamName []*paramData
 
 func (b byParamName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -842,7 +952,9 @@ const (
 
 	payloadTmpl = `// {{ gotypename .Payload nil 0 false }} is the {{ .Parent.Name }} {{ .Name }} action payload.
 type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
-`
+{{ $marshalers := wireMarshalers .Payload.AttributeDefinition "payload" (gotypename .Payload nil 0 false) }}{{ if $marshalers }}
+{{ $marshalers }}
+{{ end }}`
 
 	typeDecodeTmpl = `{{ $typeName := typeName . }}{{ $funcName := printf "Decode%s" $typeName }}// {{ $funcName }} decodes the {{ $typeName }} instance encoded in resp body.
 func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .AllRequired 0 false }}, error) {
@@ -850,6 +962,30 @@ func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .All
 	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
 	return {{ if .IsObject }}&{{ end }}decoded, err
 }
+`
+
+	trailersTmpl = `{{ $type := printf "%s%s%sTrailers" (goify .Action.Name true) (goify .Action.ResourceName true) (goify .Response.Name true) }}
+// {{ $type }} holds the trailers of the {{ .Response.Name }} response of the {{ .Action.ResourceName }} {{ .Action.Name }} action.
+type {{ $type }} struct {
+{{ range .Trailers }}{{ if .Description }}	{{ multiComment .Description }}
+{{ end }}	{{ .Field }} *{{ .Type }}
+{{ end }}}
+
+// Decode{{ $type }} decodes the trailers of the {{ .Response.Name }} response of the
+// {{ .Action.ResourceName }} {{ .Action.Name }} action. The trailers are only available once the
+// response body has been read entirely.
+func (c *Client) Decode{{ $type }}(resp *http.Response) (*{{ $type }}, error) {
+	var t {{ $type }}
+{{ range .Trailers }}	if raw := resp.Trailer.Get("{{ .Name }}"); raw != "" {
+{{ if .Parse }}		v, err := {{ .Parse }}
+		if err != nil {
+			return nil, fmt.Errorf("invalid {{ .Name }} trailer: %s", err)
+		}
+{{ else }}		v := raw
+{{ end }}		t.{{ .Field }} = &v
+	}
+{{ end }}	return &t, nil
+}
 `
 
 	pathTmpl = `{{ $funcName := printf "%sPath%s" (goify (printf "%s%s" .Route.Parent.Name (title .Route.Parent.Parent.Name)) true) ((or (and .Index (add .Index 1)) "") | printf "%v") }}{{/*
@@ -869,6 +1005,69 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params}}
 	}
 	return c.Client.Do(ctx, req)
 }
+`
+
+	serviceTmpl = `{{ $svc := printf "%sService" (goify .ResourceName true) }}{{ $mock := printf "%sMock" (goify .ResourceName true) }}{{/*
+*/}}// {{ $svc }} is the interface implemented by the client to make requests to the {{ .ResourceName }}
+// resource endpoints. Code depending on {{ $svc }} rather than on Client can be unit tested using
+// {{ $mock }}.
+type {{ $svc }} interface {
+{{ range .Actions }}{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{/*
+*/}}	{{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload (not .WebSocket) }}, contentType string{{ end }}) ({{ if .WebSocket }}*websocket.Conn{{ else }}*http.Response{{ end }}, error)
+{{ end }}}
+
+// {{ $mock }} implements {{ $svc }}, it records the calls made to it and returns the canned
+// responses and errors set in its fields. The recorded calls are retrieved with AllCalls and CallsTo.
+type {{ $mock }} struct {
+{{ range .Actions }}{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}
+	// {{ $funcName }}Response is the response returned by {{ $funcName }}.
+	{{ $funcName }}Response {{ if .WebSocket }}*websocket.Conn{{ else }}*http.Response{{ end }}
+	// {{ $funcName }}Err is the error returned by {{ $funcName }}.
+	{{ $funcName }}Err error
+{{ end }}
+	mu    sync.Mutex
+	calls []*MockCall
+}
+
+var (
+	_ {{ $svc }} = (*Client)(nil)
+	_ {{ $svc }} = (*{{ $mock }})(nil)
+)
+{{ range .Actions }}{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}
+// {{ $funcName }} records the call and returns {{ $funcName }}Response and {{ $funcName }}Err.
+func (m *{{ $mock }}) {{ $funcName }}(ctx context.Context, path string{{ if .Params }}, {{ .Params }}{{ end }}{{ if and .HasPayload (not .WebSocket) }}, contentType string{{ end }}) ({{ if .WebSocket }}*websocket.Conn{{ else }}*http.Response{{ end }}, error) {
+	m.record("{{ $funcName }}", path{{ if .ParamNames }}, {{ .ParamNames }}{{ end }}{{ if and .HasPayload (not .WebSocket) }}, contentType{{ end }})
+	return m.{{ $funcName }}Response, m.{{ $funcName }}Err
+}
+{{ end }}
+// AllCalls returns the calls made to the mock in order.
+func (m *{{ $mock }}) AllCalls() []*MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	calls := make([]*MockCall, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// CallsTo returns the calls made to the mock method with the given name in order.
+func (m *{{ $mock }}) CallsTo(method string) []*MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []*MockCall
+	for _, c := range m.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// record appends a call to the list of recorded calls.
+func (m *{{ $mock }}) record(method, path string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, &MockCall{Method: method, Path: path, Args: args})
+}
 `
 
 	clientsWSTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .ResourceName)) true }}{{ $desc := .Description }}{{/*
@@ -883,11 +1082,18 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 {{ range .QueryParams }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
 	{{ end }}{{/*
 
+// OBJECT
+*/}}{{ if .IsObject }}	goaclient.EncodeQueryObject(values, "{{ .Name }}", {{ .VarName }})
+{{/*
+
 // ARRAY
-*/}}{{ if .IsArray }}		for _, p := range {{ .VarName }} {
+*/}}{{ else if .IsArray }}{{ if .Delimiter }}		var {{ .VarName }}Values []string
+{{ end }}		for _, p := range {{ .VarName }} {
 {{ if .MustToString }}{{ $tmp := tempvar }}			{{ toString "p" $tmp .ElemAttribute }}
-			values.Add("{{ .Name }}", {{ $tmp }})
+			{{ if .Delimiter }}{{ .VarName }}Values = append({{ .VarName }}Values, {{ $tmp }}){{ else }}values.Add("{{ .Name }}", {{ $tmp }}){{ end }}
 {{ else }}			values.Add("{{ .Name }}", {{ .ValueName }})
+{{ end }}		}
+{{ if .Delimiter }}		values.Set("{{ .Name }}", strings.Join({{ .VarName }}Values, {{ printf "%q" .Delimiter }}))
 {{ end }}{{/*
 
 // NON STRING
@@ -957,13 +1163,21 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 {{ if .QueryParams }}	values := u.Query()
 {{ range .QueryParams }}{{/*
 
+// OBJECT
+*/}}{{ if .IsObject }}	goaclient.EncodeQueryObject(values, "{{ .Name }}", {{ .VarName }})
+{{/*
+
 // ARRAY
-*/}}{{ if .IsArray }}		for _, p := range {{ .VarName }} {
+*/}}{{ else if .IsArray }}{{ if .Delimiter }}	var {{ .VarName }}Values []string
+{{ end }}		for _, p := range {{ .VarName }} {
 {{ if .MustToString }}{{ $tmp := tempvar }}			{{ toString "p" $tmp .ElemAttribute }}
-			values.Add("{{ .Name }}", {{ $tmp }})
+			{{ if .Delimiter }}{{ .VarName }}Values = append({{ .VarName }}Values, {{ $tmp }}){{ else }}values.Add("{{ .Name }}", {{ $tmp }}){{ end }}
 {{ else }}			values.Add("{{ .Name }}", {{ .ValueName }})
 {{ end }}	 }
-{{/*
+{{ if .Delimiter }}	if len({{ .VarName }}Values) > 0 {
+		values.Set("{{ .Name }}", strings.Join({{ .VarName }}Values, {{ printf "%q" .Delimiter }}))
+	}
+{{ end }}{{/*
 
 // NON STRING
 */}}{{ else if .MustToString }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
@@ -1012,7 +1226,8 @@ func New(c goaclient.Doer) *Client {
 		Encoder: goa.NewHTTPEncoder(),
 		Decoder: goa.NewHTTPDecoder(),
 	}
-
+{{ if .API.RequestID }}	client.RequestIDHeader = {{ printf "%q" .API.RequestID.Header }}
+{{ end }}
 {{ if .Encoders }}	// Setup encoders and decoders
 {{ range .Encoders }}{{/*
 */}}	client.Encoder.Register({{ .PackageName }}.{{ .Function }}, "{{ joinStrings .MIMETypes "\", \"" }}")
@@ -1029,6 +1244,135 @@ func New(c goaclient.Doer) *Client {
 {{ end }}	return client
 }
 
+// UseShadow mirrors percent percent of the GET and HEAD requests sent by the client to the
+// service at baseURL, e.g. a new version of the service being deployed. The mirrored requests are
+// sent asynchronously and their responses compared to the responses of the primary service, the
+// differences are reported to report or logged if report is nil. The client always returns the
+// responses of the primary service. See goaclient.NewShadowDoer for finer control.
+func (c *Client) UseShadow(baseURL string, percent float64, report goaclient.ShadowReporter) error {
+	doer, err := goaclient.NewShadowDoer(c.Client.Doer, &goaclient.ShadowOptions{
+		BaseURL: baseURL,
+		Percent: percent,
+		Report:  report,
+	})
+	if err != nil {
+		return err
+	}
+	c.Client.Doer = doer
+	return nil
+}
+
+{{ if .API.Servers }}// serverTemplates lists the URL templates of the servers of the API indexed by name.
+var serverTemplates = map[string]string{
+{{ range .API.Servers }}	{{ printf "%q" .Name }}: {{ printf "%q" .URL }},
+{{ end }}}
+
+// serverDefaults lists the default values of the server URL variables indexed by server name.
+var serverDefaults = map[string]map[string]string{
+{{ range .API.Servers }}	{{ printf "%q" .Name }}: { {{ range $n, $v := .Defaults }}{{ printf "%q" $n }}: {{ printf "%q" $v }}, {{ end }}},
+{{ end }}}
+
+// ServerURL returns the URL of the server with the given name. The URL template variables are
+// replaced with the given values or with their default values.
+func ServerURL(name string, vars map[string]string) (*url.URL, error) {
+	tmpl, ok := serverTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown server %q", name)
+	}
+	for n, v := range serverDefaults[name] {
+		if val, ok := vars[n]; ok {
+			v = val
+		}
+		tmpl = strings.Replace(tmpl, "{"+n+"}", v, -1)
+	}
+	return url.Parse(tmpl)
+}
+
+// UseServer configures the client to send the requests to the server with the given name, see
+// ServerURL. The request paths include the API base path so that the path of the server URL is
+// ignored.
+func (c *Client) UseServer(name string, vars map[string]string) error {
+	u, err := ServerURL(name, vars)
+	if err != nil {
+		return err
+	}
+	c.Scheme = u.Scheme
+	c.Host = u.Host
+	return nil
+}
+
+{{ if .API.UsesProtocol "h2c" }}// h2cServers lists the names of the servers that speak HTTP/2 over cleartext TCP (h2c).
+var h2cServers = map[string]bool{
+{{ range .API.Servers }}{{ if eq .Protocol "h2c" }}	{{ printf "%q" .Name }}: true,
+{{ end }}{{ end }}}
+
+// H2CTransport returns a HTTP/2 transport that connects over cleartext TCP if the server with the
+// given name speaks h2c, nil otherwise.
+func H2CTransport(server string) http.RoundTripper {
+	if !h2cServers[server] {
+		return nil
+	}
+	return &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}
+}
+
+{{ end }}{{ end }}{{ if .API.UsesTLS }}// tlsRequirement describes the TLS requirements of a server.
+type tlsRequirement struct {
+	minVersion uint16
+	clientCert bool
+}
+
+// tlsRequirements lists the TLS requirements of the API servers indexed by server name, the
+// requirements of the API are indexed by the empty string.
+var tlsRequirements = map[string]tlsRequirement{
+{{ with .API.TLS }}	"": {minVersion: {{ tlsVersion .Version }}, clientCert: {{ .ClientCertRequired }}},
+{{ end }}{{ range $s := .API.Servers }}{{ if $s.TLS }}	{{ printf "%q" $s.Name }}: {minVersion: {{ tlsVersion $s.TLS.Version }}, clientCert: {{ $s.TLS.ClientCertRequired }}},
+{{ end }}{{ end }}}
+
+// NewTLSConfig returns a TLS configuration that satisfies the requirements of the server with the
+// given name or of the API if server is empty. certFile and keyFile are the paths to the PEM files
+// containing the client certificate and key used for mutual TLS. caFile is the optional path to the
+// PEM file containing the certificates of the authorities trusted to sign the server certificate.
+func NewTLSConfig(server, certFile, keyFile, caFile string) (*tls.Config, error) {
+	req := tlsRequirements[server]
+	cfg := &tls.Config{MinVersion: req.minVersion}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	} else if req.clientCert {
+		return nil, fmt.Errorf("server requires a client certificate")
+	}
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+{{ end }}// MockCall records a call made to a service mock.
+type MockCall struct {
+	// Method is the name of the mocked client method.
+	Method string
+	// Path is the request path given to the method.
+	Path string
+	// Args lists the other arguments given to the method in order.
+	Args []interface{}
+}
+
 {{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}{{/*
 */}}{{ $name := printf "%sSigner" (goify $security.SchemeName true) }}{{/*
 */}}// Set{{ $name }} sets the request signer for the {{ $security.SchemeName }} security scheme.
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_client"
 	"github.com/goadesign/goa/version"
@@ -92,6 +93,57 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with a comma separated array querystring param", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			o := design.Object{
+				"ids": &design.AttributeDefinition{
+					Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+					Metadata: dslengine.MetadataDefinition{design.ArrayStyleMetadata: {string(design.StyleComma)}},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"list": {
+								Name: "list",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+								QueryParams: &design.AttributeDefinition{Type: o},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			listAct := fooRes.Actions["list"]
+			listAct.Parent = fooRes
+			listAct.Routes[0].Parent = listAct
+		})
+
+		It("joins the values with the delimiter", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`	var idsValues []string
+	for _, p := range ids {
+		tmp2 := strconv.Itoa(p)
+		idsValues = append(idsValues, tmp2)
+	}
+	if len(idsValues) > 0 {
+		values.Set("ids", strings.Join(idsValues, ","))
+	}
+`))
+		})
+	})
+
 	Context("with an action with multiple routes", func() {
 		BeforeEach(func() {
 			design.Design = &design.APIDefinition{
@@ -135,6 +187,19 @@ var _ = Describe("Generate", func() {
 			Ω(strings.Count(string(content), "func ShowFooPath2(")).Should(Equal(1))
 		})
 
+		It("generates a service interface and a mock", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("type FooService interface {"))
+			Ω(content).Should(ContainSubstring("ShowFoo(ctx context.Context, path string) (*http.Response, error)"))
+			Ω(content).Should(ContainSubstring("type FooMock struct {"))
+			Ω(content).Should(ContainSubstring("func (m *FooMock) ShowFoo(ctx context.Context, path string) (*http.Response, error) {"))
+			Ω(content).Should(ContainSubstring("_ FooService = (*FooMock)(nil)"))
+			Ω(content).Should(ContainSubstring("func (m *FooMock) AllCalls() []*MockCall {"))
+			Ω(content).ShouldNot(ContainSubstring("Calls []*MockCall"))
+		})
+
 		Context("with a file server", func() {
 			BeforeEach(func() {
 				res := design.Design.Resources["foo"]
@@ -221,5 +286,91 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(content).Should(ContainSubstring("c.JWT1Signer.Sign(req)"))
 		})
+
+		It("generates the UseShadow method", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func (c *Client) UseShadow(baseURL string, percent float64, report goaclient.ShadowReporter) error {"))
+			Ω(content).Should(ContainSubstring("goaclient.NewShadowDoer(c.Client.Doer, &goaclient.ShadowOptions{"))
+		})
+	})
+
+	Context("with servers and TLS requirements", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				TLS:  &design.TLSDefinition{ClientCertRequired: true},
+				Servers: []*design.ServerDefinition{
+					{
+						Name: "production",
+						TLS:  &design.TLSDefinition{MinVersion: "1.3"},
+						URL:  "https://{region}.api.example.com",
+						Variables: &design.AttributeDefinition{
+							Type: design.Object{
+								"region": &design.AttributeDefinition{Type: design.String, DefaultValue: "us-east-1"},
+							},
+						},
+					},
+					{
+						Name:     "local",
+						URL:      "http://localhost:8080",
+						Protocol: design.ProtocolH2C,
+					},
+				},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:   "show",
+								Routes: []*design.RouteDefinition{{Verb: "GET", Path: ""}},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("generates the server selection code", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`"production": "https://{region}.api.example.com",`))
+			Ω(content).Should(ContainSubstring(`"production": {"region": "us-east-1"},`))
+			Ω(content).Should(ContainSubstring("func (c *Client) UseServer(name string, vars map[string]string) error {"))
+			cli, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli).Should(ContainSubstring(`"server-var"`))
+			Ω(cli).Should(ContainSubstring("c.UseServer(server, vars)"))
+		})
+
+		It("generates the TLS configuration code", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`"":           {minVersion: tls.VersionTLS12, clientCert: true},`))
+			Ω(content).Should(ContainSubstring(`"production": {minVersion: tls.VersionTLS13, clientCert: false},`))
+			Ω(content).Should(ContainSubstring("func NewTLSConfig(server, certFile, keyFile, caFile string) (*tls.Config, error) {"))
+			cli, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli).Should(ContainSubstring(`"cert-file"`))
+			Ω(cli).Should(ContainSubstring("client.NewTLSConfig(server, certFile, keyFile, caFile)"))
+		})
+
+		It("generates the h2c transport code", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`"local": true,`))
+			Ω(content).Should(ContainSubstring("func H2CTransport(server string) http.RoundTripper {"))
+			cli, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cli).Should(ContainSubstring("client.H2CTransport(server)"))
+		})
 	})
 })
@@ -0,0 +1,15 @@
+/*
+Package genconsole provides a goa generator for an interactive API console.
+The generator produces a single "index.html" file in the "console" directory of the output
+directory. The page embeds its script and style, loads the OpenAPI document produced by the
+swagger generator and lets developers call the API actions of the running server, including
+configuring the credentials of the API security schemes.
+
+The console is meant to be served by the API itself, for example:
+
+	Files("/console/*filepath", "console/")
+
+The URL of the OpenAPI document defaults to the request path of the file server that serves the
+"swagger.json" file if the design defines one, "/swagger.json" otherwise.
+*/
+package genconsole
@@ -0,0 +1,13 @@
+package genconsole_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenConsole(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenConsole Suite")
+}
@@ -0,0 +1,164 @@
+package genconsole
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the API console generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Spec     string                // URL of the OpenAPI document loaded by the console
+	genfiles []string              // Generated files
+}
+
+// Scheme describes a security scheme to the console. The OpenAPI document does not retain the
+// kind of the JWT and session schemes so the console relies on these descriptions to send the
+// credentials.
+type Scheme struct {
+	Name        string `json:"name"`                  // Scheme name as used in the OpenAPI document
+	Kind        string `json:"kind"`                  // One of "basic", "apiKey", "jwt", "oauth2" or "session"
+	Description string `json:"description,omitempty"` // Scheme description
+	In          string `json:"in,omitempty"`          // Location of the API key, "header" or "query"
+	Param       string `json:"param,omitempty"`       // Name of the API key header or query string parameter
+	Cookie      string `json:"cookie,omitempty"`      // Name of the session cookie
+}
+
+// defaultSpec is the URL of the OpenAPI document used when the design does not serve it.
+const defaultSpec = "/swagger.json"
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, spec, ver string
+	)
+
+	set := flag.NewFlagSet("console", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&spec, "spec", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Spec: spec, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the console.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Spec == "" {
+		g.Spec = specPath(g.API)
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "console")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	config, err := json.Marshal(map[string]interface{}{
+		"spec":    g.Spec,
+		"schemes": schemes(g.API),
+	})
+	if err != nil {
+		return
+	}
+	title := g.API.Title
+	if title == "" {
+		title = g.API.Name
+	}
+	indexFile := filepath.Join(g.OutDir, "index.html")
+	file, err := codegen.SourceFileFor(indexFile)
+	if err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, indexFile)
+	funcs := template.FuncMap{"html": template.HTMLEscapeString}
+	data := map[string]interface{}{"Title": title, "Config": string(config)}
+	if err = file.ExecuteTemplate("console", consoleT, funcs, data); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// specPath returns the request path of the file server that serves the OpenAPI JSON document
+// produced by the swagger generator if the design defines one, "/swagger.json" otherwise.
+func specPath(api *design.APIDefinition) string {
+	spec := ""
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateFileServers(func(fs *design.FileServerDefinition) error {
+			if spec == "" && !fs.IsDir() && path.Base(filepath.ToSlash(fs.FilePath)) == "swagger.json" {
+				spec = fs.RequestPath
+			}
+			return nil
+		})
+	})
+	if spec == "" {
+		spec = defaultSpec
+	}
+	return spec
+}
+
+// schemes returns the descriptions of the API security schemes.
+func schemes(api *design.APIDefinition) []*Scheme {
+	res := make([]*Scheme, 0, len(api.SecuritySchemes))
+	for _, s := range api.SecuritySchemes {
+		sc := &Scheme{Name: s.SchemeName, Description: strings.TrimSpace(s.Description)}
+		switch s.Kind {
+		case design.BasicAuthSecurityKind:
+			sc.Kind = "basic"
+		case design.APIKeySecurityKind:
+			sc.Kind, sc.In, sc.Param = "apiKey", s.In, s.Name
+		case design.JWTSecurityKind:
+			sc.Kind, sc.In, sc.Param = "jwt", s.In, s.Name
+			if sc.Param == "" {
+				sc.In, sc.Param = "header", "Authorization"
+			}
+		case design.OAuth2SecurityKind:
+			sc.Kind = "oauth2"
+		case design.SessionSecurityKind:
+			sc.Kind, sc.Cookie = "session", s.CookieName
+		default:
+			continue
+		}
+		res = append(res, sc)
+	}
+	return res
+}
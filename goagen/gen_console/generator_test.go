@@ -0,0 +1,101 @@
+package genconsole_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_console"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_console/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+	var content string
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genconsole.Generate()
+		if genErr == nil {
+			b, err := ioutil.ReadFile(filepath.Join(outDir, "console", "index.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content = string(b)
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with security schemes", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:  "cellar",
+				Title: "The <cellar>",
+				SecuritySchemes: []*design.SecuritySchemeDefinition{
+					{Kind: design.JWTSecurityKind, SchemeName: "jwt"},
+					{Kind: design.APIKeySecurityKind, SchemeName: "key", In: "query", Name: "k"},
+					{Kind: design.SessionSecurityKind, SchemeName: "session", CookieName: "sid"},
+				},
+			}
+		})
+
+		It("generates the console", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			Ω(content).Should(ContainSubstring("<title>The &lt;cellar&gt; console</title>"))
+			Ω(content).Should(ContainSubstring(`var config = {"schemes":[` +
+				`{"name":"jwt","kind":"jwt","in":"header","param":"Authorization"},` +
+				`{"name":"key","kind":"apiKey","in":"query","param":"k"},` +
+				`{"name":"session","kind":"session","cookie":"sid"}],` +
+				`"spec":"/swagger.json"};`))
+		})
+	})
+
+	Context("with a file server serving the OpenAPI document", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{
+				Name: "public",
+				FileServers: []*design.FileServerDefinition{
+					{FilePath: "public/index.html", RequestPath: "/"},
+					{FilePath: "swagger/swagger.json", RequestPath: "/api/swagger.json"},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:      "cellar",
+				Resources: map[string]*design.ResourceDefinition{"public": res},
+			}
+		})
+
+		It("loads the document from the file server path", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(content).Should(ContainSubstring(`"spec":"/api/swagger.json"`))
+			Ω(content).Should(ContainSubstring("<title>cellar console</title>"))
+		})
+
+		Context("and an explicit URL", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--spec=https://example.com/openapi.json")
+			})
+
+			It("uses the explicit URL", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(content).Should(ContainSubstring(`"spec":"https://example.com/openapi.json"`))
+			})
+		})
+	})
+})
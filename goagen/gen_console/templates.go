@@ -0,0 +1,363 @@
+package genconsole
+
+const consoleT = `<!DOCTYPE html>
+<!-- Code generated by goagen, DO NOT EDIT. -->
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ html .Title }} console</title>
+<style>
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 14px/1.4 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; }
+  header { display: flex; align-items: center; gap: 12px; padding: 8px 16px; background: #263238; color: #fff; }
+  header h1 { font-size: 16px; margin: 0; flex: 1; }
+  header input { width: 320px; }
+  main { display: flex; height: calc(100vh - 45px); }
+  nav { width: 300px; overflow-y: auto; border-right: 1px solid #ddd; padding: 8px; }
+  nav h2 { font-size: 12px; text-transform: uppercase; color: #666; margin: 12px 4px 4px; }
+  nav a { display: block; padding: 4px; border-radius: 3px; color: inherit; text-decoration: none; }
+  nav a:hover { background: #eceff1; }
+  section { flex: 1; overflow-y: auto; padding: 16px 24px; }
+  input, textarea, select { font: inherit; padding: 4px 6px; border: 1px solid #bbb; border-radius: 3px; }
+  textarea { width: 100%; min-height: 160px; font-family: Menlo, Consolas, monospace; }
+  table { border-collapse: collapse; margin: 8px 0; }
+  td, th { text-align: left; padding: 4px 8px; vertical-align: top; }
+  button { font: inherit; padding: 6px 14px; border: 0; border-radius: 3px; background: #1976d2; color: #fff; cursor: pointer; }
+  pre { background: #f5f5f5; padding: 8px; overflow-x: auto; white-space: pre-wrap; }
+  .verb { display: inline-block; width: 56px; font: bold 11px Menlo, Consolas, monospace; }
+  .GET { color: #2e7d32; } .POST { color: #1565c0; } .PUT, .PATCH { color: #ef6c00; } .DELETE { color: #c62828; }
+  .muted { color: #777; }
+  .error { color: #c62828; }
+  dialog { width: 480px; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{ html .Title }}</h1>
+  <label>Server <input id="server" placeholder="http://localhost:8080"></label>
+  <button id="auth-open" type="button">Authorize</button>
+</header>
+<main>
+  <nav><input id="filter" placeholder="Filter" style="width: 100%"><div id="operations"></div></nav>
+  <section id="operation"><p class="muted">Loading the API definition...</p></section>
+</main>
+<dialog id="auth">
+  <form method="dialog">
+    <h3>Credentials</h3>
+    <div id="schemes"></div>
+    <p class="muted">Credentials are kept in the local storage of the browser.</p>
+    <button id="auth-save" value="save">Save</button>
+    <button value="cancel" style="background: #777">Cancel</button>
+  </form>
+</dialog>
+<script>
+(function () {
+  "use strict";
+
+  var config = {{ .Config }};
+  var storageKey = "goa-console:" + config.spec;
+  var state = { spec: null, operations: [], credentials: {} };
+
+  function $(id) { return document.getElementById(id); }
+
+  function el(tag, attrs, children) {
+    var e = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) {
+      if (k === "text") { e.textContent = attrs[k]; } else { e.setAttribute(k, attrs[k]); }
+    });
+    (children || []).forEach(function (c) { if (c) { e.appendChild(c); } });
+    return e;
+  }
+
+  function load() {
+    try {
+      var saved = JSON.parse(localStorage.getItem(storageKey) || "{}");
+      state.credentials = saved.credentials || {};
+      $("server").value = saved.server || "";
+    } catch (e) { state.credentials = {}; }
+  }
+
+  function save() {
+    localStorage.setItem(storageKey, JSON.stringify({ server: $("server").value, credentials: state.credentials }));
+  }
+
+  // resolve follows the JSON references of the given schema.
+  function resolve(schema) {
+    var seen = 0;
+    while (schema && schema.$ref && seen++ < 32) {
+      var path = schema.$ref.replace(/^#\//, "").split("/");
+      schema = path.reduce(function (o, k) { return o && o[k]; }, state.spec);
+    }
+    return schema || {};
+  }
+
+  // example builds an example value for the given schema.
+  function example(schema, depth) {
+    schema = resolve(schema);
+    if (schema.example !== undefined) { return schema.example; }
+    if (schema["default"] !== undefined) { return schema["default"]; }
+    if (schema["enum"] && schema["enum"].length) { return schema["enum"][0]; }
+    if (depth > 4) { return null; }
+    switch (schema.type) {
+    case "object":
+      var o = {};
+      Object.keys(schema.properties || {}).forEach(function (k) { o[k] = example(schema.properties[k], depth + 1); });
+      return o;
+    case "array": return [example(schema.items || {}, depth + 1)];
+    case "integer": case "number": return 0;
+    case "boolean": return false;
+    case "string": return schema.format === "date-time" ? new Date().toISOString() : "";
+    }
+    return null;
+  }
+
+  function operations(spec) {
+    var ops = [];
+    Object.keys(spec.paths || {}).sort().forEach(function (path) {
+      var item = spec.paths[path];
+      ["get", "post", "put", "patch", "delete", "head", "options"].forEach(function (verb) {
+        var op = item[verb];
+        if (!op) { return; }
+        var params = (item.parameters || []).concat(op.parameters || []).map(resolve);
+        ops.push({
+          id: op.operationId || verb + " " + path,
+          verb: verb.toUpperCase(),
+          path: path,
+          tag: (op.tags && op.tags[0]) || "default",
+          op: op,
+          params: params
+        });
+      });
+    });
+    return ops;
+  }
+
+  function renderNav() {
+    var filter = $("filter").value.toLowerCase();
+    var nav = $("operations");
+    nav.textContent = "";
+    var tag = null;
+    state.operations.forEach(function (o) {
+      var label = o.verb + " " + o.path + " " + (o.op.summary || "");
+      if (filter && label.toLowerCase().indexOf(filter) < 0) { return; }
+      if (o.tag !== tag) {
+        tag = o.tag;
+        nav.appendChild(el("h2", { text: tag }));
+      }
+      var a = el("a", { href: "#" + encodeURIComponent(o.id) }, [
+        el("span", { "class": "verb " + o.verb, text: o.verb }),
+        document.createTextNode(o.path)
+      ]);
+      a.onclick = function () { renderOperation(o); };
+      nav.appendChild(a);
+    });
+  }
+
+  function renderOperation(o) {
+    var section = $("operation");
+    section.textContent = "";
+    section.appendChild(el("h2", {}, [el("span", { "class": "verb " + o.verb, text: o.verb }), document.createTextNode(o.path)]));
+    if (o.op.summary) { section.appendChild(el("p", { text: o.op.summary })); }
+    if (o.op.description) { section.appendChild(el("pre", { text: o.op.description })); }
+
+    var inputs = {};
+    var table = el("table");
+    o.params.forEach(function (p) {
+      if (p["in"] === "body") { return; }
+      var input = el("input", { placeholder: p.type || "" });
+      var ex = p["x-example"] !== undefined ? p["x-example"] : p["default"];
+      if (ex !== undefined) { input.value = String(ex); }
+      inputs[p["in"] + ":" + p.name] = { param: p, input: input };
+      table.appendChild(el("tr", {}, [
+        el("td", {}, [el("code", { text: p.name }), p.required ? el("span", { "class": "error", text: " *" }) : null]),
+        el("td", { "class": "muted", text: p["in"] }),
+        el("td", {}, [input]),
+        el("td", { "class": "muted", text: p.description || "" })
+      ]));
+    });
+    if (table.childNodes.length) {
+      section.appendChild(el("h3", { text: "Parameters" }));
+      section.appendChild(table);
+    }
+
+    var body = null;
+    o.params.forEach(function (p) {
+      if (p["in"] !== "body") { return; }
+      body = el("textarea");
+      body.value = JSON.stringify(example(p.schema || {}, 0), null, 2);
+      section.appendChild(el("h3", { text: "Body" }));
+      section.appendChild(body);
+    });
+
+    var samples = o.op["x-code-samples"] || [];
+    samples.forEach(function (s) {
+      section.appendChild(el("h3", { text: s.label }));
+      section.appendChild(el("pre", { text: s.source }));
+    });
+
+    var result = el("div");
+    var send = el("button", { type: "button", text: "Send" });
+    send.onclick = function () { call(o, inputs, body, result); };
+    section.appendChild(el("p", {}, [send]));
+    section.appendChild(result);
+  }
+
+  // security returns the scheme used to authorize the given operation, the first scheme listed
+  // by the operation for which the user entered credentials.
+  function security(o) {
+    var reqs = o.op.security || state.spec.security || [];
+    for (var i = 0; i < reqs.length; i++) {
+      var names = Object.keys(reqs[i]);
+      for (var j = 0; j < names.length; j++) {
+        var scheme = config.schemes.filter(function (s) { return s.name === names[j]; })[0];
+        if (scheme && (state.credentials[scheme.name] || scheme.kind === "session")) { return scheme; }
+      }
+    }
+    return null;
+  }
+
+  function call(o, inputs, body, result) {
+    result.textContent = "";
+    var base = ($("server").value || window.location.origin).replace(/\/$/, "");
+    var path = (state.spec.basePath || "").replace(/\/$/, "") + o.path;
+    var query = [];
+    var headers = {};
+    var missing = [];
+    Object.keys(inputs).forEach(function (k) {
+      var p = inputs[k].param, v = inputs[k].input.value;
+      if (v === "") {
+        if (p.required) { missing.push(p.name); }
+        return;
+      }
+      switch (p["in"]) {
+      case "path":
+        path = path.replace(new RegExp("\\{" + p.name + "\\}|:" + p.name + "(?=/|$)", "g"), encodeURIComponent(v));
+        break;
+      case "query":
+        (p.type === "array" ? v.split(",") : [v]).forEach(function (item) {
+          query.push(encodeURIComponent(p.name) + "=" + encodeURIComponent(item.trim()));
+        });
+        break;
+      case "header":
+        headers[p.name] = v;
+        break;
+      }
+    });
+    if (missing.length) {
+      result.appendChild(el("p", { "class": "error", text: "Missing required parameters: " + missing.join(", ") }));
+      return;
+    }
+    var init = { method: o.verb, headers: headers, credentials: "same-origin" };
+    var scheme = security(o);
+    if (scheme) {
+      var cred = state.credentials[scheme.name] || {};
+      switch (scheme.kind) {
+      case "basic":
+        headers.Authorization = "Basic " + btoa((cred.user || "") + ":" + (cred.password || ""));
+        break;
+      case "apiKey": case "jwt":
+        var value = cred.value || "";
+        if (scheme.kind === "jwt" && scheme.param.toLowerCase() === "authorization" && !/^bearer /i.test(value)) {
+          value = "Bearer " + value;
+        }
+        if (scheme["in"] === "query") {
+          query.push(encodeURIComponent(scheme.param) + "=" + encodeURIComponent(value));
+        } else {
+          headers[scheme.param] = value;
+        }
+        break;
+      case "oauth2":
+        headers.Authorization = "Bearer " + (cred.value || "");
+        break;
+      case "session":
+        init.credentials = "include";
+        break;
+      }
+    }
+    if (body) {
+      headers["Content-Type"] = "application/json";
+      init.body = body.value;
+    }
+    var url = base + path + (query.length ? "?" + query.join("&") : "");
+    var started = Date.now();
+    result.appendChild(el("p", { "class": "muted", text: o.verb + " " + url }));
+    fetch(url, init).then(function (resp) {
+      return resp.text().then(function (text) {
+        var lines = [];
+        resp.headers.forEach(function (v, k) { lines.push(k + ": " + v); });
+        try { text = JSON.stringify(JSON.parse(text), null, 2); } catch (e) { /* not JSON */ }
+        result.appendChild(el("h3", { text: resp.status + " " + resp.statusText + " (" + (Date.now() - started) + " ms)" }));
+        result.appendChild(el("pre", { "class": "muted", text: lines.join("\n") }));
+        result.appendChild(el("pre", { text: text }));
+      });
+    })["catch"](function (err) {
+      result.appendChild(el("p", { "class": "error", text: "Request failed: " + err.message }));
+    });
+  }
+
+  function renderSchemes() {
+    var div = $("schemes");
+    div.textContent = "";
+    if (!config.schemes.length) {
+      div.appendChild(el("p", { "class": "muted", text: "The API does not define security schemes." }));
+    }
+    config.schemes.forEach(function (s) {
+      var cred = state.credentials[s.name] || {};
+      var fields = [];
+      var field = function (key, label, type) {
+        var input = el("input", { type: type || "text", "data-scheme": s.name, "data-key": key, placeholder: label });
+        input.value = cred[key] || "";
+        fields.push(el("div", {}, [input]));
+      };
+      switch (s.kind) {
+      case "basic": field("user", "Username"); field("password", "Password", "password"); break;
+      case "session": fields.push(el("p", { "class": "muted", text: "Uses the " + s.cookie + " cookie set by the browser." })); break;
+      case "jwt": case "oauth2": field("value", "Token"); break;
+      default: field("value", "Key (" + s["in"] + " " + s.param + ")");
+      }
+      div.appendChild(el("fieldset", {}, [el("legend", { text: s.name + " (" + s.kind + ")" })].concat(fields)));
+    });
+  }
+
+  function init() {
+    load();
+    renderSchemes();
+    $("server").onchange = save;
+    $("filter").oninput = renderNav;
+    $("auth-open").onclick = function () { renderSchemes(); $("auth").showModal(); };
+    $("auth-save").onclick = function () {
+      Array.prototype.forEach.call(document.querySelectorAll("#schemes input"), function (input) {
+        var name = input.getAttribute("data-scheme");
+        state.credentials[name] = state.credentials[name] || {};
+        state.credentials[name][input.getAttribute("data-key")] = input.value;
+      });
+      save();
+    };
+    fetch(config.spec).then(function (resp) {
+      if (!resp.ok) { throw new Error(resp.status + " " + resp.statusText); }
+      return resp.json();
+    }).then(function (spec) {
+      state.spec = spec;
+      state.operations = operations(spec);
+      renderNav();
+      var id = decodeURIComponent(window.location.hash.slice(1));
+      var selected = state.operations.filter(function (o) { return o.id === id; })[0];
+      if (selected) {
+        renderOperation(selected);
+      } else {
+        $("operation").textContent = "";
+        $("operation").appendChild(el("p", { "class": "muted", text: "Select an operation." }));
+      }
+    })["catch"](function (err) {
+      $("operation").textContent = "";
+      $("operation").appendChild(el("p", { "class": "error", text: "Failed to load " + config.spec + ": " + err.message }));
+    });
+  }
+
+  init();
+})();
+</script>
+</body>
+</html>
+`
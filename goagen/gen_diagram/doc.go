@@ -0,0 +1,15 @@
+/*
+Package gendiagram provides a goa generator for design diagrams. The diagrams help onboarding and
+architecture reviews of large APIs:
+
+  - diagram/types.dot and diagram/types.d2 describe the type dependency graph: one node per user
+    type and media type and one edge per reference from a type attribute to another type.
+  - diagram/routes.dot and diagram/routes.d2 describe the route tree of each resource: the nodes
+    are the route path segments and list the actions they serve.
+
+The "dot" files use the Graphviz format, the "d2" files use the D2 format. Use the --format flag to
+generate only one format:
+
+	goagen diagram -d github.com/example/cellar/design --format dot
+*/
+package gendiagram
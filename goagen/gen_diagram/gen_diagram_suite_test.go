@@ -0,0 +1,13 @@
+package gendiagram_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenDiagram(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenDiagram Suite")
+}
@@ -0,0 +1,106 @@
+package gendiagram
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the design diagrams generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Format   string                // Diagram format: "dot", "d2" or empty for both
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, format, ver string
+
+	set := flag.NewFlagSet("diagram", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&format, "format", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Format: format, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the diagram files.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Format != "" && g.Format != "dot" && g.Format != "d2" {
+		return nil, fmt.Errorf(`invalid format "%s", must be "dot" or "d2"`, g.Format)
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "diagram")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	types := TypeGraph(g.API)
+	routes := RouteTrees(g.API)
+	if g.Format == "" || g.Format == "dot" {
+		if err = g.write("types.dot", types.Dot()); err != nil {
+			return
+		}
+		if err = g.write("routes.dot", routesDot(routes)); err != nil {
+			return
+		}
+	}
+	if g.Format == "" || g.Format == "d2" {
+		if err = g.write("types.d2", types.D2()); err != nil {
+			return
+		}
+		if err = g.write("routes.d2", routesD2(routes)); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// write writes the given content to the file with the given name in the output directory.
+func (g *Generator) write(name, content string) error {
+	file := filepath.Join(g.OutDir, name)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, file)
+	return nil
+}
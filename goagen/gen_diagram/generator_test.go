@@ -0,0 +1,118 @@
+package gendiagram_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_diagram"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var outDir string
+	var format string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "gendiagram")
+		Ω(err).ShouldNot(HaveOccurred())
+		format = ""
+		dslengine.Reset()
+		API("cellar", nil)
+		origin := Type("Origin", func() {
+			Attribute("country", String)
+		})
+		bottle := MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("name", String)
+				Attribute("origin", origin)
+			})
+			View("default", func() {
+				Attribute("name")
+			})
+		})
+		Resource("bottle", func() {
+			BasePath("/accounts/:accountID/bottles")
+			DefaultMedia(bottle)
+			Action("list", func() {
+				Routing(GET(""))
+				Response(OK, CollectionOf(bottle))
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+			})
+			Action("delete", func() {
+				Routing(DELETE("/:id"))
+				Response(NoContent)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo",
+			"--format=" + format, "--version=" + version.String()}
+		files, genErr = gendiagram.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(outDir, "diagram", name))
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(b)
+	}
+
+	It("generates the diagrams in both formats", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(files).Should(HaveLen(5))
+	})
+
+	It("generates the type dependency graph", func() {
+		dot := read("types.dot")
+		Ω(dot).Should(ContainSubstring(`"media_type:application/vnd.bottle" -> "type:Origin";`))
+		Ω(dot).Should(ContainSubstring(`"type:Origin" [label="Origin"];`))
+		Ω(read("types.d2")).Should(ContainSubstring(`"media_type:application/vnd.bottle" -> "type:Origin"`))
+	})
+
+	It("generates the route trees", func() {
+		dot := read("routes.dot")
+		Ω(dot).Should(ContainSubstring(`label="bottle";`))
+		Ω(dot).Should(ContainSubstring(`[label="/accounts/:accountID/bottles\nGET list"];`))
+		Ω(dot).Should(ContainSubstring(`[label="/:id\nDELETE delete\nGET show"];`))
+		Ω(dot).Should(ContainSubstring(`"bottle_1" -> "bottle_2";`))
+		Ω(read("routes.d2")).Should(ContainSubstring(`n1 -> n2`))
+	})
+
+	Context("with the dot format", func() {
+		BeforeEach(func() {
+			format = "dot"
+		})
+
+		It("generates the Graphviz diagrams only", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(3))
+		})
+	})
+
+	Context("with an invalid format", func() {
+		BeforeEach(func() {
+			format = "svg"
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+	})
+})
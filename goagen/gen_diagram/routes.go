@@ -0,0 +1,159 @@
+package gendiagram
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+type (
+	// RouteTree is the tree of the routes of a resource.
+	RouteTree struct {
+		// Resource is the resource name.
+		Resource string
+		// Root is the root node, its path is "/".
+		Root *RouteNode
+	}
+
+	// RouteNode is a route path prefix. Chains of nodes that serve no endpoint are merged into
+	// a single node.
+	RouteNode struct {
+		// Path is the path of the node relative to its parent, e.g. "/bottles/:id".
+		Path string
+		// Endpoints lists the endpoints served by the node, e.g. "GET show".
+		Endpoints []string
+		// Children lists the child nodes sorted by path.
+		Children []*RouteNode
+	}
+)
+
+// RouteTrees computes the route trees of the API resources sorted by resource name.
+func RouteTrees(api *design.APIDefinition) []*RouteTree {
+	var trees []*RouteTree
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		root := &RouteNode{Path: "/"}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			for _, route := range a.Routes {
+				root.insert(segments(route.FullPath()), fmt.Sprintf("%s %s", route.Verb, a.Name))
+			}
+			return nil
+		})
+		root.compress()
+		trees = append(trees, &RouteTree{Resource: r.Name, Root: root})
+		return nil
+	})
+	return trees
+}
+
+// insert adds the endpoint to the node identified by the given path segments relative to n.
+func (n *RouteNode) insert(segs []string, endpoint string) {
+	if len(segs) == 0 {
+		n.Endpoints = append(n.Endpoints, endpoint)
+		sort.Strings(n.Endpoints)
+		return
+	}
+	path := "/" + segs[0]
+	var child *RouteNode
+	for _, c := range n.Children {
+		if c.Path == path {
+			child = c
+			break
+		}
+	}
+	if child == nil {
+		child = &RouteNode{Path: path}
+		n.Children = append(n.Children, child)
+		sort.Sort(byPath(n.Children))
+	}
+	child.insert(segs[1:], endpoint)
+}
+
+// compress merges the children of n that serve no endpoint and have a single child with that child.
+func (n *RouteNode) compress() {
+	for i, c := range n.Children {
+		for len(c.Endpoints) == 0 && len(c.Children) == 1 {
+			gc := c.Children[0]
+			gc.Path = c.Path + gc.Path
+			c = gc
+		}
+		n.Children[i] = c
+		c.compress()
+	}
+}
+
+// segments returns the non empty segments of the given path.
+func segments(path string) []string {
+	var segs []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segs = append(segs, s)
+		}
+	}
+	return segs
+}
+
+// label returns the node label: its path followed by the endpoints it serves.
+func (n *RouteNode) label() string {
+	return strings.Join(append([]string{n.Path}, n.Endpoints...), "\n")
+}
+
+// walk calls fn on n and its descendants in depth first order, passing in each node the ID of its
+// parent or -1 for the root.
+func (n *RouteNode) walk(fn func(node *RouteNode, id, parent int)) {
+	next := 0
+	var walk func(node *RouteNode, parent int)
+	walk = func(node *RouteNode, parent int) {
+		id := next
+		next++
+		fn(node, id, parent)
+		for _, c := range node.Children {
+			walk(c, id)
+		}
+	}
+	walk(n, -1)
+}
+
+// routesDot returns the Graphviz representation of the route trees.
+func routesDot(trees []*RouteTree) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph routes {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for i, t := range trees {
+		fmt.Fprintf(&buf, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, t.Resource)
+		t.Root.walk(func(n *RouteNode, id, parent int) {
+			fmt.Fprintf(&buf, "\t\t\"%s_%d\" [label=%q];\n", t.Resource, id, n.label())
+			if parent >= 0 {
+				fmt.Fprintf(&buf, "\t\t\"%s_%d\" -> \"%s_%d\";\n", t.Resource, parent, t.Resource, id)
+			}
+		})
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// routesD2 returns the D2 representation of the route trees.
+func routesD2(trees []*RouteTree) string {
+	var buf bytes.Buffer
+	buf.WriteString("direction: right\n")
+	for _, t := range trees {
+		fmt.Fprintf(&buf, "%q: {\n", t.Resource)
+		t.Root.walk(func(n *RouteNode, id, parent int) {
+			fmt.Fprintf(&buf, "  n%d: %q\n", id, n.label())
+			if parent >= 0 {
+				fmt.Fprintf(&buf, "  n%d -> n%d\n", parent, id)
+			}
+		})
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+// byPath sorts route nodes by path.
+type byPath []*RouteNode
+
+func (b byPath) Len() int           { return len(b) }
+func (b byPath) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byPath) Less(i, j int) bool { return b[i].Path < b[j].Path }
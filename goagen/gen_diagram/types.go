@@ -0,0 +1,83 @@
+package gendiagram
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_symbols"
+)
+
+type (
+	// Graph is the type dependency graph of a design.
+	Graph struct {
+		// Nodes lists the user types and media types sorted by ID.
+		Nodes []*Node
+		// Edges lists the references between the nodes.
+		Edges []*Edge
+	}
+
+	// Node is a user type or media type.
+	Node struct {
+		ID    string // Unique node ID, e.g. "type:Bottle"
+		Label string // Node label, the type name and the media type identifier if any
+	}
+
+	// Edge is a reference from the attributes of a type to another type.
+	Edge struct {
+		From string // ID of referencing node
+		To   string // ID of referenced node
+	}
+)
+
+// TypeGraph computes the type dependency graph of the given API.
+func TypeGraph(api *design.APIDefinition) *Graph {
+	idx := gensymbols.Build(api)
+	g := &Graph{}
+	for _, s := range idx.Symbols {
+		var label string
+		switch s.Kind {
+		case gensymbols.KindType:
+			label = s.Name
+		case gensymbols.KindMediaType:
+			label = s.Name
+			if mt := api.MediaTypeWithIdentifier(s.Name); mt != nil {
+				label = mt.TypeName + "\n" + s.Name
+			}
+		default:
+			continue
+		}
+		g.Nodes = append(g.Nodes, &Node{ID: s.ID, Label: label})
+		for _, ref := range s.References {
+			g.Edges = append(g.Edges, &Edge{From: s.ID, To: ref})
+		}
+	}
+	return g
+}
+
+// Dot returns the Graphviz representation of the graph.
+func (g *Graph) Dot() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph types {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&buf, "\t%q [label=%q];\n", n.ID, n.Label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "\t%q -> %q;\n", e.From, e.To)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// D2 returns the D2 representation of the graph.
+func (g *Graph) D2() string {
+	var buf bytes.Buffer
+	buf.WriteString("direction: right\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&buf, "%q: %q\n", n.ID, n.Label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "%q -> %q\n", e.From, e.To)
+	}
+	return buf.String()
+}
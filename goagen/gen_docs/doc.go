@@ -0,0 +1,11 @@
+/*
+Package gendocs provides a goa generator for a Markdown API reference.
+The generator produces one Markdown file per resource in the "docs" directory of the output
+directory together with an index. Each resource file lists the resource routes and documents for
+each action the security requirements, the parameters, headers and payload attributes, the
+response bodies rendered for each view of their media type and curl and HTTPie examples built
+from the design examples. The API workflows are documented in workflows.md which lists the steps and
+their compensating actions and renders a Mermaid sequence diagram of each workflow. The files render
+on code hosting sites and are suitable for committing next to the code.
+*/
+package gendocs
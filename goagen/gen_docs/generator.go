@@ -0,0 +1,617 @@
+package gendocs
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Markdown API reference generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Scheme   string                // Scheme used in the examples
+	Host     string                // Host used in the examples
+	genfiles []string              // Generated files
+}
+
+type (
+	// Page holds the data needed to generate the reference of a resource.
+	Page struct {
+		Name        string    // Resource name
+		File        string    // Name of the Markdown file
+		Description string    // Resource description
+		Actions     []*Action // Resource actions
+	}
+
+	// Action holds the data needed to generate the reference of an action.
+	Action struct {
+		Name        string            // Action name
+		Anchor      string            // Markdown anchor of the action section
+		Description string            // Action description
+		Routes      []*Route          // Action routes
+		Security    string            // Description of the security requirements, empty if none
+		Params      []*Row            // Path and query string parameters
+		Headers     []*Row            // Request headers
+		Payload     *Schema           // Request payload if any
+		Responses   []*Response       // Action responses sorted by status
+		Samples     []*codegen.Sample // curl and HTTPie commands calling the action
+	}
+
+	// Route is a route of an action.
+	Route struct {
+		Verb string // HTTP method
+		Path string // Full request path
+	}
+
+	// Response holds the data needed to document a response.
+	Response struct {
+		Status      int       // HTTP status code
+		Name        string    // Response name
+		Description string    // Response description
+		MediaType   string    // Response media type identifier if any
+		Schemas     []*Schema // Response body schemas, one per rendered view
+	}
+
+	// Schema lists the attributes of a data structure.
+	Schema struct {
+		Title string // Schema title, e.g. the type name and view
+		Type  string // Type of the data structure if it is not an object
+		Rows  []*Row // Attributes of the data structure if it is an object
+	}
+
+	// Workflow holds the data needed to document a workflow.
+	Workflow struct {
+		Name        string          // Workflow name
+		Anchor      string          // Markdown anchor of the workflow section
+		Description string          // Workflow description
+		Steps       []*WorkflowStep // Workflow steps in execution order
+		Diagram     string          // Mermaid sequence diagram of the workflow
+	}
+
+	// WorkflowStep documents a workflow step.
+	WorkflowStep struct {
+		Name         string // Step name
+		Action       *Call  // Action called by the step
+		Compensation *Call  // Action that undoes the step if any
+	}
+
+	// Call is a reference to an action called by a workflow step.
+	Call struct {
+		Resource string // Resource name
+		Action   string // Action name
+		Link     string // Link to the action reference
+		Verb     string // HTTP method of the first action route
+		Path     string // Full path of the first action route
+	}
+
+	// Row documents an attribute.
+	Row struct {
+		Name        string // Attribute name, dotted path for nested attributes
+		In          string // Parameter location, "path" or "query", empty for attributes
+		Type        string // Attribute type
+		Required    bool   // Whether the attribute is required
+		Description string // Attribute description
+		Constraints string // Attribute validations
+	}
+)
+
+// maxDepth is the maximum number of nested levels of attributes rendered in the schemas.
+const maxDepth = 3
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, scheme, host, ver string
+	)
+
+	set := flag.NewFlagSet("docs", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Markdown API reference.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost:8080"
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "docs")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	pages, err := g.pages()
+	if err != nil {
+		return
+	}
+	workflows := g.workflows()
+	data := map[string]interface{}{
+		"API":       g.API,
+		"BaseURL":   g.Scheme + "://" + g.Host,
+		"Pages":     pages,
+		"Schemes":   g.API.SecuritySchemes,
+		"Workflows": workflows,
+	}
+	if err = g.generateFile(filepath.Join(g.OutDir, "README.md"), "index", indexT, data); err != nil {
+		return
+	}
+	if len(workflows) > 0 {
+		if err = g.generateFile(filepath.Join(g.OutDir, "workflows.md"), "workflows", workflowsT, data); err != nil {
+			return
+		}
+	}
+	for _, p := range pages {
+		data["Page"] = p
+		if err = g.generateFile(filepath.Join(g.OutDir, p.File), "resource", resourceT, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+func (g *Generator) generateFile(path, name, tmpl string, data map[string]interface{}) error {
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	funcs := template.FuncMap{"cell": cell, "schemeKind": schemeKind, "join": strings.Join}
+	return file.ExecuteTemplate(name, tmpl, funcs, data)
+}
+
+// pages computes the data needed to generate the reference of each resource.
+func (g *Generator) pages() ([]*Page, error) {
+	var pages []*Page
+	err := g.API.IterateResources(func(res *design.ResourceDefinition) error {
+		p := &Page{
+			Name:        res.Name,
+			File:        codegen.SnakeCase(res.Name) + ".md",
+			Description: res.Description,
+		}
+		err := res.IterateActions(func(action *design.ActionDefinition) error {
+			a, err := g.action(action)
+			if err != nil {
+				return err
+			}
+			p.Actions = append(p.Actions, a)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		pages = append(pages, p)
+		return nil
+	})
+	return pages, err
+}
+
+// action computes the data needed to generate the reference of the given action.
+func (g *Generator) action(action *design.ActionDefinition) (*Action, error) {
+	a := &Action{
+		Name:        action.Name,
+		Anchor:      anchor(action.Name),
+		Description: action.Description,
+	}
+	for _, r := range action.Routes {
+		a.Routes = append(a.Routes, &Route{Verb: r.Verb, Path: r.FullPath()})
+	}
+	if sec := action.Security; sec != nil && sec.Scheme.Kind != design.NoSecurityKind {
+		a.Security = fmt.Sprintf("`%s` (%s)", sec.Scheme.SchemeName, schemeKind(sec.Scheme))
+		if len(sec.Scopes) > 0 {
+			a.Security += ", scopes: `" + strings.Join(sec.Scopes, "`, `") + "`"
+		}
+		if len(sec.Roles) > 0 {
+			a.Security += ", roles: `" + strings.Join(sec.Roles, "`, `") + "`"
+		}
+	}
+
+	if params := action.AllParams(); params != nil {
+		pathParams := make(map[string]bool)
+		for _, r := range action.Routes {
+			for _, p := range r.Params() {
+				pathParams[p] = true
+			}
+		}
+		o := params.Type.ToObject()
+		for _, n := range codegen.SortedAttributeNames(o) {
+			in := "query"
+			if pathParams[n] {
+				in = "path"
+			}
+			row := attributeRow(n, o[n], params.IsRequired(n) || in == "path")
+			row.In = in
+			a.Params = append(a.Params, row)
+		}
+		sort.SliceStable(a.Params, func(i, j int) bool { return a.Params[i].In == "path" && a.Params[j].In != "path" })
+	}
+	if h := action.Headers; h != nil {
+		o := h.Type.ToObject()
+		for _, n := range codegen.SortedAttributeNames(o) {
+			a.Headers = append(a.Headers, attributeRow(n, o[n], h.IsRequired(n)))
+		}
+	}
+	if p := action.Payload; p != nil {
+		a.Payload = schema(typeName(p), p.AttributeDefinition)
+	}
+
+	for _, r := range action.Responses {
+		resp := &Response{Status: r.Status, Name: r.Name, Description: r.Description, MediaType: r.MediaType}
+		if mt, ok := g.API.MediaTypes[design.CanonicalIdentifier(r.MediaType)]; ok {
+			views := []string{r.ViewName}
+			if r.ViewName == "" {
+				views = sortedViewNames(mt)
+			}
+			for _, v := range views {
+				s, err := viewSchema(mt, v)
+				if err != nil {
+					return nil, err
+				}
+				resp.Schemas = append(resp.Schemas, s)
+			}
+		} else if r.Type != nil {
+			resp.Schemas = append(resp.Schemas, schema(typeName(r.Type), &design.AttributeDefinition{Type: r.Type}))
+		}
+		a.Responses = append(a.Responses, resp)
+	}
+	sort.Slice(a.Responses, func(i, j int) bool {
+		if a.Responses[i].Status == a.Responses[j].Status {
+			return a.Responses[i].Name < a.Responses[j].Name
+		}
+		return a.Responses[i].Status < a.Responses[j].Status
+	})
+
+	if len(action.Routes) > 0 {
+		samples, err := codegen.ActionSamples(action.Routes[0], g.Scheme+"://"+g.Host)
+		if err != nil {
+			return nil, err
+		}
+		a.Samples = samples
+	}
+	return a, nil
+}
+
+// workflows computes the data needed to document the API workflows.
+func (g *Generator) workflows() []*Workflow {
+	workflows := make([]*Workflow, len(g.API.Workflows))
+	for i, w := range g.API.Workflows {
+		wf := &Workflow{Name: w.Name, Anchor: anchor(w.Name), Description: w.Description}
+		for _, s := range w.Steps {
+			step := &WorkflowStep{Name: s.Name, Action: call(s.CallAction())}
+			if a := s.CompensationCallAction(); a != nil {
+				step.Compensation = call(a)
+			}
+			wf.Steps = append(wf.Steps, step)
+		}
+		wf.Diagram = sequenceDiagram(wf)
+		workflows[i] = wf
+	}
+	return workflows
+}
+
+// call returns the reference to the given action.
+func call(a *design.ActionDefinition) *Call {
+	c := &Call{
+		Resource: a.Parent.Name,
+		Action:   a.Name,
+		Link:     codegen.SnakeCase(a.Parent.Name) + ".md#" + anchor(a.Name),
+	}
+	if len(a.Routes) > 0 {
+		c.Verb = a.Routes[0].Verb
+		c.Path = a.Routes[0].FullPath()
+	}
+	return c
+}
+
+// sequenceDiagram returns the Mermaid sequence diagram of the given workflow. The compensating
+// calls made when a step fails are rendered in an optional block following the step.
+func sequenceDiagram(w *Workflow) string {
+	var b strings.Builder
+	b.WriteString("sequenceDiagram\n    participant Client\n")
+	seen := make(map[string]bool)
+	participant := func(c *Call) {
+		if !seen[c.Resource] {
+			seen[c.Resource] = true
+			fmt.Fprintf(&b, "    participant %s as %s\n", codegen.Goify(c.Resource, true), c.Resource)
+		}
+	}
+	for _, s := range w.Steps {
+		participant(s.Action)
+		if s.Compensation != nil {
+			participant(s.Compensation)
+		}
+	}
+	message := func(indent string, c *Call, label string) {
+		fmt.Fprintf(&b, "%sClient->>%s: %s", indent, codegen.Goify(c.Resource, true), label)
+		if c.Verb != "" {
+			fmt.Fprintf(&b, " (%s %s)", c.Verb, c.Path)
+		}
+		b.WriteString("\n")
+	}
+	for i, s := range w.Steps {
+		message("    ", s.Action, s.Name)
+		var compensated bool
+		for j := i - 1; j >= 0; j-- {
+			if w.Steps[j].Compensation != nil {
+				compensated = true
+				break
+			}
+		}
+		if !compensated {
+			continue
+		}
+		fmt.Fprintf(&b, "    opt %s fails\n", s.Name)
+		for j := i - 1; j >= 0; j-- {
+			if c := w.Steps[j].Compensation; c != nil {
+				message("        ", c, "undo "+w.Steps[j].Name)
+			}
+		}
+		b.WriteString("    end\n")
+	}
+	return b.String()
+}
+
+// viewSchema returns the schema of the given view of the given media type.
+func viewSchema(mt *design.MediaTypeDefinition, view string) (*Schema, error) {
+	if _, ok := mt.Views[view]; !ok {
+		return schema(mt.TypeName, mt.AttributeDefinition), nil
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		return nil, fmt.Errorf("%s view %#v: %s", mt.TypeName, view, err)
+	}
+	title := fmt.Sprintf("%s (%s view)", p.TypeName, view)
+	return schema(title, p.AttributeDefinition), nil
+}
+
+// schema returns the schema of the given attribute.
+func schema(title string, att *design.AttributeDefinition) *Schema {
+	s := &Schema{Title: title}
+	if !att.Type.IsObject() {
+		s.Type = typeName(att.Type)
+		return s
+	}
+	s.Rows = rows("", att, 0, nil)
+	return s
+}
+
+// rows returns the rows documenting the attributes of the given object attribute and of their
+// nested objects. prefix is the path of the attribute.
+func rows(prefix string, att *design.AttributeDefinition, depth int, seen []string) []*Row {
+	var res []*Row
+	o := att.Type.ToObject()
+	if ut, ok := att.Type.(*design.UserTypeDefinition); ok {
+		seen = append(seen, ut.TypeName)
+	} else if mt, ok := att.Type.(*design.MediaTypeDefinition); ok {
+		seen = append(seen, mt.TypeName)
+	}
+	for _, n := range codegen.SortedAttributeNames(o) {
+		child := o[n]
+		row := attributeRow(prefix+n, child, att.IsRequired(n))
+		res = append(res, row)
+		if depth+1 >= maxDepth {
+			continue
+		}
+		nested := child
+		suffix := "."
+		if arr, ok := child.Type.(*design.Array); ok {
+			nested = arr.ElemType
+			suffix = "[]."
+		}
+		if !nested.Type.IsObject() || recursive(nested.Type, seen) {
+			continue
+		}
+		res = append(res, rows(prefix+n+suffix, nested, depth+1, seen)...)
+	}
+	return res
+}
+
+// recursive returns true if the given type is a user type already rendered by the enclosing
+// rows.
+func recursive(t design.DataType, seen []string) bool {
+	var name string
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		name = actual.TypeName
+	case *design.UserTypeDefinition:
+		name = actual.TypeName
+	default:
+		return false
+	}
+	for _, s := range seen {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// attributeRow returns the row documenting the given attribute.
+func attributeRow(name string, att *design.AttributeDefinition, required bool) *Row {
+	return &Row{
+		Name:        name,
+		Type:        typeName(att.Type),
+		Required:    required,
+		Description: att.Description,
+		Constraints: constraints(att),
+	}
+}
+
+// typeName returns the name of the given type as rendered in the reference.
+func typeName(t design.DataType) string {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		if actual.IsArray() {
+			return "array<" + typeName(actual.ToArray().ElemType.Type) + ">"
+		}
+		return actual.TypeName
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.ScalarDefinition:
+		return actual.TypeName
+	case *design.FlagsDefinition:
+		return actual.TypeName
+	case *design.Array:
+		return "array<" + typeName(actual.ElemType.Type) + ">"
+	case *design.Hash:
+		return "map<" + typeName(actual.KeyType.Type) + ", " + typeName(actual.ElemType.Type) + ">"
+	case design.Object:
+		return "object"
+	case design.Primitive:
+		return actual.Name()
+	}
+	return "any"
+}
+
+// constraints returns the description of the validations of the given attribute.
+func constraints(att *design.AttributeDefinition) string {
+	var res []string
+	if att.DefaultValue != nil {
+		res = append(res, fmt.Sprintf("default: `%v`", literal(att.DefaultValue)))
+	}
+	v := att.Validation
+	if v == nil {
+		return strings.Join(res, ", ")
+	}
+	if len(v.Values) > 0 {
+		vals := make([]string, len(v.Values))
+		for i, val := range v.Values {
+			vals[i] = "`" + literal(val) + "`"
+		}
+		res = append(res, "one of "+strings.Join(vals, ", "))
+	}
+	if v.Format != "" {
+		res = append(res, "format: `"+v.Format+"`")
+	}
+	if v.Pattern != "" {
+		res = append(res, "pattern: `"+v.Pattern+"`")
+	}
+	if v.Minimum != nil {
+		res = append(res, "minimum: "+strconv.FormatFloat(*v.Minimum, 'f', -1, 64))
+	}
+	if v.Maximum != nil {
+		res = append(res, "maximum: "+strconv.FormatFloat(*v.Maximum, 'f', -1, 64))
+	}
+	if v.MinLength != nil {
+		res = append(res, "min length: "+strconv.Itoa(*v.MinLength))
+	}
+	if v.MaxLength != nil {
+		res = append(res, "max length: "+strconv.Itoa(*v.MaxLength))
+	}
+	return strings.Join(res, ", ")
+}
+
+// literal returns the JSON representation of the given value.
+func literal(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// schemeKind returns a human readable name of the kind of the given security scheme.
+func schemeKind(s *design.SecuritySchemeDefinition) string {
+	switch s.Kind {
+	case design.OAuth2SecurityKind:
+		return "OAuth2"
+	case design.BasicAuthSecurityKind:
+		return "basic auth"
+	case design.APIKeySecurityKind:
+		return fmt.Sprintf("API key in %s `%s`", s.In, s.Name)
+	case design.JWTSecurityKind:
+		return "JWT"
+	case design.SessionSecurityKind:
+		return fmt.Sprintf("session cookie `%s`", s.CookieName)
+	}
+	return "none"
+}
+
+// cell escapes the given text so that it can be rendered in a Markdown table cell.
+func cell(s string) string {
+	s = strings.Replace(strings.TrimSpace(s), "|", `\|`, -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+// anchor returns the GitHub Markdown anchor of the heading with the given text.
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// sortedViewNames returns the names of the views of the given media type, the default view first.
+func sortedViewNames(mt *design.MediaTypeDefinition) []string {
+	var names []string
+	for n := range mt.Views {
+		if n != design.DefaultView {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := mt.Views[design.DefaultView]; ok || len(names) == 0 {
+		names = append([]string{design.DefaultView}, names...)
+	}
+	return names
+}
@@ -0,0 +1,233 @@
+package gendocs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_docs"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_docs/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--host=baz", "--version=" + version.String()}
+		design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gendocs.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a secured action with params, a payload and a result", func() {
+		BeforeEach(func() {
+			scheme := &design.SecuritySchemeDefinition{
+				Kind:        design.JWTSecurityKind,
+				SchemeName:  "jwt",
+				Description: "JWT | bearer token",
+			}
+			bottle := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Bottle",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":   &design.AttributeDefinition{Type: design.Integer, Description: "Unique bottle ID"},
+							"name": &design.AttributeDefinition{Type: design.String},
+							"color": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+					},
+				},
+				Identifier: "application/vnd.bottle",
+			}
+			bottle.Views = map[string]*design.ViewDefinition{
+				"default": {
+					Name:   "default",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id":    &design.AttributeDefinition{Type: design.Integer},
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"color": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				"tiny": {
+					Name:   "tiny",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id": &design.AttributeDefinition{Type: design.Integer},
+					}},
+				},
+			}
+			action := &design.ActionDefinition{
+				Name:        "update",
+				Description: "Update a bottle",
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"bottleID": &design.AttributeDefinition{Type: design.Integer, Example: 42},
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+					},
+				},
+				QueryParams: &design.AttributeDefinition{
+					Type: design.Object{
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "UpdateBottlePayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{Type: design.String, Example: "Chateau"},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"OK":       {Name: "OK", Status: 200, MediaType: "application/vnd.bottle", ViewName: "tiny"},
+					"NotFound": {Name: "NotFound", Status: 404},
+				},
+				Security: &design.SecurityDefinition{Scheme: scheme, Scopes: []string{"bottle:write"}},
+			}
+			show := &design.ActionDefinition{
+				Name: "show",
+				Responses: map[string]*design.ResponseDefinition{
+					"OK": {Name: "OK", Status: 200, MediaType: "application/vnd.bottle"},
+				},
+			}
+			action.Routes = []*design.RouteDefinition{{Verb: "PUT", Path: "/:bottleID", Parent: action}}
+			show.Routes = []*design.RouteDefinition{{Verb: "GET", Path: "", Parent: show}}
+			res := &design.ResourceDefinition{
+				Name:        "bottle",
+				Description: "A wine bottle",
+				BasePath:    "/bottles",
+				Actions:     map[string]*design.ActionDefinition{"update": action, "show": show},
+			}
+			action.Parent = res
+			show.Parent = res
+			design.Design = &design.APIDefinition{
+				Name:            "cellar",
+				BasePath:        "/cellar",
+				Resources:       map[string]*design.ResourceDefinition{"bottle": res},
+				MediaTypes:      map[string]*design.MediaTypeDefinition{"application/vnd.bottle": bottle},
+				SecuritySchemes: []*design.SecuritySchemeDefinition{scheme},
+			}
+		})
+
+		It("generates the index", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "README.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("# cellar\n"))
+			Ω(string(content)).Should(ContainSubstring("Base URL: `http://baz/cellar`"))
+			Ω(string(content)).Should(ContainSubstring("| `jwt` | JWT | JWT \\| bearer token |"))
+			Ω(string(content)).Should(ContainSubstring("| [bottle](bottle.md) | A wine bottle |"))
+		})
+
+		It("generates the route table", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("| [show](#show) | `GET` | `/cellar/bottles` |  |\n"))
+			Ω(string(content)).Should(ContainSubstring("| [update](#update) | `PUT` | `/cellar/bottles/:bottleID` | Update a bottle |\n"))
+		})
+
+		It("documents the action", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("**Security:** `jwt` (JWT), scopes: `bottle:write`"))
+			Ω(string(content)).Should(ContainSubstring("| `bottleID` | path | integer | yes |  |  |\n| `sort` | query | string | no |  | one of `\"name\"`, `\"vintage\"` |"))
+			Ω(string(content)).Should(ContainSubstring("### Payload\n\nUpdateBottlePayload\n\n| Attribute |"))
+			Ω(string(content)).Should(ContainSubstring("| `name` | string | yes |  |  |"))
+			Ω(string(content)).Should(ContainSubstring("| 200 | OK | `application/vnd.bottle` |  |\n| 404 | NotFound |  |  |"))
+		})
+
+		It("renders the response views", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("#### 200 OK: BottleTiny (tiny view)\n"))
+			Ω(string(content)).Should(ContainSubstring("#### 200 OK: Bottle (default view)\n"))
+			Ω(string(content)).Should(ContainSubstring("| `id` | integer | yes | Unique bottle ID |  |"))
+		})
+
+		It("generates the curl and HTTPie examples", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("curl:\n\n```sh\ncurl -X PUT 'http://baz/cellar/bottles/42' \\\n" +
+				"  -H \"Authorization: Bearer $JWT_TOKEN\" \\\n" +
+				"  -H 'Content-Type: application/json' \\\n" +
+				"  -d '{\"name\":\"Chateau\"}'"))
+			Ω(string(content)).Should(ContainSubstring("HTTPie:\n\n```sh\necho '{\"name\":\"Chateau\"}' | http PUT 'http://baz/cellar/bottles/42' \\\n" +
+				"  \"Authorization:Bearer $JWT_TOKEN\""))
+		})
+
+		Context("with a workflow", func() {
+			BeforeEach(func() {
+				w := &design.WorkflowDefinition{Name: "restock", Description: "Restocks bottles"}
+				w.Steps = []*design.WorkflowStepDefinition{
+					{Name: "update", Resource: "bottle", Action: "update", CompensationResource: "bottle", CompensationAction: "show", Parent: w},
+					{Name: "check", Resource: "bottle", Action: "show", Parent: w},
+				}
+				design.Design.Workflows = []*design.WorkflowDefinition{w}
+			})
+
+			It("lists the workflows in the index", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "README.md"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("| [restock](workflows.md#restock) | Restocks bottles |"))
+			})
+
+			It("documents the workflow steps", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "workflows.md"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("| `update` | [bottle update](bottle.md#update) `PUT /cellar/bottles/:bottleID` | [bottle show](bottle.md#show) `GET /cellar/bottles` |\n"))
+				Ω(string(content)).Should(ContainSubstring("| `check` | [bottle show](bottle.md#show) `GET /cellar/bottles` |  |\n"))
+			})
+
+			It("renders the sequence diagram", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "docs", "workflows.md"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("```mermaid\nsequenceDiagram\n" +
+					"    participant Client\n" +
+					"    participant Bottle as bottle\n" +
+					"    Client->>Bottle: update (PUT /cellar/bottles/:bottleID)\n" +
+					"    Client->>Bottle: check (GET /cellar/bottles)\n" +
+					"    opt check fails\n" +
+					"        Client->>Bottle: undo update (GET /cellar/bottles)\n" +
+					"    end\n```"))
+			})
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package gendocs
+
+const indexT = `<!-- Code generated by goagen, DO NOT EDIT. -->
+
+# {{ if .API.Title }}{{ .API.Title }}{{ else }}{{ .API.Name }}{{ end }}
+{{ if .API.Description }}
+{{ .API.Description }}
+{{ end }}
+Base URL: ` + "`" + `{{ .BaseURL }}{{ .API.BasePath }}` + "`" + `
+{{ if .API.Version }}
+Version: ` + "`" + `{{ .API.Version }}` + "`" + `
+{{ end }}{{ if .Schemes }}
+## Security schemes
+
+| Scheme | Kind | Description |
+| ------ | ---- | ----------- |
+{{ range .Schemes }}| ` + "`" + `{{ .SchemeName }}` + "`" + ` | {{ cell (schemeKind .) }} | {{ cell .Description }} |
+{{ end }}{{ end }}
+## Resources
+
+| Resource | Description |
+| -------- | ----------- |
+{{ range .Pages }}| [{{ .Name }}]({{ .File }}) | {{ cell .Description }} |
+{{ end }}{{ if .Workflows }}
+## Workflows
+
+| Workflow | Description |
+| -------- | ----------- |
+{{ range .Workflows }}| [{{ .Name }}](workflows.md#{{ .Anchor }}) | {{ cell .Description }} |
+{{ end }}{{ end }}`
+
+const workflowsT = `{{ define "call" }}[{{ .Resource }} {{ .Action }}]({{ .Link }}){{ if .Verb }} ` + "`" + `{{ .Verb }} {{ .Path }}` + "`" + `{{ end }}{{ end }}<!-- Code generated by goagen, DO NOT EDIT. -->
+
+# Workflows
+
+[Back to index](README.md)
+{{ range .Workflows }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+| Step | Action | Compensation |
+| ---- | ------ | ------------ |
+{{ range .Steps }}| ` + "`" + `{{ .Name }}` + "`" + ` | {{ template "call" .Action }} | {{ with .Compensation }}{{ template "call" . }}{{ end }} |
+{{ end }}
+` + "```mermaid" + `
+{{ .Diagram }}` + "```" + `
+{{ end }}`
+
+const resourceT = `{{ define "schema" }}{{ if .Rows }}| Attribute | Type | Required | Description | Constraints |
+| --------- | ---- | -------- | ----------- | ----------- |
+{{ range .Rows }}| ` + "`" + `{{ .Name }}` + "`" + ` | {{ cell .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} | {{ cell .Constraints }} |
+{{ end }}{{ else if .Type }}Type: ` + "`" + `{{ .Type }}` + "`" + `
+{{ else }}Empty object.
+{{ end }}{{ end }}<!-- Code generated by goagen, DO NOT EDIT. -->
+
+# {{ .Page.Name }}
+{{ if .Page.Description }}
+{{ .Page.Description }}
+{{ end }}
+[Back to index](README.md)
+
+| Action | Method | Path | Description |
+| ------ | ------ | ---- | ----------- |
+{{ range $a := .Page.Actions }}{{ range .Routes }}| [{{ $a.Name }}](#{{ $a.Anchor }}) | ` + "`" + `{{ .Verb }}` + "`" + ` | ` + "`" + `{{ .Path }}` + "`" + ` | {{ cell $a.Description }} |
+{{ end }}{{ end }}{{ range .Page.Actions }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}
+{{ range .Routes }}    {{ .Verb }} {{ .Path }}
+{{ end }}
+{{ if .Security }}**Security:** {{ .Security }}
+{{ else }}**Security:** none
+{{ end }}{{ if .Params }}
+### Parameters
+
+| Name | In | Type | Required | Description | Constraints |
+| ---- | -- | ---- | -------- | ----------- | ----------- |
+{{ range .Params }}| ` + "`" + `{{ .Name }}` + "`" + ` | {{ .In }} | {{ cell .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} | {{ cell .Constraints }} |
+{{ end }}{{ end }}{{ if .Headers }}
+### Headers
+
+| Name | Type | Required | Description | Constraints |
+| ---- | ---- | -------- | ----------- | ----------- |
+{{ range .Headers }}| ` + "`" + `{{ .Name }}` + "`" + ` | {{ cell .Type }} | {{ if .Required }}yes{{ else }}no{{ end }} | {{ cell .Description }} | {{ cell .Constraints }} |
+{{ end }}{{ end }}{{ if .Payload }}
+### Payload
+
+{{ .Payload.Title }}
+
+{{ template "schema" .Payload }}{{ end }}{{ if .Responses }}
+### Responses
+
+| Status | Name | Media type | Description |
+| ------ | ---- | ---------- | ----------- |
+{{ range .Responses }}| {{ .Status }} | {{ .Name }} | {{ if .MediaType }}` + "`" + `{{ .MediaType }}` + "`" + `{{ end }} | {{ cell .Description }} |
+{{ end }}{{ range $r := .Responses }}{{ range .Schemas }}
+#### {{ $r.Status }} {{ $r.Name }}: {{ .Title }}
+
+{{ template "schema" . }}{{ end }}{{ end }}{{ end }}{{ if .Samples }}
+### Examples
+{{ range .Samples }}
+{{ .Label }}:
+
+` + "```sh" + `
+{{ .Source }}
+` + "```" + `
+{{ end }}{{ end }}{{ end }}`
@@ -0,0 +1,140 @@
+package gengateway
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+// Integration is the function used to build the AWS API Gateway integration of an operation given
+// its HTTP method, its full path (including the base path) and the names of its path parameters.
+type Integration func(verb, path string, params []string) map[string]interface{}
+
+// HTTPProxyIntegration returns the integration that proxies requests to the given upstream URL.
+func HTTPProxyIntegration(upstreamURL string) Integration {
+	return func(verb, path string, params []string) map[string]interface{} {
+		integration := map[string]interface{}{
+			"type":                "http_proxy",
+			"httpMethod":          verb,
+			"uri":                 upstreamURL + path,
+			"passthroughBehavior": "when_no_match",
+		}
+		if len(params) > 0 {
+			mapping := make(map[string]string, len(params))
+			for _, p := range params {
+				mapping["integration.request.path."+p] = "method.request.path." + p
+			}
+			integration["requestParameters"] = mapping
+		}
+		return integration
+	}
+}
+
+// AWSSpec returns the Swagger specification of the API decorated with the AWS API Gateway
+// extensions. The integration of each operation is built with the given function.
+// See https://docs.aws.amazon.com/apigateway/latest/developerguide/api-gateway-swagger-extensions.html
+func AWSSpec(api *design.APIDefinition, integration Integration) (*genswagger.Swagger, error) {
+	s, err := genswagger.New(api)
+	if err != nil {
+		return nil, err
+	}
+	if cors := corsFromDefinitions(originsFromDefinition(api)); cors != nil {
+		config := map[string]interface{}{
+			"allowOrigins":     cors.Origins,
+			"allowCredentials": cors.Credentials,
+		}
+		if len(cors.Methods) > 0 {
+			config["allowMethods"] = cors.Methods
+		}
+		if len(cors.Headers) > 0 {
+			config["allowHeaders"] = cors.Headers
+		}
+		if len(cors.Exposed) > 0 {
+			config["exposeHeaders"] = cors.Exposed
+		}
+		if cors.MaxAge > 0 {
+			config["maxAge"] = cors.MaxAge
+		}
+		setExtension(&s.Extensions, "x-amazon-apigateway-cors", config)
+	}
+	basePath := s.BasePath
+	if basePath == "/" {
+		basePath = ""
+	}
+	for key, path := range s.Paths {
+		for verb, op := range operations(path) {
+			var params []string
+			for _, p := range op.Parameters {
+				if p.In == "path" {
+					params = append(params, p.Name)
+				}
+			}
+			setExtension(&op.Extensions, "x-amazon-apigateway-integration", integration(verb, basePath+key, params))
+		}
+	}
+	return s, nil
+}
+
+// generateAWS writes the Swagger specification of the API decorated with the AWS API Gateway
+// extensions that proxy each operation to the upstream service.
+func (g *Generator) generateAWS(specFile string) error {
+	s, err := AWSSpec(g.API, HTTPProxyIntegration(g.upstreamURL()))
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(specFile, b, 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, specFile)
+	return nil
+}
+
+// originsFromDefinition returns the CORS definitions of the API sorted by origin.
+func originsFromDefinition(api *design.APIDefinition) []*design.CORSDefinition {
+	names := make([]string, 0, len(api.Origins))
+	for n := range api.Origins {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	origins := make([]*design.CORSDefinition, len(names))
+	for i, n := range names {
+		origins[i] = api.Origins[n]
+	}
+	return origins
+}
+
+// operations returns the operations of the given path indexed by HTTP method.
+func operations(path *genswagger.Path) map[string]*genswagger.Operation {
+	ops := make(map[string]*genswagger.Operation)
+	for verb, op := range map[string]*genswagger.Operation{
+		"GET":     path.Get,
+		"PUT":     path.Put,
+		"POST":    path.Post,
+		"DELETE":  path.Delete,
+		"OPTIONS": path.Options,
+		"HEAD":    path.Head,
+		"PATCH":   path.Patch,
+	} {
+		if op != nil {
+			ops[verb] = op
+		}
+	}
+	return ops
+}
+
+// setExtension sets the given vendor extension unless it is already defined in the design.
+func setExtension(extensions *map[string]interface{}, name string, value interface{}) {
+	if *extensions == nil {
+		*extensions = make(map[string]interface{})
+	}
+	if _, ok := (*extensions)[name]; !ok {
+		(*extensions)[name] = value
+	}
+}
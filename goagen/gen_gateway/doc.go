@@ -0,0 +1,19 @@
+/*
+Package gengateway provides a goa generator for API gateway configurations.
+The generator derives the gateway configuration from the design so that the routes, security
+schemes, rate limits and CORS policies enforced by the gateway stay in sync with the API:
+
+  - gateway/kong.yaml is a Kong declarative configuration with one route per action endpoint.
+  - gateway/envoy.yaml is an Envoy route configuration that forwards requests to a cluster named
+    after the API.
+  - gateway/aws.json is the Swagger specification of the API decorated with the AWS API Gateway
+    extensions that proxy each operation to the upstream service.
+
+Rate limits are defined with the "gateway:rate-limit" metadata on the API, resources or actions,
+the most specific definition wins:
+
+	Metadata("gateway:rate-limit", "100/minute")
+
+Rate limits are not generated for AWS API Gateway which relies on usage plans instead.
+*/
+package gengateway
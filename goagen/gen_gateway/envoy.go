@@ -0,0 +1,138 @@
+package gengateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// EnvoyRouteConfig is the Envoy route configuration.
+	// See https://www.envoyproxy.io/docs/envoy/latest/api-v3/config/route/v3/route.proto
+	EnvoyRouteConfig struct {
+		Name         string              `yaml:"name"`
+		VirtualHosts []*EnvoyVirtualHost `yaml:"virtual_hosts"`
+	}
+
+	// EnvoyVirtualHost describes the virtual host serving the API.
+	EnvoyVirtualHost struct {
+		Name    string        `yaml:"name"`
+		Domains []string      `yaml:"domains"`
+		Routes  []*EnvoyRoute `yaml:"routes"`
+	}
+
+	// EnvoyRoute describes a route to the upstream cluster.
+	EnvoyRoute struct {
+		Name                 string                 `yaml:"name"`
+		Match                *EnvoyRouteMatch       `yaml:"match"`
+		Route                *EnvoyRouteAction      `yaml:"route"`
+		TypedPerFilterConfig map[string]interface{} `yaml:"typed_per_filter_config,omitempty"`
+	}
+
+	// EnvoyRouteMatch describes the requests matched by a route.
+	EnvoyRouteMatch struct {
+		Path      string                `yaml:"path,omitempty"`
+		SafeRegex *EnvoyRegexMatcher    `yaml:"safe_regex,omitempty"`
+		Headers   []*EnvoyHeaderMatcher `yaml:"headers"`
+	}
+
+	// EnvoyRegexMatcher describes a regular expression matcher.
+	EnvoyRegexMatcher struct {
+		Regex string `yaml:"regex"`
+	}
+
+	// EnvoyHeaderMatcher describes a request header matcher.
+	EnvoyHeaderMatcher struct {
+		Name        string            `yaml:"name"`
+		StringMatch map[string]string `yaml:"string_match"`
+	}
+
+	// EnvoyRouteAction describes the upstream cluster requests are routed to.
+	EnvoyRouteAction struct {
+		Cluster string `yaml:"cluster"`
+	}
+)
+
+// envoyConfig builds the Envoy route configuration for the given routes. The routes forward
+// requests to a cluster named after the API that must be defined in the Envoy bootstrap
+// configuration.
+func (g *Generator) envoyConfig(routes []*Route) *EnvoyRouteConfig {
+	name := codegen.SnakeCase(g.API.Name)
+	vh := &EnvoyVirtualHost{Name: name, Domains: []string{"*"}}
+	for _, r := range routes {
+		match := &EnvoyRouteMatch{
+			Headers: []*EnvoyHeaderMatcher{
+				{Name: ":method", StringMatch: map[string]string{"exact": r.Verb}},
+			},
+		}
+		if len(design.ExtractWildcards(r.Path)) == 0 {
+			match.Path = r.Path
+		} else {
+			match.SafeRegex = &EnvoyRegexMatcher{Regex: pathRegex(r.Path, "")}
+		}
+		er := &EnvoyRoute{
+			Name:  r.Name,
+			Match: match,
+			Route: &EnvoyRouteAction{Cluster: name},
+		}
+		config := make(map[string]interface{})
+		if r.Security != nil && r.Security.Kind == design.JWTSecurityKind {
+			config["envoy.filters.http.jwt_authn"] = map[string]interface{}{
+				"@type":            "type.googleapis.com/envoy.extensions.filters.http.jwt_authn.v3.PerRouteConfig",
+				"requirement_name": r.Security.SchemeName,
+			}
+		}
+		if r.RateLimit != nil {
+			fraction := map[string]interface{}{
+				"default_value": map[string]interface{}{"numerator": 100, "denominator": "HUNDRED"},
+				"runtime_key":   "local_rate_limit_enabled",
+			}
+			config["envoy.filters.http.local_ratelimit"] = map[string]interface{}{
+				"@type":       "type.googleapis.com/envoy.extensions.filters.http.local_ratelimit.v3.LocalRateLimit",
+				"stat_prefix": strings.Replace(r.Name, "-", "_", -1) + "_rate_limiter",
+				"token_bucket": map[string]interface{}{
+					"max_tokens":      r.RateLimit.Requests,
+					"tokens_per_fill": r.RateLimit.Requests,
+					"fill_interval":   fmt.Sprintf("%ds", r.RateLimit.Seconds()),
+				},
+				"filter_enabled":  fraction,
+				"filter_enforced": fraction,
+			}
+		}
+		if r.CORS != nil {
+			var origins []map[string]string
+			for _, o := range r.CORS.Origins {
+				if o == "*" {
+					origins = append(origins, map[string]string{"prefix": ""})
+				} else {
+					origins = append(origins, map[string]string{"exact": o})
+				}
+			}
+			cors := map[string]interface{}{
+				"@type":                     "type.googleapis.com/envoy.extensions.filters.http.cors.v3.CorsPolicy",
+				"allow_origin_string_match": origins,
+				"allow_credentials":         r.CORS.Credentials,
+			}
+			if len(r.CORS.Methods) > 0 {
+				cors["allow_methods"] = strings.Join(r.CORS.Methods, ",")
+			}
+			if len(r.CORS.Headers) > 0 {
+				cors["allow_headers"] = strings.Join(r.CORS.Headers, ",")
+			}
+			if len(r.CORS.Exposed) > 0 {
+				cors["expose_headers"] = strings.Join(r.CORS.Exposed, ",")
+			}
+			if r.CORS.MaxAge > 0 {
+				cors["max_age"] = fmt.Sprintf("%d", r.CORS.MaxAge)
+			}
+			config["envoy.filters.http.cors"] = cors
+		}
+		if len(config) > 0 {
+			er.TypedPerFilterConfig = config
+		}
+		vh.Routes = append(vh.Routes, er)
+	}
+	return &EnvoyRouteConfig{Name: name, VirtualHosts: []*EnvoyVirtualHost{vh}}
+}
@@ -0,0 +1,324 @@
+package gengateway
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the API gateway configuration generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Format   string                // Gateway configuration format: "kong", "envoy", "aws" or empty for all
+	Scheme   string                // Scheme used by the gateway to reach the upstream service
+	Host     string                // Host of the upstream service
+	genfiles []string              // Generated files
+}
+
+type (
+	// Route holds the gateway independent data needed to configure the routing of an action
+	// endpoint.
+	Route struct {
+		Name      string                           // Unique route name
+		Verb      string                           // HTTP method
+		Path      string                           // Full goa path, e.g. "/bottles/:id"
+		Security  *design.SecuritySchemeDefinition // Security scheme if any
+		Scopes    []string                         // Security scopes required by the action
+		RateLimit *RateLimit                       // Rate limit if any
+		CORS      *CORS                            // CORS policy if any
+	}
+
+	// RateLimit describes the maximum number of requests allowed per time period.
+	RateLimit struct {
+		Requests int    // Maximum number of requests
+		Period   string // One of "second", "minute", "hour" or "day"
+	}
+
+	// CORS is the CORS policy applied to a route. It merges all the CORS definitions that
+	// apply to the route resource as gateways only support one policy per route.
+	CORS struct {
+		Origins     []string // Allowed origins
+		Methods     []string // Allowed methods
+		Headers     []string // Allowed headers
+		Exposed     []string // Exposed headers
+		MaxAge      uint     // Preflight response cache duration in seconds
+		Credentials bool     // Whether credentials are allowed
+	}
+)
+
+// RateLimitMetadataKey is the metadata key used to define the rate limit of an API, resource or
+// action. The value has the form "<requests>/<period>" where period is one of "second", "minute",
+// "hour" or "day", e.g. "100/minute".
+const RateLimitMetadataKey = "gateway:rate-limit"
+
+// periodSeconds lists the durations of the supported rate limit periods.
+var periodSeconds = map[string]int{"second": 1, "minute": 60, "hour": 3600, "day": 86400}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, format, scheme, host, ver string
+	)
+
+	set := flag.NewFlagSet("gateway", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&format, "format", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Format: format, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the gateway configuration files.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Format != "" && g.Format != "kong" && g.Format != "envoy" && g.Format != "aws" {
+		return nil, fmt.Errorf(`invalid format "%s", must be "kong", "envoy" or "aws"`, g.Format)
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		return nil, fmt.Errorf("missing upstream host value, set it with --host")
+	}
+
+	routes, err := Routes(g.API)
+	if err != nil {
+		return
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "gateway")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	if g.Format == "" || g.Format == "kong" {
+		if err = g.writeYAML("kong.yaml", g.kongConfig(routes)); err != nil {
+			return
+		}
+	}
+	if g.Format == "" || g.Format == "envoy" {
+		if err = g.writeYAML("envoy.yaml", g.envoyConfig(routes)); err != nil {
+			return
+		}
+	}
+	if g.Format == "" || g.Format == "aws" {
+		if err = g.generateAWS(filepath.Join(g.OutDir, "aws.json")); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// writeYAML writes the YAML serialization of v to the file with the given name in the output
+// directory.
+func (g *Generator) writeYAML(name string, v interface{}) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(g.OutDir, name)
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, file)
+	return nil
+}
+
+// upstreamURL returns the URL of the upstream service.
+func (g *Generator) upstreamURL() string {
+	return g.Scheme + "://" + g.Host
+}
+
+// Routes computes the routes of all the API action endpoints.
+func Routes(api *design.APIDefinition) ([]*Route, error) {
+	var routes []*Route
+	apiLimit, err := RateLimitFromDefinition(api.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	err = api.IterateResources(func(res *design.ResourceDefinition) error {
+		resLimit, err := RateLimitFromDefinition(res.Metadata)
+		if err != nil {
+			return err
+		}
+		if resLimit == nil {
+			resLimit = apiLimit
+		}
+		cors := corsFromDefinitions(res.AllOrigins())
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			limit, err := RateLimitFromDefinition(action.Metadata)
+			if err != nil {
+				return err
+			}
+			if limit == nil {
+				limit = resLimit
+			}
+			for i, r := range action.Routes {
+				name := codegen.SnakeCase(res.Name) + "-" + codegen.SnakeCase(action.Name)
+				if i > 0 {
+					name = fmt.Sprintf("%s-%d", name, i)
+				}
+				route := &Route{
+					Name:      strings.Replace(name, "_", "-", -1),
+					Verb:      r.Verb,
+					Path:      r.FullPath(),
+					RateLimit: limit,
+					CORS:      cors,
+				}
+				if action.Security != nil && action.Security.Scheme.Kind != design.NoSecurityKind {
+					route.Security = action.Security.Scheme
+					route.Scopes = action.Security.Scopes
+				}
+				routes = append(routes, route)
+			}
+			return nil
+		})
+	})
+	return routes, err
+}
+
+// pathRegex returns a regular expression matching the given goa path. The wildcard captures are
+// named using the given group prefix ("?P" for RE2, "?" for PCRE) or anonymous if empty.
+func pathRegex(path, group string) string {
+	var (
+		regex string
+		last  int
+	)
+	for _, loc := range design.WildcardRegex.FindAllStringSubmatchIndex(path, -1) {
+		regex += quoteMeta(path[last:loc[0]])
+		pattern := "[^/]+"
+		if path[loc[0]+1] == '*' {
+			pattern = ".*"
+		}
+		if group != "" {
+			pattern = fmt.Sprintf("%s<%s>%s", group, path[loc[2]:loc[3]], pattern)
+		}
+		regex += "/(" + pattern + ")"
+		last = loc[1]
+	}
+	return "^" + regex + quoteMeta(path[last:]) + "$"
+}
+
+// quoteMeta escapes the regular expression meta characters that may appear in a path.
+func quoteMeta(s string) string {
+	return strings.NewReplacer(".", `\.`, "+", `\+`, "(", `\(`, ")", `\)`).Replace(s)
+}
+
+// RateLimitFromDefinition returns the rate limit defined in the given metadata if any.
+func RateLimitFromDefinition(mdata dslengine.MetadataDefinition) (*RateLimit, error) {
+	vals, ok := mdata[RateLimitMetadataKey]
+	if !ok || len(vals) == 0 {
+		return nil, nil
+	}
+	elems := strings.SplitN(vals[0], "/", 2)
+	if len(elems) != 2 {
+		return nil, fmt.Errorf(`invalid rate limit "%s", must be of the form "<requests>/<period>"`, vals[0])
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(elems[0]))
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf(`invalid rate limit "%s", number of requests must be a positive integer`, vals[0])
+	}
+	period := strings.TrimSpace(elems[1])
+	if _, ok := periodSeconds[period]; !ok {
+		return nil, fmt.Errorf(`invalid rate limit "%s", period must be one of "second", "minute", "hour" or "day"`, vals[0])
+	}
+	return &RateLimit{Requests: n, Period: period}, nil
+}
+
+// Seconds returns the duration of the rate limit period in seconds.
+func (l *RateLimit) Seconds() int {
+	return periodSeconds[l.Period]
+}
+
+// corsFromDefinitions merges the given CORS definitions into a single policy, nil if there are
+// none.
+func corsFromDefinitions(defs []*design.CORSDefinition) *CORS {
+	if len(defs) == 0 {
+		return nil
+	}
+	var origins, methods, headers, exposed []string
+	cors := &CORS{}
+	for _, def := range defs {
+		origins = append(origins, def.Origin)
+		methods = append(methods, def.Methods...)
+		headers = append(headers, def.Headers...)
+		exposed = append(exposed, def.Exposed...)
+		if def.MaxAge > cors.MaxAge {
+			cors.MaxAge = def.MaxAge
+		}
+		cors.Credentials = cors.Credentials || def.Credentials
+	}
+	cors.Origins = uniq(origins)
+	cors.Methods = uniq(methods)
+	cors.Headers = uniq(headers)
+	cors.Exposed = uniq(exposed)
+	return cors
+}
+
+// uniq returns the sorted distinct elements of the given slice.
+func uniq(vals []string) []string {
+	if len(vals) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(vals))
+	var res []string
+	for _, v := range vals {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
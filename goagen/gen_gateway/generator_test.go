@@ -0,0 +1,140 @@
+package gengateway_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_gateway"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_gateway/test_"
+
+	var outDir string
+	var format string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		format = ""
+	})
+
+	JustBeforeEach(func() {
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--host=backend:8080",
+			"--format=" + format, "--version=" + version.String()}
+		files, genErr = gengateway.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a secured and rate limited action", func() {
+		BeforeEach(func() {
+			scheme := &design.SecuritySchemeDefinition{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+			}
+			action := &design.ActionDefinition{
+				Name: "show",
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"id": &design.AttributeDefinition{Type: design.Integer},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"NoContent": {Name: "NoContent", Status: 204},
+				},
+				Security: &design.SecurityDefinition{Scheme: scheme},
+				Metadata: dslengine.MetadataDefinition{"gateway:rate-limit": {"10/second"}},
+			}
+			route := &design.RouteDefinition{
+				Verb:   "GET",
+				Path:   "/:id",
+				Parent: action,
+			}
+			action.Routes = []*design.RouteDefinition{route}
+			res := &design.ResourceDefinition{
+				Name:     "bottle",
+				BasePath: "/bottles",
+				Actions:  map[string]*design.ActionDefinition{"show": action},
+			}
+			action.Parent = res
+			design.Design = &design.APIDefinition{
+				Name:      "cellar",
+				Resources: map[string]*design.ResourceDefinition{"bottle": res},
+				Metadata:  dslengine.MetadataDefinition{"gateway:rate-limit": {"100/minute"}},
+				Origins: map[string]*design.CORSDefinition{
+					"http://example.com": {Origin: "http://example.com", Methods: []string{"GET"}, MaxAge: 600},
+				},
+			}
+		})
+
+		It("generates all the gateway configurations", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(4))
+		})
+
+		It("generates the Kong configuration", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "gateway", "kong.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("url: http://backend:8080"))
+			Ω(string(content)).Should(ContainSubstring("name: bottle-show"))
+			Ω(string(content)).Should(ContainSubstring(`~^/bottles/(?<id>[^/]+)$`))
+			Ω(string(content)).Should(ContainSubstring("- name: jwt"))
+			Ω(string(content)).Should(ContainSubstring("second: 10"))
+			Ω(string(content)).Should(ContainSubstring("- http://example.com"))
+		})
+
+		It("generates the Envoy route configuration", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "gateway", "envoy.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("cluster: cellar"))
+			Ω(string(content)).Should(ContainSubstring(`regex: ^/bottles/([^/]+)$`))
+			Ω(string(content)).Should(ContainSubstring("requirement_name: jwt"))
+			Ω(string(content)).Should(ContainSubstring("fill_interval: 1s"))
+			Ω(string(content)).Should(ContainSubstring("exact: http://example.com"))
+		})
+
+		It("generates the AWS API Gateway specification", func() {
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "gateway", "aws.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"x-amazon-apigateway-integration"`))
+			Ω(string(content)).Should(ContainSubstring(`"uri": "http://backend:8080/bottles/{id}"`))
+			Ω(string(content)).Should(ContainSubstring(`"integration.request.path.id": "method.request.path.id"`))
+			Ω(string(content)).Should(ContainSubstring(`"x-amazon-apigateway-cors"`))
+		})
+
+		Context("with an explicit format", func() {
+			BeforeEach(func() {
+				format = "kong"
+			})
+
+			It("only generates the corresponding configuration", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(2))
+				Ω(files[1]).Should(HaveSuffix("kong.yaml"))
+			})
+		})
+
+		Context("with an invalid rate limit", func() {
+			BeforeEach(func() {
+				design.Design.Metadata["gateway:rate-limit"] = []string{"lots"}
+			})
+
+			It("fails", func() {
+				Ω(genErr).Should(HaveOccurred())
+			})
+		})
+	})
+})
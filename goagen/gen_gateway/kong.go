@@ -0,0 +1,116 @@
+package gengateway
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// KongConfig is the Kong declarative configuration.
+	// See https://docs.konghq.com/gateway/latest/production/deployment-topologies/db-less-and-declarative-config/
+	KongConfig struct {
+		FormatVersion string         `yaml:"_format_version"`
+		Services      []*KongService `yaml:"services"`
+	}
+
+	// KongService describes the upstream service.
+	KongService struct {
+		Name   string       `yaml:"name"`
+		URL    string       `yaml:"url"`
+		Routes []*KongRoute `yaml:"routes"`
+	}
+
+	// KongRoute describes a route to the upstream service.
+	KongRoute struct {
+		Name      string        `yaml:"name"`
+		Methods   []string      `yaml:"methods"`
+		Paths     []string      `yaml:"paths"`
+		StripPath bool          `yaml:"strip_path"`
+		Plugins   []*KongPlugin `yaml:"plugins,omitempty"`
+	}
+
+	// KongPlugin describes a plugin applied to a route.
+	KongPlugin struct {
+		Name   string                 `yaml:"name"`
+		Config map[string]interface{} `yaml:"config,omitempty"`
+	}
+)
+
+// kongConfig builds the Kong declarative configuration for the given routes.
+func (g *Generator) kongConfig(routes []*Route) *KongConfig {
+	svc := &KongService{
+		Name: codegen.SnakeCase(g.API.Name),
+		URL:  g.upstreamURL(),
+	}
+	for _, r := range routes {
+		kr := &KongRoute{
+			Name:    r.Name,
+			Methods: []string{r.Verb},
+			Paths:   []string{"~" + pathRegex(r.Path, "?")},
+		}
+		if r.Security != nil {
+			kr.Plugins = append(kr.Plugins, kongAuthPlugin(r.Security, r.Scopes))
+		}
+		if r.RateLimit != nil {
+			kr.Plugins = append(kr.Plugins, &KongPlugin{
+				Name: "rate-limiting",
+				Config: map[string]interface{}{
+					r.RateLimit.Period: r.RateLimit.Requests,
+					"policy":           "local",
+				},
+			})
+		}
+		if r.CORS != nil {
+			config := map[string]interface{}{
+				"origins":     r.CORS.Origins,
+				"credentials": r.CORS.Credentials,
+			}
+			if len(r.CORS.Methods) > 0 {
+				config["methods"] = r.CORS.Methods
+			}
+			if len(r.CORS.Headers) > 0 {
+				config["headers"] = r.CORS.Headers
+			}
+			if len(r.CORS.Exposed) > 0 {
+				config["exposed_headers"] = r.CORS.Exposed
+			}
+			if r.CORS.MaxAge > 0 {
+				config["max_age"] = r.CORS.MaxAge
+			}
+			kr.Plugins = append(kr.Plugins, &KongPlugin{Name: "cors", Config: config})
+		}
+		svc.Routes = append(svc.Routes, kr)
+	}
+	return &KongConfig{FormatVersion: "3.0", Services: []*KongService{svc}}
+}
+
+// kongAuthPlugin returns the Kong plugin that implements the given security scheme.
+func kongAuthPlugin(scheme *design.SecuritySchemeDefinition, scopes []string) *KongPlugin {
+	switch scheme.Kind {
+	case design.BasicAuthSecurityKind:
+		return &KongPlugin{Name: "basic-auth"}
+	case design.APIKeySecurityKind:
+		return &KongPlugin{
+			Name: "key-auth",
+			Config: map[string]interface{}{
+				"key_names":     []string{scheme.Name},
+				"key_in_header": scheme.In == "header",
+				"key_in_query":  scheme.In == "query",
+			},
+		}
+	case design.OAuth2SecurityKind:
+		config := map[string]interface{}{
+			"enable_authorization_code": scheme.Flow == "accessCode",
+			"enable_implicit_grant":     scheme.Flow == "implicit",
+			"enable_password_grant":     scheme.Flow == "password",
+			"enable_client_credentials": scheme.Flow == "application",
+		}
+		if len(scopes) > 0 {
+			config["scopes"] = scopes
+			config["mandatory_scope"] = true
+		}
+		return &KongPlugin{Name: "oauth2", Config: config}
+	default:
+		return &KongPlugin{Name: "jwt"}
+	}
+}
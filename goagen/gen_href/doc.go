@@ -0,0 +1,17 @@
+/*
+Package genhref provides a goa generator for reverse routing. The generated "href" package defines
+one function per resource canonical route that builds the resource href from the route parameters
+so that services never hardcode paths that may drift from the design:
+
+	href.BottleHref(accountID, bottleID) // "/accounts/1/bottles/2"
+
+The package also exposes the canonical path templates and an Expand function that builds URLs
+from any path template, path parameters and query string:
+
+	href.Expand(href.BottlePath, href.Params{"accountID": "1", "bottleID": "2"}, url.Values{"view": {"tiny"}})
+
+Generate the package with:
+
+	goagen href -d github.com/example/cellar/design
+*/
+package genhref
@@ -0,0 +1,13 @@
+package genhref_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenHref(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenHref Suite")
+}
@@ -0,0 +1,169 @@
+package genhref
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the href package generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	genfiles []string              // Generated files
+}
+
+type (
+	// HrefData holds the data needed to generate the href function of a resource canonical
+	// route.
+	HrefData struct {
+		Resource string   // Name of resource
+		Name     string   // Go name of resource, e.g. "Bottle"
+		Path     string   // Canonical path template, e.g. "/accounts/:accountID/bottles/:bottleID"
+		Params   []string // Names of route wildcards in order
+		Args     []string // Go names of function arguments in order
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("href", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the href package.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	g.OutDir = filepath.Join(g.OutDir, "href")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	hrefFile := filepath.Join(g.OutDir, "href.go")
+	file, err := codegen.SourceFileFor(hrefFile)
+	if err != nil {
+		return
+	}
+	title := fmt.Sprintf("%s: Reverse Routing", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
+	}
+	g.genfiles = append(g.genfiles, hrefFile)
+	if err = file.WriteHeader(title, "href", imports); err != nil {
+		return
+	}
+	if err = file.ExecuteTemplate("href", hrefT, nil, Hrefs(g.API)); err != nil {
+		return
+	}
+	if err = file.FormatCode(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// Hrefs returns the data needed to generate the href functions of the API resources that have a
+// canonical route sorted by resource name.
+func Hrefs(api *design.APIDefinition) []*HrefData {
+	var hrefs []*HrefData
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		ca := r.CanonicalAction()
+		if ca == nil || len(ca.Routes) == 0 {
+			return nil
+		}
+		route := ca.Routes[0]
+		data := &HrefData{
+			Resource: r.Name,
+			Name:     codegen.Goify(r.Name, true),
+			Path:     route.FullPath(),
+			Params:   route.Params(),
+		}
+		for _, p := range data.Params {
+			data.Args = append(data.Args, codegen.Goify(p, false))
+		}
+		hrefs = append(hrefs, data)
+		return nil
+	})
+	return hrefs
+}
+
+const (
+	// hrefT generates the href package.
+	// template input: []*HrefData
+	hrefT = `// Params holds the values of the path template wildcards indexed by wildcard name.
+type Params map[string]string
+{{ if . }}
+const (
+{{ range . }}	// {{ .Name }}Path is the canonical path template of the {{ printf "%q" .Resource }} resource.
+	{{ .Name }}Path = {{ printf "%q" .Path }}
+{{ end }})
+{{ end }}{{ range . }}{{ $args := .Args }}
+// {{ .Name }}Href returns the canonical href of the {{ printf "%q" .Resource }} resource.
+func {{ .Name }}Href({{ if .Args }}{{ join .Args ", " }} string{{ end }}) string {
+	return Expand({{ .Name }}Path, {{ if .Params }}Params{ {{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ printf "%q" $p }}: {{ index $args $i }}{{ end }} }{{ else }}nil{{ end }}, nil)
+}
+{{ end }}
+// Expand builds a URL from the path template replacing the ":name" and "*name" wildcards with the
+// escaped values of the corresponding params and appending the query string if any.
+func Expand(template string, params Params, query url.Values) string {
+	segments := strings.Split(template, "/")
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"):
+			segments[i] = url.PathEscape(params[s[1:]])
+		case strings.HasPrefix(s, "*"):
+			parts := strings.Split(params[s[1:]], "/")
+			for j, p := range parts {
+				parts[j] = url.PathEscape(p)
+			}
+			segments[i] = strings.Join(parts, "/")
+		}
+	}
+	href := strings.Join(segments, "/")
+	if len(query) > 0 {
+		href += "?" + query.Encode()
+	}
+	return href
+}
+`
+)
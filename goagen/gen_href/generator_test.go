@@ -0,0 +1,90 @@
+package genhref_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_href"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_href/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String()}
+		dslengine.Reset()
+		API("test", func() {})
+		Resource("account", func() {
+			BasePath("/accounts")
+			Action("show", func() { Routing(GET("/:accountID")) })
+		})
+		Resource("bottle", func() {
+			Parent("account")
+			BasePath("/bottles")
+			Action("show", func() { Routing(GET("/:bottleID")) })
+		})
+		Resource("health", func() {
+			Action("check", func() { Routing(GET("/health")) })
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genhref.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("generates the href functions of the canonical routes", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(files).Should(HaveLen(2))
+
+		content, err := ioutil.ReadFile(filepath.Join(outDir, "href", "href.go"))
+		Ω(err).ShouldNot(HaveOccurred())
+		code := string(content)
+		Ω(code).Should(ContainSubstring("package href"))
+		Ω(code).Should(ContainSubstring(`BottlePath = "/accounts/:accountID/bottles/:bottleID"`))
+		Ω(code).Should(ContainSubstring("func BottleHref(accountID, bottleID string) string {"))
+		Ω(code).Should(ContainSubstring(`return Expand(BottlePath, Params{"accountID": accountID, "bottleID": bottleID}, nil)`))
+		Ω(code).Should(ContainSubstring("func AccountHref(accountID string) string {"))
+		Ω(code).ShouldNot(ContainSubstring("HealthHref"))
+		Ω(code).Should(ContainSubstring("func Expand(template string, params Params, query url.Values) string {"))
+	})
+})
+
+var _ = Describe("Hrefs", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			CanonicalActionName("get")
+			Action("get", func() { Routing(GET("/:id")) })
+		})
+		dslengine.Run()
+	})
+
+	It("uses the canonical action", func() {
+		hrefs := genhref.Hrefs(design.Design)
+		Ω(hrefs).Should(HaveLen(1))
+		Ω(hrefs[0].Path).Should(Equal("/bottles/:id"))
+		Ω(hrefs[0].Args).Should(Equal([]string{"id"}))
+	})
+})
@@ -0,0 +1,16 @@
+/*
+Package genjava provides a goa generator for JVM client projects.
+The generator produces a Gradle project with one immutable POJO class per user type and media type
+view, one enum per enumerated attribute, one Retrofit interface per resource and a client class
+that creates the interfaces:
+
+	CellarClient client = CellarClient.builder().credential("jwt", token).build();
+	Bottle bottle = client.bottle().show(42L).execute().body();
+
+The POJO classes are built with builders whose build method checks the required fields and are
+serialized with Jackson. Required fields are annotated with the JSR-305 Nonnull annotation and
+optional fields with Nullable so that Kotlin code using the client gets null-safe types.
+
+The project is generated in the "java" directory of the output directory.
+*/
+package genjava
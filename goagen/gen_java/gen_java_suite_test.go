@@ -0,0 +1,13 @@
+package genjava_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenJava(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenJava Suite")
+}
@@ -0,0 +1,718 @@
+package genjava
+
+import (
+	"flag"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf16"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the JVM client generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Package  string                // Java package of the generated classes
+	Scheme   string                // Scheme used to make requests
+	Host     string                // Host addressed by requests
+	genfiles []string              // Generated files
+}
+
+type (
+	// Model holds the data needed to generate a POJO class.
+	Model struct {
+		Name        string   // Java class name
+		Description string   // Class javadoc
+		Fields      []*Field // Class fields, required fields first
+	}
+
+	// Field holds the data needed to generate a POJO field, its getter and its builder method.
+	Field struct {
+		Name        string // Java field name
+		Attribute   string // Attribute name
+		Key         string // JSON key as a Java string literal
+		Type        string // Java type
+		Getter      string // Getter method name
+		Required    bool   // Whether the field is required
+		Description string // Getter javadoc
+	}
+
+	// Enum holds the data needed to generate an enum class.
+	Enum struct {
+		Name    string        // Java enum name
+		Type    string        // Java type of the values
+		Members []*EnumMember // Enum constants
+	}
+
+	// EnumMember is a constant of an enum class.
+	EnumMember struct {
+		Name  string // Java constant name
+		Value string // Java literal value
+	}
+
+	// Service holds the data needed to generate the Retrofit interface of a resource.
+	Service struct {
+		Name        string    // Java interface name
+		Accessor    string    // Name of the client method returning the interface
+		Description string    // Interface javadoc
+		Methods     []*Method // Interface methods, one per action
+	}
+
+	// Method holds the data needed to generate the Retrofit method of an action.
+	Method struct {
+		Name        string   // Java method name
+		Description string   // Method javadoc
+		Verb        string   // HTTP method
+		Path        string   // Design request path
+		URL         string   // Retrofit relative URL as a Java string literal
+		HasBody     bool     // Whether the request has a body
+		Scheme      string   // Security scheme name if any
+		Params      []string // Method parameter declarations
+		Result      string   // Java type of the response body
+	}
+
+	// Scheme holds the data needed to send the credentials of a security scheme.
+	Scheme struct {
+		Name   string // Security scheme name as a Java string literal
+		Header string // Header or query string parameter name as a Java string literal
+		Prefix string // Credential prefix
+		Query  bool   // Whether the credential is sent in the query string
+		Basic  bool   // Whether the credential is base64 encoded for basic auth
+	}
+
+	// javaFile describes a generated file.
+	javaFile struct {
+		path, name, tmpl string
+		data             map[string]interface{}
+	}
+
+	// builder computes the models and enums used by the generated code.
+	builder struct {
+		api    *design.APIDefinition
+		models []*Model
+		enums  []*Enum
+		types  map[string]string
+		err    error
+	}
+)
+
+var (
+	// identRegex matches the characters that may not appear in Java identifiers.
+	identRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	// packageRegex matches valid Java package names.
+	packageRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)*$`)
+)
+
+// javaKeywords lists the Java reserved words and literals.
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true, "case": true,
+	"catch": true, "char": true, "class": true, "const": true, "continue": true, "default": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true, "final": true,
+	"finally": true, "float": true, "for": true, "goto": true, "if": true, "implements": true,
+	"import": true, "instanceof": true, "int": true, "interface": true, "long": true,
+	"native": true, "new": true, "package": true, "private": true, "protected": true,
+	"public": true, "return": true, "short": true, "static": true, "strictfp": true,
+	"super": true, "switch": true, "synchronized": true, "this": true, "throw": true,
+	"throws": true, "transient": true, "try": true, "void": true, "volatile": true,
+	"while": true, "true": true, "false": true, "null": true, "var": true, "record": true,
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, pkg, scheme, host, ver string
+	)
+
+	set := flag.NewFlagSet("java", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&pkg, "pkg", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Package: pkg, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the JVM client project.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Package == "" {
+		g.Package = strings.ToLower(identRegex.ReplaceAllString(g.API.Name, "")) + ".client"
+	}
+	if !packageRegex.MatchString(g.Package) {
+		return nil, fmt.Errorf("invalid Java package name %#v", g.Package)
+	}
+	for _, seg := range strings.Split(g.Package, ".") {
+		if javaKeywords[seg] {
+			return nil, fmt.Errorf("invalid Java package name %#v: %#v is a reserved word", g.Package, seg)
+		}
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost:8080"
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "java")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	srcDir := filepath.Join(append([]string{g.OutDir, "src", "main", "java"}, strings.Split(g.Package, ".")...)...)
+	modelDir := filepath.Join(srcDir, "model")
+	if err = os.MkdirAll(modelDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	b := &builder{api: g.API, types: make(map[string]string)}
+	services := b.services()
+	if b.err != nil {
+		return nil, b.err
+	}
+	clientName := codegen.Goify(g.API.Name, true) + "Client"
+	data := map[string]interface{}{
+		"API":        g.API,
+		"Package":    g.Package,
+		"Artifact":   strings.Trim(strings.ToLower(identRegex.ReplaceAllString(g.API.Name, "-")), "-") + "-client",
+		"ClientName": clientName,
+		"HasModels":  len(b.models)+len(b.enums) > 0,
+		"BaseURL":    javaString(g.Scheme + "://" + g.Host + "/"),
+		"Services":   services,
+		"Schemes":    schemes(g.API),
+	}
+	files := []*javaFile{
+		{filepath.Join(g.OutDir, "settings.gradle"), "settings", settingsT, data},
+		{filepath.Join(g.OutDir, "build.gradle"), "build", buildT, data},
+		{filepath.Join(srcDir, clientName+".java"), "client", clientT, data},
+	}
+	for _, s := range services {
+		files = append(files, &javaFile{filepath.Join(srcDir, s.Name+".java"), "service", serviceT, withData(data, "Service", s)})
+	}
+	for _, e := range b.enums {
+		files = append(files, &javaFile{filepath.Join(modelDir, e.Name+".java"), "enum", enumT, withData(data, "Enum", e)})
+	}
+	for _, m := range b.models {
+		files = append(files, &javaFile{filepath.Join(modelDir, m.Name+".java"), "model", modelT, withData(data, "Model", m)})
+	}
+	for _, f := range files {
+		if err = g.generateFile(f.path, f.name, f.tmpl, f.data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+func (g *Generator) generateFile(path, name, tmpl string, data map[string]interface{}) error {
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	funcs := template.FuncMap{
+		"join":         strings.Join,
+		"javadoc":      javadoc,
+		"javaString":   javaString,
+		"groovyString": groovyString,
+	}
+	return file.ExecuteTemplate(name, tmpl, funcs, data)
+}
+
+// withData returns a copy of data with the given key set to the given value.
+func withData(data map[string]interface{}, key string, val interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		res[k] = v
+	}
+	res[key] = val
+	return res
+}
+
+// schemes computes the data needed to send the credentials of the API security schemes.
+func schemes(api *design.APIDefinition) []*Scheme {
+	var res []*Scheme
+	for _, s := range api.SecuritySchemes {
+		sc := &Scheme{Name: javaString(s.SchemeName), Header: javaString("Authorization"), Prefix: "Bearer "}
+		switch s.Kind {
+		case design.BasicAuthSecurityKind:
+			sc.Prefix, sc.Basic = "Basic ", true
+		case design.APIKeySecurityKind:
+			sc.Header, sc.Prefix, sc.Query = javaString(s.Name), "", s.In == "query"
+		case design.SessionSecurityKind:
+			sc.Header, sc.Prefix = javaString("Cookie"), s.CookieName+"="
+		}
+		res = append(res, sc)
+	}
+	return res
+}
+
+// services computes the data needed to generate the Retrofit interfaces, it registers the models
+// and enums used by the interfaces.
+func (b *builder) services() []*Service {
+	var services []*Service
+	b.api.IterateResources(func(res *design.ResourceDefinition) error {
+		name := codegen.Goify(res.Name, true)
+		s := &Service{
+			Name:        name + "Api",
+			Accessor:    javaName(codegen.CamelIdentifier(res.Name, false)),
+			Description: res.Description,
+		}
+		if s.Description == "" {
+			s.Description = fmt.Sprintf("%s exposes the actions of the %s resource.", s.Name, res.Name)
+		}
+		res.IterateActions(func(action *design.ActionDefinition) error {
+			if len(action.Routes) == 0 || action.WebSocket() {
+				return nil
+			}
+			s.Methods = append(s.Methods, b.method(res, action))
+			return nil
+		})
+		if len(s.Methods) > 0 {
+			services = append(services, s)
+		}
+		return nil
+	})
+	// Generate the user types and the views of the media types not used by any action as well.
+	for _, n := range codegen.SortedUserTypeNames(b.api.Types) {
+		b.resolve(&design.AttributeDefinition{Type: b.api.Types[n]}, "")
+	}
+	for _, n := range codegen.SortedMediaTypeNames(b.api.MediaTypes) {
+		mt := b.api.MediaTypes[n]
+		if mt.Identifier == design.ErrorMediaIdentifier {
+			continue
+		}
+		if len(mt.Views) == 0 {
+			b.resolve(&design.AttributeDefinition{Type: mt}, "")
+			continue
+		}
+		mt.IterateViews(func(v *design.ViewDefinition) error {
+			b.view(mt, v.Name)
+			return nil
+		})
+	}
+	sort.Slice(b.models, func(i, j int) bool { return b.models[i].Name < b.models[j].Name })
+	sort.Slice(b.enums, func(i, j int) bool { return b.enums[i].Name < b.enums[j].Name })
+	return services
+}
+
+// method computes the data needed to generate the Retrofit method of the given action.
+func (b *builder) method(res *design.ResourceDefinition, action *design.ActionDefinition) *Method {
+	prefix := codegen.Goify(action.Name, true) + codegen.Goify(res.Name, true)
+	route := action.Routes[0]
+	m := &Method{
+		Name:        javaName(codegen.CamelIdentifier(action.Name, false)),
+		Description: action.Description,
+		Verb:        route.Verb,
+		Path:        route.FullPath(),
+		HasBody:     action.Payload != nil,
+		Result:      "Void",
+	}
+	if m.Description == "" {
+		m.Description = fmt.Sprintf("%s calls the %s action of the %s resource.", m.Name, action.Name, res.Name)
+	}
+	wildcards := make(map[string]bool)
+	m.URL = javaString(design.WildcardRegex.ReplaceAllStringFunc(m.Path, func(wc string) string {
+		if wc[1] == '*' {
+			wildcards[wc[2:]] = true
+		}
+		return "/{" + wc[2:] + "}"
+	}))
+
+	var required, optional []string
+	params := action.AllParams()
+	pathParams := make(map[string]bool)
+	for _, p := range route.Params() {
+		pathParams[p] = true
+	}
+	param := func(annotation, n string, att *design.AttributeDefinition, req bool, class string) string {
+		typ := b.resolve(att, class)
+		null := "@Nullable"
+		if req {
+			null = "@Nonnull"
+		}
+		return fmt.Sprintf("%s %s %s %s", annotation, null, typ, javaName(codegen.CamelIdentifier(n, false)))
+	}
+	var positional []string
+	var o design.Object
+	if params != nil {
+		o = params.Type.ToObject()
+	}
+	for _, n := range route.Params() {
+		annotation := fmt.Sprintf("@Path(%s)", javaString(n))
+		if wildcards[n] {
+			annotation = fmt.Sprintf("@Path(value = %s, encoded = true)", javaString(n))
+		}
+		att, ok := o[n]
+		if !ok {
+			att = &design.AttributeDefinition{Type: design.String}
+		}
+		positional = append(positional, param(annotation, n, att, true, prefix+codegen.Goify(n, true)))
+	}
+	for _, n := range codegen.SortedAttributeNames(o) {
+		if pathParams[n] {
+			continue
+		}
+		req := params.IsRequired(n)
+		if o[n].Type.IsObject() {
+			// Object params are sent as one query string param per field, e.g. filter.status.
+			att := o[n].Type.(*design.UserTypeDefinition).AttributeDefinition
+			fields := att.Type.ToObject()
+			for _, f := range codegen.SortedAttributeNames(fields) {
+				wire := n + "." + f
+				freq := req && att.IsRequired(f)
+				p := param(fmt.Sprintf("@Query(%s)", javaString(wire)), wire, fields[f], freq, prefix+codegen.Goify(n, true)+codegen.Goify(f, true))
+				if freq {
+					required = append(required, p)
+				} else {
+					optional = append(optional, p)
+				}
+			}
+			continue
+		}
+		p := param(fmt.Sprintf("@Query(%s)", javaString(n)), n, o[n], req, prefix+codegen.Goify(n, true))
+		if req {
+			required = append(required, p)
+		} else {
+			optional = append(optional, p)
+		}
+	}
+	if action.Headers != nil {
+		h := action.Headers.Type.ToObject()
+		for _, n := range codegen.SortedAttributeNames(h) {
+			req := action.Headers.IsRequired(n)
+			p := param(fmt.Sprintf("@Header(%s)", javaString(n)), n, h[n], req, prefix+codegen.Goify(n, true))
+			if req {
+				required = append(required, p)
+			} else {
+				optional = append(optional, p)
+			}
+		}
+	}
+	if p := action.Payload; p != nil {
+		typ := b.resolve(&design.AttributeDefinition{Type: p}, prefix+"Payload")
+		null := "@Nonnull"
+		if action.PayloadOptional {
+			null = "@Nullable"
+		}
+		positional = append(positional, fmt.Sprintf("@Body %s %s payload", null, typ))
+	}
+	m.Params = append(append(positional, required...), optional...)
+
+	if sec := action.Security; sec != nil {
+		m.Scheme = sec.Scheme.SchemeName
+	}
+
+	var statuses []int
+	for _, r := range action.Responses {
+		if r.Status >= 200 && r.Status < 300 {
+			statuses = append(statuses, r.Status)
+		}
+	}
+	sort.Ints(statuses)
+	for _, s := range statuses {
+		for _, r := range action.Responses {
+			if r.Status != s || r.MediaType == "" {
+				continue
+			}
+			mt, ok := b.api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]
+			if !ok || mt.Identifier == design.ErrorMediaIdentifier {
+				continue
+			}
+			view := r.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			m.Result = b.view(mt, view)
+			return m
+		}
+	}
+	return m
+}
+
+// view returns the Java type of the given view of the given media type, it registers the
+// classes needed to represent the projected media type.
+func (b *builder) view(mt *design.MediaTypeDefinition, view string) string {
+	if _, ok := mt.Views[view]; !ok || projected(mt) {
+		return b.named(mt.UserTypeDefinition)
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("%s view %#v: %s", mt.TypeName, view, err)
+		}
+		return "Object"
+	}
+	return b.named(p.UserTypeDefinition)
+}
+
+// resolve computes the Java type of the given attribute. class is the name of the enum or POJO
+// class generated for the attribute if it defines an enum or an inline object. It registers the
+// classes needed to represent the type.
+func (b *builder) resolve(att *design.AttributeDefinition, class string) string {
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		return b.view(actual, design.DefaultView)
+	case *design.UserTypeDefinition:
+		return b.named(actual)
+	case *design.ScalarDefinition:
+		wire := &design.AttributeDefinition{Type: actual.Primitive, Validation: att.Validation}
+		if len(actual.Values) == 0 {
+			return b.resolve(wire, class)
+		}
+		name := codegen.Goify(actual.TypeName, true)
+		b.enum(name, b.resolve(wire, ""), actual.Values)
+		return name
+	case *design.FlagsDefinition:
+		name := codegen.Goify(actual.TypeName, true)
+		values := make([]interface{}, len(actual.Names))
+		for i, n := range actual.Names {
+			values[i] = n
+		}
+		b.enum(name, "String", values)
+		return "List<" + name + ">"
+	case *design.Array:
+		return "List<" + b.resolve(actual.ElemType, class) + ">"
+	case *design.Hash:
+		return "Map<" + b.resolve(actual.KeyType, class+"Key") + ", " + b.resolve(actual.ElemType, class) + ">"
+	case design.Object:
+		b.model(class, att.Description, att)
+		return class
+	case design.Primitive:
+		var typ string
+		switch actual.Kind() {
+		case design.BooleanKind:
+			typ = "Boolean"
+		case design.IntegerKind:
+			typ = "Long"
+		case design.NumberKind:
+			typ = "Double"
+		case design.StringKind:
+			typ = "String"
+		case design.DateTimeKind:
+			typ = "OffsetDateTime"
+		case design.UUIDKind:
+			typ = "UUID"
+		default:
+			typ = "Object"
+		}
+		if v := att.Validation; v != nil && len(v.Values) > 0 && class != "" {
+			switch actual.Kind() {
+			case design.IntegerKind, design.NumberKind, design.StringKind:
+				b.enum(class, typ, v.Values)
+				return class
+			}
+		}
+		return typ
+	}
+	return "Object"
+}
+
+// named returns the Java type of the given user type, it registers the corresponding POJO class
+// if the type is an object.
+func (b *builder) named(ut *design.UserTypeDefinition) string {
+	class := codegen.Goify(ut.TypeName, true)
+	if t, ok := b.types[class]; ok {
+		return t
+	}
+	if !ut.Type.IsObject() {
+		b.types[class] = "Object" // guard against recursive types
+		t := b.resolve(ut.AttributeDefinition, class)
+		b.types[class] = t
+		return t
+	}
+	b.types[class] = class
+	b.model(class, ut.Description, ut.AttributeDefinition)
+	return class
+}
+
+// model registers the POJO class with the given name describing the given object attribute.
+func (b *builder) model(name, description string, att *design.AttributeDefinition) {
+	for _, m := range b.models {
+		if m.Name == name {
+			return
+		}
+	}
+	if description == "" {
+		description = name + " is a data structure of the " + b.api.Name + " API."
+	}
+	m := &Model{Name: name, Description: description}
+	b.models = append(b.models, m)
+	o := att.Type.ToObject()
+	var required, optional []*Field
+	for _, n := range codegen.SortedAttributeNames(o) {
+		f := &Field{
+			Name:        javaName(codegen.CamelIdentifier(n, false)),
+			Attribute:   n,
+			Key:         javaString(n),
+			Type:        b.resolve(o[n], name+codegen.Goify(n, true)),
+			Getter:      getter(n),
+			Required:    att.IsRequired(n),
+			Description: o[n].Description,
+		}
+		if f.Description == "" {
+			f.Description = fmt.Sprintf("%s returns the value of the %s field.", f.Getter, f.Name)
+		}
+		if f.Required {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+	m.Fields = append(required, optional...)
+}
+
+// enum registers the enum class with the given name, value type and values.
+func (b *builder) enum(name, typ string, values []interface{}) {
+	for _, e := range b.enums {
+		if e.Name == name {
+			return
+		}
+	}
+	e := &Enum{Name: name, Type: typ}
+	seen := make(map[string]bool)
+	for _, v := range values {
+		var member string
+		if s, ok := v.(string); ok {
+			member = strings.ToUpper(codegen.SnakeIdentifier(s))
+		} else {
+			member = "VALUE_" + strings.ToUpper(codegen.SnakeIdentifier(fmt.Sprintf("%v", v)))
+		}
+		if member == "" || member[0] >= '0' && member[0] <= '9' {
+			member = "VALUE_" + member
+		}
+		for seen[member] {
+			member += "_"
+		}
+		seen[member] = true
+		e.Members = append(e.Members, &EnumMember{Name: member, Value: javaLiteral(v, typ)})
+	}
+	b.enums = append(b.enums, e)
+}
+
+// projected returns true if the given media type is the result of a projection.
+func projected(mt *design.MediaTypeDefinition) bool {
+	_, params, err := mime.ParseMediaType(mt.Identifier)
+	return err == nil && params["view"] != ""
+}
+
+// javaName returns the given identifier suffixed with an underscore if it is a Java reserved word.
+func javaName(name string) string {
+	if javaKeywords[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// getter returns the name of the getter of the field with the given JSON key, it avoids the
+// final methods of java.lang.Object.
+func getter(key string) string {
+	name := "get" + codegen.CamelIdentifier(key, true)
+	if name == "getClass" {
+		name += "_"
+	}
+	return name
+}
+
+// javaLiteral returns the Java literal of the given design value for the given Java type.
+func javaLiteral(v interface{}, typ string) string {
+	switch typ {
+	case "Long":
+		switch val := v.(type) {
+		case float64:
+			return strconv.FormatInt(int64(val), 10) + "L"
+		case float32:
+			return strconv.FormatInt(int64(val), 10) + "L"
+		}
+		return fmt.Sprintf("%vL", v)
+	case "Double":
+		return fmt.Sprintf("%vD", v)
+	}
+	return javaString(fmt.Sprintf("%v", v))
+}
+
+// javaString returns the Java string literal of the given string.
+func javaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r > 0x7e {
+				if r > 0xffff {
+					r1, r2 := utf16.EncodeRune(r)
+					fmt.Fprintf(&b, `\u%04x\u%04x`, r1, r2)
+					continue
+				}
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// groovyString returns the Groovy single quoted string literal of the given string.
+func groovyString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// javadoc returns the given text formatted for a javadoc comment indented with indent.
+func javadoc(indent, text string) string {
+	text = strings.Replace(strings.TrimSpace(text), "*/", "*&#47;", -1)
+	return strings.Replace(text, "\n", "\n"+indent+" * ", -1)
+}
@@ -0,0 +1,218 @@
+package genjava_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_java"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_java/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--host=baz", "--version=" + version.String()}
+		design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genjava.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a secured action with params, a payload and a result", func() {
+		var srcDir string
+
+		BeforeEach(func() {
+			srcDir = filepath.Join(outDir, "java", "src", "main", "java", "cellar", "client")
+			scheme := &design.SecuritySchemeDefinition{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+			}
+			bottle := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Bottle",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":   &design.AttributeDefinition{Type: design.Integer},
+							"name": &design.AttributeDefinition{Type: design.String},
+							"color": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+					},
+				},
+				Identifier: "application/vnd.bottle",
+			}
+			bottle.Views = map[string]*design.ViewDefinition{
+				"default": {
+					Name:   "default",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id":    &design.AttributeDefinition{Type: design.Integer},
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"color": &design.AttributeDefinition{Type: design.String},
+					}},
+				},
+				"tiny": {
+					Name:   "tiny",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id": &design.AttributeDefinition{Type: design.Integer},
+					}},
+				},
+			}
+			filter := &design.UserTypeDefinition{
+				TypeName: "Filter",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"vintage": &design.AttributeDefinition{Type: design.Integer}},
+				},
+			}
+			action := &design.ActionDefinition{
+				Name: "update",
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"bottleID": &design.AttributeDefinition{Type: design.Integer},
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+						"filter": &design.AttributeDefinition{Type: filter},
+					},
+				},
+				QueryParams: &design.AttributeDefinition{
+					Type: design.Object{
+						"filter": &design.AttributeDefinition{Type: filter},
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "UpdateBottlePayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{Type: design.String},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"OK":       {Name: "OK", Status: 200, MediaType: "application/vnd.bottle", ViewName: "tiny"},
+					"NotFound": {Name: "NotFound", Status: 404},
+				},
+				Security: &design.SecurityDefinition{Scheme: scheme},
+			}
+			route := &design.RouteDefinition{
+				Verb:   "PUT",
+				Path:   "/:bottleID",
+				Parent: action,
+			}
+			action.Routes = []*design.RouteDefinition{route}
+			res := &design.ResourceDefinition{
+				Name:     "bottle",
+				BasePath: "/bottles",
+				Actions:  map[string]*design.ActionDefinition{"update": action},
+			}
+			action.Parent = res
+			design.Design = &design.APIDefinition{
+				Name:            "cellar",
+				BasePath:        "/cellar",
+				Resources:       map[string]*design.ResourceDefinition{"bottle": res},
+				MediaTypes:      map[string]*design.MediaTypeDefinition{"application/vnd.bottle": bottle},
+				SecuritySchemes: []*design.SecuritySchemeDefinition{scheme},
+			}
+		})
+
+		It("generates the project", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(10))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "java", "settings.gradle"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("rootProject.name = 'cellar-client'"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "java", "build.gradle"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("group = 'cellar.client'"))
+		})
+
+		It("generates one class per view", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "model", "Bottle.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package cellar.client.model;"))
+			Ω(string(content)).Should(ContainSubstring("public final class Bottle {"))
+			Ω(string(content)).Should(ContainSubstring("    @JsonProperty(\"color\")\n    @Nullable\n    public BottleColor getColor() {"))
+			Ω(string(content)).Should(ContainSubstring("    @JsonProperty(\"id\")\n    @Nonnull\n    public Long getId() {"))
+			Ω(string(content)).Should(ContainSubstring("public Builder name(@Nonnull String name) {"))
+			Ω(string(content)).Should(ContainSubstring(`throw new IllegalStateException("missing required field \"name\"");`))
+			content, err = ioutil.ReadFile(filepath.Join(srcDir, "model", "BottleTiny.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("public final class BottleTiny {"))
+			Ω(string(content)).ShouldNot(ContainSubstring("getName"))
+		})
+
+		It("generates the enums", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "model", "BottleColor.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("public enum BottleColor {\n    RED(\"red\"),\n    WHITE(\"white\");"))
+			content, err = ioutil.ReadFile(filepath.Join(srcDir, "model", "UpdateBottleSort.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("public static UpdateBottleSort fromValue(String value) {"))
+		})
+
+		It("generates the Retrofit interfaces", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "BottleApi.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("public interface BottleApi {"))
+			Ω(string(content)).Should(ContainSubstring(`@HTTP(method = "PUT", path = "/cellar/bottles/{bottleID}", hasBody = true)`))
+			Ω(string(content)).Should(ContainSubstring(`@Headers(CellarClient.SECURITY_HEADER + ": jwt")`))
+			Ω(string(content)).Should(ContainSubstring("Call<BottleTiny> update(\n" +
+				"        @Path(\"bottleID\") @Nonnull Long bottleId,\n" +
+				"        @Body @Nonnull UpdateBottlePayload payload,\n" +
+				"        @Query(\"filter.vintage\") @Nullable Long filterVintage,\n" +
+				"        @Query(\"sort\") @Nullable UpdateBottleSort sort);"))
+		})
+
+		It("generates the client", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "CellarClient.java"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`public static final String DEFAULT_BASE_URL = "http://baz/";`))
+			Ω(string(content)).Should(ContainSubstring("this.bottle = retrofit.create(BottleApi.class);"))
+			Ω(string(content)).Should(ContainSubstring("case \"jwt\":\n                    builder.header(\"Authorization\", \"Bearer \" + value);"))
+		})
+	})
+
+	Context("with an invalid package name", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{Name: "cellar"}
+			os.Args = append(os.Args, "--pkg=cellar.new")
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(MatchError(ContainSubstring("invalid Java package name")))
+		})
+	})
+})
@@ -0,0 +1,413 @@
+package genjava
+
+const settingsT = `// Settings of the {{ .API.Name }} API client project.
+//
+// Generated by goagen, DO NOT MODIFY.
+rootProject.name = {{ groovyString .Artifact }}
+`
+
+const buildT = `// Build of the {{ .API.Name }} API client project.
+//
+// Generated by goagen, DO NOT MODIFY.
+plugins {
+    id 'java-library'
+}
+
+group = {{ groovyString .Package }}
+version = {{ if .API.Version }}{{ groovyString .API.Version }}{{ else }}'0.1.0'{{ end }}
+
+java {
+    sourceCompatibility = JavaVersion.VERSION_1_8
+    targetCompatibility = JavaVersion.VERSION_1_8
+}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    api 'com.squareup.retrofit2:retrofit:2.9.0'
+    api 'com.fasterxml.jackson.core:jackson-databind:2.15.2'
+    api 'com.google.code.findbugs:jsr305:3.0.2'
+    implementation 'com.squareup.retrofit2:converter-jackson:2.9.0'
+    implementation 'com.fasterxml.jackson.datatype:jackson-datatype-jsr310:2.15.2'
+}
+`
+
+const clientT = `// Code generated by goagen, DO NOT EDIT.
+package {{ .Package }};
+
+import com.fasterxml.jackson.databind.DeserializationFeature;
+import com.fasterxml.jackson.databind.ObjectMapper;
+import com.fasterxml.jackson.databind.SerializationFeature;
+import com.fasterxml.jackson.datatype.jsr310.JavaTimeModule;
+import java.io.IOException;
+import java.nio.charset.StandardCharsets;
+import java.util.Base64;
+import java.util.HashMap;
+import java.util.Map;
+import javax.annotation.Nonnull;
+import okhttp3.Interceptor;
+import okhttp3.OkHttpClient;
+import okhttp3.Request;
+import okhttp3.Response;
+import retrofit2.Retrofit;
+import retrofit2.converter.jackson.JacksonConverterFactory;
+
+/**
+ * {{ .ClientName }} is the {{ .API.Name }} API client. It exposes one Retrofit interface per resource
+ * whose methods return calls executed synchronously with execute or asynchronously with enqueue.
+ */
+public final class {{ .ClientName }} {
+    /**
+     * DEFAULT_BASE_URL is the scheme, host and port of the API defined in the design.
+     */
+    public static final String DEFAULT_BASE_URL = {{ .BaseURL }};
+
+    /**
+     * SECURITY_HEADER is the header set by the methods of secured actions to the name of their
+     * security scheme, the client replaces it with the credential of the scheme.
+     */
+    public static final String SECURITY_HEADER = "X-Goa-Security-Scheme";
+
+    private final Retrofit retrofit;
+{{ range .Services }}    private final {{ .Name }} {{ .Accessor }};
+{{ end }}
+    private {{ .ClientName }}(Retrofit retrofit) {
+        this.retrofit = retrofit;
+{{ range .Services }}        this.{{ .Accessor }} = retrofit.create({{ .Name }}.class);
+{{ end }}    }
+
+    /**
+     * builder returns a new client builder.
+     */
+    public static Builder builder() {
+        return new Builder();
+    }
+
+    /**
+     * objectMapper returns a Jackson object mapper configured to encode and decode the API data
+     * structures.
+     */
+    public static ObjectMapper objectMapper() {
+        return new ObjectMapper()
+            .registerModule(new JavaTimeModule())
+            .disable(SerializationFeature.WRITE_DATES_AS_TIMESTAMPS)
+            .disable(DeserializationFeature.FAIL_ON_UNKNOWN_PROPERTIES)
+            .disable(DeserializationFeature.ADJUST_DATES_TO_CONTEXT_TIME_ZONE);
+    }
+
+    /**
+     * retrofit returns the Retrofit instance used to create the resource interfaces.
+     */
+    public Retrofit retrofit() {
+        return retrofit;
+    }
+{{ range .Services }}
+    /**
+     * {{ .Accessor }} returns the {{ .Name }} interface.
+     */
+    public {{ .Name }} {{ .Accessor }}() {
+        return {{ .Accessor }};
+    }
+{{ end }}
+    /**
+     * Builder builds {{ .ClientName }} instances.
+     */
+    public static final class Builder {
+        private String baseUrl = DEFAULT_BASE_URL;
+        private OkHttpClient httpClient;
+        private ObjectMapper objectMapper;
+        private final Map<String, String> credentials = new HashMap<>();
+
+        private Builder() {
+        }
+
+        /**
+         * baseUrl sets the scheme, host and port of the API, it defaults to DEFAULT_BASE_URL.
+         */
+        public Builder baseUrl(@Nonnull String baseUrl) {
+            this.baseUrl = baseUrl.endsWith("/") ? baseUrl : baseUrl + "/";
+            return this;
+        }
+
+        /**
+         * httpClient sets the OkHttp client used to send the requests, it defaults to a new
+         * client.
+         */
+        public Builder httpClient(@Nonnull OkHttpClient httpClient) {
+            this.httpClient = httpClient;
+            return this;
+        }
+
+        /**
+         * objectMapper sets the Jackson object mapper used to encode the payloads and decode the
+         * responses, it defaults to the mapper returned by {{ $.ClientName }}.objectMapper.
+         */
+        public Builder objectMapper(@Nonnull ObjectMapper objectMapper) {
+            this.objectMapper = objectMapper;
+            return this;
+        }
+
+        /**
+         * credential sets the credential sent to the actions secured with the given security
+         * scheme: "user:password" for basic auth, the key for API keys, the token for JWT and
+         * OAuth2 and the cookie value for session authentication.
+         */
+        public Builder credential(@Nonnull String scheme, @Nonnull String value) {
+            credentials.put(scheme, value);
+            return this;
+        }
+
+        /**
+         * build returns the client.
+         */
+        public {{ .ClientName }} build() {
+            OkHttpClient client = httpClient != null ? httpClient : new OkHttpClient();
+            client = client.newBuilder()
+                .addInterceptor(new SecurityInterceptor(new HashMap<>(credentials)))
+                .build();
+            ObjectMapper mapper = objectMapper != null ? objectMapper : {{ .ClientName }}.objectMapper();
+            Retrofit retrofit = new Retrofit.Builder()
+                .baseUrl(baseUrl)
+                .client(client)
+                .addConverterFactory(JacksonConverterFactory.create(mapper))
+                .build();
+            return new {{ .ClientName }}(retrofit);
+        }
+    }
+
+    /**
+     * SecurityInterceptor replaces the SECURITY_HEADER header with the credential of the
+     * corresponding security scheme.
+     */
+    private static final class SecurityInterceptor implements Interceptor {
+        private final Map<String, String> credentials;
+
+        SecurityInterceptor(Map<String, String> credentials) {
+            this.credentials = credentials;
+        }
+
+        @Override
+        public Response intercept(Chain chain) throws IOException {
+            Request request = chain.request();
+            String scheme = request.header(SECURITY_HEADER);
+            if (scheme == null) {
+                return chain.proceed(request);
+            }
+            Request.Builder builder = request.newBuilder().removeHeader(SECURITY_HEADER);
+            String value = credentials.get(scheme);
+            if (value != null) {
+                switch (scheme) {
+{{ range .Schemes }}                case {{ .Name }}:
+{{ if .Basic }}                    value = Base64.getEncoder().encodeToString(value.getBytes(StandardCharsets.UTF_8));
+{{ end }}{{ if .Query }}                    builder.url(request.url().newBuilder().addQueryParameter({{ .Header }}, value).build());
+{{ else }}                    builder.header({{ .Header }}, {{ if .Prefix }}{{ javaString .Prefix }} + {{ end }}value);
+{{ end }}                    break;
+{{ end }}                default:
+                    break;
+                }
+            }
+            return chain.proceed(builder.build());
+        }
+    }
+}
+`
+
+const serviceT = `// Code generated by goagen, DO NOT EDIT.
+package {{ .Package }};
+
+{{ if .HasModels }}import {{ .Package }}.model.*;
+{{ end }}import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.Map;
+import java.util.UUID;
+import javax.annotation.Nonnull;
+import javax.annotation.Nullable;
+import retrofit2.Call;
+import retrofit2.http.Body;
+import retrofit2.http.HTTP;
+import retrofit2.http.Header;
+import retrofit2.http.Headers;
+import retrofit2.http.Path;
+import retrofit2.http.Query;
+{{ with .Service }}
+/**
+ * {{ javadoc "" .Description }}
+ */
+public interface {{ .Name }} {
+{{ range .Methods }}
+    /**
+     * {{ javadoc "    " .Description }}
+     *
+     * <p>{{ .Verb }} {{ .Path }}
+     */
+    @HTTP(method = {{ javaString .Verb }}, path = {{ .URL }}{{ if .HasBody }}, hasBody = true{{ end }})
+{{ if .Scheme }}    @Headers({{ $.ClientName }}.SECURITY_HEADER + {{ javaString (printf ": %s" .Scheme) }})
+{{ end }}    Call<{{ .Result }}> {{ .Name }}({{ if gt (len .Params) 3 }}
+        {{ join .Params ",\n        " }}{{ else }}{{ join .Params ", " }}{{ end }});
+{{ end }}}
+{{ end }}`
+
+const enumT = `// Code generated by goagen, DO NOT EDIT.
+package {{ .Package }}.model;
+
+import com.fasterxml.jackson.annotation.JsonCreator;
+import com.fasterxml.jackson.annotation.JsonValue;
+{{ with .Enum }}
+/**
+ * {{ .Name }} enumerates the values allowed by the design.
+ */
+public enum {{ .Name }} {
+{{ range $i, $m := .Members }}{{ if $i }},
+{{ end }}    {{ $m.Name }}({{ $m.Value }}){{ end }};
+
+    private final {{ .Type }} value;
+
+    {{ .Name }}({{ .Type }} value) {
+        this.value = value;
+    }
+
+    /**
+     * getValue returns the JSON value of the constant.
+     */
+    @JsonValue
+    public {{ .Type }} getValue() {
+        return value;
+    }
+
+    /**
+     * fromValue returns the constant with the given JSON value.
+     *
+     * @throws IllegalArgumentException if no constant has the given value.
+     */
+    @JsonCreator
+    public static {{ .Name }} fromValue({{ .Type }} value) {
+        for ({{ .Name }} c : values()) {
+            if (c.value.equals(value)) {
+                return c;
+            }
+        }
+        throw new IllegalArgumentException("unexpected {{ .Name }} value " + value);
+    }
+
+    @Override
+    public String toString() {
+        return String.valueOf(value);
+    }
+}
+{{ end }}`
+
+const modelT = `// Code generated by goagen, DO NOT EDIT.
+package {{ .Package }}.model;
+
+import com.fasterxml.jackson.annotation.JsonIgnoreProperties;
+import com.fasterxml.jackson.annotation.JsonInclude;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import com.fasterxml.jackson.databind.annotation.JsonDeserialize;
+import com.fasterxml.jackson.databind.annotation.JsonPOJOBuilder;
+import java.time.OffsetDateTime;
+import java.util.List;
+import java.util.Map;
+import java.util.Objects;
+import java.util.UUID;
+import javax.annotation.Nonnull;
+import javax.annotation.Nullable;
+{{ with .Model }}
+/**
+ * {{ javadoc "" .Description }}
+ */
+@JsonInclude(JsonInclude.Include.NON_NULL)
+@JsonDeserialize(builder = {{ .Name }}.Builder.class)
+public final class {{ .Name }} {
+{{ range .Fields }}    private final {{ .Type }} {{ .Name }};
+{{ end }}
+    private {{ .Name }}(Builder builder) {
+{{ range .Fields }}        this.{{ .Name }} = builder.{{ .Name }};
+{{ end }}    }
+
+    /**
+     * builder returns a new {{ .Name }} builder.
+     */
+    public static Builder builder() {
+        return new Builder();
+    }
+
+    /**
+     * toBuilder returns a builder initialized with the fields of this {{ .Name }}.
+     */
+    public Builder toBuilder() {
+        Builder builder = new Builder();
+{{ range .Fields }}        builder.{{ .Name }} = {{ .Name }};
+{{ end }}        return builder;
+    }
+{{ range .Fields }}
+    /**
+     * {{ javadoc "    " .Description }}
+     */
+    @JsonProperty({{ .Key }})
+    {{ if .Required }}@Nonnull{{ else }}@Nullable{{ end }}
+    public {{ .Type }} {{ .Getter }}() {
+        return {{ .Name }};
+    }
+{{ end }}
+    @Override
+    public boolean equals(Object o) {
+        if (this == o) {
+            return true;
+        }
+        if (o == null || getClass() != o.getClass()) {
+            return false;
+        }
+{{ if .Fields }}        {{ .Name }} other = ({{ .Name }}) o;
+        return {{ range $i, $f := .Fields }}{{ if $i }}
+            && {{ end }}Objects.equals({{ $f.Name }}, other.{{ $f.Name }}){{ end }};
+{{ else }}        return true;
+{{ end }}    }
+
+    @Override
+    public int hashCode() {
+        return Objects.hash({{ range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.Name }}{{ end }});
+    }
+
+    @Override
+    public String toString() {
+        return "{{ .Name }}{"{{ range $i, $f := .Fields }}
+            + "{{ if $i }}, {{ end }}{{ $f.Name }}=" + {{ $f.Name }}{{ end }}
+            + "}";
+    }
+
+    /**
+     * Builder builds {{ .Name }} instances.
+     */
+    @JsonPOJOBuilder(withPrefix = "")
+    @JsonIgnoreProperties(ignoreUnknown = true)
+    public static final class Builder {
+{{ range .Fields }}        private {{ .Type }} {{ .Name }};
+{{ end }}
+        private Builder() {
+        }
+{{ range .Fields }}
+        /**
+         * {{ .Name }} sets the {{ .Name }} field{{ if .Required }}, the field is required{{ end }}.
+         */
+        @JsonProperty({{ .Key }})
+        public Builder {{ .Name }}({{ if .Required }}@Nonnull{{ else }}@Nullable{{ end }} {{ .Type }} {{ .Name }}) {
+            this.{{ .Name }} = {{ .Name }};
+            return this;
+        }
+{{ end }}
+        /**
+         * build returns the {{ $.Model.Name }}.
+         *
+         * @throws IllegalStateException if a required field is missing.
+         */
+        public {{ .Name }} build() {
+{{ range .Fields }}{{ if .Required }}            if ({{ .Name }} == null) {
+                throw new IllegalStateException({{ javaString (printf "missing required field %q" .Attribute) }});
+            }
+{{ end }}{{ end }}            return new {{ $.Model.Name }}(this);
+        }
+    }
+}
+{{ end }}`
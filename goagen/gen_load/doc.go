@@ -0,0 +1,16 @@
+/*
+Package genload provides a goa generator for load testing scenarios.
+The generator produces a k6 (https://k6.io) script and vegeta (https://github.com/tsenart/vegeta)
+target files that exercise each API endpoint. The requests are built from the design examples and
+contain placeholders for the credentials required by the endpoint security schemes.
+
+The k6 script reads the base URL and credentials from environment variables:
+
+	k6 run -e BASE_URL=http://localhost:8080 -e JWT_TOKEN=... load/script.js
+
+The vegeta targets file uses shell variables for the credentials, substitute them before running
+the attack from the "load" directory:
+
+	cd load && envsubst < targets.txt | vegeta attack -duration=30s | vegeta report
+*/
+package genload
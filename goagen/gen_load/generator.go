@@ -0,0 +1,355 @@
+package genload
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the load testing scenarios generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Format   string                // Format of generated scenarios: "k6", "vegeta" or empty for both
+	Scheme   string                // Scheme used to make requests
+	Host     string                // Host addressed by requests
+	genfiles []string              // Generated files
+}
+
+type (
+	// Endpoint holds the data needed to generate the requests made to an action endpoint.
+	Endpoint struct {
+		Resource  string        // Name of resource
+		Action    string        // Name of action
+		Verb      string        // HTTP method
+		Path      string        // Request path and query string built from the design examples
+		Headers   []*AuthHeader // Headers holding credentials
+		QueryAuth *AuthHeader   // Query string parameter holding credentials if any
+		Body      string        // JSON body built from the payload example if any
+		BodyFile  string        // Path of body file relative to output directory if any
+		Status    int           // Expected response status, 0 if not known
+	}
+
+	// AuthHeader describes a header or query string parameter holding credentials.
+	AuthHeader struct {
+		Name   string // Name of header or query string parameter
+		Prefix string // Prefix of value, e.g. "Bearer "
+		Env    string // Name of environment variable holding credentials
+	}
+)
+
+// funcMap is the template function map used to render the scenarios.
+var funcMap = template.FuncMap{"contains": strings.Contains}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, format, scheme, host, ver string
+	)
+
+	set := flag.NewFlagSet("load", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&format, "format", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Format: format, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the load testing scenarios.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Format != "" && g.Format != "k6" && g.Format != "vegeta" {
+		return nil, fmt.Errorf(`invalid format "%s", must be "k6" or "vegeta"`, g.Format)
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		return nil, fmt.Errorf("missing host value, set it with --host")
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "load")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	endpoints, err := g.endpoints()
+	if err != nil {
+		return
+	}
+	if g.Format == "" || g.Format == "k6" {
+		if err = g.generateK6(filepath.Join(g.OutDir, "script.js"), endpoints); err != nil {
+			return
+		}
+	}
+	if g.Format == "" || g.Format == "vegeta" {
+		if err = g.generateVegeta(filepath.Join(g.OutDir, "targets.txt"), endpoints); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// endpoints computes the data needed to generate the requests made to each API endpoint.
+func (g *Generator) endpoints() ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	rand := design.NewRandomGenerator(g.API.Name)
+	err := g.API.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			if len(action.Routes) == 0 || action.WebSocket() {
+				return nil
+			}
+			route := action.Routes[0]
+			e := &Endpoint{
+				Resource: res.Name,
+				Action:   action.Name,
+				Verb:     route.Verb,
+				Path:     requestPath(action, route, rand),
+				Status:   expectedStatus(action),
+			}
+			if action.Payload != nil {
+				example := action.Payload.GenerateExample(rand, nil)
+				if example != nil && example != "-" {
+					b, err := json.Marshal(example)
+					if err != nil {
+						return err
+					}
+					e.Body = string(b)
+					e.BodyFile = filepath.ToSlash(filepath.Join("bodies",
+						codegen.SnakeCase(action.Name)+"_"+codegen.SnakeCase(res.Name)+".json"))
+				}
+			}
+			if action.Security != nil {
+				scheme := action.Security.Scheme
+				auth := &AuthHeader{Name: "Authorization", Env: envVar(scheme)}
+				switch scheme.Kind {
+				case design.BasicAuthSecurityKind:
+					auth.Prefix = "Basic "
+				case design.APIKeySecurityKind:
+					auth.Name = scheme.Name
+				case design.SessionSecurityKind:
+					auth.Name = "Cookie"
+					auth.Prefix = scheme.CookieName + "="
+				default:
+					auth.Prefix = "Bearer "
+				}
+				if scheme.Kind == design.APIKeySecurityKind && scheme.In == "query" {
+					e.QueryAuth = auth
+				} else {
+					e.Headers = append(e.Headers, auth)
+				}
+			}
+			endpoints = append(endpoints, e)
+			return nil
+		})
+	})
+	return endpoints, err
+}
+
+func (g *Generator) generateK6(scriptFile string, endpoints []*Endpoint) error {
+	file, err := codegen.SourceFileFor(scriptFile)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, scriptFile)
+	data := map[string]interface{}{
+		"API":       g.API,
+		"BaseURL":   g.Scheme + "://" + g.Host,
+		"Endpoints": endpoints,
+	}
+	return file.ExecuteTemplate("k6", k6T, funcMap, data)
+}
+
+func (g *Generator) generateVegeta(targetsFile string, endpoints []*Endpoint) error {
+	file, err := codegen.SourceFileFor(targetsFile)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, targetsFile)
+	for _, e := range endpoints {
+		if e.BodyFile == "" {
+			continue
+		}
+		bodyFile := filepath.Join(g.OutDir, filepath.FromSlash(e.BodyFile))
+		if err := os.MkdirAll(filepath.Dir(bodyFile), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(bodyFile, []byte(e.Body), 0644); err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, bodyFile)
+	}
+	data := map[string]interface{}{
+		"BaseURL":   g.Scheme + "://" + g.Host,
+		"Endpoints": endpoints,
+	}
+	return file.ExecuteTemplate("vegeta", vegetaT, funcMap, data)
+}
+
+// requestPath returns the request path of the given route where the wildcards are replaced with
+// the param examples. The path includes a query string initialized with the examples of the
+// required query string parameters.
+func requestPath(action *design.ActionDefinition, route *design.RouteDefinition, rand *design.RandomGenerator) string {
+	params := action.AllParams()
+	example := func(name string) string {
+		if params == nil {
+			return ""
+		}
+		att, ok := params.Type.ToObject()[name]
+		if !ok {
+			return ""
+		}
+		switch ex := att.GenerateExample(rand, nil).(type) {
+		case nil:
+			return ""
+		case []interface{}:
+			if len(ex) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%v", ex[0])
+		default:
+			return fmt.Sprintf("%v", ex)
+		}
+	}
+	path := design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(wc string) string {
+		name := wc[2:] // remove "/:" or "/*" prefix
+		return "/" + url.PathEscape(example(name))
+	})
+	if action.QueryParams == nil {
+		return path
+	}
+	var names []string
+	for n := range action.QueryParams.Type.ToObject() {
+		if action.QueryParams.IsRequired(n) {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return path
+	}
+	sort.Strings(names)
+	values := url.Values{}
+	for _, n := range names {
+		values.Set(n, example(n))
+	}
+	return path + "?" + values.Encode()
+}
+
+// expectedStatus returns the status code of the first successful response of the action, 0 if
+// none.
+func expectedStatus(action *design.ActionDefinition) int {
+	var statuses []int
+	for _, r := range action.Responses {
+		if r.Status >= 200 && r.Status < 300 {
+			statuses = append(statuses, r.Status)
+		}
+	}
+	if len(statuses) == 0 {
+		return 0
+	}
+	sort.Ints(statuses)
+	return statuses[0]
+}
+
+// envVar returns the name of the environment variable holding the credentials for the given
+// security scheme.
+func envVar(scheme *design.SecuritySchemeDefinition) string {
+	name := strings.ToUpper(codegen.SnakeCase(codegen.Goify(scheme.SchemeName, true)))
+	switch scheme.Kind {
+	case design.BasicAuthSecurityKind:
+		return name + "_CREDENTIALS"
+	case design.APIKeySecurityKind:
+		return name + "_KEY"
+	case design.SessionSecurityKind:
+		return name + "_SESSION"
+	default:
+		return name + "_TOKEN"
+	}
+}
+
+const k6T = `// Load testing script for the {{ .API.Name }} API, run with:
+//
+//   k6 run -e BASE_URL={{ .BaseURL }} script.js
+//
+// Generated by goagen, DO NOT MODIFY.
+import http from "k6/http";
+import { check, group } from "k6";
+
+const baseURL = __ENV.BASE_URL || "{{ .BaseURL }}";
+
+export const options = {
+  vus: 10,
+  duration: "30s",
+};
+
+export default function () {
+{{ range .Endpoints }}  group("{{ .Resource }} {{ .Action }}", function () {
+    const res = http.request("{{ .Verb }}", baseURL + {{ printf "%q" .Path }}{{ if .QueryAuth }} + "{{ if contains .Path "?" }}&{{ else }}?{{ end }}{{ .QueryAuth.Name }}=" + encodeURIComponent(__ENV.{{ .QueryAuth.Env }}){{ end }}, {{ if .Body }}JSON.stringify({{ .Body }}){{ else }}null{{ end }}, {
+      headers: {
+{{ if .Body }}        "Content-Type": "application/json",
+{{ end }}{{ range .Headers }}        "{{ .Name }}": "{{ .Prefix }}" + __ENV.{{ .Env }},
+{{ end }}      },
+    });
+    check(res, {
+{{ if .Status }}      "status is {{ .Status }}": (r) => r.status === {{ .Status }},
+{{ else }}      "status is not a server error": (r) => r.status < 500,
+{{ end }}    });
+  });
+{{ end }}}
+`
+
+const vegetaT = `{{ $baseURL := .BaseURL }}{{ range .Endpoints }}{{ .Verb }} {{ $baseURL }}{{ .Path }}{{ if .QueryAuth }}{{ if contains .Path "?" }}&{{ else }}?{{ end }}{{ .QueryAuth.Name }}=${{ "{" }}{{ .QueryAuth.Env }}}{{ end }}
+{{ if .Body }}Content-Type: application/json
+{{ end }}{{ range .Headers }}{{ .Name }}: {{ .Prefix }}${{ "{" }}{{ .Env }}}
+{{ end }}{{ if .BodyFile }}@{{ .BodyFile }}
+{{ end }}
+{{ end }}`
@@ -0,0 +1,108 @@
+package genload_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_load"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_load/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--host=baz", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genload.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a secured action with a payload", func() {
+		BeforeEach(func() {
+			scheme := &design.SecuritySchemeDefinition{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+			}
+			action := &design.ActionDefinition{
+				Name: "update",
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"id": &design.AttributeDefinition{Type: design.Integer, Example: 42},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "UpdatePayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{Type: design.String},
+						},
+						Example: map[string]interface{}{"name": "foo"},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"NoContent": {Name: "NoContent", Status: 204},
+				},
+				Security: &design.SecurityDefinition{Scheme: scheme},
+			}
+			route := &design.RouteDefinition{
+				Verb:   "PUT",
+				Path:   "/:id",
+				Parent: action,
+			}
+			action.Routes = []*design.RouteDefinition{route}
+			res := &design.ResourceDefinition{
+				Name:     "bottle",
+				BasePath: "/bottles",
+				Actions:  map[string]*design.ActionDefinition{"update": action},
+			}
+			action.Parent = res
+			design.Design = &design.APIDefinition{
+				Name:      "testapi",
+				Resources: map[string]*design.ResourceDefinition{"bottle": res},
+			}
+		})
+
+		It("generates a k6 script", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "load", "script.js"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`http.request("PUT", baseURL + "/bottles/42", JSON.stringify({"name":"foo"})`))
+			Ω(string(content)).Should(ContainSubstring(`"Authorization": "Bearer " + __ENV.JWT_TOKEN`))
+			Ω(string(content)).Should(ContainSubstring(`r.status === 204`))
+		})
+
+		It("generates vegeta targets", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(4))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "load", "targets.txt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(Equal(`PUT http://baz/bottles/42
+Content-Type: application/json
+Authorization: Bearer ${JWT_TOKEN}
+@bodies/update_bottle.json
+
+`))
+			body, err := ioutil.ReadFile(filepath.Join(outDir, "load", "bodies", "update_bottle.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(body)).Should(Equal(`{"name":"foo"}`))
+		})
+	})
+})
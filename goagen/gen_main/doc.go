@@ -3,6 +3,15 @@ Package genmain provides a generator for a skeleton goa application.
 This generator generates the code for a basic "main" package and is mainly intended as a way to
 bootstrap new applications.
 The generator creates a main.go file and one file per resource listed in the API metadata.
+It also creates a servers.yaml file listing the API servers and their URL variables if the design
+defines any. The generated main function listens with a TLS configuration that enforces the API
+TLS requirements if the design defines them. It serves HTTP/2 (over TLS or cleartext TCP with h2c)
+if one of the API servers uses it.
+The controller of the resource defined with ManageAPIKeys is implemented with an in-memory
+apikey.KeyManager that main also uses to mount the API key auth middleware, main issues and logs the
+admin key used to create the first client keys. Likewise the controller
+of the resource defined with SessionEndpoints shares an in-memory session.Store with the session
+auth middleware.
 If a file already exists it skips its creation unless the flag --force is provided on the command
 line in which case it overrides the content of existing files.
 */
@@ -17,23 +17,25 @@ import (
 
 // Generator is the application code generator.
 type Generator struct {
-	API       *design.APIDefinition // The API definition
-	OutDir    string                // Path to output directory
-	DesignPkg string                // Path to design package, only used to mark generated files.
-	Target    string                // Name of generated "app" package
-	Force     bool                  // Whether to override existing files
-	genfiles  []string              // Generated files
+	API        *design.APIDefinition // The API definition
+	OutDir     string                // Path to output directory
+	OutPkgPath string                // Import path of output directory, computed from GOPATH or go.mod if empty
+	DesignPkg  string                // Path to design package, only used to mark generated files.
+	Target     string                // Name of generated "app" package
+	Force      bool                  // Whether to override existing files
+	genfiles   []string              // Generated files
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, designPkg, target, ver string
-		force                          bool
+		outDir, outPkgPath, designPkg, target, ver string
+		force                                      bool
 	)
 
 	set := flag.NewFlagSet("main", flag.PanicOnError)
 	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&outPkgPath, "out-pkg-path", "", "")
 	set.StringVar(&designPkg, "design", "", "")
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
@@ -45,7 +47,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, API: design.Design}
+	g := &Generator{OutDir: outDir, OutPkgPath: outPkgPath, DesignPkg: designPkg, Target: target, Force: force, API: design.Design}
 
 	return g.Generate()
 }
@@ -74,12 +76,12 @@ func (g *Generator) Generate() (_ []string, err error) {
 		"tempvar":   tempvar,
 		"okResp":    g.okResp,
 		"targetPkg": func() string { return g.Target },
+		"typeRef":   func(t design.DataType) string { return qualifiedTypeRef(t, g.Target) },
 	}
-	imp, err := codegen.PackagePath(g.OutDir)
+	imp, err := g.appPkgPath()
 	if err != nil {
 		return nil, err
 	}
-	imp = path.Join(filepath.ToSlash(imp), "app")
 	_, err = os.Stat(mainFile)
 	if err != nil {
 		if err = g.createMainFile(mainFile, funcs); err != nil {
@@ -90,8 +92,19 @@ func (g *Generator) Generate() (_ []string, err error) {
 		codegen.SimpleImport("io"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport(imp),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/apikey"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/session"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
+	if err = g.createContextValuesFile(funcs); err != nil {
+		return nil, err
+	}
+	if err = g.createServersFile(); err != nil {
+		return nil, err
+	}
+	if err = g.createWorkflowFiles(funcs); err != nil {
+		return nil, err
+	}
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
 		filename := filepath.Join(g.OutDir, codegen.SnakeCase(r.Name)+".go")
 		if g.Force {
@@ -106,10 +119,19 @@ func (g *Generator) Generate() (_ []string, err error) {
 				return err
 			}
 			file.WriteHeader("", "main", imports)
-			if err2 = file.ExecuteTemplate("controller", ctrlT, funcs, r); err2 != nil {
+			ctrl, actions := ctrlT, map[string]string{}
+			if r.ManagedAPIKeys() != nil {
+				ctrl, actions = apiKeysCtrlT, apiKeysActionsT
+			} else if r.SessionScheme() != nil {
+				ctrl, actions = sessionCtrlT, sessionActionsT
+			}
+			if err2 = file.ExecuteTemplate("controller", ctrl, funcs, r); err2 != nil {
 				return err
 			}
 			err2 = r.IterateActions(func(a *design.ActionDefinition) error {
+				if t, ok := actions[a.Name]; ok {
+					return file.ExecuteTemplate("action", t, funcs, a)
+				}
 				if a.WebSocket() {
 					return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
 				}
@@ -164,15 +186,25 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) erro
 		}
 		return port
 	}
-	outPkg, err := codegen.PackagePath(g.OutDir)
+	appPkg, err := g.appPkgPath()
 	if err != nil {
 		return err
 	}
-	appPkg := path.Join(outPkg, "app")
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("crypto/tls"),
+		codegen.SimpleImport("crypto/x509"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/apikey"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/security/session"),
+		codegen.SimpleImport("github.com/goadesign/goa/cloudevents"),
+		codegen.SimpleImport("github.com/goadesign/goa/outbox"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("golang.org/x/net/http2"),
+		codegen.SimpleImport("golang.org/x/net/http2/h2c"),
 		codegen.SimpleImport(appPkg),
 	}
 	file.Write([]byte("//go:generate goagen bootstrap -d " + g.DesignPkg + "\n\n"))
@@ -187,6 +219,137 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) erro
 	return file.FormatCode()
 }
 
+// createContextValuesFile scaffolds the functions that resolve the context values of the requests
+// unless the file already exists.
+func (g *Generator) createContextValuesFile(funcs template.FuncMap) error {
+	if len(g.API.ContextValues) == 0 {
+		return nil
+	}
+	filename := filepath.Join(g.OutDir, "context_values.go")
+	if g.Force {
+		os.Remove(filename)
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+	appPkg, err := g.appPkgPath()
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport(appPkg),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	file.WriteHeader("", "main", imports)
+	if err = file.ExecuteTemplate("contextValues", contextValuesT, funcs, g.API.ContextValues); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// createServersFile scaffolds the configuration file that lists the API servers and the values of
+// their URL variables unless the file already exists.
+func (g *Generator) createServersFile() error {
+	if len(g.API.Servers) == 0 {
+		return nil
+	}
+	filename := filepath.Join(g.OutDir, "servers.yaml")
+	if g.Force {
+		os.Remove(filename)
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+	tmpl, err := template.New("servers").Parse(serversT)
+	if err != nil {
+		panic(err) // bug
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, filename)
+	return tmpl.Execute(f, g.API)
+}
+
+// createWorkflowFiles scaffolds the implementations of the steps of the API workflows, one file
+// per workflow.
+func (g *Generator) createWorkflowFiles(funcs template.FuncMap) error {
+	appPkg, err := g.appPkgPath()
+	if err != nil {
+		return err
+	}
+	for _, w := range g.API.Workflows {
+		filename := filepath.Join(g.OutDir, codegen.SnakeCase(w.Name)+"_workflow.go")
+		if g.Force {
+			os.Remove(filename)
+		}
+		if _, err := os.Stat(filename); err == nil {
+			continue
+		}
+		file, err := codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, filename)
+		imports := []*codegen.ImportSpec{
+			codegen.SimpleImport("golang.org/x/net/context"),
+			codegen.SimpleImport(appPkg),
+		}
+		file.WriteHeader("", "main", imports)
+		if err := file.ExecuteTemplate("workflow", workflowT, funcs, w); err != nil {
+			return err
+		}
+		if err := file.FormatCode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// qualifiedTypeRef returns the Go type reference of t for code living outside of the generated
+// package pkg.
+func qualifiedTypeRef(t design.DataType, pkg string) string {
+	switch actual := t.(type) {
+	case *design.Array:
+		return "[]" + qualifiedTypeRef(actual.ElemType.Type, pkg)
+	case *design.Hash:
+		return fmt.Sprintf("map[%s]%s", qualifiedTypeRef(actual.KeyType.Type, pkg),
+			qualifiedTypeRef(actual.ElemType.Type, pkg))
+	case *design.FlagsDefinition:
+		return pkg + "." + actual.GoType
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		ref := codegen.GoTypeRef(t, nil, 0, false)
+		if strings.HasPrefix(ref, "*") {
+			return "*" + pkg + "." + ref[1:]
+		}
+		return pkg + "." + ref
+	default:
+		return codegen.GoTypeRef(t, nil, 0, false)
+	}
+}
+
+// appPkgPath returns the import path of the generated "app" package.
+func (g *Generator) appPkgPath() (string, error) {
+	outPkg := g.OutPkgPath
+	if outPkg == "" {
+		var err error
+		if outPkg, err = codegen.PackagePath(g.OutDir); err != nil {
+			return "", err
+		}
+	}
+	return path.Join(filepath.ToSlash(outPkg), "app"), nil
+}
+
 func (g *Generator) okResp(a *design.ActionDefinition) map[string]interface{} {
 	var ok *design.ResponseDefinition
 	for _, resp := range a.Responses {
@@ -244,22 +407,128 @@ func main() {
 	service := goa.New({{ printf "%q" .Name }})
 
 	// Mount middleware
-	service.Use(middleware.RequestID())
-	service.Use(middleware.LogRequest(true))
+{{ if .API.RequestID }}	service.Use({{ targetPkg }}.RequestIDMiddleware())
+{{ else }}	service.Use(middleware.RequestID())
+{{ end }}	service.Use(middleware.LogRequest(true))
 	service.Use(middleware.ErrorHandler(service, true))
-	service.Use(middleware.Recover())
-{{ $api := .API }}
+{{ if .API.PanicResponse }}	service.Use({{ targetPkg }}.RecoverMiddleware(service, nil))
+{{ else }}	service.Use(middleware.Recover())
+{{ end }}{{ if .API.Tenant }}	service.Use({{ targetPkg }}.TenantMiddleware())
+{{ end }}{{ range .API.ContextValues }}	service.Use({{ targetPkg }}.New{{ goify .Name true }}Middleware(resolve{{ goify .Name true }}))
+{{ end }}{{ $api := .API }}
+{{ range $res := $api.Resources }}{{ with $res.ManagedAPIKeys }}{{ $scheme := goify .SchemeName true }}{{/*
+*/}}	// Share the API keys between the {{ .SchemeName }} auth middleware and the {{ $res.Name }} controller
+	// TBD: Replace the in-memory store with a persistent implementation of apikey.KeyManager.
+	keys := apikey.NewMemoryStore()
+	{{ targetPkg }}.Use{{ $scheme }}Middleware(service, {{ targetPkg }}.New{{ $scheme }}KeyStoreMiddleware(keys))
+	// Issue the admin key used to create the first client keys, its value is only logged here
+	// TBD: Issue the admin keys out of band once the store is persistent.
+	adminKey, err := keys.Create(service.Context, "admin", nil, []string{"admin"})
+	if err != nil {
+		service.LogError("startup", "err", err)
+		return
+	}
+	service.LogInfo("admin key", "id", adminKey.ID, "value", adminKey.Value)
+{{ end }}{{ with $res.SessionScheme }}{{ $scheme := goify .SchemeName true }}{{/*
+*/}}	// Share the sessions between the {{ .SchemeName }} auth middleware and the {{ $res.Name }} controller
+	// TBD: Replace the in-memory store with session.NewRedisStore to run multiple instances.
+	sessions := session.NewMemoryStore(24 * time.Hour)
+	{{ targetPkg }}.Use{{ $scheme }}Middleware(service, {{ targetPkg }}.New{{ $scheme }}SessionMiddleware(sessions))
+{{ end }}{{ end }}{{ if $api.UsesOutbox }}
+	// Record the action events in the transactional outbox and relay them to the event publisher
+	// TBD: Replace the in-memory store with an implementation of outbox.Store that records the
+	// events in the service database and the publisher with one that delivers them to a broker.
+	events := outbox.NewMemoryStore()
+	outbox.UseStore(service, events)
+	relay := outbox.NewRelay(events, cloudevents.PublisherFunc(func(ctx context.Context, e *cloudevents.Event) error {
+		service.LogInfo("event", "type", e.Type, "id", e.ID)
+		return nil
+	}))
+	go relay.Run(service.Context)
+{{ end }}
 {{ range $name, $res := $api.Resources }}{{ $name := goify $res.Name true }} // Mount "{{$res.Name}}" controller
-	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $name }}Controller(service)
+	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $name }}Controller(service{{ if $res.ManagedAPIKeys }}, keys{{ else if $res.SessionScheme }}, sessions{{ end }})
 	{{ targetPkg }}.Mount{{ $name }}Controller(service, {{ $tmp }})
+{{ end }}{{ if $api.JSONRPC }}
+	// Mount the JSON-RPC endpoint
+	{{ targetPkg }}.MountJSONRPC(service)
+{{ end }}{{ if $api.UsesMQTT }}
+	// Subscribe the actions bound to MQTT topics
+	// TBD: Connect to the MQTT broker with a client that implements mqtt.Client, e.g. an adapter
+	// of github.com/eclipse/paho.mqtt.golang, and call {{ targetPkg }}.SubscribeMQTT(service, client).
+{{ end }}{{ if $api.UsesSchedule }}
+	// Call the scheduled actions
+	scheduler := {{ targetPkg }}.NewScheduler(service)
+	go scheduler.Run(service.Context)
 {{ end }}
 
 	// Start service
-	if err := service.ListenAndServe(":{{ getPort .API.Host }}"); err != nil {
+{{ $h2 := .API.UsesProtocol "h2" }}{{ $h2c := .API.UsesProtocol "h2c" }}{{ if or .API.TLS $h2 $h2c }}	server := &http.Server{
+		Addr:    ":{{ getPort .API.Host }}",
+		Handler: {{ if $h2c }}h2c.NewHandler(service.Mux, &http2.Server{}){{ else }}service.Mux{{ end }},
+{{ if .API.TLS }}		TLSConfig: tlsConfig(),
+{{ end }}	}
+{{ if $h2 }}	if err := http2.ConfigureServer(server, &http2.Server{}); err != nil {
 		service.LogError("startup", "err", err)
+		return
+	}
+{{ end }}{{ if or .API.TLS $h2 }}	service.LogInfo("listen", "transport", "https", "addr", server.Addr)
+	// TBD: Change the paths to the server certificate and key PEM files.
+	if err := server.ListenAndServeTLS("server.crt", "server.key"); err != nil {
+		service.LogError("startup", "err", err)
+	}
+{{ else }}	service.LogInfo("listen", "transport", "h2c", "addr", server.Addr)
+	if err := server.ListenAndServe(); err != nil {
+		service.LogError("startup", "err", err)
+	}
+{{ end }}{{ else }}	if err := service.ListenAndServe(":{{ getPort .API.Host }}"); err != nil {
+		service.LogError("startup", "err", err)
+	}
+{{ end }}}
+{{ with .API.TLS }}
+// tlsConfig returns the TLS configuration that enforces the TLS requirements of the API.
+func tlsConfig() *tls.Config {
+	cfg := &tls.Config{MinVersion: {{ tlsVersion .Version }}}
+{{ if .ClientCertRequired }}
+	// TBD: Change the path to the PEM file containing the certificates of the authorities that
+	// sign the client certificates.
+	pem, err := ioutil.ReadFile("ca.crt")
+	if err != nil {
+		panic(err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		panic("invalid client certificate authorities file")
 	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+{{ end }}
+	return cfg
 }
-`
+{{ end }}`
+
+const contextValuesT = `{{ range . }}{{ $name := goify .Name true }}
+// resolve{{ $name }} computes the {{ printf "%q" .Name }} context value of the request.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func resolve{{ $name }}(ctx context.Context, req *http.Request) (v {{ typeRef .Type }}, err error) {
+	// resolve{{ $name }}: start_implement
+
+	// Put your logic here
+
+	// resolve{{ $name }}: end_implement
+	return
+}
+{{ end }}`
+
+const serversT = `# Servers of the {{ .Name }} API, generated clients select one with UseServer or
+# with the --server and --server-var command line flags.
+servers:
+{{ range .Servers }}  - name: {{ printf "%q" .Name }}
+{{ if .Description }}    description: {{ printf "%q" .Description }}
+{{ end }}    url: {{ printf "%q" .URL }}
+{{ if .Variables }}    variables:
+{{ range $n, $v := .Defaults }}      {{ $n }}: {{ printf "%q" $v }}
+{{ end }}{{ end }}{{ end }}`
 
 const ctrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
 type {{ $ctrlName }} struct {
@@ -272,6 +541,133 @@ func New{{ $ctrlName }}(service *goa.Service) *{{ $ctrlName }} {
 }
 `
 
+const apiKeysCtrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
+// It administers the keys of the {{ .ManagedAPIKeys.SchemeName }} security scheme.
+type {{ $ctrlName }} struct {
+	*goa.Controller
+	keys apikey.KeyManager
+}
+
+// New{{ $ctrlName }} creates a {{ .Name }} controller that manages the given keys.
+func New{{ $ctrlName }}(service *goa.Service, keys apikey.KeyManager) *{{ $ctrlName }} {
+	return &{{ $ctrlName }}{Controller: service.NewController("{{ $ctrlName }}"), keys: keys}
+}
+
+// apiKeyMedia builds the API key media type from a stored key.
+func apiKeyMedia(k *apikey.Key) *{{ targetPkg }}.APIKey {
+	return &{{ targetPkg }}.APIKey{
+		ID:         k.ID,
+		Owner:      k.Owner,
+		Scopes:     k.Scopes,
+		Roles:      k.Roles,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		Revoked:    k.Revoked,
+	}
+}
+`
+
+// apiKeysActionsT lists the templates of the actions of the resource defined with ManageAPIKeys.
+var apiKeysActionsT = map[string]string{
+	"list": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	keys, err := c.keys.List(ctx)
+	if err != nil {
+		return err
+	}
+	res := make({{ targetPkg }}.APIKeyCollection, len(keys))
+	for i, k := range keys {
+		res[i] = apiKeyMedia(k)
+	}
+	return ctx.OK(res)
+}
+`,
+	"create": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	k, err := c.keys.Create(ctx, ctx.Payload.Owner, ctx.Payload.Scopes, ctx.Payload.Roles)
+	if err != nil {
+		return err
+	}
+	return ctx.CreatedSecret(&{{ targetPkg }}.APIKeySecret{
+		ID:        k.ID,
+		Key:       &k.Value,
+		Owner:     k.Owner,
+		Scopes:    k.Scopes,
+		Roles:     k.Roles,
+		CreatedAt: k.CreatedAt,
+		Revoked:   k.Revoked,
+	})
+}
+`,
+	"show": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	k, err := c.keys.Get(ctx, ctx.KeyID)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return ctx.NotFound()
+	}
+	return ctx.OK(apiKeyMedia(k))
+}
+`,
+	"revoke": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	k, err := c.keys.Get(ctx, ctx.KeyID)
+	if err != nil {
+		return err
+	}
+	if k == nil {
+		return ctx.NotFound()
+	}
+	if err := c.keys.Revoke(ctx, k.ID); err != nil {
+		return err
+	}
+	return ctx.NoContent()
+}
+`,
+}
+
+const sessionCtrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
+// It starts and ends the sessions of the {{ .SessionScheme.SchemeName }} security scheme.
+type {{ $ctrlName }} struct {
+	*goa.Controller
+	sessions session.Store
+}
+
+// New{{ $ctrlName }} creates a {{ .Name }} controller that keeps the sessions in the given store.
+func New{{ $ctrlName }}(service *goa.Service, sessions session.Store) *{{ $ctrlName }} {
+	return &{{ $ctrlName }}{Controller: service.NewController("{{ $ctrlName }}"), sessions: sessions}
+}
+`
+
+// sessionActionsT lists the templates of the actions of the resource defined with
+// SessionEndpoints.
+var sessionActionsT = map[string]string{
+	"login": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}{{ $scheme := .Parent.SessionScheme }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	// {{ $ctrlName }}_{{ goify .Name true }}: start_implement
+
+	// Put your logic here: check the credentials and initialize the session data.
+	data := {{ if $scheme.SessionData }}&{{ targetPkg }}.{{ goify $scheme.SessionData.TypeName true }}{}{{ else }}map[string]interface{}{}{{ end }}
+
+	// {{ $ctrlName }}_{{ goify .Name true }}: end_implement
+	if err := {{ targetPkg }}.Start{{ goify $scheme.SchemeName true }}Session(ctx, ctx.ResponseData, c.sessions, data); err != nil {
+		return err
+	}
+	return ctx.NoContent()
+}
+`,
+	"logout": `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}{{ $scheme := .Parent.SessionScheme }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	if err := {{ targetPkg }}.End{{ goify $scheme.SchemeName true }}Session(ctx, ctx.ResponseData, c.sessions); err != nil {
+		return err
+	}
+	return ctx.NoContent()
+}
+`,
+}
+
 const actionT = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
 func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
 	// {{ $ctrlName }}_{{ goify .Name true }}: start_implement
@@ -284,6 +680,40 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .
 }
 `
 
+const workflowT = `{{ $name := printf "%sSteps" (goify .Name true) }}// {{ $name }} implements the steps of the {{ printf "%q" .Name }} workflow.
+type {{ $name }} struct {
+}
+
+// New{{ $name }} creates the steps of the {{ printf "%q" .Name }} workflow, run them with
+// {{ targetPkg }}.New{{ goify .Name true }}Workflow.
+func New{{ $name }}() *{{ $name }} {
+	return &{{ $name }}{}
+}
+{{ range .Steps }}
+// {{ goify .Name true }} runs the {{ printf "%q" .Name }} step by calling the {{ .Resource }} {{ .Action }} action.
+func (s *{{ $name }}) {{ goify .Name true }}(ctx context.Context) error {
+	// {{ $name }}_{{ goify .Name true }}: start_implement
+
+	// Put your logic here
+
+	// {{ $name }}_{{ goify .Name true }}: end_implement
+	return nil
+}
+{{ if .CompensationAction }}
+// Compensate{{ goify .Name true }} undoes the {{ printf "%q" .Name }} step by calling the {{ .CompensationResource }} {{ .CompensationAction }} action.
+func (s *{{ $name }}) Compensate{{ goify .Name true }}(ctx context.Context) error {
+	// {{ $name }}_Compensate{{ goify .Name true }}: start_implement
+
+	// Put your logic here
+
+	// {{ $name }}_Compensate{{ goify .Name true }}: end_implement
+	return nil
+}
+{{ end }}{{ end }}
+// Make sure the steps implement the workflow interface.
+var _ {{ targetPkg }}.{{ $name }} = (*{{ $name }})(nil)
+`
+
 const actionWST = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
 func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
 	c.{{ goify .Name true }}WSHandler(ctx).ServeHTTP(ctx.ResponseWriter, ctx.Request)
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_main"
 	"github.com/goadesign/goa/version"
 	. "github.com/onsi/ginkgo"
@@ -56,4 +58,206 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 	})
+
+	Context("with an explicit output package path", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "bottle"}
+			res.Actions = map[string]*design.ActionDefinition{
+				"show": {Name: "show", Parent: res},
+			}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"bottle": res},
+			}
+			delete(codegen.Reserved, "app")
+			os.Args = append(os.Args, "--out-pkg-path=example.com/monorepo/services/test")
+		})
+
+		It("imports the app package using the given path", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"example.com/monorepo/services/test/app"`))
+		})
+	})
+
+	Context("with TLS requirements", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name: "test api",
+				TLS:  &design.TLSDefinition{MinVersion: "1.3", ClientCertRequired: true},
+				Servers: []*design.ServerDefinition{
+					{Name: "production", URL: "https://api.example.com"},
+				},
+			}
+		})
+
+		It("generates a service that listens with the TLS configuration", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("MinVersion: tls.VersionTLS13"))
+			Ω(string(content)).Should(ContainSubstring("cfg.ClientAuth = tls.RequireAndVerifyClientCert"))
+			Ω(string(content)).Should(ContainSubstring(`server.ListenAndServeTLS("server.crt", "server.key")`))
+		})
+
+		It("scaffolds the servers configuration", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "servers.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`url: "https://api.example.com"`))
+		})
+	})
+
+	Context("with managed API keys", func() {
+		BeforeEach(func() {
+			scheme := &design.SecuritySchemeDefinition{
+				SchemeName: "api_key",
+				Kind:       design.APIKeySecurityKind,
+				In:         "header",
+				Name:       "X-Api-Key",
+			}
+			res := &design.ResourceDefinition{
+				Name:     "api_keys",
+				Metadata: dslengine.MetadataDefinition{design.APIKeysMetadata: {"api_key"}},
+			}
+			res.Actions = map[string]*design.ActionDefinition{
+				"show": {Name: "show", Parent: res},
+			}
+			design.Design = &design.APIDefinition{
+				Name:            "test api",
+				SecuritySchemes: []*design.SecuritySchemeDefinition{scheme},
+				Resources:       map[string]*design.ResourceDefinition{"api_keys": res},
+			}
+		})
+
+		It("shares the key store between the middleware and the controller", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("keys := apikey.NewMemoryStore()"))
+			Ω(string(content)).Should(ContainSubstring(`adminKey, err := keys.Create(service.Context, "admin", nil, []string{"admin"})`))
+			Ω(string(content)).Should(MatchRegexp(`app_?\.UseAPIKeyMiddleware\(service, app_?\.NewAPIKeyKeyStoreMiddleware\(keys\)\)`))
+			Ω(string(content)).Should(ContainSubstring("NewAPIKeysController(service, keys)"))
+		})
+
+		It("implements the key management actions", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "api_keys.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("keys apikey.KeyManager"))
+			Ω(string(content)).Should(ContainSubstring("k, err := c.keys.Get(ctx, ctx.KeyID)"))
+		})
+	})
+
+	Context("with an action using the transactional outbox", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{
+				Name:     "bottle",
+				Metadata: dslengine.MetadataDefinition{design.OutboxMetadata: {}},
+			}
+			create := &design.ActionDefinition{Name: "create", Parent: res}
+			create.Routes = []*design.RouteDefinition{{Verb: "POST", Path: "", Parent: create}}
+			res.Actions = map[string]*design.ActionDefinition{"create": create}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"bottle": res},
+			}
+		})
+
+		It("scaffolds the outbox store and relay", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("events := outbox.NewMemoryStore()"))
+			Ω(string(content)).Should(ContainSubstring("outbox.UseStore(service, events)"))
+			Ω(string(content)).Should(ContainSubstring("go relay.Run(service.Context)"))
+		})
+	})
+
+	Context("with a workflow", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "inventory"}
+			reserve := &design.ActionDefinition{Name: "reserve", Parent: res}
+			release := &design.ActionDefinition{Name: "release", Parent: res}
+			res.Actions = map[string]*design.ActionDefinition{"reserve": reserve, "release": release}
+			w := &design.WorkflowDefinition{Name: "order_fulfillment"}
+			w.Steps = []*design.WorkflowStepDefinition{
+				{Name: "reserve", Resource: "inventory", Action: "reserve", CompensationResource: "inventory", CompensationAction: "release", Parent: w},
+			}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"inventory": res},
+				Workflows: []*design.WorkflowDefinition{w},
+			}
+		})
+
+		It("scaffolds the workflow steps", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "order_fulfillment_workflow.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("type OrderFulfillmentSteps struct"))
+			Ω(string(content)).Should(ContainSubstring("func (s *OrderFulfillmentSteps) Reserve(ctx context.Context) error"))
+			Ω(string(content)).Should(ContainSubstring("func (s *OrderFulfillmentSteps) CompensateReserve(ctx context.Context) error"))
+			Ω(string(content)).Should(MatchRegexp(`var _ app_?\.OrderFulfillmentSteps = \(\*OrderFulfillmentSteps\)\(nil\)`))
+		})
+	})
+
+	Context("with a JSON-RPC endpoint", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:    "test api",
+				JSONRPC: &design.JSONRPCDefinition{Path: "/rpc"},
+			}
+		})
+
+		It("mounts the JSON-RPC endpoint", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(MatchRegexp(`app_?\.MountJSONRPC\(service\)`))
+		})
+	})
+
+	Context("with a scheduled action", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "report"}
+			purge := &design.ActionDefinition{Name: "purge", Parent: res}
+			purge.Routes = []*design.RouteDefinition{{Verb: "DELETE", Path: "/expired", Parent: purge}}
+			purge.Schedule = &design.ScheduleDefinition{Spec: "*/5 * * * *", Parent: purge}
+			res.Actions = map[string]*design.ActionDefinition{"purge": purge}
+			design.Design = &design.APIDefinition{
+				Name:      "test api",
+				Resources: map[string]*design.ResourceDefinition{"report": res},
+			}
+		})
+
+		It("runs the scheduler", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(MatchRegexp(`scheduler := app_?\.NewScheduler\(service\)`))
+			Ω(string(content)).Should(ContainSubstring("go scheduler.Run(service.Context)"))
+		})
+	})
+
+	Context("with a h2c server", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name: "test api",
+				Servers: []*design.ServerDefinition{
+					{Name: "local", URL: "http://localhost:8080", Protocol: design.ProtocolH2C},
+				},
+			}
+		})
+
+		It("generates a service that serves HTTP/2 over cleartext TCP", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("Handler: h2c.NewHandler(service.Mux, &http2.Server{}),"))
+			Ω(string(content)).Should(ContainSubstring("server.ListenAndServe()"))
+			Ω(string(content)).ShouldNot(ContainSubstring("tlsConfig()"))
+		})
+	})
 })
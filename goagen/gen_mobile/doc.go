@@ -0,0 +1,11 @@
+/*
+Package genmobile provides a goa generator for mobile data models.
+The generator produces Codable Swift structs and kotlinx.serialization Kotlin data classes for
+the API user types and for each view of the media types, together with the enums defined by the
+attributes validations. Only the models are generated, the transport is left to the application so
+that mobile teams get schema parity with the design without adopting a client library.
+
+The Swift file is generated in the "mobile/swift" directory of the output directory and the Kotlin
+file in the "mobile/kotlin" directory under the path of the Kotlin package.
+*/
+package genmobile
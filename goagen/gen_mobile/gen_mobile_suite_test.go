@@ -0,0 +1,13 @@
+package genmobile_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenMobile(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenMobile Suite")
+}
@@ -0,0 +1,572 @@
+package genmobile
+
+import (
+	"flag"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the mobile models generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Lang     string                // Generated language, "swift", "kotlin" or both if empty
+	Package  string                // Kotlin package of the generated classes
+	genfiles []string              // Generated files
+}
+
+type (
+	// Model holds the data needed to generate a Swift struct or a Kotlin data class.
+	Model struct {
+		Name        string   // Type name
+		Description string   // Type documentation
+		Fields      []*Field // Fields, required fields first
+	}
+
+	// Field is a field of a model.
+	Field struct {
+		Attribute   string // Attribute name used as JSON key
+		Description string // Field documentation
+		Type        *Type  // Field type
+		Required    bool   // Whether the field is required
+	}
+
+	// Enum holds the data needed to generate an enum.
+	Enum struct {
+		Name   string        // Enum name
+		Kind   design.Kind   // Kind of the values, one of StringKind, IntegerKind or NumberKind
+		Values []interface{} // Enum values
+	}
+
+	// Type describes the type of a field independently of the target language.
+	Type struct {
+		Kind design.Kind // Design kind, UserTypeKind for models and enums
+		Name string      // Model or enum name
+		Key  *Type       // Hash key type
+		Elem *Type       // Array or hash element type
+	}
+
+	// builder computes the models and enums generated for the API types.
+	builder struct {
+		api     *design.APIDefinition
+		models  []*Model
+		enums   []*Enum
+		types   map[string]*Type
+		usesAny bool
+		err     error
+	}
+)
+
+var (
+	// identRegex matches the characters that may not appear in identifiers.
+	identRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	// packageRegex matches valid Kotlin package names.
+	packageRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)*$`)
+)
+
+// swiftKeywords lists the Swift reserved words that must be escaped with backticks.
+var swiftKeywords = map[string]bool{
+	"associatedtype": true, "class": true, "deinit": true, "enum": true, "extension": true,
+	"fileprivate": true, "func": true, "import": true, "init": true, "inout": true,
+	"internal": true, "let": true, "open": true, "operator": true, "private": true,
+	"protocol": true, "public": true, "rethrows": true, "static": true, "struct": true,
+	"subscript": true, "typealias": true, "var": true, "break": true, "case": true,
+	"continue": true, "default": true, "defer": true, "do": true, "else": true,
+	"fallthrough": true, "for": true, "guard": true, "if": true, "in": true, "repeat": true,
+	"return": true, "switch": true, "where": true, "while": true, "as": true, "catch": true,
+	"false": true, "is": true, "nil": true, "super": true, "self": true, "Self": true,
+	"throw": true, "throws": true, "true": true, "try": true, "Any": true, "Type": true,
+}
+
+// kotlinKeywords lists the Kotlin hard keywords that must be escaped with backticks.
+var kotlinKeywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true, "else": true,
+	"false": true, "for": true, "fun": true, "if": true, "in": true, "interface": true,
+	"is": true, "null": true, "object": true, "package": true, "return": true, "super": true,
+	"this": true, "throw": true, "true": true, "try": true, "typealias": true, "typeof": true,
+	"val": true, "var": true, "when": true, "while": true,
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, lang, pkg, ver string
+	)
+
+	set := flag.NewFlagSet("mobile", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&lang, "lang", "", "")
+	set.StringVar(&pkg, "pkg", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Lang: lang, Package: pkg, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Swift and Kotlin models.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Lang != "" && g.Lang != "swift" && g.Lang != "kotlin" {
+		return nil, fmt.Errorf(`invalid language %#v, must be "swift" or "kotlin"`, g.Lang)
+	}
+	if g.Package == "" {
+		g.Package = strings.ToLower(identRegex.ReplaceAllString(g.API.Name, "")) + ".models"
+	}
+	if !packageRegex.MatchString(g.Package) {
+		return nil, fmt.Errorf("invalid Kotlin package name %#v", g.Package)
+	}
+	for _, seg := range strings.Split(g.Package, ".") {
+		if kotlinKeywords[seg] {
+			return nil, fmt.Errorf("invalid Kotlin package name %#v: %#v is a reserved word", g.Package, seg)
+		}
+	}
+
+	b := &builder{api: g.API, types: make(map[string]*Type)}
+	b.build()
+	if b.err != nil {
+		return nil, b.err
+	}
+	data := map[string]interface{}{
+		"API":     g.API,
+		"Package": g.Package,
+		"Models":  b.models,
+		"Enums":   b.enums,
+		"UsesAny": b.usesAny,
+	}
+	name := codegen.Goify(g.API.Name, true) + "Models"
+
+	g.OutDir = filepath.Join(g.OutDir, "mobile")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+	if g.Lang == "" || g.Lang == "swift" {
+		dir := filepath.Join(g.OutDir, "swift")
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return
+		}
+		if err = g.generateFile(filepath.Join(dir, name+".swift"), "swift", swiftT, data); err != nil {
+			return
+		}
+	}
+	if g.Lang == "" || g.Lang == "kotlin" {
+		dir := filepath.Join(append([]string{g.OutDir, "kotlin"}, strings.Split(g.Package, ".")...)...)
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return
+		}
+		if err = g.generateFile(filepath.Join(dir, name+".kt"), "kotlin", kotlinT, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+func (g *Generator) generateFile(path, name, tmpl string, data map[string]interface{}) error {
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	funcs := template.FuncMap{
+		"comment":       comment,
+		"quote":         strconv.Quote,
+		"swiftType":     swiftType,
+		"swiftName":     swiftName,
+		"swiftCase":     swiftCase,
+		"swiftRaw":      swiftRaw,
+		"swiftLiteral":  swiftLiteral,
+		"kotlinType":    kotlinType,
+		"kotlinName":    kotlinName,
+		"kotlinConst":   kotlinConst,
+		"kotlinRaw":     kotlinRaw,
+		"kotlinLiteral": kotlinLiteral,
+		"kotlinKind":    kotlinKind,
+		"kotlinString":  kotlinString,
+		"upper":         strings.ToUpper,
+	}
+	return file.ExecuteTemplate(name, tmpl, funcs, data)
+}
+
+// build registers the models and enums of all the user types and media type views.
+func (b *builder) build() {
+	for _, n := range codegen.SortedUserTypeNames(b.api.Types) {
+		b.resolve(&design.AttributeDefinition{Type: b.api.Types[n]}, "")
+	}
+	for _, n := range codegen.SortedMediaTypeNames(b.api.MediaTypes) {
+		mt := b.api.MediaTypes[n]
+		if mt.Identifier == design.ErrorMediaIdentifier {
+			continue
+		}
+		if len(mt.Views) == 0 {
+			b.resolve(&design.AttributeDefinition{Type: mt}, "")
+			continue
+		}
+		mt.IterateViews(func(v *design.ViewDefinition) error {
+			b.view(mt, v.Name)
+			return nil
+		})
+	}
+	sort.Slice(b.models, func(i, j int) bool { return b.models[i].Name < b.models[j].Name })
+	sort.Slice(b.enums, func(i, j int) bool { return b.enums[i].Name < b.enums[j].Name })
+}
+
+// view returns the type of the given view of the given media type, it registers the models
+// needed to represent the projected media type.
+func (b *builder) view(mt *design.MediaTypeDefinition, view string) *Type {
+	if _, ok := mt.Views[view]; !ok || projected(mt) {
+		return b.named(mt.UserTypeDefinition)
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("%s view %#v: %s", mt.TypeName, view, err)
+		}
+		return &Type{Kind: design.AnyKind}
+	}
+	return b.named(p.UserTypeDefinition)
+}
+
+// resolve computes the type of the given attribute. class is the name of the enum or model
+// generated for the attribute if it defines an enum or an inline object. It registers the models
+// and enums needed to represent the type.
+func (b *builder) resolve(att *design.AttributeDefinition, class string) *Type {
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		return b.view(actual, design.DefaultView)
+	case *design.UserTypeDefinition:
+		return b.named(actual)
+	case *design.ScalarDefinition:
+		wire := &design.AttributeDefinition{Type: actual.Primitive, Validation: att.Validation}
+		if len(actual.Values) == 0 {
+			return b.resolve(wire, class)
+		}
+		name := codegen.Goify(actual.TypeName, true)
+		b.enum(name, actual.Primitive.Kind(), actual.Values)
+		return &Type{Kind: design.UserTypeKind, Name: name}
+	case *design.FlagsDefinition:
+		name := codegen.Goify(actual.TypeName, true)
+		values := make([]interface{}, len(actual.Names))
+		for i, n := range actual.Names {
+			values[i] = n
+		}
+		b.enum(name, design.StringKind, values)
+		return &Type{Kind: design.ArrayKind, Elem: &Type{Kind: design.UserTypeKind, Name: name}}
+	case *design.Array:
+		return &Type{Kind: design.ArrayKind, Elem: b.resolve(actual.ElemType, class)}
+	case *design.Hash:
+		return &Type{
+			Kind: design.HashKind,
+			Key:  b.resolve(actual.KeyType, class+"Key"),
+			Elem: b.resolve(actual.ElemType, class),
+		}
+	case design.Object:
+		b.model(class, att.Description, att)
+		return &Type{Kind: design.UserTypeKind, Name: class}
+	case design.Primitive:
+		kind := actual.Kind()
+		if v := att.Validation; v != nil && len(v.Values) > 0 && class != "" {
+			switch kind {
+			case design.IntegerKind, design.NumberKind, design.StringKind:
+				b.enum(class, kind, v.Values)
+				return &Type{Kind: design.UserTypeKind, Name: class}
+			}
+		}
+		switch kind {
+		case design.BooleanKind, design.IntegerKind, design.NumberKind, design.StringKind,
+			design.DateTimeKind, design.UUIDKind:
+			return &Type{Kind: kind}
+		}
+	}
+	b.usesAny = true
+	return &Type{Kind: design.AnyKind}
+}
+
+// named returns the type of the given user type, it registers the corresponding model if the
+// type is an object.
+func (b *builder) named(ut *design.UserTypeDefinition) *Type {
+	class := codegen.Goify(ut.TypeName, true)
+	if t, ok := b.types[class]; ok {
+		if t.Kind == design.AnyKind {
+			b.usesAny = true
+		}
+		return t
+	}
+	if !ut.Type.IsObject() {
+		b.types[class] = &Type{Kind: design.AnyKind} // guard against recursive types
+		t := b.resolve(ut.AttributeDefinition, class)
+		b.types[class] = t
+		return t
+	}
+	t := &Type{Kind: design.UserTypeKind, Name: class}
+	b.types[class] = t
+	b.model(class, ut.Description, ut.AttributeDefinition)
+	return t
+}
+
+// model registers the model with the given name describing the given object attribute.
+func (b *builder) model(name, description string, att *design.AttributeDefinition) {
+	for _, m := range b.models {
+		if m.Name == name {
+			return
+		}
+	}
+	if description == "" {
+		description = name + " is a data structure of the " + b.api.Name + " API."
+	}
+	m := &Model{Name: name, Description: description}
+	b.models = append(b.models, m)
+	o := att.Type.ToObject()
+	var required, optional []*Field
+	for _, n := range codegen.SortedAttributeNames(o) {
+		f := &Field{
+			Attribute:   n,
+			Description: o[n].Description,
+			Type:        b.resolve(o[n], name+codegen.Goify(n, true)),
+			Required:    att.IsRequired(n),
+		}
+		if f.Required {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+	m.Fields = append(required, optional...)
+}
+
+// enum registers the enum with the given name, value kind and values.
+func (b *builder) enum(name string, kind design.Kind, values []interface{}) {
+	for _, e := range b.enums {
+		if e.Name == name {
+			return
+		}
+	}
+	switch kind {
+	case design.IntegerKind, design.NumberKind:
+	default:
+		kind = design.StringKind
+	}
+	b.enums = append(b.enums, &Enum{Name: name, Kind: kind, Values: values})
+}
+
+// swiftType returns the Swift type of t.
+func swiftType(t *Type) string {
+	switch t.Kind {
+	case design.UserTypeKind:
+		return t.Name
+	case design.BooleanKind:
+		return "Bool"
+	case design.IntegerKind:
+		return "Int"
+	case design.NumberKind:
+		return "Double"
+	case design.StringKind, design.DateTimeKind:
+		return "String"
+	case design.UUIDKind:
+		return "UUID"
+	case design.ArrayKind:
+		return "[" + swiftType(t.Elem) + "]"
+	case design.HashKind:
+		return "[" + swiftType(t.Key) + ": " + swiftType(t.Elem) + "]"
+	}
+	return "JSONValue"
+}
+
+// kotlinType returns the Kotlin type of t.
+func kotlinType(t *Type) string {
+	switch t.Kind {
+	case design.UserTypeKind:
+		return t.Name
+	case design.BooleanKind:
+		return "Boolean"
+	case design.IntegerKind:
+		return "Long"
+	case design.NumberKind:
+		return "Double"
+	case design.StringKind, design.DateTimeKind, design.UUIDKind:
+		return "String"
+	case design.ArrayKind:
+		return "List<" + kotlinType(t.Elem) + ">"
+	case design.HashKind:
+		return "Map<" + kotlinType(t.Key) + ", " + kotlinType(t.Elem) + ">"
+	}
+	return "JsonElement"
+}
+
+// swiftName returns the Swift property name of the given attribute.
+func swiftName(att string) string {
+	name := codegen.CamelIdentifier(att, false)
+	if swiftKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// kotlinName returns the Kotlin property name of the given attribute.
+func kotlinName(att string) string {
+	name := codegen.CamelIdentifier(att, false)
+	if kotlinKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// swiftCase returns the Swift enum case names of the given values.
+func swiftCase(values []interface{}) []string {
+	names := make([]string, len(values))
+	seen := make(map[string]bool)
+	for i, v := range values {
+		name := codegen.CamelIdentifier(fmt.Sprintf("%v", v), false)
+		if _, ok := v.(string); !ok || name[0] == '_' {
+			name = codegen.CamelIdentifier("value_"+fmt.Sprintf("%v", v), false)
+		}
+		for seen[name] {
+			name += "_"
+		}
+		seen[name] = true
+		if swiftKeywords[name] {
+			name = "`" + name + "`"
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// kotlinConst returns the Kotlin enum constant names of the given values.
+func kotlinConst(values []interface{}) []string {
+	names := make([]string, len(values))
+	seen := make(map[string]bool)
+	for i, v := range values {
+		name := strings.ToUpper(codegen.SnakeIdentifier(fmt.Sprintf("%v", v)))
+		if _, ok := v.(string); !ok || name == "" || name[0] >= '0' && name[0] <= '9' {
+			name = "VALUE_" + name
+		}
+		for seen[name] {
+			name += "_"
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// swiftRaw returns the Swift raw type of the enums of the given kind.
+func swiftRaw(k design.Kind) string {
+	return swiftType(&Type{Kind: k})
+}
+
+// kotlinRaw returns the Kotlin type of the values of the enums of the given kind.
+func kotlinRaw(k design.Kind) string {
+	return kotlinType(&Type{Kind: k})
+}
+
+// kotlinKind returns the name of the kotlinx.serialization PrimitiveKind and of the
+// encoder and decoder methods used to serialize the enums of the given kind.
+func kotlinKind(k design.Kind) string {
+	switch k {
+	case design.IntegerKind:
+		return "Long"
+	case design.NumberKind:
+		return "Double"
+	}
+	return "String"
+}
+
+// swiftLiteral returns the Swift literal of the given enum value.
+func swiftLiteral(k design.Kind, v interface{}) string {
+	switch k {
+	case design.IntegerKind:
+		return fmt.Sprintf("%d", toInt(v))
+	case design.NumberKind:
+		return floatLiteral(v)
+	}
+	return strconv.Quote(fmt.Sprintf("%v", v))
+}
+
+// kotlinLiteral returns the Kotlin literal of the given enum value.
+func kotlinLiteral(k design.Kind, v interface{}) string {
+	switch k {
+	case design.IntegerKind:
+		return fmt.Sprintf("%dL", toInt(v))
+	case design.NumberKind:
+		return floatLiteral(v)
+	}
+	return kotlinString(fmt.Sprintf("%v", v))
+}
+
+// kotlinString returns the Kotlin string literal of the given string.
+func kotlinString(s string) string {
+	return strings.Replace(strconv.Quote(s), "$", `\$`, -1)
+}
+
+// toInt converts the given design integer value to int64.
+func toInt(v interface{}) int64 {
+	switch val := v.(type) {
+	case int:
+		return int64(val)
+	case int64:
+		return val
+	case float64:
+		return int64(val)
+	}
+	i, _ := strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64)
+	return i
+}
+
+// floatLiteral returns the floating point literal of the given design number value.
+func floatLiteral(v interface{}) string {
+	f, _ := strconv.ParseFloat(fmt.Sprintf("%v", v), 64)
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// projected returns true if the given media type is the result of a projection.
+func projected(mt *design.MediaTypeDefinition) bool {
+	_, params, err := mime.ParseMediaType(mt.Identifier)
+	return err == nil && params["view"] != ""
+}
+
+// comment returns the given text formatted as a documentation comment with the given line
+// prefix.
+func comment(prefix, text string) string {
+	text = strings.Replace(strings.TrimSpace(text), "*/", "*&#47;", -1)
+	return strings.Replace(text, "\n", "\n"+prefix, -1)
+}
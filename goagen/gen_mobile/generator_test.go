@@ -0,0 +1,155 @@
+package genmobile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_mobile"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_mobile/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String()}
+		design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genmobile.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a media type with views and a user type", func() {
+		BeforeEach(func() {
+			bottle := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Bottle",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":   &design.AttributeDefinition{Type: design.Integer, Description: "Unique bottle ID"},
+							"name": &design.AttributeDefinition{Type: design.String},
+							"color": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+							},
+							"extra": &design.AttributeDefinition{Type: design.Any},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+					},
+				},
+				Identifier: "application/vnd.bottle",
+			}
+			bottle.Views = map[string]*design.ViewDefinition{
+				"default": {
+					Name:   "default",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id":    &design.AttributeDefinition{Type: design.Integer},
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"color": &design.AttributeDefinition{Type: design.String},
+						"extra": &design.AttributeDefinition{Type: design.Any},
+					}},
+				},
+				"tiny": {
+					Name:   "tiny",
+					Parent: bottle,
+					AttributeDefinition: &design.AttributeDefinition{Type: design.Object{
+						"id": &design.AttributeDefinition{Type: design.Integer},
+					}},
+				},
+			}
+			payload := &design.UserTypeDefinition{
+				TypeName: "BottlePayload",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name": &design.AttributeDefinition{Type: design.String},
+						"rating": &design.AttributeDefinition{
+							Type:       design.Integer,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{1, 2, 3}},
+						},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:       "cellar",
+				Types:      map[string]*design.UserTypeDefinition{"BottlePayload": payload},
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.bottle": bottle},
+			}
+		})
+
+		It("generates the Swift models", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "mobile", "swift", "CellarModels.swift"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("public enum BottleColor: String, Codable, CaseIterable {\n    case red = \"red\"\n    case white = \"white\"\n}"))
+			Ω(string(content)).Should(ContainSubstring("public enum BottlePayloadRating: Int, Codable, CaseIterable {\n    case value1 = 1\n"))
+			Ω(string(content)).Should(ContainSubstring("public struct Bottle: Codable, Equatable {"))
+			Ω(string(content)).Should(ContainSubstring("    /// Unique bottle ID\n    public var id: Int\n"))
+			Ω(string(content)).Should(ContainSubstring("    public var color: BottleColor?\n"))
+			Ω(string(content)).Should(ContainSubstring("    public var extra: JSONValue?\n"))
+			Ω(string(content)).Should(ContainSubstring("public struct BottleTiny: Codable, Equatable {\n    /// Unique bottle ID\n    public var id: Int\n"))
+			Ω(string(content)).Should(ContainSubstring("public init(name: String, rating: BottlePayloadRating? = nil) {"))
+			Ω(string(content)).Should(ContainSubstring("public enum JSONValue: Codable, Equatable {"))
+		})
+
+		It("generates the Kotlin models", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "mobile", "kotlin", "cellar", "models", "CellarModels.kt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package cellar.models\n"))
+			Ω(string(content)).Should(ContainSubstring("enum class BottleColor(val value: String) {\n    RED(\"red\"),\n    WHITE(\"white\"),\n    ;"))
+			Ω(string(content)).Should(ContainSubstring("enum class BottlePayloadRating(val value: Long) {\n    VALUE_1(1L),"))
+			Ω(string(content)).Should(ContainSubstring("encoder.encodeLong(value.value)"))
+			Ω(string(content)).Should(ContainSubstring("@Serializable\ndata class Bottle(\n"))
+			Ω(string(content)).Should(ContainSubstring("    @SerialName(\"id\")\n    val id: Long,\n"))
+			Ω(string(content)).Should(ContainSubstring("    @SerialName(\"color\")\n    val color: BottleColor? = null,\n"))
+			Ω(string(content)).Should(ContainSubstring("    val extra: JsonElement? = null,\n"))
+			Ω(string(content)).Should(ContainSubstring("data class BottleTiny(\n"))
+		})
+	})
+
+	Context("with a language", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{Name: "cellar"}
+			os.Args = append(os.Args, "--lang=kotlin")
+		})
+
+		It("generates only the models of that language", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			_, err := os.Stat(filepath.Join(outDir, "mobile", "swift"))
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+		})
+	})
+
+	Context("with an invalid language", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{Name: "cellar"}
+			os.Args = append(os.Args, "--lang=dart")
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(MatchError(ContainSubstring("invalid language")))
+		})
+	})
+})
@@ -0,0 +1,131 @@
+package genmobile
+
+const swiftT = `// Code generated by goagen, DO NOT EDIT.
+//
+// Models of the {{ .API.Name }} API. Date-time attributes are represented with their RFC 3339
+// string representation.
+
+import Foundation
+{{ range .Enums }}{{ $e := . }}
+/// {{ .Name }} enumerates the values allowed by the design.
+public enum {{ .Name }}: {{ swiftRaw .Kind }}, Codable, CaseIterable {
+{{ range $i, $c := swiftCase .Values }}    case {{ $c }} = {{ swiftLiteral $e.Kind (index $e.Values $i) }}
+{{ end }}}
+{{ end }}{{ range .Models }}
+/// {{ comment "/// " .Description }}
+public struct {{ .Name }}: Codable, Equatable {
+{{ range .Fields }}{{ if .Description }}    /// {{ comment "    /// " .Description }}
+{{ end }}    public var {{ swiftName .Attribute }}: {{ swiftType .Type }}{{ if not .Required }}?{{ end }}
+{{ end }}{{ if .Fields }}
+    enum CodingKeys: String, CodingKey {
+{{ range .Fields }}        case {{ swiftName .Attribute }} = {{ quote .Attribute }}
+{{ end }}    }
+{{ end }}
+    public init({{ if gt (len .Fields) 3 }}{{ range $i, $f := .Fields }}{{ if $i }},{{ end }}
+        {{ swiftName $f.Attribute }}: {{ swiftType $f.Type }}{{ if not $f.Required }}? = nil{{ end }}{{ end }}
+    {{ else }}{{ range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ swiftName $f.Attribute }}: {{ swiftType $f.Type }}{{ if not $f.Required }}? = nil{{ end }}{{ end }}{{ end }}) {
+{{ range .Fields }}        self.{{ swiftName .Attribute }} = {{ swiftName .Attribute }}
+{{ end }}    }
+}
+{{ end }}{{ if .UsesAny }}
+/// JSONValue holds the values of the attributes of type Any.
+public enum JSONValue: Codable, Equatable {
+    case null
+    case bool(Bool)
+    case number(Double)
+    case string(String)
+    case array([JSONValue])
+    case object([String: JSONValue])
+
+    public init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        if container.decodeNil() {
+            self = .null
+        } else if let value = try? container.decode(Bool.self) {
+            self = .bool(value)
+        } else if let value = try? container.decode(Double.self) {
+            self = .number(value)
+        } else if let value = try? container.decode(String.self) {
+            self = .string(value)
+        } else if let value = try? container.decode([JSONValue].self) {
+            self = .array(value)
+        } else {
+            self = .object(try container.decode([String: JSONValue].self))
+        }
+    }
+
+    public func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        switch self {
+        case .null:
+            try container.encodeNil()
+        case .bool(let value):
+            try container.encode(value)
+        case .number(let value):
+            try container.encode(value)
+        case .string(let value):
+            try container.encode(value)
+        case .array(let value):
+            try container.encode(value)
+        case .object(let value):
+            try container.encode(value)
+        }
+    }
+}
+{{ end }}`
+
+const kotlinT = `// Code generated by goagen, DO NOT EDIT.
+//
+// Models of the {{ .API.Name }} API. Date-time attributes are represented with their RFC 3339
+// string representation.
+package {{ .Package }}
+
+import kotlinx.serialization.KSerializer
+import kotlinx.serialization.SerialName
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.descriptors.PrimitiveKind
+import kotlinx.serialization.descriptors.PrimitiveSerialDescriptor
+import kotlinx.serialization.descriptors.SerialDescriptor
+import kotlinx.serialization.encoding.Decoder
+import kotlinx.serialization.encoding.Encoder
+import kotlinx.serialization.json.JsonElement
+{{ range .Enums }}{{ $e := . }}
+/**
+ * {{ .Name }} enumerates the values allowed by the design.
+ */
+@Serializable(with = {{ .Name }}.Serializer::class)
+enum class {{ .Name }}(val value: {{ kotlinRaw .Kind }}) {
+{{ range $i, $c := kotlinConst .Values }}    {{ $c }}({{ kotlinLiteral $e.Kind (index $e.Values $i) }}),
+{{ end }}    ;
+
+    /**
+     * Serializer encodes the constants with their JSON value.
+     */
+    object Serializer : KSerializer<{{ .Name }}> {
+        override val descriptor: SerialDescriptor =
+            PrimitiveSerialDescriptor({{ kotlinString (printf "%s.%s" $.Package .Name) }}, PrimitiveKind.{{ upper (kotlinKind .Kind) }})
+
+        override fun serialize(encoder: Encoder, value: {{ .Name }}) {
+            encoder.encode{{ kotlinKind .Kind }}(value.value)
+        }
+
+        override fun deserialize(decoder: Decoder): {{ .Name }} {
+            val value = decoder.decode{{ kotlinKind .Kind }}()
+            return values().firstOrNull { it.value == value }
+                ?: throw IllegalArgumentException("unexpected {{ .Name }} value $value")
+        }
+    }
+}
+{{ end }}{{ range .Models }}
+/**
+ * {{ comment " * " .Description }}
+ */
+@Serializable
+{{ if .Fields }}data class {{ .Name }}(
+{{ range .Fields }}{{ if .Description }}    /**
+     * {{ comment "     * " .Description }}
+     */
+{{ end }}    @SerialName({{ kotlinString .Attribute }})
+    val {{ kotlinName .Attribute }}: {{ kotlinType .Type }}{{ if not .Required }}? = null{{ end }},
+{{ end }}){{ else }}class {{ .Name }}{{ end }}
+{{ end }}`
@@ -0,0 +1,13 @@
+/*
+Package genpii provides a goa generator for PII data-flow reports.
+The generator produces a report listing, for each class of personally identifiable information
+defined with the PII DSL, the endpoints that receive the class in their requests (parameters,
+headers or payload) and the endpoints that return it in their responses (headers or body). The
+report is meant to support privacy reviews such as GDPR records of processing or SOC2 audits.
+
+The report is generated in Markdown (pii/report.md) for humans and in JSON (pii/report.json) for
+tooling:
+
+	goagen pii -d github.com/example/cellar/design
+*/
+package genpii
@@ -0,0 +1,13 @@
+package genpii_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenPII(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenPII Suite")
+}
@@ -0,0 +1,330 @@
+package genpii
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the PII data-flow report generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	genfiles []string              // Generated files
+}
+
+type (
+	// Flow lists the endpoints that receive or return a class of PII.
+	Flow struct {
+		// Class is the PII class.
+		Class string `json:"class"`
+		// Endpoints lists the endpoints handling the class sorted by resource and action.
+		Endpoints []*Endpoint `json:"endpoints"`
+	}
+
+	// Endpoint describes how an endpoint handles a class of PII.
+	Endpoint struct {
+		// Resource is the name of the resource.
+		Resource string `json:"resource"`
+		// Action is the name of the action.
+		Action string `json:"action"`
+		// Routes lists the action routes, e.g. "POST /users".
+		Routes []string `json:"routes"`
+		// Receives lists the request attributes holding the PII, e.g. "payload.email".
+		Receives []string `json:"receives,omitempty"`
+		// Returns lists the response attributes holding the PII, e.g. "OK.email".
+		Returns []string `json:"returns,omitempty"`
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+
+	set := flag.NewFlagSet("pii", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the PII data-flow report in Markdown and JSON.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	g.OutDir = filepath.Join(g.OutDir, "pii")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	flows, err := Flows(g.API)
+	if err != nil {
+		return
+	}
+	if err = g.generateMarkdown(filepath.Join(g.OutDir, "report.md"), flows); err != nil {
+		return
+	}
+	if err = g.generateJSON(filepath.Join(g.OutDir, "report.json"), flows); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// Flows computes the PII data flows of the given API sorted by class.
+func Flows(api *design.APIDefinition) ([]*Flow, error) {
+	byClass := make(map[string]*Flow)
+	err := api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			endpoints := make(map[string]*Endpoint)
+			endpoint := func(class string) *Endpoint {
+				if e, ok := endpoints[class]; ok {
+					return e
+				}
+				e := &Endpoint{Resource: res.Name, Action: action.Name}
+				for _, r := range action.Routes {
+					e.Routes = append(e.Routes, r.Verb+" "+r.FullPath())
+				}
+				endpoints[class] = e
+				return e
+			}
+			receive := func(path, class string) {
+				e := endpoint(class)
+				e.Receives = append(e.Receives, path)
+			}
+			walk(action.AllParams(), "params", receive)
+			walk(res.Headers, "headers", receive)
+			walk(action.Headers, "headers", receive)
+			if action.Payload != nil {
+				walk(action.Payload.AttributeDefinition, "payload", receive)
+			}
+			err := action.IterateResponses(func(resp *design.ResponseDefinition) error {
+				ret := func(path, class string) {
+					e := endpoint(class)
+					e.Returns = append(e.Returns, path)
+				}
+				walk(resp.Headers, resp.Name+".headers", ret)
+				if att := responseAttribute(api, resp); att != nil {
+					walk(att, resp.Name, ret)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			for class, e := range endpoints {
+				f, ok := byClass[class]
+				if !ok {
+					f = &Flow{Class: class}
+					byClass[class] = f
+				}
+				e.Receives = dedup(e.Receives)
+				e.Returns = dedup(e.Returns)
+				f.Endpoints = append(f.Endpoints, e)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	classes := make([]string, 0, len(byClass))
+	for c := range byClass {
+		classes = append(classes, c)
+	}
+	sort.Strings(classes)
+	flows := make([]*Flow, len(classes))
+	for i, c := range classes {
+		flows[i] = byClass[c]
+	}
+	return flows, nil
+}
+
+// responseAttribute returns the attribute describing the body of the given response using the
+// response view if any, nil if the response has no body media type.
+func responseAttribute(api *design.APIDefinition, resp *design.ResponseDefinition) *design.AttributeDefinition {
+	if resp.Type != nil {
+		if mt, ok := resp.Type.(*design.MediaTypeDefinition); ok {
+			return viewAttribute(mt, resp.ViewName)
+		}
+		return &design.AttributeDefinition{Type: resp.Type}
+	}
+	if resp.MediaType == "" {
+		return nil
+	}
+	mt := api.MediaTypeWithIdentifier(resp.MediaType)
+	if mt == nil {
+		return nil
+	}
+	return viewAttribute(mt, resp.ViewName)
+}
+
+// viewAttribute returns the attribute of the given view of the media type, the media type
+// attribute if there is no such view. The attribute of collection views is an array of the element
+// view attributes.
+func viewAttribute(mt *design.MediaTypeDefinition, view string) *design.AttributeDefinition {
+	if view == "" {
+		view = design.DefaultView
+	}
+	if mt.IsArray() {
+		if elem, ok := mt.ToArray().ElemType.Type.(*design.MediaTypeDefinition); ok {
+			return &design.AttributeDefinition{
+				Type: &design.Array{ElemType: viewAttribute(elem, view)},
+			}
+		}
+	}
+	if v, ok := mt.Views[view]; ok && v.AttributeDefinition != nil {
+		return v.AttributeDefinition
+	}
+	return mt.AttributeDefinition
+}
+
+// walk calls fn with the path and class of each classified attribute nested in att. Array elements
+// are denoted with "[]" and map values with "{}" in the paths.
+func walk(att *design.AttributeDefinition, path string, fn func(path, class string)) {
+	walkAttribute(att, path, fn, make(map[string]bool))
+}
+
+func walkAttribute(att *design.AttributeDefinition, path string, fn func(path, class string), seen map[string]bool) {
+	if att == nil || att.Type == nil {
+		return
+	}
+	if class := att.PIIClass(); class != "" {
+		fn(path, class)
+	}
+	switch actual := att.Type.(type) {
+	case design.Object:
+		names := make([]string, 0, len(actual))
+		for n := range actual {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			walkAttribute(actual[n], path+"."+n, fn, seen)
+		}
+	case *design.Array:
+		walkAttribute(actual.ElemType, path+"[]", fn, seen)
+	case *design.Hash:
+		walkAttribute(actual.ElemType, path+"{}", fn, seen)
+	case *design.UserTypeDefinition:
+		walkUserType(actual, path, fn, seen)
+	case *design.MediaTypeDefinition:
+		walkUserType(actual.UserTypeDefinition, path, fn, seen)
+	}
+}
+
+func walkUserType(ut *design.UserTypeDefinition, path string, fn func(path, class string), seen map[string]bool) {
+	if ut == nil || seen[ut.TypeName] {
+		return
+	}
+	seen[ut.TypeName] = true
+	defer delete(seen, ut.TypeName)
+	walkAttribute(ut.AttributeDefinition, path, fn, seen)
+}
+
+// dedup returns the sorted unique values of the given slice.
+func dedup(vals []string) []string {
+	if len(vals) == 0 {
+		return nil
+	}
+	sort.Strings(vals)
+	res := vals[:1]
+	for _, v := range vals[1:] {
+		if v != res[len(res)-1] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func (g *Generator) generateMarkdown(reportFile string, flows []*Flow) error {
+	file, err := codegen.SourceFileFor(reportFile)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, reportFile)
+	data := map[string]interface{}{
+		"API":   g.API,
+		"Flows": flows,
+	}
+	return file.ExecuteTemplate("report", reportT, funcMap, data)
+}
+
+func (g *Generator) generateJSON(reportFile string, flows []*Flow) error {
+	if flows == nil {
+		flows = []*Flow{}
+	}
+	b, err := json.MarshalIndent(flows, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(reportFile, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, reportFile)
+	return nil
+}
+
+// funcMap is the template function map used to render the Markdown report.
+var funcMap = template.FuncMap{
+	"join": func(vals []string) string {
+		if len(vals) == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("`%s`", strings.Join(vals, "`, `"))
+	},
+}
+
+const reportT = `# PII data-flow report for the {{ .API.Name }} API
+
+Generated by goagen, DO NOT MODIFY.
+
+This report lists the endpoints that receive or return each class of personally identifiable
+information defined with the PII DSL.
+{{ if not .Flows }}
+The design does not classify any attribute.
+{{ end }}{{ range .Flows }}
+## {{ .Class }}
+
+| Resource | Action | Routes | Receives | Returns |
+| -------- | ------ | ------ | -------- | ------- |
+{{ range .Endpoints }}| {{ .Resource }} | {{ .Action }} | {{ join .Routes }} | {{ join .Receives }} | {{ join .Returns }} |
+{{ end }}{{ end }}`
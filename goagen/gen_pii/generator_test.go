@@ -0,0 +1,129 @@
+package genpii_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_pii"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_pii/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String()}
+		dslengine.Reset()
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpii.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with classified attributes", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			user := MediaType("application/vnd.user", func() {
+				Attributes(func() {
+					Attribute("id", design.Integer)
+					Attribute("email", design.String, func() {
+						PII("email")
+					})
+					Attribute("phones", ArrayOf(design.String), func() {
+						PII("phone")
+					})
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("email")
+				})
+				View("full", func() {
+					Attribute("id")
+					Attribute("email")
+					Attribute("phones")
+				})
+			})
+			Resource("user", func() {
+				BasePath("/users")
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Attribute("email", design.String, func() {
+							PII("email")
+						})
+					})
+					Response(design.Created)
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(design.OK, func() {
+						Media(user, "full")
+					})
+				})
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Param("email", design.String, func() {
+							PII("email")
+						})
+					})
+					Response(design.OK, func() {
+						Media(CollectionOf(user))
+					})
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("computes the data flows", func() {
+			flows, err := genpii.Flows(design.Design)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(flows).Should(HaveLen(2))
+			Ω(flows[0].Class).Should(Equal("email"))
+			Ω(flows[0].Endpoints).Should(HaveLen(3))
+			Ω(flows[0].Endpoints[0].Action).Should(Equal("create"))
+			Ω(flows[0].Endpoints[0].Receives).Should(Equal([]string{"payload.email"}))
+			Ω(flows[0].Endpoints[0].Returns).Should(BeEmpty())
+			Ω(flows[0].Endpoints[1].Action).Should(Equal("list"))
+			Ω(flows[0].Endpoints[1].Receives).Should(Equal([]string{"params.email"}))
+			Ω(flows[0].Endpoints[1].Returns).Should(Equal([]string{"OK[].email"}))
+			Ω(flows[1].Class).Should(Equal("phone"))
+			Ω(flows[1].Endpoints).Should(HaveLen(1))
+			Ω(flows[1].Endpoints[0].Action).Should(Equal("show"))
+			Ω(flows[1].Endpoints[0].Returns).Should(Equal([]string{"OK.phones"}))
+		})
+
+		It("generates the reports", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "pii", "report.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("## email"))
+			Ω(string(content)).Should(ContainSubstring("| user | create | `POST /users` | `payload.email` | - |"))
+			Ω(string(content)).Should(ContainSubstring("| user | show | `GET /users/:id` | - | `OK.email` |"))
+			Ω(string(content)).Should(ContainSubstring("## phone"))
+			Ω(string(content)).Should(ContainSubstring("| user | show | `GET /users/:id` | - | `OK.phones` |"))
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "pii", "report.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`"class": "phone"`))
+		})
+	})
+})
@@ -0,0 +1,17 @@
+/*
+Package genpython provides a goa generator for Python client packages.
+The generator produces a Python package with one dataclass per user type and media type, one enum
+class per enumerated attribute and a Client class exposing one method per API endpoint. The
+methods check the arguments against the design validations, encode the payloads and decode the
+responses into the dataclasses. The generated code depends on requests by default but the client
+accepts any session object with a compatible request method such as an httpx.Client:
+
+	from cellar_client import Client
+
+	client = Client("http://localhost:8080", credentials={"jwt": token})
+	bottle = client.show_bottle(42)
+
+The package is generated in the "python" directory of the output directory together with a
+pyproject.toml file so that it can be installed with pip.
+*/
+package genpython
@@ -0,0 +1,13 @@
+package genpython_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenPython(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenPython Suite")
+}
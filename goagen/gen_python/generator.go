@@ -0,0 +1,732 @@
+package genpython
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Python client package generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Package  string                // Name of the generated Python package
+	Scheme   string                // Scheme used to make requests
+	Host     string                // Host addressed by requests
+	genfiles []string              // Generated files
+}
+
+type (
+	// Model holds the data needed to generate a dataclass.
+	Model struct {
+		Name        string   // Python class name
+		Description string   // Class docstring
+		Fields      []*Field // Class fields, required fields first
+	}
+
+	// Field holds the data needed to generate a dataclass field.
+	Field struct {
+		Name     string   // Python field name
+		Key      string   // Quoted JSON key
+		Hint     string   // Python type hint
+		Required bool     // Whether the field is required
+		Decode   string   // Expression decoding the JSON value held in d[Key]
+		Encode   string   // Expression encoding the field value
+		Checks   []string // Validation statements indented for the validate method
+	}
+
+	// Enum holds the data needed to generate an enum class.
+	Enum struct {
+		Name    string        // Python class name
+		Base    string        // Python base type of the values
+		Members []*EnumMember // Enum members
+	}
+
+	// EnumMember is a member of an enum class.
+	EnumMember struct {
+		Name  string // Python member name
+		Value string // Python literal value
+	}
+
+	// Endpoint holds the data needed to generate the client method of an action.
+	Endpoint struct {
+		Name        string   // Python method name
+		Description string   // Method docstring
+		Verb        string   // HTTP method
+		Path        string   // Design request path
+		PathFormat  string   // Python format string of the request path
+		PathArgs    []string // Python expressions of the path format arguments
+		Args        []string // Method arguments
+		Params      []*Param // Query string parameters
+		Headers     []*Param // Request headers
+		Checks      []string // Validation statements indented for the method body
+		Body        string   // Expression encoding the request body if any
+		Auth        string   // Python tuple describing the request credentials if any
+		Statuses    string   // Python tuple of the successful response statuses if any
+		Result      string   // Python type hint of the result
+		Decode      string   // Expression decoding the response body held in resp.json()
+	}
+
+	// Param is a query string parameter or a request header.
+	Param struct {
+		Name string // Quoted parameter or header name
+		Expr string // Python expression of the value
+	}
+
+	// pyType describes how a design type maps to Python.
+	pyType struct {
+		Hint  string                      // Python type hint
+		Kind  design.Kind                 // Design kind, UserTypeKind for dataclasses
+		Class string                      // Enum or dataclass name if any
+		Enum  bool                        // Whether Class is an enum class
+		Elem  *pyType                     // Array or hash element type
+		Att   *design.AttributeDefinition // Attribute holding the validations
+		Model *design.AttributeDefinition // Underlying attribute of dataclass types
+	}
+
+	// builder computes the models and enums used by the generated code.
+	builder struct {
+		api    *design.APIDefinition
+		models []*Model
+		enums  []*Enum
+		types  map[string]*pyType
+	}
+)
+
+// pyKeywords lists the Python reserved words.
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true, "self": true,
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, pkg, scheme, host, ver string
+	)
+
+	set := flag.NewFlagSet("python", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&pkg, "pkg", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Package: pkg, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Python client package.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Package == "" {
+		g.Package = pyName(g.API.Name) + "_client"
+	}
+	if pyName(g.Package) != g.Package {
+		return nil, fmt.Errorf("invalid Python package name %#v", g.Package)
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost:8080"
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "python")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	pkgDir := filepath.Join(g.OutDir, g.Package)
+	if err = os.MkdirAll(pkgDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	b := &builder{api: g.API, types: make(map[string]*pyType)}
+	endpoints, err := b.endpoints()
+	if err != nil {
+		return
+	}
+	data := map[string]interface{}{
+		"API":       g.API,
+		"Package":   g.Package,
+		"BaseURL":   g.Scheme + "://" + g.Host,
+		"Models":    b.models,
+		"Enums":     b.enums,
+		"Endpoints": endpoints,
+	}
+	files := []struct {
+		path, name, tmpl string
+	}{
+		{filepath.Join(g.OutDir, "pyproject.toml"), "pyproject", pyprojectT},
+		{filepath.Join(pkgDir, "__init__.py"), "init", initT},
+		{filepath.Join(pkgDir, "models.py"), "models", modelsT},
+		{filepath.Join(pkgDir, "client.py"), "client", clientT},
+	}
+	for _, f := range files {
+		if err = g.generateFile(f.path, f.name, f.tmpl, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+func (g *Generator) generateFile(path, name, tmpl string, data map[string]interface{}) error {
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	funcs := template.FuncMap{"join": strings.Join, "docstring": docstring}
+	return file.ExecuteTemplate(name, tmpl, funcs, data)
+}
+
+// endpoints computes the data needed to generate the client methods, it registers the models and
+// enums used by the methods.
+func (b *builder) endpoints() ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	err := b.api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			if len(action.Routes) == 0 || action.WebSocket() {
+				return nil
+			}
+			endpoints = append(endpoints, b.endpoint(res, action))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Generate the user types and media types not used by any endpoint as well.
+	for _, n := range codegen.SortedUserTypeNames(b.api.Types) {
+		b.resolve(&design.AttributeDefinition{Type: b.api.Types[n]}, "")
+	}
+	for _, n := range codegen.SortedMediaTypeNames(b.api.MediaTypes) {
+		mt := b.api.MediaTypes[n]
+		if mt.Identifier != design.ErrorMediaIdentifier {
+			b.resolve(&design.AttributeDefinition{Type: mt}, "")
+		}
+	}
+	sort.Slice(b.models, func(i, j int) bool { return b.models[i].Name < b.models[j].Name })
+	sort.Slice(b.enums, func(i, j int) bool { return b.enums[i].Name < b.enums[j].Name })
+	return endpoints, nil
+}
+
+// endpoint computes the data needed to generate the client method of the given action.
+func (b *builder) endpoint(res *design.ResourceDefinition, action *design.ActionDefinition) *Endpoint {
+	name := pyName(action.Name) + "_" + pyName(res.Name)
+	prefix := codegen.Goify(action.Name, true) + codegen.Goify(res.Name, true)
+	route := action.Routes[0]
+	e := &Endpoint{
+		Name:        name,
+		Description: action.Description,
+		Verb:        route.Verb,
+		Path:        route.FullPath(),
+	}
+	if e.Description == "" {
+		e.Description = fmt.Sprintf("%s calls the %s action of the %s resource.", name, action.Name, res.Name)
+	}
+
+	var required, optional []string
+	params := action.AllParams()
+	pathParams := make(map[string]bool)
+	for _, p := range route.Params() {
+		pathParams[p] = true
+	}
+	e.PathFormat = design.WildcardRegex.ReplaceAllStringFunc(strings.Replace(e.Path, "%", "%%", -1), func(wc string) string {
+		n := wc[2:] // remove "/:" or "/*" prefix
+		safe := ""
+		if wc[1] == '*' {
+			safe = "/"
+		}
+		e.PathArgs = append(e.PathArgs, fmt.Sprintf("quote(_format(%s), safe=%q)", pyName(n), safe))
+		return "/%s"
+	})
+	addArg := func(n string, att *design.AttributeDefinition, req bool, class string) {
+		t := b.resolve(att, class)
+		arg := pyName(n)
+		if req {
+			required = append(required, arg+": "+t.Hint)
+			e.Checks = append(e.Checks, fmt.Sprintf("_check_required(%s, %s)", arg, strconv.Quote(n)))
+			e.Checks = append(e.Checks, b.checks(t, arg, n, "")...)
+		} else {
+			optional = append(optional, arg+": Optional["+t.Hint+"] = None")
+			if checks := b.checks(t, arg, n, "    "); len(checks) > 0 {
+				e.Checks = append(e.Checks, "if "+arg+" is not None:")
+				e.Checks = append(e.Checks, checks...)
+			}
+		}
+	}
+	var positional []string
+	if params != nil {
+		o := params.Type.ToObject()
+		for _, n := range route.Params() {
+			if att, ok := o[n]; ok {
+				t := b.resolve(att, prefix+codegen.Goify(n, true))
+				positional = append(positional, pyName(n)+": "+t.Hint)
+				e.Checks = append(e.Checks, b.checks(t, pyName(n), n, "")...)
+			} else {
+				positional = append(positional, pyName(n)+": str")
+			}
+		}
+		for _, n := range codegen.SortedAttributeNames(o) {
+			if pathParams[n] {
+				continue
+			}
+			addArg(n, o[n], params.IsRequired(n), prefix+codegen.Goify(n, true))
+			if o[n].Type.IsObject() {
+				// Object params are sent as one query string param per field, e.g. filter.status.
+				for _, f := range codegen.SortedAttributeNames(o[n].Type.ToObject()) {
+					expr := fmt.Sprintf("None if %s is None else _format(%s.%s)", pyName(n), pyName(n), pyName(f))
+					e.Params = append(e.Params, &Param{Name: strconv.Quote(n + "." + f), Expr: expr})
+				}
+				continue
+			}
+			e.Params = append(e.Params, &Param{Name: strconv.Quote(n), Expr: "_format(" + pyName(n) + ")"})
+		}
+	}
+	if action.Headers != nil {
+		o := action.Headers.Type.ToObject()
+		for _, n := range codegen.SortedAttributeNames(o) {
+			addArg(n, o[n], action.Headers.IsRequired(n), prefix+codegen.Goify(n, true))
+			e.Headers = append(e.Headers, &Param{Name: strconv.Quote(n), Expr: "_format(" + pyName(n) + ")"})
+		}
+	}
+	if p := action.Payload; p != nil {
+		t := b.resolve(&design.AttributeDefinition{Type: p}, prefix+"Payload")
+		if action.PayloadOptional {
+			optional = append([]string{"payload: Optional[" + t.Hint + "] = None"}, optional...)
+			if checks := b.checks(t, "payload", "payload", "    "); len(checks) > 0 {
+				e.Checks = append(e.Checks, "if payload is not None:")
+				e.Checks = append(e.Checks, checks...)
+			}
+			e.Body = "None if payload is None else " + b.encode(t, "payload", 0)
+		} else {
+			positional = append(positional, "payload: "+t.Hint)
+			e.Checks = append(e.Checks, `_check_required(payload, "payload")`)
+			e.Checks = append(e.Checks, b.checks(t, "payload", "payload", "")...)
+			e.Body = b.encode(t, "payload", 0)
+		}
+	}
+	e.Args = positional
+	if len(required)+len(optional) > 0 {
+		e.Args = append(e.Args, "*")
+		e.Args = append(e.Args, required...)
+		e.Args = append(e.Args, optional...)
+	}
+
+	if sec := action.Security; sec != nil {
+		scheme := sec.Scheme
+		header, prefix, query, basic := "Authorization", "Bearer ", false, false
+		switch scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			prefix, basic = "Basic ", true
+		case design.APIKeySecurityKind:
+			header, prefix, query = scheme.Name, "", scheme.In == "query"
+		case design.SessionSecurityKind:
+			header, prefix = "Cookie", scheme.CookieName+"="
+		}
+		e.Auth = fmt.Sprintf("(%s, %s, %s, %s, %s)", strconv.Quote(scheme.SchemeName),
+			strconv.Quote(header), strconv.Quote(prefix), pyBool(query), pyBool(basic))
+	}
+
+	e.Result = "None"
+	var statuses []int
+	for _, r := range action.Responses {
+		if r.Status >= 200 && r.Status < 300 {
+			statuses = append(statuses, r.Status)
+		}
+	}
+	sort.Ints(statuses)
+	if len(statuses) > 0 {
+		strs := make([]string, len(statuses))
+		for i, s := range statuses {
+			strs[i] = strconv.Itoa(s)
+		}
+		e.Statuses = "(" + strings.Join(strs, ", ") + ",)"
+		if len(statuses) > 1 {
+			e.Statuses = "(" + strings.Join(strs, ", ") + ")"
+		}
+	}
+	for _, s := range statuses {
+		for _, r := range action.Responses {
+			if r.Status != s || r.MediaType == "" {
+				continue
+			}
+			mt, ok := b.api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]
+			if !ok || mt.Identifier == design.ErrorMediaIdentifier {
+				continue
+			}
+			t := b.resolve(&design.AttributeDefinition{Type: mt}, "")
+			e.Result = t.Hint
+			e.Decode = b.decode(t, "resp.json()", 0)
+			return e
+		}
+	}
+	return e
+}
+
+// resolve computes the Python type of the given attribute. class is the name of the enum or
+// dataclass generated for the attribute if it defines an enum or an inline object. It registers
+// the dataclasses and enums needed to represent the type.
+func (b *builder) resolve(att *design.AttributeDefinition, class string) *pyType {
+	t := &pyType{Kind: att.Type.Kind(), Att: att}
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		return b.named(actual.UserTypeDefinition, att)
+	case *design.UserTypeDefinition:
+		return b.named(actual, att)
+	case *design.ScalarDefinition:
+		wire := &design.AttributeDefinition{Type: actual.Primitive, Validation: att.Validation}
+		if len(actual.Values) == 0 {
+			t = b.resolve(wire, class)
+			t.Att = att
+			return t
+		}
+		t = b.resolve(wire, "")
+		name := codegen.Goify(actual.TypeName, true)
+		b.enum(name, t.Hint, actual.Values)
+		t.Hint, t.Class, t.Enum, t.Att = name, name, true, att
+	case *design.FlagsDefinition:
+		name := codegen.Goify(actual.TypeName, true)
+		values := make([]interface{}, len(actual.Names))
+		for i, n := range actual.Names {
+			values[i] = n
+		}
+		b.enum(name, "str", values)
+		t.Elem = &pyType{Hint: name, Kind: design.StringKind, Class: name, Enum: true, Att: actual.ElemType}
+		t.Hint = "List[" + name + "]"
+	case *design.Array:
+		t.Elem = b.resolve(actual.ElemType, class)
+		t.Hint = "List[" + t.Elem.Hint + "]"
+	case *design.Hash:
+		key := b.resolve(actual.KeyType, class+"Key")
+		t.Elem = b.resolve(actual.ElemType, class)
+		t.Hint = "Dict[" + key.Hint + ", " + t.Elem.Hint + "]"
+	case design.Object:
+		t.Kind = design.UserTypeKind
+		t.Class = class
+		t.Hint = class
+		t.Model = att
+		b.model(class, att.Description, att)
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			t.Hint = "bool"
+		case design.IntegerKind:
+			t.Hint = "int"
+		case design.NumberKind:
+			t.Hint = "float"
+		case design.StringKind:
+			t.Hint = "str"
+		case design.DateTimeKind:
+			t.Hint = "datetime.datetime"
+		case design.UUIDKind:
+			t.Hint = "uuid.UUID"
+		default:
+			t.Hint = "Any"
+		}
+		if v := att.Validation; v != nil && len(v.Values) > 0 && class != "" {
+			switch actual.Kind() {
+			case design.IntegerKind, design.NumberKind, design.StringKind:
+				t.Class = class
+				t.Enum = true
+				b.enum(class, t.Hint, v.Values)
+				t.Hint = class
+			}
+		}
+	default:
+		t.Kind = design.AnyKind
+		t.Hint = "Any"
+	}
+	return t
+}
+
+// named returns the Python type of the given user type, it registers the corresponding dataclass
+// if the type is an object.
+func (b *builder) named(ut *design.UserTypeDefinition, att *design.AttributeDefinition) *pyType {
+	class := codegen.Goify(ut.TypeName, true)
+	if t, ok := b.types[class]; ok {
+		return t
+	}
+	if !ut.Type.IsObject() {
+		t := b.resolve(ut.AttributeDefinition, class)
+		b.types[class] = t
+		return t
+	}
+	t := &pyType{Hint: class, Kind: design.UserTypeKind, Class: class, Att: att, Model: ut.AttributeDefinition}
+	b.types[class] = t
+	b.model(class, ut.Description, ut.AttributeDefinition)
+	return t
+}
+
+// model registers the dataclass with the given name describing the given object attribute.
+func (b *builder) model(name, description string, att *design.AttributeDefinition) {
+	if description == "" {
+		description = name + " is a data structure of the " + b.api.Name + " API."
+	}
+	m := &Model{Name: name, Description: description}
+	b.models = append(b.models, m)
+	o := att.Type.ToObject()
+	var required, optional []*Field
+	for _, n := range codegen.SortedAttributeNames(o) {
+		t := b.resolve(o[n], name+codegen.Goify(n, true))
+		key := strconv.Quote(n)
+		f := &Field{
+			Name:     pyName(n),
+			Key:      key,
+			Hint:     t.Hint,
+			Required: att.IsRequired(n),
+			Decode:   b.decode(t, "d["+key+"]", 0),
+			Encode:   b.encode(t, "self."+pyName(n), 0),
+		}
+		expr := "self." + f.Name
+		if f.Required {
+			f.Checks = append(f.Checks, fmt.Sprintf("_check_required(%s, %s)", expr, key))
+			f.Checks = append(f.Checks, b.checks(t, expr, n, "")...)
+			required = append(required, f)
+			continue
+		}
+		f.Hint = "Optional[" + t.Hint + "]"
+		if checks := b.checks(t, expr, n, "    "); len(checks) > 0 {
+			f.Checks = append(f.Checks, "if "+expr+" is not None:")
+			f.Checks = append(f.Checks, checks...)
+		}
+		optional = append(optional, f)
+	}
+	m.Fields = append(required, optional...)
+}
+
+// enum registers the enum class with the given name and values.
+func (b *builder) enum(name, base string, values []interface{}) {
+	for _, e := range b.enums {
+		if e.Name == name {
+			return
+		}
+	}
+	e := &Enum{Name: name, Base: base}
+	seen := make(map[string]bool)
+	for _, v := range values {
+		var member string
+		if s, ok := v.(string); ok {
+			member = strings.ToUpper(pyName(s))
+		} else {
+			member = "VALUE_" + strings.ToUpper(pyName(fmt.Sprintf("%v", v)))
+		}
+		if member == "" || member[0] >= '0' && member[0] <= '9' {
+			member = "VALUE_" + member
+		}
+		for seen[member] {
+			member += "_"
+		}
+		seen[member] = true
+		e.Members = append(e.Members, &EnumMember{Name: member, Value: pyLiteral(v)})
+	}
+	b.enums = append(b.enums, e)
+}
+
+// decode returns the Python expression that decodes the JSON value expr into a value of type t.
+func (b *builder) decode(t *pyType, expr string, depth int) string {
+	switch {
+	case t.Enum:
+		return t.Class + "(" + expr + ")"
+	case t.Class != "":
+		return t.Class + ".from_dict(" + expr + ")"
+	}
+	v := fmt.Sprintf("v%d", depth)
+	switch t.Kind {
+	case design.DateTimeKind:
+		return "_parse_datetime(" + expr + ")"
+	case design.UUIDKind:
+		return "uuid.UUID(" + expr + ")"
+	case design.ArrayKind:
+		if elem := b.decode(t.Elem, v, depth+1); elem != v {
+			return fmt.Sprintf("[%s for %s in %s]", elem, v, expr)
+		}
+		return "list(" + expr + ")"
+	case design.HashKind:
+		if elem := b.decode(t.Elem, v, depth+1); elem != v {
+			k := fmt.Sprintf("k%d", depth)
+			return fmt.Sprintf("{%s: %s for %s, %s in %s.items()}", k, elem, k, v, expr)
+		}
+		return "dict(" + expr + ")"
+	}
+	return expr
+}
+
+// encode returns the Python expression that encodes the value expr of type t into its JSON
+// representation.
+func (b *builder) encode(t *pyType, expr string, depth int) string {
+	switch {
+	case t.Enum:
+		return "_enum_value(" + expr + ")"
+	case t.Class != "":
+		return expr + ".to_dict()"
+	}
+	v := fmt.Sprintf("v%d", depth)
+	switch t.Kind {
+	case design.DateTimeKind:
+		return expr + ".isoformat()"
+	case design.UUIDKind:
+		return "str(" + expr + ")"
+	case design.ArrayKind:
+		if elem := b.encode(t.Elem, v, depth+1); elem != v {
+			return fmt.Sprintf("[%s for %s in %s]", elem, v, expr)
+		}
+		return "list(" + expr + ")"
+	case design.HashKind:
+		if elem := b.encode(t.Elem, v, depth+1); elem != v {
+			k := fmt.Sprintf("k%d", depth)
+			return fmt.Sprintf("{%s: %s for %s, %s in %s.items()}", k, elem, k, v, expr)
+		}
+		return "dict(" + expr + ")"
+	}
+	return expr
+}
+
+// checks returns the Python statements that validate the value expr of type t, path is the
+// path to the value used in error messages. The statements are indented with indent.
+func (b *builder) checks(t *pyType, expr, path, indent string) []string {
+	var res []string
+	add := func(format string, args ...interface{}) {
+		res = append(res, indent+fmt.Sprintf(format, args...))
+	}
+	qpath := strconv.Quote(path)
+	if t.Enum {
+		add("_check_enum(%s, %s, %s)", t.Class, expr, qpath)
+		return res
+	}
+	if t.Class != "" {
+		add("%s.validate()", expr)
+		return res
+	}
+	if v := t.Att.Validation; v != nil {
+		if len(v.Values) > 0 {
+			values := make([]string, len(v.Values))
+			for i, val := range v.Values {
+				values[i] = pyLiteral(val)
+			}
+			add("_check_values(%s, (%s,), %s)", expr, strings.Join(values, ", "), qpath)
+		}
+		if v.Pattern != "" {
+			add("_check_pattern(%s, %s, %s)", expr, strconv.Quote(v.Pattern), qpath)
+		}
+		if v.Minimum != nil {
+			add("_check_minimum(%s, %s, %s)", expr, strconv.FormatFloat(*v.Minimum, 'f', -1, 64), qpath)
+		}
+		if v.Maximum != nil {
+			add("_check_maximum(%s, %s, %s)", expr, strconv.FormatFloat(*v.Maximum, 'f', -1, 64), qpath)
+		}
+		if v.MinLength != nil {
+			add("_check_min_length(%s, %d, %s)", expr, *v.MinLength, qpath)
+		}
+		if v.MaxLength != nil {
+			add("_check_max_length(%s, %d, %s)", expr, *v.MaxLength, qpath)
+		}
+	}
+	if t.Elem == nil {
+		return res
+	}
+	v := fmt.Sprintf("v%d", strings.Count(indent, "    "))
+	var elem []string
+	switch t.Kind {
+	case design.ArrayKind:
+		elem = b.checks(t.Elem, v, path+"[*]", indent+"    ")
+		if len(elem) > 0 {
+			add("for %s in %s:", v, expr)
+		}
+	case design.HashKind:
+		elem = b.checks(t.Elem, v, path+"[*]", indent+"    ")
+		if len(elem) > 0 {
+			add("for %s in %s.values():", v, expr)
+		}
+	}
+	return append(res, elem...)
+}
+
+// pyName returns a valid snake case Python identifier built from the given name.
+func pyName(name string) string {
+	name = codegen.SnakeIdentifier(name)
+	if name == "" {
+		return "_"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	if pyKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// pyLiteral returns the Python literal representation of the given design value.
+func pyLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		return pyBool(val)
+	case string:
+		return strconv.Quote(val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// pyBool returns the Python literal of the given boolean.
+func pyBool(b bool) string {
+	if b {
+		return "True"
+	}
+	return "False"
+}
+
+// docstring returns the given text formatted for a Python docstring indented with indent.
+func docstring(indent, text string) string {
+	text = strings.Replace(strings.TrimSpace(text), `"""`, `\"\"\"`, -1)
+	return strings.Replace(text, "\n", "\n"+indent, -1)
+}
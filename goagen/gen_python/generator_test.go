@@ -0,0 +1,183 @@
+package genpython_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_python"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_python/test_"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--host=baz", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpython.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a secured action with params, a payload and a result", func() {
+		BeforeEach(func() {
+			min := 1.0
+			maxLen := 20
+			scheme := &design.SecuritySchemeDefinition{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+			}
+			bottle := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					TypeName: "Bottle",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"id":   &design.AttributeDefinition{Type: design.Integer},
+							"name": &design.AttributeDefinition{Type: design.String},
+							"color": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id", "name"}},
+					},
+				},
+				Identifier: "application/vnd.bottle",
+			}
+			filter := &design.UserTypeDefinition{
+				TypeName: "Filter",
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{"vintage": &design.AttributeDefinition{Type: design.Integer}},
+				},
+			}
+			action := &design.ActionDefinition{
+				Name: "update",
+				Params: &design.AttributeDefinition{
+					Type: design.Object{
+						"bottleID": &design.AttributeDefinition{
+							Type:       design.Integer,
+							Validation: &dslengine.ValidationDefinition{Minimum: &min},
+						},
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+						"filter": &design.AttributeDefinition{Type: filter},
+					},
+				},
+				QueryParams: &design.AttributeDefinition{
+					Type: design.Object{
+						"filter": &design.AttributeDefinition{Type: filter},
+						"sort": &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"name", "vintage"}},
+						},
+					},
+				},
+				Payload: &design.UserTypeDefinition{
+					TypeName: "UpdateBottlePayload",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: &dslengine.ValidationDefinition{MaxLength: &maxLen},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+					},
+				},
+				Responses: map[string]*design.ResponseDefinition{
+					"OK":       {Name: "OK", Status: 200, MediaType: "application/vnd.bottle"},
+					"NotFound": {Name: "NotFound", Status: 404},
+				},
+				Security: &design.SecurityDefinition{Scheme: scheme},
+			}
+			route := &design.RouteDefinition{
+				Verb:   "PUT",
+				Path:   "/:bottleID",
+				Parent: action,
+			}
+			action.Routes = []*design.RouteDefinition{route}
+			res := &design.ResourceDefinition{
+				Name:     "bottle",
+				BasePath: "/bottles",
+				Actions:  map[string]*design.ActionDefinition{"update": action},
+			}
+			action.Parent = res
+			design.Design = &design.APIDefinition{
+				Name:       "cellar",
+				BasePath:   "/cellar",
+				Resources:  map[string]*design.ResourceDefinition{"bottle": res},
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.bottle": bottle},
+			}
+		})
+
+		It("generates the package", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(5))
+			_, err := os.Stat(filepath.Join(outDir, "python", "pyproject.toml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "python", "cellar_client", "__init__.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("from .client import DEFAULT_BASE_URL, APIError, Client"))
+		})
+
+		It("generates the dataclasses and enums", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "python", "cellar_client", "models.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("class BottleColor(str, enum.Enum):"))
+			Ω(string(content)).Should(ContainSubstring(`    RED = "red"`))
+			Ω(string(content)).Should(ContainSubstring("class Bottle:"))
+			Ω(string(content)).Should(ContainSubstring("    id: int\n    name: str\n    color: Optional[BottleColor] = None\n"))
+			Ω(string(content)).Should(ContainSubstring(`color=None if d.get("color") is None else BottleColor(d["color"]),`))
+			Ω(string(content)).Should(ContainSubstring(`d["color"] = _enum_value(self.color)`))
+			Ω(string(content)).Should(ContainSubstring(`_check_max_length(self.name, 20, "name")`))
+			Ω(string(content)).Should(ContainSubstring(`_check_required(self.name, "name")`))
+		})
+
+		It("generates the client methods", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "python", "cellar_client", "client.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`DEFAULT_BASE_URL = "http://baz"`))
+			Ω(string(content)).Should(ContainSubstring("def update_bottle(self, bottle_id: int, payload: UpdateBottlePayload, *, filter: Optional[Filter] = None, sort: Optional[UpdateBottleSort] = None) -> Bottle:"))
+			Ω(string(content)).Should(ContainSubstring(`_check_minimum(bottle_id, 1, "bottleID")`))
+			Ω(string(content)).Should(ContainSubstring(`_check_enum(UpdateBottleSort, sort, "sort")`))
+			Ω(string(content)).Should(ContainSubstring(`"/cellar/bottles/%s" % (quote(_format(bottle_id), safe=""),),`))
+			Ω(string(content)).Should(ContainSubstring(`params={"filter.vintage": None if filter is None else _format(filter.vintage), "sort": _format(sort)},`))
+			Ω(string(content)).Should(ContainSubstring(`body=payload.to_dict(),`))
+			Ω(string(content)).Should(ContainSubstring(`auth=("jwt", "Authorization", "Bearer ", False, False),`))
+			Ω(string(content)).Should(ContainSubstring("if resp.status_code not in (200,):"))
+			Ω(string(content)).Should(ContainSubstring("return Bottle.from_dict(resp.json())"))
+		})
+	})
+
+	Context("with an invalid package name", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{Name: "cellar"}
+			os.Args = append(os.Args, "--pkg=cellar-client")
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(MatchError(ContainSubstring("invalid Python package name")))
+		})
+	})
+})
@@ -0,0 +1,278 @@
+package genpython
+
+const pyprojectT = `# Python client package of the {{ .API.Name }} API.
+#
+# Generated by goagen, DO NOT MODIFY.
+[build-system]
+requires = ["setuptools>=61"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = {{ printf "%q" .Package }}
+version = {{ if .API.Version }}{{ printf "%q" .API.Version }}{{ else }}"0.1.0"{{ end }}
+description = {{ printf "%q" (printf "Client for the %s API" .API.Name) }}
+requires-python = ">=3.7"
+dependencies = ["requests"]
+
+[tool.setuptools]
+packages = [{{ printf "%q" .Package }}]
+`
+
+const initT = `"""{{ if .API.Title }}{{ docstring "" .API.Title }}{{ else }}{{ .API.Name }}{{ end }} client.
+{{ if .API.Description }}
+{{ docstring "" .API.Description }}
+{{ end }}
+Generated by goagen, DO NOT MODIFY.
+"""
+from .client import DEFAULT_BASE_URL, APIError, Client
+from .models import *  # noqa: F401,F403
+from .models import ValidationError
+`
+
+const modelsT = `"""Data structures of the {{ .API.Name }} API.
+
+Generated by goagen, DO NOT MODIFY.
+"""
+from __future__ import annotations
+
+import datetime
+import enum
+import re
+import uuid
+from dataclasses import dataclass
+from typing import Any, Dict, List, Optional
+
+__all__ = [
+    "ValidationError",
+{{ range .Enums }}    {{ printf "%q" .Name }},
+{{ end }}{{ range .Models }}    {{ printf "%q" .Name }},
+{{ end }}]
+
+
+class ValidationError(ValueError):
+    """ValidationError is raised when a value does not satisfy the validations of the design."""
+
+
+def _check_required(value: Any, path: str) -> None:
+    if value is None:
+        raise ValidationError("%s is required" % path)
+
+
+def _check_enum(cls: Any, value: Any, path: str) -> None:
+    try:
+        cls(value)
+    except ValueError:
+        raise ValidationError("%s must be one of %r, got %r" % (path, [m.value for m in cls], value))
+
+
+def _check_values(value: Any, values: Any, path: str) -> None:
+    if value not in values:
+        raise ValidationError("%s must be one of %r, got %r" % (path, list(values), value))
+
+
+def _check_pattern(value: str, pattern: str, path: str) -> None:
+    if re.search(pattern, value) is None:
+        raise ValidationError("%s must match the regexp %r, got %r" % (path, pattern, value))
+
+
+def _check_minimum(value: Any, minimum: Any, path: str) -> None:
+    if value < minimum:
+        raise ValidationError("%s must be greater than or equal to %r, got %r" % (path, minimum, value))
+
+
+def _check_maximum(value: Any, maximum: Any, path: str) -> None:
+    if value > maximum:
+        raise ValidationError("%s must be less than or equal to %r, got %r" % (path, maximum, value))
+
+
+def _check_min_length(value: Any, length: int, path: str) -> None:
+    if len(value) < length:
+        raise ValidationError("length of %s must be greater than or equal to %d" % (path, length))
+
+
+def _check_max_length(value: Any, length: int, path: str) -> None:
+    if len(value) > length:
+        raise ValidationError("length of %s must be less than or equal to %d" % (path, length))
+
+
+def _enum_value(value: Any) -> Any:
+    return value.value if isinstance(value, enum.Enum) else value
+
+
+def _parse_datetime(value: str) -> datetime.datetime:
+    return datetime.datetime.fromisoformat(value.replace("Z", "+00:00"))
+{{ range .Enums }}
+
+class {{ .Name }}({{ .Base }}, enum.Enum):
+    """{{ .Name }} enumerates the values allowed by the design."""
+
+{{ range .Members }}    {{ .Name }} = {{ .Value }}
+{{ end }}{{ end }}{{ range .Models }}
+
+@dataclass
+class {{ .Name }}:
+    """{{ docstring "    " .Description }}"""
+
+{{ range .Fields }}    {{ .Name }}: {{ .Hint }}{{ if not .Required }} = None{{ end }}
+{{ end }}{{ if .Fields }}
+{{ end }}    @classmethod
+    def from_dict(cls, d: Dict[str, Any]) -> "{{ .Name }}":
+        """from_dict builds a {{ .Name }} from its JSON representation."""
+        return cls(
+{{ range .Fields }}            {{ .Name }}=None if d.get({{ .Key }}) is None else {{ .Decode }},
+{{ end }}        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        """to_dict returns the JSON representation of the {{ .Name }}."""
+        d: Dict[str, Any] = {}
+{{ range .Fields }}        if self.{{ .Name }} is not None:
+            d[{{ .Key }}] = {{ .Encode }}
+{{ end }}        return d
+
+    def validate(self) -> None:
+        """validate raises ValidationError if the {{ .Name }} does not satisfy the design validations."""
+{{ range .Fields }}{{ range .Checks }}        {{ . }}
+{{ end }}{{ end }}        return None
+{{ end }}`
+
+const clientT = `"""Client of the {{ .API.Name }} API.
+
+Generated by goagen, DO NOT MODIFY.
+"""
+from __future__ import annotations
+
+import base64
+import datetime
+import enum
+import uuid
+from typing import Any, Dict, List, Optional, Tuple
+from urllib.parse import quote
+
+from .models import *  # noqa: F401,F403
+from .models import (
+    _check_enum,
+    _check_max_length,
+    _check_maximum,
+    _check_min_length,
+    _check_minimum,
+    _check_pattern,
+    _check_required,
+    _check_values,
+    _enum_value,
+    _parse_datetime,
+)
+
+DEFAULT_BASE_URL = {{ printf "%q" .BaseURL }}
+
+
+class APIError(Exception):
+    """APIError is raised when the API responds with an unexpected status code.
+
+    status is the response status code, body the decoded JSON response body or its text if the
+    body is not JSON and response the underlying HTTP response.
+    """
+
+    def __init__(self, status: int, body: Any, response: Any) -> None:
+        super().__init__("unexpected response status %d: %r" % (status, body))
+        self.status = status
+        self.body = body
+        self.response = response
+
+
+def _format(value: Any) -> Any:
+    if value is None:
+        return None
+    if isinstance(value, enum.Enum):
+        value = value.value
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, datetime.datetime):
+        return value.isoformat()
+    if isinstance(value, (list, tuple)):
+        return [_format(v) for v in value]
+    return str(value)
+
+
+def _body(resp: Any) -> Any:
+    try:
+        return resp.json()
+    except ValueError:
+        return resp.text
+
+
+class Client:
+    """Client is the {{ .API.Name }} API client.
+
+    base_url is the scheme and host of the API. session sends the requests, it may be
+    a requests.Session or an httpx.Client and defaults to a new requests.Session. timeout is the
+    request timeout in seconds and headers the headers added to all the requests. credentials
+    maps the names of the design security schemes to the credentials sent to the endpoints
+    secured with the scheme: "user:password" for basic auth, the key for API keys, the token for
+    JWT and OAuth2 and the cookie value for session authentication.
+    """
+
+    def __init__(
+        self,
+        base_url: str = DEFAULT_BASE_URL,
+        session: Any = None,
+        timeout: float = 30,
+        headers: Optional[Dict[str, str]] = None,
+        credentials: Optional[Dict[str, str]] = None,
+    ) -> None:
+        if session is None:
+            import requests
+
+            session = requests.Session()
+        self.base_url = base_url.rstrip("/")
+        self.session = session
+        self.timeout = timeout
+        self.headers = dict(headers or {})
+        self.credentials = dict(credentials or {})
+
+    def _send(
+        self,
+        method: str,
+        path: str,
+        params: Optional[Dict[str, Any]] = None,
+        headers: Optional[Dict[str, Any]] = None,
+        body: Any = None,
+        auth: Optional[Tuple[str, str, str, bool, bool]] = None,
+    ) -> Any:
+        query = {k: v for k, v in (params or {}).items() if v is not None}
+        h = dict(self.headers)
+        h.update({k: v for k, v in (headers or {}).items() if v is not None})
+        if auth is not None:
+            scheme, name, prefix, in_query, basic = auth
+            value = self.credentials.get(scheme)
+            if value is not None:
+                if basic:
+                    value = base64.b64encode(value.encode("utf-8")).decode("ascii")
+                if in_query:
+                    query[name] = value
+                else:
+                    h[name] = prefix + value
+        kwargs: Dict[str, Any] = {"params": query, "headers": h, "timeout": self.timeout}
+        if body is not None:
+            kwargs["json"] = body
+        return self.session.request(method, self.base_url + path, **kwargs)
+{{ range .Endpoints }}
+    def {{ .Name }}(self{{ range .Args }}, {{ . }}{{ end }}) -> {{ .Result }}:
+        """{{ docstring "        " .Description }}
+
+        {{ .Verb }} {{ .Path }}
+        """
+{{ range .Checks }}        {{ . }}
+{{ end }}        resp = self._send(
+            {{ printf "%q" .Verb }},
+            {{ printf "%q" .PathFormat }}{{ if .PathArgs }} % ({{ join .PathArgs ", " }},){{ end }},
+{{ if .Params }}            params={ {{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Expr }}{{ end }}},
+{{ end }}{{ if .Headers }}            headers={ {{- range $i, $p := .Headers }}{{ if $i }}, {{ end }}{{ $p.Name }}: {{ $p.Expr }}{{ end }}},
+{{ end }}{{ if .Body }}            body={{ .Body }},
+{{ end }}{{ if .Auth }}            auth={{ .Auth }},
+{{ end }}        )
+{{ if .Statuses }}        if resp.status_code not in {{ .Statuses }}:
+{{ else }}        if not 200 <= resp.status_code < 300:
+{{ end }}            raise APIError(resp.status_code, _body(resp), resp)
+{{ if .Decode }}        return {{ .Decode }}
+{{ else }}        return None
+{{ end }}{{ end }}`
@@ -7,8 +7,10 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 )
 
@@ -48,6 +50,10 @@ type (
 
 		// Union
 		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+
+		// Extensions lists the vendor extensions ("x-" prefixed keys) added to the
+		// serialized schema.
+		Extensions map[string]interface{} `json:"-"`
 	}
 
 	// JSONType is the JSON type enum.
@@ -124,6 +130,68 @@ func (s *JSONSchema) JSON() ([]byte, error) {
 	return json.Marshal(s)
 }
 
+// MarshalJSON serializes the schema and its vendor extensions into JSON.
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	type schema JSONSchema
+	return MarshalWithExtensions(schema(s), s.Extensions)
+}
+
+// MarshalWithExtensions serializes v which must marshal to a JSON object and adds the given vendor
+// extensions to the resulting object.
+func MarshalWithExtensions(v interface{}, extensions map[string]interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return b, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, e := range extensions {
+		m[k] = e
+	}
+	return json.Marshal(m)
+}
+
+// ExtensionsFromDefinition returns the vendor extensions defined in the given metadata. Extensions
+// are defined with keys of the form "openapi:extension:x-xxx" or "swagger:extension:x-xxx". Values
+// that are valid JSON are decoded, other values are used as is. Multiple values for the same key
+// produce an array.
+func ExtensionsFromDefinition(mdata dslengine.MetadataDefinition) map[string]interface{} {
+	var extensions map[string]interface{}
+	for key, values := range mdata {
+		var name string
+		switch {
+		case strings.HasPrefix(key, "openapi:extension:"):
+			name = strings.TrimPrefix(key, "openapi:extension:")
+		case strings.HasPrefix(key, "swagger:extension:"):
+			name = strings.TrimPrefix(key, "swagger:extension:")
+		default:
+			continue
+		}
+		if !strings.HasPrefix(name, "x-") || len(values) == 0 {
+			continue
+		}
+		vals := make([]interface{}, len(values))
+		for i, v := range values {
+			var val interface{}
+			if err := json.Unmarshal([]byte(v), &val); err != nil {
+				val = v
+			}
+			vals[i] = val
+		}
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		if len(vals) == 1 {
+			extensions[name] = vals[0]
+		} else {
+			extensions[name] = vals
+		}
+	}
+	return extensions
+}
+
 // APISchema produces the API JSON hyper schema.
 func APISchema(api *design.APIDefinition) *JSONSchema {
 	api.IterateResources(func(r *design.ResourceDefinition) error {
@@ -293,6 +361,17 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 		case design.IntegerKind:
 			s.Format = "int64"
 		}
+	case *design.ScalarDefinition:
+		s = TypeSchema(api, actual.Primitive)
+		s.Description = actual.Description
+		if actual.SchemaFormat != "" {
+			s.Format = actual.SchemaFormat
+		}
+		if actual.IsEnum() {
+			s.Enum = actual.Values
+		}
+	case *design.FlagsDefinition:
+		s = TypeSchema(api, actual.Array)
 	case *design.Array:
 		s.Type = JSONArray
 		s.Items = NewJSONSchema()
@@ -336,6 +415,7 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 		{&s.Format, other.Format, s.Format == ""},
 		{&s.Pattern, other.Pattern, s.Pattern == ""},
 		{&s.AdditionalProperties, other.AdditionalProperties, s.AdditionalProperties == false},
+		{&s.Extensions, other.Extensions, s.Extensions == nil},
 		{
 			a: s.Minimum, b: other.Minimum,
 			needed: (s.Minimum == nil && s.Minimum != nil) ||
@@ -415,6 +495,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		MaxLength:            s.MaxLength,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
+		Extensions:           s.Extensions,
 	}
 	for n, p := range s.Properties {
 		js.Properties[n] = p.Dup()
@@ -444,12 +525,30 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.DefaultValue = toStringMap(at.DefaultValue)
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
+	if ext := ExtensionsFromDefinition(at.Metadata); ext != nil {
+		s.Extensions = ext
+	}
+	if key := at.EncryptionKey(); key != "" {
+		if s.Extensions == nil {
+			s.Extensions = make(map[string]interface{})
+		}
+		s.Extensions["x-encrypted"] = key
+	}
+	if class := at.PIIClass(); class != "" {
+		if s.Extensions == nil {
+			s.Extensions = make(map[string]interface{})
+		}
+		s.Extensions["x-pii"] = class
+	}
 	val := at.Validation
 	if val == nil {
 		return s
 	}
-	s.Enum = val.Values
-	s.Format = val.Format
+	if _, ok := at.Type.(*design.ScalarDefinition); !ok {
+		// scalars do not support validations, keep their enum values and format
+		s.Enum = val.Values
+		s.Format = val.Format
+	}
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
 		s.Minimum = val.Minimum
@@ -570,4 +669,7 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 		}
 	}
 	buildAttributeSchema(api, s, projected.AttributeDefinition)
+	if ext := ExtensionsFromDefinition(mt.Metadata); ext != nil {
+		s.Extensions = ext
+	}
 }
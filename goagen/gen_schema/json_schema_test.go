@@ -1,6 +1,8 @@
 package genschema_test
 
 import (
+	"encoding/json"
+
 	"github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -78,4 +80,80 @@ var _ = Describe("TypeSchema", func() {
 		})
 
 	})
+
+	Context("with vendor extensions", func() {
+		BeforeEach(func() {
+			Type("Foo", func() {
+				Attribute("bar", design.String, func() {
+					Metadata("openapi:extension:x-bar", `{"nullable":true}`)
+					Metadata("swagger:extension:x-tag", "baz")
+					Metadata("openapi:extension:ignored", "qux")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Foo"].Type
+		})
+
+		It("sets the extensions of the properties", func() {
+			Ω(s.Properties).Should(HaveKey("bar"))
+			Ω(s.Properties["bar"].Extensions).Should(Equal(map[string]interface{}{
+				"x-bar": map[string]interface{}{"nullable": true},
+				"x-tag": "baz",
+			}))
+		})
+
+		It("serializes the extensions", func() {
+			b, err := json.Marshal(s.Properties["bar"])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`"x-bar":{"nullable":true}`))
+			Ω(string(b)).Should(ContainSubstring(`"x-tag":"baz"`))
+			Ω(string(b)).Should(ContainSubstring(`"type":"string"`))
+		})
+	})
+
+	Context("with an encrypted attribute", func() {
+		BeforeEach(func() {
+			Type("Foo", func() {
+				Attribute("ssn", design.String, func() {
+					Encrypted("alias/pii")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Foo"].Type
+		})
+
+		It("sets the x-encrypted extension", func() {
+			Ω(s.Properties).Should(HaveKey("ssn"))
+			Ω(s.Properties["ssn"].Extensions).Should(Equal(map[string]interface{}{
+				"x-encrypted": "alias/pii",
+			}))
+		})
+	})
+
+	Context("with built-in scalars", func() {
+		BeforeEach(func() {
+			Type("Foo", func() {
+				Attribute("id", design.Int64, func() {
+					StringEncoded()
+				})
+				Attribute("amount", design.Decimal)
+				Attribute("at", design.Time, func() {
+					TimeFormat(design.TimeUnix)
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.Types["Foo"].Type
+		})
+
+		It("uses the wire type and format", func() {
+			Ω(s.Properties["id"].Type).Should(BeEquivalentTo(genschema.JSONString))
+			Ω(s.Properties["id"].Format).Should(Equal("int64"))
+			Ω(s.Properties["amount"].Type).Should(BeEquivalentTo(genschema.JSONString))
+			Ω(s.Properties["amount"].Format).Should(Equal("decimal"))
+			Ω(s.Properties["at"].Type).Should(BeEquivalentTo(genschema.JSONInteger))
+		})
+	})
 })
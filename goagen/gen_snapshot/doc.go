@@ -0,0 +1,13 @@
+/*
+Package gensnapshot provides a goa generator for schema snapshots.
+The generator records the attributes of the API user types and media types in a JSON file
+("goa.snapshot.json" by default, see the SchemaSnapshot DSL). The file should be committed along
+with the design: the design validation compares the types with the snapshot to reject changes to
+the Frozen types and, if the API is AdditiveOnly, breaking changes to any type.
+
+	goagen snapshot -d github.com/example/cellar/design
+
+Changes rejected by the validation prevent the generation of a new snapshot, delete the snapshot
+file first to accept them.
+*/
+package gensnapshot
@@ -0,0 +1,13 @@
+package gensnapshot_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenSnapshot(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenSnapshot Suite")
+}
@@ -0,0 +1,58 @@
+package gensnapshot_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_snapshot"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "gensnapshot")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--version=" + version.String()}
+		design.Design = &design.APIDefinition{
+			Name: "test",
+			Types: map[string]*design.UserTypeDefinition{
+				"Money": {
+					TypeName: "Money",
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"amount":   &design.AttributeDefinition{Type: design.Integer},
+							"currency": &design.AttributeDefinition{Type: design.String},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gensnapshot.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("writes the snapshot", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(Equal([]string{filepath.Join(outDir, design.DefaultSnapshotFile)}))
+		s, err := design.LoadSnapshot(files[0])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(s.Types).Should(HaveKey("Money"))
+		Ω(s.Types["Money"].Attributes).Should(HaveLen(2))
+		Ω(s.Types["Money"].Attributes["amount"].Type).Should(Equal("integer"))
+	})
+})
@@ -8,6 +8,7 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_schema"
 )
 
@@ -29,6 +30,7 @@ type (
 		SecurityDefinitions map[string]*SecurityDefinition   `json:"securityDefinitions,omitempty"`
 		Tags                []*Tag                           `json:"tags,omitempty"`
 		ExternalDocs        *ExternalDocs                    `json:"externalDocs,omitempty"`
+		Extensions          map[string]interface{}           `json:"-"`
 	}
 
 	// Info provides metadata about the API. The metadata can be used by the clients if needed,
@@ -95,6 +97,8 @@ type (
 		Deprecated bool `json:"deprecated,omitempty"`
 		// Secury is a declaration of which security schemes are applied for this operation.
 		Security []map[string][]string `json:"security,omitempty"`
+		// Extensions lists the vendor extensions ("x-" prefixed keys) of the operation.
+		Extensions map[string]interface{} `json:"-"`
 	}
 
 	// Parameter describes a single operation parameter.
@@ -144,6 +148,8 @@ type (
 		UniqueItems      bool          `json:"uniqueItems,omitempty"`
 		Enum             []interface{} `json:"enum,omitempty"`
 		MultipleOf       float64       `json:"multipleOf,omitempty"`
+		// Extensions lists the vendor extensions ("x-" prefixed keys) of the parameter.
+		Extensions map[string]interface{} `json:"-"`
 	}
 
 	// Response describes an operation response.
@@ -326,7 +332,12 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 		Tags:                tags,
 		ExternalDocs:        docsFromDefinition(api.Docs),
 		SecurityDefinitions: securityDefsFromDefinition(api.SecuritySchemes),
+		Extensions:          genschema.ExtensionsFromDefinition(api.Metadata),
 	}
+	applyTenant(s, api.Tenant)
+	applyServers(s, api.Servers)
+	applyTLS(s, api.TLS)
+	applyRequestID(s, api.RequestID)
 
 	err = api.IterateResponses(func(r *design.ResponseDefinition) error {
 		res, err := responseSpecFromDefinition(s, api, r)
@@ -373,6 +384,24 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	return s, nil
 }
 
+// MarshalJSON serializes the swagger object and its vendor extensions into JSON.
+func (s Swagger) MarshalJSON() ([]byte, error) {
+	type swagger Swagger
+	return genschema.MarshalWithExtensions(swagger(s), s.Extensions)
+}
+
+// MarshalJSON serializes the operation and its vendor extensions into JSON.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return genschema.MarshalWithExtensions(operation(o), o.Extensions)
+}
+
+// MarshalJSON serializes the parameter and its vendor extensions into JSON.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type parameter Parameter
+	return genschema.MarshalWithExtensions(parameter(p), p.Extensions)
+}
+
 // hasAbsoluteRoutes returns true if any action exposed by the API uses an absolute route of if the
 // API has file servers. This is needed as Swagger does not support exceptions to the base path so
 // if the API has any absolute route the base path must be "/" and all routes must be absolutes.
@@ -418,6 +447,9 @@ func securityDefsFromDefinition(schemes []*design.SecuritySchemeDefinition) map[
 			TokenURL:         scheme.TokenURL,
 			Scopes:           scheme.Scopes,
 		}
+		if scheme.Kind == design.SessionSecurityKind {
+			def.Description += fmt.Sprintf("\n\n**Session cookie**: %s", scheme.CookieName)
+		}
 		if scheme.Kind == design.JWTSecurityKind {
 			if def.TokenURL != "" {
 				def.Description += fmt.Sprintf("\n\n**Token URL**: %s", def.TokenURL)
@@ -521,8 +553,7 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 	if obj == nil {
 		return nil, fmt.Errorf("invalid parameters definition, not an object")
 	}
-	res := make([]*Parameter, len(obj))
-	i := 0
+	res := make([]*Parameter, 0, len(obj))
 	wildcards := design.ExtractWildcards(path)
 	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
 		in := "query"
@@ -534,14 +565,29 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 				break
 			}
 		}
-		param := paramFor(at, n, in, required)
-		res[i] = param
-		i++
+		if ut, ok := at.Type.(*design.UserTypeDefinition); ok && ut.IsObject() {
+			res = append(res, objectParams(ut, n, required)...)
+			return nil
+		}
+		res = append(res, paramFor(at, n, in, required))
 		return nil
 	})
 	return res, nil
 }
 
+// objectParams returns the query string parameters that hold the attributes of the parameter
+// whose type is the given object user type. Swagger does not support object parameters so each
+// attribute is described with a parameter named after the parameter and the attribute separated
+// with a dot.
+func objectParams(ut *design.UserTypeDefinition, name string, required bool) []*Parameter {
+	var res []*Parameter
+	ut.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		res = append(res, paramFor(at, name+"."+n, "query", required && ut.IsRequired(n)))
+		return nil
+	})
+	return res
+}
+
 func paramsFromHeaders(action *design.ActionDefinition) []*Parameter {
 	params := []*Parameter{}
 	action.IterateHeaders(func(name string, required bool, header *design.AttributeDefinition) error {
@@ -559,15 +605,40 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		Default:     toStringMap(at.DefaultValue),
 		Description: at.Description,
 		Required:    required,
-		Type:        at.Type.Name(),
+		Type:        paramType(at.Type),
+		Extensions:  genschema.ExtensionsFromDefinition(at.Metadata),
+	}
+	if s, ok := at.Type.(*design.ScalarDefinition); ok {
+		p.Format = s.SchemaFormat
+		if s.IsEnum() {
+			p.Enum = s.Values
+		}
 	}
 	if at.Type.IsArray() {
 		p.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
+		p.CollectionFormat = collectionFormat(at)
 	}
 	initValidations(at, p)
 	return p
 }
 
+// collectionFormat returns the collection format that corresponds to the style of the array
+// parameter if the design sets one.
+func collectionFormat(at *design.AttributeDefinition) string {
+	if _, ok := at.Metadata[design.ArrayStyleMetadata]; !ok {
+		return ""
+	}
+	switch at.ArrayStyle() {
+	case design.StyleComma:
+		return "csv"
+	case design.StylePipe:
+		return "pipes"
+	case design.StyleSpace:
+		return "ssv"
+	}
+	return "multi"
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -604,8 +675,23 @@ func toString(val interface{}) string {
 	}
 }
 
+// paramType returns the swagger type of parameters, items and headers of the given type. Custom
+// scalars use the type of their wire primitive.
+func paramType(t design.DataType) string {
+	if s, ok := t.(*design.ScalarDefinition); ok {
+		return s.Primitive.Name()
+	}
+	return t.Name()
+}
+
 func itemsFromDefinition(at *design.AttributeDefinition) *Items {
-	items := &Items{Type: at.Type.Name()}
+	items := &Items{Type: paramType(at.Type)}
+	if s, ok := at.Type.(*design.ScalarDefinition); ok {
+		items.Format = s.SchemaFormat
+		if s.IsEnum() {
+			items.Enum = s.Values
+		}
+	}
 	initValidations(at, items)
 	if at.Type.IsArray() {
 		items.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
@@ -668,7 +754,7 @@ func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Hea
 		header := &Header{
 			Default:     at.DefaultValue,
 			Description: at.Description,
-			Type:        at.Type.Name(),
+			Type:        paramType(at.Type),
 		}
 		initValidations(at, header)
 		res[n] = header
@@ -772,6 +858,21 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		params = append(params, pp)
 	}
 
+	var consumes []string
+	if elem := action.StreamingPayload; elem != nil {
+		streamSchema := genschema.NewJSONSchema()
+		streamSchema.Type = genschema.JSONArray
+		streamSchema.Items = genschema.TypeSchema(api, elem)
+		params = append(params, &Parameter{
+			Name:        "payload",
+			In:          "body",
+			Description: fmt.Sprintf("Stream of %s elements sent as a JSON array or as newline delimited JSON", elem.TypeName),
+			Required:    true,
+			Schema:      streamSchema,
+		})
+		consumes = []string{"application/json", "application/x-ndjson"}
+	}
+
 	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
 	index := 0
 	for i, rt := range action.Routes {
@@ -795,13 +896,29 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Summary:      summaryFromDefinition(action.Name+" "+action.Parent.Name, action.Metadata),
 		ExternalDocs: docsFromDefinition(action.Docs),
 		OperationID:  operationID,
+		Consumes:     consumes,
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
 		Deprecated:   false,
+		Extensions:   genschema.ExtensionsFromDefinition(action.Metadata),
 	}
 
 	applySecurity(operation, action.Security)
+	applyInterceptors(operation, action)
+	applyAudit(operation, action.Audit)
+	applyCompression(operation, action.Parent.AllCompression())
+	applyCache(operation, action.AllCache())
+	applyLoadShedding(operation, action.AllConcurrency())
+	if err := applyMaintenance(s, api, operation); err != nil {
+		return err
+	}
+	if action.Payload != nil || action.StreamingPayload != nil {
+		applyDecompression(operation, action.Parent.AllDecompression())
+	}
+	if err := applyCodeSamples(operation, api, route); err != nil {
+		return err
+	}
 
 	key := design.WildcardRegex.ReplaceAllStringFunc(
 		route.FullPath(),
@@ -846,6 +963,292 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 	return nil
 }
 
+// applyTenant documents the API tenancy in the "x-tenant" extension unless the design already
+// defines it.
+func applyTenant(s *Swagger, tenant *design.TenantDefinition) {
+	if tenant == nil {
+		return
+	}
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	if _, ok := s.Extensions["x-tenant"]; ok {
+		return
+	}
+	in := "header"
+	if tenant.Param != "" {
+		in = "path"
+	}
+	ext := map[string]interface{}{"in": in, "name": tenant.Name()}
+	if tenant.Description != "" {
+		ext["description"] = tenant.Description
+	}
+	s.Extensions["x-tenant"] = ext
+}
+
+// applyServers lists the API servers in the "x-servers" extension using the shape of the OpenAPI 3
+// servers object unless the design already defines it.
+func applyServers(s *Swagger, servers []*design.ServerDefinition) {
+	if len(servers) == 0 {
+		return
+	}
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	if _, ok := s.Extensions["x-servers"]; ok {
+		return
+	}
+	ext := make([]map[string]interface{}, len(servers))
+	for i, server := range servers {
+		srv := map[string]interface{}{"url": server.URL, "x-name": server.Name}
+		if server.Description != "" {
+			srv["description"] = server.Description
+		}
+		if names := server.VariableNames(); len(names) > 0 {
+			vars := make(map[string]interface{}, len(names))
+			for _, n := range names {
+				att := server.Variables.Type.ToObject()[n]
+				v := map[string]interface{}{"default": att.DefaultValue}
+				if att.Description != "" {
+					v["description"] = att.Description
+				}
+				if att.Validation != nil && len(att.Validation.Values) > 0 {
+					v["enum"] = att.Validation.Values
+				}
+				vars[n] = v
+			}
+			srv["variables"] = vars
+		}
+		if server.TLS != nil {
+			srv["x-tls"] = tlsExtension(server.TLS)
+		}
+		if server.Protocol != "" {
+			srv["x-protocol"] = server.Protocol
+		}
+		ext[i] = srv
+	}
+	s.Extensions["x-servers"] = ext
+}
+
+// applyTLS documents the TLS requirements of the API in the "x-tls" extension unless the design
+// already defines it.
+func applyTLS(s *Swagger, tls *design.TLSDefinition) {
+	if tls == nil {
+		return
+	}
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	if _, ok := s.Extensions["x-tls"]; ok {
+		return
+	}
+	s.Extensions["x-tls"] = tlsExtension(tls)
+}
+
+// applyRequestID documents the header that carries the request ID in the "x-request-id"
+// extension unless the design already defines it.
+func applyRequestID(s *Swagger, reqID *design.RequestIDDefinition) {
+	if reqID == nil {
+		return
+	}
+	if s.Extensions == nil {
+		s.Extensions = make(map[string]interface{})
+	}
+	if _, ok := s.Extensions["x-request-id"]; ok {
+		return
+	}
+	s.Extensions["x-request-id"] = map[string]interface{}{"header": reqID.Header}
+}
+
+// tlsExtension returns the value of the "x-tls" extension that describes the given TLS
+// requirements.
+func tlsExtension(tls *design.TLSDefinition) map[string]interface{} {
+	return map[string]interface{}{
+		"minVersion":         tls.Version(),
+		"clientCertRequired": tls.ClientCertRequired,
+	}
+}
+
+// applyInterceptors lists the names of the interceptors that apply to the action in the
+// "x-interceptors" extension of the operation unless the design already defines it.
+func applyInterceptors(operation *Operation, action *design.ActionDefinition) {
+	interceptors := action.AllInterceptors()
+	if len(interceptors) == 0 {
+		return
+	}
+	if operation.Extensions == nil {
+		operation.Extensions = make(map[string]interface{})
+	}
+	if _, ok := operation.Extensions["x-interceptors"]; ok {
+		return
+	}
+	names := make([]string, len(interceptors))
+	for i, in := range interceptors {
+		names[i] = in.Name
+	}
+	operation.Extensions["x-interceptors"] = names
+}
+
+// applyAudit documents the audit event emitted by the action in the "x-audit" extension of the
+// operation unless the design already defines it.
+func applyAudit(operation *Operation, audit *design.AuditDefinition) {
+	if audit == nil {
+		return
+	}
+	if operation.Extensions == nil {
+		operation.Extensions = make(map[string]interface{})
+	}
+	if _, ok := operation.Extensions["x-audit"]; ok {
+		return
+	}
+	ext := map[string]interface{}{"event": audit.Event}
+	if redact := audit.RedactedPaths(); len(redact) > 0 {
+		ext["redact"] = redact
+	}
+	operation.Extensions["x-audit"] = ext
+}
+
+// applyCompression documents the Content-Encoding header of the responses that have a body when
+// the action responses are compressed.
+func applyCompression(operation *Operation, compression *design.CompressionDefinition) {
+	if compression == nil {
+		return
+	}
+	enum := make([]interface{}, len(compression.Encodings))
+	for i, e := range compression.Encodings {
+		enum[i] = e
+	}
+	for _, resp := range operation.Responses {
+		if resp.Schema == nil {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]*Header)
+		}
+		if _, ok := resp.Headers["Content-Encoding"]; ok {
+			continue
+		}
+		resp.Headers["Content-Encoding"] = &Header{
+			Description: fmt.Sprintf("Encoding of the response body, bodies smaller than %d bytes are not compressed", compression.MinSize),
+			Type:        "string",
+			Enum:        enum,
+		}
+	}
+}
+
+// applyCache documents the Cache-Control and Vary headers of the successful responses when the
+// action defines a cache policy.
+func applyCache(operation *Operation, cache *design.CacheDefinition) {
+	if cache == nil {
+		return
+	}
+	for code, resp := range operation.Responses {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]*Header)
+		}
+		if _, ok := resp.Headers["Cache-Control"]; !ok {
+			resp.Headers["Cache-Control"] = &Header{
+				Description: "Caching directives of the response",
+				Type:        "string",
+				Default:     cache.CacheControl(),
+			}
+		}
+		if _, ok := resp.Headers["Vary"]; !ok && len(cache.Vary) > 0 {
+			resp.Headers["Vary"] = &Header{
+				Description: "Request headers that select the response",
+				Type:        "string",
+				Default:     strings.Join(cache.Vary, ", "),
+			}
+		}
+	}
+}
+
+// applyLoadShedding documents the 503 response of the actions that shed the requests exceeding
+// their concurrency budget.
+func applyLoadShedding(operation *Operation, concurrency *design.ConcurrencyDefinition) {
+	if concurrency == nil || !concurrency.Shed {
+		return
+	}
+	if _, ok := operation.Responses["503"]; ok {
+		return
+	}
+	operation.Responses["503"] = &Response{
+		Description: fmt.Sprintf("Too many concurrent requests, at most %d requests are handled concurrently", concurrency.Max),
+		Headers: map[string]*Header{
+			"Retry-After": {
+				Description: "Number of seconds to wait before retrying the request",
+				Type:        "integer",
+				Default:     concurrency.RetryAfter,
+			},
+		},
+	}
+}
+
+// applyMaintenance documents the response sent by the actions while in maintenance mode.
+func applyMaintenance(s *Swagger, api *design.APIDefinition, operation *Operation) error {
+	if api.Maintenance == nil {
+		return nil
+	}
+	status := strconv.Itoa(api.Maintenance.Status)
+	if _, ok := operation.Responses[status]; ok {
+		return nil
+	}
+	resp, err := responseSpecFromDefinition(s, api, api.Maintenance)
+	if err != nil {
+		return err
+	}
+	operation.Responses[status] = resp
+	return nil
+}
+
+// applyCodeSamples lists the curl and HTTPie command lines that call the operation in the
+// "x-code-samples" extension unless the design already defines it. The samples use the first
+// scheme of the operation and the API host, "localhost:8080" if the design does not define one.
+func applyCodeSamples(operation *Operation, api *design.APIDefinition, route *design.RouteDefinition) error {
+	if _, ok := operation.Extensions["x-code-samples"]; ok {
+		return nil
+	}
+	scheme, host := "http", api.Host
+	if len(operation.Schemes) > 0 {
+		scheme = operation.Schemes[0]
+	}
+	if host == "" {
+		host = "localhost:8080"
+	}
+	samples, err := codegen.ActionSamples(route, scheme+"://"+host)
+	if err != nil || len(samples) == 0 {
+		return err
+	}
+	if operation.Extensions == nil {
+		operation.Extensions = make(map[string]interface{})
+	}
+	operation.Extensions["x-code-samples"] = samples
+	return nil
+}
+
+// applyDecompression documents the Content-Encoding header of the requests when the action accepts
+// compressed payloads.
+func applyDecompression(operation *Operation, decompression *design.DecompressionDefinition) {
+	if decompression == nil {
+		return
+	}
+	for _, p := range operation.Parameters {
+		if p.In == "header" && p.Name == "Content-Encoding" {
+			return
+		}
+	}
+	operation.Parameters = append(operation.Parameters, &Parameter{
+		Name:        "Content-Encoding",
+		In:          "header",
+		Description: fmt.Sprintf("Encoding of the request body, decompressed bodies may not exceed %d bytes", decompression.MaxSize),
+		Type:        "string",
+		Enum:        []interface{}{"gzip", "deflate", "identity"},
+	})
+}
+
 func applySecurity(operation *Operation, security *design.SecurityDefinition) {
 	if security != nil && security.Scheme.Kind != design.NoSecurityKind {
 		if security.Scheme.Kind == design.JWTSecurityKind {
@@ -860,6 +1263,14 @@ func applySecurity(operation *Operation, security *design.SecurityDefinition) {
 		}
 		sec := []map[string][]string{{security.Scheme.SchemeName: scopes}}
 		operation.Security = sec
+		if len(security.Roles) > 0 {
+			if operation.Extensions == nil {
+				operation.Extensions = make(map[string]interface{})
+			}
+			if _, ok := operation.Extensions["x-roles"]; !ok {
+				operation.Extensions["x-roles"] = security.Roles
+			}
+		}
 	}
 }
 
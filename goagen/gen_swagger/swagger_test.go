@@ -508,6 +508,74 @@ var _ = Describe("New", func() {
 			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
 		})
 
+		Context("with response compression", func() {
+			BeforeEach(func() {
+				Widget := MediaType("application/vnd.widget", func() {
+					Attributes(func() {
+						Attribute("name", String)
+					})
+					View("default", func() {
+						Attribute("name")
+					})
+				})
+				Resource("widget", func() {
+					Compress(func() {
+						Encodings("gzip")
+						MinSize(256)
+					})
+					Action("show", func() {
+						Routing(GET("/widget"))
+						Response(OK, Widget)
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("documents the Content-Encoding header of the responses with a body", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				get := swagger.Paths["/widget"].Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Responses["200"].Headers).Should(HaveKey("Content-Encoding"))
+				h := get.Responses["200"].Headers["Content-Encoding"]
+				Ω(h.Type).Should(Equal("string"))
+				Ω(h.Enum).Should(Equal([]interface{}{"gzip"}))
+				Ω(h.Description).Should(ContainSubstring("256 bytes"))
+				Ω(get.Responses["204"].Headers).ShouldNot(HaveKey("Content-Encoding"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with a maintenance response", func() {
+			BeforeEach(func() {
+				base := Design.DSLFunc
+				Design.DSLFunc = func() {
+					base()
+					Maintenance(func() {
+						Description("Restocking")
+						Media(ErrorMedia)
+					})
+				}
+				Resource("widget", func() {
+					Action("show", func() {
+						Routing(GET("/widget"))
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("documents the maintenance response of all the actions", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				get := swagger.Paths["/widget"].Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Responses).Should(HaveKey("503"))
+				Ω(get.Responses["503"].Description).Should(Equal("Restocking"))
+				Ω(get.Responses["503"].Schema).ShouldNot(BeNil())
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
 		Context("with metadata", func() {
 			const gat = "gat"
 
@@ -518,9 +586,15 @@ var _ = Describe("New", func() {
 					Action("act", func() {
 						Metadata("swagger:tag:Update")
 						Metadata("struct:tag:json", "action")
+						Metadata("openapi:extension:x-amazon-apigateway-integration", `{"type":"http_proxy"}`)
 						Routing(
 							PUT("/"),
 						)
+						Params(func() {
+							Param("p", String, func() {
+								Metadata("swagger:extension:x-example", "foo")
+							})
+						})
 					})
 				})
 				base := Design.DSLFunc
@@ -528,6 +602,7 @@ var _ = Describe("New", func() {
 					base()
 					Metadata("swagger:tag:" + gat)
 					Metadata("struct:tag:json", "api")
+					Metadata("openapi:extension:x-logo", "http://example.com/logo.png")
 				}
 			})
 
@@ -545,6 +620,24 @@ var _ = Describe("New", func() {
 				tags := []string{"res", "Update"}
 				Ω(swagger.Paths[""].Put.Tags).Should(Equal(tags))
 			})
+
+			It("should set the vendor extensions", func() {
+				Ω(swagger.Extensions).Should(Equal(map[string]interface{}{"x-logo": "http://example.com/logo.png"}))
+				put := swagger.Paths[""].Put
+				Ω(put.Extensions).Should(HaveLen(2))
+				Ω(put.Extensions).Should(HaveKeyWithValue("x-amazon-apigateway-integration", map[string]interface{}{"type": "http_proxy"}))
+				Ω(put.Extensions).Should(HaveKey("x-code-samples"))
+				Ω(put.Parameters).Should(HaveLen(1))
+				Ω(put.Parameters[0].Extensions).Should(Equal(map[string]interface{}{"x-example": "foo"}))
+			})
+
+			It("serializes the vendor extensions", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"x-logo":"http://example.com/logo.png"`),
+					[]byte(`"x-amazon-apigateway-integration":{"type":"http_proxy"}`),
+					[]byte(`"x-example":"foo"`),
+				})
+			})
 		})
 	})
 })
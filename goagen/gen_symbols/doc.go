@@ -0,0 +1,11 @@
+/*
+Package gensymbols provides the symbol information needed by editor integrations such as language
+servers to offer go-to-definition and find-references across design files. The index lists where
+each user type, media type, resource and action is declared and the references between them.
+
+Tools may build the index of an evaluated design with Build or load the index of a design
+package with Load. The generator entry point also makes it possible to print the index in JSON:
+
+	goagen symbols -d github.com/example/cellar/design
+*/
+package gensymbols
@@ -0,0 +1,13 @@
+package gensymbols_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenSymbols(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Symbols Generator Suite")
+}
@@ -0,0 +1,61 @@
+package gensymbols
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Generate is the generator entry point called by the meta generator. It returns the JSON
+// representation of the design symbol index instead of a list of generated files.
+func Generate() ([]string, error) {
+	var ver string
+	set := flag.NewFlagSet("symbols", flag.PanicOnError)
+	set.String("out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(Build(design.Design))
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(b)}, nil
+}
+
+// Load compiles and runs the design package with the given import path and returns its symbol
+// index.
+func Load(designPkgPath string) (*Index, error) {
+	out, err := ioutil.TempDir("", "gensymbols")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+	gen, err := meta.NewGenerator(
+		"gensymbols.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_symbols")},
+		map[string]string{"design": designPkgPath, "out": out},
+	)
+	if err != nil {
+		return nil, err
+	}
+	res, err := gen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal([]byte(strings.Join(res, "\n")), &idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
@@ -0,0 +1,245 @@
+package gensymbols
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Symbol kinds
+const (
+	// KindType is the kind of user type symbols.
+	KindType = "type"
+	// KindMediaType is the kind of media type symbols.
+	KindMediaType = "media_type"
+	// KindResource is the kind of resource symbols.
+	KindResource = "resource"
+	// KindAction is the kind of action symbols.
+	KindAction = "action"
+)
+
+type (
+	// Index lists the symbols of a design.
+	Index struct {
+		// Symbols lists the design symbols sorted by ID.
+		Symbols []*Symbol `json:"symbols"`
+	}
+
+	// Symbol describes a design definition.
+	Symbol struct {
+		// ID uniquely identifies the symbol, e.g. "type:Bottle" or "action:bottle.show".
+		ID string `json:"id"`
+		// Kind is the symbol kind, one of KindType, KindMediaType, KindResource or
+		// KindAction.
+		Kind string `json:"kind"`
+		// Name is the symbol name: the type name, media type identifier, resource name or
+		// action name.
+		Name string `json:"name"`
+		// Container is the ID of the symbol containing this symbol if any, e.g. the resource
+		// of an action.
+		Container string `json:"container,omitempty"`
+		// File is the absolute path to the file declaring the symbol, empty for generated
+		// definitions such as the media types created by CollectionOf.
+		File string `json:"file,omitempty"`
+		// Line is the line of File where the symbol is declared.
+		Line int `json:"line,omitempty"`
+		// References lists the IDs of the symbols referenced by this symbol sorted
+		// alphabetically.
+		References []string `json:"references,omitempty"`
+	}
+)
+
+// Build returns the symbol index of the given API definition. The DSL must have run.
+func Build(api *design.APIDefinition) *Index {
+	idx := &Index{}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		refs := make(map[string]bool)
+		attributeRefs(api, ut.AttributeDefinition, refs)
+		idx.add(&Symbol{
+			ID:         typeID(ut.TypeName),
+			Kind:       KindType,
+			Name:       ut.TypeName,
+			File:       ut.Location.File,
+			Line:       ut.Location.Line,
+			References: sortedRefs(refs),
+		})
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		refs := make(map[string]bool)
+		attributeRefs(api, mt.AttributeDefinition, refs)
+		idx.add(&Symbol{
+			ID:         mediaTypeID(mt.Identifier),
+			Kind:       KindMediaType,
+			Name:       mt.Identifier,
+			File:       mt.Location.File,
+			Line:       mt.Location.Line,
+			References: sortedRefs(refs),
+		})
+		return nil
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		refs := make(map[string]bool)
+		mediaTypeRef(api, r.MediaType, refs)
+		if r.ParentName != "" {
+			refs[resourceID(r.ParentName)] = true
+		}
+		idx.add(&Symbol{
+			ID:         resourceID(r.Name),
+			Kind:       KindResource,
+			Name:       r.Name,
+			File:       r.Location.File,
+			Line:       r.Location.Line,
+			References: sortedRefs(refs),
+		})
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			refs := make(map[string]bool)
+			if a.Payload != nil {
+				if _, ok := api.Types[a.Payload.TypeName]; ok {
+					refs[typeID(a.Payload.TypeName)] = true
+				} else {
+					attributeRefs(api, a.Payload.AttributeDefinition, refs)
+				}
+			}
+			attributeRefs(api, a.Params, refs)
+			attributeRefs(api, a.Headers, refs)
+			for _, resp := range a.Responses {
+				mediaTypeRef(api, resp.MediaType, refs)
+				if resp.Type != nil {
+					attributeRefs(api, &design.AttributeDefinition{Type: resp.Type}, refs)
+				}
+			}
+			idx.add(&Symbol{
+				ID:         actionID(r.Name, a.Name),
+				Kind:       KindAction,
+				Name:       a.Name,
+				Container:  resourceID(r.Name),
+				File:       a.Location.File,
+				Line:       a.Location.Line,
+				References: sortedRefs(refs),
+			})
+			return nil
+		})
+	})
+	sort.Sort(byID(idx.Symbols))
+	return idx
+}
+
+// Symbol returns the symbol with the given ID, nil if there is none.
+func (idx *Index) Symbol(id string) *Symbol {
+	i := sort.Search(len(idx.Symbols), func(i int) bool { return idx.Symbols[i].ID >= id })
+	if i < len(idx.Symbols) && idx.Symbols[i].ID == id {
+		return idx.Symbols[i]
+	}
+	return nil
+}
+
+// References returns the symbols that reference the symbol with the given ID.
+func (idx *Index) References(id string) []*Symbol {
+	var res []*Symbol
+	for _, s := range idx.Symbols {
+		for _, ref := range s.References {
+			if ref == id {
+				res = append(res, s)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// DeclaredIn returns the symbols declared in the given file sorted by line.
+func (idx *Index) DeclaredIn(file string) []*Symbol {
+	var res []*Symbol
+	for _, s := range idx.Symbols {
+		if s.File == file {
+			res = append(res, s)
+		}
+	}
+	sort.Stable(byLine(res))
+	return res
+}
+
+// add appends the symbol to the index.
+func (idx *Index) add(s *Symbol) {
+	idx.Symbols = append(idx.Symbols, s)
+}
+
+// attributeRefs records the IDs of the user types and media types referenced by the given
+// attribute. It does not recurse into the referenced types.
+func attributeRefs(api *design.APIDefinition, att *design.AttributeDefinition, refs map[string]bool) {
+	if att == nil || att.Type == nil {
+		return
+	}
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		mediaTypeRef(api, actual.Identifier, refs)
+	case *design.UserTypeDefinition:
+		refs[typeID(actual.TypeName)] = true
+	case design.Object:
+		for _, n := range sortedNames(actual) {
+			attributeRefs(api, actual[n], refs)
+		}
+	case *design.Array:
+		attributeRefs(api, actual.ElemType, refs)
+	case *design.Hash:
+		attributeRefs(api, actual.KeyType, refs)
+		attributeRefs(api, actual.ElemType, refs)
+	}
+}
+
+// mediaTypeRef records the ID of the media type with the given identifier if it is defined in the
+// design. Collections created with CollectionOf also reference their element type.
+func mediaTypeRef(api *design.APIDefinition, identifier string, refs map[string]bool) {
+	mt := api.MediaTypeWithIdentifier(identifier)
+	if mt == nil || mt.IsError() {
+		return
+	}
+	refs[mediaTypeID(mt.Identifier)] = true
+	if mt.IsArray() {
+		attributeRefs(api, mt.ToArray().ElemType, refs)
+	}
+}
+
+// sortedRefs returns the given reference IDs sorted alphabetically.
+func sortedRefs(refs map[string]bool) []string {
+	if len(refs) == 0 {
+		return nil
+	}
+	res := make([]string, 0, len(refs))
+	for r := range refs {
+		res = append(res, r)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// sortedNames returns the object attribute names sorted alphabetically.
+func sortedNames(o design.Object) []string {
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func typeID(name string) string           { return KindType + ":" + name }
+func mediaTypeID(identifier string) string { return KindMediaType + ":" + design.CanonicalIdentifier(identifier) }
+func resourceID(name string) string       { return KindResource + ":" + name }
+func actionID(res, name string) string    { return fmt.Sprintf("%s:%s.%s", KindAction, res, name) }
+
+// byID sorts symbols by ID.
+type byID []*Symbol
+
+func (b byID) Len() int           { return len(b) }
+func (b byID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byID) Less(i, j int) bool { return b[i].ID < b[j].ID }
+
+// byLine sorts symbols by line.
+type byLine []*Symbol
+
+func (b byLine) Len() int           { return len(b) }
+func (b byLine) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byLine) Less(i, j int) bool { return b[i].Line < b[j].Line }
@@ -0,0 +1,80 @@
+package gensymbols_test
+
+import (
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_symbols"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Build", func() {
+	var idx *gensymbols.Index
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", nil)
+		origin := Type("Origin", func() {
+			Attribute("country", String)
+		})
+		bottle := MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("name", String)
+				Attribute("origin", origin)
+			})
+			View("default", func() {
+				Attribute("name")
+			})
+		})
+		payload := Type("BottlePayload", func() {
+			Attribute("name", String)
+		})
+		Resource("bottle", func() {
+			DefaultMedia(bottle)
+			Action("list", func() {
+				Routing(GET(""))
+				Response(OK, CollectionOf(bottle))
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(payload)
+				Response(Created)
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		idx = gensymbols.Build(Design)
+	})
+
+	It("lists the symbols", func() {
+		Ω(idx.Symbol("type:Origin")).ShouldNot(BeNil())
+		Ω(idx.Symbol("media_type:application/vnd.bottle")).ShouldNot(BeNil())
+		Ω(idx.Symbol("resource:bottle")).ShouldNot(BeNil())
+		list := idx.Symbol("action:bottle.list")
+		Ω(list).ShouldNot(BeNil())
+		Ω(list.Container).Should(Equal("resource:bottle"))
+		Ω(idx.Symbol("type:Unknown")).Should(BeNil())
+	})
+
+	It("records the declaration locations", func() {
+		origin := idx.Symbol("type:Origin")
+		Ω(filepath.Base(origin.File)).Should(Equal("symbols_test.go"))
+		Ω(origin.Line).Should(Equal(20))
+		Ω(idx.DeclaredIn(origin.File)).Should(HaveLen(6))
+	})
+
+	It("records the references", func() {
+		Ω(idx.Symbol("media_type:application/vnd.bottle").References).Should(Equal([]string{"type:Origin"}))
+		Ω(idx.Symbol("action:bottle.list").References).Should(ContainElement("media_type:application/vnd.bottle"))
+		Ω(idx.Symbol("action:bottle.create").References).Should(Equal([]string{"type:BottlePayload"}))
+		refs := idx.References("media_type:application/vnd.bottle")
+		var ids []string
+		for _, r := range refs {
+			ids = append(ids, r.ID)
+		}
+		Ω(ids).Should(ContainElement("action:bottle.list"))
+		Ω(ids).Should(ContainElement("resource:bottle"))
+	})
+})
@@ -0,0 +1,20 @@
+/*
+Package genterraform provides a goa generator for Terraform modules.
+The generated module describes the cloud footprint of the API on AWS: an API Gateway REST API whose
+routes are derived from the design, the stage and deployment, an optional custom domain, a usage
+plan whose throttling settings come from the "gateway:rate-limit" metadata and the compute backend
+serving the requests, either a Fargate ECS service or a Lambda function.
+
+The module is generated in the "terraform" directory and is parameterized with variables, for
+example:
+
+	module "cellar" {
+	  source       = "./terraform"
+	  upstream_url = "http://cellar-alb.example.com"
+	  image        = "example/cellar:latest"
+	  ...
+	}
+
+Regenerating the module after changing the design keeps the infrastructure in sync with the API.
+*/
+package genterraform
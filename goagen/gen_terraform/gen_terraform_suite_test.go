@@ -0,0 +1,13 @@
+package genterraform_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenTerraform(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenTerraform Suite")
+}
@@ -0,0 +1,526 @@
+package genterraform
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_gateway"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// Generator is the Terraform module generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Destination directory
+	Backend  string                // Compute backend: "ecs" or "lambda"
+	genfiles []string              // Generated files
+}
+
+// Throttle describes the API Gateway throttling settings derived from a rate limit.
+type Throttle struct {
+	Path       string  // Resource path and HTTP method, e.g. "/bottles/{id}/GET", empty for API
+	RateLimit  float64 // Steady-state number of requests per second
+	BurstLimit int     // Maximum number of concurrent requests
+}
+
+// upstreamPlaceholder is replaced with the Terraform template variable holding the integration
+// URI after the specification has been escaped.
+const upstreamPlaceholder = "@@UPSTREAM@@"
+
+// invalidStageChars matches the characters not allowed in API Gateway stage names.
+var invalidStageChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// funcMap is the template function map used to render the module.
+var funcMap = template.FuncMap{"hcl": hclString}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, backend, ver string
+	)
+
+	set := flag.NewFlagSet("terraform", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&backend, "backend", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	// First check compatibility
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	// Now proceed
+	g := &Generator{OutDir: outDir, Backend: backend, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Terraform module.
+func (g *Generator) Generate() (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Backend == "" {
+		g.Backend = "ecs"
+	}
+	if g.Backend != "ecs" && g.Backend != "lambda" {
+		return nil, fmt.Errorf(`invalid backend "%s", must be "ecs" or "lambda"`, g.Backend)
+	}
+
+	spec, err := g.spec()
+	if err != nil {
+		return
+	}
+	throttles, apiThrottle, err := g.throttles(spec.basePath)
+	if err != nil {
+		return
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "terraform")
+	if err = os.RemoveAll(g.OutDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, g.OutDir)
+
+	specFile := filepath.Join(g.OutDir, "openapi.json.tpl")
+	if err = ioutil.WriteFile(specFile, spec.content, 0644); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, specFile)
+
+	stage := "default"
+	if g.API.Version != "" {
+		stage = invalidStageChars.ReplaceAllString(g.API.Version, "_")
+	}
+	data := map[string]interface{}{
+		"API":         g.API,
+		"Name":        strings.Replace(codegen.SnakeCase(g.API.Name), "_", "-", -1),
+		"Lambda":      g.Backend == "lambda",
+		"Stage":       stage,
+		"Throttles":   throttles,
+		"APIThrottle": apiThrottle,
+	}
+	for _, f := range []struct{ name, tmpl string }{
+		{"main.tf", mainT},
+		{"variables.tf", variablesT},
+		{"outputs.tf", outputsT},
+	} {
+		if err = g.render(f.name, f.tmpl, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// render executes the given template and writes the result to the file with the given name in the
+// output directory.
+func (g *Generator) render(name, tmpl string, data interface{}) error {
+	path := filepath.Join(g.OutDir, name)
+	file, err := codegen.SourceFileFor(path)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	return file.ExecuteTemplate(name, tmpl, funcMap, data)
+}
+
+// awsSpec holds the Terraform template of the API Gateway OpenAPI definition.
+type awsSpec struct {
+	content  []byte // Escaped template content
+	basePath string // Base path of the specification
+}
+
+// spec builds the API Gateway OpenAPI definition template. The integrations refer to the Terraform
+// template variable "upstream_url" for ECS backends and "lambda_invoke_arn" for Lambda backends.
+func (g *Generator) spec() (*awsSpec, error) {
+	integration := gengateway.HTTPProxyIntegration(upstreamPlaceholder)
+	variable := "${upstream_url}"
+	if g.Backend == "lambda" {
+		integration = func(_, _ string, _ []string) map[string]interface{} {
+			return map[string]interface{}{
+				"type":                "aws_proxy",
+				"httpMethod":          "POST",
+				"uri":                 upstreamPlaceholder,
+				"passthroughBehavior": "when_no_match",
+			}
+		}
+		variable = "${lambda_invoke_arn}"
+	}
+	s, err := gengateway.AWSSpec(g.API, integration)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	content := escapeTemplate(string(b))
+	content = strings.Replace(content, upstreamPlaceholder, variable, -1)
+	return &awsSpec{content: []byte(content), basePath: s.BasePath}, nil
+}
+
+// throttles computes the API Gateway throttling settings from the design rate limits. It returns
+// the per-method settings and the API wide settings if any.
+func (g *Generator) throttles(basePath string) ([]*Throttle, *Throttle, error) {
+	apiLimit, err := gengateway.RateLimitFromDefinition(g.API.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	routes, err := gengateway.Routes(g.API)
+	if err != nil {
+		return nil, nil, err
+	}
+	var throttles []*Throttle
+	for _, r := range routes {
+		if r.RateLimit == nil || (apiLimit != nil && *r.RateLimit == *apiLimit) {
+			continue
+		}
+		path := design.WildcardRegex.ReplaceAllStringFunc(r.Path, func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		})
+		if basePath != "/" {
+			path = strings.TrimPrefix(path, basePath)
+		}
+		if path == "" {
+			path = "/"
+		}
+		t := throttle(r.RateLimit)
+		t.Path = path + "/" + r.Verb
+		throttles = append(throttles, t)
+	}
+	if apiLimit == nil {
+		return throttles, nil, nil
+	}
+	return throttles, throttle(apiLimit), nil
+}
+
+// throttle converts the given rate limit into API Gateway throttling settings.
+func throttle(l *gengateway.RateLimit) *Throttle {
+	rate := float64(l.Requests) / float64(l.Seconds())
+	burst := l.Requests
+	if l.Seconds() > 1 {
+		burst = int(math.Ceil(rate))
+	}
+	return &Throttle{RateLimit: rate, BurstLimit: burst}
+}
+
+// escapeTemplate escapes the Terraform template sequences that appear in s.
+func escapeTemplate(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
+}
+
+// hclString returns the HCL string literal for s.
+func hclString(s string) string {
+	return escapeTemplate(strconv.Quote(s))
+}
+
+const mainT = `# Terraform module for the {{ .API.Name }} API.
+# Generated by goagen, DO NOT MODIFY.
+
+locals {
+  name = "{{ .Name }}"
+}
+
+resource "aws_api_gateway_rest_api" "api" {
+  name        = local.name
+  description = {{ hcl .API.Description }}
+  body = templatefile("${path.module}/openapi.json.tpl", {
+{{ if .Lambda }}    lambda_invoke_arn = aws_lambda_function.service.invoke_arn
+{{ else }}    upstream_url = var.upstream_url
+{{ end }}  })
+
+  endpoint_configuration {
+    types = [var.endpoint_type]
+  }
+}
+
+resource "aws_api_gateway_deployment" "api" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+
+  triggers = {
+    redeployment = sha1(aws_api_gateway_rest_api.api.body)
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+resource "aws_api_gateway_stage" "api" {
+  deployment_id = aws_api_gateway_deployment.api.id
+  rest_api_id   = aws_api_gateway_rest_api.api.id
+  stage_name    = var.stage_name
+}
+
+resource "aws_api_gateway_domain_name" "api" {
+  count                    = var.domain_name == "" ? 0 : 1
+  domain_name              = var.domain_name
+  regional_certificate_arn = var.certificate_arn
+
+  endpoint_configuration {
+    types = ["REGIONAL"]
+  }
+}
+
+resource "aws_api_gateway_base_path_mapping" "api" {
+  count       = var.domain_name == "" ? 0 : 1
+  api_id      = aws_api_gateway_rest_api.api.id
+  stage_name  = aws_api_gateway_stage.api.stage_name
+  domain_name = aws_api_gateway_domain_name.api[0].domain_name
+}
+{{ if or .Throttles .APIThrottle }}
+resource "aws_api_gateway_usage_plan" "api" {
+  name = "${local.name}-usage-plan"
+
+  api_stages {
+    api_id = aws_api_gateway_rest_api.api.id
+    stage  = aws_api_gateway_stage.api.stage_name
+{{ range .Throttles }}
+    throttle {
+      path        = "{{ .Path }}"
+      rate_limit  = {{ .RateLimit }}
+      burst_limit = {{ .BurstLimit }}
+    }
+{{ end }}  }
+{{ with .APIThrottle }}
+  throttle_settings {
+    rate_limit  = {{ .RateLimit }}
+    burst_limit = {{ .BurstLimit }}
+  }
+{{ end }}}
+{{ end }}{{ if .Lambda }}
+resource "aws_lambda_function" "service" {
+  function_name    = local.name
+  role             = var.lambda_role_arn
+  filename         = var.lambda_package
+  source_code_hash = filebase64sha256(var.lambda_package)
+  handler          = var.lambda_handler
+  runtime          = var.lambda_runtime
+  memory_size      = var.lambda_memory_size
+  timeout          = var.lambda_timeout
+}
+
+resource "aws_lambda_permission" "api" {
+  statement_id  = "AllowAPIGatewayInvoke"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.service.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.api.execution_arn}/*/*"
+}
+{{ else }}
+resource "aws_ecs_task_definition" "service" {
+  family                   = local.name
+  requires_compatibilities = ["FARGATE"]
+  network_mode             = "awsvpc"
+  cpu                      = var.cpu
+  memory                   = var.memory
+  execution_role_arn       = var.execution_role_arn
+
+  container_definitions = jsonencode([{
+    name      = local.name
+    image     = var.image
+    essential = true
+    portMappings = [{
+      containerPort = var.container_port
+    }]
+  }])
+}
+
+resource "aws_ecs_service" "service" {
+  name            = local.name
+  cluster         = var.cluster_arn
+  task_definition = aws_ecs_task_definition.service.arn
+  desired_count   = var.desired_count
+  launch_type     = "FARGATE"
+
+  network_configuration {
+    subnets         = var.subnet_ids
+    security_groups = var.security_group_ids
+  }
+
+  load_balancer {
+    target_group_arn = var.target_group_arn
+    container_name   = local.name
+    container_port   = var.container_port
+  }
+}
+{{ end }}`
+
+const variablesT = `# Generated by goagen, DO NOT MODIFY.
+
+variable "stage_name" {
+  description = "Name of the API Gateway stage."
+  type        = string
+  default     = "{{ .Stage }}"
+}
+
+variable "endpoint_type" {
+  description = "API Gateway endpoint type, one of EDGE, REGIONAL or PRIVATE."
+  type        = string
+  default     = "REGIONAL"
+}
+
+variable "domain_name" {
+  description = "Custom domain name of the API, no custom domain is created if empty."
+  type        = string
+  default     = ""
+}
+
+variable "certificate_arn" {
+  description = "ARN of the ACM certificate used by the custom domain."
+  type        = string
+  default     = ""
+}
+{{ if .Lambda }}
+variable "lambda_package" {
+  description = "Path to the zip archive containing the service binary."
+  type        = string
+}
+
+variable "lambda_role_arn" {
+  description = "ARN of the IAM role assumed by the service function."
+  type        = string
+}
+
+variable "lambda_handler" {
+  description = "Name of the service function handler."
+  type        = string
+  default     = "bootstrap"
+}
+
+variable "lambda_runtime" {
+  description = "Runtime of the service function."
+  type        = string
+  default     = "provided.al2"
+}
+
+variable "lambda_memory_size" {
+  description = "Amount of memory in MB allocated to the service function."
+  type        = number
+  default     = 128
+}
+
+variable "lambda_timeout" {
+  description = "Timeout in seconds of the service function."
+  type        = number
+  default     = 30
+}
+{{ else }}
+variable "upstream_url" {
+  description = "URL of the load balancer forwarding requests to the service, e.g. http://my-alb.example.com."
+  type        = string
+}
+
+variable "image" {
+  description = "Docker image of the service."
+  type        = string
+}
+
+variable "cluster_arn" {
+  description = "ARN of the ECS cluster running the service."
+  type        = string
+}
+
+variable "execution_role_arn" {
+  description = "ARN of the IAM role used to start the service tasks."
+  type        = string
+}
+
+variable "subnet_ids" {
+  description = "IDs of the subnets the service tasks run in."
+  type        = list(string)
+}
+
+variable "security_group_ids" {
+  description = "IDs of the security groups of the service tasks."
+  type        = list(string)
+  default     = []
+}
+
+variable "target_group_arn" {
+  description = "ARN of the load balancer target group the service tasks are registered with."
+  type        = string
+}
+
+variable "container_port" {
+  description = "Port the service listens on."
+  type        = number
+  default     = 8080
+}
+
+variable "cpu" {
+  description = "Number of CPU units allocated to each task."
+  type        = number
+  default     = 256
+}
+
+variable "memory" {
+  description = "Amount of memory in MB allocated to each task."
+  type        = number
+  default     = 512
+}
+
+variable "desired_count" {
+  description = "Number of tasks to run."
+  type        = number
+  default     = 1
+}
+{{ end }}`
+
+const outputsT = `# Generated by goagen, DO NOT MODIFY.
+
+output "rest_api_id" {
+  description = "ID of the API Gateway REST API."
+  value       = aws_api_gateway_rest_api.api.id
+}
+
+output "invoke_url" {
+  description = "URL used to invoke the API stage."
+  value       = aws_api_gateway_stage.api.invoke_url
+}
+
+output "domain_regional_name" {
+  description = "Hostname targeted by the custom domain DNS record, empty if there is no custom domain."
+  value       = var.domain_name == "" ? "" : aws_api_gateway_domain_name.api[0].regional_domain_name
+}
+{{ if .Lambda }}
+output "function_arn" {
+  description = "ARN of the service function."
+  value       = aws_lambda_function.service.arn
+}
+{{ else }}
+output "service_id" {
+  description = "ID of the ECS service."
+  value       = aws_ecs_service.service.id
+}
+{{ end }}`
@@ -0,0 +1,123 @@
+package genterraform_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_terraform"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_terraform/test_"
+
+	var outDir string
+	var backend string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend = ""
+
+		action := &design.ActionDefinition{
+			Name: "show",
+			Params: &design.AttributeDefinition{
+				Type: design.Object{
+					"id": &design.AttributeDefinition{Type: design.Integer},
+				},
+			},
+			Responses: map[string]*design.ResponseDefinition{
+				"NoContent": {Name: "NoContent", Status: 204},
+			},
+			Metadata: dslengine.MetadataDefinition{"gateway:rate-limit": {"10/second"}},
+		}
+		route := &design.RouteDefinition{
+			Verb:   "GET",
+			Path:   "/:id",
+			Parent: action,
+		}
+		action.Routes = []*design.RouteDefinition{route}
+		res := &design.ResourceDefinition{
+			Name:     "bottle",
+			BasePath: "/bottles",
+			Actions:  map[string]*design.ActionDefinition{"show": action},
+		}
+		action.Parent = res
+		design.Design = &design.APIDefinition{
+			Name:        "cellar",
+			Description: "The ${wine} cellar",
+			Version:     "1.0",
+			Resources:   map[string]*design.ResourceDefinition{"bottle": res},
+			Metadata:    dslengine.MetadataDefinition{"gateway:rate-limit": {"3600/hour"}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--backend=" + backend,
+			"--version=" + version.String()}
+		files, genErr = genterraform.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("generates the module", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(5))
+		content, err := ioutil.ReadFile(filepath.Join(outDir, "terraform", "main.tf"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(ContainSubstring(`description = "The $${wine} cellar"`))
+		Ω(string(content)).Should(ContainSubstring(`upstream_url = var.upstream_url`))
+		Ω(string(content)).Should(ContainSubstring(`resource "aws_ecs_service" "service"`))
+		Ω(string(content)).Should(ContainSubstring(`path        = "/bottles/{id}/GET"`))
+		Ω(string(content)).Should(ContainSubstring("rate_limit  = 10\n"))
+		Ω(string(content)).Should(ContainSubstring("rate_limit  = 1\n"))
+		vars, err := ioutil.ReadFile(filepath.Join(outDir, "terraform", "variables.tf"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(vars)).Should(ContainSubstring(`default     = "1_0"`))
+	})
+
+	It("generates the API Gateway definition template", func() {
+		content, err := ioutil.ReadFile(filepath.Join(outDir, "terraform", "openapi.json.tpl"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(content)).Should(ContainSubstring(`"uri": "${upstream_url}/bottles/{id}"`))
+		Ω(string(content)).Should(ContainSubstring(`The $${wine} cellar`))
+	})
+
+	Context("with a Lambda backend", func() {
+		BeforeEach(func() {
+			backend = "lambda"
+		})
+
+		It("generates the function", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "terraform", "main.tf"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`resource "aws_lambda_function" "service"`))
+			Ω(string(content)).Should(ContainSubstring(`lambda_invoke_arn = aws_lambda_function.service.invoke_arn`))
+			spec, err := ioutil.ReadFile(filepath.Join(outDir, "terraform", "openapi.json.tpl"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(spec)).Should(ContainSubstring(`"uri": "${lambda_invoke_arn}"`))
+		})
+	})
+
+	Context("with an invalid backend", func() {
+		BeforeEach(func() {
+			backend = "k8s"
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+	})
+})
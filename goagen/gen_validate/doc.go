@@ -0,0 +1,11 @@
+/*
+Package genvalidate provides a goa generator that validates the design without generating any
+file. The generator runs the DSL and writes a JSON report to standard output listing the DSL and
+validation errors, warnings about likely design mistakes and statistics about the API. The report
+is intended for CI pipelines and editor plugins:
+
+	goagen validate -d github.com/example/cellar/design
+
+The command exits with status 1 if the design is invalid. Warnings do not affect the exit status.
+*/
+package genvalidate
@@ -0,0 +1,173 @@
+package genvalidate
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Report is the validation report written by the generator.
+	Report struct {
+		// Valid is true if the design has no error.
+		Valid bool `json:"valid"`
+		// Errors lists the DSL and validation errors.
+		Errors []*Issue `json:"errors"`
+		// Warnings lists likely design mistakes that do not prevent code generation.
+		Warnings []*Issue `json:"warnings"`
+		// Stats contains statistics about the API, it is nil if the DSL failed to run.
+		Stats *Stats `json:"stats,omitempty"`
+	}
+
+	// Issue describes an error or a warning.
+	Issue struct {
+		// Message is the error or warning message.
+		Message string `json:"message"`
+		// Definition describes the definition the issue relates to if any, e.g.
+		// `resource "bottle"`.
+		Definition string `json:"definition,omitempty"`
+		// File is the path to the design file where the error occurred if known.
+		File string `json:"file,omitempty"`
+		// Line is the line in File where the error occurred if known.
+		Line int `json:"line,omitempty"`
+	}
+
+	// Stats contains statistics about the API.
+	Stats struct {
+		Resources  int `json:"resources"`
+		Endpoints  int `json:"endpoints"`
+		Routes     int `json:"routes"`
+		Types      int `json:"types"`
+		MediaTypes int `json:"media_types"`
+	}
+)
+
+// Generate is the generator entry point called by the meta generator. The meta generator does not
+// run the DSL prior to calling Generate so that the DSL errors can be reported.
+func Generate() ([]string, error) {
+	var ver string
+	set := flag.NewFlagSet("validate", flag.PanicOnError)
+	set.String("out", "", "")
+	set.String("design", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	report := Validate()
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(b)}, nil
+}
+
+// Validate runs the DSL and returns the corresponding report. It reports the errors of the DSL
+// functions executed at declaration time without running the DSL if there are any.
+func Validate() *Report {
+	report := &Report{Errors: []*Issue{}, Warnings: []*Issue{}}
+	if dslengine.Errors == nil {
+		dslengine.Run()
+		if dslengine.Errors == nil || !hasDSLErrors(dslengine.Errors) {
+			report.Stats = stats(design.Design)
+			report.Warnings = warnings(design.Design)
+		}
+	}
+	report.Errors = issues(dslengine.Errors)
+	report.Valid = len(report.Errors) == 0
+	return report
+}
+
+// hasDSLErrors returns true if errs contains errors other than validation errors, that is if
+// the DSL failed to run.
+func hasDSLErrors(errs dslengine.MultiError) bool {
+	for _, e := range errs {
+		if _, ok := e.GoError.(*dslengine.ValidationErrors); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// issues converts DSL errors into issues, validation errors are split into one issue per error.
+func issues(errs dslengine.MultiError) []*Issue {
+	res := []*Issue{}
+	for _, e := range errs {
+		if verr, ok := e.GoError.(*dslengine.ValidationErrors); ok {
+			for i, err := range verr.Errors {
+				res = append(res, &Issue{
+					Message:    err.Error(),
+					Definition: verr.Definitions[i].Context(),
+				})
+			}
+			continue
+		}
+		res = append(res, &Issue{Message: e.GoError.Error(), File: e.File, Line: e.Line})
+	}
+	return res
+}
+
+// stats computes the API statistics.
+func stats(api *design.APIDefinition) *Stats {
+	s := &Stats{
+		Resources:  len(api.Resources),
+		Types:      len(api.Types),
+		MediaTypes: len(api.MediaTypes),
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			s.Endpoints++
+			s.Routes += len(a.Routes)
+			return nil
+		})
+	})
+	return s
+}
+
+// warnings lists the user types and media types that are not used by any action and the actions
+// that define no response.
+func warnings(api *design.APIDefinition) []*Issue {
+	res := []*Issue{}
+	used := make(map[string]bool)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			for n := range a.UserTypes() {
+				used[n] = true
+			}
+			if len(a.Responses) == 0 {
+				res = append(res, &Issue{
+					Message:    "action does not define any response",
+					Definition: a.Context(),
+				})
+			}
+			return nil
+		})
+	})
+	var unused []*Issue
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if !used[ut.TypeName] {
+			unused = append(unused, &Issue{
+				Message:    fmt.Sprintf("type %s is not used by any action", ut.TypeName),
+				Definition: ut.Context(),
+			})
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if !used[mt.TypeName] && !mt.IsError() {
+			unused = append(unused, &Issue{
+				Message:    fmt.Sprintf("media type %s is not used by any action", mt.Identifier),
+				Definition: mt.Context(),
+			})
+		}
+		return nil
+	})
+	return append(res, unused...)
+}
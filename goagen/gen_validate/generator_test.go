@@ -0,0 +1,102 @@
+package genvalidate_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_validate"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+	var report *genvalidate.Report
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", nil)
+	})
+
+	JustBeforeEach(func() {
+		report = genvalidate.Validate()
+	})
+
+	Context("with a valid design", func() {
+		BeforeEach(func() {
+			bottle := MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("name", String)
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			Type("Unused", func() {
+				Attribute("name", String)
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"), GET("/bottles/:id"))
+					Response(OK, bottle)
+				})
+				Action("delete", func() {
+					Routing(DELETE("/:id"))
+				})
+			})
+		})
+
+		It("reports the design as valid", func() {
+			Ω(report.Valid).Should(BeTrue())
+			Ω(report.Errors).Should(BeEmpty())
+		})
+
+		It("computes the statistics", func() {
+			Ω(report.Stats).ShouldNot(BeNil())
+			Ω(*report.Stats).Should(Equal(genvalidate.Stats{
+				Resources:  1,
+				Endpoints:  2,
+				Routes:     3,
+				Types:      1,
+				MediaTypes: 1,
+			}))
+		})
+
+		It("reports the warnings", func() {
+			Ω(report.Warnings).Should(HaveLen(2))
+			Ω(report.Warnings[0].Message).Should(Equal("action does not define any response"))
+			Ω(report.Warnings[0].Definition).Should(ContainSubstring(`action "delete"`))
+			Ω(report.Warnings[1].Message).Should(Equal("type Unused is not used by any action"))
+		})
+	})
+
+	Context("with an invalid design", func() {
+		BeforeEach(func() {
+			MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("name", String)
+				})
+			})
+		})
+
+		It("reports the validation errors", func() {
+			Ω(report.Valid).Should(BeFalse())
+			Ω(report.Errors).ShouldNot(BeEmpty())
+			Ω(report.Errors[0].Definition).Should(ContainSubstring("Bottle"))
+		})
+	})
+
+	Context("with a DSL error", func() {
+		BeforeEach(func() {
+			Resource("bottle", func() {
+				Routing(GET("/"))
+			})
+		})
+
+		It("reports the error with its location", func() {
+			Ω(report.Valid).Should(BeFalse())
+			Ω(report.Errors).Should(HaveLen(1))
+			Ω(report.Errors[0].File).ShouldNot(BeEmpty())
+			Ω(report.Stats).Should(BeNil())
+		})
+	})
+})
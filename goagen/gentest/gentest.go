@@ -0,0 +1,260 @@
+/*
+Package gentest provides a test harness for generator authors. The harness runs a generator
+in-process against the design evaluated by the test and compares the generated files with the
+content of a golden directory, reporting the differences as line diffs. This avoids building and
+running goagen and diffing its output manually.
+
+A test evaluates the design with designtest.Eval then runs the generator entry point, i.e. the
+Generate function of the generator package:
+
+	func TestGenerate(t *testing.T) {
+		designtest.Eval(t)
+		gentest.Run(t, "testdata/golden", mygen.Generate, "--pkg=things")
+	}
+
+The generated files refer to the output directory and goa version in their headers, Golden
+replaces them with "$(OUT)" and "$(VERSION)" before comparing so that the golden files do not
+depend on the environment. Setting the GOAGEN_UPDATE_GOLDEN environment variable makes Golden
+overwrite the golden directory with the generated files instead of comparing them.
+*/
+package gentest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goatest"
+	"github.com/goadesign/goa/version"
+)
+
+// UpdateEnv is the name of the environment variable that makes Golden update the golden
+// directories.
+const UpdateEnv = "GOAGEN_UPDATE_GOLDEN"
+
+// maxDiffLines is the maximum number of lines reported in the diff of a file.
+const maxDiffLines = 50
+
+// Generator is a generator entry point. It reads its flags from the command line and returns the
+// paths of the generated files.
+type Generator func() ([]string, error)
+
+// Run generates the files in a temporary directory using Generate, compares them with goldenDir
+// using Golden then deletes the directory.
+func Run(t goatest.TInterface, goldenDir string, genfunc Generator, flags ...string) {
+	outDir, err := ioutil.TempDir("", "gentest")
+	if err != nil {
+		t.Fatalf("failed to create output directory: %s", err)
+		return
+	}
+	defer os.RemoveAll(outDir)
+	Generate(t, genfunc, outDir, flags...)
+	Golden(t, outDir, goldenDir)
+}
+
+// Generate runs the generator with the output directory and version flags followed by the given
+// flags and returns the generated files. It fails the test if the design has not been evaluated or
+// if the generator returns an error. The command line is restored afterwards.
+func Generate(t goatest.TInterface, genfunc Generator, outDir string, flags ...string) []string {
+	if design.Design == nil {
+		t.Fatalf("no design, evaluate the design before running the generator (see designtest.Eval)")
+		return nil
+	}
+	args := os.Args
+	defer func() { os.Args = args }()
+	os.Args = append([]string{"goagen", "--out=" + outDir, "--design=gentest", "--version=" + version.String()}, flags...)
+	files, err := genfunc()
+	if err != nil {
+		t.Fatalf("generator failed: %s", err)
+		return nil
+	}
+	return files
+}
+
+// Golden compares the files of outDir with the files of goldenDir and reports a test error for
+// each missing, unexpected or different file. The differences are reported as line diffs. If the
+// UpdateEnv environment variable is set Golden replaces the content of goldenDir with the files
+// of outDir instead.
+func Golden(t goatest.TInterface, outDir, goldenDir string) {
+	actual, err := readTree(outDir)
+	if err != nil {
+		t.Fatalf("failed to read generated files: %s", err)
+		return
+	}
+	for n, content := range actual {
+		actual[n] = normalize(content, outDir)
+	}
+	if os.Getenv(UpdateEnv) != "" {
+		if err := writeTree(goldenDir, actual); err != nil {
+			t.Fatalf("failed to update golden files: %s", err)
+		}
+		return
+	}
+	expected, err := readTree(goldenDir)
+	if err != nil {
+		t.Fatalf("failed to read golden files: %s (set %s to create them)", err, UpdateEnv)
+		return
+	}
+	for _, n := range sortedNames(expected, actual) {
+		exp, eok := expected[n]
+		act, aok := actual[n]
+		switch {
+		case !aok:
+			t.Errorf("%s: missing, the generator did not produce the golden file", n)
+		case !eok:
+			t.Errorf("%s: unexpected, the generator produced a file that has no golden file", n)
+		case exp != act:
+			t.Errorf("%s: differs from golden file (-golden +generated):\n%s", n, Diff(exp, act))
+		}
+	}
+}
+
+// Diff returns the differences between the lines of a and b. The removed lines are prefixed with
+// "-", the added lines with "+" and the common lines surrounding the changes with " ".
+func Diff(a, b string) string {
+	al, bl := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	// Trim the common prefix and suffix to keep the comparison small.
+	pre := 0
+	for pre < len(al) && pre < len(bl) && al[pre] == bl[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(al)-pre && suf < len(bl)-pre && al[len(al)-1-suf] == bl[len(bl)-1-suf] {
+		suf++
+	}
+	am, bm := al[pre:len(al)-suf], bl[pre:len(bl)-suf]
+
+	var lines []string
+	if pre > 0 {
+		lines = append(lines, fmt.Sprintf("@@ line %d @@", pre+1), " "+al[pre-1])
+	}
+	lines = append(lines, diffLines(am, bm)...)
+	if suf > 0 {
+		lines = append(lines, " "+al[len(al)-suf])
+	}
+	if len(lines) > maxDiffLines {
+		lines = append(lines[:maxDiffLines], fmt.Sprintf("... %d more lines", len(lines)-maxDiffLines))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffLines returns the edit script turning a into b computed from their longest common
+// subsequence. Large inputs are reported as a removal of a followed by an addition of b.
+func diffLines(a, b []string) []string {
+	var lines []string
+	if len(a)*len(b) > 1000000 {
+		for _, l := range a {
+			lines = append(lines, "-"+l)
+		}
+		for _, l := range b {
+			lines = append(lines, "+"+l)
+		}
+		return lines
+	}
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, "+"+b[j])
+			j++
+		default:
+			lines = append(lines, "-"+a[i])
+			i++
+		}
+	}
+	return lines
+}
+
+// normalize replaces the output directory and the goa version with placeholders.
+func normalize(content, outDir string) string {
+	for _, p := range filepath.SplitList(os.Getenv("GOPATH")) {
+		if p != "" && strings.HasPrefix(outDir, p) {
+			content = strings.Replace(content, "$(GOPATH)"+outDir[len(p):], "$(OUT)", -1)
+		}
+	}
+	content = strings.Replace(content, outDir, "$(OUT)", -1)
+	return strings.Replace(content, version.String(), "$(VERSION)", -1)
+}
+
+// readTree returns the content of the files of dir indexed by slash separated path relative to
+// dir.
+func readTree(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(b)
+		return nil
+	})
+	return files, err
+}
+
+// writeTree replaces the content of dir with the given files.
+func writeTree(dir string, files map[string]string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for n, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(n))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedNames returns the sorted union of the keys of the given maps.
+func sortedNames(maps ...map[string]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range maps {
+		for n := range m {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,157 @@
+package gentest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/design/designtest"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gentest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// recorder implements goatest.TInterface and records the test errors.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// generate is a generator writing the API name constant to app/app.go.
+func generate() ([]string, error) {
+	var outDir, ver string
+	for _, a := range os.Args[1:] {
+		switch {
+		case strings.HasPrefix(a, "--out="):
+			outDir = a[len("--out="):]
+		case strings.HasPrefix(a, "--version="):
+			ver = a[len("--version="):]
+		}
+	}
+	dir := filepath.Join(outDir, "app")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "app.go")
+	content := fmt.Sprintf("// Generated in %s by goagen %s\n\npackage app\n\n// Name is the name of the API.\nconst Name = %q\n",
+		outDir, ver, design.Design.Name)
+	return []string{file}, ioutil.WriteFile(file, []byte(content), 0644)
+}
+
+var _ = Describe("gentest", func() {
+	var t *recorder
+	var name string
+
+	BeforeEach(func() {
+		t = new(recorder)
+		name = "cellar"
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Reset()
+		API(name, func() {})
+		designtest.Eval(t)
+	})
+
+	It("matches the golden files", func() {
+		gentest.Run(t, "testdata/golden", generate)
+		Ω(t.errors).Should(BeEmpty())
+	})
+
+	It("restores the command line", func() {
+		args := os.Args
+		gentest.Run(t, "testdata/golden", generate, "--pkg=app")
+		Ω(os.Args).Should(Equal(args))
+	})
+
+	Context("with a different output", func() {
+		BeforeEach(func() {
+			name = "winecellar"
+		})
+
+		It("reports the differences", func() {
+			gentest.Run(t, "testdata/golden", generate)
+			Ω(t.errors).Should(HaveLen(1))
+			Ω(t.errors[0]).Should(ContainSubstring("app/app.go: differs from golden file"))
+			Ω(t.errors[0]).Should(ContainSubstring(`-const Name = "cellar"`))
+			Ω(t.errors[0]).Should(ContainSubstring(`+const Name = "winecellar"`))
+		})
+	})
+
+	Context("with missing and unexpected files", func() {
+		var goldenDir string
+
+		BeforeEach(func() {
+			var err error
+			goldenDir, err = ioutil.TempDir("", "golden")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ioutil.WriteFile(filepath.Join(goldenDir, "main.go"), []byte("package main\n"), 0644)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(goldenDir)
+		})
+
+		It("reports the files", func() {
+			gentest.Run(t, goldenDir, generate)
+			Ω(t.errors).Should(HaveLen(2))
+			Ω(t.errors[0]).Should(ContainSubstring("app/app.go: unexpected"))
+			Ω(t.errors[1]).Should(ContainSubstring("main.go: missing"))
+		})
+
+		Context("when updating the golden files", func() {
+			BeforeEach(func() {
+				os.Setenv(gentest.UpdateEnv, "1")
+			})
+
+			AfterEach(func() {
+				os.Unsetenv(gentest.UpdateEnv)
+			})
+
+			It("replaces the golden files", func() {
+				gentest.Run(t, goldenDir, generate)
+				Ω(t.errors).Should(BeEmpty())
+				Ω(filepath.Join(goldenDir, "main.go")).ShouldNot(BeAnExistingFile())
+				b, err := ioutil.ReadFile(filepath.Join(goldenDir, "app", "app.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(HavePrefix("// Generated in $(OUT) by goagen $(VERSION)\n"))
+			})
+		})
+	})
+
+	Context("without a design", func() {
+		JustBeforeEach(func() {
+			design.Design = nil
+		})
+
+		It("fails the test", func() {
+			gentest.Generate(t, generate, "unused")
+			Ω(t.errors).Should(HaveLen(1))
+			Ω(t.errors[0]).Should(ContainSubstring("no design"))
+		})
+	})
+})
+
+var _ = Describe("Diff", func() {
+	It("reports the changed lines with their context", func() {
+		diff := gentest.Diff("a\nb\nc\nd\ne", "a\nb\nx\nd\ne")
+		Ω(diff).Should(Equal("@@ line 3 @@\n b\n-c\n+x\n d"))
+	})
+
+	It("reports the added and removed lines", func() {
+		diff := gentest.Diff("a\nb\nc", "a\nc\nd")
+		Ω(diff).Should(Equal("@@ line 2 @@\n a\n-b\n c\n+d"))
+	})
+})
@@ -0,0 +1,6 @@
+// Generated in $(OUT) by goagen $(VERSION)
+
+package app
+
+// Name is the name of the API.
+const Name = "cellar"
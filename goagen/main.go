@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -38,7 +39,23 @@ the "app" command generates the code that supports the service controllers.
 
 The "bootstrap" command runs the "app", "main", "client" and "swagger" commands generating the
 controllers supporting code and main skeleton code (if not already present) as well as a client
-package and tool and the Swagger specification for the API.
+package and tool and the Swagger specification for the API. The "--only" flag of the "bootstrap"
+command restricts the generation to some of these targets, e.g. "--only=openapi" or
+"--only=client,server".
+
+The flags may also be set in a goa.yaml configuration file versioned with the project, see the
+"--config" flag. The top level values set the global flags, the values under a command name set
+the flags of that command and the values under "plugins" set the flags of the third-party
+generators indexed by package import path:
+
+    design: github.com/acme/cellar/design
+    bootstrap:
+      only: server,openapi
+    client:
+      tool: cellar-cli
+    plugins:
+      github.com/acme/gen_events:
+        broker: nats
 `}
 	var (
 		designPkg string
@@ -46,8 +63,14 @@ package and tool and the Swagger specification for the API.
 	)
 
 	rootCmd.PersistentFlags().StringP("out", "o", ".", "output directory")
+	rootCmd.PersistentFlags().String("service-dir", "", "sub-directory of the output directory where artifacts are generated, e.g. to host multiple services in a monorepo")
 	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path")
-	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files and fails if the generator modifies the design.")
+	rootCmd.PersistentFlags().String("profile", "", "comma separated list of the DSL profiles to activate, see apidsl.Profile.")
+	rootCmd.PersistentFlags().String("vars", "", "comma separated list of name=value pairs setting the values of the design variables, see apidsl.Var.")
+	rootCmd.PersistentFlags().Bool("trace", false, "trace the DSL execution and print the slowest definitions to stderr.")
+	rootCmd.PersistentFlags().String("config", defaultConfig, "configuration file setting the flags not given on the command line, ignored if missing and not set explicitly.")
+	rootCmd.PersistentPreRunE = func(c *cobra.Command, _ []string) error { return loadConfig(c) }
 
 	// versionCmd implements the "version" command
 	versionCmd := &cobra.Command{
@@ -61,8 +84,8 @@ package and tool and the Swagger specification for the API.
 
 	// appCmd implements the "app" command.
 	var (
-		pkg    string
-		notest bool
+		pkg, outPkgPath string
+		notest          bool
 	)
 	appCmd := &cobra.Command{
 		Use:   "app",
@@ -70,7 +93,8 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("genapp", c) },
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
-	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers and fuzz targets")
+	appCmd.Flags().StringVar(&outPkgPath, "out-pkg-path", "", "Import path of output directory, use when it cannot be computed from GOPATH or go.mod (e.g. custom monorepo layouts)")
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
@@ -83,6 +107,7 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmain", c) },
 	}
 	mainCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
+	mainCmd.Flags().StringVar(&outPkgPath, "out-pkg-path", "", "Import path of output directory, use when it cannot be computed from GOPATH or go.mod (e.g. custom monorepo layouts)")
 	rootCmd.AddCommand(mainCmd)
 
 	// clientCmd implements the "client" command.
@@ -99,6 +124,7 @@ package and tool and the Swagger specification for the API.
 	clientCmd.Flags().StringVar(&toolDir, "tooldir", "tool", "Name of generated tool directory")
 	clientCmd.Flags().StringVar(&tool, "tool", "[API-name]-cli", "Name of generated tool")
 	clientCmd.Flags().BoolVar(&notool, "notool", false, "Prevent generation of cli tool")
+	clientCmd.Flags().StringVar(&outPkgPath, "out-pkg-path", "", "Import path of output directory, use when it cannot be computed from GOPATH or go.mod (e.g. custom monorepo layouts)")
 	rootCmd.AddCommand(clientCmd)
 
 	// swaggerCmd implements the "swagger" command.
@@ -126,6 +152,153 @@ package and tool and the Swagger specification for the API.
 	jsCmd.Flags().BoolVar(&noexample, "noexample", false, `Skip generation of example HTML and controller`)
 	rootCmd.AddCommand(jsCmd)
 
+	// loadCmd implements the "load" command.
+	var format string
+	loadCmd := &cobra.Command{
+		Use:   "load",
+		Short: "Generate load testing scenarios",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genload", c) },
+	}
+	loadCmd.Flags().StringVar(&format, "format", "", `the scenarios format, one of "k6" or "vegeta", generates both if empty`)
+	loadCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	loadCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(loadCmd)
+
+	// pythonCmd implements the "python" command.
+	var pyPkg string
+	pythonCmd := &cobra.Command{
+		Use:   "python",
+		Short: "Generate Python client package",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpython", c) },
+	}
+	pythonCmd.Flags().StringVar(&pyPkg, "pkg", "", `Name of generated Python package, defaults to the snake case API name suffixed with "_client"`)
+	pythonCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	pythonCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(pythonCmd)
+
+	// javaCmd implements the "java" command.
+	var javaPkg string
+	javaCmd := &cobra.Command{
+		Use:   "java",
+		Short: "Generate Java client project usable from Java and Kotlin",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genjava", c) },
+	}
+	javaCmd.Flags().StringVar(&javaPkg, "pkg", "", `Java package of the generated classes, defaults to the lower case API name suffixed with ".client"`)
+	javaCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	javaCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(javaCmd)
+
+	// mobileCmd implements the "mobile" command.
+	var (
+		mobileLang, mobilePkg string
+	)
+	mobileCmd := &cobra.Command{
+		Use:   "mobile",
+		Short: "Generate Swift and Kotlin data models",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmobile", c) },
+	}
+	mobileCmd.Flags().StringVar(&mobileLang, "lang", "", `the generated language, one of "swift" or "kotlin", generates both if empty`)
+	mobileCmd.Flags().StringVar(&mobilePkg, "pkg", "", `Kotlin package of the generated classes, defaults to the lower case API name suffixed with ".models"`)
+	rootCmd.AddCommand(mobileCmd)
+
+	// docsCmd implements the "docs" command.
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate Markdown API reference",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendocs", c) },
+	}
+	docsCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used in the examples, defaults to the scheme defined in the API design if any.`)
+	docsCmd.Flags().StringVar(&host, "host", "", `the API hostname used in the examples, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(docsCmd)
+
+	// consoleCmd implements the "console" command.
+	var spec string
+	consoleCmd := &cobra.Command{
+		Use:   "console",
+		Short: "Generate interactive API console",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genconsole", c) },
+	}
+	consoleCmd.Flags().StringVar(&spec, "spec", "", `URL of the OpenAPI document loaded by the console, defaults to the path serving "swagger.json" in the design or "/swagger.json"`)
+	rootCmd.AddCommand(consoleCmd)
+
+	// piiCmd implements the "pii" command.
+	piiCmd := &cobra.Command{
+		Use:   "pii",
+		Short: "Generate PII data-flow report",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpii", c) },
+	}
+	rootCmd.AddCommand(piiCmd)
+
+	// snapshotCmd implements the "snapshot" command.
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Generate schema snapshot checked by the Frozen and AdditiveOnly DSLs",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gensnapshot", c) },
+	}
+	rootCmd.AddCommand(snapshotCmd)
+
+	// validateCmd implements the "validate" command.
+	var validated, invalid bool
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the design and print a JSON report without generating anything",
+		Run: func(c *cobra.Command, _ []string) {
+			var report []string
+			report, err = runValidate(c)
+			if err == nil {
+				validated, invalid = true, !isValid(report)
+				fmt.Println(strings.Join(report, "\n"))
+			}
+		},
+	}
+	rootCmd.AddCommand(validateCmd)
+
+	// symbolsCmd implements the "symbols" command.
+	symbolsCmd := &cobra.Command{
+		Use:   "symbols",
+		Short: "Print the design symbols index in JSON for editor integrations",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gensymbols", c) },
+	}
+	rootCmd.AddCommand(symbolsCmd)
+
+	// gatewayCmd implements the "gateway" command.
+	gatewayCmd := &cobra.Command{
+		Use:   "gateway",
+		Short: "Generate API gateway configurations",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gengateway", c) },
+	}
+	gatewayCmd.Flags().StringVar(&format, "format", "", `the gateway configuration format, one of "kong", "envoy" or "aws", generates all if empty`)
+	gatewayCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used by the gateway to reach the upstream service, defaults to the scheme defined in the API design if any.`)
+	gatewayCmd.Flags().StringVar(&host, "host", "", `the upstream service hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(gatewayCmd)
+
+	// diagramCmd implements the "diagram" command.
+	diagramCmd := &cobra.Command{
+		Use:   "diagram",
+		Short: "Generate type dependency and route tree diagrams",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendiagram", c) },
+	}
+	diagramCmd.Flags().StringVar(&format, "format", "", `the diagram format, one of "dot" (Graphviz) or "d2", generates both if empty`)
+	rootCmd.AddCommand(diagramCmd)
+
+	// hrefCmd implements the "href" command.
+	hrefCmd := &cobra.Command{
+		Use:   "href",
+		Short: "Generate reverse routing package building resource hrefs",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genhref", c) },
+	}
+	rootCmd.AddCommand(hrefCmd)
+
+	// terraformCmd implements the "terraform" command.
+	var backend string
+	terraformCmd := &cobra.Command{
+		Use:   "terraform",
+		Short: "Generate Terraform module describing the API infrastructure",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genterraform", c) },
+	}
+	terraformCmd.Flags().StringVar(&backend, "backend", "ecs", `the compute backend serving the API, one of "ecs" or "lambda"`)
+	rootCmd.AddCommand(terraformCmd)
+
 	// schemaCmd implements the "schema" command.
 	schemaCmd := &cobra.Command{
 		Use:   "schema",
@@ -147,32 +320,29 @@ package and tool and the Swagger specification for the API.
 	rootCmd.AddCommand(genCmd)
 
 	// boostrapCmd implements the "bootstrap" command.
+	var only string
 	bootCmd := &cobra.Command{
 		Use:   "bootstrap",
 		Short: `Equivalent to running the "app", "main", "client" and "swagger" commands.`,
 		Run: func(c *cobra.Command, a []string) {
-			appCmd.Run(c, a)
-			if err != nil {
-				return
-			}
-			prev := files
-
-			mainCmd.Run(c, a)
+			var names []string
+			names, err = selectTargets(only)
 			if err != nil {
 				return
 			}
-			prev = append(prev, files...)
-
-			clientCmd.Run(c, a)
-			if err != nil {
-				return
+			cmds := map[string]*cobra.Command{"app": appCmd, "main": mainCmd, "client": clientCmd, "swagger": swaggerCmd}
+			var prev []string
+			for _, n := range names {
+				cmds[n].Run(targetCommand(c, cmds[n]), a)
+				prev = append(prev, files...)
+				if err != nil {
+					break
+				}
 			}
-			prev = append(prev, files...)
-
-			swaggerCmd.Run(c, a)
-			files = append(prev, files...)
+			files = prev
 		},
 	}
+	bootCmd.Flags().StringVar(&only, "only", "", `comma separated list of the targets to generate: "app", "main", "client", "swagger" or the "server" (app and main) and "openapi" (swagger) groups, the targets they depend on are also generated. Generates all targets if empty.`)
 	bootCmd.Flags().AddFlagSet(appCmd.Flags())
 	bootCmd.Flags().AddFlagSet(mainCmd.Flags())
 	bootCmd.Flags().AddFlagSet(clientCmd.Flags())
@@ -199,7 +369,16 @@ package and tool and the Swagger specification for the API.
 		terminatedByUser = true
 	})
 
-	rootCmd.Execute()
+	if e := rootCmd.Execute(); e != nil {
+		os.Exit(1)
+	}
+
+	if validated {
+		if invalid {
+			os.Exit(1)
+		}
+		return
+	}
 
 	if terminatedByUser {
 		cleanup()
@@ -251,16 +430,75 @@ func runGen(c *cobra.Command) ([]string, error) {
 	return generate(pkgName, pkgPath, c)
 }
 
+// runValidate runs the validation generator which runs the DSL itself to report its errors.
+func runValidate(c *cobra.Command) ([]string, error) {
+	gen, err := newGenerator("genvalidate", "github.com/goadesign/goa/goagen/gen_validate", c)
+	if err != nil {
+		return nil, err
+	}
+	gen.NoRun = true
+	return gen.Generate()
+}
+
+// isValid returns true if the report produced by the validation generator says the design is
+// valid.
+func isValid(report []string) bool {
+	var r struct {
+		Valid bool `json:"valid"`
+	}
+	if err := json.Unmarshal([]byte(strings.Join(report, "\n")), &r); err != nil {
+		return false
+	}
+	return r.Valid
+}
+
 func generate(pkgName, pkgPath string, c *cobra.Command) ([]string, error) {
+	gen, err := newGenerator(pkgName, pkgPath, c)
+	if err != nil {
+		return nil, err
+	}
+	return gen.Generate()
+}
+
+// targetCommand returns the command holding the flags of the bootstrap command c that are global
+// or defined by the target command so that the generator of the target is only given the flags
+// it accepts.
+func targetCommand(c, target *cobra.Command) *cobra.Command {
+	tc := &cobra.Command{Use: target.Use}
+	inherited := c.InheritedFlags()
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if inherited.Lookup(f.Name) != nil || target.Flags().Lookup(f.Name) != nil {
+			tc.Flags().AddFlag(f)
+		}
+	})
+	return tc
+}
+
+// newGenerator returns the meta generator that runs the generator implemented by the package with
+// the given name and path using the command flags.
+func newGenerator(pkgName, pkgPath string, c *cobra.Command) (*meta.Generator, error) {
 	m := make(map[string]string)
-	c.Flags().Visit(func(f *pflag.Flag) {
-		if f.Name != "pkg-path" {
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && f.Name != "pkg-path" && f.Name != "only" && f.Name != "config" {
 			m[f.Name] = f.Value.String()
 		}
 	})
+	for k, v := range pluginFlags(pkgPath) {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
 	if _, ok := m["out"]; !ok {
 		m["out"] = c.Flag("out").DefValue
 	}
+	// generate into the service sub-directory if any
+	if sd, ok := m["service-dir"]; ok {
+		m["out"] = filepath.Join(m["out"], sd)
+		if p, ok := m["out-pkg-path"]; ok {
+			m["out-pkg-path"] = path.Join(p, filepath.ToSlash(sd))
+		}
+		delete(m, "service-dir")
+	}
 	// turn "out" into an absolute path
 	var err error
 	m["out"], err = filepath.Abs(m["out"])
@@ -268,15 +506,11 @@ func generate(pkgName, pkgPath string, c *cobra.Command) ([]string, error) {
 		return nil, err
 	}
 
-	gen, err := meta.NewGenerator(
+	return meta.NewGenerator(
 		pkgName+".Generate",
 		[]*codegen.ImportSpec{codegen.SimpleImport(pkgPath)},
 		m,
 	)
-	if err != nil {
-		return nil, err
-	}
-	return gen.Generate()
 }
 
 type (
@@ -332,11 +566,13 @@ func flagJSON(fl *pflag.Flag) *flag {
 	f := &flag{Long: fl.Name, Short: fl.Shorthand, Description: fl.Usage}
 	f.Required = fl.Name == "pkg-path" || fl.Name == "design"
 	switch fl.Name {
-	case "out":
+	case "out", "service-dir":
 		f.Argument = "$DIR"
+	case "config":
+		f.Argument = "$FILE"
 	case "design":
 		f.Argument = "$DESIGN_PKG"
-	case "pkg-path":
+	case "pkg-path", "out-pkg-path":
 		f.Argument = "$PKG"
 	}
 	return f
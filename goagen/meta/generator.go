@@ -11,6 +11,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/version"
 )
@@ -39,7 +40,21 @@ type Generator struct {
 	// DesignPkgPath is the Go import path to the design package.
 	DesignPkgPath string
 
+	// NoRun prevents the generated tool from running the DSL before calling Genfunc. The
+	// generator function is then responsible for running the DSL and reporting its errors.
+	NoRun bool
+
 	debug bool
+	trace bool
+	env   []string
+}
+
+// envFlags maps the names of the flags whose values must be known when the design package
+// initializes, before the generator parses its command line, to the environment variables used to
+// pass them to the generator.
+var envFlags = map[string]string{
+	"profile": dslengine.ProfilesEnv,
+	"vars":    dslengine.VarsEnv,
 }
 
 // NewGenerator returns a meta generator that can run an actual Generator
@@ -47,7 +62,8 @@ type Generator struct {
 func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[string]string) (*Generator, error) {
 	var (
 		outDir, designPkgPath string
-		debug                 bool
+		debug, trace          bool
+		env                   []string
 	)
 
 	if o, ok := flags["out"]; ok {
@@ -63,6 +79,19 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 			return nil, fmt.Errorf("failed to parse debug flag: %s", err)
 		}
 	}
+	if t, ok := flags["trace"]; ok {
+		var err error
+		trace, err = strconv.ParseBool(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse trace flag: %s", err)
+		}
+	}
+	for f, e := range envFlags {
+		if v, ok := flags[f]; ok {
+			env = append(env, e+"="+v)
+		}
+	}
+	sort.Strings(env)
 
 	return &Generator{
 		Genfunc:       genfunc,
@@ -71,6 +100,8 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 		OutDir:        outDir,
 		DesignPkgPath: designPkgPath,
 		debug:         debug,
+		trace:         trace,
+		env:           env,
 	}, nil
 }
 
@@ -145,6 +176,7 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	file := pkg.CreateSourceFile("main.go")
 	imports := append(m.Imports,
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("os"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa/dslengine"),
 		codegen.NewImport("_", filepath.ToSlash(m.DesignPkgPath)),
@@ -158,10 +190,11 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 	if err != nil {
 		panic(err)
 	}
-	context := map[string]string{
+	context := map[string]interface{}{
 		"Genfunc":       m.Genfunc,
 		"DesignPackage": m.DesignPkgPath,
 		"PkgName":       pkgName,
+		"NoRun":         m.NoRun,
 	}
 	err = tmpl.Execute(file, context)
 	if err != nil {
@@ -172,16 +205,32 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 // spawn runs the compiled generator using the arguments initialized by Kingpin
 // when parsing the command line.
 func (m *Generator) spawn(genbin string) ([]string, error) {
-	args := make([]string, len(m.Flags))
-	i := 0
+	var args []string
 	for k, v := range m.Flags {
-		args[i] = fmt.Sprintf("--%s=%s", k, v)
-		i++
+		// debug and trace are passed to the generator with environment variables.
+		if _, ok := envFlags[k]; ok || k == "debug" || k == "trace" {
+			continue
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", k, v))
 	}
 	sort.Strings(args)
 	args = append(args, "--version="+version.String())
 	cmd := exec.Command(genbin, args...)
-	out, err := cmd.CombinedOutput()
+	cmd.Env = append(os.Environ(), m.env...)
+	if m.debug {
+		cmd.Env = append(cmd.Env, "GOAGEN_DEBUG=1")
+	}
+	var out []byte
+	var err error
+	if m.trace {
+		// Traces are written to stderr, let them through and only capture the list of
+		// generated files written to stdout.
+		cmd.Env = append(cmd.Env, "GOAGEN_TRACE=1")
+		cmd.Stderr = os.Stderr
+		out, err = cmd.Output()
+	} else {
+		out, err = cmd.CombinedOutput()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("%s\n%s", err, string(out))
 	}
@@ -194,14 +243,23 @@ func (m *Generator) spawn(genbin string) ([]string, error) {
 
 const mainTmpl = `
 func main() {
-	// Check if there were errors while running the first DSL pass
+	// Trace the DSL execution if requested
+	if os.Getenv("GOAGEN_TRACE") != "" {
+		dslengine.Trace = os.Stderr
+	}
+
+	// Detect the changes made to the design by the generator in debug mode
+	dslengine.CheckMutations = os.Getenv("GOAGEN_DEBUG") != ""
+
+{{ if not .NoRun }}	// Check if there were errors while running the first DSL pass
 	dslengine.FailOnError(dslengine.Errors)
 
 	// Now run the secondary DSLs
 	dslengine.FailOnError(dslengine.Run())
-
+{{ end }}
 	files, err := {{.Genfunc}}()
 	dslengine.FailOnError(err)
+	dslengine.CheckFrozen()
 
 	// We're done
 	fmt.Println(strings.Join(files, "\n"))
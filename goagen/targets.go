@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// target is a group of artifacts generated by the bootstrap command.
+type target struct {
+	// Name is the name of the command that generates the artifacts.
+	Name string
+	// Deps lists the targets whose artifacts are used by the target artifacts, they are
+	// regenerated with the target so that the artifacts stay consistent.
+	Deps []string
+}
+
+var (
+	// bootstrapTargets lists the bootstrap command targets in generation order.
+	bootstrapTargets = []*target{
+		{Name: "app"},
+		{Name: "main", Deps: []string{"app"}},
+		{Name: "client"},
+		{Name: "swagger"},
+	}
+
+	// targetAliases lists the names of the target groups accepted by the bootstrap --only flag.
+	targetAliases = map[string][]string{
+		"server":  {"app", "main"},
+		"openapi": {"swagger"},
+	}
+)
+
+// selectTargets returns the names of the bootstrap targets to generate in generation order given
+// the value of the --only flag: a comma separated list of target or alias names. The targets the
+// selected targets depend on are also selected. All the targets are selected if only is empty.
+func selectTargets(only string) ([]string, error) {
+	selected := make(map[string]bool)
+	var add func(name string) error
+	add = func(name string) error {
+		if names, ok := targetAliases[name]; ok {
+			for _, n := range names {
+				if err := add(n); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		t := findTarget(name)
+		if t == nil {
+			return fmt.Errorf("unknown target %q, valid targets are %s", name, strings.Join(targetNames(), ", "))
+		}
+		if selected[name] {
+			return nil
+		}
+		selected[name] = true
+		for _, d := range t.Deps {
+			if err := add(d); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, n := range strings.Split(only, ",") {
+		if n = strings.TrimSpace(n); n == "" {
+			continue
+		}
+		if err := add(n); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for _, t := range bootstrapTargets {
+		if len(selected) == 0 || selected[t.Name] {
+			names = append(names, t.Name)
+		}
+	}
+	return names, nil
+}
+
+// findTarget returns the bootstrap target with the given name, nil if there is none.
+func findTarget(name string) *target {
+	for _, t := range bootstrapTargets {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// targetNames returns the sorted names of the bootstrap targets and aliases.
+func targetNames() []string {
+	names := make([]string, 0, len(bootstrapTargets)+len(targetAliases))
+	for _, t := range bootstrapTargets {
+		names = append(names, t.Name)
+	}
+	for a := range targetAliases {
+		names = append(names, a)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,71 @@
+/*
+Package random provides a property based testing helper that produces random instances of design
+types. The instances satisfy all the validations defined in the design so that they may be used as
+valid payloads in tests, by mock servers or by load generators:
+
+	g := random.New("seed")
+	for i := 0; i < 100; i++ {
+		var payload app.CreateBottlePayload
+		if err := g.Fill(&payload, design.BottlePayload); err != nil {
+			t.Fatal(err)
+		}
+		// ... use payload
+	}
+
+Generators created with the same seed produce the same sequence of values.
+*/
+package random
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/goadesign/goa/design"
+)
+
+// Generator produces random values that satisfy the validations of design types.
+type Generator struct {
+	rand *design.RandomGenerator
+}
+
+// New returns a generator seeded with the given value.
+func New(seed string) *Generator {
+	return &Generator{rand: design.NewRandomGenerator(seed)}
+}
+
+// Value returns a random value of the given data type. Objects are represented with
+// map[string]interface{} values, arrays with slices and hashes with maps.
+func (g *Generator) Value(t design.DataType) interface{} {
+	return g.Attribute(attributeFor(t))
+}
+
+// Attribute returns a random value that satisfies the validations of the given attribute.
+func (g *Generator) Attribute(att *design.AttributeDefinition) interface{} {
+	return att.RandomValue(g.rand)
+}
+
+// Fill initializes the data structure pointed to by v with a random value of the given data type.
+// v is typically a pointer to the generated struct corresponding to t. The random value is
+// assigned using its JSON representation so that the generated struct field tags are honored.
+func (g *Generator) Fill(v interface{}, t design.DataType) error {
+	b, err := json.Marshal(g.Value(t))
+	if err != nil {
+		return fmt.Errorf("failed to serialize random %s: %s", t.Name(), err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to initialize %T with random %s: %s", v, t.Name(), err)
+	}
+	return nil
+}
+
+// attributeFor returns the attribute definition used to generate values of the given type.
+func attributeFor(t design.DataType) *design.AttributeDefinition {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		return &design.AttributeDefinition{Type: actual}
+	case *design.UserTypeDefinition:
+		return actual.AttributeDefinition
+	default:
+		return &design.AttributeDefinition{Type: t}
+	}
+}
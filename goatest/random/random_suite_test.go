@@ -0,0 +1,13 @@
+package random_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Random Suite")
+}
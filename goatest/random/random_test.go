@@ -0,0 +1,72 @@
+package random_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goatest/random"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generator", func() {
+	var ut *design.UserTypeDefinition
+	var g *random.Generator
+
+	BeforeEach(func() {
+		min, max := 10.0, 20.0
+		minLength := 2
+		ut = &design.UserTypeDefinition{
+			TypeName: "Payload",
+			AttributeDefinition: &design.AttributeDefinition{
+				Type: design.Object{
+					"count": &design.AttributeDefinition{
+						Type:       design.Integer,
+						Validation: &dslengine.ValidationDefinition{Minimum: &min, Maximum: &max},
+					},
+					"color": &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{Values: []interface{}{"red", "white"}},
+					},
+					"tags": &design.AttributeDefinition{
+						Type:       &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+						Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+					},
+				},
+				Example: map[string]interface{}{"count": 42},
+			},
+		}
+		g = random.New("test")
+	})
+
+	It("produces values that satisfy the validations", func() {
+		for i := 0; i < 20; i++ {
+			v, ok := g.Value(ut).(map[string]interface{})
+			Ω(ok).Should(BeTrue())
+			Ω(v["count"]).Should(BeNumerically(">=", 10))
+			Ω(v["count"]).Should(BeNumerically("<=", 20))
+			Ω(v["color"]).Should(BeElementOf("red", "white"))
+			Ω(len(v["tags"].([]string))).Should(BeNumerically(">=", 2))
+		}
+	})
+
+	It("produces different values on successive calls", func() {
+		first := g.Value(design.String)
+		Ω(g.Value(design.String)).ShouldNot(Equal(first))
+	})
+
+	It("produces the same values given the same seed", func() {
+		Ω(random.New("test").Value(ut)).Should(Equal(g.Value(ut)))
+	})
+
+	It("fills Go structs", func() {
+		var payload struct {
+			Count int      `json:"count"`
+			Color string   `json:"color"`
+			Tags  []string `json:"tags"`
+		}
+		Ω(g.Fill(&payload, ut)).ShouldNot(HaveOccurred())
+		Ω(payload.Count).Should(BeNumerically(">=", 10))
+		Ω(payload.Color).ShouldNot(BeEmpty())
+		Ω(len(payload.Tags)).Should(BeNumerically(">=", 2))
+	})
+})
@@ -0,0 +1,352 @@
+/*
+Package jsonrpc exposes the actions of a goa service over a single JSON-RPC 2.0 endpoint
+(https://www.jsonrpc.org/specification). The code generated for the APIs designed with JSONRPC
+mounts a Server whose methods describe the route of each action. The server turns each call into
+an HTTP request sent to the action through the service mux so that the action middleware,
+decoding and validation run as for the HTTP requests, and turns the action response into the
+call response.
+*/
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/goadesign/goa"
+)
+
+// Version is the version of the JSON-RPC specification implemented by the package.
+const Version = "2.0"
+
+// The JSON-RPC error codes defined by the specification.
+const (
+	// ParseError indicates that the request body is not valid JSON.
+	ParseError = -32700
+	// InvalidRequest indicates that the call is not a valid JSON-RPC request.
+	InvalidRequest = -32600
+	// MethodNotFound indicates that no action matches the method of the call.
+	MethodNotFound = -32601
+	// InvalidParams indicates that the call params are invalid.
+	InvalidParams = -32602
+	// InternalError indicates that the action failed with a 5xx response.
+	InternalError = -32603
+	// ServerError is the code of the errors that do not map to a more specific code.
+	ServerError = -32000
+)
+
+const (
+	// DefaultMaxRequestBodyLength is the maximum length read from the request bodies when the
+	// server does not specify one, it matches the default of the controllers.
+	DefaultMaxRequestBodyLength = 1073741824 // 1 GB
+	// DefaultMaxBatchSize is the maximum number of calls of a batch when the server does not
+	// specify one.
+	DefaultMaxBatchSize = 100
+)
+
+type (
+	// Server dispatches the JSON-RPC calls to the service actions.
+	Server struct {
+		// Mux is the service mux the calls are dispatched to.
+		Mux goa.ServeMux
+		// Methods describes the actions indexed by JSON-RPC method name.
+		Methods map[string]*Method
+		// Codes maps the codes of the error responses to JSON-RPC error codes.
+		Codes map[string]int
+		// MaxRequestBodyLength is the maximum length read from the request bodies.
+		// Defaults to DefaultMaxRequestBodyLength.
+		MaxRequestBodyLength int64
+		// MaxBatchSize is the maximum number of calls of a batch, larger batches are
+		// rejected with InvalidRequest. Defaults to DefaultMaxBatchSize.
+		MaxBatchSize int
+	}
+
+	// Method describes the route of the action called by a JSON-RPC method.
+	Method struct {
+		// Verb is the HTTP method of the action route.
+		Verb string
+		// Path is the full path of the action route including wildcards.
+		Path string
+		// Params lists the names of the action path and querystring parameters.
+		Params []string
+		// Payload is true if the action accepts a request payload.
+		Payload bool
+	}
+
+	// Request is a JSON-RPC call.
+	Request struct {
+		// JSONRPC is the version of the protocol, must be "2.0".
+		JSONRPC string `json:"jsonrpc"`
+		// Method is the name of the method, "<resource>.<action>".
+		Method string `json:"method"`
+		// Params holds the action parameters and payload.
+		Params json.RawMessage `json:"params,omitempty"`
+		// ID identifies the call, nil for notifications.
+		ID json.RawMessage `json:"id,omitempty"`
+	}
+
+	// Response is the response to a JSON-RPC call.
+	Response struct {
+		// JSONRPC is the version of the protocol, always "2.0".
+		JSONRPC string `json:"jsonrpc"`
+		// Result is the body of the action response if the call succeeded.
+		Result json.RawMessage `json:"result,omitempty"`
+		// Error describes the failure if the call failed.
+		Error *Error `json:"error,omitempty"`
+		// ID is the identifier of the call.
+		ID json.RawMessage `json:"id"`
+	}
+
+	// Error describes a failed JSON-RPC call.
+	Error struct {
+		// Code identifies the kind of error.
+		Code int `json:"code"`
+		// Message describes the error.
+		Message string `json:"message"`
+		// Data is the body of the action error response if any.
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+)
+
+// MuxHandler returns the handler of the JSON-RPC endpoint requests.
+func (s *Server) MuxHandler() goa.MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+		s.ServeHTTP(rw, req)
+	}
+}
+
+// ServeHTTP handles a JSON-RPC request made of a single call or of a batch of calls. The calls of
+// a batch are handled in order. Notifications produce no response, the endpoint responds with 204
+// if the request only contains notifications. Requests whose body exceeds MaxRequestBodyLength
+// bytes or whose batch exceeds MaxBatchSize calls are rejected with InvalidRequest.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	maxLength := s.MaxRequestBodyLength
+	if maxLength <= 0 {
+		maxLength = DefaultMaxRequestBodyLength
+	}
+	maxBatch := s.MaxBatchSize
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatchSize
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, maxLength)).Decode(&body); err != nil {
+		if err.Error() == "http: request body too large" {
+			msg := fmt.Sprintf("Invalid Request: body length exceeds %d bytes", maxLength)
+			write(rw, newErrorResponse(nil, InvalidRequest, msg))
+			return
+		}
+		write(rw, newErrorResponse(nil, ParseError, "Parse error"))
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		if resp := s.call(req, body); resp != nil {
+			write(rw, resp)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var calls []json.RawMessage
+	if err := json.Unmarshal(body, &calls); err != nil || len(calls) == 0 {
+		write(rw, newErrorResponse(nil, InvalidRequest, "Invalid Request"))
+		return
+	}
+	if len(calls) > maxBatch {
+		msg := fmt.Sprintf("Invalid Request: batch exceeds %d calls", maxBatch)
+		write(rw, newErrorResponse(nil, InvalidRequest, msg))
+		return
+	}
+	var resps []*Response
+	for _, c := range calls {
+		if resp := s.call(req, c); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+	write(rw, resps)
+}
+
+// call handles a single call and returns its response, nil if the call is a notification.
+func (s *Server) call(req *http.Request, raw json.RawMessage) *Response {
+	var r Request
+	if err := json.Unmarshal(raw, &r); err != nil || r.JSONRPC != Version || r.Method == "" {
+		return newErrorResponse(r.ID, InvalidRequest, "Invalid Request")
+	}
+	resp := s.dispatch(req, &r)
+	if r.ID == nil {
+		return nil
+	}
+	return resp
+}
+
+// dispatch sends the call to the action and builds the call response from the action response.
+func (s *Server) dispatch(req *http.Request, r *Request) *Response {
+	m, ok := s.Methods[r.Method]
+	if !ok {
+		return newErrorResponse(r.ID, MethodNotFound, "Method not found")
+	}
+	params := make(map[string]json.RawMessage)
+	if len(r.Params) > 0 && string(r.Params) != "null" {
+		if err := json.Unmarshal(r.Params, &params); err != nil {
+			return newErrorResponse(r.ID, InvalidParams, "Invalid params: params must be an object")
+		}
+	}
+	values := make(url.Values)
+	for _, n := range m.Params {
+		raw, ok := params[n]
+		if !ok {
+			continue
+		}
+		delete(params, n)
+		if obj, ok := paramObject(raw); ok {
+			// Object parameters are bound from dotted query string keys
+			for k, v := range obj {
+				vals, err := paramValues(v)
+				if err != nil {
+					return newErrorResponse(r.ID, InvalidParams, "Invalid params: "+n+"."+k+" must be a scalar or an array of scalars")
+				}
+				values[n+"."+k] = vals
+			}
+			continue
+		}
+		vals, err := paramValues(raw)
+		if err != nil {
+			return newErrorResponse(r.ID, InvalidParams, "Invalid params: "+n+" must be a scalar, an array of scalars or an object")
+		}
+		values[n] = vals
+	}
+	var body []byte
+	if m.Payload && len(params) > 0 {
+		body, _ = json.Marshal(params)
+	}
+	areq, err := http.NewRequest(m.Verb, goa.DispatchURL(m.Path, values), bytes.NewReader(body))
+	if err != nil {
+		return newErrorResponse(r.ID, InvalidParams, "Invalid params: "+err.Error())
+	}
+	areq = areq.WithContext(req.Context())
+	for k, v := range req.Header {
+		areq.Header[k] = v
+	}
+	// The action response must not be compressed as it becomes the call result
+	areq.Header.Del("Content-Length")
+	areq.Header.Del("Content-Encoding")
+	areq.Header.Del("Accept-Encoding")
+	areq.Header.Set("Content-Type", "application/json")
+	areq.Header.Set("Accept", "application/json")
+	areq.RemoteAddr = req.RemoteAddr
+	return s.response(r.ID, goa.Dispatch(s.Mux, areq))
+}
+
+// response builds the call response from the recorded action response.
+func (s *Server) response(id json.RawMessage, rec *goa.DispatchResponse) *Response {
+	data := bytes.TrimSpace(rec.Body)
+	if len(data) == 0 {
+		data = []byte("null")
+	} else if !json.Valid(data) {
+		data, _ = json.Marshal(string(data))
+	}
+	if rec.Status < 400 {
+		return &Response{JSONRPC: Version, Result: data, ID: id}
+	}
+	var e goa.ErrorResponse
+	json.Unmarshal(data, &e)
+	code, ok := s.Codes[e.Code]
+	if !ok {
+		switch {
+		case rec.Status == http.StatusBadRequest:
+			code = InvalidParams
+		case rec.Status >= 500:
+			code = InternalError
+		default:
+			code = ServerError
+		}
+	}
+	msg := e.Detail
+	if msg == "" {
+		msg = http.StatusText(rec.Status)
+	}
+	resp := newErrorResponse(id, code, msg)
+	if string(data) != "null" {
+		resp.Error.Data = data
+	}
+	return resp
+}
+
+// paramObject returns the fields of the parameter with the given raw JSON value if the value is an
+// object.
+func paramObject(raw json.RawMessage) (map[string]json.RawMessage, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '{' {
+		return nil, false
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// paramValues returns the string values of the parameter with the given raw JSON value.
+func paramValues(raw json.RawMessage) ([]string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, err
+		}
+		vals := make([]string, len(elems))
+		for i, e := range elems {
+			v, err := paramValue(e)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = v
+		}
+		return vals, nil
+	}
+	v, err := paramValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	return []string{v}, nil
+}
+
+// paramValue returns the string value of a scalar raw JSON value.
+func paramValue(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return "", &json.UnsupportedValueError{Str: string(raw)}
+	}
+	return string(raw), nil
+}
+
+// newErrorResponse returns the response to a failed call.
+func newErrorResponse(id json.RawMessage, code int, msg string) *Response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, Error: &Error{Code: code, Message: msg}, ID: id}
+}
+
+// write writes the JSON encoding of v to rw.
+func write(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	json.NewEncoder(rw).Encode(v)
+}
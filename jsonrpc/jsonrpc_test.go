@@ -0,0 +1,269 @@
+package jsonrpc_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/jsonrpc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		service *goa.Service
+		server  *jsonrpc.Server
+		body    string
+		rw      *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		service.Mux.Handle("GET", "/bottles/:id", func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+			if params.Get("id") == "0" {
+				rw.Header().Set("Content-Type", "application/json")
+				rw.WriteHeader(404)
+				rw.Write([]byte(`{"id":"x","code":"not_found","status":404,"detail":"bottle 0 not found"}`))
+				return
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			rw.Write([]byte(`{"id":` + params.Get("id") + `,"view":"` + params.Get("view") + `"}`))
+		})
+		service.Mux.Handle("GET", "/bottles", func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+			rw.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(rw).Encode(map[string]string{
+				"query":    req.URL.RawQuery,
+				"encoding": req.Header.Get("Accept-Encoding"),
+			})
+		})
+		service.Mux.Handle("POST", "/bottles", func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+			b, _ := ioutil.ReadAll(req.Body)
+			if len(b) == 0 {
+				rw.WriteHeader(400)
+				rw.Write([]byte(`{"id":"x","code":"bad_request","status":400,"detail":"missing payload"}`))
+				return
+			}
+			rw.WriteHeader(201)
+			rw.Write(b)
+		})
+		server = &jsonrpc.Server{
+			Mux: service.Mux,
+			Methods: map[string]*jsonrpc.Method{
+				"bottle.show":   {Verb: "GET", Path: "/bottles/:id", Params: []string{"id", "view"}},
+				"bottle.create": {Verb: "POST", Path: "/bottles", Payload: true},
+				"bottle.list":   {Verb: "GET", Path: "/bottles", Params: []string{"filter"}},
+			},
+			Codes: map[string]int{"not_found": -32001},
+		}
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("POST", "/rpc", strings.NewReader(body))
+		req.Header.Set("Accept-Encoding", "gzip")
+		server.ServeHTTP(rw, req)
+	})
+
+	decode := func(v interface{}) {
+		Ω(json.Unmarshal(rw.Body.Bytes(), v)).Should(Succeed())
+	}
+
+	Context("with a call", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.show","params":{"id":1,"view":"tiny"},"id":"a"}`
+		})
+
+		It("sets the action path and querystring parameters", func() {
+			Ω(rw.Code).Should(Equal(200))
+			var resp map[string]interface{}
+			decode(&resp)
+			Ω(resp).Should(HaveKeyWithValue("jsonrpc", "2.0"))
+			Ω(resp).Should(HaveKeyWithValue("id", "a"))
+			Ω(resp).Should(HaveKeyWithValue("result", map[string]interface{}{"id": 1.0, "view": "tiny"}))
+			Ω(resp).ShouldNot(HaveKey("error"))
+		})
+	})
+
+	Context("with a call using an object parameter", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.list","params":{"filter":{"status":"active","min":3}},"id":1}`
+		})
+
+		It("sets the dotted querystring parameters", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error).Should(BeNil())
+			var result map[string]string
+			Ω(json.Unmarshal(resp.Result, &result)).Should(Succeed())
+			Ω(result["query"]).Should(Equal("filter.min=3&filter.status=active"))
+		})
+
+		It("does not forward the accepted encodings", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			var result map[string]string
+			Ω(json.Unmarshal(resp.Result, &result)).Should(Succeed())
+			Ω(result["encoding"]).Should(BeEmpty())
+		})
+	})
+
+	Context("with a call sending a payload", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.create","params":{"name":"red"},"id":1}`
+		})
+
+		It("sends the params as the request body", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error).Should(BeNil())
+			Ω(string(resp.Result)).Should(MatchJSON(`{"name":"red"}`))
+			Ω(string(resp.ID)).Should(Equal("1"))
+		})
+	})
+
+	Context("with a call failing with a declared error", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.show","params":{"id":0},"id":1}`
+		})
+
+		It("maps the error code", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error).ShouldNot(BeNil())
+			Ω(resp.Error.Code).Should(Equal(-32001))
+			Ω(resp.Error.Message).Should(Equal("bottle 0 not found"))
+			Ω(string(resp.Error.Data)).Should(ContainSubstring(`"code":"not_found"`))
+		})
+	})
+
+	Context("with a call failing with a bad request", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.create","id":1}`
+		})
+
+		It("uses the invalid params code", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.InvalidParams))
+		})
+	})
+
+	Context("with an unknown method", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.delete","id":1}`
+		})
+
+		It("returns the method not found error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.MethodNotFound))
+		})
+	})
+
+	Context("with positional params", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.show","params":[1],"id":1}`
+		})
+
+		It("returns the invalid params error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.InvalidParams))
+		})
+	})
+
+	Context("with invalid JSON", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":`
+		})
+
+		It("returns the parse error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.ParseError))
+			Ω(string(resp.ID)).Should(Equal("null"))
+		})
+	})
+
+	Context("with a notification", func() {
+		BeforeEach(func() {
+			body = `{"jsonrpc":"2.0","method":"bottle.show","params":{"id":1}}`
+		})
+
+		It("does not respond", func() {
+			Ω(rw.Code).Should(Equal(204))
+			Ω(rw.Body.Len()).Should(BeZero())
+		})
+	})
+
+	Context("with a batch", func() {
+		BeforeEach(func() {
+			body = `[
+				{"jsonrpc":"2.0","method":"bottle.show","params":{"id":1},"id":1},
+				{"jsonrpc":"2.0","method":"bottle.show","params":{"id":2}},
+				{"jsonrpc":"1.0","method":"bottle.show","id":3},
+				{"jsonrpc":"2.0","method":"bottle.show","params":{"id":0},"id":4}
+			]`
+		})
+
+		It("responds to each call that is not a notification in order", func() {
+			var resps []*jsonrpc.Response
+			decode(&resps)
+			Ω(resps).Should(HaveLen(3))
+			Ω(string(resps[0].ID)).Should(Equal("1"))
+			Ω(resps[0].Error).Should(BeNil())
+			Ω(string(resps[1].ID)).Should(Equal("3"))
+			Ω(resps[1].Error.Code).Should(Equal(jsonrpc.InvalidRequest))
+			Ω(string(resps[2].ID)).Should(Equal("4"))
+			Ω(resps[2].Error.Code).Should(Equal(-32001))
+		})
+	})
+
+	Context("with a batch exceeding the maximum size", func() {
+		BeforeEach(func() {
+			server.MaxBatchSize = 1
+			body = `[
+				{"jsonrpc":"2.0","method":"bottle.show","params":{"id":1},"id":1},
+				{"jsonrpc":"2.0","method":"bottle.show","params":{"id":2},"id":2}
+			]`
+		})
+
+		It("returns the invalid request error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.InvalidRequest))
+			Ω(resp.Error.Message).Should(ContainSubstring("batch exceeds 1 calls"))
+		})
+	})
+
+	Context("with a body exceeding the maximum length", func() {
+		BeforeEach(func() {
+			server.MaxRequestBodyLength = 16
+			body = `{"jsonrpc":"2.0","method":"bottle.show","params":{"id":1},"id":1}`
+		})
+
+		It("returns the invalid request error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.InvalidRequest))
+			Ω(resp.Error.Message).Should(ContainSubstring("body length exceeds 16 bytes"))
+		})
+	})
+
+	Context("with an empty batch", func() {
+		BeforeEach(func() {
+			body = `[]`
+		})
+
+		It("returns the invalid request error", func() {
+			var resp jsonrpc.Response
+			decode(&resp)
+			Ω(resp.Error.Code).Should(Equal(jsonrpc.InvalidRequest))
+		})
+	})
+})
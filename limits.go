@@ -0,0 +1,93 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// PayloadLimits caps the complexity of request bodies, a zero value means no limit.
+type PayloadLimits struct {
+	// MaxDepth is the maximum nesting depth of the arrays and objects.
+	MaxDepth int
+	// MaxArrayLength is the maximum number of elements of the arrays.
+	MaxArrayLength int
+	// MaxMapSize is the maximum number of keys of the objects.
+	MaxMapSize int
+}
+
+// limitsFrame records the number of values read in an array or object being scanned.
+type limitsFrame struct {
+	object bool
+	count  int
+}
+
+// LimitRequest scans JSON request bodies and returns an ErrInvalidRequest error if they exceed
+// the given limits. The body is tokenized without building the values it contains so that
+// malicious payloads are rejected before memory is allocated to decode them. LimitRequest
+// leaves the body untouched if it is malformed so that decoding it reports the error. The
+// request body is replaced with a reader over the scanned bytes. Bodies without a Content-Type
+// header are scanned as the service decodes them as JSON, bodies that use other content types are
+// not scanned. The code generated for the actions of designs that use the PayloadLimits
+// DSL calls LimitRequest prior to decoding the request body.
+func LimitRequest(req *http.Request, limits PayloadLimits) error {
+	if ct := req.Header.Get("Content-Type"); ct != "" && !isJSON(ct) {
+		return nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return scanLimits(json.NewDecoder(bytes.NewReader(body)), limits)
+}
+
+// scanLimits reads the tokens of dec and checks the limits.
+func scanLimits(dec *json.Decoder, limits PayloadLimits) error {
+	var stack []*limitsFrame
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// EOF or malformed body, the decoder reports the latter.
+			return nil
+		}
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if len(stack) > 0 {
+			f := stack[len(stack)-1]
+			f.count++
+			if f.object {
+				if size := (f.count + 1) / 2; limits.MaxMapSize > 0 && size > limits.MaxMapSize {
+					msg := fmt.Sprintf("request body contains an object with more than %d keys", limits.MaxMapSize)
+					return ErrInvalidRequest(msg, "limit", "map_size", "max", limits.MaxMapSize)
+				}
+			} else if limits.MaxArrayLength > 0 && f.count > limits.MaxArrayLength {
+				msg := fmt.Sprintf("request body contains an array with more than %d elements", limits.MaxArrayLength)
+				return ErrInvalidRequest(msg, "limit", "array_length", "max", limits.MaxArrayLength)
+			}
+		}
+		if d, ok := tok.(json.Delim); ok {
+			stack = append(stack, &limitsFrame{object: d == '{'})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				msg := fmt.Sprintf("request body exceeds the maximum nesting depth of %d", limits.MaxDepth)
+				return ErrInvalidRequest(msg, "limit", "depth", "max", limits.MaxDepth)
+			}
+		}
+	}
+}
+
+// isJSON returns true if the content type designates a JSON document.
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,120 @@
+package goa_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LimitRequest", func() {
+	var body, contentType string
+	var limits goa.PayloadLimits
+	var req *http.Request
+	var limitErr error
+
+	BeforeEach(func() {
+		body = `{"name":"foo","tags":["a","b"],"winery":{"region":{"name":"napa"}}}`
+		contentType = "application/json"
+		limits = goa.PayloadLimits{MaxDepth: 3, MaxArrayLength: 2, MaxMapSize: 3}
+	})
+
+	JustBeforeEach(func() {
+		req, _ = http.NewRequest("POST", "/bottles", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		limitErr = goa.LimitRequest(req, limits)
+	})
+
+	It("accepts bodies within the limits and preserves them", func() {
+		Ω(limitErr).ShouldNot(HaveOccurred())
+		b, err := ioutil.ReadAll(req.Body)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(body))
+	})
+
+	Context("with a body nested too deeply", func() {
+		BeforeEach(func() {
+			body = `{"a":{"b":{"c":{"d":1}}}}`
+		})
+
+		It("rejects the body", func() {
+			Ω(limitErr).Should(HaveOccurred())
+			Ω(limitErr.Error()).Should(ContainSubstring("maximum nesting depth of 3"))
+			Ω(limitErr.(*goa.ErrorResponse).Status).Should(Equal(400))
+		})
+	})
+
+	Context("with an array too long", func() {
+		BeforeEach(func() {
+			body = `{"tags":[[1],[2],[3]]}`
+		})
+
+		It("rejects the body", func() {
+			Ω(limitErr).Should(HaveOccurred())
+			Ω(limitErr.Error()).Should(ContainSubstring("array with more than 2 elements"))
+		})
+	})
+
+	Context("with an object with too many keys", func() {
+		BeforeEach(func() {
+			body = `[{"a":{},"b":[],"c":1,"d":2}]`
+		})
+
+		It("rejects the body", func() {
+			Ω(limitErr).Should(HaveOccurred())
+			Ω(limitErr.Error()).Should(ContainSubstring("object with more than 3 keys"))
+		})
+	})
+
+	Context("with no limits", func() {
+		BeforeEach(func() {
+			body = `{"a":{"b":{"c":{"d":[1,2,3,4]}}}}`
+			limits = goa.PayloadLimits{}
+		})
+
+		It("accepts the body", func() {
+			Ω(limitErr).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a malformed body", func() {
+		BeforeEach(func() {
+			body = `{"a":`
+		})
+
+		It("leaves the error to the decoder", func() {
+			Ω(limitErr).ShouldNot(HaveOccurred())
+			b, _ := ioutil.ReadAll(req.Body)
+			Ω(string(b)).Should(Equal(body))
+		})
+	})
+
+	Context("with a body with no content type", func() {
+		BeforeEach(func() {
+			body = `{"a":{"b":{"c":{"d":1}}}}`
+			contentType = ""
+		})
+
+		It("scans the body as JSON", func() {
+			Ω(limitErr).Should(HaveOccurred())
+			Ω(limitErr.Error()).Should(ContainSubstring("maximum nesting depth of 3"))
+		})
+	})
+
+	Context("with a body that is not JSON", func() {
+		BeforeEach(func() {
+			body = "a=1&b=2&c=3&d=4"
+			contentType = "application/x-www-form-urlencoded"
+			limits = goa.PayloadLimits{MaxMapSize: 1}
+		})
+
+		It("does not scan the body", func() {
+			Ω(limitErr).ShouldNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package goa
+
+import (
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+type (
+	// MaintenanceSwitch records which endpoints of the service are in maintenance mode. The
+	// switch may be flipped at any time while the service runs, requests made to endpoints in
+	// maintenance are answered with the maintenance response declared in the design.
+	// Endpoints are identified by resource name ("bottle") or by resource and action names
+	// separated with "#" ("bottle#show").
+	MaintenanceSwitch struct {
+		mu        sync.RWMutex
+		all       bool
+		endpoints map[string]bool
+	}
+
+	// MaintenanceResponse describes the response sent to requests made to endpoints in
+	// maintenance.
+	MaintenanceResponse struct {
+		// Status is the HTTP status of the response.
+		Status int
+		// Error is true if the response body is an error using the error media type,
+		// the response has no body otherwise.
+		Error bool
+	}
+)
+
+// NewMaintenanceSwitch returns a maintenance switch with all endpoints in service.
+func NewMaintenanceSwitch() *MaintenanceSwitch {
+	return &MaintenanceSwitch{endpoints: make(map[string]bool)}
+}
+
+// Enable puts the given endpoints in maintenance mode. Enable puts the whole API in maintenance
+// mode when called without argument.
+func (m *MaintenanceSwitch) Enable(endpoints ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(endpoints) == 0 {
+		m.all = true
+		return
+	}
+	for _, e := range endpoints {
+		m.endpoints[e] = true
+	}
+}
+
+// Disable puts the given endpoints back in service. Disable puts all the endpoints back in
+// service when called without argument.
+func (m *MaintenanceSwitch) Disable(endpoints ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(endpoints) == 0 {
+		m.all = false
+		m.endpoints = make(map[string]bool)
+		return
+	}
+	for _, e := range endpoints {
+		delete(m.endpoints, e)
+	}
+}
+
+// Enabled returns true if the given resource action is in maintenance mode, either because the
+// whole API, the resource or the action is.
+func (m *MaintenanceSwitch) Enabled(resource, action string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.all || m.endpoints[resource] || m.endpoints[resource+"#"+action]
+}
+
+// Handle returns a middleware that answers requests made to the given resource action with resp
+// while the action is in maintenance mode.
+func (m *MaintenanceSwitch) Handle(resource, action string, resp MaintenanceResponse) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if !m.Enabled(resource, action) {
+				return h(ctx, rw, req)
+			}
+			if resp.Error {
+				return NewErrorClass("maintenance", resp.Status)("the service is under maintenance",
+					"endpoint", resource+"#"+action)
+			}
+			rw.WriteHeader(resp.Status)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaintenanceSwitch", func() {
+	var sw *goa.MaintenanceSwitch
+
+	BeforeEach(func() {
+		sw = goa.NewMaintenanceSwitch()
+	})
+
+	It("starts with all endpoints in service", func() {
+		Ω(sw.Enabled("bottle", "show")).Should(BeFalse())
+	})
+
+	It("puts the whole API in maintenance", func() {
+		sw.Enable()
+		Ω(sw.Enabled("bottle", "show")).Should(BeTrue())
+		Ω(sw.Enabled("account", "list")).Should(BeTrue())
+		sw.Disable()
+		Ω(sw.Enabled("bottle", "show")).Should(BeFalse())
+	})
+
+	It("puts resources and actions in maintenance", func() {
+		sw.Enable("bottle", "account#show")
+		Ω(sw.Enabled("bottle", "list")).Should(BeTrue())
+		Ω(sw.Enabled("account", "show")).Should(BeTrue())
+		Ω(sw.Enabled("account", "list")).Should(BeFalse())
+		sw.Disable("bottle")
+		Ω(sw.Enabled("bottle", "list")).Should(BeFalse())
+		Ω(sw.Enabled("account", "show")).Should(BeTrue())
+	})
+
+	Describe("Handle", func() {
+		var resp goa.MaintenanceResponse
+		var called bool
+		var rw *httptest.ResponseRecorder
+		var err error
+
+		BeforeEach(func() {
+			resp = goa.MaintenanceResponse{Status: 503}
+			called = false
+			rw = httptest.NewRecorder()
+		})
+
+		JustBeforeEach(func() {
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				called = true
+				return nil
+			}
+			req, _ := http.NewRequest("GET", "/bottles", nil)
+			err = sw.Handle("bottle", "list", resp)(h)(context.Background(), rw, req)
+		})
+
+		It("calls the handler when in service", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(called).Should(BeTrue())
+		})
+
+		Context("in maintenance", func() {
+			BeforeEach(func() {
+				sw.Enable("bottle#list")
+			})
+
+			It("writes the maintenance status", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(called).Should(BeFalse())
+				Ω(rw.Code).Should(Equal(503))
+			})
+
+			Context("with an error response", func() {
+				BeforeEach(func() {
+					resp = goa.MaintenanceResponse{Status: 502, Error: true}
+				})
+
+				It("returns a maintenance error", func() {
+					Ω(called).Should(BeFalse())
+					Ω(err).Should(HaveOccurred())
+					serr, ok := err.(goa.ServiceError)
+					Ω(ok).Should(BeTrue())
+					Ω(serr.ResponseStatus()).Should(Equal(502))
+					Ω(serr.Token()).ShouldNot(BeEmpty())
+				})
+			})
+		})
+	})
+})
@@ -0,0 +1,21 @@
+/*
+Package cache provides a middleware that sets the Cache-Control and Vary headers of successful
+responses according to a caching policy and optionally caches the responses server-side.
+
+The server-side cache is enabled by registering a store with UseStore prior to mounting the
+controllers. Responses are keyed by controller, action, request parameters and the values of the
+request headers listed in the policy Vary field. Cached responses are served for MaxAge seconds,
+then for StaleWhileRevalidate more seconds while a background request refreshes the entry.
+
+The responses of secured actions are only stored if the policy varies on the request header that
+carries the credentials, see Policy.Secured and Policy.Credential, so that a response is never
+served to a caller other than the one it was computed for. The generated code mounts the cache
+inside the checks of the action required scopes and roles and the audit event emission so that
+cached responses are subject to the same authorization and auditing as computed ones.
+
+The cache is mounted inside the compression and CORS middlewares. The headers these middlewares
+set depend on the request: Content-Encoding, Content-Length, the Access-Control headers and the
+Origin and Accept-Encoding Vary values are not stored, and serving a cached response never
+overrides the headers already set so that they apply to the cached responses as to computed ones.
+*/
+package cache
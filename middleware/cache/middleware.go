@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// Policy describes how the responses of an action may be cached.
+	Policy struct {
+		// MaxAge is the number of seconds during which the response is fresh.
+		MaxAge int
+		// StaleWhileRevalidate is the number of seconds after the response becomes stale
+		// during which caches may serve it while revalidating it in the background.
+		StaleWhileRevalidate int
+		// Vary lists the request headers that select the cached response.
+		Vary []string
+		// Secured is true if the action requires credentials. The responses of secured
+		// actions are only stored if the policy varies on the Credential header.
+		Secured bool
+		// Credential is the name of the request header that carries the credentials of
+		// secured actions, e.g. "Authorization", "X-Api-Key" or "Cookie". It is empty if the
+		// credentials are carried by a query string parameter as the parameters are always
+		// part of the cache key.
+		Credential string
+	}
+
+	// storeKey is the key used to store the response cache store in the service context.
+	storeKey struct{}
+
+	// recorder is the response writer that sets the policy headers and captures the response
+	// sent to the client.
+	recorder struct {
+		http.ResponseWriter
+		policy  *Policy
+		capture bool
+		status  int
+		body    []byte
+	}
+
+	// discardWriter is the response writer used by the background refreshes.
+	discardWriter struct {
+		header http.Header
+	}
+)
+
+// UseStore registers the store used to cache the responses server-side. UseStore must be called
+// before the controllers are created.
+func UseStore(service *goa.Service, s Store) {
+	service.Context = context.WithValue(service.Context, storeKey{}, s)
+}
+
+// CacheControl returns the value of the Cache-Control header corresponding to the policy.
+func (p *Policy) CacheControl() string {
+	cc := fmt.Sprintf("max-age=%d", p.MaxAge)
+	if p.StaleWhileRevalidate > 0 {
+		cc += fmt.Sprintf(", stale-while-revalidate=%d", p.StaleWhileRevalidate)
+	}
+	return cc
+}
+
+// Middleware returns a middleware that sets the Cache-Control and Vary headers of the successful
+// responses according to the given policy. The middleware also caches the responses to GET
+// requests in the store registered with UseStore if any. The responses of secured actions are only
+// cached if the policy varies on the header carrying the credentials, the responses to requests
+// carrying an Authorization header are only cached if the policy varies on it.
+func Middleware(p Policy) goa.Middleware {
+	var mu sync.Mutex
+	refreshing := make(map[string]bool)
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			resp := goa.ContextResponse(ctx)
+			if resp == nil {
+				return h(ctx, rw, req)
+			}
+			store, _ := ctx.Value(storeKey{}).(Store)
+			if store == nil || req.Method != "GET" || !p.shareable(req) {
+				return record(ctx, h, rw, req, &p, nil)
+			}
+			key := p.key(ctx, req)
+			if e, ok := store.Get(key); ok {
+				age := int(time.Since(e.Stored).Seconds())
+				if age > p.MaxAge {
+					mu.Lock()
+					refresh := !refreshing[key]
+					refreshing[key] = true
+					mu.Unlock()
+					if refresh {
+						go func() {
+							defer func() {
+								mu.Lock()
+								delete(refreshing, key)
+								mu.Unlock()
+							}()
+							p.refresh(ctx, h, req, store, key)
+						}()
+					}
+				}
+				return serve(resp, e, age)
+			}
+			return record(ctx, h, rw, req, &p, func(e *Entry) {
+				store.Set(key, e, p.ttl())
+			})
+		}
+	}
+}
+
+// record calls the handler with a writer that sets the policy headers and captures the response.
+// It calls store with the captured response if the response can be cached.
+func record(ctx context.Context, h goa.Handler, rw http.ResponseWriter, req *http.Request, p *Policy, store func(*Entry)) error {
+	resp := goa.ContextResponse(ctx)
+	w := resp.SwitchWriter(nil)
+	rec := &recorder{ResponseWriter: w, policy: p, capture: store != nil}
+	resp.SwitchWriter(rec)
+	err := h(ctx, rw, req)
+	resp.SwitchWriter(w)
+	if err == nil && store != nil && rec.status == http.StatusOK && cacheable(w.Header()) {
+		store(&Entry{
+			Status: rec.status,
+			Header: storedHeader(w.Header()),
+			Body:   rec.body,
+			Stored: time.Now(),
+		})
+	}
+	return err
+}
+
+// refresh calls the handler in the background and stores the new response.
+func (p *Policy) refresh(ctx context.Context, h goa.Handler, req *http.Request, store Store, key string) {
+	w := &discardWriter{header: make(http.Header)}
+	rctx := goa.NewContext(ctx, w, req, goa.ContextRequest(ctx).Params)
+	record(rctx, h, goa.ContextResponse(rctx), req, p, func(e *Entry) {
+		store.Set(key, e, p.ttl())
+	})
+}
+
+// serve writes the cached response. The headers already set by the middlewares mounted around the
+// cache, e.g. the CORS headers, are kept and the Vary values are merged.
+func serve(resp *goa.ResponseData, e *Entry, age int) error {
+	h := resp.Header()
+	for k, v := range e.Header {
+		if k == "Vary" {
+			for _, val := range v {
+				if !hasToken(h[k], val) {
+					h.Add(k, val)
+				}
+			}
+			continue
+		}
+		if _, ok := h[k]; ok {
+			continue
+		}
+		h[k] = v
+	}
+	h.Set("Age", strconv.Itoa(age))
+	resp.WriteHeader(e.Status)
+	_, err := resp.Write(e.Body)
+	return err
+}
+
+// key computes the cache key of the request.
+func (p *Policy) key(ctx context.Context, req *http.Request) string {
+	key := goa.ContextController(ctx) + "#" + goa.ContextAction(ctx) + "?" + goa.ContextRequest(ctx).Params.Encode()
+	for _, v := range p.Vary {
+		key += "\n" + http.CanonicalHeaderKey(v) + ": " + strings.Join(req.Header[http.CanonicalHeaderKey(v)], ",")
+	}
+	return key
+}
+
+// shareable returns false if the action is secured or the request carries credentials and the
+// policy does not vary on the header carrying the credentials.
+func (p *Policy) shareable(req *http.Request) bool {
+	cred := p.Credential
+	if !p.Secured {
+		if req.Header.Get("Authorization") == "" {
+			return true
+		}
+		cred = "Authorization"
+	} else if cred == "" {
+		return true
+	}
+	for _, v := range p.Vary {
+		if http.CanonicalHeaderKey(v) == http.CanonicalHeaderKey(cred) {
+			return true
+		}
+	}
+	return false
+}
+
+// ttl returns the duration during which cached responses may be served.
+func (p *Policy) ttl() time.Duration {
+	return time.Duration(p.MaxAge+p.StaleWhileRevalidate) * time.Second
+}
+
+// cacheable returns false if the response headers forbid storing the response.
+func cacheable(h http.Header) bool {
+	if h.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := h.Get("Cache-Control")
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private") && !strings.Contains(cc, "no-cache")
+}
+
+// storedHeader returns a copy of the given headers without the headers that depend on the request
+// and are set by the compression and CORS middlewares mounted around the cache: Content-Encoding,
+// Content-Length, the Access-Control headers and the Origin and Accept-Encoding Vary values.
+func storedHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		switch {
+		case k == "Content-Encoding", k == "Content-Length", strings.HasPrefix(k, "Access-Control-"):
+			continue
+		case k == "Vary":
+			var vary []string
+			for _, val := range v {
+				for _, t := range strings.Split(val, ",") {
+					t = http.CanonicalHeaderKey(strings.TrimSpace(t))
+					if t != "" && t != "Origin" && t != "Accept-Encoding" {
+						vary = append(vary, t)
+					}
+				}
+			}
+			if len(vary) > 0 {
+				c[k] = vary
+			}
+			continue
+		}
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+// hasToken returns true if the comma separated header values contain the given token.
+func hasToken(values []string, token string) bool {
+	for _, val := range values {
+		for _, t := range strings.Split(val, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteHeader sets the policy headers of successful responses and records the status.
+func (r *recorder) WriteHeader(status int) {
+	if r.status != 0 {
+		return
+	}
+	r.status = status
+	if status >= 200 && status < 300 {
+		h := r.Header()
+		if h.Get("Cache-Control") == "" {
+			h.Set("Cache-Control", r.policy.CacheControl())
+		}
+		for _, v := range r.policy.Vary {
+			h.Add("Vary", http.CanonicalHeaderKey(v))
+		}
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body.
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.capture {
+		r.body = append(r.body, b...)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Header returns the response headers.
+func (w *discardWriter) Header() http.Header { return w.header }
+
+// Write discards the data.
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+
+// WriteHeader does nothing.
+func (w *discardWriter) WriteHeader(int) {}
@@ -0,0 +1,250 @@
+package cache_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/cache"
+	"github.com/goadesign/goa/middleware/compress"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	var service *goa.Service
+	var policy cache.Policy
+	var store cache.Store
+	var calls int32
+	var status int
+	var header http.Header
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		policy = cache.Policy{MaxAge: 60, StaleWhileRevalidate: 300, Vary: []string{"accept"}}
+		store = nil
+		calls = 0
+		status = http.StatusOK
+		header = make(http.Header)
+	})
+
+	send := func(params url.Values, reqHeader http.Header) *httptest.ResponseRecorder {
+		if store != nil {
+			cache.UseStore(service, store)
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			atomic.AddInt32(&calls, 1)
+			for k, v := range header {
+				rw.Header()[k] = v
+			}
+			rw.WriteHeader(status)
+			rw.Write([]byte("body"))
+			return nil
+		}
+		req, _ := http.NewRequest("GET", "/widgets", nil)
+		for k, v := range reqHeader {
+			req.Header[k] = v
+		}
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(goa.WithAction(service.Context, "show"), rw, req, params)
+		err := cache.Middleware(policy)(h)(ctx, goa.ContextResponse(ctx), req)
+		Ω(err).ShouldNot(HaveOccurred())
+		return rw
+	}
+
+	It("sets the Cache-Control and Vary headers of successful responses", func() {
+		rw := send(nil, nil)
+		Ω(rw.Header().Get("Cache-Control")).Should(Equal("max-age=60, stale-while-revalidate=300"))
+		Ω(rw.Header().Get("Vary")).Should(Equal("Accept"))
+		Ω(rw.Body.String()).Should(Equal("body"))
+	})
+
+	Context("with an error response", func() {
+		BeforeEach(func() {
+			status = http.StatusNotFound
+		})
+
+		It("does not set the caching headers", func() {
+			rw := send(nil, nil)
+			Ω(rw.Code).Should(Equal(http.StatusNotFound))
+			Ω(rw.Header().Get("Cache-Control")).Should(BeEmpty())
+		})
+	})
+
+	Context("with a store", func() {
+		BeforeEach(func() {
+			store = cache.NewMemoryStore()
+		})
+
+		It("serves cached responses", func() {
+			send(url.Values{"id": {"1"}}, nil)
+			rw := send(url.Values{"id": {"1"}}, nil)
+			Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(1)))
+			Ω(rw.Code).Should(Equal(http.StatusOK))
+			Ω(rw.Body.String()).Should(Equal("body"))
+			Ω(rw.Header().Get("Age")).Should(Equal("0"))
+			Ω(rw.Header().Get("Cache-Control")).Should(Equal("max-age=60, stale-while-revalidate=300"))
+		})
+
+		It("keys the responses by parameters", func() {
+			send(url.Values{"id": {"1"}}, nil)
+			send(url.Values{"id": {"2"}}, nil)
+			Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+		})
+
+		It("keys the responses by vary headers", func() {
+			send(nil, http.Header{"Accept": {"application/json"}})
+			send(nil, http.Header{"Accept": {"application/xml"}})
+			send(nil, http.Header{"Accept": {"application/json"}})
+			Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+		})
+
+		It("does not cache the responses to authorized requests", func() {
+			send(nil, http.Header{"Authorization": {"Bearer token"}})
+			send(nil, http.Header{"Authorization": {"Bearer token"}})
+			Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+		})
+
+		It("caches the responses to authorized requests if the policy varies on the credentials", func() {
+			policy.Vary = []string{"Authorization"}
+			send(nil, http.Header{"Authorization": {"Bearer token"}})
+			send(nil, http.Header{"Authorization": {"Bearer other"}})
+			send(nil, http.Header{"Authorization": {"Bearer token"}})
+			Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+		})
+
+		Context("and a secured action", func() {
+			BeforeEach(func() {
+				policy.Secured = true
+				policy.Credential = "X-Api-Key"
+			})
+
+			It("does not cache the responses", func() {
+				send(nil, http.Header{"X-Api-Key": {"key1"}})
+				send(nil, http.Header{"X-Api-Key": {"key2"}})
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+			})
+
+			It("caches the responses by credentials if the policy varies on them", func() {
+				policy.Vary = append(policy.Vary, "X-Api-Key")
+				send(nil, http.Header{"X-Api-Key": {"key1"}})
+				send(nil, http.Header{"X-Api-Key": {"key2"}})
+				send(nil, http.Header{"X-Api-Key": {"key1"}})
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+			})
+		})
+
+		Context("and a response that must not be stored", func() {
+			BeforeEach(func() {
+				header.Set("Cache-Control", "no-store")
+			})
+
+			It("does not cache it", func() {
+				send(nil, nil)
+				rw := send(nil, nil)
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(2)))
+				Ω(rw.Header().Get("Cache-Control")).Should(Equal("no-store"))
+			})
+		})
+
+		Context("mounted with the compression and CORS middlewares", func() {
+			serveWith := func(reqHeader http.Header) *httptest.ResponseRecorder {
+				cache.UseStore(service, store)
+				h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					atomic.AddInt32(&calls, 1)
+					rw.Header().Set("Content-Type", "text/plain")
+					rw.WriteHeader(http.StatusOK)
+					rw.Write([]byte("body"))
+					return nil
+				}
+				origin := func(h goa.Handler) goa.Handler {
+					return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+						if o := req.Header.Get("Origin"); o != "" {
+							rw.Header().Set("Access-Control-Allow-Origin", o)
+							rw.Header().Set("Vary", "Origin")
+						}
+						return h(ctx, rw, req)
+					}
+				}
+				compressor := compress.Middleware(compress.Options{MinSize: 1})
+				req, _ := http.NewRequest("GET", "/widgets", nil)
+				for k, v := range reqHeader {
+					req.Header[k] = v
+				}
+				rw := httptest.NewRecorder()
+				ctx := goa.NewContext(goa.WithAction(service.Context, "show"), rw, req, nil)
+				err := origin(compressor(cache.Middleware(policy)(h)))(ctx, goa.ContextResponse(ctx), req)
+				Ω(err).ShouldNot(HaveOccurred())
+				return rw
+			}
+
+			It("does not replay the content encoding of the cached response", func() {
+				rw := serveWith(http.Header{"Accept-Encoding": {"gzip"}})
+				Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+				rw = serveWith(nil)
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(1)))
+				Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+				Ω(rw.Body.String()).Should(Equal("body"))
+			})
+
+			It("compresses the cached response for the clients that accept it", func() {
+				serveWith(nil)
+				rw := serveWith(http.Header{"Accept-Encoding": {"gzip"}})
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(1)))
+				Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+				gz, err := gzip.NewReader(rw.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				body, err := ioutil.ReadAll(gz)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(body)).Should(Equal("body"))
+			})
+
+			It("does not replay the CORS headers of the cached response", func() {
+				serveWith(http.Header{"Origin": {"http://a.example"}})
+				rw := serveWith(http.Header{"Origin": {"http://b.example"}})
+				Ω(atomic.LoadInt32(&calls)).Should(Equal(int32(1)))
+				Ω(rw.Header().Get("Access-Control-Allow-Origin")).Should(Equal("http://b.example"))
+				rw = serveWith(nil)
+				Ω(rw.Header().Get("Access-Control-Allow-Origin")).Should(BeEmpty())
+				Ω(rw.Header()["Vary"]).Should(Equal([]string{"Accept"}))
+			})
+		})
+
+		Context("and a stale response", func() {
+			BeforeEach(func() {
+				store = &agingStore{Store: store, age: 90 * time.Second}
+			})
+
+			It("serves it while refreshing it in the background", func() {
+				send(nil, nil)
+				rw := send(nil, nil)
+				Ω(rw.Body.String()).Should(Equal("body"))
+				Ω(rw.Header().Get("Age")).Should(Equal("90"))
+				Eventually(func() int32 { return atomic.LoadInt32(&calls) }).Should(Equal(int32(2)))
+			})
+		})
+	})
+})
+
+// agingStore is a store that makes the entries look older than they are.
+type agingStore struct {
+	cache.Store
+	age time.Duration
+}
+
+func (s *agingStore) Get(key string) (*cache.Entry, bool) {
+	e, ok := s.Store.Get(key)
+	if !ok {
+		return nil, false
+	}
+	aged := *e
+	aged.Stored = e.Stored.Add(-s.age)
+	return &aged, true
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// Store is the interface implemented by the server-side response caches.
+	Store interface {
+		// Get returns the entry stored under the given key if any.
+		Get(key string) (*Entry, bool)
+		// Set stores the entry under the given key for the given duration.
+		Set(key string, e *Entry, ttl time.Duration)
+	}
+
+	// Entry is a cached response.
+	Entry struct {
+		// Status is the response status code.
+		Status int
+		// Header contains the response headers.
+		Header http.Header
+		// Body is the response body.
+		Body []byte
+		// Stored is the time the response was stored.
+		Stored time.Time
+	}
+
+	// memoryStore is a Store that keeps the entries in memory.
+	memoryStore struct {
+		mu      sync.Mutex
+		entries map[string]*memoryEntry
+	}
+
+	// memoryEntry is an entry of the in-memory store.
+	memoryEntry struct {
+		entry   *Entry
+		expires time.Time
+	}
+)
+
+// NewMemoryStore returns a store that keeps the entries in memory. Expired entries are evicted
+// when accessed.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// Get returns the entry stored under the given key if it has not expired.
+func (s *memoryStore) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return e.entry, true
+}
+
+// Set stores the entry under the given key for the given duration.
+func (s *memoryStore) Set(key string, e *Entry, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &memoryEntry{entry: e, expires: time.Now().Add(ttl)}
+}
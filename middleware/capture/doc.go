@@ -0,0 +1,14 @@
+/*
+Package capture provides a middleware that records the requests handled by a service together with
+the responses it sends into a pluggable sink, and the functions needed to replay the recorded
+requests against another deployment of the service.
+
+Capturing is enabled by registering a sink with UseSink prior to mounting the controllers, the
+middleware does nothing otherwise. The recorded request bodies are snapshots of the decoded
+payloads. The values of the sensitive attributes, headers and parameters listed in the middleware
+options are masked before the exchanges reach the sink so that captures can be shared safely.
+
+NewWriterSink writes the exchanges as newline delimited JSON, ReadExchanges reads them back and
+Replay re-issues a recorded request against the given host.
+*/
+package capture
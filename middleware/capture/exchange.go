@@ -0,0 +1,99 @@
+package capture
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type (
+	// Exchange is a captured request together with the response sent by the service.
+	Exchange struct {
+		// Resource is the name of the resource that handled the request.
+		Resource string `json:"resource"`
+		// Action is the name of the action that handled the request.
+		Action string `json:"action"`
+		// Time is the time the request was received.
+		Time time.Time `json:"time"`
+		// Duration is the time it took to handle the request.
+		Duration time.Duration `json:"duration"`
+		// Request is the captured request.
+		Request *Request `json:"request"`
+		// Response is the captured response.
+		Response *Response `json:"response"`
+		// Error is the error returned by the action if any. The response of actions that
+		// return an error is written by the error handler middleware and is not captured.
+		Error string `json:"error,omitempty"`
+	}
+
+	// Request is a captured request.
+	Request struct {
+		// Method is the request HTTP method.
+		Method string `json:"method"`
+		// URL is the request URI made of the path and query string.
+		URL string `json:"url"`
+		// Header contains the request headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is a snapshot of the request payload if any.
+		Body interface{} `json:"body,omitempty"`
+	}
+
+	// Response is a captured response.
+	Response struct {
+		// Status is the response status code, it is 0 if the action returned an error
+		// before writing the response.
+		Status int `json:"status"`
+		// Header contains the response headers.
+		Header http.Header `json:"header,omitempty"`
+		// Body is the response body, JSON bodies are stored decoded so that their sensitive
+		// attributes can be masked.
+		Body interface{} `json:"body,omitempty"`
+		// Truncated is true if the response body was larger than the captured size.
+		Truncated bool `json:"truncated,omitempty"`
+	}
+
+	// Sink is the interface implemented by the capture backends.
+	Sink interface {
+		// Write records the given exchange.
+		Write(ctx context.Context, e *Exchange) error
+	}
+
+	// writerSink is a Sink that writes the exchanges to a writer.
+	writerSink struct {
+		mu  sync.Mutex
+		enc *json.Encoder
+	}
+)
+
+// NewWriterSink returns a sink that writes the exchanges to w as newline delimited JSON. The sink
+// may be used concurrently.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{enc: json.NewEncoder(w)}
+}
+
+// Write writes the exchange followed by a newline.
+func (s *writerSink) Write(ctx context.Context, e *Exchange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(e)
+}
+
+// ReadExchanges reads the exchanges written by a writer sink.
+func ReadExchanges(r io.Reader) ([]*Exchange, error) {
+	var exchanges []*Exchange
+	dec := json.NewDecoder(r)
+	for {
+		var e Exchange
+		if err := dec.Decode(&e); err != nil {
+			if err == io.EOF {
+				return exchanges, nil
+			}
+			return nil, err
+		}
+		exchanges = append(exchanges, &e)
+	}
+}
@@ -0,0 +1,227 @@
+package capture
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+// DefaultMaxBodySize is the maximum number of response body bytes captured when the options do
+// not specify one.
+const DefaultMaxBodySize = 64 << 10
+
+// Redacted is the value of the masked attributes, headers and parameters.
+const Redacted = "[REDACTED]"
+
+// SensitiveHeaders lists the request and response headers that are always masked.
+var SensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+type (
+	// Options configures the capture middleware of an action.
+	Options struct {
+		// Resource is the name of the resource recorded in the exchanges.
+		Resource string
+		// Action is the name of the action recorded in the exchanges.
+		Action string
+		// MaxBodySize is the maximum number of response body bytes captured, larger bodies
+		// are omitted. Defaults to DefaultMaxBodySize.
+		MaxBodySize int
+		// Payload lists the paths to the sensitive payload attributes, e.g. "card.number".
+		Payload []string
+		// Response lists the paths to the sensitive attributes of the JSON response bodies.
+		Response []string
+		// Headers lists the names of the sensitive headers in addition to
+		// SensitiveHeaders.
+		Headers []string
+		// Params lists the names of the sensitive query string parameters.
+		Params []string
+	}
+
+	// sinkKey is the key used to store the sink in the service context.
+	sinkKey struct{}
+
+	// recorder is the response writer that records the response sent to the client.
+	recorder struct {
+		http.ResponseWriter
+		max       int
+		status    int
+		body      []byte
+		truncated bool
+	}
+)
+
+// UseSink registers the sink that receives the captured exchanges. UseSink must be called before
+// the controllers are created.
+func UseSink(service *goa.Service, s Sink) {
+	service.Context = context.WithValue(service.Context, sinkKey{}, s)
+}
+
+// Middleware returns a middleware that records the requests and responses of an action in the
+// sink registered with UseSink if any. The values of the sensitive attributes, headers and
+// parameters listed in the options are masked. Response bodies that are not JSON are omitted
+// when the options list sensitive response attributes.
+func Middleware(opts Options) goa.Middleware {
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			sink, _ := ctx.Value(sinkKey{}).(Sink)
+			resp := goa.ContextResponse(ctx)
+			if sink == nil || resp == nil {
+				return h(ctx, rw, req)
+			}
+			started := time.Now()
+			w := resp.SwitchWriter(nil)
+			rec := &recorder{ResponseWriter: w, max: opts.MaxBodySize}
+			resp.SwitchWriter(rec)
+			err := h(ctx, rw, req)
+			resp.SwitchWriter(w)
+
+			e := &Exchange{
+				Resource: opts.Resource,
+				Action:   opts.Action,
+				Time:     started,
+				Duration: time.Since(started),
+				Request:  opts.request(ctx, req),
+				Response: &Response{
+					Status:    rec.status,
+					Header:    opts.header(w.Header()),
+					Truncated: rec.truncated,
+				},
+			}
+			if err != nil {
+				e.Error = err.Error()
+			}
+			if !rec.truncated && len(rec.body) > 0 {
+				e.Response.Body = opts.responseBody(rec.body)
+			}
+			if serr := sink.Write(ctx, e); serr != nil {
+				goa.LogError(ctx, "capture failed", "err", serr)
+			}
+			return err
+		}
+	}
+}
+
+// request builds the captured request.
+func (o *Options) request(ctx context.Context, req *http.Request) *Request {
+	r := &Request{
+		Method: req.Method,
+		URL:    req.URL.EscapedPath(),
+		Header: o.header(req.Header),
+	}
+	if q := req.URL.Query(); len(q) > 0 {
+		for _, p := range o.Params {
+			if vals, ok := q[p]; ok {
+				for i := range vals {
+					vals[i] = Redacted
+				}
+			}
+		}
+		r.URL += "?" + q.Encode()
+	}
+	if rd := goa.ContextRequest(ctx); rd != nil && rd.Payload != nil {
+		r.Body = Snapshot(rd.Payload, o.Payload...)
+	}
+	return r
+}
+
+// responseBody returns the captured response body.
+func (o *Options) responseBody(body []byte) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		if len(o.Response) > 0 {
+			return nil
+		}
+		return string(body)
+	}
+	for _, p := range o.Response {
+		redact(v, strings.Split(p, "."))
+	}
+	return v
+}
+
+// header returns a copy of h where the sensitive headers are masked.
+func (o *Options) header(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	for _, names := range [][]string{SensitiveHeaders, o.Headers} {
+		for _, n := range names {
+			if _, ok := c[http.CanonicalHeaderKey(n)]; ok {
+				c.Set(n, Redacted)
+			}
+		}
+	}
+	return c
+}
+
+// Snapshot returns a copy of v made of JSON compatible values where the values of the attributes
+// at the given paths are masked. Array elements are traversed transparently so that "items.sku"
+// masks the "sku" attribute of all the elements of the "items" array.
+func Snapshot(v interface{}, redacted ...string) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var snapshot interface{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil
+	}
+	for _, p := range redacted {
+		redact(snapshot, strings.Split(p, "."))
+	}
+	return snapshot
+}
+
+// redact masks the value at the given path in v.
+func redact(v interface{}, path []string) {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			if _, ok := actual[path[0]]; ok {
+				actual[path[0]] = Redacted
+			}
+			return
+		}
+		redact(actual[path[0]], path[1:])
+	case []interface{}:
+		for _, e := range actual {
+			redact(e, path)
+		}
+	}
+}
+
+// WriteHeader records the status.
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the body up to the maximum captured size.
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if !r.truncated {
+		if len(r.body)+len(b) > r.max {
+			r.truncated = true
+			r.body = nil
+		} else {
+			r.body = append(r.body, b...)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
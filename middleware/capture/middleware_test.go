@@ -0,0 +1,135 @@
+package capture_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/capture"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type card struct {
+	Holder string `json:"holder"`
+	Number string `json:"number"`
+}
+
+type order struct {
+	Items []*card `json:"items"`
+	Note  string  `json:"note"`
+}
+
+var _ = Describe("Middleware", func() {
+	var service *goa.Service
+	var opts capture.Options
+	var buf *bytes.Buffer
+	var payload interface{}
+	var respBody string
+	var handlerErr error
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		buf = new(bytes.Buffer)
+		opts = capture.Options{Resource: "order", Action: "create"}
+		payload = &order{Items: []*card{{Holder: "joe", Number: "4111"}}, Note: "fast"}
+		respBody = `{"id":1,"secret":"s3cr3t"}`
+		handlerErr = nil
+	})
+
+	send := func(target string) (*httptest.ResponseRecorder, error) {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if handlerErr != nil {
+				return handlerErr
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte(respBody))
+			return nil
+		}
+		req, _ := http.NewRequest("POST", target, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set("X-Api-Key", "key")
+		req.Header.Set("X-Trace", "trace")
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(service.Context, rw, req, nil)
+		goa.ContextRequest(ctx).Payload = payload
+		err := capture.Middleware(opts)(h)(ctx, goa.ContextResponse(ctx), req)
+		return rw, err
+	}
+
+	read := func() []*capture.Exchange {
+		exchanges, err := capture.ReadExchanges(buf)
+		Ω(err).ShouldNot(HaveOccurred())
+		return exchanges
+	}
+
+	It("does nothing without a sink", func() {
+		rw, err := send("/orders")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusCreated))
+		Ω(buf.Len()).Should(Equal(0))
+	})
+
+	Context("with a sink", func() {
+		BeforeEach(func() {
+			capture.UseSink(service, capture.NewWriterSink(buf))
+			opts.Payload = []string{"items.number"}
+			opts.Response = []string{"secret"}
+			opts.Headers = []string{"X-Api-Key"}
+			opts.Params = []string{"token"}
+		})
+
+		It("captures the exchange with the sensitive values masked", func() {
+			rw, err := send("/orders?token=abc&page=2")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Body.String()).Should(Equal(respBody))
+
+			exchanges := read()
+			Ω(exchanges).Should(HaveLen(1))
+			e := exchanges[0]
+			Ω(e.Resource).Should(Equal("order"))
+			Ω(e.Action).Should(Equal("create"))
+			Ω(e.Request.Method).Should(Equal("POST"))
+			Ω(e.Request.URL).Should(Equal("/orders?page=2&token=%5BREDACTED%5D"))
+			Ω(e.Request.Header.Get("Authorization")).Should(Equal(capture.Redacted))
+			Ω(e.Request.Header.Get("X-Api-Key")).Should(Equal(capture.Redacted))
+			Ω(e.Request.Header.Get("X-Trace")).Should(Equal("trace"))
+			Ω(e.Request.Body).Should(Equal(map[string]interface{}{
+				"items": []interface{}{map[string]interface{}{"holder": "joe", "number": capture.Redacted}},
+				"note":  "fast",
+			}))
+			Ω(e.Response.Status).Should(Equal(http.StatusCreated))
+			Ω(e.Response.Body).Should(Equal(map[string]interface{}{"id": float64(1), "secret": capture.Redacted}))
+		})
+
+		It("omits the non JSON response bodies that have sensitive attributes", func() {
+			respBody = "secret=s3cr3t"
+			_, err := send("/orders")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(read()[0].Response.Body).Should(BeNil())
+		})
+
+		It("omits the response bodies larger than the maximum size", func() {
+			opts.MaxBodySize = 4
+			_, err := send("/orders")
+			Ω(err).ShouldNot(HaveOccurred())
+			e := read()[0]
+			Ω(e.Response.Body).Should(BeNil())
+			Ω(e.Response.Truncated).Should(BeTrue())
+		})
+
+		It("records the action errors", func() {
+			handlerErr = errors.New("boom")
+			_, err := send("/orders")
+			Ω(err).Should(Equal(handlerErr))
+			e := read()[0]
+			Ω(e.Error).Should(Equal("boom"))
+			Ω(e.Response.Status).Should(Equal(0))
+		})
+	})
+})
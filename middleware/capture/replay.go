@@ -0,0 +1,84 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/client"
+)
+
+// Replay re-issues the captured request against the service listening on the given scheme and
+// host using doer, typically a goa client configured for the target environment. See
+// ReplayRequest for how the request is built.
+func Replay(ctx context.Context, doer client.Doer, scheme, host string, e *Exchange) (*http.Response, error) {
+	req, err := ReplayRequest(scheme, host, e)
+	if err != nil {
+		return nil, err
+	}
+	return doer.Do(ctx, req)
+}
+
+// ReplayRequest builds the request that re-issues the captured request against the service
+// listening on the given scheme and host. The masked headers and query string parameters are not
+// set so that the caller may provide the values valid in the target environment, e.g. by signing
+// the request. The request body is the JSON encoding of the captured payload snapshot.
+func ReplayRequest(scheme, host string, e *Exchange) (*http.Request, error) {
+	if e.Request == nil {
+		return nil, fmt.Errorf("exchange of %s %s has no captured request", e.Resource, e.Action)
+	}
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid captured URL %#v: %s", e.Request.URL, err)
+	}
+	u.Scheme = scheme
+	u.Host = host
+	if q := u.Query(); len(q) > 0 {
+		for k, vals := range q {
+			var kept []string
+			for _, v := range vals {
+				if v != Redacted {
+					kept = append(kept, v)
+				}
+			}
+			if len(kept) == 0 {
+				q.Del(k)
+			} else {
+				q[k] = kept
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	var body io.Reader
+	if e.Request.Body != nil {
+		b, err := json.Marshal(e.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(e.Request.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range e.Request.Header {
+		switch k {
+		case "Content-Length", "Content-Encoding":
+			continue
+		}
+		for _, v := range vals {
+			if v != Redacted {
+				req.Header.Add(k, v)
+			}
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
@@ -0,0 +1,66 @@
+package capture_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/client"
+	"github.com/goadesign/goa/middleware/capture"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay", func() {
+	var received *http.Request
+	var body string
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			b, _ := ioutil.ReadAll(req.Body)
+			received = req
+			body = string(b)
+			rw.WriteHeader(http.StatusCreated)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("re-issues the captured request without the masked values", func() {
+		e := &capture.Exchange{
+			Resource: "order",
+			Action:   "create",
+			Request: &capture.Request{
+				Method: "POST",
+				URL:    "/orders?page=2&token=%5BREDACTED%5D",
+				Header: http.Header{
+					"Authorization":  {capture.Redacted},
+					"X-Trace":        {"trace"},
+					"Content-Length": {"42"},
+				},
+				Body: map[string]interface{}{"note": "fast"},
+			},
+		}
+		host := strings.TrimPrefix(server.URL, "http://")
+		resp, err := capture.Replay(context.Background(), client.HTTPClientDoer(http.DefaultClient), "http", host, e)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(resp.StatusCode).Should(Equal(http.StatusCreated))
+		Ω(received.Method).Should(Equal("POST"))
+		Ω(received.URL.String()).Should(Equal("/orders?page=2"))
+		Ω(received.Header.Get("Authorization")).Should(BeEmpty())
+		Ω(received.Header.Get("X-Trace")).Should(Equal("trace"))
+		Ω(received.Header.Get("Content-Type")).Should(Equal("application/json"))
+		Ω(body).Should(MatchJSON(`{"note":"fast"}`))
+	})
+
+	It("fails when the exchange has no request", func() {
+		_, err := capture.Replay(context.Background(), nil, "http", "localhost", &capture.Exchange{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,9 @@
+/*
+Package compress provides a middleware that compresses the response bodies using the encoding
+negotiated with the client via the Accept-Encoding header. The gzip and deflate encodings are
+built-in, other encodings such as brotli ("br") can be added with RegisterEncoder.
+
+Responses smaller than the configured minimum size, responses whose content type is not allowed
+and responses that already have a Content-Encoding header are sent as is.
+*/
+package compress
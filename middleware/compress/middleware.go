@@ -0,0 +1,238 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+	headerContentType     = "Content-Type"
+	headerVary            = "Vary"
+)
+
+type (
+	// EncoderFactory creates a writer that compresses the data written to it and writes the
+	// result to w. Closing the writer flushes the compressed data.
+	EncoderFactory func(w io.Writer) (io.WriteCloser, error)
+
+	// Options configures the compression middleware.
+	Options struct {
+		// Encodings lists the names of the supported encodings in order of preference,
+		// defaults to "gzip" and "deflate".
+		Encodings []string
+		// MinSize is the minimum size in bytes of the response bodies that get compressed.
+		MinSize int
+		// ContentTypes lists the media types of the response bodies that get compressed,
+		// all bodies are compressed if empty. A type may end with "/*" to allow all the
+		// subtypes.
+		ContentTypes []string
+	}
+
+	// compressWriter is the response writer that compresses the response body. It buffers the
+	// body until MinSize bytes are written to decide whether to compress it.
+	compressWriter struct {
+		http.ResponseWriter
+		opts     *Options
+		encoding string
+		factory  EncoderFactory
+		buf      []byte
+		status   int
+		decided  bool
+		enc      io.WriteCloser
+	}
+)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]EncoderFactory{
+		"gzip": func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		},
+		"deflate": func(w io.Writer) (io.WriteCloser, error) {
+			return flate.NewWriter(w, flate.DefaultCompression)
+		},
+	}
+)
+
+// RegisterEncoder registers the factory of the encoder with the given name, e.g. "br". It
+// replaces the factory previously registered with the same name if any.
+func RegisterEncoder(name string, factory EncoderFactory) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[name] = factory
+}
+
+// Middleware returns a middleware that compresses the response bodies according to the given
+// options.
+func Middleware(opts Options) goa.Middleware {
+	if len(opts.Encodings) == 0 {
+		opts.Encodings = []string{"gzip", "deflate"}
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			encoding, factory := negotiate(req.Header.Get(headerAcceptEncoding), opts.Encodings)
+			resp := goa.ContextResponse(ctx)
+			if factory == nil || resp == nil {
+				return h(ctx, rw, req)
+			}
+			w := resp.SwitchWriter(nil)
+			cw := &compressWriter{ResponseWriter: w, opts: &opts, encoding: encoding, factory: factory}
+			resp.SwitchWriter(cw)
+			err := h(ctx, rw, req)
+			cerr := cw.Close()
+			resp.SwitchWriter(w)
+			if err != nil {
+				return err
+			}
+			return cerr
+		}
+	}
+}
+
+// WriteHeader records the status until the middleware decides whether to compress the body.
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+// Write buffers the body until it reaches the minimum size then compresses it if allowed.
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.opts.MinSize {
+		return len(b), nil
+	}
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Close sends the buffered body if any and flushes the compressed data.
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+	return nil
+}
+
+// decide sets the response headers and starts compressing if the buffered body is big enough
+// and of an allowed content type, it then sends the buffered status and body.
+func (w *compressWriter) decide() error {
+	w.decided = true
+	h := w.Header()
+	h.Add(headerVary, headerAcceptEncoding)
+	if len(w.buf) > 0 && len(w.buf) >= w.opts.MinSize && h.Get(headerContentEncoding) == "" {
+		if h.Get(headerContentType) == "" {
+			h.Set(headerContentType, http.DetectContentType(w.buf))
+		}
+		if w.allowed(h.Get(headerContentType)) {
+			enc, err := w.factory(w.ResponseWriter)
+			if err != nil {
+				return err
+			}
+			w.enc = enc
+			h.Set(headerContentEncoding, w.encoding)
+			h.Del(headerContentLength)
+		}
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// allowed returns true if the body of the given content type may be compressed.
+func (w *compressWriter) allowed(contentType string) bool {
+	if len(w.opts.ContentTypes) == 0 {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range w.opts.ContentTypes {
+		if t == mt || strings.HasSuffix(t, "/*") && strings.HasPrefix(mt, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiate returns the name and factory of the supported encoding preferred by the client
+// given the value of its Accept-Encoding header. Encodings with the same quality are ordered by
+// server preference. It returns a nil factory if no supported encoding is acceptable.
+func negotiate(accept string, supported []string) (string, EncoderFactory) {
+	if accept == "" {
+		return "", nil
+	}
+	quality := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		quality[name] = q
+	}
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	var best string
+	var bestQ float64
+	for _, s := range supported {
+		q, ok := quality[s]
+		if !ok {
+			q = quality["*"]
+		}
+		if _, ok := encoders[s]; ok && q > bestQ {
+			best, bestQ = s, q
+		}
+	}
+	if best == "" {
+		return "", nil
+	}
+	return best, encoders[best]
+}
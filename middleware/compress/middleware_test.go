@@ -0,0 +1,153 @@
+package compress_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/compress"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	var ctx context.Context
+	var req *http.Request
+	var rw *httptest.ResponseRecorder
+	var opts compress.Options
+	var body string
+	var contentType string
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("GET", "/foo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = httptest.NewRecorder()
+		ctx = goa.NewContext(nil, rw, req, nil)
+		opts = compress.Options{}
+		body = strings.Repeat("compress me! ", 10)
+		contentType = "text/plain"
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set("Content-Type", contentType)
+			rw.WriteHeader(http.StatusOK)
+			rw.Write([]byte(body))
+			return nil
+		}
+		err := compress.Middleware(opts)(h)(ctx, goa.ContextResponse(ctx), req)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("with a client that does not accept compressed bodies", func() {
+		It("does not compress the body", func() {
+			Ω(rw.Code).Should(Equal(http.StatusOK))
+			Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+			Ω(rw.Body.String()).Should(Equal(body))
+		})
+	})
+
+	Context("with a client that accepts gzip", func() {
+		BeforeEach(func() {
+			req.Header.Set("Accept-Encoding", "gzip")
+		})
+
+		It("compresses the body", func() {
+			Ω(rw.Code).Should(Equal(http.StatusOK))
+			Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+			Ω(rw.Header().Get("Vary")).Should(Equal("Accept-Encoding"))
+			gzr, err := gzip.NewReader(rw.Body)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadAll(gzr)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(body))
+		})
+
+		Context("and a body smaller than the minimum size", func() {
+			BeforeEach(func() {
+				opts.MinSize = len(body) + 1
+			})
+
+			It("does not compress the body", func() {
+				Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+				Ω(rw.Header().Get("Vary")).Should(Equal("Accept-Encoding"))
+				Ω(rw.Body.String()).Should(Equal(body))
+			})
+		})
+
+		Context("and a content type that is not allowed", func() {
+			BeforeEach(func() {
+				opts.ContentTypes = []string{"application/json"}
+			})
+
+			It("does not compress the body", func() {
+				Ω(rw.Header().Get("Content-Encoding")).Should(BeEmpty())
+				Ω(rw.Body.String()).Should(Equal(body))
+			})
+		})
+
+		Context("and a content type allowed by a wildcard", func() {
+			BeforeEach(func() {
+				opts.ContentTypes = []string{"application/json", "text/*"}
+				contentType = "text/plain; charset=utf-8"
+			})
+
+			It("compresses the body", func() {
+				Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+			})
+		})
+	})
+
+	Context("with a client that prefers deflate", func() {
+		BeforeEach(func() {
+			req.Header.Set("Accept-Encoding", "gzip;q=0.5, deflate")
+		})
+
+		It("compresses the body with deflate", func() {
+			Ω(rw.Header().Get("Content-Encoding")).Should(Equal("deflate"))
+			b, err := ioutil.ReadAll(flate.NewReader(rw.Body))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(body))
+		})
+	})
+
+	Context("with a client that accepts any encoding", func() {
+		BeforeEach(func() {
+			req.Header.Set("Accept-Encoding", "*")
+		})
+
+		It("uses the server preferred encoding", func() {
+			Ω(rw.Header().Get("Content-Encoding")).Should(Equal("gzip"))
+		})
+	})
+
+	Context("with a registered encoder", func() {
+		BeforeEach(func() {
+			compress.RegisterEncoder("test", func(w io.Writer) (io.WriteCloser, error) {
+				return nopCloser{w}, nil
+			})
+			opts.Encodings = []string{"test", "gzip"}
+			req.Header.Set("Accept-Encoding", "gzip, test")
+		})
+
+		It("uses the registered encoder", func() {
+			Ω(rw.Header().Get("Content-Encoding")).Should(Equal("test"))
+			Ω(rw.Body.String()).Should(Equal(body))
+		})
+	})
+})
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa"
+
+	"golang.org/x/net/context"
+)
+
+// ConcurrencyLimit limits the number of requests handled concurrently by the wrapped handler to
+// max. Requests exceeding the limit wait for a slot to free up unless shed is true in which case
+// they are rejected right away with a goa.ErrServiceUnavailable error. Waiting requests give up
+// with the same error after maxWait, or when the client disconnects, a maxWait of 0 means no
+// bound. The Retry-After header of the rejected requests responses is set to retryAfter seconds.
+func ConcurrencyLimit(max int, shed bool, retryAfter int, maxWait time.Duration) goa.Middleware {
+	sem := make(chan struct{}, max)
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			reject := func() error {
+				rw.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				return goa.ErrServiceUnavailable("too many concurrent requests", "max", max)
+			}
+			if shed {
+				select {
+				case sem <- struct{}{}:
+				default:
+					return reject()
+				}
+			} else {
+				var expired <-chan time.Time
+				if maxWait > 0 {
+					timer := time.NewTimer(maxWait)
+					defer timer.Stop()
+					expired = timer.C
+				}
+				select {
+				case sem <- struct{}{}:
+				case <-expired:
+					return reject()
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-req.Context().Done():
+					return req.Context().Err()
+				}
+			}
+			defer func() { <-sem }()
+			return h(ctx, rw, req)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package middleware_test
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConcurrencyLimit", func() {
+	var shed bool
+	var maxWait time.Duration
+	var release chan struct{}
+	var started chan struct{}
+	var limited goa.Handler
+
+	BeforeEach(func() {
+		shed = false
+		maxWait = 0
+		release = make(chan struct{})
+		started = make(chan struct{}, 2)
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}
+		limited = middleware.ConcurrencyLimit(1, shed, 5, maxWait)(h)
+	})
+
+	callRequest := func(ctx context.Context, req *http.Request) (*testResponseWriter, chan error) {
+		rw := newTestResponseWriter()
+		done := make(chan error, 1)
+		go func() { done <- limited(ctx, rw, req) }()
+		return rw, done
+	}
+
+	call := func(ctx context.Context) (*testResponseWriter, chan error) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		return callRequest(ctx, req)
+	}
+
+	Context("with load shedding", func() {
+		BeforeEach(func() {
+			shed = true
+		})
+
+		It("rejects the requests exceeding the budget", func() {
+			_, first := call(context.Background())
+			Eventually(started).Should(Receive())
+			rw, second := call(context.Background())
+			var err error
+			Eventually(second).Should(Receive(&err))
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(503))
+			Ω(rw.Header().Get("Retry-After")).Should(Equal("5"))
+			close(release)
+			Eventually(first).Should(Receive(BeNil()))
+		})
+	})
+
+	Context("without load shedding", func() {
+		It("makes the requests exceeding the budget wait", func() {
+			_, first := call(context.Background())
+			Eventually(started).Should(Receive())
+			_, second := call(context.Background())
+			Consistently(started, 50*time.Millisecond).ShouldNot(Receive())
+			close(release)
+			Eventually(first).Should(Receive(BeNil()))
+			Eventually(second).Should(Receive(BeNil()))
+		})
+
+		It("stops waiting when the request context is done", func() {
+			_, first := call(context.Background())
+			Eventually(started).Should(Receive())
+			ctx, cancel := context.WithCancel(context.Background())
+			_, second := call(ctx)
+			cancel()
+			Eventually(second).Should(Receive(Equal(context.Canceled)))
+			close(release)
+			Eventually(first).Should(Receive(BeNil()))
+		})
+
+		It("stops waiting when the client disconnects", func() {
+			_, first := call(context.Background())
+			Eventually(started).Should(Receive())
+			reqCtx, cancel := context.WithCancel(context.Background())
+			req, _ := http.NewRequest("GET", "/", nil)
+			_, second := callRequest(context.Background(), req.WithContext(reqCtx))
+			cancel()
+			Eventually(second).Should(Receive(Equal(context.Canceled)))
+			close(release)
+			Eventually(first).Should(Receive(BeNil()))
+		})
+
+		Context("with a bounded wait", func() {
+			BeforeEach(func() {
+				maxWait = 50 * time.Millisecond
+			})
+
+			It("rejects the requests still waiting when the wait expires", func() {
+				_, first := call(context.Background())
+				Eventually(started).Should(Receive())
+				rw, second := call(context.Background())
+				var err error
+				Eventually(second).Should(Receive(&err))
+				Ω(err).Should(HaveOccurred())
+				Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(503))
+				Ω(rw.Header().Get("Retry-After")).Should(Equal("5"))
+				Ω(started).ShouldNot(Receive())
+				close(release)
+				Eventually(first).Should(Receive(BeNil()))
+			})
+		})
+	})
+})
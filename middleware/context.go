@@ -6,3 +6,7 @@ type middlewareKey int
 
 // ReqIDKey is the context key used by the RequestID middleware to store the request ID value.
 const reqIDKey middlewareKey = 1
+
+// correlationKey is the context key used by the CorrelationID middleware to record that the
+// request ID must be added to the error responses.
+const correlationKey middlewareKey = 2
@@ -50,6 +50,11 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 					}
 				}
 			}
+			if ctx.Value(correlationKey) != nil {
+				if err, ok := respBody.(error); ok {
+					respBody = ErrorWithRequestID(ctx, err)
+				}
+			}
 			return service.Send(ctx, status, respBody)
 		}
 	}
@@ -0,0 +1,11 @@
+/*
+Package feature provides a middleware that gates actions behind feature flags so that unreleased
+endpoints can ship dark.
+
+The flags are looked up in the provider registered with UseProvider prior to mounting the
+controllers. The middleware responds with the status given in its options, typically 404 to hide
+the endpoint or 403, when the flag is off, when the provider fails or when no provider is
+registered. NewStaticProvider returns a provider that keeps the flags in memory, adapters to
+external flag services implement Provider or use ProviderFunc.
+*/
+package feature
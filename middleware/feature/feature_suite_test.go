@@ -0,0 +1,13 @@
+package feature_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFeature(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Feature Suite")
+}
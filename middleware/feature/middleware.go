@@ -0,0 +1,63 @@
+package feature
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// Options describes the flag gating an action and the response sent when it is off.
+	Options struct {
+		// Flag is the name of the feature flag.
+		Flag string
+		// Status is the status of the response sent when the flag is off, typically 404 or
+		// 403.
+		Status int
+		// Error is true if the response renders the error media type, false if it has no
+		// body.
+		Error bool
+	}
+
+	// providerKey is the key used to store the flag provider in the service context.
+	providerKey struct{}
+)
+
+// UseProvider registers the provider used to look up the feature flags. UseProvider must be called
+// before the controllers are created.
+func UseProvider(service *goa.Service, p Provider) {
+	service.Context = context.WithValue(service.Context, providerKey{}, p)
+}
+
+// Gate returns a middleware that responds to the requests with the status given in opts unless
+// the flag is on. The flag is considered off if no provider is registered or if the provider
+// fails, the provider errors are logged. The response does not mention the flag so that gated
+// endpoints are indistinguishable from missing or forbidden ones.
+func Gate(opts Options) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if p, ok := ctx.Value(providerKey{}).(Provider); ok {
+				on, err := p.Enabled(ctx, opts.Flag)
+				if err != nil {
+					goa.LogError(ctx, "feature flag lookup failed", "flag", opts.Flag, "err", err)
+				} else if on {
+					return h(ctx, rw, req)
+				}
+			}
+			if !opts.Error {
+				rw.WriteHeader(opts.Status)
+				return nil
+			}
+			switch opts.Status {
+			case http.StatusNotFound:
+				return goa.ErrNotFound(http.StatusText(opts.Status))
+			case http.StatusForbidden:
+				return goa.ErrForbidden(http.StatusText(opts.Status))
+			default:
+				return goa.NewErrorClass("feature_disabled", opts.Status)(http.StatusText(opts.Status))
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package feature_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/feature"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Gate", func() {
+	var service *goa.Service
+	var provider feature.Provider
+	var opts feature.Options
+	var called bool
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		provider = nil
+		opts = feature.Options{Flag: "new-checkout", Status: http.StatusNotFound}
+		called = false
+	})
+
+	send := func() (*httptest.ResponseRecorder, error) {
+		if provider != nil {
+			feature.UseProvider(service, provider)
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			rw.WriteHeader(http.StatusOK)
+			return nil
+		}
+		req, _ := http.NewRequest("POST", "/checkout", nil)
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(service.Context, rw, req, nil)
+		err := feature.Gate(opts)(h)(ctx, rw, req)
+		return rw, err
+	}
+
+	It("responds with the given status when no provider is registered", func() {
+		rw, err := send()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(called).Should(BeFalse())
+		Ω(rw.Code).Should(Equal(http.StatusNotFound))
+	})
+
+	Context("with a provider", func() {
+		var static *feature.StaticProvider
+
+		BeforeEach(func() {
+			static = feature.NewStaticProvider("new-checkout")
+			provider = static
+		})
+
+		It("calls the handler when the flag is on", func() {
+			rw, err := send()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(called).Should(BeTrue())
+			Ω(rw.Code).Should(Equal(http.StatusOK))
+		})
+
+		It("responds with the given status when the flag is off", func() {
+			static.Disable("new-checkout")
+			rw, err := send()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(called).Should(BeFalse())
+			Ω(rw.Code).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("with a failing provider", func() {
+		BeforeEach(func() {
+			provider = feature.ProviderFunc(func(context.Context, string) (bool, error) {
+				return true, errors.New("unavailable")
+			})
+		})
+
+		It("considers the flag off", func() {
+			rw, err := send()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(called).Should(BeFalse())
+			Ω(rw.Code).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("with an error response", func() {
+		BeforeEach(func() {
+			opts.Status = http.StatusForbidden
+			opts.Error = true
+		})
+
+		It("returns an error with the given status", func() {
+			_, err := send()
+			Ω(err).Should(HaveOccurred())
+			serr, ok := err.(goa.ServiceError)
+			Ω(ok).Should(BeTrue())
+			Ω(serr.ResponseStatus()).Should(Equal(http.StatusForbidden))
+			Ω(err.Error()).ShouldNot(ContainSubstring("new-checkout"))
+			Ω(called).Should(BeFalse())
+		})
+	})
+})
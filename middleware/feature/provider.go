@@ -0,0 +1,62 @@
+package feature
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+type (
+	// Provider is the interface implemented by the feature flag providers.
+	Provider interface {
+		// Enabled returns true if the flag with the given name is on for the request with
+		// the given context.
+		Enabled(ctx context.Context, flag string) (bool, error)
+	}
+
+	// ProviderFunc is an adapter that allows using a function as a Provider.
+	ProviderFunc func(ctx context.Context, flag string) (bool, error)
+
+	// StaticProvider is a Provider that keeps the flags in memory.
+	StaticProvider struct {
+		mu    sync.RWMutex
+		flags map[string]bool
+	}
+)
+
+// Enabled calls f(ctx, flag).
+func (f ProviderFunc) Enabled(ctx context.Context, flag string) (bool, error) {
+	return f(ctx, flag)
+}
+
+// NewStaticProvider returns a provider with the given flags on, all other flags are off.
+func NewStaticProvider(flags ...string) *StaticProvider {
+	p := &StaticProvider{flags: make(map[string]bool)}
+	p.Enable(flags...)
+	return p
+}
+
+// Enable turns the given flags on.
+func (p *StaticProvider) Enable(flags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, f := range flags {
+		p.flags[f] = true
+	}
+}
+
+// Disable turns the given flags off.
+func (p *StaticProvider) Disable(flags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, f := range flags {
+		delete(p.flags, f)
+	}
+}
+
+// Enabled returns true if the flag is on.
+func (p *StaticProvider) Enabled(_ context.Context, flag string) (bool, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.flags[flag], nil
+}
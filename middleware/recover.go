@@ -11,30 +11,80 @@ import (
 	"golang.org/x/net/context"
 )
 
+// CrashReporter is the type of functions notified of the panics recovered by RecoverResponse. p
+// is the value given to panic and stack the stack trace of the panicking goroutine.
+type CrashReporter func(ctx context.Context, p interface{}, stack string)
+
 // Recover is a middleware that recovers panics and maps them to errors.
 func Recover() goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
 			defer func() {
 				if r := recover(); r != nil {
-					var msg string
-					switch x := r.(type) {
-					case string:
-						msg = fmt.Sprintf("panic: %s", x)
-					case error:
-						msg = fmt.Sprintf("panic: %s", x)
-					default:
-						msg = "unknown panic"
-					}
-					const size = 64 << 10 // 64KB
-					buf := make([]byte, size)
-					buf = buf[:runtime.Stack(buf, false)]
-					lines := strings.Split(string(buf), "\n")
-					stack := lines[3:]
-					err = fmt.Errorf("%s\n%s", msg, strings.Join(stack, "\n"))
+					_, err = panicError(r)
 				}
 			}()
 			return h(ctx, rw, req)
 		}
 	}
 }
+
+// RecoverResponse is a middleware that recovers panics and sends a 500 response instead of
+// returning the panic as an error. The response body is an internal error that includes the
+// request ID if any encoded with the given content type, the response has no body if contentType
+// is empty. The panic message and stack trace are logged and never sent to the client. reporter is
+// notified of each panic if not nil. The panic is returned as an error if the handler had already
+// started writing the response.
+func RecoverResponse(service *goa.Service, contentType string, reporter CrashReporter) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				stack, perr := panicError(r)
+				goa.LogError(ctx, "panic", "err", perr)
+				if reporter != nil {
+					reporter(ctx, r, stack)
+				}
+				resp := goa.ContextResponse(ctx)
+				if resp == nil || resp.Written() {
+					err = perr
+					return
+				}
+				if contentType == "" {
+					resp.WriteHeader(http.StatusInternalServerError)
+					err = nil
+					return
+				}
+				e := goa.ErrInternal(http.StatusText(http.StatusInternalServerError))
+				resp.ErrorCode = e.(goa.ServiceError).Token()
+				rw.Header().Set("Content-Type", contentType)
+				err = service.Send(ctx, http.StatusInternalServerError, ErrorWithRequestID(ctx, e))
+			}()
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// panicError returns the stack trace of the panicking goroutine and the error built from the value
+// given to panic.
+func panicError(r interface{}) (string, error) {
+	var msg string
+	switch x := r.(type) {
+	case string:
+		msg = fmt.Sprintf("panic: %s", x)
+	case error:
+		msg = fmt.Sprintf("panic: %s", x)
+	default:
+		msg = "unknown panic"
+	}
+	const size = 64 << 10 // 64KB
+	buf := make([]byte, size)
+	buf = buf[:runtime.Stack(buf, false)]
+	lines := strings.Split(string(buf), "\n")
+	// Skip the goroutine header and the panicError and deferred function frames.
+	stack := strings.Join(lines[5:], "\n")
+	return stack, fmt.Errorf("%s\n%s", msg, stack)
+}
@@ -1,6 +1,7 @@
 package middleware_test
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 
@@ -60,3 +61,84 @@ var _ = Describe("Recover", func() {
 		})
 	})
 })
+
+var _ = Describe("RecoverResponse", func() {
+	var service *goa.Service
+	var h goa.Handler
+	var contentType string
+	var reporter middleware.CrashReporter
+	var rw *testResponseWriter
+	var err error
+
+	BeforeEach(func() {
+		service = newService(nil)
+		h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			panic("boom")
+		}
+		contentType = goa.ErrorMediaIdentifier
+		reporter = nil
+	})
+
+	JustBeforeEach(func() {
+		rw = newTestResponseWriter()
+		req, e := http.NewRequest("GET", "/foo", nil)
+		Ω(e).ShouldNot(HaveOccurred())
+		ctx := newContext(service, rw, req, nil)
+		err = middleware.RecoverResponse(service, contentType, reporter)(h)(ctx, rw, req)
+	})
+
+	It("sends an internal error response", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Status).Should(Equal(500))
+		Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ErrorMediaIdentifier}))
+		var decoded errorResponse
+		Ω(service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")).Should(Succeed())
+		Ω(decoded.Code).Should(Equal("internal"))
+		Ω(decoded.Detail).Should(Equal("Internal Server Error"))
+		Ω(string(rw.Body)).ShouldNot(ContainSubstring("boom"))
+	})
+
+	Context("with no content type", func() {
+		BeforeEach(func() {
+			contentType = ""
+		})
+
+		It("sends an empty internal error response", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Status).Should(Equal(500))
+			Ω(rw.Body).Should(BeEmpty())
+		})
+	})
+
+	Context("with a crash reporter", func() {
+		var reported interface{}
+		var stack string
+
+		BeforeEach(func() {
+			reported, stack = nil, ""
+			reporter = func(ctx context.Context, p interface{}, s string) {
+				reported, stack = p, s
+			}
+		})
+
+		It("notifies the reporter", func() {
+			Ω(reported).Should(Equal("boom"))
+			Ω(stack).ShouldNot(BeEmpty())
+		})
+	})
+
+	Context("with a handler that panics after writing the response", func() {
+		BeforeEach(func() {
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				goa.ContextResponse(ctx).WriteHeader(200)
+				panic("boom")
+			}
+		})
+
+		It("returns the panic as an error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(HavePrefix("panic: boom\n"))
+			Ω(rw.Status).Should(Equal(200))
+		})
+	})
+})
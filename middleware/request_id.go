@@ -72,6 +72,37 @@ func RequestID() goa.Middleware {
 	return RequestIDWithHeader(RequestIDHeader)
 }
 
+// CorrelationID behaves like RequestIDWithHeader and also writes the request ID to the
+// requestIDHeader response header. The ErrorHandler middleware adds the request ID to the
+// "request_id" meta of the error responses of the requests handled by this middleware.
+func CorrelationID(requestIDHeader string) goa.Middleware {
+	reqID := RequestIDWithHeader(requestIDHeader)
+	return func(h goa.Handler) goa.Handler {
+		return reqID(func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set(requestIDHeader, ContextRequestID(ctx))
+			ctx = context.WithValue(ctx, correlationKey, true)
+			return h(ctx, rw, req)
+		})
+	}
+}
+
+// ErrorWithRequestID returns a copy of err whose meta includes the request ID stored in ctx under
+// the "request_id" key if err is a *goa.ErrorResponse and ctx holds a request ID. It returns err
+// otherwise.
+func ErrorWithRequestID(ctx context.Context, err error) error {
+	e, ok := err.(*goa.ErrorResponse)
+	if !ok {
+		return err
+	}
+	id := ContextRequestID(ctx)
+	if id == "" {
+		return err
+	}
+	cp := *e
+	cp.Meta = append(append([]map[string]interface{}{}, e.Meta...), map[string]interface{}{"request_id": id})
+	return &cp
+}
+
 // ContextRequestID extracts the Request ID from the context.
 func ContextRequestID(ctx context.Context) (reqID string) {
 	id := ctx.Value(reqIDKey)
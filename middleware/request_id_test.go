@@ -83,6 +83,51 @@ var _ = Describe("RequestID", func() {
 		Ω(middleware.ContextRequestID(newCtx)).Should(Equal(string(original)))
 	})
 
+	Context("CorrelationID", func() {
+		BeforeEach(func() {
+			rw = newTestResponseWriter()
+			req.Header.Set("X-Correlation-Id", "correlation")
+			ctx = newContext(service, rw, req, params)
+		})
+
+		It("writes the request ID to the response header", func() {
+			var newCtx context.Context
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				newCtx = ctx
+				return service.Send(ctx, 200, "ok")
+			}
+			rg := middleware.CorrelationID("X-Correlation-Id")(h)
+			Ω(rg(ctx, rw, req)).ShouldNot(HaveOccurred())
+			Ω(middleware.ContextRequestID(newCtx)).Should(Equal("correlation"))
+			Ω(rw.Header().Get("X-Correlation-Id")).Should(Equal("correlation"))
+		})
+
+		It("adds the request ID to the error responses", func() {
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return goa.ErrBadRequest("boom")
+			}
+			rg := middleware.CorrelationID("X-Correlation-Id")(middleware.ErrorHandler(service, true)(h))
+			Ω(rg(ctx, rw, req)).ShouldNot(HaveOccurred())
+			body := string(rw.(*testResponseWriter).Body)
+			Ω(body).Should(ContainSubstring(`"meta":[{"request_id":"correlation"}]`))
+		})
+	})
+
+	Context("ErrorWithRequestID", func() {
+		It("does not modify the original error", func() {
+			var newCtx context.Context
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				newCtx = ctx
+				return nil
+			}
+			rg := middleware.RequestID()(h)
+			Ω(rg(ctx, rw, req)).ShouldNot(HaveOccurred())
+			orig := goa.ErrBadRequest("boom").(*goa.ErrorResponse)
+			err := middleware.ErrorWithRequestID(newCtx, orig)
+			Ω(err.(*goa.ErrorResponse).Meta).Should(Equal([]map[string]interface{}{{"request_id": reqID}}))
+			Ω(orig.Meta).Should(BeEmpty())
+		})
+	})
 })
 
 func makeRequestID(length int) string {
@@ -0,0 +1,120 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goadesign/goa"
+	"golang.org/x/net/context"
+)
+
+type (
+	// Key describes an API key issued to a client.
+	Key struct {
+		// ID identifies the key, it is safe to log and to display.
+		ID string
+		// Value is the secret sent by clients, it is only set when the key is created.
+		Value string
+		// Owner identifies the principal the key was issued to.
+		Owner string
+		// Scopes lists the security scopes granted to the key.
+		Scopes []string
+		// Roles lists the roles granted to the key.
+		Roles []string
+		// CreatedAt is the key creation time.
+		CreatedAt time.Time
+		// LastUsedAt is the time the key was last used to authenticate a request.
+		LastUsedAt *time.Time
+		// Revoked is true if the key may no longer be used.
+		Revoked bool
+	}
+
+	// KeyStore is the interface used by the middleware to validate API keys.
+	KeyStore interface {
+		// Lookup returns the key with the given secret value or nil if there is none.
+		Lookup(ctx context.Context, value string) (*Key, error)
+		// Touch records that the key with the given ID was used at the given time.
+		Touch(ctx context.Context, id string, at time.Time) error
+		// Revoke revokes the key with the given ID.
+		Revoke(ctx context.Context, id string) error
+	}
+
+	// KeyManager extends KeyStore with the operations needed to administer the keys.
+	KeyManager interface {
+		KeyStore
+		// Create issues a new key, the returned key Value field contains the secret.
+		Create(ctx context.Context, owner string, scopes, roles []string) (*Key, error)
+		// List returns all the keys.
+		List(ctx context.Context) ([]*Key, error)
+		// Get returns the key with the given ID or nil if there is none.
+		Get(ctx context.Context, id string) (*Key, error)
+	}
+)
+
+// ErrAPIKeyError is the error returned by the middleware when the API key is missing, unknown or
+// revoked.
+var ErrAPIKeyError = goa.NewErrorClass("api_key_security_error", 401)
+
+// New returns a middleware to be used with the APIKeySecurity DSL definitions of goa. The
+// middleware reads the key from the header or querystring value described by the scheme, looks it
+// up in the store and records its use. Requests using unknown or revoked keys are rejected.
+//
+// The key is stored in the request context where ContextKey retrieves it. The key owner, scopes
+// and roles are also recorded as the "sub", "scopes" and "roles" claims so that the generated code
+// may check the scopes and roles required by the actions (see goa.Authorize).
+func New(store KeyStore, scheme *goa.APIKeySecurity) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			var value string
+			if scheme.In == goa.LocQuery {
+				value = req.URL.Query().Get(scheme.Name)
+			} else {
+				value = req.Header.Get(scheme.Name)
+			}
+			if value == "" {
+				return ErrAPIKeyError(fmt.Sprintf("missing API key %q", scheme.Name))
+			}
+			key, err := store.Lookup(ctx, value)
+			if err != nil {
+				return err
+			}
+			if key == nil {
+				return ErrAPIKeyError("invalid API key")
+			}
+			if key.Revoked {
+				return ErrAPIKeyError("revoked API key", "id", key.ID)
+			}
+			if err := store.Touch(ctx, key.ID, time.Now()); err != nil {
+				goa.LogError(ctx, "failed to record API key use", "id", key.ID, "err", err)
+			}
+			ctx = WithKey(ctx, key)
+			ctx = goa.WithClaims(ctx, map[string]interface{}{
+				"sub":    key.Owner,
+				"scopes": key.Scopes,
+				"roles":  key.Roles,
+			})
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+type contextKey int
+
+const (
+	apiKeyKey contextKey = iota + 1
+)
+
+// WithKey creates a child context containing the given API key.
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, apiKeyKey, k)
+}
+
+// ContextKey retrieves the API key from a context that went through the middleware.
+func ContextKey(ctx context.Context) *Key {
+	k, ok := ctx.Value(apiKeyKey).(*Key)
+	if !ok {
+		return nil
+	}
+	return k
+}
@@ -0,0 +1,107 @@
+package apikey_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/security/apikey"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Middleware", func() {
+	var store *apikey.MemoryStore
+	var key *apikey.Key
+	var scheme *goa.APIKeySecurity
+	var request *http.Request
+	var fetchedKey *apikey.Key
+	var fetchedClaims map[string]interface{}
+	var dispatchResult error
+
+	BeforeEach(func() {
+		store = apikey.NewMemoryStore()
+		var err error
+		key, err = store.Create(context.Background(), "alice", []string{"read"}, []string{"admin"})
+		Ω(err).ShouldNot(HaveOccurred())
+		scheme = &goa.APIKeySecurity{In: goa.LocHeader, Name: "X-Api-Key"}
+		request, _ = http.NewRequest("GET", "http://example.com/", nil)
+		request.Header.Set("X-Api-Key", key.Value)
+		fetchedKey = nil
+		fetchedClaims = nil
+	})
+
+	JustBeforeEach(func() {
+		handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			fetchedKey = apikey.ContextKey(ctx)
+			fetchedClaims = goa.ContextClaims(ctx)
+			return nil
+		}
+		dispatchResult = apikey.New(store, scheme)(handler)(context.Background(), httptest.NewRecorder(), request)
+	})
+
+	It("should go through", func() {
+		Ω(dispatchResult).ShouldNot(HaveOccurred())
+		Ω(fetchedKey).ShouldNot(BeNil())
+		Ω(fetchedKey.ID).Should(Equal(key.ID))
+		Ω(fetchedKey.Value).Should(BeEmpty())
+	})
+
+	It("records the key owner, scopes and roles as claims", func() {
+		Ω(fetchedClaims).Should(HaveKeyWithValue("sub", "alice"))
+		Ω(fetchedClaims).Should(HaveKeyWithValue("scopes", []string{"read"}))
+		Ω(fetchedClaims).Should(HaveKeyWithValue("roles", []string{"admin"}))
+	})
+
+	It("tracks the last use of the key", func() {
+		k, err := store.Get(context.Background(), key.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(k.LastUsedAt).ShouldNot(BeNil())
+	})
+
+	Context("with the key in the querystring", func() {
+		BeforeEach(func() {
+			scheme = &goa.APIKeySecurity{In: goa.LocQuery, Name: "key"}
+			request, _ = http.NewRequest("GET", "http://example.com/?key="+key.Value, nil)
+		})
+
+		It("should go through", func() {
+			Ω(dispatchResult).ShouldNot(HaveOccurred())
+			Ω(fetchedKey).ShouldNot(BeNil())
+		})
+	})
+
+	Context("with a missing key", func() {
+		BeforeEach(func() {
+			request.Header.Del("X-Api-Key")
+		})
+
+		It("should fail with an error", func() {
+			Ω(dispatchResult).Should(HaveOccurred())
+			Ω(dispatchResult.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+
+	Context("with an unknown key", func() {
+		BeforeEach(func() {
+			request.Header.Set("X-Api-Key", "unknown")
+		})
+
+		It("should fail with an error", func() {
+			Ω(dispatchResult).Should(HaveOccurred())
+			Ω(fetchedKey).Should(BeNil())
+		})
+	})
+
+	Context("with a revoked key", func() {
+		BeforeEach(func() {
+			Ω(store.Revoke(context.Background(), key.ID)).ShouldNot(HaveOccurred())
+		})
+
+		It("should fail with an error", func() {
+			Ω(dispatchResult).Should(HaveOccurred())
+			Ω(dispatchResult.Error()).Should(ContainSubstring("revoked"))
+		})
+	})
+})
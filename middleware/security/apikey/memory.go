@@ -0,0 +1,125 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// MemoryStore is a KeyManager that keeps the keys in memory. It is intended for tests and
+// development, production services should provide a persistent implementation.
+type MemoryStore struct {
+	sync.Mutex
+	keys    map[string]*Key
+	byValue map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory key store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys:    make(map[string]*Key),
+		byValue: make(map[string]string),
+	}
+}
+
+// Create issues a new key with a random ID and secret value.
+func (s *MemoryStore) Create(ctx context.Context, owner string, scopes, roles []string) (*Key, error) {
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	value, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	k := &Key{
+		ID:        id,
+		Owner:     owner,
+		Scopes:    scopes,
+		Roles:     roles,
+		CreatedAt: time.Now(),
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.keys[id] = k
+	s.byValue[value] = id
+	created := *k
+	created.Value = value
+	return &created, nil
+}
+
+// List returns all the keys sorted by creation time.
+func (s *MemoryStore) List(ctx context.Context) ([]*Key, error) {
+	s.Lock()
+	defer s.Unlock()
+	keys := make([]*Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		c := *k
+		keys = append(keys, &c)
+	}
+	sort.Sort(byCreation(keys))
+	return keys, nil
+}
+
+// Get returns the key with the given ID or nil if there is none.
+func (s *MemoryStore) Get(ctx context.Context, id string) (*Key, error) {
+	s.Lock()
+	defer s.Unlock()
+	k, ok := s.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	c := *k
+	return &c, nil
+}
+
+// Lookup returns the key with the given secret value or nil if there is none.
+func (s *MemoryStore) Lookup(ctx context.Context, value string) (*Key, error) {
+	s.Lock()
+	id, ok := s.byValue[value]
+	s.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	return s.Get(ctx, id)
+}
+
+// Touch records the last use of the key with the given ID.
+func (s *MemoryStore) Touch(ctx context.Context, id string, at time.Time) error {
+	s.Lock()
+	defer s.Unlock()
+	if k, ok := s.keys[id]; ok {
+		k.LastUsedAt = &at
+	}
+	return nil
+}
+
+// Revoke revokes the key with the given ID.
+func (s *MemoryStore) Revoke(ctx context.Context, id string) error {
+	s.Lock()
+	defer s.Unlock()
+	if k, ok := s.keys[id]; ok {
+		k.Revoked = true
+	}
+	return nil
+}
+
+// randomHex returns the hex encoding of n random bytes.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// byCreation sorts keys by creation time.
+type byCreation []*Key
+
+func (b byCreation) Len() int           { return len(b) }
+func (b byCreation) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byCreation) Less(i, j int) bool { return b[i].CreatedAt.Before(b[j].CreatedAt) }
@@ -121,6 +121,9 @@ func New(validationKeys interface{}, validationFunc goa.Middleware, scheme *goa.
 			}
 
 			ctx = WithJWT(ctx, token)
+			if claims, ok := token.Claims.(jwt.MapClaims); ok {
+				ctx = goa.WithClaims(ctx, claims)
+			}
 			if validationFunc != nil {
 				nextHandler = validationFunc(nextHandler)
 			}
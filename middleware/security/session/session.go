@@ -0,0 +1,134 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/goadesign/goa"
+	"golang.org/x/net/context"
+)
+
+type (
+	// Session is a session loaded by the middleware.
+	Session struct {
+		// ID is the session ID stored in the session cookie.
+		ID string
+		// Data is the encoded session data.
+		Data []byte
+	}
+
+	// Store is the interface implemented by the session stores.
+	Store interface {
+		// Load returns the data of the session with the given ID or nil if there is no such
+		// session or if it expired.
+		Load(ctx context.Context, id string) ([]byte, error)
+		// Save stores the data of the session with the given ID.
+		Save(ctx context.Context, id string, data []byte) error
+		// Delete deletes the session with the given ID.
+		Delete(ctx context.Context, id string) error
+	}
+)
+
+// ErrSessionError is the error returned by the middleware when the session cookie is missing or
+// when the session does not exist.
+var ErrSessionError = goa.NewErrorClass("session_security_error", 401)
+
+// New returns a middleware to be used with the SessionSecurity DSL definitions of goa. The
+// middleware reads the session ID from the cookie described by the scheme and loads the session
+// from the store. Requests with no cookie or with an unknown or expired session are rejected.
+// The session is stored in the request context where ContextSession retrieves it.
+func New(store Store, scheme *goa.SessionSecurity) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			cookie, err := req.Cookie(scheme.CookieName)
+			if err != nil || cookie.Value == "" {
+				return ErrSessionError(fmt.Sprintf("missing session cookie %q", scheme.CookieName))
+			}
+			data, err := store.Load(ctx, cookie.Value)
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				return ErrSessionError("invalid or expired session")
+			}
+			ctx = WithSession(ctx, &Session{ID: cookie.Value, Data: data})
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// Start creates a session holding the given data and sets the session cookie on the response.
+func Start(ctx context.Context, rw http.ResponseWriter, store Store, scheme *goa.SessionSecurity, data []byte) (*Session, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	s := &Session{ID: hex.EncodeToString(b), Data: data}
+	if err := store.Save(ctx, s.ID, data); err != nil {
+		return nil, err
+	}
+	http.SetCookie(rw, &http.Cookie{
+		Name:     scheme.CookieName,
+		Value:    s.ID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isTLS(ctx),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return s, nil
+}
+
+// End deletes the session of the request from the store and clears the session cookie.
+func End(ctx context.Context, rw http.ResponseWriter, store Store, scheme *goa.SessionSecurity) error {
+	var id string
+	if s := ContextSession(ctx); s != nil {
+		id = s.ID
+	} else if req := goa.ContextRequest(ctx); req != nil {
+		if cookie, err := req.Cookie(scheme.CookieName); err == nil {
+			id = cookie.Value
+		}
+	}
+	if id != "" {
+		if err := store.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(rw, &http.Cookie{
+		Name:     scheme.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isTLS(ctx),
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// isTLS returns true if the request stored in the context was received over TLS.
+func isTLS(ctx context.Context) bool {
+	req := goa.ContextRequest(ctx)
+	return req != nil && req.TLS != nil
+}
+
+type contextKey int
+
+const (
+	sessionKey contextKey = iota + 1
+)
+
+// WithSession creates a child context containing the given session.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, s)
+}
+
+// ContextSession retrieves the session from a context that went through the middleware.
+func ContextSession(ctx context.Context) *Session {
+	s, ok := ctx.Value(sessionKey).(*Session)
+	if !ok {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/security/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Middleware", func() {
+	var store *session.MemoryStore
+	var scheme *goa.SessionSecurity
+	var request *http.Request
+	var fetched *session.Session
+	var dispatchResult error
+
+	BeforeEach(func() {
+		store = session.NewMemoryStore(time.Hour)
+		scheme = &goa.SessionSecurity{CookieName: "sid"}
+		request, _ = http.NewRequest("GET", "http://example.com/", nil)
+		fetched = nil
+	})
+
+	JustBeforeEach(func() {
+		handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+			fetched = session.ContextSession(ctx)
+			return nil
+		}
+		dispatchResult = session.New(store, scheme)(handler)(context.Background(), httptest.NewRecorder(), request)
+	})
+
+	Context("with a started session", func() {
+		var started *session.Session
+		var rw *httptest.ResponseRecorder
+
+		BeforeEach(func() {
+			rw = httptest.NewRecorder()
+			var err error
+			started, err = session.Start(context.Background(), rw, store, scheme, []byte(`{"name":"alice"}`))
+			Ω(err).ShouldNot(HaveOccurred())
+			cookie := rw.Result().Cookies()[0]
+			Ω(cookie.Name).Should(Equal("sid"))
+			Ω(cookie.HttpOnly).Should(BeTrue())
+			request.AddCookie(cookie)
+		})
+
+		It("loads the session", func() {
+			Ω(dispatchResult).ShouldNot(HaveOccurred())
+			Ω(fetched).ShouldNot(BeNil())
+			Ω(fetched.ID).Should(Equal(started.ID))
+			Ω(string(fetched.Data)).Should(Equal(`{"name":"alice"}`))
+		})
+
+		Context("that ended", func() {
+			BeforeEach(func() {
+				ctx := session.WithSession(context.Background(), started)
+				Ω(session.End(ctx, httptest.NewRecorder(), store, scheme)).ShouldNot(HaveOccurred())
+			})
+
+			It("should fail with an error", func() {
+				Ω(dispatchResult).Should(HaveOccurred())
+				Ω(dispatchResult.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+			})
+		})
+	})
+
+	Context("with no session cookie", func() {
+		It("should fail with an error", func() {
+			Ω(dispatchResult).Should(HaveOccurred())
+			Ω(fetched).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("RedisStore", func() {
+	var commands [][]interface{}
+	var values map[string][]byte
+	var store *session.RedisStore
+
+	BeforeEach(func() {
+		commands = nil
+		values = make(map[string][]byte)
+		store = session.NewRedisStore(func(cmd string, args ...interface{}) (interface{}, error) {
+			commands = append(commands, append([]interface{}{cmd}, args...))
+			key := args[0].(string)
+			switch cmd {
+			case "SET":
+				values[key] = args[1].([]byte)
+			case "GET":
+				if v, ok := values[key]; ok {
+					return v, nil
+				}
+			case "DEL":
+				delete(values, key)
+			}
+			return nil, nil
+		}, time.Minute)
+	})
+
+	It("stores the sessions with an expiration", func() {
+		ctx := context.Background()
+		Ω(store.Save(ctx, "id", []byte("data"))).ShouldNot(HaveOccurred())
+		Ω(commands[0]).Should(Equal([]interface{}{"SET", "session:id", []byte("data"), "PX", int64(60000)}))
+		data, err := store.Load(ctx, "id")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(data)).Should(Equal("data"))
+		Ω(store.Delete(ctx, "id")).ShouldNot(HaveOccurred())
+		data, err = store.Load(ctx, "id")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(data).Should(BeNil())
+	})
+})
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+type (
+	// MemoryStore is a Store that keeps the sessions in memory. It is intended for tests and
+	// development or for services running a single instance.
+	MemoryStore struct {
+		sync.Mutex
+		// TTL is the duration after which sessions expire, zero means sessions never expire.
+		TTL      time.Duration
+		sessions map[string]*memorySession
+	}
+
+	// RedisStore is a Store that keeps the sessions in Redis. It sends commands using a function
+	// with the signature of the Do method of the redigo connections so that any Redis client
+	// may be used, e.g.:
+	//
+	//    store := session.NewRedisStore(func(cmd string, args ...interface{}) (interface{}, error) {
+	//        conn := pool.Get()
+	//        defer conn.Close()
+	//        return conn.Do(cmd, args...)
+	//    }, 24*time.Hour)
+	//
+	RedisStore struct {
+		// Do sends a command to Redis and returns the reply.
+		Do func(cmd string, args ...interface{}) (interface{}, error)
+		// Prefix is prepended to the session IDs to build the Redis keys.
+		Prefix string
+		// TTL is the duration after which sessions expire, zero means sessions never expire.
+		TTL time.Duration
+	}
+
+	// memorySession is a session kept by MemoryStore.
+	memorySession struct {
+		data    []byte
+		expires time.Time
+	}
+)
+
+// NewMemoryStore creates an empty in-memory store whose sessions expire after the given TTL.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{TTL: ttl, sessions: make(map[string]*memorySession)}
+}
+
+// Load returns the data of the session with the given ID.
+func (s *MemoryStore) Load(ctx context.Context, id string) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	ms, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	if !ms.expires.IsZero() && time.Now().After(ms.expires) {
+		delete(s.sessions, id)
+		return nil, nil
+	}
+	return ms.data, nil
+}
+
+// Save stores the data of the session with the given ID.
+func (s *MemoryStore) Save(ctx context.Context, id string, data []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	ms := &memorySession{data: data}
+	if s.TTL > 0 {
+		ms.expires = time.Now().Add(s.TTL)
+	}
+	s.sessions[id] = ms
+	return nil
+}
+
+// Delete deletes the session with the given ID.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// NewRedisStore creates a store that sends Redis commands with do and whose sessions expire after
+// the given TTL. The Redis keys are prefixed with "session:".
+func NewRedisStore(do func(cmd string, args ...interface{}) (interface{}, error), ttl time.Duration) *RedisStore {
+	return &RedisStore{Do: do, Prefix: "session:", TTL: ttl}
+}
+
+// Load returns the data of the session with the given ID.
+func (s *RedisStore) Load(ctx context.Context, id string) ([]byte, error) {
+	reply, err := s.Do("GET", s.Prefix+id)
+	if err != nil {
+		return nil, err
+	}
+	switch r := reply.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return r, nil
+	case string:
+		return []byte(r), nil
+	default:
+		return nil, fmt.Errorf("unexpected Redis reply type %T", reply)
+	}
+}
+
+// Save stores the data of the session with the given ID.
+func (s *RedisStore) Save(ctx context.Context, id string, data []byte) error {
+	args := []interface{}{s.Prefix + id, data}
+	if s.TTL > 0 {
+		args = append(args, "PX", int64(s.TTL/time.Millisecond))
+	}
+	_, err := s.Do("SET", args...)
+	return err
+}
+
+// Delete deletes the session with the given ID.
+func (s *RedisStore) Delete(ctx context.Context, id string) error {
+	_, err := s.Do("DEL", s.Prefix+id)
+	return err
+}
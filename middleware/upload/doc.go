@@ -0,0 +1,23 @@
+/*
+Package upload provides a middleware that implements the tus resumable upload protocol
+(https://tus.io/protocols/resumable-upload.html) for the actions designed with ResumableUpload.
+
+Clients create uploads with a POST request to the action route announcing the upload length in the
+Upload-Length header, the response Location header gives the URL of the upload. They then send
+the content in one or several PATCH requests to that URL and may query the current upload offset
+with HEAD requests to resume interrupted uploads. The action handler is invoked by the PATCH
+request that completes the upload, ContextInfo returns the completed upload information.
+
+The upload content is written to the store registered with UseStore prior to mounting the
+controllers. NewMemoryStore returns a store that keeps the uploads in memory and is suitable for
+tests, services implement Store to write the uploads to disk or to an object storage. The uploads
+are not bound to the clients that create them, anyone who knows the upload URL may resume and
+complete the upload: stores must identify the uploads with unguessable identifiers.
+
+The Presigned middleware implements the actions designed with PresignedUpload: it hands out
+presigned PUT URLs that let clients upload files directly to an object storage bucket and invokes
+the action handler when the clients call the completion callback, ContextObject returns the
+uploaded object. The storage provider is abstracted by the Presigner registered with UsePresigner.
+Only the keys handed out by the middleware may be completed and each key may be completed once.
+*/
+package upload
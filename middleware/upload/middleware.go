@@ -0,0 +1,228 @@
+package upload
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+const (
+	// TusVersion is the version of the tus protocol implemented by the middleware.
+	TusVersion = "1.0.0"
+
+	// IDParam is the name of the path wildcard that identifies the uploads in the paths of the
+	// routes that receive the upload chunks.
+	IDParam = "upload_id"
+
+	// ChunkContentType is the content type of the PATCH requests that carry the upload chunks.
+	ChunkContentType = "application/offset+octet-stream"
+)
+
+var (
+	// ErrUnsupportedVersion is the error produced when a request uses a version of the tus
+	// protocol other than TusVersion.
+	ErrUnsupportedVersion = goa.NewErrorClass("unsupported_tus_version", 412)
+
+	// ErrOffsetMismatch is the error produced when the offset of a chunk does not match the
+	// current offset of the upload.
+	ErrOffsetMismatch = goa.NewErrorClass("upload_offset_mismatch", 409)
+
+	// ErrInvalidContentType is the error produced when a chunk is not sent with the
+	// ChunkContentType content type.
+	ErrInvalidContentType = goa.NewErrorClass("invalid_chunk_content_type", 415)
+)
+
+type (
+	// Options describes the uploads received by an action.
+	Options struct {
+		// MaxSize is the maximum size in bytes of the uploads, 0 means no limit.
+		MaxSize int64
+		// Required lists the metadata that clients must provide when creating uploads.
+		Required []string
+	}
+
+	// storeKey is the key used to store the upload store in the service context.
+	storeKey struct{}
+
+	// infoKey is the key used to store the completed upload in the request context.
+	infoKey struct{}
+)
+
+// UseStore registers the store the uploads are written to. UseStore must be called before the
+// controllers are created.
+func UseStore(service *goa.Service, s Store) {
+	service.Context = context.WithValue(service.Context, storeKey{}, s)
+}
+
+// ContextInfo returns the upload completed by the request, nil if the request did not complete an
+// upload.
+func ContextInfo(ctx context.Context) *Info {
+	if info, ok := ctx.Value(infoKey{}).(*Info); ok {
+		return info
+	}
+	return nil
+}
+
+// Resumable returns a middleware that implements the creation, offset retrieval and chunk
+// transfer requests of the tus protocol. The wrapped handler is only invoked by the PATCH request
+// that completes an upload, ContextInfo returns the upload in its context. The requests fail with
+// a 500 response if no store is registered with UseStore.
+func Resumable(opts Options) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set("Tus-Resumable", TusVersion)
+			if v := req.Header.Get("Tus-Resumable"); v != TusVersion {
+				rw.Header().Set("Tus-Version", TusVersion)
+				return ErrUnsupportedVersion("unsupported tus protocol version", "version", v)
+			}
+			s, ok := ctx.Value(storeKey{}).(Store)
+			if !ok {
+				return goa.ErrInternal("no upload store, make sure upload.UseStore is called before mounting the controllers")
+			}
+			switch req.Method {
+			case "POST":
+				return create(ctx, s, opts, rw, req)
+			case "HEAD":
+				return offset(ctx, s, rw)
+			case "PATCH":
+				return write(ctx, s, h, rw, req)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// ParseMetadata parses the value of a Upload-Metadata header: comma separated pairs of keys and
+// base64 encoded values separated by a space, the values may be omitted.
+func ParseMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		elems := strings.Fields(pair)
+		if len(elems) == 0 || len(elems) > 2 {
+			return nil, fmt.Errorf("invalid upload metadata %q", pair)
+		}
+		var value string
+		if len(elems) == 2 {
+			v, err := base64.StdEncoding.DecodeString(elems[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid upload metadata %q: %s", elems[0], err)
+			}
+			value = string(v)
+		}
+		metadata[elems[0]] = value
+	}
+	return metadata, nil
+}
+
+// FormatMetadata returns the value of the Upload-Metadata header that carries the given metadata.
+func FormatMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k
+		if v := metadata[k]; v != "" {
+			pairs[i] += " " + base64.StdEncoding.EncodeToString([]byte(v))
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// create handles the upload creation requests.
+func create(ctx context.Context, s Store, opts Options, rw http.ResponseWriter, req *http.Request) error {
+	raw := req.Header.Get("Upload-Length")
+	length, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || length < 0 {
+		return goa.ErrBadRequest("invalid Upload-Length header", "value", raw)
+	}
+	if opts.MaxSize > 0 && length > opts.MaxSize {
+		return goa.ErrRequestBodyTooLarge(fmt.Sprintf("upload length exceeds %d bytes", opts.MaxSize))
+	}
+	metadata, err := ParseMetadata(req.Header.Get("Upload-Metadata"))
+	if err != nil {
+		return goa.ErrBadRequest(err)
+	}
+	for _, n := range opts.Required {
+		if _, ok := metadata[n]; !ok {
+			return goa.ErrBadRequest("missing required upload metadata", "name", n)
+		}
+	}
+	id, err := s.Create(ctx, length, metadata)
+	if err != nil {
+		return err
+	}
+	rw.Header().Set("Location", strings.TrimSuffix(req.URL.Path, "/")+"/"+id)
+	rw.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// offset handles the requests that retrieve the offset of an upload.
+func offset(ctx context.Context, s Store, rw http.ResponseWriter) error {
+	info, err := lookup(ctx, s)
+	if err != nil {
+		return err
+	}
+	rw.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	rw.Header().Set("Upload-Length", strconv.FormatInt(info.Length, 10))
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// write handles the requests that carry the upload chunks, it invokes h once the upload is
+// complete.
+func write(ctx context.Context, s Store, h goa.Handler, rw http.ResponseWriter, req *http.Request) error {
+	if ct := req.Header.Get("Content-Type"); ct != ChunkContentType {
+		return ErrInvalidContentType("invalid chunk content type", "content-type", ct)
+	}
+	raw := req.Header.Get("Upload-Offset")
+	off, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || off < 0 {
+		return goa.ErrBadRequest("invalid Upload-Offset header", "value", raw)
+	}
+	info, err := lookup(ctx, s)
+	if err != nil {
+		return err
+	}
+	if off != info.Offset {
+		return ErrOffsetMismatch("chunk offset does not match the upload offset", "offset", info.Offset)
+	}
+	n, err := s.Write(ctx, info.ID, off, io.LimitReader(req.Body, info.Length-off))
+	info.Offset += n
+	rw.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	if err != nil {
+		if err.Error() == "http: request body too large" {
+			return goa.ErrRequestBodyTooLarge("chunk exceeds the maximum request body length, resume the upload with smaller chunks")
+		}
+		return err
+	}
+	if !info.Complete() {
+		rw.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return h(context.WithValue(ctx, infoKey{}, info), rw, req)
+}
+
+// lookup returns the upload identified by the request path.
+func lookup(ctx context.Context, s Store) (*Info, error) {
+	id := goa.ContextRequest(ctx).Params.Get(IDParam)
+	info, err := s.Info(ctx, id)
+	if err == ErrNotFound {
+		return nil, goa.ErrNotFound("upload not found", "id", id)
+	}
+	return info, err
+}
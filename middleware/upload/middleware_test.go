@@ -0,0 +1,167 @@
+package upload_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/upload"
+	"github.com/goadesign/goa/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Resumable", func() {
+	var service *goa.Service
+	var store *upload.MemoryStore
+	var opts upload.Options
+	var completed *upload.Info
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		store = upload.NewMemoryStore()
+		upload.UseStore(service, store)
+		opts = upload.Options{MaxSize: 10, Required: []string{"filename"}}
+		completed = nil
+	})
+
+	send := func(method, id string, headers map[string]string, body string) (*httptest.ResponseRecorder, error) {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			completed = upload.ContextInfo(ctx)
+			rw.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		path := "/uploads"
+		var params url.Values
+		if id != "" {
+			path += "/" + id
+			params = url.Values{upload.IDParam: []string{id}}
+		}
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Tus-Resumable", upload.TusVersion)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(service.Context, rw, req, params)
+		err := upload.Resumable(opts)(h)(ctx, rw, req)
+		return rw, err
+	}
+
+	create := func(length int) string {
+		rw, err := send("POST", "", map[string]string{
+			"Upload-Length":   strconv.Itoa(length),
+			"Upload-Metadata": upload.FormatMetadata(map[string]string{"filename": "notes.txt"}),
+		}, "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusCreated))
+		Ω(rw.Header().Get("Tus-Resumable")).Should(Equal(upload.TusVersion))
+		loc := rw.Header().Get("Location")
+		Ω(loc).Should(HavePrefix("/uploads/"))
+		return strings.TrimPrefix(loc, "/uploads/")
+	}
+
+	chunk := func(id string, offset int, data string) (*httptest.ResponseRecorder, error) {
+		return send("PATCH", id, map[string]string{
+			"Content-Type":  upload.ChunkContentType,
+			"Upload-Offset": strconv.Itoa(offset),
+		}, data)
+	}
+
+	It("creates uploads and receives the chunks", func() {
+		id := create(8)
+
+		rw, err := chunk(id, 0, "abcd")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusNoContent))
+		Ω(rw.Header().Get("Upload-Offset")).Should(Equal("4"))
+		Ω(completed).Should(BeNil())
+
+		rw, err = send("HEAD", id, nil, "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusOK))
+		Ω(rw.Header().Get("Upload-Offset")).Should(Equal("4"))
+		Ω(rw.Header().Get("Upload-Length")).Should(Equal("8"))
+
+		rw, err = chunk(id, 4, "efgh")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("Upload-Offset")).Should(Equal("8"))
+		Ω(completed).ShouldNot(BeNil())
+		Ω(completed.ID).Should(Equal(id))
+		Ω(completed.Length).Should(Equal(int64(8)))
+		Ω(completed.Metadata).Should(Equal(map[string]string{"filename": "notes.txt"}))
+
+		r, err := store.Open(context.Background(), id)
+		Ω(err).ShouldNot(HaveOccurred())
+		content, _ := ioutil.ReadAll(r)
+		Ω(string(content)).Should(Equal("abcdefgh"))
+	})
+
+	It("identifies the uploads with random UUIDs", func() {
+		id1, id2 := create(5), create(5)
+		Ω(id1).ShouldNot(Equal(id2))
+		_, err := uuid.FromString(id1)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("rejects chunks with the wrong offset", func() {
+		id := create(8)
+		_, err := chunk(id, 2, "abcd")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusConflict))
+	})
+
+	It("rejects chunks with the wrong content type", func() {
+		id := create(8)
+		_, err := send("PATCH", id, map[string]string{"Upload-Offset": "0"}, "abcd")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusUnsupportedMediaType))
+	})
+
+	It("rejects unknown uploads", func() {
+		_, err := send("HEAD", "42", nil, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusNotFound))
+	})
+
+	It("rejects uploads exceeding the maximum size", func() {
+		_, err := send("POST", "", map[string]string{"Upload-Length": "11", "Upload-Metadata": "filename"}, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusRequestEntityTooLarge))
+	})
+
+	It("rejects uploads missing required metadata", func() {
+		_, err := send("POST", "", map[string]string{"Upload-Length": "4"}, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.Error()).Should(ContainSubstring("missing required upload metadata"))
+	})
+
+	It("rejects other protocol versions", func() {
+		rw, err := send("POST", "", map[string]string{"Tus-Resumable": "0.2.2", "Upload-Length": "4"}, "")
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusPreconditionFailed))
+		Ω(rw.Header().Get("Tus-Version")).Should(Equal(upload.TusVersion))
+	})
+})
+
+var _ = Describe("Metadata", func() {
+	It("round trips", func() {
+		md := map[string]string{"filename": "a b.txt", "empty": ""}
+		header := upload.FormatMetadata(md)
+		Ω(header).Should(Equal("empty,filename YSBiLnR4dA=="))
+		parsed, err := upload.ParseMetadata(header)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(parsed).Should(Equal(md))
+	})
+
+	It("rejects invalid values", func() {
+		_, err := upload.ParseMetadata("filename !!")
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,191 @@
+package upload
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/uuid"
+)
+
+type (
+	// Presigner is the interface implemented by the object storage providers used to upload
+	// files with presigned URLs.
+	Presigner interface {
+		// PresignPut returns a URL that lets clients PUT the object with the given key in
+		// the bucket until expires. contentType is the content type of the object if known.
+		PresignPut(ctx context.Context, bucket, key, contentType string, expires time.Time) (string, error)
+		// Stat returns the object with the given key in the bucket, ErrNotFound if there
+		// isn't one.
+		Stat(ctx context.Context, bucket, key string) (*Object, error)
+	}
+
+	// Object describes a file uploaded to an object storage bucket.
+	Object struct {
+		// Bucket is the name of the bucket.
+		Bucket string
+		// Key identifies the object in the bucket.
+		Key string
+		// Size is the size of the object in bytes.
+		Size int64
+		// ContentType is the content type of the object if known.
+		ContentType string
+	}
+
+	// PresignedURL is the body of the responses that hand out presigned URLs.
+	PresignedURL struct {
+		// URL is the presigned URL.
+		URL string `json:"url"`
+		// Method is the HTTP method used to upload the file.
+		Method string `json:"method"`
+		// Key identifies the object in the bucket.
+		Key string `json:"key"`
+		// ExpiresAt is the time after which the URL cannot be used anymore.
+		ExpiresAt time.Time `json:"expires_at"`
+		// CompleteURL is the path of the completion callback called once the file is
+		// uploaded.
+		CompleteURL string `json:"complete_url"`
+	}
+
+	// PresignOptions describes the presigned uploads handled by an action.
+	PresignOptions struct {
+		// Bucket is the name of the bucket the files are uploaded to.
+		Bucket string
+		// Expiry is the duration during which the presigned URLs are valid.
+		Expiry time.Duration
+	}
+
+	// presignerKey is the key used to store the presigner in the service context.
+	presignerKey struct{}
+
+	// objectKey is the key used to store the uploaded object in the request context.
+	objectKey struct{}
+
+	// issuedKeys records the keys of the objects handed out by a Presigned middleware that have
+	// not been completed yet.
+	issuedKeys struct {
+		mu   sync.Mutex
+		keys map[string]*issuedKey
+	}
+
+	// issuedKey is a key handed out by a Presigned middleware.
+	issuedKey struct {
+		// deadline is the time after which the completion callback is rejected.
+		deadline time.Time
+		// claimed is true while a completion callback is being handled.
+		claimed bool
+	}
+)
+
+// UsePresigner registers the presigner used to hand out presigned URLs. UsePresigner must be
+// called before the controllers are created.
+func UsePresigner(service *goa.Service, p Presigner) {
+	service.Context = context.WithValue(service.Context, presignerKey{}, p)
+}
+
+// ContextObject returns the object uploaded with a presigned URL whose completion callback is
+// handled by the request, nil if the request is not a completion callback.
+func ContextObject(ctx context.Context) *Object {
+	if o, ok := ctx.Value(objectKey{}).(*Object); ok {
+		return o
+	}
+	return nil
+}
+
+// Presigned returns a middleware that hands out presigned PUT URLs and handles the completion
+// callbacks. Requests that do not carry an upload identifier get a PresignedURL for a new object,
+// the content_type query string parameter sets the content type of the object if present. The
+// completion callback requests invoke the wrapped handler once the object is found in the bucket,
+// ContextObject returns it in its context. Only the keys handed out by the middleware may be
+// completed, once and until twice the expiry duration has elapsed since they were handed out: the
+// keys are recorded in memory so the completion callback must be handled by the process that
+// handed out the URL. The requests fail with a 500 response if no presigner is registered with
+// UsePresigner.
+func Presigned(opts PresignOptions) goa.Middleware {
+	issued := &issuedKeys{keys: make(map[string]*issuedKey)}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			p, ok := ctx.Value(presignerKey{}).(Presigner)
+			if !ok {
+				return goa.ErrInternal("no presigner, make sure upload.UsePresigner is called before mounting the controllers")
+			}
+			if key := goa.ContextRequest(ctx).Params.Get(IDParam); key != "" {
+				if !issued.claim(key) {
+					return goa.ErrNotFound("upload not found", "key", key)
+				}
+				o, err := p.Stat(ctx, opts.Bucket, key)
+				if err == nil {
+					err = h(context.WithValue(ctx, objectKey{}, o), rw, req)
+				}
+				// Let the client retry failed completions
+				issued.release(key, err == nil)
+				if err == ErrNotFound {
+					return goa.ErrNotFound("upload not found", "key", key)
+				}
+				return err
+			}
+			key := uuid.NewV4().String()
+			expires := time.Now().Add(opts.Expiry).UTC()
+			u, err := p.PresignPut(ctx, opts.Bucket, key, req.URL.Query().Get("content_type"), expires)
+			if err != nil {
+				return err
+			}
+			issued.add(key, expires.Add(opts.Expiry))
+			body := &PresignedURL{
+				URL:         u,
+				Method:      "PUT",
+				Key:         key,
+				ExpiresAt:   expires,
+				CompleteURL: strings.TrimSuffix(req.URL.Path, "/") + "/" + key + "/complete",
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(http.StatusCreated)
+			return json.NewEncoder(rw).Encode(body)
+		}
+	}
+}
+
+// add records a key handed out by the middleware and forgets the keys whose deadline has passed.
+func (i *issuedKeys) add(key string, deadline time.Time) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	now := time.Now()
+	for k, ik := range i.keys {
+		if !ik.claimed && now.After(ik.deadline) {
+			delete(i.keys, k)
+		}
+	}
+	i.keys[key] = &issuedKey{deadline: deadline}
+}
+
+// claim returns true if the key was handed out by the middleware, its deadline has not passed and
+// no other completion callback is being handled for it.
+func (i *issuedKeys) claim(key string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	ik, ok := i.keys[key]
+	if !ok || ik.claimed || time.Now().After(ik.deadline) {
+		return false
+	}
+	ik.claimed = true
+	return true
+}
+
+// release forgets the key if the upload is completed, it makes the key available to another
+// completion callback otherwise.
+func (i *issuedKeys) release(key string, completed bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if completed {
+		delete(i.keys, key)
+		return
+	}
+	if ik, ok := i.keys[key]; ok {
+		ik.claimed = false
+	}
+}
@@ -0,0 +1,142 @@
+package upload_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/upload"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// testPresigner is a presigner that keeps the objects in memory.
+type testPresigner struct {
+	objects     map[string]*upload.Object
+	contentType string
+	err         error
+}
+
+func (p *testPresigner) PresignPut(_ context.Context, bucket, key, contentType string, _ time.Time) (string, error) {
+	p.contentType = contentType
+	return "https://" + bucket + ".storage.test/" + key + "?sig=xyz", p.err
+}
+
+func (p *testPresigner) Stat(_ context.Context, bucket, key string) (*upload.Object, error) {
+	if o, ok := p.objects[key]; ok {
+		return o, nil
+	}
+	return nil, upload.ErrNotFound
+}
+
+var _ = Describe("Presigned", func() {
+	var service *goa.Service
+	var presigner *testPresigner
+	var completed *upload.Object
+	var handler goa.Handler
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		presigner = &testPresigner{objects: map[string]*upload.Object{
+			"abc": {Bucket: "avatars", Key: "abc", Size: 42, ContentType: "image/png"},
+		}}
+		upload.UsePresigner(service, presigner)
+		completed = nil
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			completed = upload.ContextObject(ctx)
+			rw.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		handler = upload.Presigned(upload.PresignOptions{Bucket: "avatars", Expiry: time.Minute})(h)
+	})
+
+	send := func(path, key string) (*httptest.ResponseRecorder, error) {
+		var params url.Values
+		if key != "" {
+			params = url.Values{upload.IDParam: []string{key}}
+		}
+		req, _ := http.NewRequest("POST", path, nil)
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(service.Context, rw, req, params)
+		err := handler(ctx, rw, req)
+		return rw, err
+	}
+
+	// issue hands out a presigned URL and stores the corresponding object in the bucket.
+	issue := func() string {
+		rw, err := send("/avatars", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		var body upload.PresignedURL
+		Ω(json.Unmarshal(rw.Body.Bytes(), &body)).Should(Succeed())
+		presigner.objects[body.Key] = &upload.Object{Bucket: "avatars", Key: body.Key, Size: 42}
+		return body.Key
+	}
+
+	notFound := func(err error) {
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusNotFound))
+		Ω(completed).Should(BeNil())
+	}
+
+	It("hands out presigned URLs", func() {
+		rw, err := send("/avatars?content_type=image/png", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusCreated))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("application/json"))
+		var body upload.PresignedURL
+		Ω(json.Unmarshal(rw.Body.Bytes(), &body)).Should(Succeed())
+		Ω(body.Key).ShouldNot(BeEmpty())
+		Ω(body.Method).Should(Equal("PUT"))
+		Ω(body.URL).Should(Equal("https://avatars.storage.test/" + body.Key + "?sig=xyz"))
+		Ω(body.CompleteURL).Should(Equal("/avatars/" + body.Key + "/complete"))
+		Ω(body.ExpiresAt).Should(BeTemporally("~", time.Now().Add(time.Minute), 5*time.Second))
+		Ω(presigner.contentType).Should(Equal("image/png"))
+		Ω(completed).Should(BeNil())
+	})
+
+	It("fails when the presigner fails", func() {
+		presigner.err = errors.New("boom")
+		_, err := send("/avatars", "")
+		Ω(err).Should(MatchError("boom"))
+	})
+
+	It("invokes the handler on completion", func() {
+		key := issue()
+		rw, err := send("/avatars/"+key+"/complete", key)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusNoContent))
+		Ω(completed).Should(Equal(presigner.objects[key]))
+	})
+
+	It("rejects completions of keys that were not handed out", func() {
+		_, err := send("/avatars/abc/complete", "abc")
+		notFound(err)
+	})
+
+	It("rejects the completions of completed uploads", func() {
+		key := issue()
+		_, err := send("/avatars/"+key+"/complete", key)
+		Ω(err).ShouldNot(HaveOccurred())
+		completed = nil
+		_, err = send("/avatars/"+key+"/complete", key)
+		notFound(err)
+	})
+
+	It("lets clients retry the completion of objects not uploaded yet", func() {
+		key := issue()
+		o := presigner.objects[key]
+		delete(presigner.objects, key)
+		_, err := send("/avatars/"+key+"/complete", key)
+		notFound(err)
+		presigner.objects[key] = o
+		_, err = send("/avatars/"+key+"/complete", key)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(completed).Should(Equal(o))
+	})
+})
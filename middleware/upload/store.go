@@ -0,0 +1,118 @@
+package upload
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/uuid"
+)
+
+// ErrNotFound is the error returned by stores when asked for an upload that does not exist.
+var ErrNotFound = errors.New("upload not found")
+
+type (
+	// Info describes the state of an upload.
+	Info struct {
+		// ID identifies the upload in the store.
+		ID string
+		// Length is the size of the upload in bytes.
+		Length int64
+		// Offset is the number of bytes received so far.
+		Offset int64
+		// Metadata contains the metadata sent by the client when creating the upload.
+		Metadata map[string]string
+	}
+
+	// Store is the interface implemented by the upload stores. The uploads are not bound to the
+	// clients that create them: any client that knows the identifier of an upload may resume it
+	// and complete it, so the identifiers returned by Create must be unguessable.
+	Store interface {
+		// Create creates an upload with the given length and metadata and returns its
+		// identifier. The identifier must be unguessable, e.g. a random UUID.
+		Create(ctx context.Context, length int64, metadata map[string]string) (string, error)
+		// Info returns the state of the upload with the given identifier, ErrNotFound if
+		// there isn't one.
+		Info(ctx context.Context, id string) (*Info, error)
+		// Write appends the content read from r to the upload with the given identifier and
+		// returns the number of bytes written. offset is the current offset of the upload.
+		Write(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+		// Open returns a reader on the content of the upload with the given identifier.
+		Open(ctx context.Context, id string) (io.ReadCloser, error)
+	}
+
+	// MemoryStore is a Store that keeps the uploads in memory.
+	MemoryStore struct {
+		mu      sync.Mutex
+		uploads map[string]*memoryUpload
+	}
+
+	// memoryUpload is an upload kept in memory.
+	memoryUpload struct {
+		info Info
+		data []byte
+	}
+)
+
+// Complete returns true if all the upload content has been received.
+func (i *Info) Complete() bool {
+	return i.Offset >= i.Length
+}
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{uploads: make(map[string]*memoryUpload)}
+}
+
+// Create creates an empty upload identified by a random UUID.
+func (s *MemoryStore) Create(_ context.Context, length int64, metadata map[string]string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := uuid.NewV4().String()
+	s.uploads[id] = &memoryUpload{info: Info{ID: id, Length: length, Metadata: metadata}}
+	return id, nil
+}
+
+// Info returns a copy of the upload state.
+func (s *MemoryStore) Info(_ context.Context, id string) (*Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	info := u.info
+	return &info, nil
+}
+
+// Write appends the content read from r to the upload.
+func (s *MemoryStore) Write(_ context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	chunk, err := ioutil.ReadAll(r)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	if offset != u.info.Offset {
+		return 0, errors.New("upload offset mismatch")
+	}
+	u.data = append(u.data, chunk...)
+	u.info.Offset += int64(len(chunk))
+	return int64(len(chunk)), err
+}
+
+// Open returns a reader on the upload content received so far.
+func (s *MemoryStore) Open(_ context.Context, id string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.uploads[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return ioutil.NopCloser(bytes.NewReader(u.data)), nil
+}
@@ -0,0 +1,145 @@
+/*
+Package mqtt binds service actions to MQTT topics. The code generated for the actions designed with
+MQTT subscribes each action to its topic using the Client provided by the service: the messages
+published to the topic are sent to the action through the service mux as the request payload so
+that the action middleware, decoding and validation run as for the HTTP requests. The successful
+action results are published to the result topic of the binding if any.
+
+The package does not depend on a specific MQTT library, Client is implemented by adapting the
+library used by the service, e.g. github.com/eclipse/paho.mqtt.golang.
+*/
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goadesign/goa"
+)
+
+type (
+	// Message is a message published to a topic.
+	Message struct {
+		// Topic is the name of the topic the message was published to.
+		Topic string
+		// Payload is the message content.
+		Payload []byte
+	}
+
+	// Client is the interface implemented by the MQTT clients.
+	Client interface {
+		// Subscribe calls handler with the messages published to the topics that match
+		// filter.
+		Subscribe(filter string, qos byte, handler func(*Message)) error
+		// Publish publishes payload to topic.
+		Publish(topic string, qos byte, payload []byte) error
+	}
+
+	// Binding describes the topic an action subscribes to.
+	Binding struct {
+		// Topic is the topic the action subscribes to, the levels starting with ":" capture
+		// the values of the action parameters with the same names.
+		Topic string
+		// QoS is the quality of service of the subscription and of the published results.
+		QoS byte
+		// ResultTopic is the topic the action results are published to if any.
+		ResultTopic string
+		// Verb is the HTTP method of the action route.
+		Verb string
+		// Path is the full path of the action route including wildcards.
+		Path string
+	}
+)
+
+// Subscribe subscribes the action described by b to its topic using c.
+func Subscribe(service *goa.Service, c Client, b *Binding) error {
+	return c.Subscribe(Filter(b.Topic), b.QoS, func(m *Message) {
+		handle(service, c, b, m)
+	})
+}
+
+// Publish publishes the JSON encoding of v to topic.
+func Publish(c Client, topic string, qos byte, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Publish(topic, qos, payload)
+}
+
+// Filter returns the filter used to subscribe to topic: the levels that start with ":" are replaced
+// with the "+" single level wildcard.
+func Filter(topic string) string {
+	levels := strings.Split(topic, "/")
+	for i, l := range levels {
+		if strings.HasPrefix(l, ":") {
+			levels[i] = "+"
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+// Match returns the values of the parameters captured by the levels of pattern that start with
+// ":" if topic matches pattern.
+func Match(pattern, topic string) (map[string]string, bool) {
+	plevels := strings.Split(pattern, "/")
+	tlevels := strings.Split(topic, "/")
+	if len(plevels) != len(tlevels) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, l := range plevels {
+		if strings.HasPrefix(l, ":") {
+			params[l[1:]] = tlevels[i]
+		} else if l != tlevels[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Topic returns the topic built from pattern by replacing the levels that start with ":" with the
+// values of the corresponding parameters.
+func Topic(pattern string, params map[string]string) string {
+	levels := strings.Split(pattern, "/")
+	for i, l := range levels {
+		if strings.HasPrefix(l, ":") {
+			levels[i] = params[l[1:]]
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+// handle sends the message to the action and publishes the result.
+func handle(service *goa.Service, c Client, b *Binding, m *Message) {
+	params, ok := Match(b.Topic, m.Topic)
+	if !ok {
+		return
+	}
+	values := make(url.Values, len(params))
+	for n, v := range params {
+		values.Set(n, v)
+	}
+	req, err := http.NewRequest(b.Verb, goa.DispatchURL(b.Path, values), bytes.NewReader(m.Payload))
+	if err != nil {
+		service.LogError("mqtt", "topic", m.Topic, "err", err)
+		return
+	}
+	req = req.WithContext(service.Context)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp := goa.Dispatch(service.Mux, req)
+	if resp.Status >= 400 {
+		service.LogError("mqtt", "topic", m.Topic, "status", resp.Status, "err", string(resp.Body))
+		return
+	}
+	if b.ResultTopic == "" {
+		return
+	}
+	if err := c.Publish(Topic(b.ResultTopic, params), b.QoS, resp.Body); err != nil {
+		service.LogError("mqtt", "topic", b.ResultTopic, "err", err)
+	}
+}
@@ -0,0 +1,97 @@
+package mqtt_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/mqtt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fakeClient records the subscriptions and the published messages.
+type fakeClient struct {
+	filters   map[string]func(*mqtt.Message)
+	published []*mqtt.Message
+}
+
+func (c *fakeClient) Subscribe(filter string, qos byte, handler func(*mqtt.Message)) error {
+	c.filters[filter] = handler
+	return nil
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, payload []byte) error {
+	c.published = append(c.published, &mqtt.Message{Topic: topic, Payload: payload})
+	return nil
+}
+
+var _ = Describe("Topics", func() {
+	It("builds the subscription filter", func() {
+		Ω(mqtt.Filter("devices/:deviceID/telemetry")).Should(Equal("devices/+/telemetry"))
+	})
+
+	It("captures the topic parameters", func() {
+		params, ok := mqtt.Match("devices/:deviceID/telemetry", "devices/42/telemetry")
+		Ω(ok).Should(BeTrue())
+		Ω(params).Should(Equal(map[string]string{"deviceID": "42"}))
+		_, ok = mqtt.Match("devices/:deviceID/telemetry", "devices/42/status")
+		Ω(ok).Should(BeFalse())
+	})
+
+	It("builds topics from the parameters", func() {
+		Ω(mqtt.Topic("devices/:deviceID/ack", map[string]string{"deviceID": "42"})).Should(Equal("devices/42/ack"))
+	})
+})
+
+var _ = Describe("Subscribe", func() {
+	var (
+		service *goa.Service
+		client  *fakeClient
+		status  int
+		body    string
+		query   url.Values
+	)
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		client = &fakeClient{filters: make(map[string]func(*mqtt.Message))}
+		status = 200
+		body = ""
+		service.Mux.Handle("POST", "/devices/:deviceID/telemetry", func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+			b, _ := ioutil.ReadAll(req.Body)
+			body = string(b)
+			query = params
+			rw.WriteHeader(status)
+			rw.Write([]byte(`{"ok":true}`))
+		})
+		b := &mqtt.Binding{
+			Topic:       "devices/:deviceID/telemetry",
+			QoS:         1,
+			ResultTopic: "devices/:deviceID/ack",
+			Verb:        "POST",
+			Path:        "/devices/:deviceID/telemetry",
+		}
+		Ω(mqtt.Subscribe(service, client, b)).Should(Succeed())
+	})
+
+	It("subscribes to the topic filter", func() {
+		Ω(client.filters).Should(HaveKey("devices/+/telemetry"))
+	})
+
+	It("sends the messages to the action and publishes the results", func() {
+		client.filters["devices/+/telemetry"](&mqtt.Message{Topic: "devices/42/telemetry", Payload: []byte(`{"temp":21}`)})
+		Ω(body).Should(Equal(`{"temp":21}`))
+		Ω(query.Get("deviceID")).Should(Equal("42"))
+		Ω(client.published).Should(HaveLen(1))
+		Ω(client.published[0].Topic).Should(Equal("devices/42/ack"))
+		Ω(string(client.published[0].Payload)).Should(Equal(`{"ok":true}`))
+	})
+
+	It("does not publish the error responses", func() {
+		status = 400
+		client.filters["devices/+/telemetry"](&mqtt.Message{Topic: "devices/42/telemetry", Payload: []byte(`{}`)})
+		Ω(client.published).Should(BeEmpty())
+	})
+})
@@ -1,8 +1,14 @@
 package goa
 
 import (
+	"bytes"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
+
+	"golang.org/x/net/context"
 
 	"github.com/dimfeld/httptreemux"
 )
@@ -84,3 +90,87 @@ func (m *mux) Lookup(method, path string) MuxHandler {
 func (m *mux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	m.router.ServeHTTP(rw, req)
 }
+
+// HeadHandler returns a mux handler that handles HEAD requests by calling the given GET handler
+// and discarding the response body so that the response headers are identical.
+func HeadHandler(get MuxHandler) MuxHandler {
+	return func(rw http.ResponseWriter, req *http.Request, params url.Values) {
+		get(headResponseWriter{rw}, req, params)
+	}
+}
+
+// OptionsHandler returns a handler that responds to OPTIONS requests with the Allow header set
+// to the given HTTP methods.
+func OptionsHandler(methods ...string) Handler {
+	allow := strings.Join(methods, ", ")
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Allow", allow)
+		rw.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+// headResponseWriter is the response writer used to handle HEAD requests, it discards the body.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Write discards the body and reports it as written.
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// MethodOverrideMux returns a mux that routes the POST requests carrying an overriding PUT, PATCH
+// or DELETE method in the given header or in the given field of a URL encoded form body as if
+// they used that method. An empty header or field name disables the corresponding source. The
+// request body is left intact so that the form can still be decoded. MethodOverrideMux returns
+// mux unchanged if it already handles method overrides.
+func MethodOverrideMux(mux ServeMux, header, field string) ServeMux {
+	if _, ok := mux.(*methodOverrideMux); ok {
+		return mux
+	}
+	return &methodOverrideMux{ServeMux: mux, header: header, field: field}
+}
+
+// methodOverrideMux is the ServeMux that handles method overrides.
+type methodOverrideMux struct {
+	ServeMux
+	header, field string
+}
+
+// ServeHTTP overrides the request method if needed before routing the request.
+func (m *methodOverrideMux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method == "POST" {
+		if method := m.overridingMethod(req); method != "" {
+			req.Method = method
+		}
+	}
+	m.ServeMux.ServeHTTP(rw, req)
+}
+
+// overridingMethod returns the method carried by the request if it is one of the methods that
+// can be tunneled, the empty string otherwise.
+func (m *methodOverrideMux) overridingMethod(req *http.Request) string {
+	var method string
+	if m.header != "" {
+		method = req.Header.Get(m.header)
+	}
+	if method == "" && m.field != "" && req.Body != nil {
+		ct, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if ct == "application/x-www-form-urlencoded" {
+			body, err := ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			if err == nil {
+				if vals, err := url.ParseQuery(string(body)); err == nil {
+					method = vals.Get(m.field)
+				}
+			}
+		}
+	}
+	switch method = strings.ToUpper(method); method {
+	case "PUT", "PATCH", "DELETE":
+		return method
+	}
+	return ""
+}
@@ -66,4 +66,98 @@ var _ = Describe("Mux", func() {
 		})
 	})
 
+	Context("with a HEAD handler", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("HEAD", "/foo", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			get := func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				rw.Header().Set("X-Foo", "bar")
+				rw.WriteHeader(200)
+				rw.Write([]byte("some body"))
+			}
+			mux.Handle("GET", "/foo", get)
+			mux.Handle("HEAD", "/foo", goa.HeadHandler(get))
+		})
+
+		It("sends the GET headers without the body", func() {
+			Ω(rw.Status).Should(Equal(200))
+			Ω(rw.ParentHeader.Get("X-Foo")).Should(Equal("bar"))
+			Ω(rw.Body).Should(BeEmpty())
+		})
+	})
+
+	Context("with an OPTIONS handler", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest("OPTIONS", "/foo", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			ctrl := goa.New("test").NewController("test")
+			mux.Handle("OPTIONS", "/foo", ctrl.MuxHandler("options", goa.OptionsHandler("GET", "OPTIONS"), nil))
+		})
+
+		It("sets the Allow header", func() {
+			Ω(rw.Status).Should(Equal(200))
+			Ω(rw.ParentHeader.Get("Allow")).Should(Equal("GET, OPTIONS"))
+		})
+	})
+
+	Context("with method overrides", func() {
+		var readMeth, readBody string
+
+		BeforeEach(func() {
+			readMeth, readBody = "", ""
+			mux.Handle("DELETE", "/foo", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				b, err := ioutil.ReadAll(req.Body)
+				Ω(err).ShouldNot(HaveOccurred())
+				readMeth = req.Method
+				readBody = string(b)
+			})
+			mux = goa.MethodOverrideMux(mux, "X-HTTP-Method-Override", "_method")
+		})
+
+		Context("using the header", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("POST", "/foo", &bytes.Buffer{})
+				Ω(err).ShouldNot(HaveOccurred())
+				req.Header.Set("X-HTTP-Method-Override", "delete")
+			})
+
+			It("routes the request using the overriding method", func() {
+				Ω(readMeth).Should(Equal("DELETE"))
+			})
+		})
+
+		Context("using the form field", func() {
+			const body = "_method=DELETE&name=foo"
+
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("POST", "/foo", bytes.NewBufferString(body))
+				Ω(err).ShouldNot(HaveOccurred())
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			})
+
+			It("routes the request and preserves the body", func() {
+				Ω(readMeth).Should(Equal("DELETE"))
+				Ω(readBody).Should(Equal(body))
+			})
+		})
+
+		Context("with a method that cannot be tunneled", func() {
+			BeforeEach(func() {
+				var err error
+				req, err = http.NewRequest("POST", "/foo", &bytes.Buffer{})
+				Ω(err).ShouldNot(HaveOccurred())
+				req.Header.Set("X-HTTP-Method-Override", "GET")
+			})
+
+			It("does not override the method", func() {
+				Ω(readMeth).Should(BeEmpty())
+				Ω(rw.Status).Should(Equal(405))
+			})
+		})
+	})
+
 })
@@ -0,0 +1,140 @@
+package goa
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// The functions below convert the values of the Int64, UInt64, BigInt and Decimal design
+// primitives and of the numeric attributes encoded as strings (see the StringEncoded DSL) between
+// their wire and Go representations.
+
+// ParseInt64 converts an integer wire value into an int64.
+func ParseInt64(i int) (int64, error) {
+	return int64(i), nil
+}
+
+// FormatInt64 converts an int64 into an integer wire value.
+func FormatInt64(i int64) int {
+	return int(i)
+}
+
+// ParseUint64 converts an integer wire value into an uint64.
+func ParseUint64(i int) (uint64, error) {
+	if i < 0 {
+		return 0, fmt.Errorf("negative value %d cannot be converted to an unsigned integer", i)
+	}
+	return uint64(i), nil
+}
+
+// FormatUint64 converts an uint64 into an integer wire value.
+func FormatUint64(i uint64) int {
+	return int(i)
+}
+
+// ParseIntString parses the decimal representation of an int.
+func ParseIntString(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+// FormatIntString returns the decimal representation of i.
+func FormatIntString(i int) string {
+	return strconv.Itoa(i)
+}
+
+// ParseFloatString parses the decimal representation of a float64.
+func ParseFloatString(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// FormatFloatString returns the shortest decimal representation of f.
+func FormatFloatString(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// ParseInt64String parses the decimal representation of an int64.
+func ParseInt64String(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// FormatInt64String returns the decimal representation of i.
+func FormatInt64String(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+// ParseUint64String parses the decimal representation of an uint64.
+func ParseUint64String(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// FormatUint64String returns the decimal representation of i.
+func FormatUint64String(i uint64) string {
+	return strconv.FormatUint(i, 10)
+}
+
+// ParseBigInt parses the decimal representation of an arbitrary precision integer.
+func ParseBigInt(s string) (*big.Int, error) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %#v", s)
+	}
+	return i, nil
+}
+
+// FormatBigInt returns the decimal representation of i, the empty string if i is nil.
+func FormatBigInt(i *big.Int) string {
+	if i == nil {
+		return ""
+	}
+	return i.String()
+}
+
+// ParseDecimal parses a decimal number such as "-12.345" into an exact rational number. Fractions
+// ("1/3") and exponents are not accepted.
+func ParseDecimal(s string) (*big.Rat, error) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '/' || c == 'e' || c == 'E' {
+			return nil, fmt.Errorf("invalid decimal %#v", s)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal %#v", s)
+	}
+	return r, nil
+}
+
+// FormatDecimal returns the decimal representation of r, the empty string if r is nil. The
+// representation is exact if r is a decimal number (its denominator only has 2 and 5 as prime
+// factors) and rounded to 20 decimal places otherwise.
+func FormatDecimal(r *big.Rat) string {
+	if r == nil {
+		return ""
+	}
+	return r.FloatString(decimalPlaces(r.Denom()))
+}
+
+// decimalPlaces returns the number of decimal places needed to represent a rational number with
+// denominator d exactly, 20 if it cannot be.
+func decimalPlaces(d *big.Int) int {
+	d = new(big.Int).Set(d)
+	var twos, fives int
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for d.Cmp(big.NewInt(1)) > 0 {
+		switch {
+		case rem.Mod(d, two).Sign() == 0:
+			d.Quo(d, two)
+			twos++
+		case rem.Mod(d, five).Sign() == 0:
+			d.Quo(d, five)
+			fives++
+		default:
+			return 20
+		}
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
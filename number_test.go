@@ -0,0 +1,49 @@
+package goa_test
+
+import (
+	"math/big"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("decimals", func() {
+	It("round trips decimal numbers exactly", func() {
+		for _, s := range []string{"0", "12.3", "-0.0001", "123456789012345678901234567890.125"} {
+			r, err := goa.ParseDecimal(s)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(goa.FormatDecimal(r)).Should(Equal(s))
+		}
+	})
+
+	It("rejects fractions and exponents", func() {
+		for _, s := range []string{"1/3", "1e3", "", "abc"} {
+			_, err := goa.ParseDecimal(s)
+			Ω(err).Should(HaveOccurred(), s)
+		}
+	})
+
+	It("rounds non decimal numbers", func() {
+		Ω(goa.FormatDecimal(big.NewRat(1, 3))).Should(Equal("0.33333333333333333333"))
+		Ω(goa.FormatDecimal(nil)).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("64 bit integers", func() {
+	It("parses unsigned integers", func() {
+		i, err := goa.ParseUint64String("18446744073709551615")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(i).Should(Equal(uint64(18446744073709551615)))
+		_, err = goa.ParseUint64(-1)
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("parses big integers", func() {
+		i, err := goa.ParseBigInt("123456789012345678901234567890")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(goa.FormatBigInt(i)).Should(Equal("123456789012345678901234567890"))
+		_, err = goa.ParseBigInt("1.5")
+		Ω(err).Should(HaveOccurred())
+	})
+})
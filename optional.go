@@ -0,0 +1,225 @@
+package goa
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// The Null* types are the fields generated for the optional attributes of the types that use the
+// nullable field policy (see the OptionalFields DSL). Valid is false when the attribute is absent
+// from the request. The types encode to and decode from the JSON null value and implement the
+// database/sql Scanner and driver Valuer interfaces so they can be stored as is.
+type (
+	// NullString is an optional string attribute.
+	NullString struct {
+		String string
+		Valid  bool
+	}
+
+	// NullInt is an optional integer attribute.
+	NullInt struct {
+		Int   int
+		Valid bool
+	}
+
+	// NullFloat64 is an optional number attribute.
+	NullFloat64 struct {
+		Float64 float64
+		Valid   bool
+	}
+
+	// NullBool is an optional boolean attribute.
+	NullBool struct {
+		Bool  bool
+		Valid bool
+	}
+
+	// NullTime is an optional date time attribute.
+	NullTime struct {
+		Time  time.Time
+		Valid bool
+	}
+
+	// Presence is the bitmap that records the optional attributes present in a request for the
+	// types that use the presence field policy. Bit i is set when the i-th optional attribute in
+	// alphabetical order is present.
+	Presence struct {
+		bits []uint64
+	}
+)
+
+// Set records the presence of the i-th attribute.
+func (p *Presence) Set(i int) {
+	for len(p.bits) <= i/64 {
+		p.bits = append(p.bits, 0)
+	}
+	p.bits[i/64] |= 1 << uint(i%64)
+}
+
+// Has returns true if the i-th attribute is present.
+func (p Presence) Has(i int) bool {
+	if i/64 >= len(p.bits) {
+		return false
+	}
+	return p.bits[i/64]&(1<<uint(i%64)) != 0
+}
+
+// MarshalJSON encodes the string or null if not valid.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.String)
+}
+
+// UnmarshalJSON decodes the string, null invalidates n.
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.String, &n.Valid)
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullString) Scan(value interface{}) error {
+	var s sql.NullString
+	err := s.Scan(value)
+	n.String, n.Valid = s.String, s.Valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullString) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.String, nil
+}
+
+// MarshalJSON encodes the integer or null if not valid.
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Int)
+}
+
+// UnmarshalJSON decodes the integer, null invalidates n.
+func (n *NullInt) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Int, &n.Valid)
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullInt) Scan(value interface{}) error {
+	var i sql.NullInt64
+	err := i.Scan(value)
+	n.Int, n.Valid = int(i.Int64), i.Valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullInt) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Int), nil
+}
+
+// MarshalJSON encodes the number or null if not valid.
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Float64)
+}
+
+// UnmarshalJSON decodes the number, null invalidates n.
+func (n *NullFloat64) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Float64, &n.Valid)
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullFloat64) Scan(value interface{}) error {
+	var f sql.NullFloat64
+	err := f.Scan(value)
+	n.Float64, n.Valid = f.Float64, f.Valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullFloat64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Float64, nil
+}
+
+// MarshalJSON encodes the boolean or null if not valid.
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Bool)
+}
+
+// UnmarshalJSON decodes the boolean, null invalidates n.
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Bool, &n.Valid)
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullBool) Scan(value interface{}) error {
+	var v sql.NullBool
+	err := v.Scan(value)
+	n.Bool, n.Valid = v.Bool, v.Valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullBool) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Bool, nil
+}
+
+// MarshalJSON encodes the date time or null if not valid.
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	return marshalNull(n.Valid, n.Time)
+}
+
+// UnmarshalJSON decodes the date time, null invalidates n.
+func (n *NullTime) UnmarshalJSON(b []byte) error {
+	return unmarshalNull(b, &n.Time, &n.Valid)
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullTime) Scan(value interface{}) error {
+	if value == nil {
+		n.Time, n.Valid = time.Time{}, false
+		return nil
+	}
+	t, ok := value.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into NullTime", value)
+	}
+	n.Time, n.Valid = t, true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time, nil
+}
+
+// marshalNull encodes v or null if valid is false.
+func marshalNull(valid bool, v interface{}) ([]byte, error) {
+	if !valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalNull decodes b into v and sets valid unless b is null.
+func unmarshalNull(b []byte, v interface{}, valid *bool) error {
+	if string(b) == "null" {
+		*valid = false
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	*valid = true
+	return nil
+}
@@ -0,0 +1,65 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NullString", func() {
+	type payload struct {
+		Name goa.NullString `json:"name"`
+	}
+
+	It("decodes absent, null and set values", func() {
+		var p payload
+		Ω(json.Unmarshal([]byte(`{}`), &p)).ShouldNot(HaveOccurred())
+		Ω(p.Name.Valid).Should(BeFalse())
+		Ω(json.Unmarshal([]byte(`{"name":null}`), &p)).ShouldNot(HaveOccurred())
+		Ω(p.Name.Valid).Should(BeFalse())
+		Ω(json.Unmarshal([]byte(`{"name":""}`), &p)).ShouldNot(HaveOccurred())
+		Ω(p.Name).Should(Equal(goa.NullString{String: "", Valid: true}))
+	})
+
+	It("encodes invalid values as null", func() {
+		b, err := json.Marshal(payload{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"name":null}`))
+		b, err = json.Marshal(payload{Name: goa.NullString{String: "foo", Valid: true}})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`{"name":"foo"}`))
+	})
+
+	It("implements the sql interfaces", func() {
+		var n goa.NullString
+		Ω(n.Scan("foo")).ShouldNot(HaveOccurred())
+		Ω(n).Should(Equal(goa.NullString{String: "foo", Valid: true}))
+		Ω(n.Scan(nil)).ShouldNot(HaveOccurred())
+		Ω(n.Valid).Should(BeFalse())
+		v, err := n.Value()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(v).Should(BeNil())
+	})
+})
+
+var _ = Describe("NullInt", func() {
+	It("decodes zero values as valid", func() {
+		var n goa.NullInt
+		Ω(json.Unmarshal([]byte(`0`), &n)).ShouldNot(HaveOccurred())
+		Ω(n).Should(Equal(goa.NullInt{Int: 0, Valid: true}))
+	})
+})
+
+var _ = Describe("Presence", func() {
+	It("records the present attributes", func() {
+		var p goa.Presence
+		p.Set(1)
+		p.Set(70)
+		Ω(p.Has(0)).Should(BeFalse())
+		Ω(p.Has(1)).Should(BeTrue())
+		Ω(p.Has(70)).Should(BeTrue())
+		Ω(p.Has(200)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"errors"
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/cloudevents"
+)
+
+// ErrTxDone is the error returned when using a transaction that is already committed or rolled
+// back.
+var ErrTxDone = errors.New("outbox transaction already committed or rolled back")
+
+type (
+	// MemoryStore is a Store that keeps the events in memory. It is meant for tests and
+	// prototypes: the events do not commit atomically with any other change.
+	MemoryStore struct {
+		mu     sync.Mutex
+		events []*cloudevents.Event
+	}
+
+	// memoryTx is a transaction of a MemoryStore.
+	memoryTx struct {
+		store  *MemoryStore
+		events []*cloudevents.Event
+		done   bool
+	}
+)
+
+// NewMemoryStore returns an empty memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Begin starts a transaction.
+func (s *MemoryStore) Begin(context.Context) (Tx, error) {
+	return &memoryTx{store: s}, nil
+}
+
+// Pending returns up to limit committed events in the order they were recorded.
+func (s *MemoryStore) Pending(_ context.Context, limit int) ([]*cloudevents.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.events)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	return append([]*cloudevents.Event(nil), s.events[:n]...), nil
+}
+
+// Delete removes the events with the given identifiers.
+func (s *MemoryStore) Delete(_ context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	events := s.events[:0]
+	for _, e := range s.events {
+		if !deleted[e.ID] {
+			events = append(events, e)
+		}
+	}
+	s.events = events
+	return nil
+}
+
+// Record adds the event to the transaction.
+func (tx *memoryTx) Record(_ context.Context, e *cloudevents.Event) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.events = append(tx.events, e)
+	return nil
+}
+
+// Commit adds the recorded events to the store.
+func (tx *memoryTx) Commit() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.store.mu.Lock()
+	tx.store.events = append(tx.store.events, tx.events...)
+	tx.store.mu.Unlock()
+	return nil
+}
+
+// Rollback discards the recorded events.
+func (tx *memoryTx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.events = nil
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Package outbox implements the transactional outbox pattern for the actions designed with Outbox.
+The requests of these actions run in a transaction of the store registered with UseStore: the
+action implementation writes its changes and records the resulting events with the transaction so
+that both commit atomically. The events are not published by the request, a Relay periodically
+reads the committed events from the store and delivers them to a cloudevents.Publisher.
+
+Store implementations typically wrap a database transaction: the events are inserted in an outbox
+table of the database that holds the service data and the Tx returned by Begin exposes the
+underlying transaction to the action implementations.
+*/
+package outbox
+
+import (
+	"bytes"
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/cloudevents"
+)
+
+type (
+	// Store is the interface implemented by the outbox stores.
+	Store interface {
+		// Begin starts a transaction.
+		Begin(ctx context.Context) (Tx, error)
+		// Pending returns up to limit events recorded by committed transactions that have
+		// not been relayed yet in the order they were recorded.
+		Pending(ctx context.Context, limit int) ([]*cloudevents.Event, error)
+		// Delete removes the events with the given identifiers from the outbox once they
+		// are relayed.
+		Delete(ctx context.Context, ids ...string) error
+	}
+
+	// Tx is a transaction of an outbox store.
+	Tx interface {
+		// Record adds the event to the outbox, the event is relayed once the transaction
+		// commits.
+		Record(ctx context.Context, e *cloudevents.Event) error
+		// Commit commits the transaction.
+		Commit() error
+		// Rollback aborts the transaction and discards the recorded events.
+		Rollback() error
+	}
+
+	// bufferedWriter is the response writer that holds the response until the transaction
+	// commits.
+	bufferedWriter struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+
+	// storeKey is the key used to store the outbox store in the service context.
+	storeKey struct{}
+
+	// txKey is the key used to store the transaction in the request context.
+	txKey struct{}
+)
+
+// UseStore registers the outbox store with the service. UseStore must be called before the
+// controllers are created.
+func UseStore(service *goa.Service, s Store) {
+	service.Context = context.WithValue(service.Context, storeKey{}, s)
+}
+
+// ContextTx returns the transaction of the request, nil if the request does not run in a
+// transaction.
+func ContextTx(ctx context.Context) Tx {
+	if tx, ok := ctx.Value(txKey{}).(Tx); ok {
+		return tx
+	}
+	return nil
+}
+
+// Transactional returns a middleware that runs the requests in a transaction of the store
+// registered with UseStore, ContextTx returns the transaction in the handler context. The response
+// is held until the handler returns: the transaction commits before the response is sent if the
+// handler succeeds and rolls back otherwise. The request fails with the commit error and the held
+// response is discarded if the transaction cannot commit. The requests fail with a 500 response if
+// no store is registered with UseStore.
+func Transactional() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			s, ok := ctx.Value(storeKey{}).(Store)
+			if !ok {
+				return goa.ErrInternal("no outbox store, make sure outbox.UseStore is called before mounting the controllers")
+			}
+			tx, err := s.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			resp := goa.ContextResponse(ctx)
+			w := resp.SwitchWriter(nil)
+			buf := &bufferedWriter{header: make(http.Header)}
+			resp.SwitchWriter(buf)
+			err = h(context.WithValue(ctx, txKey{}, tx), rw, req)
+			resp.SwitchWriter(w)
+			if err != nil || resp.Status >= 400 {
+				if rerr := tx.Rollback(); rerr != nil {
+					goa.LogError(ctx, "failed to roll back outbox transaction", "err", rerr)
+				}
+				if err != nil {
+					return err
+				}
+				return buf.flush(w)
+			}
+			if err := tx.Commit(); err != nil {
+				resp.Status = 0
+				resp.Length = 0
+				return err
+			}
+			return buf.flush(w)
+		}
+	}
+}
+
+// Header returns the header of the held response.
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status of the held response.
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write appends b to the body of the held response.
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// flush writes the held response to rw.
+func (w *bufferedWriter) flush(rw http.ResponseWriter) error {
+	if w.status == 0 {
+		return nil
+	}
+	h := rw.Header()
+	for k, v := range w.header {
+		h[k] = v
+	}
+	rw.WriteHeader(w.status)
+	_, err := rw.Write(w.body.Bytes())
+	return err
+}
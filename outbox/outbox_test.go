@@ -0,0 +1,163 @@
+package outbox_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/cloudevents"
+	"github.com/goadesign/goa/outbox"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// failingStore is a store whose transactions fail to commit.
+type failingStore struct {
+	*outbox.MemoryStore
+}
+
+type failingTx struct {
+	outbox.Tx
+}
+
+func (s failingStore) Begin(ctx context.Context) (outbox.Tx, error) {
+	tx, _ := s.MemoryStore.Begin(ctx)
+	return failingTx{tx}, nil
+}
+
+func (failingTx) Commit() error {
+	return errors.New("commit failed")
+}
+
+var _ = Describe("Transactional", func() {
+	var service *goa.Service
+	var store *outbox.MemoryStore
+	var status int
+	var handlerErr error
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		store = outbox.NewMemoryStore()
+		outbox.UseStore(service, store)
+		status = http.StatusCreated
+		handlerErr = nil
+	})
+
+	serve := func() (*httptest.ResponseRecorder, *goa.ResponseData, error) {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			tx := outbox.ContextTx(ctx)
+			Ω(tx).ShouldNot(BeNil())
+			Ω(tx.Record(ctx, cloudevents.New("test.created", "/test", "", nil))).Should(Succeed())
+			if handlerErr != nil {
+				return handlerErr
+			}
+			resp := goa.ContextResponse(ctx)
+			resp.Header().Set("Location", "/things/1")
+			resp.WriteHeader(status)
+			resp.Write([]byte("body"))
+			return nil
+		}
+		req, _ := http.NewRequest("POST", "/things", nil)
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(service.Context, rw, req, nil)
+		err := outbox.Transactional()(h)(ctx, goa.ContextResponse(ctx), req)
+		return rw, goa.ContextResponse(ctx), err
+	}
+
+	It("commits the events before sending the response", func() {
+		rw, _, err := serve()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusCreated))
+		Ω(rw.Header().Get("Location")).Should(Equal("/things/1"))
+		Ω(rw.Body.String()).Should(Equal("body"))
+		events, _ := store.Pending(context.Background(), 0)
+		Ω(events).Should(HaveLen(1))
+		Ω(events[0].Type).Should(Equal("test.created"))
+	})
+
+	It("rolls back the transaction of failed requests", func() {
+		status = http.StatusBadRequest
+		rw, _, err := serve()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(http.StatusBadRequest))
+		Ω(rw.Body.String()).Should(Equal("body"))
+		Ω(store.Pending(context.Background(), 0)).Should(BeEmpty())
+	})
+
+	It("rolls back the transaction when the handler fails", func() {
+		handlerErr = errors.New("boom")
+		_, _, err := serve()
+		Ω(err).Should(Equal(handlerErr))
+		Ω(store.Pending(context.Background(), 0)).Should(BeEmpty())
+	})
+
+	It("discards the response when the transaction fails to commit", func() {
+		outbox.UseStore(service, failingStore{store})
+		rw, resp, err := serve()
+		Ω(err).Should(MatchError("commit failed"))
+		Ω(resp.Written()).Should(BeFalse())
+		Ω(rw.Body.String()).Should(BeEmpty())
+		Ω(rw.Header().Get("Location")).Should(BeEmpty())
+	})
+
+	It("fails when no store is registered", func() {
+		service = goa.New("test")
+		_, _, err := serve()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(http.StatusInternalServerError))
+	})
+})
+
+var _ = Describe("Relay", func() {
+	var store *outbox.MemoryStore
+	var published []string
+	var failOn string
+	var relay *outbox.Relay
+
+	BeforeEach(func() {
+		store = outbox.NewMemoryStore()
+		published = nil
+		failOn = ""
+		relay = outbox.NewRelay(store, cloudevents.PublisherFunc(func(_ context.Context, e *cloudevents.Event) error {
+			if e.Type == failOn {
+				return errors.New("unavailable")
+			}
+			published = append(published, e.Type)
+			return nil
+		}))
+		tx, _ := store.Begin(context.Background())
+		for _, t := range []string{"a", "b", "c"} {
+			tx.Record(context.Background(), cloudevents.New(t, "/test", "", nil))
+		}
+		Ω(tx.Commit()).Should(Succeed())
+	})
+
+	It("publishes the pending events in order", func() {
+		n, err := relay.Flush(context.Background())
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(n).Should(Equal(3))
+		Ω(published).Should(Equal([]string{"a", "b", "c"}))
+		Ω(store.Pending(context.Background(), 0)).Should(BeEmpty())
+	})
+
+	It("stops at the first event that fails to publish", func() {
+		failOn = "b"
+		n, err := relay.Flush(context.Background())
+		Ω(err).Should(MatchError("unavailable"))
+		Ω(n).Should(Equal(1))
+		pending, _ := store.Pending(context.Background(), 0)
+		Ω(pending).Should(HaveLen(2))
+		Ω(pending[0].Type).Should(Equal("b"))
+	})
+
+	It("relays the events in batches", func() {
+		relay.BatchSize = 2
+		n, err := relay.Flush(context.Background())
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(n).Should(Equal(2))
+		Ω(published).Should(Equal([]string{"a", "b"}))
+	})
+})
@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/cloudevents"
+)
+
+const (
+	// DefaultInterval is the default duration between two relay runs.
+	DefaultInterval = time.Second
+
+	// DefaultBatchSize is the default maximum number of events delivered by a relay run.
+	DefaultBatchSize = 100
+)
+
+// Relay delivers the events recorded in an outbox store to a publisher. The events are delivered
+// at least once: an event is removed from the store once published, a failure to remove it causes
+// it to be published again on the next run.
+type Relay struct {
+	// Store is the outbox store.
+	Store Store
+	// Publisher delivers the events.
+	Publisher cloudevents.Publisher
+	// Interval is the duration between two runs, DefaultInterval if zero.
+	Interval time.Duration
+	// BatchSize is the maximum number of events delivered by a run, DefaultBatchSize if zero.
+	BatchSize int
+}
+
+// NewRelay returns a relay that delivers the events of the store to the publisher with the
+// default interval and batch size.
+func NewRelay(s Store, p cloudevents.Publisher) *Relay {
+	return &Relay{Store: s, Publisher: p, Interval: DefaultInterval, BatchSize: DefaultBatchSize}
+}
+
+// Run relays the events until ctx is done. Errors are logged and the failed events retried on the
+// next run.
+func (r *Relay) Run(ctx context.Context) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Flush(ctx); err != nil {
+			goa.LogError(ctx, "failed to relay outbox events", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes one batch of pending events in order and removes them from the store. It stops
+// at the first event that fails to publish so that the events are delivered in order and returns
+// the number of events relayed.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	size := r.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	events, err := r.Store.Pending(ctx, size)
+	if err != nil {
+		return 0, err
+	}
+	var ids []string
+	var perr error
+	for _, e := range events {
+		if perr = r.Publisher.Publish(ctx, e); perr != nil {
+			break
+		}
+		ids = append(ids, e.ID)
+	}
+	if len(ids) > 0 {
+		if err := r.Store.Delete(ctx, ids...); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), perr
+}
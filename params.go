@@ -0,0 +1,21 @@
+package goa
+
+import "strings"
+
+// SplitParams splits the values of an array parameter serialized with delimited values, e.g.
+// ?id=1,2, into the individual values. Each value is split so that repeated parameters are also
+// accepted, e.g. ?id=1,2&id=3 produces the values 1, 2 and 3. The code generated for the array
+// parameters that use a delimited style calls SplitParams prior to coercing the values.
+func SplitParams(values []string, delimiter string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	var res []string
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		res = append(res, strings.Split(v, delimiter)...)
+	}
+	return res
+}
@@ -0,0 +1,22 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SplitParams", func() {
+	It("splits delimited values", func() {
+		Ω(goa.SplitParams([]string{"1,2"}, ",")).Should(Equal([]string{"1", "2"}))
+	})
+
+	It("accepts repeated parameters", func() {
+		Ω(goa.SplitParams([]string{"1|2", "3"}, "|")).Should(Equal([]string{"1", "2", "3"}))
+	})
+
+	It("ignores empty values", func() {
+		Ω(goa.SplitParams([]string{""}, ",")).Should(BeEmpty())
+		Ω(goa.SplitParams(nil, ",")).Should(BeEmpty())
+	})
+})
@@ -1,6 +1,10 @@
 package goa
 
-import "golang.org/x/net/context"
+import (
+	"strings"
+
+	"golang.org/x/net/context"
+)
 
 // Location is the enum defining where the value of key based security schemes should be read:
 // either a HTTP request header or a URL querystring value
@@ -27,6 +31,66 @@ func WithRequiredScopes(ctx context.Context, scopes []string) context.Context {
 	return context.WithValue(ctx, securityScopesKey, scopes)
 }
 
+// ContextClaims extracts the claims of the authenticated principal from the given context.
+func ContextClaims(ctx context.Context) map[string]interface{} {
+	if c := ctx.Value(securityClaimsKey); c != nil {
+		return c.(map[string]interface{})
+	}
+	return nil
+}
+
+// WithClaims builds a context containing the given claims. Auth middlewares store the claims of
+// the authenticated principal so that Authorize may check the required scopes and roles.
+func WithClaims(ctx context.Context, claims map[string]interface{}) context.Context {
+	return context.WithValue(ctx, securityClaimsKey, claims)
+}
+
+// Authorize checks that the claims stored in the context grant all the given scopes and roles.
+// Scopes are read from the "scopes" claim and roles from the "roles" claim, both may either be
+// a list of strings or a single string with space-separated values. Scopes are only checked when
+// claims are present as auth middlewares that do not record claims validate scopes themselves.
+// Authorize returns an ErrForbidden error when a scope or role is missing.
+func Authorize(ctx context.Context, scopes, roles []string) error {
+	claims := ContextClaims(ctx)
+	if claims != nil {
+		if missing := missingClaims(claims["scopes"], scopes); len(missing) > 0 {
+			return ErrForbidden("authorization failed: required scopes not granted", "required", scopes, "missing", missing)
+		}
+	}
+	if missing := missingClaims(claims["roles"], roles); len(missing) > 0 {
+		return ErrForbidden("authorization failed: required roles not granted", "required", roles, "missing", missing)
+	}
+	return nil
+}
+
+// missingClaims returns the required values not present in the given claim value.
+func missingClaims(claim interface{}, required []string) []string {
+	granted := make(map[string]bool)
+	switch c := claim.(type) {
+	case string:
+		for _, v := range strings.Fields(c) {
+			granted[v] = true
+		}
+	case []string:
+		for _, v := range c {
+			granted[v] = true
+		}
+	case []interface{}:
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				granted[s] = true
+			}
+		}
+	}
+	var missing []string
+	for _, r := range required {
+		if !granted[r] {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
 // OAuth2Security represents the `oauth2` security scheme. It is instantiated by the generated code
 // accordingly to the use of the different `*Security()` DSL functions and `Security()` in the
 // design.
@@ -75,3 +139,12 @@ type JWTSecurity struct {
 	// Scopes defines a list of scopes for the security scheme, along with their description.
 	Scopes map[string]string
 }
+
+// SessionSecurity represents the session security scheme. Clients authenticate with the session
+// cookie set on login, the session data is kept server side in a session store.
+type SessionSecurity struct {
+	// Description of the security scheme
+	Description string
+	// CookieName is the name of the cookie that holds the session ID.
+	CookieName string
+}
@@ -0,0 +1,93 @@
+package goa_test
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorize", func() {
+	var ctx context.Context
+	var scopes, roles []string
+	var err error
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scopes = nil
+		roles = nil
+	})
+
+	JustBeforeEach(func() {
+		err = goa.Authorize(ctx, scopes, roles)
+	})
+
+	Context("with no claims", func() {
+		BeforeEach(func() {
+			scopes = []string{"read:bottles"}
+		})
+
+		It("leaves scopes to the auth middleware", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("and required roles", func() {
+			BeforeEach(func() {
+				roles = []string{"admin"}
+			})
+
+			It("returns a forbidden error", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(403))
+			})
+		})
+	})
+
+	Context("with claims", func() {
+		BeforeEach(func() {
+			ctx = goa.WithClaims(ctx, map[string]interface{}{
+				"scopes": "read:bottles write:bottles",
+				"roles":  []interface{}{"admin"},
+			})
+		})
+
+		It("stores the claims in the context", func() {
+			Ω(goa.ContextClaims(ctx)).Should(HaveKey("roles"))
+		})
+
+		Context("granting the required scopes and roles", func() {
+			BeforeEach(func() {
+				scopes = []string{"read:bottles", "write:bottles"}
+				roles = []string{"admin"}
+			})
+
+			It("authorizes the request", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("missing a required scope", func() {
+			BeforeEach(func() {
+				scopes = []string{"delete:bottles"}
+			})
+
+			It("returns a forbidden error", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(403))
+				Ω(err.Error()).Should(ContainSubstring("delete:bottles"))
+			})
+		})
+
+		Context("missing a required role", func() {
+			BeforeEach(func() {
+				roles = []string{"owner"}
+			})
+
+			It("returns a forbidden error", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring("owner"))
+			})
+		})
+	})
+})
@@ -34,6 +34,9 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
+		// Maintenance is the switch used to put endpoints in maintenance mode, it only
+		// applies to the actions of APIs that declare a maintenance response.
+		Maintenance *MaintenanceSwitch
 
 		middleware []Middleware       // Middleware chain
 		cancel     context.CancelFunc // Service context cancel signal trigger
@@ -84,6 +87,8 @@ func New(name string) *Service {
 			Decoder: NewHTTPDecoder(),
 			Encoder: NewHTTPEncoder(),
 
+			Maintenance: NewMaintenanceSwitch(),
+
 			cancel: cancel,
 		}
 		notFoundHandler Handler
@@ -191,6 +196,9 @@ func (service *Service) DecodeRequest(req *http.Request, v interface{}) error {
 	defer body.Close()
 
 	if err := service.Decoder.Decode(v, body, contentType); err != nil {
+		if isDecompressionError(err) {
+			return err
+		}
 		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
 	}
 
@@ -267,7 +275,7 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 				if err.Error() == "http: request body too large" {
 					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
 					err = ErrRequestBodyTooLarge(msg)
-				} else {
+				} else if !isDecompressionError(err) {
 					err = ErrBadRequest(err)
 				}
 				ctx = WithError(ctx, err)
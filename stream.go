@@ -0,0 +1,94 @@
+package goa
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PayloadStream decodes the elements of a streaming request payload one at a time. The request
+// body may either be a JSON array or a sequence of newline delimited JSON values (NDJSON). The
+// code generated for actions that define a streaming payload uses a PayloadStream to implement the
+// Recv method of the action stream.
+type PayloadStream struct {
+	r       *bufio.Reader
+	dec     *json.Decoder
+	array   bool
+	started bool
+	done    bool
+}
+
+// NewPayloadStream returns a stream that decodes the elements read from body.
+func NewPayloadStream(body io.Reader) *PayloadStream {
+	r := bufio.NewReader(body)
+	return &PayloadStream{r: r, dec: json.NewDecoder(r)}
+}
+
+// Decode decodes the next element of the stream into v. It returns io.EOF once all the elements
+// have been decoded and an ErrInvalidEncoding error if the body is not a valid stream.
+func (s *PayloadStream) Decode(v interface{}) error {
+	if s.done {
+		return io.EOF
+	}
+	if !s.started {
+		s.started = true
+		if err := s.start(); err != nil {
+			s.done = true
+			return err
+		}
+	}
+	if s.array {
+		if !s.dec.More() {
+			s.done = true
+			if _, err := s.dec.Token(); err != nil {
+				return ErrInvalidEncoding(fmt.Sprintf("invalid streaming payload: %s", err))
+			}
+			return io.EOF
+		}
+	}
+	if err := s.dec.Decode(v); err != nil {
+		s.done = true
+		if err == io.EOF && !s.array {
+			return io.EOF
+		}
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if se, ok := err.(ServiceError); ok {
+			return se
+		}
+		if err.Error() == "http: request body too large" {
+			return ErrRequestBodyTooLarge(err)
+		}
+		return ErrInvalidEncoding(fmt.Sprintf("invalid streaming payload element: %s", err))
+	}
+	return nil
+}
+
+// start detects whether the body is a JSON array and consumes the opening bracket if so.
+func (s *PayloadStream) start() error {
+	for {
+		b, err := s.r.ReadByte()
+		if err == io.EOF {
+			return io.EOF
+		}
+		if err != nil {
+			return err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		if err := s.r.UnreadByte(); err != nil {
+			return err
+		}
+		if b == '[' {
+			s.array = true
+			if _, err := s.dec.Token(); err != nil {
+				return ErrInvalidEncoding(fmt.Sprintf("invalid streaming payload: %s", err))
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package goa_test
+
+import (
+	"io"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PayloadStream", func() {
+	type element struct {
+		Name string `json:"name"`
+	}
+	var body string
+	var elems []element
+	var streamErr error
+
+	JustBeforeEach(func() {
+		elems = nil
+		streamErr = nil
+		s := goa.NewPayloadStream(strings.NewReader(body))
+		for {
+			var e element
+			if err := s.Decode(&e); err != nil {
+				if err != io.EOF {
+					streamErr = err
+				}
+				break
+			}
+			elems = append(elems, e)
+		}
+	})
+
+	Context("with a JSON array", func() {
+		BeforeEach(func() {
+			body = ` [{"name":"a"}, {"name":"b"}]`
+		})
+
+		It("decodes the elements", func() {
+			Ω(streamErr).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal([]element{{"a"}, {"b"}}))
+		})
+	})
+
+	Context("with newline delimited JSON", func() {
+		BeforeEach(func() {
+			body = "{\"name\":\"a\"}\n{\"name\":\"b\"}\n"
+		})
+
+		It("decodes the elements", func() {
+			Ω(streamErr).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal([]element{{"a"}, {"b"}}))
+		})
+	})
+
+	Context("with an empty body", func() {
+		BeforeEach(func() {
+			body = ""
+		})
+
+		It("decodes no element", func() {
+			Ω(streamErr).ShouldNot(HaveOccurred())
+			Ω(elems).Should(BeEmpty())
+		})
+	})
+
+	Context("with an empty array", func() {
+		BeforeEach(func() {
+			body = "[]"
+		})
+
+		It("decodes no element", func() {
+			Ω(streamErr).ShouldNot(HaveOccurred())
+			Ω(elems).Should(BeEmpty())
+		})
+	})
+
+	Context("with a truncated array", func() {
+		BeforeEach(func() {
+			body = `[{"name":"a"},`
+		})
+
+		It("returns an invalid encoding error after the valid elements", func() {
+			Ω(elems).Should(Equal([]element{{"a"}}))
+			Ω(streamErr).Should(HaveOccurred())
+			Ω(streamErr.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+		})
+	})
+
+	Context("with an invalid element", func() {
+		BeforeEach(func() {
+			body = "{\"name\":\"a\"}\n{\"name\":42}\n"
+		})
+
+		It("returns an invalid encoding error", func() {
+			Ω(elems).Should(Equal([]element{{"a"}}))
+			Ω(streamErr).Should(HaveOccurred())
+			Ω(streamErr.Error()).Should(ContainSubstring("invalid streaming payload element"))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package goa
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeRequestStrict works like DecodeRequest but also fails if the request body contains
+// attributes that have no corresponding field in v. The unknown attributes are reported with
+// UnknownAttributeError errors merged into a single invalid request error. The fields of v are
+// matched using the names given in their json struct tags. The code generated for the actions of
+// designs that use the DisallowUnknownFields DSL calls DecodeRequestStrict to decode the payloads.
+//
+// The check requires the decoder registered for the request content type to be able to decode
+// into an empty interface, it is skipped for the content types whose decoders cannot. The fields
+// of the structs that implement JSONAliaser are also matched using their legacy names.
+func (service *Service) DecodeRequestStrict(req *http.Request, v interface{}) error {
+	contentType := req.Header.Get("Content-Type")
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		if isDecompressionError(err) {
+			return err
+		}
+		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
+	}
+	if err := service.Decoder.Decode(v, bytes.NewReader(body), contentType); err != nil {
+		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
+	}
+	var raw interface{}
+	if err := service.Decoder.Decode(&raw, bytes.NewReader(body), contentType); err != nil {
+		return nil
+	}
+	return unknownAttributes("payload", raw, reflect.TypeOf(v))
+}
+
+// unknownAttributes returns the errors describing the keys of the objects contained in raw that
+// have no corresponding field in the Go type t.
+func unknownAttributes(ctx string, raw interface{}, t reflect.Type) error {
+	if raw == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types that decode themselves such as enums, flags and scalars are leaves. Structs are
+	// walked even if they decode themselves as the generated UnmarshalJSON methods only change
+	// the encoding of the field values.
+	if t.Kind() != reflect.Struct && unmarshaler(t) {
+		return nil
+	}
+	var err error
+	switch t.Kind() {
+	case reflect.Struct:
+		obj := rawObject(raw)
+		if obj == nil {
+			return nil
+		}
+		fields := structFields(t)
+		if a, ok := reflect.New(t).Interface().(JSONAliaser); ok {
+			for alias, name := range a.JSONAliases() {
+				if ft, ok := fields[name]; ok {
+					fields[alias] = ft
+				}
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ft, ok := fields[k]
+			if !ok {
+				err = MergeErrors(err, UnknownAttributeError(ctx, k))
+				continue
+			}
+			err = MergeErrors(err, unknownAttributes(ctx+"."+k, obj[k], ft))
+		}
+	case reflect.Map:
+		obj := rawObject(raw)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			err = MergeErrors(err, unknownAttributes(fmt.Sprintf("%s[%q]", ctx, k), obj[k], t.Elem()))
+		}
+	case reflect.Slice, reflect.Array:
+		if elems, ok := raw.([]interface{}); ok {
+			for i, e := range elems {
+				err = MergeErrors(err, unknownAttributes(fmt.Sprintf("%s[%d]", ctx, i), e, t.Elem()))
+			}
+		}
+	}
+	return err
+}
+
+// unmarshaler returns true if t or a pointer to t implements json.Unmarshaler or
+// encoding.TextUnmarshaler.
+func unmarshaler(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) ||
+		pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+// rawObject returns the object decoded into raw indexed by key, nil if raw is not an object.
+func rawObject(raw interface{}) map[string]interface{} {
+	switch m := raw.(type) {
+	case map[string]interface{}:
+		return m
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			obj[fmt.Sprintf("%v", k)] = v
+		}
+		return obj
+	}
+	return nil
+}
+
+// structFields returns the types of the exported fields of the struct type t indexed by the names
+// given in their json tags.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
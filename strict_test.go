@@ -0,0 +1,135 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DecodeRequestStrict", func() {
+	type bottle struct {
+		Name    *string           `json:"name,omitempty"`
+		Vintage *int              `json:"vintage,omitempty"`
+		Tags    map[string]string `json:"tags,omitempty"`
+		Winery  *struct {
+			Name string `json:"name"`
+		} `json:"winery,omitempty"`
+		Ratings []*struct {
+			Score int `json:"score"`
+		} `json:"ratings,omitempty"`
+	}
+
+	var body string
+	var payload *bottle
+	var decErr error
+
+	BeforeEach(func() {
+		body = `{"name":"foo","vintage":2012,"tags":{"any":"key"},"winery":{"name":"bar"},"ratings":[{"score":4}]}`
+		payload = &bottle{}
+	})
+
+	JustBeforeEach(func() {
+		service := goa.New("test")
+		service.Decoder.Register(goa.NewJSONDecoder, "application/json")
+		req, _ := http.NewRequest("POST", "/bottles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		decErr = service.DecodeRequestStrict(req, payload)
+	})
+
+	It("decodes bodies that only contain known attributes", func() {
+		Ω(decErr).ShouldNot(HaveOccurred())
+		Ω(*payload.Name).Should(Equal("foo"))
+		Ω(payload.Tags).Should(HaveKeyWithValue("any", "key"))
+		Ω(payload.Winery.Name).Should(Equal("bar"))
+		Ω(payload.Ratings[0].Score).Should(Equal(4))
+	})
+
+	Context("with unknown attributes", func() {
+		BeforeEach(func() {
+			body = `{"name":"foo","color":"red","winery":{"name":"bar","country":"fr"},"ratings":[{"score":4,"by":"me"}]}`
+		})
+
+		It("reports all the unknown attributes", func() {
+			Ω(decErr).Should(HaveOccurred())
+			resp, ok := decErr.(*goa.ErrorResponse)
+			Ω(ok).Should(BeTrue())
+			Ω(resp.Code).Should(Equal("invalid_request"))
+			Ω(resp.Status).Should(Equal(400))
+			Ω(resp.Detail).Should(ContainSubstring(`attribute "color" of payload is not defined in the design`))
+			Ω(resp.Detail).Should(ContainSubstring(`attribute "country" of payload.winery is not defined in the design`))
+			Ω(resp.Detail).Should(ContainSubstring(`attribute "by" of payload.ratings[0] is not defined in the design`))
+			Ω(*payload.Name).Should(Equal("foo"))
+		})
+	})
+
+	Context("with an invalid body", func() {
+		BeforeEach(func() {
+			body = `{"name":`
+		})
+
+		It("returns the decoding error", func() {
+			Ω(decErr).Should(HaveOccurred())
+			Ω(decErr.Error()).Should(ContainSubstring("failed to decode request body"))
+		})
+	})
+})
+
+var _ = Describe("DecodeRequestStrict with a struct that decodes itself", func() {
+	var body string
+	var payload *aliasedBottle
+	var decErr error
+
+	JustBeforeEach(func() {
+		service := goa.New("test")
+		service.Decoder.Register(goa.NewJSONDecoder, "application/json")
+		req, _ := http.NewRequest("POST", "/bottles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		payload = &aliasedBottle{}
+		decErr = service.DecodeRequestStrict(req, payload)
+	})
+
+	Context("with unknown attributes", func() {
+		BeforeEach(func() {
+			body = `{"name":"foo","bogus":true}`
+		})
+
+		It("reports the unknown attributes", func() {
+			Ω(decErr).Should(HaveOccurred())
+			Ω(decErr.Error()).Should(ContainSubstring(`attribute "bogus" of payload is not defined in the design`))
+		})
+	})
+
+	Context("with a legacy attribute name", func() {
+		BeforeEach(func() {
+			body = `{"title":"foo"}`
+		})
+
+		It("accepts it", func() {
+			Ω(decErr).ShouldNot(HaveOccurred())
+			Ω(*payload.Name).Should(Equal("foo"))
+		})
+	})
+})
+
+// aliasedBottle decodes itself accepting the legacy name "title" of its "name" attribute like the
+// structs generated for the attributes defined with Alias.
+type aliasedBottle struct {
+	Name *string `json:"name,omitempty"`
+}
+
+func (b *aliasedBottle) JSONAliases() map[string]string {
+	return map[string]string{"title": "name"}
+}
+
+func (b *aliasedBottle) UnmarshalJSON(data []byte) error {
+	type alias aliasedBottle
+	data, err := goa.ResolveJSONAliases(data, b.JSONAliases())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, (*alias)(b))
+}
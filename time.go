@@ -0,0 +1,144 @@
+package goa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The functions below convert the values of the Time, Date and Duration design primitives between
+// their wire and Go representations. The generated code calls them according to the format
+// selected with the TimeFormat DSL.
+
+// ParseTime parses a RFC3339 time value.
+func ParseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, s)
+}
+
+// FormatTime formats t as a RFC3339 value.
+func FormatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// ParseUnixTime converts a number of seconds since the unix epoch into a UTC time value.
+func ParseUnixTime(sec int) (time.Time, error) {
+	return time.Unix(int64(sec), 0).UTC(), nil
+}
+
+// FormatUnixTime returns the number of seconds elapsed between the unix epoch and t.
+func FormatUnixTime(t time.Time) int {
+	return int(t.Unix())
+}
+
+// ParseDate parses a ISO8601 calendar date value, e.g. "2017-06-28".
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse("2006-01-02", s)
+}
+
+// FormatDate formats t as a ISO8601 calendar date value.
+func FormatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// ParseDurationSeconds converts a (possibly fractional) number of seconds into a duration.
+func ParseDurationSeconds(sec float64) (time.Duration, error) {
+	return time.Duration(sec * float64(time.Second)), nil
+}
+
+// FormatDurationSeconds returns the number of seconds in d.
+func FormatDurationSeconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+// ParseISO8601Duration parses a ISO8601 duration value such as "P1DT2H30M" or "PT0.5S". Years and
+// months do not have a fixed duration and are thus not supported, days last 24 hours and weeks 7
+// days.
+func ParseISO8601Duration(s string) (time.Duration, error) {
+	invalid := func() (time.Duration, error) {
+		return 0, fmt.Errorf("invalid ISO8601 duration %#v", s)
+	}
+	val := s
+	neg := strings.HasPrefix(val, "-")
+	if neg || strings.HasPrefix(val, "+") {
+		val = val[1:]
+	}
+	if !strings.HasPrefix(val, "P") {
+		return invalid()
+	}
+	val = val[1:]
+	var d time.Duration
+	var inTime bool
+	last := -1
+	for val != "" {
+		if val[0] == 'T' {
+			if inTime || len(val) == 1 {
+				return invalid()
+			}
+			inTime = true
+			val = val[1:]
+			continue
+		}
+		i := 0
+		for i < len(val) && (val[i] >= '0' && val[i] <= '9' || val[i] == '.' || val[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(val) {
+			return invalid()
+		}
+		n, err := strconv.ParseFloat(strings.Replace(val[:i], ",", ".", 1), 64)
+		if err != nil {
+			return invalid()
+		}
+		designators := "WD"
+		if inTime {
+			designators = "HMS"
+		}
+		rank := strings.IndexByte(designators, val[i])
+		if rank < 0 {
+			return invalid()
+		}
+		if inTime {
+			rank += 2
+		}
+		if rank <= last {
+			return invalid()
+		}
+		last = rank
+		unit := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}[rank]
+		d += time.Duration(n * float64(unit))
+		val = val[i+1:]
+	}
+	if last < 0 {
+		return invalid()
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// FormatISO8601Duration formats d as a ISO8601 duration using hours, minutes and seconds, e.g.
+// "PT26H3M0.5S".
+func FormatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	res := "PT"
+	if d < 0 {
+		res = "-PT"
+		d = -d
+	}
+	if h := d / time.Hour; h > 0 {
+		res += strconv.FormatInt(int64(h), 10) + "H"
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		res += strconv.FormatInt(int64(m), 10) + "M"
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		res += strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S"
+	}
+	return res
+}
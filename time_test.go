@@ -0,0 +1,48 @@
+package goa_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ISO8601 durations", func() {
+	It("parses durations", func() {
+		d, err := goa.ParseISO8601Duration("P1DT2H30M")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(26*time.Hour + 30*time.Minute))
+		d, err = goa.ParseISO8601Duration("-PT0.5S")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(-500 * time.Millisecond))
+		d, err = goa.ParseISO8601Duration("P2W")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d).Should(Equal(14 * 24 * time.Hour))
+	})
+
+	It("rejects invalid durations", func() {
+		for _, s := range []string{"", "P", "PT", "1H", "P1Y", "P1M", "PT1H2H", "PT1M1H", "P1DT"} {
+			_, err := goa.ParseISO8601Duration(s)
+			Ω(err).Should(HaveOccurred(), s)
+		}
+	})
+
+	It("formats durations", func() {
+		Ω(goa.FormatISO8601Duration(0)).Should(Equal("PT0S"))
+		Ω(goa.FormatISO8601Duration(26*time.Hour + 90*time.Second)).Should(Equal("PT26H1M30S"))
+		Ω(goa.FormatISO8601Duration(-1500 * time.Millisecond)).Should(Equal("-PT1.5S"))
+	})
+})
+
+var _ = Describe("time formats", func() {
+	It("round trips dates and unix times", func() {
+		t, err := goa.ParseDate("2017-06-28")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(goa.FormatDate(t)).Should(Equal("2017-06-28"))
+		t, err = goa.ParseUnixTime(1500000000)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(goa.FormatTime(t)).Should(Equal("2017-07-14T02:40:00Z"))
+		Ω(goa.FormatUnixTime(t)).Should(Equal(1500000000))
+	})
+})
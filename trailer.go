@@ -0,0 +1,22 @@
+package goa
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AnnounceTrailers lists the given trailers in the Trailer header of the response. It must be
+// called before the response header is written, the generated response helpers of responses that
+// define trailers call it automatically.
+func (r *ResponseData) AnnounceTrailers(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	r.Header().Set("Trailer", strings.Join(names, ", "))
+}
+
+// SetTrailer sets the value of the given trailer. SetTrailer may be called after the response body
+// has been written, the trailer is sent once the handler returns.
+func (r *ResponseData) SetTrailer(name, value string) {
+	r.Header().Set(http.TrailerPrefix+name, value)
+}
@@ -0,0 +1,42 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("ResponseData trailers", func() {
+	var rw *httptest.ResponseRecorder
+	var resp *goa.ResponseData
+
+	BeforeEach(func() {
+		rw = httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		resp = goa.ContextResponse(ctx)
+	})
+
+	It("announces the trailers", func() {
+		resp.AnnounceTrailers("X-Checksum", "X-Record-Count")
+		Ω(rw.Header().Get("Trailer")).Should(Equal("X-Checksum, X-Record-Count"))
+	})
+
+	It("does not announce empty trailer lists", func() {
+		resp.AnnounceTrailers()
+		Ω(rw.Header()).ShouldNot(HaveKey("Trailer"))
+	})
+
+	It("sends the trailers after the body", func() {
+		resp.AnnounceTrailers("X-Checksum")
+		resp.WriteHeader(200)
+		resp.Write([]byte("body"))
+		resp.SetTrailer("X-Checksum", "abc")
+		Ω(rw.Result().Trailer.Get("X-Checksum")).Should(Equal("abc"))
+	})
+})
@@ -0,0 +1,131 @@
+/*
+Package workflow runs the workflows designed with the Workflow DSL. A workflow is a sequence of
+steps that each call an action of the API. When a step fails the steps that completed before it
+are undone in reverse order by their compensation hooks (saga pattern). The code generated for each
+workflow wraps Workflow with a typed state machine whose steps are implemented by the service.
+*/
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+const (
+	// StatePending is the state of workflows that have not started.
+	StatePending = "pending"
+	// StateCompleted is the state of workflows whose steps all completed.
+	StateCompleted = "completed"
+	// StateCompensated is the state of workflows whose completed steps were undone after a
+	// step failed.
+	StateCompensated = "compensated"
+	// StateFailed is the state of workflows whose compensation hooks failed to undo the
+	// completed steps.
+	StateFailed = "failed"
+)
+
+type (
+	// Workflow is a sequence of steps.
+	Workflow struct {
+		// Name of the workflow.
+		Name string
+		// Steps lists the steps in execution order.
+		Steps []*Step
+		// OnTransition is called each time the workflow state changes if not nil. The state is
+		// the name of the running step or one of the StateXXX constants.
+		OnTransition func(from, to string)
+		// state is the current state.
+		state string
+	}
+
+	// Step is a step of a workflow.
+	Step struct {
+		// Name of the step.
+		Name string
+		// Run executes the step.
+		Run func(context.Context) error
+		// Compensate undoes the step, nil if the step cannot be undone.
+		Compensate func(context.Context) error
+	}
+
+	// Error is the error returned when a workflow step fails.
+	Error struct {
+		// Workflow is the name of the workflow.
+		Workflow string
+		// Step is the name of the step that failed.
+		Step string
+		// Err is the error returned by the step.
+		Err error
+		// CompensationErrors lists the errors returned by the compensation hooks indexed by
+		// step name.
+		CompensationErrors map[string]error
+	}
+)
+
+// State returns the current state of the workflow.
+func (w *Workflow) State() string {
+	if w.state == "" {
+		return StatePending
+	}
+	return w.state
+}
+
+// Run runs the steps in order. If a step fails Run calls the compensation hooks of the steps that
+// completed before it in reverse order and returns an *Error. All the compensation hooks are called
+// even if some fail.
+func (w *Workflow) Run(ctx context.Context) error {
+	var done []*Step
+	for _, s := range w.Steps {
+		w.transition(s.Name)
+		if err := s.Run(ctx); err != nil {
+			werr := &Error{Workflow: w.Name, Step: s.Name, Err: err}
+			for i := len(done) - 1; i >= 0; i-- {
+				c := done[i]
+				if c.Compensate == nil {
+					continue
+				}
+				if cerr := c.Compensate(ctx); cerr != nil {
+					if werr.CompensationErrors == nil {
+						werr.CompensationErrors = make(map[string]error)
+					}
+					werr.CompensationErrors[c.Name] = cerr
+				}
+			}
+			if werr.CompensationErrors != nil {
+				w.transition(StateFailed)
+			} else {
+				w.transition(StateCompensated)
+			}
+			return werr
+		}
+		done = append(done, s)
+	}
+	w.transition(StateCompleted)
+	return nil
+}
+
+// transition sets the workflow state and calls OnTransition.
+func (w *Workflow) transition(to string) {
+	from := w.State()
+	w.state = to
+	if w.OnTransition != nil {
+		w.OnTransition(from, to)
+	}
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("workflow %q: step %q failed: %s", e.Workflow, e.Step, e.Err)
+	if len(e.CompensationErrors) == 0 {
+		return msg
+	}
+	var failed []string
+	for n, err := range e.CompensationErrors {
+		failed = append(failed, fmt.Sprintf("%q: %s", n, err))
+	}
+	sort.Strings(failed)
+	return msg + ", compensation failed: " + strings.Join(failed, ", ")
+}
@@ -0,0 +1,83 @@
+package workflow_test
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa/workflow"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Workflow", func() {
+	var calls []string
+	var failing, failingCompensation string
+	var transitions []string
+	var w *workflow.Workflow
+
+	step := func(name string, compensated bool) *workflow.Step {
+		s := &workflow.Step{
+			Name: name,
+			Run: func(context.Context) error {
+				calls = append(calls, name)
+				if name == failing {
+					return errors.New("boom")
+				}
+				return nil
+			},
+		}
+		if compensated {
+			s.Compensate = func(context.Context) error {
+				calls = append(calls, "undo "+name)
+				if name == failingCompensation {
+					return errors.New("stuck")
+				}
+				return nil
+			}
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		calls = nil
+		transitions = nil
+		failing = ""
+		failingCompensation = ""
+		w = &workflow.Workflow{
+			Name:  "order_fulfillment",
+			Steps: []*workflow.Step{step("reserve", true), step("charge", true), step("notify", false), step("ship", false)},
+			OnTransition: func(from, to string) {
+				transitions = append(transitions, from+">"+to)
+			},
+		}
+	})
+
+	It("runs the steps in order", func() {
+		Ω(w.State()).Should(Equal(workflow.StatePending))
+		Ω(w.Run(context.Background())).Should(Succeed())
+		Ω(calls).Should(Equal([]string{"reserve", "charge", "notify", "ship"}))
+		Ω(w.State()).Should(Equal(workflow.StateCompleted))
+		Ω(transitions).Should(Equal([]string{"pending>reserve", "reserve>charge", "charge>notify", "notify>ship", "ship>completed"}))
+	})
+
+	It("compensates the completed steps in reverse order", func() {
+		failing = "ship"
+		err := w.Run(context.Background())
+		Ω(err).Should(HaveOccurred())
+		werr := err.(*workflow.Error)
+		Ω(werr.Step).Should(Equal("ship"))
+		Ω(werr.CompensationErrors).Should(BeEmpty())
+		Ω(calls).Should(Equal([]string{"reserve", "charge", "notify", "ship", "undo charge", "undo reserve"}))
+		Ω(w.State()).Should(Equal(workflow.StateCompensated))
+	})
+
+	It("reports the compensation failures", func() {
+		failing = "ship"
+		failingCompensation = "charge"
+		err := w.Run(context.Background())
+		Ω(err).Should(MatchError(`workflow "order_fulfillment": step "ship" failed: boom, compensation failed: "charge": stuck`))
+		Ω(calls).Should(ContainElement("undo reserve"))
+		Ω(w.State()).Should(Equal(workflow.StateFailed))
+	})
+})